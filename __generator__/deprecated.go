@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-yaml/yaml"
+)
+
+const deprecatedHeader = `# Deprecated variables and functions, key is the name and value is the replacement.
+# Empty replacement means there is no compatible replacement.
+# Entries are merged into predefined.yml and builtin.yml definitions on generation,
+# run "go run . -sync-deprecated" to add newly deprecated ones from Fastly documentation.
+`
+
+// Deprecations is a deprecation database of Fastly variables and functions
+type Deprecations struct {
+	Variables map[string]string `yaml:"variables"`
+	Functions map[string]string `yaml:"functions"`
+}
+
+func loadDeprecations(file string) (*Deprecations, error) {
+	fp, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	d := &Deprecations{
+		Variables: map[string]string{},
+		Functions: map[string]string{},
+	}
+	if err := yaml.NewDecoder(fp).Decode(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Deprecations) applyVariables(defs map[string]*Definition) error {
+	for name, replacement := range d.Variables {
+		def, ok := defs[name]
+		if !ok {
+			return fmt.Errorf("Deprecated variable %s is not defined in predefined.yml", name)
+		}
+		if _, ok := defs[replacement]; replacement != "" && !ok {
+			return fmt.Errorf("Replacement %s of deprecated variable %s is not defined in predefined.yml", replacement, name)
+		}
+		def.Deprecated = true
+		def.Replacement = replacement
+	}
+	return nil
+}
+
+func (d *Deprecations) applyFunctions(defs map[string]*FunctionSpec) error {
+	for name, replacement := range d.Functions {
+		def, ok := defs[name]
+		if !ok {
+			return fmt.Errorf("Deprecated function %s is not defined in builtin.yml", name)
+		}
+		if _, ok := defs[replacement]; replacement != "" && !ok {
+			return fmt.Errorf("Replacement %s of deprecated function %s is not defined in builtin.yml", replacement, name)
+		}
+		def.Deprecated = true
+		def.Replacement = replacement
+	}
+	return nil
+}
+
+// Deprecation notice on the reference documentation, replacement is suggested in following code element
+var deprecatedNotice = regexp.MustCompile(`(?is)\bdeprecated\b(.{0,300})`)
+var codeElement = regexp.MustCompile(`<code>([a-z0-9_.]+)</code>`)
+
+// Fetch reference documentation of all variables and functions,
+// and add entries which have deprecation notice to the deprecation database.
+// Existing entries are kept as they are because the replacement may be curated by hand.
+func syncDeprecations(file string) error {
+	d, err := loadDeprecations(file)
+	if err != nil {
+		return err
+	}
+
+	variables := map[string]*Definition{}
+	if err := decodeYaml("./predefined.yml", &variables); err != nil {
+		return err
+	}
+	functions := map[string]*FunctionSpec{}
+	if err := decodeYaml("./builtin.yml", &functions); err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, name := range keySort[Definition](variables) {
+		if _, ok := d.Variables[name]; ok {
+			continue
+		}
+		replacement, deprecated, err := fetchDeprecation(client, variables[name].Ref)
+		if err != nil {
+			return err
+		}
+		if !deprecated {
+			continue
+		}
+		if _, ok := variables[replacement]; !ok {
+			replacement = ""
+		}
+		fmt.Printf("Variable %s is deprecated, replacement=%q\n", name, replacement)
+		d.Variables[name] = replacement
+	}
+	for _, name := range keySort[FunctionSpec](functions) {
+		if _, ok := d.Functions[name]; ok {
+			continue
+		}
+		replacement, deprecated, err := fetchDeprecation(client, functions[name].Ref)
+		if err != nil {
+			return err
+		}
+		if !deprecated {
+			continue
+		}
+		if _, ok := functions[replacement]; !ok {
+			replacement = ""
+		}
+		fmt.Printf("Function %s is deprecated, replacement=%q\n", name, replacement)
+		d.Functions[name] = replacement
+	}
+
+	out, err := yaml.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append([]byte(deprecatedHeader), out...), 0644)
+}
+
+func fetchDeprecation(client *http.Client, url string) (string, bool, error) {
+	// Undocumented variables do not have the page on Fastly documentation
+	if !strings.HasPrefix(url, "https://developer.fastly.com/") {
+		return "", false, nil
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, err
+	}
+
+	match := deprecatedNotice.FindSubmatch(body)
+	if match == nil {
+		return "", false, nil
+	}
+	if code := codeElement.FindSubmatch(match[1]); code != nil {
+		return string(code[1]), true, nil
+	}
+	return "", true, nil
+}
+
+func decodeYaml(file string, v interface{}) error {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(buf, v)
+}
@@ -17,6 +17,7 @@ type Linter struct {
 	predefinedOutput string
 	builtinInput     string
 	builtinOutput    string
+	deprecatedInput  string
 }
 
 func newLinter() *Linter {
@@ -25,6 +26,7 @@ func newLinter() *Linter {
 		predefinedOutput: "../context/builtin.go",
 		builtinInput:     "./builtin.yml",
 		builtinOutput:    "../context/predefined.go",
+		deprecatedInput:  "./deprecated.yml",
 	}
 }
 
@@ -39,6 +41,13 @@ func (l *Linter) generatePredefined() error {
 	if err := yaml.NewDecoder(fp).Decode(&defs); err != nil {
 		return err
 	}
+	deprecations, err := loadDeprecations(l.deprecatedInput)
+	if err != nil {
+		return err
+	}
+	if err := deprecations.applyVariables(defs); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	vars := map[string]*Object{}
@@ -109,6 +118,13 @@ func (l *Linter) generateBuiltInFunction() error {
 	if err := yaml.NewDecoder(fp).Decode(&defs); err != nil {
 		return err
 	}
+	deprecations, err := loadDeprecations(l.deprecatedInput)
+	if err != nil {
+		return err
+	}
+	if err := deprecations.applyFunctions(defs); err != nil {
+		return err
+	}
 
 	var buf bytes.Buffer
 	fns := map[string]*Spec{}
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"sort"
 )
@@ -24,7 +25,16 @@ var typeToType = map[string]string{
 }
 
 func main() {
+	syncDeprecated := flag.Bool("sync-deprecated", false, "Add deprecated variables and functions from Fastly documentation")
+	flag.Parse()
+
 	l := newLinter()
+	if *syncDeprecated {
+		if err := syncDeprecations(l.deprecatedInput); err != nil {
+			panic(err)
+		}
+	}
+
 	if err := l.generatePredefined(); err != nil {
 		panic(err)
 	}
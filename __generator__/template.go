@@ -52,6 +52,9 @@ type BuiltinFunction struct {
 	Scopes    						int
 	Reference 						string
 	IsUserDefinedFunction bool
+	// Deprecated function may have the replacement function
+	Deprecated            bool
+	Replacement           string
 }
 
 func builtinFunctions() Functions {
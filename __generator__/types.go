@@ -12,6 +12,10 @@ type FunctionSpec struct {
 	Extra     string     `yaml:"extra"`
 	On        []string   `yaml:"on"`
 	Ref       string     `yaml:"reference"`
+
+	// Filled from deprecation database
+	Deprecated  bool   `yaml:"-"`
+	Replacement string `yaml:"-"`
 }
 
 func (f *FunctionSpec) String() string {
@@ -38,6 +42,12 @@ func (f *FunctionSpec) String() string {
 	}
 	buf.WriteString(fmt.Sprintf("Scopes: %s,\n", strings.Join(f.On, "|")))
 	buf.WriteString(fmt.Sprintf(`Reference: "%s"`+",\n", f.Ref))
+	if f.Deprecated {
+		buf.WriteString("Deprecated: true,\n")
+		if f.Replacement != "" {
+			buf.WriteString(fmt.Sprintf(`Replacement: "%s"`+",\n", f.Replacement))
+		}
+	}
 	buf.WriteString("},\n")
 	return buf.String()
 }
@@ -53,6 +63,10 @@ type Definition struct {
 	Unset bool     `yaml:"unset"`
 	On    []string `yaml:"on"`
 	Ref   string   `yaml:"reference"`
+
+	// Filled from deprecation database
+	Deprecated  bool   `yaml:"-"`
+	Replacement string `yaml:"-"`
 }
 
 func (d *Definition) String() string {
@@ -72,6 +86,12 @@ func (d *Definition) String() string {
 	buf.WriteString(fmt.Sprintf("Unset: %t,\n", d.Unset))
 	buf.WriteString(fmt.Sprintf("Scopes: %s,\n", strings.Join(d.On, "|")))
 	buf.WriteString(fmt.Sprintf(`Reference: "%s"`+",\n", d.Ref))
+	if d.Deprecated {
+		buf.WriteString("Deprecated: true,\n")
+		if d.Replacement != "" {
+			buf.WriteString(fmt.Sprintf(`Replacement: "%s"`+",\n", d.Replacement))
+		}
+	}
 	buf.WriteString("},\n")
 	return buf.String()
 }
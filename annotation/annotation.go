@@ -0,0 +1,311 @@
+// Package annotation parses annotation comments like "// @scope: recv, fetch" which tell falco
+// what VCL could not express, e.g. the scopes where the custom subroutine is called.
+//
+// Annotations are defined in the Registry. Builtin annotations are registered in the default registry,
+// and features or plugins which are compiled in could register their own definitions by Register.
+package annotation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/token"
+)
+
+// Kind is the value type of the annotation
+type Kind int
+
+const (
+	// Annotation without value like "@debugger"
+	Flag Kind = iota
+	// Annotation with a single value like "@suite: name", whole text after the colon is the value
+	Single
+	// Annotation with comma separated values like "@scope: recv, fetch"
+	List
+	// Annotation which accepts any value or no value, e.g. annotations which are consumed by other tools
+	Any
+)
+
+// Target is the node which the annotation could be written for
+type Target int
+
+const (
+	Subroutine Target = 1 << iota
+	Statement
+
+	AnyTarget = Subroutine | Statement
+)
+
+func (t Target) String() string {
+	switch t {
+	case Subroutine:
+		return "subroutine"
+	case Statement:
+		return "statement"
+	default:
+		return "subroutine or statement"
+	}
+}
+
+// Definition describes the annotation which is recognized
+type Definition struct {
+	Name string
+	Kind Kind
+	// Nodes which the annotation could be written for, any node is allowed when zero
+	Target Target
+	// Annotation could be written multiple times for the same node, values are merged
+	Repeatable bool
+	// Validate each value of Single or List annotation, returned error describes why the value is invalid
+	Validate    func(value string) error
+	Description string
+}
+
+// Annotation is the parsed annotation comment
+type Annotation struct {
+	Name   string
+	Values []string
+	// Former scope annotation like "@recv, @fetch"
+	Legacy bool
+	// Position of "@" character of the annotation
+	Token token.Token
+}
+
+// Annotations are parsed annotations of the node in written order
+type Annotations []*Annotation
+
+// Get returns the first annotation of the name
+func (a Annotations) Get(name string) (*Annotation, bool) {
+	for _, v := range a {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Values returns all values of the annotations which have the name
+func (a Annotations) Values(name string) []string {
+	var values []string
+	for _, v := range a {
+		if v.Name == name {
+			values = append(values, v.Values...)
+		}
+	}
+	return values
+}
+
+// ErrorKind is the kind of problem of the annotation
+type ErrorKind int
+
+const (
+	// Annotation is not registered
+	Unknown ErrorKind = iota
+	// Annotation or the value is written more than once
+	Duplicated
+	// Value is invalid or the annotation is written for the node which is not the target
+	Invalid
+)
+
+// Error is the problem of the annotation with the position
+type Error struct {
+	Kind    ErrorKind
+	Token   token.Token
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at line %d, position %d", e.Message, e.Token.Line, e.Token.Position)
+}
+
+// Parse annotations in comments with the default registry
+func Parse(comments ast.Comments, target Target) (Annotations, []*Error) {
+	return defaultRegistry.Parse(comments, target)
+}
+
+// Parse annotations in comments of the target node.
+// Annotations which have problems are reported as errors, and unknown or invalid annotations are not returned.
+func (r *Registry) Parse(comments ast.Comments, target Target) (Annotations, []*Error) {
+	var parsed Annotations
+	var errs []*Error
+
+	seen := make(map[string]map[string]struct{})
+	for _, c := range comments {
+		for i, line := range strings.Split(c.Value, "\n") {
+			a, explicit, ok := parseLine(c, i, line)
+			if !ok {
+				continue
+			}
+			def, ok := r.Lookup(a.Name)
+			if !ok {
+				// Free-form text in doc comments like "@see https://..." or "@author someone" is not an annotation,
+				// only "@name:" form is reported in order to find typos of annotation names
+				if !explicit {
+					continue
+				}
+				errs = append(errs, &Error{
+					Kind:    Unknown,
+					Token:   a.Token,
+					Message: fmt.Sprintf("Unknown annotation @%s", a.Name),
+				})
+				continue
+			}
+			if err := def.check(a, target); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			values, ok := seen[a.Name]
+			if ok && !def.Repeatable {
+				errs = append(errs, &Error{
+					Kind:    Duplicated,
+					Token:   a.Token,
+					Message: fmt.Sprintf("Annotation @%s is duplicated", a.Name),
+				})
+				continue
+			} else if !ok {
+				values = make(map[string]struct{})
+				seen[a.Name] = values
+			}
+			// Duplicated values are dropped in order to be used as a set
+			unique := a.Values[:0]
+			for _, v := range a.Values {
+				key := strings.ToLower(v)
+				if _, ok := values[key]; ok {
+					errs = append(errs, &Error{
+						Kind:    Duplicated,
+						Token:   a.Token,
+						Message: fmt.Sprintf(`Value "%s" of annotation @%s is duplicated`, v, a.Name),
+					})
+					continue
+				}
+				values[key] = struct{}{}
+				unique = append(unique, v)
+			}
+			a.Values = unique
+			parsed = append(parsed, a)
+		}
+	}
+
+	return parsed, errs
+}
+
+// Parse the annotation in the line of the comment.
+// The line must start with "@" and the name, like "@scope: recv", "@debugger" or legacy "@recv, @fetch".
+// Second returned value reports the annotation is written in "@name:" form.
+func parseLine(c *ast.Comment, index int, line string) (*Annotation, bool, bool) {
+	trimmed := strings.TrimLeft(line, " \t*/#")
+	if !strings.HasPrefix(trimmed, "@") {
+		return nil, false, false
+	}
+	trimmed = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(trimmed), "*/"))
+
+	// Name consists of letters, digits, underscore and hyphen which starts with a letter
+	end := 1
+	for end < len(trimmed) && isNameChar(trimmed[end], end == 1) {
+		end++
+	}
+	if end == 1 {
+		return nil, false, false
+	}
+	name, rest := trimmed[1:end], strings.TrimSpace(trimmed[end:])
+
+	// Column of "@" character, following lines of the block comment start from the first column
+	position := 1
+	if index == 0 {
+		position = c.Token.Position
+	}
+	position += len([]rune(line[:strings.Index(line, "@")]))
+	a := &Annotation{
+		Name: name,
+		Token: token.Token{
+			Type:     token.COMMENT,
+			Literal:  "@" + name,
+			File:     c.Token.File,
+			Line:     c.Token.Line + index,
+			Position: position,
+		},
+	}
+
+	switch {
+	case strings.HasPrefix(rest, ":"):
+		a.Values = []string{strings.TrimSpace(rest[1:])}
+		return a, true, true
+	case rest != "" && !strings.HasPrefix(rest, ",") && trimmed[end] != ' ' && trimmed[end] != '\t':
+		// Text like "@example.com" is not an annotation
+		return nil, false, false
+	case isScopeName(name):
+		// Former scope annotation like "@recv, @fetch" or "@fetch, miss"
+		a.Name = "scope"
+		a.Legacy = true
+		a.Values = []string{strings.TrimPrefix(trimmed, "@")}
+	case rest != "":
+		a.Values = []string{rest}
+	}
+	return a, false, true
+}
+
+func isNameChar(b byte, first bool) bool {
+	if b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' {
+		return true
+	}
+	if first {
+		return false
+	}
+	return b >= '0' && b <= '9' || b == '_' || b == '-'
+}
+
+// Check the annotation matches the definition and split values by the kind
+func (d *Definition) check(a *Annotation, target Target) *Error {
+	invalid := func(format string, args ...any) *Error {
+		return &Error{
+			Kind:    Invalid,
+			Token:   a.Token,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+
+	if d.Target != 0 && d.Target&target == 0 {
+		return invalid("Annotation @%s is only available for %s", d.Name, d.Target)
+	}
+
+	var raw string
+	if len(a.Values) > 0 {
+		raw = a.Values[0]
+	}
+	switch d.Kind {
+	case Flag:
+		if raw != "" {
+			return invalid("Annotation @%s does not accept value", d.Name)
+		}
+		a.Values = nil
+		return nil
+	case Any:
+		return nil
+	case Single:
+		if raw == "" {
+			return invalid("Annotation @%s requires a value", d.Name)
+		}
+	case List:
+		var values []string
+		for _, v := range strings.Split(raw, ",") {
+			// Values of legacy scope annotation may have "@" prefix
+			if v = strings.TrimPrefix(strings.TrimSpace(v), "@"); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return invalid("Annotation @%s requires at least one value", d.Name)
+		}
+		a.Values = values
+	}
+
+	if d.Validate != nil {
+		for _, v := range a.Values {
+			if err := d.Validate(v); err != nil {
+				return invalid("Invalid value of annotation @%s: %s", d.Name, err.Error())
+			}
+		}
+	}
+	return nil
+}
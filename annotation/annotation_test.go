@@ -0,0 +1,236 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+// Parse the subroutine and return leading comments of it
+func leadingComments(t *testing.T, input string) ast.Comments {
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Fatalf("Unexpected parser error: %s", err)
+	}
+	sub, ok := vcl.Statements[0].(*ast.SubroutineDeclaration)
+	if !ok {
+		t.Fatalf("First statement must be a subroutine, got=%T", vcl.Statements[0])
+	}
+	return sub.Leading
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect Annotations
+	}{
+		{
+			name: "scope annotation",
+			input: `// @scope: recv, FETCH
+sub custom {}`,
+			expect: Annotations{
+				{Name: "scope", Values: []string{"recv", "FETCH"}},
+			},
+		},
+		{
+			name: "legacy scope annotation",
+			input: `// @recv, @fetch
+sub custom {}`,
+			expect: Annotations{
+				{Name: "scope", Values: []string{"recv", "fetch"}, Legacy: true},
+			},
+		},
+		{
+			name: "repeated scope annotations and other annotations",
+			input: `# @scope: recv
+# @scope: deliver
+# @suite: custom subroutine test
+# @debugger
+sub custom {}`,
+			expect: Annotations{
+				{Name: "scope", Values: []string{"recv"}},
+				{Name: "scope", Values: []string{"deliver"}},
+				{Name: "suite", Values: []string{"custom subroutine test"}},
+				{Name: "debugger"},
+			},
+		},
+		{
+			name: "annotations in block comment",
+			input: `/*
+ * Custom subroutine
+ * @scope: recv
+ */
+sub custom {}`,
+			expect: Annotations{
+				{Name: "scope", Values: []string{"recv"}},
+			},
+		},
+		{
+			name: "text which is not an annotation",
+			input: `// Contact to someone@example.com
+// @example.com
+// @
+// @see https://developer.fastly.com/reference/vcl/
+/**
+ * @author someone
+ * @todo
+ */
+sub custom {}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, errs := Parse(leadingComments(t, tt.input), Subroutine)
+			if len(errs) > 0 {
+				t.Errorf("Unexpected errors: %v", errs)
+			}
+			if diff := cmp.Diff(tt.expect, actual, cmpopts.IgnoreFields(Annotation{}, "Token")); diff != "" {
+				t.Errorf("Annotations mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		target Target
+		expect []*Error
+	}{
+		{
+			name: "unknown annotation",
+			input: `// @scopes: recv
+sub custom {}`,
+			target: Subroutine,
+			expect: []*Error{
+				{Kind: Unknown, Message: "Unknown annotation @scopes"},
+			},
+		},
+		{
+			name: "duplicated annotation and value",
+			input: `// @suite: foo
+// @suite: bar
+// @scope: recv, fetch, RECV
+sub custom {}`,
+			target: Subroutine,
+			expect: []*Error{
+				{Kind: Duplicated, Message: "Annotation @suite is duplicated"},
+				{Kind: Duplicated, Message: `Value "RECV" of annotation @scope is duplicated`},
+			},
+		},
+		{
+			name: "invalid values",
+			input: `// @scope: recieve
+// @suite:
+// @debugger: true
+// @scope: ,
+sub custom {}`,
+			target: Subroutine,
+			expect: []*Error{
+				{
+					Kind:    Invalid,
+					Message: `Invalid value of annotation @scope: scope "recieve" must be one of recv, hash, hit, miss, pass, fetch, error, deliver, log`,
+				},
+				{Kind: Invalid, Message: "Annotation @suite requires a value"},
+				{Kind: Invalid, Message: "Annotation @debugger does not accept value"},
+				{Kind: Invalid, Message: "Annotation @scope requires at least one value"},
+			},
+		},
+		{
+			name: "annotation for the node which is not the target",
+			input: `// @scope: recv
+sub custom {}`,
+			target: Statement,
+			expect: []*Error{
+				{Kind: Invalid, Message: "Annotation @scope is only available for subroutine"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := Parse(leadingComments(t, tt.input), tt.target)
+			if diff := cmp.Diff(tt.expect, errs, cmpopts.IgnoreFields(Error{}, "Token")); diff != "" {
+				t.Errorf("Errors mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	input := `
+  // @unknown: value
+/*
+ * @scope: recv
+ *   @suite: foo */
+sub custom {}`
+	annotations, errs := Parse(leadingComments(t, input), Subroutine)
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got=%v", errs)
+	}
+	if errs[0].Token.Line != 2 || errs[0].Token.Position != 6 {
+		t.Errorf("Unexpected error position, line=%d, position=%d", errs[0].Token.Line, errs[0].Token.Position)
+	}
+	expects := []struct {
+		line     int
+		position int
+	}{
+		{line: 4, position: 4},
+		{line: 5, position: 6},
+	}
+	if len(annotations) != len(expects) {
+		t.Fatalf("Expected %d annotations, got=%d", len(expects), len(annotations))
+	}
+	for i, e := range expects {
+		tok := annotations[i].Token
+		if tok.Line != e.line || tok.Position != e.position {
+			t.Errorf("Unexpected position of @%s, expect=%d:%d, got=%d:%d",
+				annotations[i].Name, e.line, e.position, tok.Line, tok.Position)
+		}
+	}
+	if v := annotations.Values("suite"); len(v) != 1 || v[0] != "foo" {
+		t.Errorf("Value must not contain the end of block comment, got=%v", v)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("register duplicated or invalid name", func(t *testing.T) {
+		r := Default().Clone()
+		if err := r.Register(&Definition{Name: "scope", Kind: Flag}); err == nil {
+			t.Errorf("Expected error on registering builtin annotation")
+		}
+		if err := r.Register(&Definition{Name: "1st", Kind: Flag}); err == nil {
+			t.Errorf("Expected error on registering name which starts with a digit")
+		}
+		if err := r.Register(&Definition{Name: "cache key", Kind: Flag}); err == nil {
+			t.Errorf("Expected error on registering name which contains a space")
+		}
+	})
+
+	t.Run("registered annotations are not shared with the origin", func(t *testing.T) {
+		r := Default().Clone()
+		if err := r.Register(&Definition{Name: "owner", Kind: Single, Target: AnyTarget}); err != nil {
+			t.Fatalf("Unexpected error on registering: %s", err)
+		}
+		if _, ok := Default().Lookup("owner"); ok {
+			t.Errorf("Annotation registered to the clone must not be found in the default registry")
+		}
+
+		input := `// @owner: platform-team
+sub custom {}`
+		annotations, errs := r.Parse(leadingComments(t, input), Subroutine)
+		if len(errs) > 0 {
+			t.Errorf("Unexpected errors: %v", errs)
+		}
+		if a, ok := annotations.Get("owner"); !ok || a.Values[0] != "platform-team" {
+			t.Errorf("Registered annotation must be parsed, got=%v", annotations)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package annotation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Scope names which are used for @scope annotation, and also written as legacy annotation like "@recv"
+var scopeNames = []string{"recv", "hash", "hit", "miss", "pass", "fetch", "error", "deliver", "log"}
+
+func isScopeName(name string) bool {
+	for _, s := range scopeNames {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Builtin annotations which falco recognizes
+func builtinDefinitions() []*Definition {
+	return []*Definition{
+		{
+			Name:       "scope",
+			Kind:       List,
+			Target:     Subroutine,
+			Repeatable: true,
+			Validate: func(value string) error {
+				if !isScopeName(value) {
+					return fmt.Errorf(`scope "%s" must be one of %s`, value, strings.Join(scopeNames, ", "))
+				}
+				return nil
+			},
+			Description: "Scopes where the custom subroutine is called",
+		},
+		{
+			Name:        "suite",
+			Kind:        Single,
+			Target:      Subroutine,
+			Description: "Test suite name of the testing subroutine",
+		},
+		{
+			Name:        "debugger",
+			Kind:        Flag,
+			Target:      AnyTarget,
+			Description: "Break point of the simulator debugger",
+		},
+	}
+}
+
+// Registry holds annotation definitions keyed by the name
+type Registry struct {
+	mu   sync.RWMutex
+	defs map[string]*Definition
+}
+
+func NewRegistry(defs ...*Definition) *Registry {
+	r := &Registry{
+		defs: make(map[string]*Definition),
+	}
+	for _, def := range defs {
+		r.defs[def.Name] = def
+	}
+	return r
+}
+
+var defaultRegistry = NewRegistry(builtinDefinitions()...)
+
+// Default returns the registry which has builtin annotations and annotations registered by Register
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register registers annotations to the default registry.
+// It is typically called in init() of the package which is compiled in.
+func Register(defs ...*Definition) error {
+	return defaultRegistry.Register(defs...)
+}
+
+// Register registers annotations, returns an error when the annotation which has the same name is already registered
+func (r *Registry) Register(defs ...*Definition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, def := range defs {
+		if def.Name == "" || !isNameChar(def.Name[0], true) {
+			return fmt.Errorf(`Annotation name "%s" must start with a letter`, def.Name)
+		}
+		for i := 1; i < len(def.Name); i++ {
+			if !isNameChar(def.Name[i], false) {
+				return fmt.Errorf(`Annotation name "%s" must consist of letters, digits, "_" and "-"`, def.Name)
+			}
+		}
+		if _, ok := r.defs[def.Name]; ok {
+			return fmt.Errorf("Annotation @%s is already registered", def.Name)
+		}
+		r.defs[def.Name] = def
+	}
+	return nil
+}
+
+// Lookup returns the definition of the name
+func (r *Registry) Lookup(name string) (*Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.defs[name]
+	return def, ok
+}
+
+// Definitions returns all definitions ordered by the name
+func (r *Registry) Definitions() []*Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]*Definition, 0, len(r.defs))
+	for _, def := range r.defs {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Name < defs[j].Name
+	})
+	return defs
+}
+
+// Clone returns the registry which has the same definitions, definitions registered to the clone are not shared
+func (r *Registry) Clone() *Registry {
+	return NewRegistry(r.Definitions()...)
+}
@@ -31,6 +31,8 @@ func (a *AclDeclaration) String() string {
 	return buf.String()
 }
 
+// IP, or Inverse for the negated entry, shares the same Meta with the entry when the entry does not have any comments
+// in order to reduce memory of huge ACLs, so do not modify their Meta in place.
 type AclCidr struct {
 	*Meta
 	Inverse *Boolean
@@ -0,0 +1,120 @@
+package ast
+
+import (
+	"bytes"
+)
+
+// SwitchStatement compares the control expression with values of cases in order.
+// Statements of the matched case are executed until break statement,
+// or continue to the statements of the next case on fallthrough statement.
+type SwitchStatement struct {
+	*Meta
+	Control Expression
+	Cases   []*CaseStatement
+	// Index of default case in Cases, -1 if switch does not have default case
+	Default int
+}
+
+func (s *SwitchStatement) statement()     {}
+func (s *SwitchStatement) GetMeta() *Meta { return s.Meta }
+func (s *SwitchStatement) String() string {
+	var buf bytes.Buffer
+
+	buf.WriteString(s.LeadingComment())
+	buf.WriteString(indent(s.Nest) + "switch (")
+	buf.WriteString(s.Control.String())
+	buf.WriteString(") {\n")
+	for _, c := range s.Cases {
+		buf.WriteString(c.String())
+	}
+	buf.WriteString(s.InfixComment())
+	buf.WriteString(indent(s.Nest) + "}")
+	buf.WriteString(s.TrailingComment())
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+// CaseStatement is a case of switch statement, Value is nil for default case.
+// Case label is placed at the same indent of switch statement, and its statements are nested.
+type CaseStatement struct {
+	*Meta
+	// "~" for regular expression matching, empty for exact matching
+	Operator   string
+	Value      Expression
+	Statements []Statement
+}
+
+func (c *CaseStatement) statement()     {}
+func (c *CaseStatement) GetMeta() *Meta { return c.Meta }
+func (c *CaseStatement) String() string {
+	var buf bytes.Buffer
+
+	for _, v := range c.Leading {
+		buf.WriteString(indent(c.Nest-1) + v.String() + "\n")
+	}
+	if c.Value == nil {
+		buf.WriteString(indent(c.Nest-1) + "default:")
+	} else {
+		buf.WriteString(indent(c.Nest-1) + "case ")
+		if c.Operator != "" {
+			buf.WriteString(c.Operator + " ")
+		}
+		buf.WriteString(c.Value.String() + ":")
+	}
+	buf.WriteString(c.TrailingComment())
+	buf.WriteString("\n")
+	for _, stmt := range c.Statements {
+		buf.WriteString(stmt.String())
+	}
+
+	return buf.String()
+}
+
+// IsDefault returns true if the case is default case
+func (c *CaseStatement) IsDefault() bool {
+	return c.Value == nil
+}
+
+// IsFallthrough returns true if the case ends with fallthrough statement
+func (c *CaseStatement) IsFallthrough() bool {
+	if len(c.Statements) == 0 {
+		return false
+	}
+	_, ok := c.Statements[len(c.Statements)-1].(*FallthroughStatement)
+	return ok
+}
+
+type BreakStatement struct {
+	*Meta
+}
+
+func (b *BreakStatement) statement()     {}
+func (b *BreakStatement) GetMeta() *Meta { return b.Meta }
+func (b *BreakStatement) String() string {
+	var buf bytes.Buffer
+
+	buf.WriteString(b.LeadingComment())
+	buf.WriteString(indent(b.Nest) + "break;")
+	buf.WriteString(b.TrailingComment())
+	buf.WriteString("\n")
+
+	return buf.String()
+}
+
+type FallthroughStatement struct {
+	*Meta
+}
+
+func (f *FallthroughStatement) statement()     {}
+func (f *FallthroughStatement) GetMeta() *Meta { return f.Meta }
+func (f *FallthroughStatement) String() string {
+	var buf bytes.Buffer
+
+	buf.WriteString(f.LeadingComment())
+	buf.WriteString(indent(f.Nest) + "fallthrough;")
+	buf.WriteString(f.TrailingComment())
+	buf.WriteString("\n")
+
+	return buf.String()
+}
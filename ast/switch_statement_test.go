@@ -0,0 +1,79 @@
+package ast
+
+import (
+	"testing"
+)
+
+func TestSwitchStatement(t *testing.T) {
+	sw := &SwitchStatement{
+		Meta: New(T, 0, comments("// This is comment"), comments("/* This is comment */"), comments("// This is comment")),
+		Control: &Ident{
+			Meta:  New(T, 0),
+			Value: "req.http.Host",
+		},
+		Cases: []*CaseStatement{
+			{
+				Meta: New(T, 1, comments("// This is comment"), comments("/* This is comment */")),
+				Value: &String{
+					Meta:  New(T, 1),
+					Value: "example.com",
+				},
+				Statements: []Statement{
+					&EsiStatement{
+						Meta: New(T, 1),
+					},
+					&FallthroughStatement{
+						Meta: New(T, 1),
+					},
+				},
+			},
+			{
+				Meta:     New(T, 1),
+				Operator: "~",
+				Value: &String{
+					Meta:  New(T, 1),
+					Value: "^api\\.",
+				},
+				Statements: []Statement{
+					&BreakStatement{
+						Meta: New(T, 1, comments("// This is comment")),
+					},
+				},
+			},
+			{
+				Meta: New(T, 1),
+				Statements: []Statement{
+					&BreakStatement{
+						Meta: New(T, 1),
+					},
+				},
+			},
+		},
+		Default: 2,
+	}
+
+	expect := `// This is comment
+switch (req.http.Host) {
+// This is comment
+case "example.com": /* This is comment */
+  esi;
+  fallthrough;
+case ~ "^api\.":
+  // This is comment
+  break;
+default:
+  break;
+// This is comment
+} /* This is comment */
+`
+
+	if sw.String() != expect {
+		t.Errorf("stringer error.\nexpect:\n%s\nactual:\n%s\n", expect, sw.String())
+	}
+	if !sw.Cases[0].IsFallthrough() || sw.Cases[1].IsFallthrough() {
+		t.Errorf("IsFallthrough returns unexpected value")
+	}
+	if sw.Cases[0].IsDefault() || !sw.Cases[2].IsDefault() {
+		t.Errorf("IsDefault returns unexpected value")
+	}
+}
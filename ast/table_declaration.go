@@ -33,6 +33,8 @@ func (t *TableDeclaration) String() string {
 	return buf.String()
 }
 
+// Key shares the same Meta with the property when the entry does not have any comments
+// in order to reduce memory of huge tables, so do not modify Key.Meta in place.
 type TableProperty struct {
 	*Meta
 	Key      *String
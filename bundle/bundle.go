@@ -0,0 +1,187 @@
+// Package bundle inlines included modules into the main VCL and streams the result,
+// so that the single VCL file could be passed to deploy tools which do not resolve include statements.
+// Source text is copied as it is, then comments and formatting are kept in the bundle.
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Include statement text from "include" keyword to the semicolon, module name could be a long string
+var includeStatement = regexp.MustCompile(`^include\s+(?:"[^"\n]*"|\{"[\s\S]*?"\})\s*;`)
+
+// Included module which is found in the source
+type include struct {
+	module string
+	isRoot bool
+	start  int
+	end    int
+}
+
+// Write streams the main VCL which inlines included modules recursively to the writer.
+// Fastly managed snippets which are included as "snippet::[name]" are kept as include statements
+// because they are resolved by Fastly.
+func Write(w io.Writer, main *resolver.VCL, r resolver.Resolver) error {
+	b := &bundler{
+		w:        w,
+		resolver: r,
+	}
+	return b.write(main.Name, main.Data, true)
+}
+
+type bundler struct {
+	w        io.Writer
+	resolver resolver.Resolver
+	// Stack of modules which are being written to detect cyclic inclusion
+	stack []string
+}
+
+func (b *bundler) write(name, data string, isRoot bool) error {
+	for _, s := range b.stack {
+		if s == name {
+			return fmt.Errorf("Cyclic include detected: %s -> %s", strings.Join(b.stack, " -> "), name)
+		}
+	}
+	b.stack = append(b.stack, name)
+	defer func() { b.stack = b.stack[:len(b.stack)-1] }()
+
+	includes, err := findIncludes(name, data, isRoot)
+	if err != nil {
+		return err
+	}
+
+	var cursor int
+	for _, inc := range includes {
+		if _, err := io.WriteString(b.w, data[cursor:inc.start]); err != nil {
+			return errors.WithStack(err)
+		}
+		module, err := b.resolver.Resolve(&ast.IncludeStatement{
+			Module: &ast.String{Value: inc.module},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to resolve module %s included in %s: %w", inc.module, name, err)
+		}
+		if err := b.write(module.Name, strings.TrimRight(module.Data, "\n"), inc.isRoot); err != nil {
+			return err
+		}
+		cursor = inc.end
+	}
+	if _, err := io.WriteString(b.w, data[cursor:]); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Find include statements and their byte offsets in the source.
+// Include statements in subroutines are parsed as snippet like linter does.
+func findIncludes(name, data string, isRoot bool) ([]*include, error) {
+	p := parser.New(lexer.NewFromString(data, lexer.WithFile(name)))
+	var statements []ast.Statement
+	if isRoot {
+		vcl, err := p.ParseVCL()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %w", name, errors.Cause(err))
+		}
+		statements = vcl.Statements
+	} else {
+		var err error
+		if statements, err = p.ParseSnippetVCL(); err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %w", name, errors.Cause(err))
+		}
+	}
+
+	var found []*ast.IncludeStatement
+	var inRoot []bool
+	var walk func(stmts []ast.Statement, root bool)
+	walk = func(stmts []ast.Statement, root bool) {
+		for _, stmt := range stmts {
+			switch t := stmt.(type) {
+			case *ast.IncludeStatement:
+				found = append(found, t)
+				inRoot = append(inRoot, root)
+			case *ast.SubroutineDeclaration:
+				walk(t.Block.Statements, false)
+			case *ast.BlockStatement:
+				walk(t.Statements, false)
+			case *ast.IfStatement:
+				walk(t.Consequence.Statements, false)
+				for _, a := range t.Another {
+					walk(a.Consequence.Statements, false)
+				}
+				if t.Alternative != nil {
+					walk(t.Alternative.Statements, false)
+				}
+			case *ast.SwitchStatement:
+				for _, c := range t.Cases {
+					walk(c.Statements, false)
+				}
+			}
+		}
+	}
+	walk(statements, isRoot)
+
+	offsets := lineOffsets(data)
+	var includes []*include
+	for i, stmt := range found {
+		if strings.HasPrefix(stmt.Module.Value, "snippet::") {
+			continue
+		}
+		tok := stmt.GetMeta().Token
+		start, ok := byteOffset(data, offsets, tok.Line, tok.Position)
+		if !ok {
+			return nil, fmt.Errorf("Could not find include statement at line %d in %s", tok.Line, name)
+		}
+		loc := includeStatement.FindStringIndex(data[start:])
+		if loc == nil {
+			return nil, fmt.Errorf("Could not find include statement at line %d in %s", tok.Line, name)
+		}
+		includes = append(includes, &include{
+			module: stmt.Module.Value,
+			isRoot: inRoot[i],
+			start:  start,
+			end:    start + loc[1],
+		})
+	}
+	sort.Slice(includes, func(i, j int) bool {
+		return includes[i].start < includes[j].start
+	})
+	return includes, nil
+}
+
+// Byte offsets of the beginning of each line
+func lineOffsets(data string) []int {
+	offsets := []int{0}
+	for i := 0; i < len(data); i++ {
+		if data[i] == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// Convert line and position, which is counted in characters from 1, to byte offset
+func byteOffset(data string, offsets []int, line, position int) (int, bool) {
+	if line < 1 || line > len(offsets) || position < 1 {
+		return 0, false
+	}
+	offset := offsets[line-1]
+	for i := 1; i < position; i++ {
+		if offset >= len(data) || data[offset] == '\n' {
+			return 0, false
+		}
+		_, size := utf8.DecodeRuneInString(data[offset:])
+		offset += size
+	}
+	return offset, true
+}
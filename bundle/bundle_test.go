@@ -0,0 +1,113 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+type mapResolver map[string]string
+
+func (m mapResolver) MainVCL() (*resolver.VCL, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m mapResolver) Resolve(stmt *ast.IncludeStatement) (*resolver.VCL, error) {
+	v, ok := m[stmt.Module.Value]
+	if !ok {
+		return nil, errors.New(stmt.Module.Value + " is not found")
+	}
+	return &resolver.VCL{Name: stmt.Module.Value + ".vcl", Data: v}, nil
+}
+
+func (m mapResolver) Name() string {
+	return ""
+}
+
+func TestWrite(t *testing.T) {
+	main := `# main
+include "backends";
+include "snippet::managed";
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo) {
+    include "recv";
+  }
+  return(lookup);
+}
+`
+	r := mapResolver{
+		"backends": "include \"origin\";\n",
+		"origin": `backend F_origin {
+  .host = "example.com";
+}
+`,
+		"recv": `set req.http.Recv = "1"; # ünïcode`,
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, &resolver.VCL{Name: "main.vcl", Data: main}, r); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expect := `# main
+backend F_origin {
+  .host = "example.com";
+}
+include "snippet::managed";
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo) {
+    set req.http.Recv = "1"; # ünïcode
+  }
+  return(lookup);
+}
+`
+	if diff := cmp.Diff(expect, buf.String()); diff != "" {
+		t.Errorf("Bundle mismatch, diff=%s", diff)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		main   string
+		r      mapResolver
+		expect string
+	}{
+		{
+			name:   "module not found",
+			main:   `include "missing";`,
+			r:      mapResolver{},
+			expect: "Failed to resolve module missing",
+		},
+		{
+			name:   "cyclic include",
+			main:   `include "a";`,
+			r:      mapResolver{"a": `include "b";`, "b": `include "a";`},
+			expect: "Cyclic include detected: main.vcl -> a.vcl -> b.vcl -> a.vcl",
+		},
+		{
+			name:   "parse error",
+			main:   `include "a";`,
+			r:      mapResolver{"a": `sub foo {`},
+			expect: "Failed to parse a.vcl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Write(&buf, &resolver.VCL{Name: "main.vcl", Data: tt.main}, tt.r)
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("Error should contain %q, got %v", tt.expect, err)
+			}
+		})
+	}
+}
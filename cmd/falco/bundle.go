@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ysugimoto/falco/bundle"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Diagnostic which is written to stderr as a JSON line in pipeline mode
+type diagnosticLine struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"`
+	Position  int    `json:"position"`
+	Severity  string `json:"severity"`
+	Rule      string `json:"rule,omitempty"`
+	Message   string `json:"message"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// Stream the main VCL which inlines included modules to stdout or the output file.
+// When lint option is specified, the bundle is not written if lint errors are found.
+func runBundle(runner *Runner, rslv resolver.Resolver) error {
+	c := runner.config
+	if c.Bundle.Stdout && c.Bundle.Output != "" {
+		writeln(red, "--stdout and -o option could not be specified at the same time")
+		return ErrExit
+	}
+	if c.Bundle.Lint && !lintBundle(runner, rslv) {
+		return ErrExit
+	}
+
+	main, err := rslv.MainVCL()
+	if err != nil {
+		writeln(red, err.Error())
+		return ErrExit
+	}
+	if c.Bundle.Output == "" {
+		if err := writeBundle(os.Stdout, main, rslv); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+		return nil
+	}
+
+	f, err := os.Create(c.Bundle.Output)
+	if err != nil {
+		writeln(red, "Failed to create %s: %s", c.Bundle.Output, err)
+		return ErrExit
+	}
+	err = writeBundle(f, main, rslv)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		// Do not leave incomplete bundle
+		os.Remove(c.Bundle.Output) // nolint:errcheck
+		writeln(red, err.Error())
+		return ErrExit
+	}
+	writeln(green, "Bundled VCL is written to %s", c.Bundle.Output)
+	return nil
+}
+
+func writeBundle(w io.Writer, main *resolver.VCL, rslv resolver.Resolver) error {
+	bw := bufio.NewWriter(w)
+	if err := bundle.Write(bw, main, rslv); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("Failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// Lint the VCL and write diagnostics to stderr as JSON lines, returns false when errors are found
+func lintBundle(runner *Runner, rslv resolver.Resolver) bool {
+	// JSON mode suppresses human readable messages and collects diagnostics
+	runner.config.Json = true
+	enc := json.NewEncoder(os.Stderr)
+
+	result, err := runner.Run(rslv)
+	if err != nil {
+		enc.Encode(&diagnosticLine{Severity: "Error", Message: err.Error()}) // nolint:errcheck
+		return false
+	}
+
+	for _, pe := range result.ParseErrors {
+		enc.Encode(&diagnosticLine{ // nolint:errcheck
+			File:     pe.Token.File,
+			Line:     pe.Token.Line,
+			Position: pe.Token.Position,
+			Severity: "Error",
+			Message:  pe.Message,
+		})
+	}
+
+	files := make([]string, 0, len(result.LintErrors))
+	for file := range result.LintErrors {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		for _, d := range result.LintErrors[file] {
+			enc.Encode(&diagnosticLine{ // nolint:errcheck
+				File:      d.Token.File,
+				Line:      d.Token.Line,
+				Position:  d.Token.Position,
+				Severity:  string(d.Severity),
+				Rule:      string(d.Rule),
+				Message:   d.Message,
+				Reference: d.Reference,
+			})
+		}
+	}
+	return result.Errors == 0 && len(result.ParseErrors) == 0
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter/dashboard"
+)
+
+const (
+	cacheCommandList  = "ls"
+	cacheCommandGet   = "get"
+	cacheCommandPurge = "purge"
+)
+
+// cacheClient communicates with cache inspection API of running simulator
+type cacheClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newCacheClient(port int) *cacheClient {
+	return &cacheClient{
+		endpoint: fmt.Sprintf("http://localhost:%d%s", port, dashboard.CachePath),
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (c *cacheClient) do(method, hash string, v any) error {
+	endpoint := c.endpoint
+	if hash != "" {
+		endpoint += "?hash=" + url.QueryEscape(hash)
+	}
+	req, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to connect simulator, ensure simulator is running: %w", err)
+	}
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(buf, &e); err == nil && e.Error != "" {
+			return fmt.Errorf("%s", e.Error)
+		}
+		return fmt.Errorf("Simulator responds unexpected status code %d", resp.StatusCode)
+	}
+	return json.Unmarshal(buf, v)
+}
+
+func runCache(c *config.Config) error {
+	client := newCacheClient(c.Simulator.Port)
+
+	switch c.Commands.At(1) {
+	case cacheCommandList:
+		var entries []*dashboard.CacheEntry
+		if err := client.do(http.MethodGet, "", &entries); err != nil {
+			return err
+		}
+		if c.Json {
+			return json.NewEncoder(os.Stdout).Encode(entries)
+		}
+		if len(entries) == 0 {
+			writeln(white, "No cache entries")
+			return nil
+		}
+		for _, e := range entries {
+			writeln(white, "%s", e.Hash)
+			writeln(white, "  status: %d, ttl: %.1fs, grace: %.1fs, hits: %d", e.StatusCode, e.TTL, e.Grace, e.Hits)
+		}
+		return nil
+	case cacheCommandGet:
+		hash := c.Commands.At(2)
+		if hash == "" {
+			return fmt.Errorf("Cache hash to get must be specified")
+		}
+		var entry dashboard.CacheEntry
+		if err := client.do(http.MethodGet, hash, &entry); err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entry)
+	case cacheCommandPurge:
+		hash := c.Commands.At(2)
+		if hash == "" && !c.Cache.All {
+			return fmt.Errorf("Cache hash to purge must be specified, or provide --all option to purge all entries")
+		}
+		var result struct {
+			Purged int `json:"purged"`
+		}
+		if err := client.do(http.MethodDelete, hash, &result); err != nil {
+			return err
+		}
+		writeln(green, "Purged %d cache entries", result.Purged)
+		return nil
+	case "":
+		printHelp(subcommandCache)
+		return ErrExit
+	default:
+		return fmt.Errorf("Unrecognized cache command: %s", c.Commands.At(1))
+	}
+}
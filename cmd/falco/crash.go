@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ysugimoto/falco/crash"
+)
+
+// Recover the crash of the command, write the crash bundle and exit with the distinct code.
+// This function must be deferred at the beginning of main.
+func recoverCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	bundle := crash.NewBundle(r, version, os.Args[1:])
+	writeln(red, ":boom: falco crashed: %s", bundle.Panic)
+	for _, path := range bundle.ASTPath {
+		writeln(white, "    %s", path)
+	}
+	if file, err := bundle.Write(os.Getenv(crash.DirEnv)); err != nil {
+		writeln(red, "Failed to write crash bundle: %s", err)
+		writeln(white, bundle.Stack)
+	} else {
+		writeln(white, "Crash bundle is written to %s", file)
+		writeln(white, "Please report the issue with the bundle at https://github.com/ysugimoto/falco/issues")
+	}
+	os.Exit(crash.ExitCode)
+}
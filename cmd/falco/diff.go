@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/linter"
+)
+
+// Changed lines are loaded once and shared between runners, because the diff file may be stdin
+var changedLines struct {
+	once    sync.Once
+	changes linter.ChangedLines
+	err     error
+}
+
+// Load changed lines from the diff file or git diff, returns nil when diff mode is not enabled
+func loadChangedLines(c *config.LinterConfig) (linter.ChangedLines, error) {
+	if c.Diff == "" && c.DiffBase == "" {
+		return nil, nil
+	}
+	changedLines.once.Do(func() {
+		if c.Diff != "" {
+			changedLines.changes, changedLines.err = readDiffFile(c.Diff)
+			return
+		}
+		changedLines.changes, changedLines.err = gitDiff(c.DiffBase)
+	})
+	return changedLines.changes, changedLines.err
+}
+
+// Read unified diff file, "-" means stdin. File paths in the diff are relative from the working directory
+func readDiffFile(file string) (linter.ChangedLines, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get working directory: %w", err)
+	}
+	if file == "-" {
+		return linter.ParseDiff(os.Stdin, cwd)
+	}
+	fp, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open diff file %s: %w", file, err)
+	}
+	defer fp.Close()
+	return linter.ParseDiff(fp, cwd)
+}
+
+// Get changes of the working tree from the revision via git command.
+// File paths in git diff are relative from the repository root.
+func gitDiff(base string) (linter.ChangedLines, error) {
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	out, err := runGit("diff", "--unified=0", "--no-color", "--no-ext-diff", base, "--")
+	if err != nil {
+		return nil, err
+	}
+	return linter.ParseDiff(strings.NewReader(out), strings.TrimSpace(root))
+}
+
+func runGit(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("Failed to run git %s: %w\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return stdout.String(), nil
+}
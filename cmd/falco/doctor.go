@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/ysugimoto/falco/doctor"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Data of doctor command output
+type doctorOutput struct {
+	*doctor.Report
+	Scorecards []*doctor.Scorecard `json:"scorecards"`
+	Score      int                 `json:"score"`
+	Ready      bool                `json:"ready"`
+}
+
+func runDoctor(runner *Runner, rslv resolver.Resolver) error {
+	report, err := runner.Doctor(rslv)
+	if err != nil {
+		if runner.config.Json {
+			out := errorCommandOutput(subcommandDoctor, err)
+			if err == ErrParser {
+				out.Data = map[string]interface{}{"parse_errors": runner.parseErrors}
+			}
+			out.Write(os.Stdout) // nolint:errcheck
+		} else if err != ErrParser {
+			writeln(red, err.Error())
+		}
+		return ErrExit
+	}
+
+	if runner.config.Json {
+		if err := doctorCommandOutput(report).Write(os.Stdout); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+	} else {
+		printDoctorReport(report)
+	}
+
+	if report.Failed() {
+		return ErrExit
+	}
+	return nil
+}
+
+func doctorCommandOutput(report *doctor.Report) *CommandOutput {
+	status := outputStatusSuccess
+	switch {
+	case report.Failed():
+		status = outputStatusFailure
+	case report.Count(doctor.StatusWarn) > 0:
+		status = outputStatusWarning
+	}
+	summary := fmt.Sprintf("Readiness %d%%: %d passed, %d warnings, %d failed",
+		report.Score(), report.Count(doctor.StatusPass), report.Count(doctor.StatusWarn), report.Count(doctor.StatusFail))
+
+	return newCommandOutput(subcommandDoctor, status, summary, &doctorOutput{
+		Report:     report,
+		Scorecards: report.Scorecards(),
+		Score:      report.Score(),
+		Ready:      !report.Failed(),
+	})
+}
+
+func printDoctorReport(report *doctor.Report) {
+	if len(report.Results) == 0 {
+		writeln(white, "No resources to check")
+		return
+	}
+
+	for _, r := range report.Results {
+		var mark string
+		switch r.Status {
+		case doctor.StatusPass:
+			mark = ":white_check_mark:"
+		case doctor.StatusWarn:
+			mark = ":warning:"
+		case doctor.StatusFail:
+			mark = ":x:"
+		default:
+			mark = ":fast_forward:"
+		}
+		writeln(statusColor(r.Status), "%s [%s] %s: %s", mark, r.Category, r.Target, r.Message)
+	}
+
+	writeln(white, "")
+	writeln(white, "Readiness Scorecard")
+	writeln(white, "---------------------------------------------")
+	for _, card := range report.Scorecards() {
+		writeln(statusColor(card.Status()), "%-8s %-5s %3d passed, %3d warnings, %3d failed, %3d skipped",
+			card.Category, card.Status(), card.Pass, card.Warn, card.Fail, card.Skip)
+	}
+	writeln(white, "---------------------------------------------")
+	write(green, "%d passed, ", report.Count(doctor.StatusPass))
+	write(yellow, "%d warnings, ", report.Count(doctor.StatusWarn))
+	writeln(red, "%d failed", report.Count(doctor.StatusFail))
+	if report.Failed() {
+		writeln(red, "Readiness: %d%% - NOT READY to deploy", report.Score())
+	} else {
+		writeln(green, "Readiness: %d%% - READY to deploy", report.Score())
+	}
+}
+
+func statusColor(status doctor.Status) *color.Color {
+	switch status {
+	case doctor.StatusPass:
+		return green
+	case doctor.StatusWarn:
+		return yellow
+	case doctor.StatusFail:
+		return red
+	}
+	return white
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/migrate"
+)
+
+// Explain the linter rule, or list all rules when the rule is not specified
+func runExplain(c *config.Config) error {
+	name := c.Commands.At(1)
+	if name == "" {
+		return listRules(c)
+	}
+	if renamed, ok := migrate.RenamedRule(name); ok {
+		if !c.Json {
+			writeln(yellow, "Rule %s is renamed to %s", name, renamed)
+		}
+		name = renamed
+	}
+	doc, err := linter.Describe(linter.Rule(name))
+	if err != nil {
+		return err
+	}
+
+	if c.Json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+
+	title := string(doc.Rule) + " (" + string(doc.Category) + ")"
+	writeln(white, title)
+	writeln(white, strings.Repeat("=", len(title)))
+	writeln(white, doc.Summary)
+	if doc.Rationale != "" {
+		writeln(white, "")
+		writeln(white, doc.Rationale)
+	}
+	for _, ex := range doc.Examples {
+		writeln(white, "")
+		if ex.Title != "" {
+			writeln(cyan, "%s:", ex.Title)
+		}
+		for _, line := range strings.Split(ex.Code, "\n") {
+			if line == "" {
+				writeln(white, "")
+				continue
+			}
+			writeln(white, "    %s", line)
+		}
+	}
+	writeln(white, "")
+	writeln(white, "References:")
+	for _, ref := range doc.References {
+		writeln(white, "  - %s", ref)
+	}
+	return nil
+}
+
+func listRules(c *config.Config) error {
+	var docs []*linter.RuleDocument
+	for _, rule := range linter.DescribedRules() {
+		doc, err := linter.Describe(rule)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	}
+
+	if c.Json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(docs)
+	}
+
+	var width int
+	for _, doc := range docs {
+		if len(doc.Rule) > width {
+			width = len(doc.Rule)
+		}
+	}
+	for _, doc := range docs {
+		// Show only the first sentence of the summary in the list
+		summary, _, _ := strings.Cut(doc.Summary, "\n")
+		writeln(white, "%-*s %s", width, doc.Rule, summary)
+	}
+	writeln(white, "")
+	writeln(white, "Run falco explain [rule] to see the details of the rule")
+	return nil
+}
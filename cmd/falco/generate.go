@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/generator"
+	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/remote"
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/tester"
+)
+
+const (
+	generateTargetDevices   = "devices"
+	generateTargetTables    = "tables"
+	generateTargetAcl       = "acl"
+	generateTargetRedirects = "redirects"
+)
+
+var aclNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+func runGenerate(c *config.Config) error {
+	switch c.Commands.At(1) {
+	case generateTargetDevices:
+		return generateDevices(c.Generate)
+	case generateTargetTables:
+		return generateTables(c, c.Commands.At(2))
+	case generateTargetAcl:
+		return generateAcl(c, c.Commands.At(2))
+	case generateTargetRedirects:
+		return generateRedirects(c, c.Commands.At(2))
+	case "":
+		printHelp(subcommandGenerate)
+		return ErrExit
+	default:
+		return fmt.Errorf("Unrecognized generate target: %s", c.Commands.At(1))
+	}
+}
+
+func generateDevices(c *config.GenerateConfig) error {
+	list, err := generator.LoadDeviceList(c.DevicesSource)
+	if err != nil {
+		return fmt.Errorf("Failed to load device list: %w", err)
+	}
+	devices := generator.GenerateDevices(list)
+
+	if err := os.MkdirAll(c.Output, 0o755); err != nil {
+		return fmt.Errorf("Failed to create output directory: %w", err)
+	}
+	files := []struct {
+		name    string
+		content string
+	}{
+		{name: "devices.vcl", content: devices.VCL},
+		{name: "devices.test.vcl", content: devices.Test},
+	}
+	for _, f := range files {
+		path := filepath.Join(c.Output, f.name)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			return fmt.Errorf("Failed to write %s: %w", path, err)
+		}
+		writeln(green, "Generated %s", path)
+	}
+	return nil
+}
+
+func generateTables(c *config.Config, file string) error {
+	if file == "" {
+		return fmt.Errorf("VCL file to split tables must be specified")
+	}
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Failed to read %s: %w", file, err)
+	}
+	vcl, err := parser.New(lexer.NewFromString(string(buf), lexer.WithFile(file))).ParseVCL()
+	if err != nil {
+		return fmt.Errorf("Failed to parse %s: %w", file, err)
+	}
+	result, err := generator.SplitLargeTables(vcl, c.Generate.TableLimit)
+	if err != nil {
+		return fmt.Errorf("Failed to split tables: %w", err)
+	}
+	if len(result.Tables) == 0 {
+		writeln(white, "No tables exceed %d items, nothing to do", c.Generate.TableLimit)
+		return nil
+	}
+
+	output := filepath.Join(c.Generate.Output, filepath.Base(file))
+	if src, err := filepath.Abs(file); err != nil {
+		return fmt.Errorf("Failed to get absolute path of %s: %w", file, err)
+	} else if dst, err := filepath.Abs(output); err != nil {
+		return fmt.Errorf("Failed to get absolute path of %s: %w", output, err)
+	} else if src == dst {
+		return fmt.Errorf("Output file must be different from %s, specify other directory with -o option", file)
+	}
+	testFile := strings.TrimSuffix(output, ".vcl") + ".tables.test.vcl"
+
+	if err := os.MkdirAll(c.Generate.Output, 0o755); err != nil {
+		return fmt.Errorf("Failed to create output directory: %w", err)
+	}
+	for path, content := range map[string]string{output: result.VCL, testFile: result.Test} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("Failed to write %s: %w", path, err)
+		}
+	}
+	writeln(green, "Split tables %s into %s", strings.Join(result.Tables, ", "), output)
+	writeln(green, "Generated %s", testFile)
+
+	// Verify partitioned lookups are equivalent to original tables by running generated tests on the interpreter
+	return verifyGeneratedTests(
+		c, output, testFile, "Partitioned tables are not equivalent to original tables", filepath.Dir(file),
+	)
+}
+
+// Run generated test file on the interpreter, output file is used as main VCL
+func verifyGeneratedTests(c *config.Config, output, testFile, failure string, includePaths ...string) error {
+	includePaths = append(includePaths, c.IncludePaths...)
+	resolvers, err := resolver.NewFileResolvers(output, includePaths)
+	if err != nil {
+		return fmt.Errorf("Failed to verify generated tests: %w", err)
+	}
+	// Run only generated test file which is placed at the same directory of the output
+	factory, err := tester.New(&config.TestConfig{
+		Filter: "*/" + filepath.Base(testFile),
+	}, []icontext.Option{icontext.WithResolver(resolvers[0])}).Run(output)
+	if err != nil {
+		return fmt.Errorf("Failed to verify generated tests: %w", err)
+	}
+
+	var failed bool
+	for _, r := range factory.Results {
+		for _, tc := range r.Cases {
+			if tc.Error != nil {
+				failed = true
+				writeln(red, "%s: %s", tc.Name, tc.Error)
+			}
+		}
+	}
+	if failed {
+		return fmt.Errorf("%s", failure)
+	}
+	writeln(green, "Verified %d assertions on the interpreter", factory.Statistics.Asserts)
+	return nil
+}
+
+func generateRedirects(c *config.Config, file string) error {
+	if file == "" {
+		return fmt.Errorf("Redirect map file must be specified")
+	}
+	redirects, err := generator.LoadRedirects(file)
+	if err != nil {
+		return fmt.Errorf("Failed to load redirect map: %w", err)
+	}
+	// Subroutine and table names are prefixed by the file name like "redirects_recv"
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	if !aclNameRegex.MatchString(name) {
+		return fmt.Errorf("Redirect map file name must be valid VCL identifier, got %q", name)
+	}
+	result, err := generator.GenerateRedirects(name, redirects, c.Generate.TableLimit)
+	if err != nil {
+		return fmt.Errorf("Failed to generate redirects: %w", err)
+	}
+
+	if err := os.MkdirAll(c.Generate.Output, 0o755); err != nil {
+		return fmt.Errorf("Failed to create output directory: %w", err)
+	}
+	output := filepath.Join(c.Generate.Output, name+".vcl")
+	testFile := filepath.Join(c.Generate.Output, name+".test.vcl")
+	for path, content := range map[string]string{output: result.VCL, testFile: result.Test} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("Failed to write %s: %w", path, err)
+		}
+	}
+	writeln(green, "Generated %s with %d redirects", output, len(redirects))
+	if len(result.SplitTables) > 0 {
+		writeln(green, "Split tables %s into partitions", strings.Join(result.SplitTables, ", "))
+	}
+	writeln(green, "Generated %s", testFile)
+	writeln(white, "Call %s_recv in vcl_recv and %s_error in vcl_error to handle redirects", name, name)
+
+	return verifyGeneratedTests(c, output, testFile, "Generated redirects do not work as the redirect map")
+}
+
+func generateAcl(c *config.Config, name string) error {
+	if !aclNameRegex.MatchString(name) {
+		return fmt.Errorf("Valid ACL name must be specified, got %q", name)
+	}
+	if c.Remote && (c.FastlyServiceID == "" || c.FastlyApiKey == "") {
+		return fmt.Errorf("Both FASTLY_SERVICE_ID and FASTLY_API_KEY environment variables must be specified")
+	}
+	prefixes, err := generator.LoadIPList(c.Generate.FromURL)
+	if err != nil {
+		return fmt.Errorf("Failed to load IP list: %w", err)
+	}
+
+	if err := os.MkdirAll(c.Generate.Output, 0o755); err != nil {
+		return fmt.Errorf("Failed to create output directory: %w", err)
+	}
+	output := filepath.Join(c.Generate.Output, name+".vcl")
+	acl := generator.GenerateACL(name, c.Generate.FromURL, prefixes)
+	if err := os.WriteFile(output, []byte(acl), 0o644); err != nil {
+		return fmt.Errorf("Failed to write %s: %w", output, err)
+	}
+	writeln(green, "Generated %s with %d entries", output, len(prefixes))
+
+	if !c.Remote {
+		return nil
+	}
+	return syncAcl(c, name, prefixes)
+}
+
+func syncAcl(c *config.Config, name string, prefixes []netip.Prefix) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := remote.NewFastlyClient(http.DefaultClient, c.FastlyServiceID, c.FastlyApiKey)
+	version, err := client.LatestVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get latest version: %w", err)
+	}
+	acl, err := client.GetAccessControl(ctx, version, name)
+	if err != nil {
+		return fmt.Errorf("Failed to get ACL %s, the ACL must exist in the active version: %w", name, err)
+	}
+	entries, err := client.ListAccessControlEntries(ctx, acl.Id)
+	if err != nil {
+		return fmt.Errorf("Failed to list entries of ACL %s: %w", name, err)
+	}
+
+	ops := generator.DiffACLEntries(prefixes, entries)
+	if len(ops) == 0 {
+		writeln(white, "ACL %s is up to date", name)
+		return nil
+	}
+	for _, op := range ops {
+		entry := op.Ip
+		if op.Subnet != nil {
+			entry += fmt.Sprintf("/%d", *op.Subnet)
+		}
+		if op.Negated == "1" {
+			entry = "!" + entry
+		}
+		if op.Op == "delete" {
+			writeln(red, "- %s", entry)
+		} else {
+			writeln(green, "+ %s", entry)
+		}
+	}
+	if c.DryRun {
+		writeln(yellow, "Dry run: %d changes are not applied to ACL %s", len(ops), name)
+		return nil
+	}
+	if err := client.BatchUpdateAccessControlEntries(ctx, acl.Id, ops); err != nil {
+		return fmt.Errorf("Failed to update entries of ACL %s: %w", name, err)
+	}
+	writeln(green, "Applied %d changes to ACL %s", len(ops), name)
+	return nil
+}
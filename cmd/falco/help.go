@@ -17,6 +17,30 @@ func printHelp(cmd string) {
 		printTestHelp()
 	case subcommandLint:
 		printLintHelp()
+	case subcommandGenerate:
+		printGenerateHelp()
+	case subcommandSync:
+		printSyncHelp()
+	case subcommandCache:
+		printCacheHelp()
+	case subcommandTimeTravel:
+		printTimeTravelHelp()
+	case subcommandDoctor:
+		printDoctorHelp()
+	case subcommandRoutes:
+		printRoutesHelp()
+	case subcommandMigrateConfig:
+		printMigrateConfigHelp()
+	case subcommandPlugin:
+		printPluginHelp()
+	case subcommandBundle:
+		printBundleHelp()
+	case subcommandExplain:
+		printExplainHelp()
+	case subcommandImport:
+		printImportHelp()
+	case subcommandLoad:
+		printLoadHelp()
 	default:
 		printGlobalHelp()
 	}
@@ -46,6 +70,18 @@ Subcommands:
     stats     : Analyze VCL statistics
     simulate  : Run simulator server with provided VCLs
     test      : Run local testing for provided VCLs
+    generate  : Generate VCLs and tests from maintained lists
+    sync      : Sync declarative table data to Fastly
+    cache     : Inspect and purge cache of running simulator
+    timetravel: View time-travel execution log of simulated request
+    doctor    : Run preflight checks before deploy
+    routes    : Export decision table of routing logic in vcl_recv
+    migrate-config: Rewrite configuration and VCL comments for the current version
+    plugin    : Install and list plugins
+    bundle    : Output single VCL which inlines included modules
+    explain   : Show documentation of linter rules
+    import    : Import custom VCLs and resources of Fastly service as falco project
+    load      : Generate synthetic traffic against the simulator and report metrics per route
 
 See subcommands help with:
     falco [subcommand] -h
@@ -54,10 +90,26 @@ All Flags:
     -I, --include_path : Add include path
     -h, --help         : Show this help
     -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
     -V, --version      : Display build version
     -v                 : Output lint warnings (verbose)
     -vv                : Output all lint results (very verbose)
     -json              : Output results as JSON (very verbose)
+    --compliance       : Enable compliance rule pack
+    --only             : Report only rules in the categories (comma separated)
+    --skip             : Skip rules in the categories (comma separated)
+    --fix              : Apply suggested fixes of lint errors to the source files
+    --baseline         : Report only lint errors which are not recorded in the baseline file
+    --update-baseline  : Record all current lint errors to the baseline file
+    --diff             : Report only lint errors on changed lines of the unified diff file, "-" reads stdin
+    --diff-base        : Report only lint errors on changed lines from the git revision
+    --parallel         : Number of entry VCLs which are linted concurrently
+    --explain-fatal    : Report partial results and context of the fatal error instead of aborting
+    --fail-fast        : Stop linting at the first error
+    --profile-rules    : Report time spent for each rule
+    --rego             : Evaluate Rego policy file or directory via opa command
+    --facts            : Export facts about VCL as JSON to the file
+    --lang             : Language of diagnostic messages (en, ja)
 
 Simple linting example:
     falco -I . -vv /path/to/vcl/main.vcl
@@ -81,6 +133,7 @@ Flags:
     -v                 : Output lint warnings (verbose)
     -vv                : Output all lint results (very verbose)
     -json              : Output results as JSON (very verbose)
+    --compliance       : Enable compliance rule pack
 
 Linting with terraform:
     terraform plan -out planned.out
@@ -97,8 +150,15 @@ Flags:
     -I, --include_path : Add include path
     -h, --help         : Show this help
     -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
     -request           : Simulate request config
     -debug             : Enable debug mode
+    --shadow           : Mirror backend requests to the shadow backend URL
+    --check_debug_headers : Fail when debug headers leak to the response
+    --debug_response   : Add Falco-Debug-* headers which summarize the process to the response
+    --dashboard        : Serve web UI dashboard on /_falco/
+    --time_travel      : Record time-travel execution log to the response
+    --explain-cache    : Explain why the response is cached or not
     --max_backends     : Override max backends limitation
     --max_acls         : Override max acls limitation
 
@@ -107,6 +167,9 @@ Local simulator example:
 
 Local debugger example:
     falco simulate -I . -debug /path/to/vcl/main.vcl
+
+Multiple services which are configured in .falco.yml example:
+    falco simulate
 	`))
 }
 
@@ -119,6 +182,7 @@ Flags:
     -I, --include_path : Add include path
     -h, --help         : Show this help
     -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
     -json              : Output results as JSON
 
 Get statistics example:
@@ -135,10 +199,16 @@ Flags:
     -I, --include_path : Add include path
     -h, --help         : Show this help
     -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
     -t, --timeout      : Set timeout to running test
     -f, --filter       : Override glob filter to find test files
     -json              : Output results as JSON
     -request           : Override request config
+    --explain-cache    : Explain caching decision of each test case
+    -p, --parallel     : Number of test files which are run concurrently
+    --retries          : Number of retries for failed tests
+    --quarantine       : Suite name pattern of quarantined test
+    --format           : Output format, text (default), json or junit
     --max_backends     : Override max backends limitation
     --max_acls         : Override max acls limitation
 
@@ -156,12 +226,300 @@ Flags:
     -I, --include_path : Add include path
     -h, --help         : Show this help
     -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
     -V, --version      : Display build version
     -v                 : Output lint warnings (verbose)
     -vv                : Output all lint results (very verbose)
     -json              : Output results as JSON (very verbose)
+    --format           : Output format, text (default), json which is the structured report or junit
+    --junit-group      : Test case unit of JUnit XML output, file (default) or rule
+    --compliance       : Enable compliance rule pack
+    --fix              : Apply suggested fixes of lint errors to the source files
+    --baseline         : Report only lint errors which are not recorded in the baseline file
+    --update-baseline  : Record all current lint errors to the baseline file
+    --diff             : Report only lint errors on changed lines of the unified diff file, "-" reads stdin
+    --diff-base        : Report only lint errors on changed lines from the git revision
+    --parallel         : Number of entry VCLs which are linted concurrently
+    --explain-fatal    : Report partial results and context of the fatal error instead of aborting
+    --fail-fast        : Stop linting at the first error
+    --profile-rules    : Report time spent for each rule
 
 Simple linting with very verbose example:
     falco lint -I . -vv /path/to/vcl/main.vcl
+
+Linting only crucial rules example:
+    falco lint -I . --only correctness,security /path/to/vcl/main.vcl
+
+Linting multiple services concurrently example:
+    falco lint -I ./includes /path/to/www/main.vcl /path/to/api/main.vcl
+	`))
+}
+
+func printGenerateHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco generate [target] [flags]
+
+Targets:
+    devices   : Generate device detection and crawler normalization VCL with tests
+    tables    : Split large tables into partitioned tables with equivalence tests
+    acl       : Generate ACL declaration from IP list feed
+    redirects : Compile CSV or YAML redirect map into redirect tables and handlers with tests
+
+Flags:
+    -I, --include_path : Add include path
+    -h, --help         : Show this help
+    -o, --output       : Output directory, default is current directory
+    -r, --remote       : Sync generated ACL entries to Fastly ACL
+    --source           : Device list file path or URL, bundled list is used if omitted
+    --table_limit      : Maximum items per table, default is 1000
+    --from-url         : IP list feed file path or URL for ACL generation
+    --dry-run          : Show ACL entry changes without applying them on remote sync
+
+Generate device detection example:
+    falco generate devices -o ./vcl
+
+Split large tables example:
+    falco generate tables -o ./dist /path/to/vcl/main.vcl
+
+Generate ACL and sync to Fastly example:
+    falco generate acl office --from-url https://example.com/office-ips.txt -r --dry-run
+
+Generate redirects example:
+    falco generate redirects -o ./vcl ./redirects.csv
+	`))
+}
+
+func printSyncHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco sync [target] [name] [flags]
+
+Targets:
+    table : Sync edge dictionary items with JSON table data
+
+Flags:
+    -h, --help         : Show this help
+    --from             : JSON file of the table data
+    --dry-run          : Show changes without applying them
+    --format           : Output format, text (default) or json
+
+Sync edge dictionary example:
+    falco sync table redirects --from ./data/redirects.json --dry-run
+	`))
+}
+
+func printImportHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco import [flags]
+
+Flags:
+    -h, --help         : Show this help
+    --service-id       : Service ID to import, FASTLY_SERVICE_ID environment variable is used when omitted
+    -o, --output       : Output directory of the project (default: current directory)
+    --force            : Overwrite existing files in the output directory
+    --format           : Output format, text (default) or json
+
+Import service example:
+    FASTLY_API_KEY=xxx falco import --service-id=SU1Z0isxPaozGVKXdv0eY -o ./my-service
+    cd ./my-service && falco lint vcl/main.vcl
+	`))
+}
+
+func printCacheHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco cache [command] [hash] [flags]
+
+Commands:
+    ls    : List cache entries of running simulator
+    get   : Inspect cache entry of the hash
+    purge : Purge cache entry of the hash
+
+Flags:
+    -h, --help         : Show this help
+    -p, --port         : Port of running simulator
+    -json              : Output cache entries as JSON
+    --all              : Purge all cache entries
+
+Purge all cache entries example:
+    falco cache purge --all -p 3124
+	`))
+}
+
+func printTimeTravelHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco timetravel [summary json file]
+
+Flags:
+    -h, --help         : Show this help
+
+Record and view time-travel execution log example:
+    falco simulate --time_travel /path/to/vcl/main.vcl
+    curl -s http://localhost:3124/ > summary.json
+    falco timetravel summary.json
+
+Read from stdin example:
+    curl -s http://localhost:3124/ | falco timetravel
+	`))
+}
+
+func printDoctorHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco doctor [flags]
+
+Flags:
+    -I, --include_path : Add include path
+    -h, --help         : Show this help
+    -r, --remote       : Connect with Fastly API, domains and logging endpoints of the service are also checked
+    -json              : Output results as JSON
+    --format           : Output format, text (default) or json
+    --max_backends     : Override max backends limitation
+    --max_acls         : Override max acls limitation
+
+Checks:
+    lint    : Run linter, any errors fail
+    include : Verify all include statements are resolved
+    dns     : Resolve backend hosts which are not IP addresses
+    tls     : TLS handshake to backends which enable TLS, verifies certificate hostname, chain and expiration
+    logging : Verify logging endpoints referenced by log statements exist in the service
+    limits  : Estimate resource usages against Fastly limits
+
+Run preflight checks example:
+    falco doctor -I . /path/to/vcl/main.vcl
+	`))
+}
+
+func printRoutesHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco routes [flags]
+
+Flags:
+    -I, --include_path : Add include path
+    -h, --help         : Show this help
+    -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
+    -json              : Output results as JSON
+    --format           : Output format, text (default), json, csv or html
+
+Export decision table example:
+    falco routes -I . --format html /path/to/vcl/main.vcl > routes.html
+	`))
+}
+
+func printMigrateConfigHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco migrate-config [paths...] [flags]
+
+Rewrites the configuration file and ignore comments and annotations in VCL files
+which are written for former falco versions. VCL files are found in the paths,
+or include paths when no paths are specified.
+
+Flags:
+    -I, --include_path : Add include path
+    -h, --help         : Show this help
+    --dry-run          : Show changes without rewriting files
+    --format           : Output format, text (default) or json
+
+Migration example:
+    falco migrate-config ./vcl --dry-run
+	`))
+}
+
+func printExplainHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco explain [rule] [flags]
+
+Shows the summary, rationale, examples and references of the linter rule.
+All rules are listed when the rule is not specified.
+
+Flags:
+    -h, --help         : Show this help
+    --format           : Output format, text (default) or json
+
+Explain rule example:
+    falco explain cache/poisoning
+	`))
+}
+
+func printPluginHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco plugin [command] [flags]
+
+Commands:
+    install [name]     : Install plugin from the registry, or GitHub releases like github.com/owner/repo
+    list               : List installed plugins with versions and capabilities
+
+Plugins are installed to plugin.path in configuration, ~/.falco/plugins by default.
+The checksum of the downloaded file is always verified, and the signature is verified
+when plugin.trusted_keys is configured.
+
+Flags:
+    -h, --help         : Show this help
+    --format           : Output format, text (default) or json
+
+Install example:
+    falco plugin install lambdaedge@1.2.0
+    falco plugin install github.com/owner/falco-transform-lambdaedge
+	`))
+}
+
+func printBundleHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco bundle [flags] [main vcl file]
+
+Outputs single VCL which inlines included modules recursively.
+Fastly managed snippets which are included as "snippet::[name]" are kept as include statements.
+
+Flags:
+    -I, --include_path : Add include path
+    -h, --help         : Show this help
+    -r, --remote       : Connect with Fastly API
+    --snapshot         : Read remote resources from the snapshot file which is written by import command
+    -o, --output       : Write the bundle to the file
+    --stdout           : Stream the bundle to stdout (default)
+    --lint             : Lint before bundling, diagnostics are written to stderr as JSON lines
+                         and the bundle is not written when lint errors are found
+
+Pipeline example:
+    falco bundle -I . --lint --stdout /path/to/vcl/main.vcl | other-tool
+	`))
+}
+
+func printLoadHelp() {
+	writeln(white, strings.TrimSpace(`
+Usage:
+    falco load [flags] [main vcl file]
+
+Generates synthetic traffic from the profile against the simulator, and reports
+cache hit ratio, backend QPS and p99 simulated latency per route.
+The simulator is set up with simulator configuration, so override_backends should point to
+local backends not to send the load to production origins.
+
+Flags:
+    -I, --include_path     : Add include path
+    -h, --help             : Show this help
+    -r, --remote           : Connect with Fastly API
+    --snapshot             : Read remote resources from the snapshot file which is written by import command
+    --profile              : Traffic profile file which describes URL mix, header distributions and client IP pools
+    --rps                  : Requests per second (default 100)
+    --duration             : Seconds to generate the load (default 10)
+    --format               : Output format, text (default) or json
+    --save-report          : Write the report to the file in order to store it as the baseline artifact
+    --compare              : Fail when metrics regress from the baseline report file
+    --max-hit-ratio-drop   : Percentage points which cache hit ratio may drop from the baseline (default 5)
+    --max-backend-increase : Percent which backend fetches per request may increase from the baseline (default 10)
+
+Load example:
+    falco load -I . --rps 500 --profile traffic.yaml /path/to/vcl/main.vcl
+    falco load -I . --profile traffic.yaml --compare baseline.json /path/to/vcl/main.vcl
 	`))
 }
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-yaml/yaml"
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/remote"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	importSnapshotFile = "service.json"
+	importConfigFile   = ".falco.yml"
+	importVCLDir       = "vcl"
+	importSnippetDir   = "snippets"
+	importTableDir     = "fixtures/tables"
+	importAclDir       = "fixtures/acls"
+	// Main VCL file name when the service does not have custom VCLs
+	importMainVCL = "main.vcl"
+)
+
+// Characters which could not be used in the file name of imported resources
+var unsafeFileNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.\-]`)
+
+// Fastly boilerplate VCL which is used as the main VCL when the service only has VCL snippets.
+// Fastly expands the service configuration and snippets at the place of "#FASTLY" macros.
+var boilerplateVCL = `sub vcl_recv {
+  #FASTLY RECV
+  if (req.method != "HEAD" && req.method != "GET" && req.method != "FASTLYPURGE") {
+    return(pass);
+  }
+  return(lookup);
+}
+
+sub vcl_hash {
+  set req.hash += req.url;
+  set req.hash += req.http.host;
+  #FASTLY HASH
+  return(hash);
+}
+
+sub vcl_hit {
+  #FASTLY HIT
+  if (!obj.cacheable) {
+    return(pass);
+  }
+  return(deliver);
+}
+
+sub vcl_miss {
+  #FASTLY MISS
+  return(fetch);
+}
+
+sub vcl_pass {
+  #FASTLY PASS
+  return(pass);
+}
+
+sub vcl_fetch {
+  #FASTLY FETCH
+  if ((beresp.status == 500 || beresp.status == 503) && req.restarts < 1 && (req.method == "GET" || req.method == "HEAD")) {
+    restart;
+  }
+  if (req.restarts > 0) {
+    set beresp.http.Fastly-Restarts = req.restarts;
+  }
+  if (beresp.http.Set-Cookie) {
+    set req.http.Fastly-Cachetype = "SETCOOKIE";
+    return(pass);
+  }
+  if (beresp.http.Cache-Control ~ "private") {
+    set req.http.Fastly-Cachetype = "PRIVATE";
+    return(pass);
+  }
+  if (beresp.status == 500 || beresp.status == 503) {
+    set req.http.Fastly-Cachetype = "ERROR";
+    set beresp.ttl = 1s;
+    set beresp.grace = 5s;
+    return(deliver);
+  }
+  if (!(beresp.http.Expires || beresp.http.Surrogate-Control ~ "max-age" || beresp.http.Cache-Control ~ "(?:s-maxage|max-age)")) {
+    set beresp.ttl = %ds;
+  }
+  return(deliver);
+}
+
+sub vcl_error {
+  #FASTLY ERROR
+  return(deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  return(deliver);
+}
+
+sub vcl_log {
+  #FASTLY LOG
+}
+`
+
+// Fastly default TTL which is used when the service settings do not have it
+const defaultServiceTTL = 3600
+
+// Result of import command
+type importResult struct {
+	ServiceId string   `json:"service_id"`
+	Version   int64    `json:"version"`
+	Output    string   `json:"output"`
+	Main      string   `json:"main"`
+	Files     []string `json:"files"`
+}
+
+func (r *importResult) summary() string {
+	return fmt.Sprintf("Imported version %d of service %s into %s", r.Version, r.ServiceId, r.Output)
+}
+
+// Remote resources of the service version to import
+type importedService struct {
+	id               string
+	version          int64
+	settings         *remote.Settings
+	vcls             []*remote.CustomVCL
+	snippets         []*remote.VCLSnippet
+	dictionaries     []*remote.EdgeDictionary
+	acls             []*remote.AccessControl
+	backends         []*remote.Backend
+	domains          []*remote.Domain
+	loggingEndpoints []string
+}
+
+// File of the imported project, Path is relative from the output directory
+type projectFile struct {
+	Path    string
+	Content []byte
+}
+
+// Configuration file of the imported project
+type importedConfig struct {
+	IncludePaths []string                 `yaml:"include_paths"`
+	Snapshot     string                   `yaml:"snapshot"`
+	Simulator    *importedSimulatorConfig `yaml:"simulator,omitempty"`
+}
+
+type importedSimulatorConfig struct {
+	DataFiles struct {
+		Tables map[string]string `yaml:"tables,omitempty"`
+		Acls   map[string]string `yaml:"acls,omitempty"`
+	} `yaml:"data_files"`
+}
+
+func runImport(c *config.Config) error {
+	result, err := importService(c)
+	if err != nil {
+		if !c.Json {
+			return err
+		}
+		errorCommandOutput(subcommandImport, err).Write(os.Stdout) // nolint:errcheck
+		return ErrExit
+	}
+	if c.Json {
+		return newCommandOutput(subcommandImport, outputStatusSuccess, result.summary(), result).Write(os.Stdout)
+	}
+
+	for _, file := range result.Files {
+		writeln(green, "Created %s", filepath.Join(result.Output, file))
+	}
+	writeln(white, "%s", result.summary())
+	writeln(white, `Run "falco lint %s" in %s to lint the service`, result.Main, result.Output)
+	return nil
+}
+
+func importService(c *config.Config) (*importResult, error) {
+	serviceId := c.Import.ServiceID
+	if serviceId == "" {
+		serviceId = c.FastlyServiceID
+	}
+	if serviceId == "" || c.FastlyApiKey == "" {
+		return nil, fmt.Errorf(
+			"Service ID must be specified with --service-id option or FASTLY_SERVICE_ID environment variable, " +
+				"and FASTLY_API_KEY environment variable must be specified",
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	client := remote.NewFastlyClient(http.DefaultClient, serviceId, c.FastlyApiKey)
+	service, err := fetchService(ctx, client, serviceId)
+	if err != nil {
+		return nil, err
+	}
+	files, main, err := layoutProject(service)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeProject(c.Import.Output, files, c.Import.Force); err != nil {
+		return nil, err
+	}
+
+	result := &importResult{
+		ServiceId: serviceId,
+		Version:   service.version,
+		Output:    c.Import.Output,
+		Main:      main,
+	}
+	for _, file := range files {
+		result.Files = append(result.Files, file.Path)
+	}
+	return result, nil
+}
+
+// Fetch all resources of the active version which are needed to lint and simulate the service
+func fetchService(ctx context.Context, client *remote.FastlyClient, serviceId string) (*importedService, error) {
+	version, err := client.LatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get latest version: %w", err)
+	}
+	s := &importedService{
+		id:      serviceId,
+		version: version,
+	}
+
+	var eg errgroup.Group
+	eg.Go(func() (err error) {
+		if s.settings, err = client.GetSettings(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get settings: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.vcls, err = client.ListCustomVCLs(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get custom VCLs: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.snippets, err = client.ListSnippets(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get VCL snippets: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.dictionaries, err = client.ListEdgeDictionaries(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get edge dictionaries: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.acls, err = client.ListAccessControlLists(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get ACLs: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.backends, err = client.ListBackends(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get backends: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.domains, err = client.ListDomains(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get domains: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() (err error) {
+		if s.loggingEndpoints, err = client.ListLoggingEndpoints(ctx, version); err != nil {
+			return fmt.Errorf("Failed to get logging endpoints: %w", err)
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Lay out the service as falco project, returns files and path of the main VCL.
+// Custom VCLs are placed in the include path so that include statements are resolved as Fastly does,
+// and the other resources are written to the snapshot which is read instead of Fastly API.
+func layoutProject(s *importedService) ([]*projectFile, string, error) {
+	var files []*projectFile
+	paths := make(map[string]string)
+	add := func(name, path string, content []byte) error {
+		if other, ok := paths[path]; ok {
+			return fmt.Errorf("Both %s and %s are written to the same file %s, rename one of them", other, name, path)
+		}
+		paths[path] = name
+		files = append(files, &projectFile{Path: path, Content: content})
+		return nil
+	}
+
+	var main string
+	for _, v := range s.vcls {
+		path := filepath.Join(importVCLDir, safeFileName(strings.TrimSuffix(v.Name, ".vcl"))+".vcl")
+		if err := add("custom VCL "+v.Name, path, []byte(v.Content)); err != nil {
+			return nil, "", err
+		}
+		if v.Main {
+			main = path
+		}
+	}
+	if main == "" {
+		ttl := int64(defaultServiceTTL)
+		if s.settings != nil && s.settings.DefaultTTL > 0 {
+			ttl = s.settings.DefaultTTL
+		}
+		main = filepath.Join(importVCLDir, importMainVCL)
+		if err := add("boilerplate VCL", main, []byte(fmt.Sprintf(boilerplateVCL, ttl))); err != nil {
+			return nil, "", err
+		}
+	}
+
+	snapshot := &remote.Snapshot{
+		ServiceId:        s.id,
+		Version:          s.version,
+		Settings:         s.settings,
+		Domains:          []string{},
+		LoggingEndpoints: append([]string{}, s.loggingEndpoints...),
+		Backends:         []*remote.SnapshotBackend{},
+		Dictionaries:     []*remote.SnapshotResource{},
+		Acls:             []*remote.SnapshotResource{},
+		Snippets:         []*remote.SnapshotSnippet{},
+	}
+	// Logging endpoints are fetched concurrently, sort them to output the same snapshot
+	sort.Strings(snapshot.LoggingEndpoints)
+	for _, d := range s.domains {
+		snapshot.Domains = append(snapshot.Domains, d.Name)
+	}
+	for _, b := range s.backends {
+		snapshot.Backends = append(snapshot.Backends, &remote.SnapshotBackend{
+			Name:    b.Name,
+			Address: b.Address,
+			Shield:  b.Shield,
+		})
+	}
+
+	for _, v := range s.snippets {
+		priority, err := strconv.ParseInt(v.Priority, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("Failed to convert priority of VCL snippet %s to int: %w", v.Name, err)
+		}
+		var content string
+		if v.Content != nil {
+			content = *v.Content
+		}
+		path := filepath.Join(importSnippetDir, safeFileName(v.Name)+".vcl")
+		if err := add("VCL snippet "+v.Name, path, []byte(content)); err != nil {
+			return nil, "", err
+		}
+		snapshot.Snippets = append(snapshot.Snippets, &remote.SnapshotSnippet{
+			Name:     v.Name,
+			Type:     v.Type,
+			Priority: priority,
+			Dynamic:  v.Dynamic == "1",
+			File:     filepath.ToSlash(path),
+		})
+	}
+
+	c := &importedConfig{
+		IncludePaths: []string{"./" + importVCLDir},
+		Snapshot:     "./" + importSnapshotFile,
+	}
+	simulator := &importedSimulatorConfig{}
+	for _, d := range s.dictionaries {
+		// Items of private dictionary could not be read, the table is declared without items
+		if d.WriteOnly {
+			snapshot.Dictionaries = append(snapshot.Dictionaries, &remote.SnapshotResource{
+				Name:    d.Name,
+				Private: true,
+			})
+			continue
+		}
+		items := make(map[string]string, len(d.Items))
+		for _, item := range d.Items {
+			items[item.Key] = item.Value
+		}
+		buf, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return nil, "", errors.WithStack(err)
+		}
+		path := filepath.Join(importTableDir, safeFileName(d.Name)+".json")
+		if err := add("edge dictionary "+d.Name, path, append(buf, '\n')); err != nil {
+			return nil, "", err
+		}
+		snapshot.Dictionaries = append(snapshot.Dictionaries, &remote.SnapshotResource{
+			Name: d.Name,
+			File: filepath.ToSlash(path),
+		})
+		if simulator.DataFiles.Tables == nil {
+			simulator.DataFiles.Tables = make(map[string]string)
+		}
+		simulator.DataFiles.Tables[d.Name] = "./" + filepath.ToSlash(path)
+	}
+
+	for _, a := range s.acls {
+		entries := make([]string, len(a.Entries))
+		for i, e := range a.Entries {
+			entries[i] = aclDataEntry(e)
+		}
+		buf, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return nil, "", errors.WithStack(err)
+		}
+		path := filepath.Join(importAclDir, safeFileName(a.Name)+".json")
+		if err := add("ACL "+a.Name, path, append(buf, '\n')); err != nil {
+			return nil, "", err
+		}
+		snapshot.Acls = append(snapshot.Acls, &remote.SnapshotResource{
+			Name: a.Name,
+			File: filepath.ToSlash(path),
+		})
+		if simulator.DataFiles.Acls == nil {
+			simulator.DataFiles.Acls = make(map[string]string)
+		}
+		simulator.DataFiles.Acls[a.Name] = "./" + filepath.ToSlash(path)
+	}
+	if simulator.DataFiles.Tables != nil || simulator.DataFiles.Acls != nil {
+		c.Simulator = simulator
+	}
+
+	buf, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+	if err := add("snapshot", importSnapshotFile, append(buf, '\n')); err != nil {
+		return nil, "", err
+	}
+
+	conf, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+	header := fmt.Sprintf("# Imported from version %d of Fastly service %s by \"falco import\"\n", s.version, s.id)
+	if err := add("configuration", importConfigFile, append([]byte(header), conf...)); err != nil {
+		return nil, "", err
+	}
+
+	return files, main, nil
+}
+
+// Format ACL entry as the data file entry like "!192.168.0.0/16"
+func aclDataEntry(e *remote.AccessControlEntry) string {
+	var entry string
+	if e.Negated == "1" {
+		entry = "!"
+	}
+	entry += e.Ip
+	if e.Subnet != nil {
+		entry += "/" + strconv.FormatInt(*e.Subnet, 10)
+	}
+	return entry
+}
+
+func safeFileName(name string) string {
+	return unsafeFileNameCharacters.ReplaceAllString(name, "_")
+}
+
+// Write project files into the directory, existing files are not overwritten unless force is true
+func writeProject(dir string, files []*projectFile, force bool) error {
+	if !force {
+		for _, file := range files {
+			path := filepath.Join(dir, file.Path)
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("File %s already exists, use --force option to overwrite", path)
+			}
+		}
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("Failed to create directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			return fmt.Errorf("Failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/remote"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Fake Fastly API which responds fixed JSON for each path, logging endpoints are empty unless specified
+type fakeFastlyApi map[string]string
+
+func (f fakeFastlyApi) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, ok := f[r.URL.Path]
+	status := http.StatusOK
+	if !ok {
+		if strings.Contains(r.URL.Path, "/logging/") {
+			body = "[]"
+		} else {
+			status = http.StatusNotFound
+			body = `{"msg": "Record not found"}`
+		}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+var importMainVCLContent = `include "utils";
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (client.ip ~ internal) {
+    set req.http.X-Internal = "1";
+  }
+  if (table.contains(redirects, req.url.path)) {
+    error 618 "redirect";
+  }
+  set req.backend = F_origin;
+  call normalize_request;
+  return(lookup);
+}
+
+sub vcl_error {
+  #FASTLY ERROR
+  if (obj.status == 618) {
+    set obj.status = 301;
+    set obj.http.Location = table.lookup(redirects, req.url.path);
+    return(deliver);
+  }
+}
+`
+
+var importUtilsVCLContent = `sub normalize_request {
+  unset req.http.Cookie;
+}
+`
+
+func importTestApi() fakeFastlyApi {
+	return fakeFastlyApi{
+		"/service/SERVICE/version/active":     `{"number": 3}`,
+		"/service/SERVICE/version/3/settings": `{"general.default_host": "", "general.default_ttl": 600, "general.stale_if_error": false}`,
+		"/service/SERVICE/version/3/vcl": `[
+			{"name": "main", "main": true, "content": ` + jsonString(importMainVCLContent) + `},
+			{"name": "utils", "main": false, "content": ` + jsonString(importUtilsVCLContent) + `}
+		]`,
+		"/service/SERVICE/version/3/snippet": `[
+			{"id": "S1", "name": "set header", "dynamic": "0", "type": "recv", "priority": "100", "content": "set req.http.X-Snippet = \"1\";"},
+			{"id": "S2", "name": "dynamic", "dynamic": "1", "type": "deliver", "priority": "50", "content": null}
+		]`,
+		"/service/SERVICE/snippet/S2": `{"content": "set resp.http.X-Dynamic = \"1\";"}`,
+		"/service/SERVICE/version/3/dictionary": `[
+			{"id": "D1", "name": "redirects", "write_only": false},
+			{"id": "D2", "name": "secrets", "write_only": true}
+		]`,
+		"/service/SERVICE/dictionary/D1/items": `[{"item_key": "/old", "item_value": "/new"}]`,
+		"/service/SERVICE/version/3/acl":       `[{"id": "A1", "name": "internal"}]`,
+		"/service/SERVICE/acl/A1/entries": `[
+			{"id": "E1", "ip": "192.168.0.0", "subnet": 16, "negated": "0"},
+			{"id": "E2", "ip": "192.168.100.1", "subnet": null, "negated": "1"}
+		]`,
+		"/service/SERVICE/version/3/backend":        `[{"name": "origin", "address": "example.com", "shield": null}]`,
+		"/service/SERVICE/version/3/domain":         `[{"name": "www.example.com", "comment": ""}]`,
+		"/service/SERVICE/version/3/logging/s3":     `[{"name": "access_log"}]`,
+		"/service/SERVICE/version/3/logging/syslog": `[{"name": "audit_log"}]`,
+	}
+}
+
+func jsonString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func importTestProject(t *testing.T, dir string) string {
+	client := remote.NewFastlyClient(&http.Client{Transport: importTestApi()}, "SERVICE", "dummy")
+	service, err := fetchService(context.Background(), client, "SERVICE")
+	if err != nil {
+		t.Fatalf("Unexpected fetch error: %s", err)
+	}
+	files, main, err := layoutProject(service)
+	if err != nil {
+		t.Fatalf("Unexpected layout error: %s", err)
+	}
+	if err := writeProject(dir, files, false); err != nil {
+		t.Fatalf("Unexpected write error: %s", err)
+	}
+	return main
+}
+
+func TestImportProjectLayout(t *testing.T) {
+	dir := t.TempDir()
+	main := importTestProject(t, dir)
+	if main != filepath.Join("vcl", "main.vcl") {
+		t.Errorf("Main VCL expects vcl/main.vcl but got %s", main)
+	}
+
+	expects := map[string]string{
+		"vcl/main.vcl":                   importMainVCLContent,
+		"vcl/utils.vcl":                  importUtilsVCLContent,
+		"snippets/set_header.vcl":        `set req.http.X-Snippet = "1";`,
+		"snippets/dynamic.vcl":           `set resp.http.X-Dynamic = "1";`,
+		"fixtures/tables/redirects.json": "{\n  \"/old\": \"/new\"\n}\n",
+		"fixtures/acls/internal.json":    "[\n  \"192.168.0.0/16\",\n  \"!192.168.100.1\"\n]\n",
+	}
+	for file, expect := range expects {
+		buf, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			continue
+		}
+		if diff := cmp.Diff(expect, string(buf)); diff != "" {
+			t.Errorf("Content of %s mismatch, diff=%s", file, diff)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fixtures/tables/secrets.json")); err == nil {
+		t.Errorf("Private dictionary must not have the data file")
+	}
+
+	conf, err := os.ReadFile(filepath.Join(dir, ".falco.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, expect := range []string{
+		"include_paths:\n- ./vcl\n",
+		"snapshot: ./service.json\n",
+		"redirects: ./fixtures/tables/redirects.json\n",
+		"internal: ./fixtures/acls/internal.json\n",
+	} {
+		if !strings.Contains(string(conf), expect) {
+			t.Errorf("Configuration must contain %q, got %s", expect, conf)
+		}
+	}
+
+	buf, err := os.ReadFile(filepath.Join(dir, "service.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var snapshot remote.Snapshot
+	if err := json.Unmarshal(buf, &snapshot); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"access_log", "audit_log"}, snapshot.LoggingEndpoints); diff != "" {
+		t.Errorf("Logging endpoints mismatch, diff=%s", diff)
+	}
+	if diff := cmp.Diff([]string{"www.example.com"}, snapshot.Domains); diff != "" {
+		t.Errorf("Domains mismatch, diff=%s", diff)
+	}
+
+	// Files must not be overwritten without force option
+	client := remote.NewFastlyClient(&http.Client{Transport: importTestApi()}, "SERVICE", "dummy")
+	service, err := fetchService(context.Background(), client, "SERVICE")
+	if err != nil {
+		t.Fatalf("Unexpected fetch error: %s", err)
+	}
+	files, _, err := layoutProject(service)
+	if err != nil {
+		t.Fatalf("Unexpected layout error: %s", err)
+	}
+	if err := writeProject(dir, files, false); err == nil {
+		t.Errorf("Expected error for existing files but got nil")
+	}
+	if err := writeProject(dir, files, true); err != nil {
+		t.Errorf("Unexpected error with force option: %s", err)
+	}
+}
+
+func TestImportedProjectLintsCleanly(t *testing.T) {
+	dir := t.TempDir()
+	main := importTestProject(t, dir)
+
+	fetcher, err := remote.NewSnapshotFetcher(filepath.Join(dir, "service.json"))
+	if err != nil {
+		t.Fatalf("Unexpected snapshot error: %s", err)
+	}
+	snippets, err := fetcher.Snippets()
+	if err != nil {
+		t.Fatalf("Unexpected snippets error: %s", err)
+	}
+	if len(snippets) != 2 || snippets[1].Content != `set resp.http.X-Dynamic = "1";` || snippets[1].Priority != 50 {
+		t.Errorf("Snippets are not restored from the snapshot: %v", snippets)
+	}
+
+	resolvers, err := resolver.NewFileResolvers(filepath.Join(dir, main), []string{filepath.Join(dir, "vcl")})
+	if err != nil {
+		t.Fatalf("Unexpected resolver error: %s", err)
+	}
+	r, err := NewRunner(&config.Config{Linter: &config.LinterConfig{}}, fetcher)
+	if err != nil {
+		t.Fatalf("Unexpected runner creation error: %s", err)
+	}
+	ret, err := r.Run(resolvers[0])
+	if err != nil {
+		t.Fatalf("Unexpected Run() error: %s", err)
+	}
+	if ret.Errors != 0 {
+		t.Errorf("Imported project must lint cleanly, got %d errors", ret.Errors)
+	}
+}
+
+func TestImportBoilerplateLintsCleanly(t *testing.T) {
+	dir := t.TempDir()
+	api := importTestApi()
+	api["/service/SERVICE/version/3/vcl"] = "[]"
+	client := remote.NewFastlyClient(&http.Client{Transport: api}, "SERVICE", "dummy")
+	service, err := fetchService(context.Background(), client, "SERVICE")
+	if err != nil {
+		t.Fatalf("Unexpected fetch error: %s", err)
+	}
+	files, main, err := layoutProject(service)
+	if err != nil {
+		t.Fatalf("Unexpected layout error: %s", err)
+	}
+	if err := writeProject(dir, files, false); err != nil {
+		t.Fatalf("Unexpected write error: %s", err)
+	}
+	buf, err := os.ReadFile(filepath.Join(dir, main))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !strings.Contains(string(buf), "set beresp.ttl = 600s;") {
+		t.Errorf("Boilerplate must use default TTL of the service settings")
+	}
+
+	fetcher, err := remote.NewSnapshotFetcher(filepath.Join(dir, "service.json"))
+	if err != nil {
+		t.Fatalf("Unexpected snapshot error: %s", err)
+	}
+	resolvers, err := resolver.NewFileResolvers(filepath.Join(dir, main), []string{filepath.Join(dir, "vcl")})
+	if err != nil {
+		t.Fatalf("Unexpected resolver error: %s", err)
+	}
+	r, err := NewRunner(&config.Config{Linter: &config.LinterConfig{}}, fetcher)
+	if err != nil {
+		t.Fatalf("Unexpected runner creation error: %s", err)
+	}
+	ret, err := r.Run(resolvers[0])
+	if err != nil {
+		t.Fatalf("Unexpected Run() error: %s", err)
+	}
+	if ret.Errors != 0 {
+		t.Errorf("Boilerplate must lint cleanly, got %d errors", ret.Errors)
+	}
+}
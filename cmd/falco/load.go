@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter/loadgen"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Generate synthetic traffic against the simulator and report metrics for each route
+func runLoad(runner *Runner, rslv resolver.Resolver) error {
+	if !runner.config.Json {
+		writeln(cyan, "Sending %d requests per second for %d seconds...", runner.config.Load.RPS, runner.config.Load.Duration)
+	}
+	report, err := runner.Load(rslv)
+	if err != nil {
+		writeln(red, err.Error())
+		return ErrExit
+	}
+
+	if runner.config.Json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+	} else {
+		printLoadReport(report)
+	}
+
+	return compareLoadReport(runner.config.Load, report)
+}
+
+// Save the report as an artifact and compare it with the baseline report for CI gating
+func compareLoadReport(lc *config.LoadConfig, report *loadgen.Report) error {
+	if lc.SaveReport != "" {
+		if err := loadgen.WriteReport(lc.SaveReport, report); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+		writeln(white, "Report is saved to %s", lc.SaveReport)
+	}
+	if lc.Compare == "" {
+		return nil
+	}
+
+	baseline, err := loadgen.ReadReport(lc.Compare)
+	if err != nil {
+		writeln(red, err.Error())
+		return ErrExit
+	}
+	regressions := loadgen.Compare(baseline, report, loadgen.Thresholds{
+		MaxHitRatioDrop:    lc.MaxHitRatioDrop,
+		MaxBackendIncrease: lc.MaxBackendIncrease,
+	})
+	if len(regressions) == 0 {
+		writeln(green, "No regression against the baseline %s", lc.Compare)
+		return nil
+	}
+	for _, r := range regressions {
+		writeln(red, ":fire:%s", r.String())
+	}
+	writeln(red, "%d metrics regressed against the baseline %s", len(regressions), lc.Compare)
+	return ErrExit
+}
+
+func printLoadReport(report *loadgen.Report) {
+	printRow := func(r *loadgen.RouteReport) {
+		fmt.Fprintf(
+			os.Stdout, "%-32s %10d %10.1f%% %12.1f %8d %10s %10s\n",
+			r.Name, r.Requests, r.CacheHitRatio*100, r.BackendQPS, r.Errors, r.P50Latency, r.P99Latency,
+		)
+	}
+
+	fmt.Fprintf(
+		os.Stdout, "%-32s %10s %11s %12s %8s %10s %10s\n",
+		"Route", "Requests", "Hit Ratio", "Backend QPS", "Errors", "p50", "p99",
+	)
+	for _, r := range report.Routes {
+		printRow(r)
+	}
+	fmt.Fprintln(os.Stdout, strings.Repeat("-", 100))
+	printRow(report.Total)
+	writeln(white, "%d requests in %s (%.1f requests per second)", report.Total.Requests, report.Elapsed.Round(time.Millisecond), report.RPS)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -15,8 +16,10 @@ import (
 	"github.com/mattn/go-colorable"
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/i18n"
 	ife "github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/linter"
 	"github.com/ysugimoto/falco/remote"
 	"github.com/ysugimoto/falco/resolver"
 	"github.com/ysugimoto/falco/snippets"
@@ -46,15 +49,32 @@ var (
 )
 
 const (
-	subcommandLint      = "lint"
-	subcommandTerraform = "terraform"
-	subcommandSimulate  = "simulate"
-	subcommandStats     = "stats"
-	subcommandTest      = "test"
+	subcommandLint       = "lint"
+	subcommandTerraform  = "terraform"
+	subcommandSimulate   = "simulate"
+	subcommandStats      = "stats"
+	subcommandTest       = "test"
+	subcommandGenerate   = "generate"
+	subcommandSync       = "sync"
+	subcommandCache      = "cache"
+	subcommandTimeTravel = "timetravel"
+	subcommandDoctor     = "doctor"
+	subcommandRoutes     = "routes"
+
+	subcommandMigrateConfig = "migrate-config"
+	subcommandPlugin        = "plugin"
+	subcommandBundle        = "bundle"
+	subcommandExplain       = "explain"
+	subcommandImport        = "import"
+	subcommandLoad          = "load"
 )
 
 func write(c *color.Color, format string, args ...interface{}) {
-	c.Fprint(output, emoji.Sprintf(format, args...))
+	fwrite(output, c, format, args...)
+}
+
+func fwrite(w io.Writer, c *color.Color, format string, args ...interface{}) {
+	c.Fprint(w, emoji.Sprintf(format, args...))
 }
 
 func writeln(c *color.Color, format string, args ...interface{}) {
@@ -62,11 +82,17 @@ func writeln(c *color.Color, format string, args ...interface{}) {
 }
 
 func main() {
+	defer recoverCrash()
+
 	c, err := config.New(os.Args[1:])
 	if err != nil {
 		writeln(red, "Failed to initialize config: %s", err)
 		os.Exit(1)
 	}
+	if err := i18n.SetLanguage(c.Language); err != nil {
+		writeln(red, err.Error())
+		os.Exit(1)
+	}
 	if c.Help {
 		printHelp(c.Commands.At(0))
 		os.Exit(1)
@@ -77,6 +103,8 @@ func main() {
 
 	var fetcher snippets.Fetcher
 	var action string
+	// Entry VCL files when multiple files are linted
+	var entries []string
 	// falco could lint multiple services so resolver should be a slice
 	var resolvers []resolver.Resolver
 	switch c.Commands.At(0) {
@@ -87,11 +115,95 @@ func main() {
 			fetcher = terraform.NewTerraformFetcher(fastlyServices)
 		}
 		action = c.Commands.At(1)
-	case subcommandSimulate, subcommandLint, subcommandStats, subcommandTest:
-		// "lint", "simulate", "stats" and "test" command provides single file of service,
-		// then resolvers size is always 1
-		resolvers, err = resolver.NewFileResolvers(c.Commands.At(1), c.IncludePaths)
+	case subcommandSimulate, subcommandLint, subcommandStats, subcommandTest, subcommandDoctor, subcommandRoutes, subcommandBundle,
+		subcommandLoad:
+		// "simulate" command without main VCL runs configured services side by side
+		if c.Commands.At(0) == subcommandSimulate && c.Commands.At(1) == "" && len(c.Simulator.Services) > 0 {
+			if err := runSimulateServices(c); err != nil {
+				writeln(red, err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+		// "simulate", "stats", "test", "doctor", "routes", "bundle" and "load" command provides single file of service,
+		// then resolvers size is always 1. "lint" command accepts multiple entry VCLs of services
+		if c.Commands.At(0) == subcommandLint && len(c.Commands) > 2 {
+			entries = c.Commands[1:]
+			resolvers, err = entryResolvers(entries, c.IncludePaths)
+		} else {
+			resolvers, err = resolver.NewFileResolvers(c.Commands.At(1), c.IncludePaths)
+		}
 		action = c.Commands.At(0)
+	case subcommandGenerate:
+		// "generate" command does not need any VCLs
+		if err := runGenerate(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	case subcommandSync:
+		// "sync" command does not need any VCLs
+		if err := runSync(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	case subcommandImport:
+		// "import" command does not need any VCLs
+		if err := runImport(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	case subcommandCache:
+		// "cache" command communicates with running simulator
+		if err := runCache(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	case subcommandTimeTravel:
+		// "timetravel" command views the log which is recorded by the simulator
+		if err := runTimeTravel(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	case subcommandMigrateConfig:
+		// "migrate-config" command rewrites configuration and VCL files for the current version
+		if err := runMigrateConfig(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
+	case subcommandExplain:
+		// "explain" command shows documentation of linter rules
+		if err := runExplain(c); err != nil {
+			writeln(red, err.Error())
+			os.Exit(1)
+		}
+		return
+	case subcommandPlugin:
+		// "plugin" command installs and lists plugins
+		if err := runPlugin(c); err != nil {
+			if err != ErrExit {
+				writeln(red, err.Error())
+			}
+			os.Exit(1)
+		}
+		return
 	case "":
 		printHelp("")
 		os.Exit(1)
@@ -99,8 +211,13 @@ func main() {
 		if filepath.Ext(c.Commands.At(0)) != ".vcl" {
 			err = fmt.Errorf("Unrecognized subcommand: %s", c.Commands.At(0))
 		} else {
-			// "lint" command provides single file of service, then resolvers size is always 1
-			resolvers, err = resolver.NewFileResolvers(c.Commands.At(0), c.IncludePaths)
+			// "lint" command provides single file of service, or multiple entry VCLs of services
+			if len(c.Commands) > 1 {
+				entries = c.Commands
+				resolvers, err = entryResolvers(entries, c.IncludePaths)
+			} else {
+				resolvers, err = resolver.NewFileResolvers(c.Commands.At(0), c.IncludePaths)
+			}
 			action = c.Commands.At(0)
 		}
 	}
@@ -120,6 +237,14 @@ func main() {
 		}
 		// Create remote fetcher
 		fetcher = remote.NewFastlyApiFetcher(c.FastlyServiceID, c.FastlyApiKey, 5*time.Second)
+	} else if c.Snapshot != "" && fetcher == nil {
+		// Snapshot of the service which is written by import command is used instead of Fastly API
+		snapshot, serr := remote.NewSnapshotFetcher(c.Snapshot)
+		if serr != nil {
+			writeln(red, serr.Error())
+			os.Exit(1)
+		}
+		fetcher = snapshot
 	}
 
 	if err != nil {
@@ -127,6 +252,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Multiple entry VCLs are linted concurrently with sharing parsed modules
+	if len(entries) > 1 {
+		if err := runLintParallel(c, fetcher, entries, resolvers); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	var shouldExit bool
 	for _, v := range resolvers {
 		if name := v.Name(); name != "" {
@@ -154,6 +287,14 @@ func main() {
 			exitErr = runSimulate(runner, v)
 		case subcommandStats:
 			exitErr = runStats(runner, v)
+		case subcommandDoctor:
+			exitErr = runDoctor(runner, v)
+		case subcommandRoutes:
+			exitErr = runRoutes(runner, v)
+		case subcommandBundle:
+			exitErr = runBundle(runner, v)
+		case subcommandLoad:
+			exitErr = runLoad(runner, v)
 		default:
 			exitErr = runLint(runner, v)
 		}
@@ -170,6 +311,11 @@ func main() {
 
 func runLint(runner *Runner, rslv resolver.Resolver) error {
 	result, err := runner.Run(rslv)
+	return reportLint(runner, result, err)
+}
+
+// Output lint result and run transformers
+func reportLint(runner *Runner, result *RunnerResult, err error) error {
 	if err != nil {
 		if err != ErrParser {
 			writeln(red, err.Error())
@@ -177,7 +323,24 @@ func runLint(runner *Runner, rslv resolver.Resolver) error {
 		return ErrExit
 	}
 
-	if runner.config.Json {
+	switch {
+	case runner.config.Format == "json":
+		// Structured report in the stable schema
+		if err := lintCommandOutput(result).Write(os.Stdout); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+	case runner.config.Format == "junit":
+		group, err := linter.ParseJUnitGroup(runner.config.Linter.JUnitGroup)
+		if err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+		if err := lintReport(result).WriteJUnit(os.Stdout, group); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+	case runner.config.Json:
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		if err := enc.Encode(result); err != nil {
@@ -189,6 +352,15 @@ func runLint(runner *Runner, rslv resolver.Resolver) error {
 	write(red, ":fire:%d errors, ", result.Errors)
 	write(yellow, ":exclamation:%d warnings, ", result.Warnings)
 	writeln(cyan, ":speaker:%d recommendations.", result.Infos)
+	if result.Baselined > 0 {
+		writeln(white, "%d lint errors are suppressed by the baseline file.", result.Baselined)
+	}
+	if result.Unchanged > 0 {
+		writeln(white, "%d lint errors outside of changed lines are not reported.", result.Unchanged)
+	}
+	if len(result.Profiles) > 0 && !runner.config.Json {
+		printRuleProfiles(result.Profiles)
+	}
 
 	// Display message corresponds to runner result
 	if result.Errors == 0 {
@@ -223,6 +395,16 @@ func runLint(runner *Runner, rslv resolver.Resolver) error {
 	return nil
 }
 
+// Output time spent for each rule in descending order
+func printRuleProfiles(profiles []*linter.RuleProfile) {
+	writeln(white, "")
+	writeln(white, "%-40s %12s %10s", "Rule", "Time", "Calls")
+	for _, p := range profiles {
+		writeln(white, "%-40s %12s %10d", p.Rule, p.Time.Round(time.Microsecond), p.Calls)
+	}
+	writeln(white, "")
+}
+
 func runSimulate(runner *Runner, rslv resolver.Resolver) error {
 	if err := runner.Simulate(rslv); err != nil {
 		writeln(red, "Failed to start local simulator: %s", err.Error())
@@ -231,6 +413,17 @@ func runSimulate(runner *Runner, rslv resolver.Resolver) error {
 	return nil
 }
 
+func runSimulateServices(c *config.Config) error {
+	runner, err := NewRunner(c, nil)
+	if err != nil {
+		return err
+	}
+	if err := runner.SimulateServices(); err != nil {
+		return fmt.Errorf("Failed to start local simulator: %w", err)
+	}
+	return nil
+}
+
 func runStats(runner *Runner, rslv resolver.Resolver) error {
 	stats, err := runner.Stats(rslv)
 	if err != nil {
@@ -272,6 +465,16 @@ func runStats(runner *Runner, rslv resolver.Resolver) error {
 	printStats(strings.Repeat("-", 80))
 	printStats("| %-22s | %51d |", "Directors", stats.Directors)
 	printStats(strings.Repeat("-", 80))
+
+	if len(stats.Complexity) > 0 {
+		printStats("")
+		printStats("| %-48s | %10s | %10s |", "Subroutine", "Cyclomatic", "Nesting")
+		printStats(strings.Repeat("=", 80))
+		for _, m := range stats.Complexity {
+			printStats("| %-48s | %10d | %10d |", m.Name, m.Cyclomatic, m.Nesting)
+			printStats(strings.Repeat("-", 80))
+		}
+	}
 	return nil
 }
 
@@ -300,6 +503,17 @@ func runTest(runner *Runner, rslv resolver.Resolver) error {
 		return nil
 	}
 
+	if runner.config.Format == "junit" {
+		if err := factory.WriteJUnit(os.Stdout); err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+		if factory.Statistics.Fails > 0 {
+			return ErrExit
+		}
+		return nil
+	}
+
 	// shrthand indent making
 	indent := func(level int) string {
 		return strings.Repeat(" ", level*2)
@@ -321,7 +535,22 @@ func runTest(runner *Runner, rslv resolver.Resolver) error {
 		}
 	}
 
-	var passedCount, failedCount, totalCount int
+	printAssertionDiff := func(diff []string) {
+		writeln(white, "%sDiff (-expect +actual):", indent(2))
+		for _, line := range diff {
+			color := white
+			switch {
+			case strings.HasPrefix(line, "-"):
+				color = red
+			case strings.HasPrefix(line, "+"):
+				color = green
+			}
+			writeln(color, "%s%s", indent(3), line)
+		}
+		writeln(white, "")
+	}
+
+	var passedCount, failedCount, flakyCount, quarantinedCount, totalCount int
 	for _, r := range factory.Results {
 		switch {
 		case len(r.Cases) == 0:
@@ -337,24 +566,45 @@ func runTest(runner *Runner, rslv resolver.Resolver) error {
 
 		for _, c := range r.Cases {
 			totalCount++
-			if c.Error != nil {
+			switch {
+			case c.Error != nil && c.Quarantined:
+				writeln(yellow, "%s○ [%s] %s (quarantined)", indent(1), c.Scope, c.Name)
+				writeln(yellow, "%s%s", indent(2), c.Error.Error())
+				quarantinedCount++
+			case c.Error != nil:
 				writeln(redBold, "%s●  [%s] %s\n", indent(1), c.Scope, c.Name)
 				writeln(red, "%s%s", indent(2), c.Error.Error())
 				switch e := c.Error.(type) {
 				case *ife.AssertionError:
-					write(white, "%sActual Value: ", indent(2))
-					writeln(red, "%s\n", e.Actual.String())
+					if len(e.Diff) > 0 {
+						printAssertionDiff(e.Diff)
+					} else {
+						write(white, "%sActual Value: ", indent(2))
+						writeln(red, "%s\n", e.Actual.String())
+					}
 					printCodeLine(r.Lexer, e.Token)
 				case *ife.TestingError:
 					writeln(white, "")
 					printCodeLine(r.Lexer, e.Token)
 				}
+				if len(c.RetryErrors) > 0 {
+					writeln(white, "%sFailed %d times including retries", indent(2), len(c.RetryErrors)+1)
+				}
 				writeln(white, "")
 				failedCount++
-			} else {
+			case c.IsFlaky():
+				writeln(yellow, "%s✓ [%s] %s (flaky, passed after %d retries)", indent(1), c.Scope, c.Name, len(c.RetryErrors))
+				passedCount++
+				flakyCount++
+			default:
 				writeln(green, "%s✓ [%s] %s", indent(1), c.Scope, c.Name)
 				passedCount++
 			}
+			if c.CacheExplanation != nil {
+				for _, line := range strings.Split(strings.TrimRight(c.CacheExplanation.String(), "\n"), "\n") {
+					writeln(white, "%s%s", indent(2), line)
+				}
+			}
 		}
 	}
 
@@ -368,6 +618,12 @@ func runTest(runner *Runner, rslv resolver.Resolver) error {
 	} else {
 		write(white, "%d failed, ", failedCount)
 	}
+	if flakyCount > 0 {
+		write(yellow, "%d flaky, ", flakyCount)
+	}
+	if quarantinedCount > 0 {
+		write(yellow, "%d quarantined, ", quarantinedCount)
+	}
 	write(white, "%d total, ", totalCount)
 	writeln(white, "%d assertions", factory.Statistics.Asserts)
 
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/migrate"
+)
+
+// Migrated file of migrate-config command
+type migratedFile struct {
+	File    string            `json:"file"`
+	Changes []*migrate.Change `json:"changes"`
+}
+
+// Result of migrate-config command
+type migrateResult struct {
+	DryRun bool            `json:"dry_run"`
+	Files  []*migratedFile `json:"files"`
+}
+
+func (r *migrateResult) summary() string {
+	var changes int
+	for _, f := range r.Files {
+		changes += len(f.Changes)
+	}
+	switch {
+	case changes == 0:
+		return "Configuration and VCL files are up to date"
+	case r.DryRun:
+		return fmt.Sprintf("Dry run: %d changes in %d files are not applied", changes, len(r.Files))
+	}
+	return fmt.Sprintf("Migrated %d changes in %d files", changes, len(r.Files))
+}
+
+func runMigrateConfig(c *config.Config) error {
+	result, err := migrateFiles(c)
+	if err != nil {
+		if !c.Json {
+			return err
+		}
+		errorCommandOutput(subcommandMigrateConfig, err).Write(os.Stdout) // nolint:errcheck
+		return ErrExit
+	}
+	if c.Json {
+		return newCommandOutput(subcommandMigrateConfig, outputStatusSuccess, result.summary(), result).Write(os.Stdout)
+	}
+
+	for _, f := range result.Files {
+		writeln(white, "%s", f.File)
+		for _, change := range f.Changes {
+			writeln(red, "  %d: - %s", change.Line, change.Before)
+			writeln(green, "  %d: + %s", change.Line, change.After)
+		}
+	}
+	switch {
+	case len(result.Files) == 0:
+		writeln(white, "%s", result.summary())
+	case result.DryRun:
+		writeln(yellow, "%s", result.summary())
+	default:
+		writeln(green, "%s", result.summary())
+	}
+	return nil
+}
+
+// Migrate the configuration file and VCL files in the directories.
+// VCL files are found from arguments, or include paths when arguments are not specified.
+func migrateFiles(c *config.Config) (*migrateResult, error) {
+	result := &migrateResult{
+		DryRun: c.DryRun,
+		Files:  []*migratedFile{},
+	}
+
+	configFile, err := config.FindConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if configFile != "" {
+		f, err := migrateFile(configFile, migrate.Config, c.DryRun)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			result.Files = append(result.Files, f)
+		}
+	}
+
+	roots := c.Commands[1:]
+	if len(roots) == 0 {
+		roots = c.IncludePaths
+	}
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != ".vcl" {
+				return nil
+			}
+			f, err := migrateFile(path, migrate.VCL, c.DryRun)
+			if err != nil {
+				return err
+			}
+			if f != nil {
+				result.Files = append(result.Files, f)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to migrate files in %s: %w", root, err)
+		}
+	}
+	return result, nil
+}
+
+// Rewrite the file unless dry run, returns nil when the file does not have any changes
+func migrateFile(path string, fn func([]byte) ([]byte, []*migrate.Change), dryRun bool) (*migratedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+	migrated, changes := fn(data)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if !dryRun {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to stat %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, migrated, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("Failed to write %s: %w", path, err)
+		}
+	}
+	return &migratedFile{File: path, Changes: changes}, nil
+}
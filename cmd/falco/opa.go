@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/token"
+)
+
+// OpaEvaluator evaluates Rego policies against collected VCL facts via "opa eval" command
+type OpaEvaluator struct {
+	bin      string
+	policies []string
+	query    string
+}
+
+func NewOpaEvaluator(c *config.OpaConfig) (*OpaEvaluator, error) {
+	bin, err := exec.LookPath(c.Command)
+	if err != nil {
+		return nil, fmt.Errorf(`OPA command "%s" does not exist in PATH`, c.Command)
+	}
+	return &OpaEvaluator{
+		bin:      bin,
+		policies: c.Policies,
+		query:    c.Query,
+	}, nil
+}
+
+func (o *OpaEvaluator) Evaluate(facts *linter.Facts) ([]*linter.Diagnostic, error) {
+	input, err := json.Marshal(facts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode facts: %w", err)
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, p := range o.policies {
+		args = append(args, "--data", p)
+	}
+	args = append(args, o.query)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(o.bin, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Failed to evaluate Rego policies: %w\n%s", err, stderr.String())
+	}
+	return parseOpaResult(stdout.Bytes())
+}
+
+// Output format of "opa eval --format json"
+type opaResult struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// Violation object which is returned from the query.
+// Query could return violation message string simply, or an object with location.
+type opaViolation struct {
+	Msg      string `json:"msg"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+func parseOpaResult(buf []byte) ([]*linter.Diagnostic, error) {
+	var result opaResult
+	if err := json.Unmarshal(buf, &result); err != nil {
+		return nil, fmt.Errorf("Failed to decode OPA result: %w", err)
+	}
+
+	var diagnostics []*linter.Diagnostic
+	for _, r := range result.Result {
+		for _, e := range r.Expressions {
+			var values []json.RawMessage
+			if err := json.Unmarshal(e.Value, &values); err != nil {
+				return nil, fmt.Errorf("OPA query result must be a set or an array of violations: %w", err)
+			}
+			for _, v := range values {
+				d, err := opaViolationToDiagnostic(v)
+				if err != nil {
+					return nil, err
+				}
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+func opaViolationToDiagnostic(v json.RawMessage) (*linter.Diagnostic, error) {
+	var violation opaViolation
+	var msg string
+	if err := json.Unmarshal(v, &msg); err == nil {
+		violation.Msg = msg
+	} else if err := json.Unmarshal(v, &violation); err != nil {
+		return nil, fmt.Errorf("Unexpected OPA violation %s: %w", string(v), err)
+	}
+
+	message := violation.Msg
+	if message == "" {
+		message = violation.Message
+	}
+	severity := linter.ERROR
+	switch strings.ToUpper(violation.Severity) {
+	case "WARNING":
+		severity = linter.WARNING
+	case "INFO":
+		severity = linter.INFO
+	}
+
+	d := &linter.Diagnostic{
+		Severity: severity,
+		Token: token.Token{
+			File:     violation.File,
+			Line:     violation.Line,
+			Position: violation.Column,
+		},
+		Message: message,
+	}
+	return d.Match(linter.REGO_POLICY_VIOLATION), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/linter"
+)
+
+func TestParseOpaResult(t *testing.T) {
+	output := `{
+  "result": [
+    {
+      "expressions": [
+        {
+          "value": [
+            "Backend must enable TLS",
+            {"msg": "X-Debug header must not be set", "file": "main.vcl", "line": 9, "column": 7, "severity": "warning"},
+            {"message": "Too many tables", "severity": "info"}
+          ],
+          "text": "data.falco.deny",
+          "location": {"row": 1, "col": 1}
+        }
+      ]
+    }
+  ]
+}`
+
+	diagnostics, err := parseOpaResult([]byte(output))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	var actual []string
+	for _, d := range diagnostics {
+		if d.Rule != linter.REGO_POLICY_VIOLATION {
+			t.Errorf("Unexpected rule %s", d.Rule)
+		}
+		actual = append(actual, string(d.Severity)+": "+d.Message)
+	}
+	expect := []string{
+		"Error: Backend must enable TLS",
+		"Warning: X-Debug header must not be set",
+		"Info: Too many tables",
+	}
+	if diff := cmp.Diff(expect, actual); diff != "" {
+		t.Errorf("Parsed violations mismatch, diff=%s", diff)
+	}
+	if diagnostics[1].Token.File != "main.vcl" || diagnostics[1].Token.Line != 9 || diagnostics[1].Token.Position != 7 {
+		t.Errorf("Unexpected violation location: %+v", diagnostics[1].Token)
+	}
+
+	// Undefined query returns empty result
+	diagnostics, err = parseOpaResult([]byte(`{}`))
+	if err != nil || len(diagnostics) != 0 {
+		t.Errorf("Empty result should not have violations, got %v, %v", diagnostics, err)
+	}
+
+	// Query must return a collection
+	if _, err := parseOpaResult([]byte(`{"result":[{"expressions":[{"value":true}]}]}`)); err == nil {
+		t.Errorf("Expected error for non-collection result but got nil")
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ysugimoto/falco/linter"
+)
+
+// Schema version of the command output, increment it when the output has breaking changes
+const outputSchemaVersion = 1
+
+// Command output status
+const (
+	outputStatusSuccess = "success"
+	outputStatusWarning = "warning"
+	outputStatusFailure = "failure"
+)
+
+// CommandOutput is the stable envelope of machine-readable output for orchestration commands like doctor and sync.
+// Summary is a single line which could be displayed as it is in chat or dashboards,
+// and Data is the command specific details.
+type CommandOutput struct {
+	SchemaVersion int         `json:"schema_version"`
+	Command       string      `json:"command"`
+	Status        string      `json:"status"`
+	Summary       string      `json:"summary"`
+	Error         string      `json:"error,omitempty"`
+	Data          interface{} `json:"data,omitempty"`
+}
+
+func newCommandOutput(command, status, summary string, data interface{}) *CommandOutput {
+	return &CommandOutput{
+		SchemaVersion: outputSchemaVersion,
+		Command:       command,
+		Status:        status,
+		Summary:       summary,
+		Data:          data,
+	}
+}
+
+// Output for the command which could not complete
+func errorCommandOutput(command string, err error) *CommandOutput {
+	out := newCommandOutput(command, outputStatusFailure, "Failed to run "+command, nil)
+	out.Error = err.Error()
+	return out
+}
+
+func (o *CommandOutput) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(o)
+}
+
+// Output for lint command, data is the structured report of lint results.
+// Parse errors are reported as diagnostics which do not have the rule.
+func lintCommandOutput(result *RunnerResult) *CommandOutput {
+	report := lintReport(result)
+	status := outputStatusSuccess
+	switch {
+	case report.Summary.Errors > 0:
+		status = outputStatusFailure
+	case report.Summary.Warnings > 0:
+		status = outputStatusWarning
+	}
+	summary := fmt.Sprintf(
+		"%d errors, %d warnings, %d recommendations",
+		report.Summary.Errors, report.Summary.Warnings, report.Summary.Infos,
+	)
+	return newCommandOutput(subcommandLint, status, summary, report)
+}
+
+// Create the structured report from lint result, parse errors are reported as errors without the rule
+func lintReport(result *RunnerResult) *linter.Report {
+	var diagnostics []*linter.Diagnostic
+	files := make([]string, 0, len(result.ParseErrors))
+	for file := range result.ParseErrors {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		pe := result.ParseErrors[file]
+		diagnostics = append(diagnostics, &linter.Diagnostic{
+			Severity: linter.ERROR,
+			Token:    pe.Token,
+			Message:  pe.Message,
+		})
+	}
+	for _, d := range result.LintErrors {
+		diagnostics = append(diagnostics, d...)
+	}
+
+	return linter.NewReport(diagnostics)
+}
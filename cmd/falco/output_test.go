@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/doctor"
+	"github.com/ysugimoto/falco/remote"
+)
+
+func TestDoctorCommandOutput(t *testing.T) {
+	report := &doctor.Report{}
+	report.Add(
+		&doctor.Result{Category: doctor.CategoryLint, Target: "vcl", Status: doctor.StatusPass},
+		&doctor.Result{Category: doctor.CategoryDNS, Target: "F_origin", Status: doctor.StatusWarn},
+	)
+
+	var buf bytes.Buffer
+	if err := doctorCommandOutput(report).Write(&buf); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	expect := map[string]interface{}{
+		"schema_version": float64(outputSchemaVersion),
+		"command":        "doctor",
+		"status":         "warning",
+		"summary":        "Readiness 75%: 1 passed, 1 warnings, 0 failed",
+	}
+	for key, value := range expect {
+		if diff := cmp.Diff(value, out[key]); diff != "" {
+			t.Errorf("Field %s mismatch, diff=%s", key, diff)
+		}
+	}
+	data, ok := out["data"].(map[string]interface{})
+	if !ok {
+		t.Errorf("data field must be an object")
+		return
+	}
+	for _, key := range []string{"results", "scorecards", "score", "ready"} {
+		if _, ok := data[key]; !ok {
+			t.Errorf("data field must have %s", key)
+		}
+	}
+}
+
+func TestErrorCommandOutput(t *testing.T) {
+	out := errorCommandOutput(subcommandSync, fmt.Errorf("Unrecognized sync target: foo"))
+	if out.Status != outputStatusFailure || out.Error != "Unrecognized sync target: foo" {
+		t.Errorf("Unexpected output: %+v", out)
+	}
+}
+
+func TestSyncResultSummary(t *testing.T) {
+	changes := []*remote.EdgeDictionaryItemOperation{
+		{Op: "create", Key: "/campaign", Value: "/sale"},
+	}
+	tests := []struct {
+		result *syncResult
+		expect string
+	}{
+		{
+			result: &syncResult{Name: "redirects"},
+			expect: "Edge dictionary redirects is up to date",
+		},
+		{
+			result: &syncResult{Name: "redirects", DryRun: true, Changes: changes},
+			expect: "Dry run: 1 changes are not applied to edge dictionary redirects",
+		},
+		{
+			result: &syncResult{Name: "redirects", Applied: 1, Changes: changes},
+			expect: "Applied 1 changes to edge dictionary redirects",
+		},
+	}
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.expect, tt.result.summary()); diff != "" {
+			t.Errorf("Summary mismatch, diff=%s", diff)
+		}
+	}
+}
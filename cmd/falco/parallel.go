@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/crash"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/snippets"
+)
+
+// Create resolvers for each entry VCL
+func entryResolvers(files []string, includePaths []string) ([]resolver.Resolver, error) {
+	var resolvers []resolver.Resolver
+	for _, file := range files {
+		r, err := resolver.NewFileResolvers(file, includePaths)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, r...)
+	}
+	return resolvers, nil
+}
+
+// Lint multiple entry VCLs concurrently. Included modules which are shared between entries are parsed only once,
+// and results are output in the order of entries after all entries are linted.
+func runLintParallel(c *config.Config, fetcher snippets.Fetcher, files []string, resolvers []resolver.Resolver) error {
+	concurrency := c.Linter.Parallel
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	modules := linter.NewModuleCache()
+	// Fixes rewrite source files which may be included from other entries,
+	// then entries must be linted one by one without sharing parsed modules
+	if c.Linter.Fix {
+		concurrency = 1
+		modules = nil
+	}
+
+	runners := make([]*Runner, len(resolvers))
+	buffers := make([]*bytes.Buffer, len(resolvers))
+	for i := range resolvers {
+		runner, err := NewRunner(c, fetcher)
+		if err != nil {
+			writeln(red, err.Error())
+			return ErrExit
+		}
+		buffers[i] = &bytes.Buffer{}
+		runner.modules = modules
+		runner.out = buffers[i]
+		runners[i] = runner
+	}
+
+	results := make([]*RunnerResult, len(resolvers))
+	errs := make([]error, len(resolvers))
+	panics := make([]*crash.Panic, len(resolvers))
+	// Entries which are not linted yet are skipped after any entry fails in fail-fast mode
+	skipped := make([]bool, len(resolvers))
+	var failed atomic.Bool
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range resolvers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// Panic in this goroutine could not be recovered by main, keep it and raise after all linting finished
+			defer func() {
+				if r := recover(); r != nil {
+					panics[i] = crash.Wrap(r)
+				}
+			}()
+			if c.Linter.FailFast && failed.Load() {
+				skipped[i] = true
+				return
+			}
+			results[i], errs[i] = runners[i].Run(resolvers[i])
+			if errs[i] != nil || results[i].Errors > 0 {
+				failed.Store(true)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range panics {
+		if panics[i] != nil {
+			panic(panics[i])
+		}
+	}
+
+	var exitErr error
+	for i := range resolvers {
+		writeln(white, `Lint main VCL of "%s"`, files[i])
+		writeln(white, strings.Repeat("=", 19+len(files[i])))
+		if skipped[i] {
+			writeln(white, "Skipped because linting of other entry failed in fail-fast mode")
+			writeln(white, "")
+			continue
+		}
+		output.Write(buffers[i].Bytes()) // nolint:errcheck
+		if err := reportLint(runners[i], results[i], errs[i]); err == ErrExit {
+			exitErr = ErrExit
+		}
+		writeln(white, "")
+	}
+	return exitErr
+}
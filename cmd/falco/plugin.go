@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/plugin"
+)
+
+const (
+	pluginCommandInstall = "install"
+	pluginCommandList    = "list"
+)
+
+// Result of plugin list command
+type pluginListResult struct {
+	Path    string              `json:"path"`
+	Plugins []*plugin.Installed `json:"plugins"`
+}
+
+// Plugin directory which is configured, or the default one
+func pluginDir(c *config.Config) string {
+	if c.Plugin != nil && c.Plugin.Path != "" {
+		return c.Plugin.Path
+	}
+	return plugin.DefaultPath()
+}
+
+func runPlugin(c *config.Config) error {
+	var err error
+	switch c.Commands.At(1) {
+	case pluginCommandInstall:
+		err = runPluginInstall(c, c.Commands.At(2))
+	case pluginCommandList:
+		err = runPluginList(c)
+	case "":
+		printHelp(subcommandPlugin)
+		return ErrExit
+	default:
+		err = fmt.Errorf("Unrecognized plugin command: %s", c.Commands.At(1))
+	}
+
+	if err != nil {
+		if !c.Json {
+			return err
+		}
+		errorCommandOutput(subcommandPlugin, err).Write(os.Stdout) // nolint:errcheck
+		return ErrExit
+	}
+	return nil
+}
+
+// Install plugin which is specified as "name", "name@version" or "github.com/owner/repo@version"
+func runPluginInstall(c *config.Config, spec string) error {
+	if spec == "" {
+		return fmt.Errorf("Plugin name to install must be specified")
+	}
+	name, version := spec, ""
+	if i := strings.LastIndex(spec, "@"); i > 0 {
+		name, version = spec[:i], spec[i+1:]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	registry := plugin.NewRegistry(c.Plugin.Registry, http.DefaultClient)
+	installer, err := plugin.NewInstaller(registry, pluginDir(c), c.Plugin.TrustedKeys)
+	if err != nil {
+		return err
+	}
+	installed, err := installer.Install(ctx, name, version)
+	if err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("Installed plugin %s %s to %s", installed.Name, installed.Version, installed.Binary)
+	if c.Json {
+		return newCommandOutput(subcommandPlugin, outputStatusSuccess, summary, installed).Write(os.Stdout)
+	}
+	writeln(green, "%s", summary)
+	if installed.Verified {
+		writeln(white, "Checksum and signature are verified")
+	} else {
+		writeln(yellow, "Checksum is verified but signature is not, configure plugin.trusted_keys to require signed plugins")
+	}
+	return nil
+}
+
+func runPluginList(c *config.Config) error {
+	dir := pluginDir(c)
+	plugins, err := plugin.List(dir)
+	if err != nil {
+		return err
+	}
+	result := &pluginListResult{
+		Path:    dir,
+		Plugins: plugins,
+	}
+	if c.Json {
+		summary := fmt.Sprintf("%d plugins are found", len(plugins))
+		return newCommandOutput(subcommandPlugin, outputStatusSuccess, summary, result).Write(os.Stdout)
+	}
+
+	if len(plugins) == 0 {
+		writeln(white, "No plugins are installed in %s", dir)
+		return nil
+	}
+	fmt.Fprintf(os.Stdout, "%-24s %-12s %-16s %s\n", "NAME", "VERSION", "CAPABILITIES", "SOURCE")
+	for _, p := range plugins {
+		version := p.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(os.Stdout, "%-24s %-12s %-16s %s\n", p.Name, version, strings.Join(p.Capabilities, ","), p.Source)
+	}
+	return nil
+}
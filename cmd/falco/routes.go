@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func runRoutes(runner *Runner, rslv resolver.Resolver) error {
+	table, err := runner.Routes(rslv)
+	if err != nil {
+		if err != ErrParser {
+			writeln(red, err.Error())
+		}
+		return ErrExit
+	}
+
+	switch {
+	case runner.config.Json:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(table)
+	case runner.config.Format == "csv":
+		err = table.WriteCSV(os.Stdout)
+	case runner.config.Format == "html":
+		err = table.WriteHTML(os.Stdout, "Routing decision table of "+table.Main)
+	default:
+		printRoute := func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stdout, format+"\n", args...)
+		}
+		for i, r := range table.Routes {
+			printRoute("Route %d: %s -> %s (%s)", i+1, r.BackendName(), r.Action, r.Location())
+			for j, c := range r.ConditionStrings() {
+				if j > 0 {
+					c = "AND " + c
+				}
+				printRoute("    %s", c)
+			}
+		}
+		printRoute(strings.Repeat("-", 80))
+		printRoute("%d routes in vcl_recv of %s", len(table.Routes), table.Main)
+	}
+	if err != nil {
+		writeln(red, err.Error())
+		return ErrExit
+	}
+
+	if table.Truncated {
+		writeln(yellow, "Routes are truncated because the number of paths exceeds the limit")
+	}
+	return nil
+}
@@ -2,9 +2,15 @@ package main
 
 import (
 	"bytes"
+	gocontext "context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
@@ -12,22 +18,35 @@ import (
 	"github.com/ysugimoto/falco/config"
 	"github.com/ysugimoto/falco/context"
 	"github.com/ysugimoto/falco/debugger"
+	"github.com/ysugimoto/falco/doctor"
 	"github.com/ysugimoto/falco/interpreter"
 	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/dashboard"
+	"github.com/ysugimoto/falco/interpreter/datafile"
+	"github.com/ysugimoto/falco/interpreter/loadgen"
+	"github.com/ysugimoto/falco/interpreter/shadow"
+	"github.com/ysugimoto/falco/interpreter/tenant"
 	"github.com/ysugimoto/falco/lexer"
 	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/linter/fix"
+	"github.com/ysugimoto/falco/migrate"
 	"github.com/ysugimoto/falco/parser"
 	"github.com/ysugimoto/falco/plugin"
 	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/routing"
 	"github.com/ysugimoto/falco/snippets"
 	"github.com/ysugimoto/falco/tester"
 	"github.com/ysugimoto/falco/types"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
 	ErrParser = fmt.Errorf("parser error")
 )
 
+// Baseline file which is generated by --update-baseline when the path is not configured
+const defaultBaselineFile = ".falco-baseline.json"
+
 type Level int
 
 const (
@@ -41,8 +60,16 @@ type RunnerResult struct {
 	Warnings int
 	Errors   int
 
-	LintErrors  map[string][]*linter.LintError
+	LintErrors  map[string][]*linter.Diagnostic
 	ParseErrors map[string]*parser.ParseError
+	// Complexity metrics of each subroutine
+	Metrics []*linter.SubroutineMetrics
+	// Count of lint errors which are suppressed by the baseline file
+	Baselined int
+	// Count of lint errors which are not reported because they are outside of changed lines
+	Unchanged int
+	// Time spent for each rule, only set when profiling is enabled
+	Profiles []*linter.RuleProfile `json:",omitempty"`
 
 	Vcl *plugin.VCL
 }
@@ -56,6 +83,8 @@ type StatsResult struct {
 	Directors   int    `json:"directors"`
 	Files       int    `json:"files"`
 	Lines       int    `json:"lines"`
+
+	Complexity []*linter.SubroutineMetrics `json:"complexity"`
 }
 
 type Fetcher interface {
@@ -70,18 +99,33 @@ type RunMode int
 const (
 	RunModeLint RunMode = 0x000001
 	RunModeStat RunMode = 0x000010
+	// Collect facts and diagnostics for preflight checks
+	RunModeFacts RunMode = 0x000100
 )
 
 type Runner struct {
 	transformers []*Transformer
-	overrides    map[string]linter.Severity
+	severities   map[linter.Rule]linter.Severity
+	only         []linter.Category
+	skip         []linter.Category
+	opa          *OpaEvaluator
 	lexers       map[string]*lexer.Lexer
 	snippets     *snippets.Snippets
 	config       *config.Config
+	// Parsed modules which are shared with other runners, may be nil
+	modules *linter.ModuleCache
+	// Buffer messages instead of writing to stderr when runners run concurrently
+	out io.Writer
 
 	level       Level
-	lintErrors  map[string][]*linter.LintError
+	lintErrors  map[string][]*linter.Diagnostic
 	parseErrors map[string]*parser.ParseError
+	facts       *linter.Facts
+	diagnostics []*linter.Diagnostic
+	metrics     []*linter.SubroutineMetrics
+	profiles    []*linter.RuleProfile
+	baselined   int
+	unchanged   int
 
 	// runner result fields
 	infos    int
@@ -97,16 +141,20 @@ func (r *Runner) message(c *color.Color, format string, args ...interface{}) {
 	if r.config.Json {
 		return
 	}
+	if r.out != nil {
+		fwrite(r.out, c, format, args...)
+		return
+	}
 	write(c, format, args...)
 }
 
 func NewRunner(c *config.Config, fetcher snippets.Fetcher) (*Runner, error) {
 	r := &Runner{
 		level:       LevelError,
-		overrides:   make(map[string]linter.Severity),
+		severities:  make(map[linter.Rule]linter.Severity),
 		lexers:      make(map[string]*lexer.Lexer),
 		config:      c,
-		lintErrors:  make(map[string][]*linter.LintError),
+		lintErrors:  make(map[string][]*linter.Diagnostic),
 		parseErrors: make(map[string]*parser.ParseError),
 	}
 
@@ -120,13 +168,16 @@ func NewRunner(c *config.Config, fetcher snippets.Fetcher) (*Runner, error) {
 		if err := r.snippets.FetchLoggingEndpoint(fetcher); err != nil {
 			r.message(red, err.Error()+"\n")
 		}
+		if err := r.snippets.FetchDomains(fetcher); err != nil {
+			r.message(red, err.Error()+"\n")
+		}
 	}
 
 	// Check transformer exists and format to absolute path
 	// Transformer is provided as independent binary, named "falco-transform-[name]"
 	// so, if transformer specified with "lambdaedge", program lookup "falco-transform-lambdaedge" binary existence
 	for i := range c.Transforms {
-		tf, err := NewTransformer(c.Transforms[i])
+		tf, err := NewTransformer(c.Transforms[i], pluginDir(c))
 		if err != nil {
 			return nil, err
 		}
@@ -142,17 +193,39 @@ func NewRunner(c *config.Config, fetcher snippets.Fetcher) (*Runner, error) {
 
 	// Override linter rules
 	for key, value := range c.Linter.Rules {
-		switch strings.ToUpper(value) {
-		case "ERROR":
-			r.overrides[key] = linter.ERROR
-		case "WARNING":
-			r.overrides[key] = linter.WARNING
-		case "INFO":
-			r.overrides[key] = linter.INFO
-		case "IGNORE":
-			r.overrides[key] = linter.IGNORE
-		default:
+		severity, err := linter.ParseSeverity(value)
+		if err != nil {
 			r.message(yellow, "Level for rule %s has invalid value %s, skipping.\n", key, value)
+			continue
+		}
+		if renamed, ok := migrate.RenamedRule(key); ok {
+			r.message(yellow, "Rule %s is renamed to %s, run falco migrate-config to update the configuration.\n", key, renamed)
+			key = renamed
+		}
+		r.severities[linter.Rule(key)] = severity
+	}
+
+	// Select rule categories to report
+	var err error
+	if r.only, err = linter.ParseCategories(c.Linter.OnlyCategories); err != nil {
+		return nil, err
+	}
+	if r.skip, err = linter.ParseCategories(c.Linter.SkipCategories); err != nil {
+		return nil, err
+	}
+
+	// Validate naming convention patterns and statement policies before linting
+	if _, err := c.Linter.Naming.Patterns(); err != nil {
+		return nil, err
+	}
+	if err := linter.ValidatePolicies(c.Linter.Policies); err != nil {
+		return nil, err
+	}
+
+	// Rego policies are evaluated by external opa command
+	if c.Linter.Opa.IsEnabled() {
+		if r.opa, err = NewOpaEvaluator(c.Linter.Opa); err != nil {
+			return nil, err
 		}
 	}
 
@@ -199,6 +272,10 @@ func (r *Runner) Run(rslv resolver.Resolver) (*RunnerResult, error) {
 		Errors:      r.errors,
 		LintErrors:  r.lintErrors,
 		ParseErrors: r.parseErrors,
+		Metrics:     r.metrics,
+		Baselined:   r.baselined,
+		Unchanged:   r.unchanged,
+		Profiles:    r.profiles,
 		Vcl:         vcl,
 	}, nil
 }
@@ -220,12 +297,48 @@ func (r *Runner) run(ctx *context.Context, main *resolver.VCL, mode RunMode) (*p
 		}
 	}
 
-	lt := linter.New()
+	options := []linter.Option{
+		linter.WithDebugHeader(r.config.DebugHeader),
+		linter.WithCompliance(r.config.Linter.Compliance),
+		linter.WithCategories(r.only, r.skip),
+		linter.WithSeverities(r.severities),
+		linter.WithNamingConvention(r.config.Linter.Naming),
+		linter.WithPolicies(r.config.Linter.Policies),
+		linter.WithComputeHosts(r.config.Linter.ComputeHosts),
+		linter.WithComplexity(r.config.Linter.Complexity),
+		linter.WithTableLimits(r.config.Linter.TableLimits),
+		linter.WithCompilationLimits(r.config.Linter.CompilationLimits),
+		linter.WithProtectedHeaders(r.config.Linter.ProtectedHeaders),
+		linter.WithExplainFatal(r.config.Linter.ExplainFatal),
+		linter.WithFailFast(r.config.Linter.FailFast),
+		linter.WithAnnotations(r.config.Linter.Annotations),
+	}
+	if r.modules != nil {
+		options = append(options, linter.WithModuleCache(r.modules))
+	}
+	if opa := r.config.Linter.Opa; r.opa != nil || (opa != nil && opa.Facts != "") || mode&RunModeFacts > 0 {
+		options = append(options, linter.WithFacts())
+	}
+	if r.snippets != nil && len(r.snippets.Domains) > 0 {
+		options = append(options, linter.WithServiceDomains(r.snippets.Domains))
+	}
+	lt := linter.New(options...)
+	// Lexers of the main VCL and snippets are not owned by the linter
+	lt.LintSourceEncoding(main.Name, r.lexers[main.Name])
+	if r.snippets != nil {
+		for _, snip := range r.snippets.EmbedSnippets() {
+			lt.LintSourceEncoding(snip.Name, r.lexers[snip.Name])
+		}
+	}
 	lt.Lint(vcl, ctx)
 
 	for k, v := range lt.Lexers() {
 		r.lexers[k] = v
 	}
+	r.metrics = lt.Metrics()
+	if r.config.Linter.ProfileRules {
+		r.profiles = lt.Profiles()
+	}
 
 	// If runner is running as stat mode, prevent to output lint result
 	if mode&RunModeStat > 0 {
@@ -233,6 +346,14 @@ func (r *Runner) run(ctx *context.Context, main *resolver.VCL, mode RunMode) (*p
 	}
 
 	// Checking Fatal error, it means parse error occurs on included submodule
+	if lt.FatalError != nil && r.config.Linter.ExplainFatal && !r.config.Json {
+		// Report partial results which are linted other than the fatal part, and then explain the fatal error
+		for _, le := range lt.Diagnostics {
+			r.printLinterError(r.lexers[main.Name], le.Severity, le)
+		}
+		r.printFatalError(r.lexers[main.Name], lt.FatalError)
+		return nil, ErrParser
+	}
 	if lt.FatalError != nil {
 		if pe, ok := lt.FatalError.Error.(*parser.ParseError); ok {
 			var file string
@@ -249,23 +370,34 @@ func (r *Runner) run(ctx *context.Context, main *resolver.VCL, mode RunMode) (*p
 		return nil, ErrParser
 	}
 
-	if len(lt.Errors) > 0 {
-		for _, err := range lt.Errors {
-			le, ok := err.(*linter.LintError)
-			if !ok {
-				continue
-			}
-			// check severity with overrides
-			severity := le.Severity
-			if v, ok := r.overrides[string(le.Rule)]; ok {
-				severity = v
-			}
+	if err := r.evaluateFacts(lt); err != nil {
+		return nil, err
+	}
+	if mode&RunModeFacts > 0 {
+		r.facts = lt.Facts()
+		r.diagnostics = lt.Diagnostics
+	}
 
-			// Store all but ignored linter errors
-			if r.config.Json && severity != linter.IGNORE {
+	diagnostics := lt.Diagnostics
+	if r.config.Linter.Fix {
+		if diagnostics, err = r.applyFixes(diagnostics); err != nil {
+			return nil, err
+		}
+	}
+	if diagnostics, err = r.applyBaseline(diagnostics); err != nil {
+		return nil, err
+	}
+	if diagnostics, err = r.applyDiff(diagnostics); err != nil {
+		return nil, err
+	}
+
+	if len(diagnostics) > 0 {
+		for _, le := range diagnostics {
+			// Severity is already overridden and ignored rules are not reported by linter
+			if r.config.Json || r.config.Format == "junit" {
 				r.lintErrors[le.Token.File] = append(r.lintErrors[le.Token.File], le)
 			}
-			r.printLinterError(r.lexers[main.Name], severity, le)
+			r.printLinterError(r.lexers[main.Name], le.Severity, le)
 		}
 	}
 
@@ -275,6 +407,119 @@ func (r *Runner) run(ctx *context.Context, main *resolver.VCL, mode RunMode) (*p
 	}, nil
 }
 
+// Apply suggested fixes to the source files and returns diagnostics which are not fixed
+func (r *Runner) applyFixes(diagnostics []*linter.Diagnostic) ([]*linter.Diagnostic, error) {
+	applied := make(map[*linter.Fix]struct{})
+	for file, fixes := range fix.Files(diagnostics) {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read %s to fix: %w", file, err)
+		}
+		result, err := fix.Apply(src, fixes)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fix %s: %w", file, err)
+		}
+		if len(result.Applied) == 0 {
+			continue
+		}
+		if err := os.WriteFile(file, result.Source, 0o644); err != nil {
+			return nil, fmt.Errorf("Failed to write fixed source to %s: %w", file, err)
+		}
+		for _, f := range result.Applied {
+			applied[f] = struct{}{}
+		}
+		r.message(green, ":wrench: %d problems are fixed in %s\n", len(result.Applied), file)
+		if len(result.Skipped) > 0 {
+			r.message(yellow, "%d fixes are skipped because they conflict with other fixes, run again to apply them\n", len(result.Skipped))
+		}
+	}
+
+	var remains []*linter.Diagnostic
+	for _, d := range diagnostics {
+		if d.Fix != nil {
+			if _, ok := applied[d.Fix]; ok {
+				continue
+			}
+		}
+		remains = append(remains, d)
+	}
+	return remains, nil
+}
+
+// Suppress diagnostics which are recorded in the baseline file, or record all diagnostics to the file on update
+func (r *Runner) applyBaseline(diagnostics []*linter.Diagnostic) ([]*linter.Diagnostic, error) {
+	file := r.config.Linter.Baseline
+	if r.config.Linter.UpdateBaseline {
+		if file == "" {
+			file = defaultBaselineFile
+		}
+		if err := linter.NewBaseline(diagnostics, r.lexers).Save(file); err != nil {
+			return nil, err
+		}
+		r.message(green, "%d lint errors are recorded to the baseline file %s\n", len(diagnostics), file)
+	}
+	if file == "" {
+		return diagnostics, nil
+	}
+
+	baseline, err := linter.LoadBaseline(file)
+	if err != nil {
+		return nil, err
+	}
+	remains, suppressed := baseline.Filter(diagnostics, r.lexers)
+	r.baselined = suppressed
+	if resolved := len(baseline.Entries) - suppressed; resolved > 0 {
+		r.message(cyan, "%d lint errors in the baseline file are resolved, run with --update-baseline to remove them\n", resolved)
+	}
+	return remains, nil
+}
+
+// Report only diagnostics which intersect changed lines in diff mode.
+// Whole VCL is still parsed and linted, so errors on changed lines which depend on other files are reported.
+func (r *Runner) applyDiff(diagnostics []*linter.Diagnostic) ([]*linter.Diagnostic, error) {
+	changes, err := loadChangedLines(r.config.Linter)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		return diagnostics, nil
+	}
+	remains, skipped := changes.Filter(diagnostics)
+	r.unchanged = skipped
+	return remains, nil
+}
+
+// Export collected facts and evaluate Rego policies, violations are merged as lint errors
+func (r *Runner) evaluateFacts(lt *linter.Linter) error {
+	facts := lt.Facts()
+	if facts == nil {
+		return nil
+	}
+
+	if opa := r.config.Linter.Opa; opa != nil && opa.Facts != "" {
+		file := opa.Facts
+		buf, err := json.MarshalIndent(facts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Failed to encode facts: %w", err)
+		}
+		if err := os.WriteFile(file, buf, 0o644); err != nil {
+			return fmt.Errorf("Failed to write facts to %s: %w", file, err)
+		}
+	}
+
+	if r.opa == nil {
+		return nil
+	}
+	diagnostics, err := r.opa.Evaluate(facts)
+	if err != nil {
+		return err
+	}
+	for _, d := range diagnostics {
+		lt.Error(d)
+	}
+	return nil
+}
+
 func (r *Runner) parseVCL(name, code string) (*ast.VCL, error) {
 	lx := lexer.NewFromString(code, lexer.WithFile(name))
 	p := parser.New(lx)
@@ -325,7 +570,43 @@ func (r *Runner) printParseError(lx *lexer.Lexer, file string, err *parser.Parse
 	}
 }
 
-func (r *Runner) printLinterError(lx *lexer.Lexer, severity linter.Severity, err *linter.LintError) {
+// Explain the fatal error with the statement being processed and the reproduction snippet
+func (r *Runner) printFatalError(lx *lexer.Lexer, fe *linter.FatalError) {
+	if fe.Lexer != nil {
+		lx = fe.Lexer
+	}
+	r.message(red, "\n:boom: Fatal error occurred, lint results above may be partial\n")
+	if pe, ok := fe.Error.(*parser.ParseError); ok {
+		var file string
+		if pe.Token.File != "" {
+			file = "in " + pe.Token.File + " "
+		}
+		r.printParseError(lx, file, pe)
+	} else {
+		r.message(red, "%s\n", fe.Error)
+	}
+
+	if fe.Statement != nil {
+		tok := fe.Statement.GetMeta().Token
+		file := "main VCL"
+		if tok.File != "" {
+			file = tok.File
+		}
+		r.message(white, "\nProcessing statement in %s at line %d, position %d:\n", file, tok.Line, tok.Position)
+	}
+	if snippet := fe.Snippet(); snippet != "" {
+		r.message(white, "\nReproduction snippet:\n")
+		for _, line := range strings.Split(snippet, "\n") {
+			r.message(white, "    %s\n", strings.ReplaceAll(line, "\t", "    "))
+		}
+	}
+	if len(fe.Stack) > 0 {
+		r.message(white, "\nStack trace:\n%s\n", fe.Stack)
+	}
+	r.message(white, "\nIf this looks like a bug of falco, please report it with the snippet at https://github.com/ysugimoto/falco/issues\n")
+}
+
+func (r *Runner) printLinterError(lx *lexer.Lexer, severity linter.Severity, err *linter.Diagnostic) {
 	var rule, file string
 
 	if err.Rule != "" {
@@ -358,10 +639,14 @@ func (r *Runner) printLinterError(lx *lexer.Lexer, severity linter.Severity, err
 		return
 	}
 
-	r.message(white, "%sat line %d, position %d\n", file, err.Token.Line, err.Token.Position)
+	// Error which is reported by external policy engine may not have location
+	if err.Token.Line > 0 {
+		r.message(white, "%sat line %d, position %d\n", file, err.Token.Line, err.Token.Position)
+	}
 
+	// Lexer may not exist when the error is reported by external policy engine
 	problemLine := err.Token.Line
-	for l := problemLine - 1; l <= problemLine+1; l++ {
+	for l := problemLine - 1; lx != nil && problemLine > 0 && l <= problemLine+1; l++ {
 		line, ok := lx.GetLine(l)
 		if !ok {
 			continue
@@ -380,12 +665,34 @@ func (r *Runner) printLinterError(lx *lexer.Lexer, severity linter.Severity, err
 		}
 	}
 
+	for _, related := range err.Related {
+		r.printRelatedInformation(lx, related)
+	}
+
 	if err.Reference != "" {
 		r.message(white, "See reference documentation: %s\n", err.Reference)
 	}
 	r.message(white, "\n")
 }
 
+func (r *Runner) printRelatedInformation(lx *lexer.Lexer, related *linter.RelatedInformation) {
+	var file string
+	if related.Token.File != "" {
+		file = "in " + related.Token.File + " "
+		lx = r.lexers[related.Token.File]
+	}
+
+	r.message(cyan, "  %s %sat line %d, position %d\n",
+		related.Message, file, related.Token.Line, related.Token.Position,
+	)
+	if lx == nil {
+		return
+	}
+	if line, ok := lx.GetLine(related.Token.Line); ok {
+		r.message(white, "   %d|%s\n", related.Token.Line, strings.ReplaceAll(line, "\t", "    "))
+	}
+}
+
 func (r *Runner) Stats(rslv resolver.Resolver) (*StatsResult, error) {
 	options := []context.Option{context.WithResolver(rslv)}
 	// If remote snippets exists, prepare parse and prepend to main VCL
@@ -412,6 +719,7 @@ func (r *Runner) Stats(rslv resolver.Resolver) (*StatsResult, error) {
 		Backends:    len(ctx.Backends),
 		Acls:        len(ctx.Acls),
 		Directors:   len(ctx.Directors),
+		Complexity:  r.metrics,
 	}
 
 	for _, lx := range r.lexers {
@@ -422,7 +730,260 @@ func (r *Runner) Stats(rslv resolver.Resolver) (*StatsResult, error) {
 	return stats, nil
 }
 
+// Routes builds the decision table of routing logic in vcl_recv
+func (r *Runner) Routes(rslv resolver.Resolver) (*routing.Table, error) {
+	main, err := rslv.MainVCL()
+	if err != nil {
+		return nil, err
+	}
+
+	vcl, err := r.parseVCL(main.Name, main.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []routing.Option{routing.WithResolver(rslv)}
+	// If remote snippets exists, prepend to main VCL in order to find backends
+	if r.snippets != nil {
+		options = append(options, routing.WithSnippets(r.snippets))
+		for _, snip := range r.snippets.EmbedSnippets() {
+			s, err := r.parseVCL(snip.Name, snip.Data)
+			if err != nil {
+				return nil, err
+			}
+			vcl.Statements = append(s.Statements, vcl.Statements...)
+		}
+	}
+
+	table, err := routing.New(options...).Analyze(vcl)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	table.Main = main.Name
+	return table, nil
+}
+
+// Doctor runs preflight checks for resources which are declared in VCL
+func (r *Runner) Doctor(rslv resolver.Resolver) (*doctor.Report, error) {
+	options := []context.Option{context.WithResolver(rslv)}
+	if r.snippets != nil {
+		options = append(options, context.WithSnippets(r.snippets))
+	}
+
+	main, err := rslv.MainVCL()
+	if err != nil {
+		return nil, err
+	}
+
+	// Run linter and print diagnostics as the first check, parse errors stop all checks
+	ctx := context.New(options...)
+	if _, err := r.run(ctx, main, RunModeLint|RunModeFacts); err != nil {
+		return nil, err
+	}
+
+	report := &doctor.Report{}
+	report.Add(doctor.LintResult(r.errors, r.warnings))
+	report.Add(doctor.IncludeResults(len(r.lexers), r.diagnostics)...)
+
+	c := gocontext.Background()
+	report.Add(doctor.NewDNSChecker().Check(c, doctor.BackendDNSTargets(ctx.Backends))...)
+
+	targets := doctor.BackendTLSTargets(ctx.Backends)
+	var endpoints map[string]struct{}
+	if r.snippets != nil {
+		targets = append(targets, doctor.DomainTLSTargets(r.snippets.Domains)...)
+		endpoints = r.snippets.LoggingEndpoints
+	}
+	report.Add(doctor.NewTLSChecker().Check(c, targets)...)
+	report.Add(doctor.LoggingResults(r.facts.Logs, endpoints)...)
+
+	files := make(map[string]int)
+	for name, lx := range r.lexers {
+		files[name] = lexerSize(lx)
+	}
+	report.Add(doctor.LimitResults(r.facts, files, doctor.Limits{
+		MaxBackends: r.config.OverrideMaxBackends,
+		MaxAcls:     r.config.OverrideMaxAcls,
+	})...)
+	return report, nil
+}
+
+// Estimate byte size of the source from lexed lines
+func lexerSize(lx *lexer.Lexer) int {
+	var size int
+	for n := 1; ; n++ {
+		line, ok := lx.GetLine(n)
+		if !ok {
+			return size
+		}
+		size += len(line) + 1
+	}
+}
+
 func (r *Runner) Simulate(rslv resolver.Resolver) error {
+	sc := r.config.Simulator
+	w, err := r.watchDataFiles()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	options := r.simulatorOptions(rslv, w)
+
+	// If debugger flag is on, run debugger mode
+	if sc.IsDebug {
+		return debugger.New(interpreter.New(options...)).Run(sc.Port)
+	}
+
+	i := interpreter.New(options...)
+	if err := r.attachSimulatorHooks(i); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Otherwise, simply start simulator server
+	if sc.Dashboard {
+		writeln(cyan, "Simulator dashboard is enabled: http://localhost:%d%s", sc.Port, dashboard.Prefix)
+	}
+	s := &http.Server{
+		Handler: r.simulatorHandler(i),
+		Addr:    fmt.Sprintf(":%d", sc.Port),
+	}
+	writeln(green, "Simulator server starts on 0.0.0.0:%d", sc.Port)
+	return s.ListenAndServe()
+}
+
+// SimulateServices runs multiple services which are configured in simulator.services side by side.
+// Each service has an isolated interpreter, so cache and context are not shared between services.
+func (r *Runner) SimulateServices() error {
+	sc := r.config.Simulator
+	w, err := r.watchDataFiles()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Services are looked up lazily because chained services could be declared in any order
+	names := make(map[string]*tenant.Service)
+	for _, svc := range sc.Services {
+		names[svc.Name] = nil
+	}
+
+	// Group services by listen port
+	ports := make(map[int][]*tenant.Service)
+	for _, svc := range sc.Services {
+		if svc.Main == "" {
+			return fmt.Errorf("Main VCL of service %s must be specified", svc.Name)
+		}
+		backends := make(map[string]icontext.ServiceBackend)
+		for backend, name := range svc.Backends {
+			if _, ok := names[name]; !ok {
+				return fmt.Errorf("Service %s is not found for backend %s of service %s", name, backend, svc.Name)
+			}
+			from, to := svc.Name, name
+			backends[backend] = func(req *http.Request) (*http.Response, error) {
+				return names[to].Backend(from)(req)
+			}
+		}
+		includePaths := append([]string{}, r.config.IncludePaths...)
+		resolvers, err := resolver.NewFileResolvers(svc.Main, append(includePaths, svc.IncludePaths...))
+		if err != nil {
+			return fmt.Errorf("Failed to resolve main VCL of service %s: %w", svc.Name, err)
+		}
+		options := r.simulatorOptions(resolvers[0], w)
+		if len(backends) > 0 {
+			options = append(options, icontext.WithServiceBackends(backends))
+		}
+		i := interpreter.New(options...)
+		if err := r.attachSimulatorHooks(i); err != nil {
+			return errors.WithStack(err)
+		}
+
+		port := svc.Port
+		if port == 0 {
+			port = sc.Port
+		}
+		service := &tenant.Service{
+			Name:    svc.Name,
+			Hosts:   svc.Hosts,
+			Handler: r.simulatorHandler(i),
+			Edge:    i.Serve,
+		}
+		names[svc.Name] = service
+		ports[port] = append(ports[port], service)
+	}
+
+	listens := make([]int, 0, len(ports))
+	for port := range ports {
+		listens = append(listens, port)
+	}
+	sort.Ints(listens)
+
+	var eg errgroup.Group
+	for _, port := range listens {
+		services := ports[port]
+		router, err := tenant.NewRouter(services...)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, svc := range services {
+			hosts := "all hosts"
+			if len(services) > 1 {
+				hosts = strings.Join(svc.Hosts, ", ")
+			}
+			writeln(green, "Service %s starts on 0.0.0.0:%d for %s", svc.Name, port, hosts)
+		}
+		s := &http.Server{
+			Handler: router,
+			Addr:    fmt.Sprintf(":%d", port),
+		}
+		eg.Go(s.ListenAndServe)
+	}
+	return eg.Wait()
+}
+
+// Watch table and ACL data files in order to apply changes without restart
+// Load sends synthetic traffic which is generated from the profile to the simulator.
+// The simulator is set up in the same way as simulate command but does not listen the port.
+func (r *Runner) Load(rslv resolver.Resolver) (*loadgen.Report, error) {
+	lc := r.config.Load
+	if lc.Profile == "" {
+		return nil, fmt.Errorf("Traffic profile must be specified with --profile option")
+	}
+	if lc.RPS <= 0 || lc.Duration <= 0 {
+		return nil, fmt.Errorf("Both --rps and --duration must be greater than zero")
+	}
+	profile, err := loadgen.LoadProfile(lc.Profile)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	w, err := r.watchDataFiles()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	i := interpreter.New(r.simulatorOptions(rslv, w)...)
+	return loadgen.New(i, profile).Run(lc.RPS, time.Duration(lc.Duration)*time.Second)
+}
+
+func (r *Runner) watchDataFiles() (*datafile.Watcher, error) {
+	dc := r.config.Simulator.DataFiles
+	if dc == nil || (len(dc.Tables) == 0 && len(dc.Acls) == 0) {
+		return nil, nil
+	}
+
+	w, err := datafile.New(dc, datafile.WithReporter(func(e *datafile.Event) {
+		if e.Error != nil {
+			writeln(red, e.String())
+		} else {
+			writeln(green, e.String())
+		}
+	}))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	go w.Watch(gocontext.Background())
+	writeln(cyan, "Watching %d table and ACL data files", len(dc.Tables)+len(dc.Acls))
+	return w, nil
+}
+
+func (r *Runner) simulatorOptions(rslv resolver.Resolver, w *datafile.Watcher) []icontext.Option {
 	sc := r.config.Simulator
 	options := []icontext.Option{
 		icontext.WithResolver(rslv),
@@ -438,24 +999,61 @@ func (r *Runner) Simulate(rslv resolver.Resolver) error {
 	if r.config.OverrideBackends != nil {
 		options = append(options, icontext.WithOverrideBackends(r.config.OverrideBackends))
 	}
+	if len(sc.DNS) > 0 {
+		options = append(options, icontext.WithDNSOverrides(sc.DNS))
+	}
+	if sc.DebugResponse {
+		options = append(options, icontext.WithDebugResponseHeaders())
+	}
+	if sc.TimeTravel {
+		options = append(options, icontext.WithTimeTravel())
+	}
+	if sc.ExplainCache {
+		options = append(options, icontext.WithExplainCache())
+	}
+	if w != nil {
+		options = append(options, icontext.WithDataFiles(w))
+	}
+	return options
+}
 
-	i := interpreter.New(options...)
+func (r *Runner) attachSimulatorHooks(i *interpreter.Interpreter) error {
+	sc := r.config.Simulator
 
-	// If debugger flag is on, run debugger mode
-	if sc.IsDebug {
-		return debugger.New(interpreter.New(options...)).Run(sc.Port)
+	// Verify debug headers are not leaked to the client response
+	if sc.CheckDebugHeaders {
+		i.AddHook(interpreter.DebugHeaderLeakHook(r.config.DebugHeader))
 	}
 
-	// Otherwise, simply start simulator server
-	mux := http.NewServeMux()
-	mux.Handle("/", i)
+	// If shadow backend is configured, mirror backend requests and report differences
+	if sc.Shadow != nil && sc.Shadow.Backend != "" {
+		sh, err := shadow.New(sc.Shadow, shadow.WithReporter(func(report *shadow.Report) {
+			if report.IsMatched() {
+				writeln(green, report.String())
+			} else {
+				writeln(yellow, report.String())
+			}
+		}))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		i.AddHook(sh.Hook)
+		writeln(cyan, "Shadow backend is enabled: %s", sc.Shadow.Backend)
+	}
+	return nil
+}
 
-	s := &http.Server{
-		Handler: mux,
-		Addr:    fmt.Sprintf(":%d", sc.Port),
+func (r *Runner) simulatorHandler(i *interpreter.Interpreter) http.Handler {
+	mux := http.NewServeMux()
+	if r.config.Simulator.Dashboard {
+		d := dashboard.New(i)
+		mux.Handle("/", d.Simulator())
+		mux.Handle(dashboard.Prefix, d)
+	} else {
+		mux.Handle("/", i)
+		mux.Handle(dashboard.CachePath, dashboard.CacheHandler(i.Cache()))
 	}
-	writeln(green, "Simulator server starts on 0.0.0.0:%d", sc.Port)
-	return s.ListenAndServe()
+	return mux
 }
 
 func (r *Runner) Test(rslv resolver.Resolver) (*tester.TestFactory, error) {
@@ -474,6 +1072,9 @@ func (r *Runner) Test(rslv resolver.Resolver) (*tester.TestFactory, error) {
 	if tc.OverrideHost != "" {
 		options = append(options, icontext.WithOverrideHost(tc.OverrideHost))
 	}
+	if tc.ExplainCache {
+		options = append(options, icontext.WithExplainCache())
+	}
 
 	r.message(white, "Running tests...")
 	factory, err := tester.New(tc, options).Run(r.config.Commands.At(1))
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter/datafile"
+	"github.com/ysugimoto/falco/remote"
+)
+
+const (
+	syncTargetTable = "table"
+)
+
+// Result of sync command
+type syncResult struct {
+	Target  string                                `json:"target"`
+	Name    string                                `json:"name"`
+	DryRun  bool                                  `json:"dry_run"`
+	Private bool                                  `json:"private"`
+	Applied int                                   `json:"applied"`
+	Changes []*remote.EdgeDictionaryItemOperation `json:"changes"`
+}
+
+func (r *syncResult) summary() string {
+	switch {
+	case len(r.Changes) == 0:
+		return fmt.Sprintf("Edge dictionary %s is up to date", r.Name)
+	case r.DryRun:
+		return fmt.Sprintf("Dry run: %d changes are not applied to edge dictionary %s", len(r.Changes), r.Name)
+	}
+	return fmt.Sprintf("Applied %d changes to edge dictionary %s", r.Applied, r.Name)
+}
+
+func runSync(c *config.Config) error {
+	var result *syncResult
+	var err error
+
+	switch c.Commands.At(1) {
+	case syncTargetTable:
+		result, err = syncTable(c, c.Commands.At(2))
+	case "":
+		printHelp(subcommandSync)
+		return ErrExit
+	default:
+		err = fmt.Errorf("Unrecognized sync target: %s", c.Commands.At(1))
+	}
+
+	if err != nil {
+		if !c.Json {
+			return err
+		}
+		errorCommandOutput(subcommandSync, err).Write(os.Stdout) // nolint:errcheck
+		return ErrExit
+	}
+	if c.Json {
+		return newCommandOutput(subcommandSync, outputStatusSuccess, result.summary(), result).Write(os.Stdout)
+	}
+	printSyncResult(result)
+	return nil
+}
+
+func printSyncResult(result *syncResult) {
+	if result.Private {
+		writeln(yellow, "Edge dictionary %s is private, all items are upserted and removed items are not deleted", result.Name)
+	}
+	for _, op := range result.Changes {
+		switch op.Op {
+		case "delete":
+			writeln(red, "- %s", op.Key)
+		case "update", "upsert":
+			writeln(yellow, "~ %s: %s", op.Key, op.Value)
+		default:
+			writeln(green, "+ %s: %s", op.Key, op.Value)
+		}
+	}
+
+	switch {
+	case len(result.Changes) == 0:
+		writeln(white, "%s", result.summary())
+	case result.DryRun:
+		writeln(yellow, "%s", result.summary())
+	default:
+		writeln(green, "%s", result.summary())
+	}
+}
+
+func syncTable(c *config.Config, name string) (*syncResult, error) {
+	if name == "" {
+		return nil, fmt.Errorf("Edge dictionary name to sync must be specified")
+	}
+	if c.Sync.From == "" {
+		return nil, fmt.Errorf("JSON file of the table data must be specified with --from option")
+	}
+	if c.FastlyServiceID == "" || c.FastlyApiKey == "" {
+		return nil, fmt.Errorf("Both FASTLY_SERVICE_ID and FASTLY_API_KEY environment variables must be specified")
+	}
+	desired, err := loadTableData(c.Sync.From)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load table data from %s: %w", c.Sync.From, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := remote.NewFastlyClient(http.DefaultClient, c.FastlyServiceID, c.FastlyApiKey)
+	version, err := client.LatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get latest version: %w", err)
+	}
+	dict, err := client.GetEdgeDictionary(ctx, version, name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get edge dictionary %s, the dictionary must exist in the active version: %w", name, err)
+	}
+
+	result := &syncResult{
+		Target:  syncTargetTable,
+		Name:    name,
+		DryRun:  c.DryRun,
+		Private: dict.WriteOnly,
+	}
+	if dict.WriteOnly {
+		// Items of private dictionary could not be read, so we could only upsert all items
+		// and could not delete items which are removed from the table data
+		result.Changes = remote.DiffEdgeDictionaryItems(desired, nil)
+		for i := range result.Changes {
+			result.Changes[i].Op = "upsert"
+		}
+	} else {
+		items, err := client.ListEdgeDictionaryItems(ctx, dict.Id)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list items of edge dictionary %s: %w", name, err)
+		}
+		result.Changes = remote.DiffEdgeDictionaryItems(desired, items)
+	}
+	// Output empty array instead of null in order to keep the output schema
+	if result.Changes == nil {
+		result.Changes = []*remote.EdgeDictionaryItemOperation{}
+	}
+
+	if len(result.Changes) == 0 || c.DryRun {
+		return result, nil
+	}
+	if err := client.BatchUpdateEdgeDictionaryItems(ctx, dict.Id, result.Changes); err != nil {
+		return nil, fmt.Errorf("Failed to update items of edge dictionary %s: %w", name, err)
+	}
+	result.Applied = len(result.Changes)
+	return result, nil
+}
+
+// Load table data from JSON object, scalar values are stored as string because edge dictionary only has string values
+func loadTableData(file string) (map[string]string, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return datafile.ParseTable(buf)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadTableData(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		expect  map[string]string
+		isError bool
+	}{
+		{
+			name:    "scalar values",
+			content: `{"/old": "/new", "ttl": 3600, "ratio": 0.5, "enabled": true}`,
+			expect:  map[string]string{"/old": "/new", "ttl": "3600", "ratio": "0.5", "enabled": "true"},
+		},
+		{
+			name:    "nested value",
+			content: `{"key": {"nested": "value"}}`,
+			isError: true,
+		},
+		{
+			name:    "not an object",
+			content: `["foo", "bar"]`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		file := filepath.Join(dir, "data.json")
+		if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		table, err := loadTableData(file)
+		if tt.isError {
+			if err == nil {
+				t.Errorf("%s: expected error but got nil", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, table); diff != "" {
+			t.Errorf("%s: table data mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
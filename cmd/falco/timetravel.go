@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/debugger/timetravel"
+)
+
+func runTimeTravel(c *config.Config) error {
+	var buf []byte
+	var err error
+
+	if file := c.Commands.At(1); file != "" {
+		buf, err = os.ReadFile(file)
+	} else if isPiped(os.Stdin) {
+		buf, err = io.ReadAll(os.Stdin)
+	} else {
+		printHelp(subcommandTimeTravel)
+		return ErrExit
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read time-travel log: %w", err)
+	}
+
+	log, err := timetravel.Parse(buf)
+	if err != nil {
+		return err
+	}
+	return timetravel.New(log).Run()
+}
+
+func isPiped(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice == 0
+}
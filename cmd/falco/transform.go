@@ -3,8 +3,9 @@ package main
 import (
 	"fmt"
 	"io"
-
 	"os/exec"
+
+	"github.com/ysugimoto/falco/plugin"
 )
 
 type Transformer struct {
@@ -12,11 +13,12 @@ type Transformer struct {
 	bin     string
 }
 
-func NewTransformer(name string) (*Transformer, error) {
-	command := fmt.Sprintf("falco-transform-%s", name)
-	bin, err := exec.LookPath(command)
+// Transformer binary is looked up from the plugin directory first, then PATH
+func NewTransformer(name, pluginDir string) (*Transformer, error) {
+	command := plugin.BinaryName(name)
+	bin, err := plugin.LookPath(pluginDir, name)
 	if err != nil {
-		return nil, fmt.Errorf(`Transformer command "%s" does not exist in PATH`, command)
+		return nil, fmt.Errorf(`Transformer command "%s" does not exist in plugin directory or PATH`, command)
 	}
 	return &Transformer{
 		command: command,
@@ -14,12 +14,43 @@ func (c Commands) At(n int) string {
 }
 
 var needValueOptions = map[string]struct{}{
-	"-I":             {},
-	"--include_path": {},
-	"-t":             {},
-	"--transformer":  {},
-	"-f":             {},
-	"--filter":       {},
+	"-I":                     {},
+	"--include_path":         {},
+	"-t":                     {},
+	"--transformer":          {},
+	"-f":                     {},
+	"--filter":               {},
+	"--shadow":               {},
+	"-o":                     {},
+	"--output":               {},
+	"--source":               {},
+	"--table_limit":          {},
+	"--from-url":             {},
+	"--from":                 {},
+	"--format":               {},
+	"--only":                 {},
+	"--skip":                 {},
+	"--rego":                 {},
+	"--facts":                {},
+	"--lang":                 {},
+	"--baseline":             {},
+	"--diff":                 {},
+	"--diff-base":            {},
+	"--parallel":             {},
+	"--junit-group":          {},
+	"--retries":              {},
+	"--quarantine":           {},
+	"-p":                     {},
+	"--port":                 {},
+	"--rps":                  {},
+	"--duration":             {},
+	"--profile":              {},
+	"--save-report":          {},
+	"--snapshot":             {},
+	"--service-id":           {},
+	"--compare":              {},
+	"--max-hit-ratio-drop":   {},
+	"--max-backend-increase": {},
 }
 
 func parseCommands(args []string) Commands {
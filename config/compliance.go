@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+)
+
+const defaultComplianceMinTLSVersion = "1.2"
+
+var defaultSensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Compliance rule pack configuration.
+// Compliance rules are opt-in, all rules are disabled unless Enable is true.
+type ComplianceConfig struct {
+	Enable           bool     `cli:"compliance" yaml:"enable"`
+	MinTLSVersion    string   `yaml:"min_tls_version"`
+	SensitiveHeaders []string `yaml:"sensitive_headers"`
+}
+
+// IsEnabled returns true when compliance rule pack is enabled
+func (c *ComplianceConfig) IsEnabled() bool {
+	return c != nil && c.Enable
+}
+
+// TLSFloor returns minimum TLS version which backends must specify
+func (c *ComplianceConfig) TLSFloor() string {
+	if c == nil || c.MinTLSVersion == "" {
+		return defaultComplianceMinTLSVersion
+	}
+	return c.MinTLSVersion
+}
+
+// IsSensitiveHeader returns true when the header value must not be logged
+func (c *ComplianceConfig) IsSensitiveHeader(name string) bool {
+	headers := defaultSensitiveHeaders
+	if c != nil && len(c.SensitiveHeaders) > 0 {
+		headers = c.SensitiveHeaders
+	}
+	for i := range headers {
+		if strings.EqualFold(headers[i], name) {
+			return true
+		}
+	}
+	return false
+}
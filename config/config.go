@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -9,7 +10,7 @@ import (
 )
 
 var (
-	configurationFiles = []string{".falco.yaml", ".falco.yml"}
+	configurationFiles = []string{".falco.yaml", ".falco.yml", ".falco.json"}
 )
 
 type OverrideBackend struct {
@@ -24,13 +25,117 @@ type LinterConfig struct {
 	VerboseWarning bool              `cli:"v"`
 	VerboseInfo    bool              `cli:"vv"`
 	Rules          map[string]string `yaml:"rules"`
+	Compliance     *ComplianceConfig `yaml:"compliance"`
+	Naming         *NamingConfig     `yaml:"naming"`
+	Complexity     *ComplexityConfig `yaml:"complexity"`
+	Policies       []*PolicyConfig   `yaml:"policies"`
+	Opa            *OpaConfig        `yaml:"opa"`
+	// Additional protected headers which must not be modified, or Fastly protected headers which are allowed to be modified
+	ProtectedHeaders *ProtectedHeaderConfig `yaml:"protected_headers"`
+	// Hostnames which are served by Compute services, accepts wildcard like "*.example.com"
+	ComputeHosts []string `yaml:"compute_hosts"`
+	// Annotations which are consumed by other tools like transformer plugins, they are not reported as unknown
+	Annotations []string `yaml:"annotations"`
+	// Run only rules which belong to the categories, or skip them
+	OnlyCategories []string `cli:"only" yaml:"only"`
+	SkipCategories []string `cli:"skip" yaml:"skip"`
+	// Apply suggested fixes of lint errors to the source files
+	Fix bool `cli:"fix"`
+	// Baseline file of existing lint errors, errors in the baseline are not reported
+	Baseline       string `cli:"baseline" yaml:"baseline"`
+	UpdateBaseline bool   `cli:"update-baseline"`
+	// Report only lint errors on changed lines of the unified diff file, or of git diff from the revision
+	Diff     string `cli:"diff"`
+	DiffBase string `cli:"diff-base" yaml:"diff_base"`
+	// Number of entry VCLs which are linted concurrently, GOMAXPROCS is used when zero
+	Parallel int `cli:"parallel" yaml:"parallel"`
+	// Test case unit of JUnit XML output, file or rule
+	JUnitGroup string `cli:"junit-group" yaml:"junit_group"`
+	// Report partial results and context of the fatal error instead of aborting
+	ExplainFatal bool `cli:"explain-fatal" yaml:"explain_fatal"`
+	// Stop linting at the first error, intended for quick checks like pre-commit hooks
+	FailFast bool `cli:"fail-fast" yaml:"fail_fast"`
+	// Report time spent for each rule
+	ProfileRules bool `cli:"profile-rules"`
+	// Thresholds of table items and key length
+	TableLimits *TableLimitsConfig `yaml:"table_limits"`
+	// Thresholds of estimated VCL size, subroutine count and workspace usage
+	CompilationLimits *CompilationLimitsConfig `yaml:"compilation_limits"`
+}
+
+// Thresholds of table limitations for inline tables and edge dictionaries which are fetched from remote.
+// Fastly can increase the limitations by contacting to support, so they are configurable.
+type TableLimitsConfig struct {
+	Table          *TableLimit `yaml:"table"`
+	EdgeDictionary *TableLimit `yaml:"edge_dictionary"`
+}
+
+type TableLimit struct {
+	MaxItems       int `yaml:"max_items" default:"1000"`
+	MaxKeyLength   int `yaml:"max_key_length" default:"256"`
+	MaxValueLength int `yaml:"max_value_length" default:"8000"`
+}
+
+// Thresholds of Fastly compilation limits which are estimated after resolving includes and snippets.
+// Actual usage depends on the compiled code, so the linter warns when the service is likely to hit them.
+type CompilationLimitsConfig struct {
+	MaxVCLSize     int `yaml:"max_vcl_size" default:"1048576"`
+	MaxSubroutines int `yaml:"max_subroutines" default:"1000"`
+	MaxWorkspace   int `yaml:"max_workspace" default:"131072"`
+}
+
+// Thresholds of subroutine complexity, the linter warns when the subroutine exceeds them
+type ComplexityConfig struct {
+	MaxCyclomatic int `yaml:"max_cyclomatic" default:"20"`
+	MaxNesting    int `yaml:"max_nesting" default:"5"`
+}
+
+// Shadow backend configuration for the simulator
+type ShadowConfig struct {
+	Backend       string   `cli:"shadow" yaml:"backend"`
+	Paths         []string `yaml:"paths"`
+	IgnoreHeaders []string `yaml:"ignore_headers"`
+	// Report the first different bytes of response bodies in addition to their sizes and digests
+	BodyDiff bool `yaml:"body_diff"`
+}
+
+// Data files which are applied to tables and ACLs of the running simulator without restart,
+// like Fastly edge dictionaries and ACLs which are updated without version bump
+type DataFilesConfig struct {
+	// Map of table name and JSON object file path
+	Tables map[string]string `yaml:"tables"`
+	// Map of ACL name and JSON array file path
+	Acls map[string]string `yaml:"acls"`
+}
+
+// Service which is simulated side by side with other services in one simulator process
+type ServiceConfig struct {
+	Name         string   `yaml:"name"`
+	Main         string   `yaml:"main"`
+	IncludePaths []string `yaml:"include_paths"`
+	// Listen port, the service is served on the simulator port when zero
+	Port int `yaml:"port"`
+	// Hostnames to route requests to the service when multiple services share the port
+	Hosts []string `yaml:"hosts"`
+	// Backend name to another service name, the backend request is processed by the service in-process
+	Backends map[string]string `yaml:"backends"`
 }
 
 // Simulator configuration
 type SimulatorConfig struct {
-	Port         int      `cli:"p,port" yaml:"port" default:"3124"`
-	IsDebug      bool     `cli:"debug"` // Enable only in CLI option
-	IncludePaths []string // Copy from root field
+	Port              int              `cli:"p,port" yaml:"port" default:"3124"`
+	IsDebug           bool             `cli:"debug"` // Enable only in CLI option
+	IncludePaths      []string         // Copy from root field
+	Shadow            *ShadowConfig    `yaml:"shadow"`
+	CheckDebugHeaders bool             `cli:"check_debug_headers" yaml:"check_debug_headers"`
+	DebugResponse     bool             `cli:"debug_response" yaml:"debug_response"`
+	Dashboard         bool             `cli:"dashboard" yaml:"dashboard"`
+	TimeTravel        bool             `cli:"time_travel" yaml:"time_travel"`
+	ExplainCache      bool             `cli:"explain-cache" yaml:"explain_cache"`
+	DataFiles         *DataFilesConfig `yaml:"data_files"`
+	Services          []*ServiceConfig `yaml:"services"`
+	// Map of hostname and addresses which pin DNS resolution of backend hosts
+	DNS map[string][]string `yaml:"dns"`
 
 	// Override Request configuration
 	OverrideRequest *RequestConfig
@@ -42,11 +147,81 @@ type TestConfig struct {
 	Filter       string   `cli:"f,filter" default:"*.test.vcl"`
 	IncludePaths []string // Copy from root field
 	OverrideHost string   `yaml:"host"`
+	ExplainCache bool     `cli:"explain-cache" yaml:"explain_cache"`
+	// Number of test files which are run concurrently, GOMAXPROCS is used when zero
+	Parallel int `cli:"p,parallel" yaml:"parallel"`
+	// Number of retries for failed test subroutine, test which passes after retries is reported as flaky
+	Retries int `cli:"retries" yaml:"retries"`
+	// Suite name patterns of quarantined tests, failures of quarantined tests do not fail the suite
+	Quarantine []string `cli:"quarantine" yaml:"quarantine"`
 
 	// Override Request configuration
 	OverrideRequest *RequestConfig
 }
 
+// Generator configuration
+type GenerateConfig struct {
+	Output        string `cli:"o,output" yaml:"output" default:"."`
+	DevicesSource string `cli:"source" yaml:"devices_source"`
+	TableLimit    int    `cli:"table_limit" yaml:"table_limit" default:"1000"`
+	FromURL       string `cli:"from-url"`
+}
+
+// Remote resource sync configuration
+type SyncConfig struct {
+	From string `cli:"from"`
+}
+
+// Import command configuration
+type ImportConfig struct {
+	// Service ID to import, FASTLY_SERVICE_ID environment variable is used when empty
+	ServiceID string `cli:"service-id"`
+	Output    string `cli:"o,output" default:"."`
+	// Overwrite existing files in the output directory
+	Force bool `cli:"force"`
+}
+
+// Bundle command configuration
+type BundleConfig struct {
+	// Lint before bundling, diagnostics are written to stderr as JSON lines
+	Lint   bool   `cli:"lint"`
+	Stdout bool   `cli:"stdout"`
+	Output string `cli:"o,output"`
+}
+
+// Load command configuration
+type LoadConfig struct {
+	// Requests per second which are sent to the simulator
+	RPS int `cli:"rps" default:"100"`
+	// Seconds to generate the load
+	Duration int `cli:"duration" default:"10"`
+	// Traffic profile file
+	Profile string `cli:"profile"`
+	// Write the report to the file in order to store it as the baseline artifact
+	SaveReport string `cli:"save-report"`
+	// Baseline report file to compare, the command fails when metrics regress beyond thresholds
+	Compare string `cli:"compare"`
+	// Percentage points which cache hit ratio may drop from the baseline
+	MaxHitRatioDrop float64 `cli:"max-hit-ratio-drop" default:"5"`
+	// Percent which backend fetches per request may increase from the baseline
+	MaxBackendIncrease float64 `cli:"max-backend-increase" default:"10"`
+}
+
+// Plugin configuration, plugins are installed by plugin command
+type PluginConfig struct {
+	// Directory where plugins are installed, ~/.falco/plugins is used when empty
+	Path string `yaml:"path" env:"FALCO_PLUGIN_PATH"`
+	// URL or file path of the registry index
+	Registry string `yaml:"registry" env:"FALCO_PLUGIN_REGISTRY"`
+	// Base64 encoded ed25519 public keys, plugins must be signed by one of them when specified
+	TrustedKeys []string `yaml:"trusted_keys"`
+}
+
+// Simulator cache command configuration
+type CacheConfig struct {
+	All bool `cli:"all"`
+}
+
 type Config struct {
 	// Root configurations
 	IncludePaths []string `cli:"I,include_path" yaml:"include_paths"`
@@ -55,7 +230,13 @@ type Config struct {
 	Version      bool     `cli:"V"`
 	Remote       bool     `cli:"r,remote" yaml:"remote"`
 	Json         bool     `cli:"json"`
+	Format       string   `cli:"format"`
 	Request      string   `cli:"request"`
+	DryRun       bool     `cli:"dry-run"`
+	// Language of diagnostic messages, English is used when empty
+	Language string `cli:"lang" yaml:"language" env:"FALCO_LANG"`
+	// Snapshot file of remote resources which is written by import command, used when remote option is not provided
+	Snapshot string `cli:"snapshot" yaml:"snapshot"`
 
 	// Remote options, only provided via environment variable
 	FastlyServiceID string `env:"FASTLY_SERVICE_ID"`
@@ -67,6 +248,9 @@ type Config struct {
 	// Override Origin fetching URL
 	OverrideBackends map[string]*OverrideBackend `yaml:"override_backends"`
 
+	// Debug header leakage settings, used in both linter and simulator
+	DebugHeader *DebugHeaderConfig `yaml:"debug_header"`
+
 	// Override resource limits
 	OverrideMaxBackends int `cli:"max_backends" yaml:"max_backends"`
 	OverrideMaxAcls     int `cli:"mac_acls" yaml:"max_acls"`
@@ -77,11 +261,25 @@ type Config struct {
 	Simulator *SimulatorConfig `yaml:"simulator"`
 	// Testing configuration
 	Testing *TestConfig `yaml:"testing"`
+	// Generator configuration
+	Generate *GenerateConfig `yaml:"generate"`
+	// Sync configuration
+	Sync *SyncConfig
+	// Simulator cache command configuration
+	Cache *CacheConfig
+	// Plugin configuration
+	Plugin *PluginConfig `yaml:"plugin"`
+	// Bundle command configuration
+	Bundle *BundleConfig
+	// Import command configuration
+	Import *ImportConfig
+	// Load command configuration
+	Load *LoadConfig
 }
 
 func New(args []string) (*Config, error) {
 	var options []twist.Option
-	if file, err := findConfigFile(); err != nil {
+	if file, err := FindConfigFile(); err != nil {
 		return nil, errors.WithStack(err)
 	} else if file != "" {
 		options = append(options, twist.WithYaml(file))
@@ -104,6 +302,25 @@ func New(args []string) (*Config, error) {
 	}
 	c.Commands = parseCommands(args)
 
+	// --format json is equivalent to -json option
+	switch c.Format {
+	case "json":
+		c.Json = true
+	case "", "text":
+		break
+	case "csv", "html":
+		// Decision table formats which only routes command supports
+		if c.Commands.At(0) != "routes" {
+			return nil, errors.WithStack(fmt.Errorf("Output format %s is only supported by routes command", c.Format))
+		}
+	case "junit":
+		if cmd := c.Commands.At(0); cmd != "lint" && cmd != "test" {
+			return nil, errors.WithStack(fmt.Errorf("Output format %s is only supported by lint and test command", c.Format))
+		}
+	default:
+		return nil, errors.WithStack(fmt.Errorf("Unsupported output format %s, must be one of text, json, csv, html or junit", c.Format))
+	}
+
 	// Merge verbose level
 	switch c.Linter.VerboseLevel {
 	case "warning":
@@ -127,7 +344,8 @@ func New(args []string) (*Config, error) {
 	return c, nil
 }
 
-func findConfigFile() (string, error) {
+// FindConfigFile finds up the configuration file from the current directory, returns empty string when not found
+func FindConfigFile() (string, error) {
 	// find up configuration file
 	cwd, err := os.Getwd()
 	if err != nil {
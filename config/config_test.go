@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -20,6 +22,20 @@ func TestParseCommand(t *testing.T) {
 	if diff := cmp.Diff(c, Commands{"foo"}); diff != "" {
 		t.Errorf("Unmatch parsed commands, diff=%s", diff)
 	}
+
+	t.Run("diff options with separated values", func(t *testing.T) {
+		c := parseCommands([]string{"lint", "--diff-base", "origin/main", "--diff", "changes.patch", "main.vcl"})
+		if diff := cmp.Diff(c, Commands{"lint", "main.vcl"}); diff != "" {
+			t.Errorf("Unmatch parsed commands, diff=%s", diff)
+		}
+	})
+
+	t.Run("snapshot options with separated values", func(t *testing.T) {
+		c := parseCommands([]string{"simulate", "--snapshot", "snap.json", "--service-id", "abc123", "main.vcl"})
+		if diff := cmp.Diff(c, Commands{"simulate", "main.vcl"}); diff != "" {
+			t.Errorf("Unmatch parsed commands, diff=%s", diff)
+		}
+	})
 }
 
 func TestConfigFromCLI(t *testing.T) {
@@ -49,13 +65,28 @@ func TestConfigFromCLI(t *testing.T) {
 		Json:     true,
 		Commands: Commands{"lint"},
 		Linter: &LinterConfig{
-			VerboseLevel:   "",
-			VerboseWarning: true,
-			VerboseInfo:    true,
+			VerboseLevel:     "",
+			VerboseWarning:   true,
+			VerboseInfo:      true,
+			Compliance:       &ComplianceConfig{},
+			Naming:           &NamingConfig{},
+			Complexity:       &ComplexityConfig{MaxCyclomatic: 20, MaxNesting: 5},
+			ProtectedHeaders: &ProtectedHeaderConfig{},
+			TableLimits: &TableLimitsConfig{
+				Table:          &TableLimit{MaxItems: 1000, MaxKeyLength: 256, MaxValueLength: 8000},
+				EdgeDictionary: &TableLimit{MaxItems: 1000, MaxKeyLength: 256, MaxValueLength: 8000},
+			},
+			CompilationLimits: &CompilationLimitsConfig{MaxVCLSize: 1048576, MaxSubroutines: 1000, MaxWorkspace: 131072},
+			Opa: &OpaConfig{
+				Query:   "data.falco.deny",
+				Command: "opa",
+			},
 		},
 		Simulator: &SimulatorConfig{
 			Port:            3124,
 			IncludePaths:    []string{"."},
+			Shadow:          &ShadowConfig{},
+			DataFiles:       &DataFilesConfig{},
 			OverrideRequest: &RequestConfig{},
 		},
 		Testing: &TestConfig{
@@ -63,7 +94,18 @@ func TestConfigFromCLI(t *testing.T) {
 			IncludePaths:    []string{"."},
 			OverrideRequest: &RequestConfig{},
 		},
+		Generate: &GenerateConfig{
+			Output:     ".",
+			TableLimit: 1000,
+		},
+		Sync:             &SyncConfig{},
+		Cache:            &CacheConfig{},
+		Plugin:           &PluginConfig{},
+		Bundle:           &BundleConfig{},
+		Import:           &ImportConfig{Output: "."},
+		Load:             &LoadConfig{RPS: 100, Duration: 10, MaxHitRatioDrop: 5, MaxBackendIncrease: 10},
 		OverrideBackends: make(map[string]*OverrideBackend),
+		DebugHeader:      &DebugHeaderConfig{},
 	}
 
 	if diff := cmp.Diff(c, expect, cmpopts.IgnoreFields(Config{}, "FastlyServiceID", "FastlyApiKey")); diff != "" {
@@ -86,3 +128,88 @@ func TestConfigFromEnv(t *testing.T) {
 		t.Errorf("Unmatch FastlyApiKey field, expect=%s, got=%s", "example_api_key", c.FastlyApiKey)
 	}
 }
+
+func TestConfigOutputFormat(t *testing.T) {
+	c, err := New([]string{"--format", "json", "doctor"})
+	if err != nil {
+		t.Errorf("Failed to initialize config: %s", err)
+		return
+	}
+	if !c.Json {
+		t.Errorf("Json field must be true with --format json")
+	}
+	if c.Commands.At(0) != "doctor" {
+		t.Errorf("Format value must not be parsed as command, got=%v", c.Commands)
+	}
+
+	if _, err := New([]string{"--format", "yaml", "doctor"}); err == nil {
+		t.Errorf("Expects error for unsupported format")
+	} else if !strings.Contains(err.Error(), "text, json, csv, html or junit") {
+		t.Errorf("Error must list all supported formats, got=%s", err)
+	}
+
+	// Decision table formats are only supported by routes command
+	if _, err := New([]string{"--format", "csv", "routes", "main.vcl"}); err != nil {
+		t.Errorf("Unexpected error for csv format of routes command: %s", err)
+	}
+	if _, err := New([]string{"--format", "html", "doctor"}); err == nil {
+		t.Errorf("Expects error for html format of doctor command")
+	}
+
+	// JUnit XML format is only supported by lint and test command
+	c, err = New([]string{"--format", "junit", "--junit-group", "rule", "lint", "main.vcl"})
+	if err != nil {
+		t.Errorf("Unexpected error for junit format of lint command: %s", err)
+	} else if c.Linter.JUnitGroup != "rule" {
+		t.Errorf("JUnitGroup field must be rule, got=%s", c.Linter.JUnitGroup)
+	}
+	if _, err := New([]string{"--format", "junit", "routes", "main.vcl"}); err == nil {
+		t.Errorf("Expects error for junit format of routes command")
+	}
+	c, err = New([]string{"--format", "junit", "--retries", "2", "--quarantine", "flaky *", "test", "main.vcl"})
+	if err != nil {
+		t.Errorf("Unexpected error for junit format of test command: %s", err)
+	} else {
+		if c.Testing.Retries != 2 {
+			t.Errorf("Retries field must be 2, got=%d", c.Testing.Retries)
+		}
+		if diff := cmp.Diff([]string{"flaky *"}, c.Testing.Quarantine); diff != "" {
+			t.Errorf("Quarantine field mismatch, diff=%s", diff)
+		}
+		if diff := cmp.Diff(Commands{"test", "main.vcl"}, c.Commands); diff != "" {
+			t.Errorf("Commands mismatch, diff=%s", diff)
+		}
+	}
+}
+
+func TestConfigFromJSONFile(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"linter": {"rules": {"unused/variable": "ERROR", "condition/literal": "ignore"}}}`
+	if err := os.WriteFile(filepath.Join(dir, ".falco.json"), []byte(content), 0o644); err != nil {
+		t.Errorf("Failed to write config file: %s", err)
+		return
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Errorf("Failed to get working directory: %s", err)
+		return
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Errorf("Failed to change directory: %s", err)
+		return
+	}
+	defer os.Chdir(cwd) // nolint:errcheck
+
+	c, err := New([]string{})
+	if err != nil {
+		t.Errorf("Failed to initialize config: %s", err)
+		return
+	}
+	expect := map[string]string{
+		"unused/variable":   "ERROR",
+		"condition/literal": "ignore",
+	}
+	if diff := cmp.Diff(expect, c.Linter.Rules); diff != "" {
+		t.Errorf("Unmatch linter rules, diff=%s", diff)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+var (
+	defaultDebugHeaderPatterns = []string{"X-Debug-*", "X-Cache-Key"}
+	defaultDebugAllowedHeaders = []string{"Fastly-Debug"}
+)
+
+// Debug header leakage configuration.
+// Patterns accept glob pattern and all header names are compared case-insensitively.
+type DebugHeaderConfig struct {
+	Patterns       []string `yaml:"patterns"`
+	AllowedHeaders []string `yaml:"allowed_headers"`
+	AllowedAcls    []string `yaml:"allowed_acls"`
+}
+
+// IsDebugHeader returns true when the header name is treated as debug header
+func (c *DebugHeaderConfig) IsDebugHeader(name string) bool {
+	patterns := defaultDebugHeaderPatterns
+	if c != nil && len(c.Patterns) > 0 {
+		patterns = c.Patterns
+	}
+	return matchHeaderPatterns(patterns, name)
+}
+
+// IsAllowedHeader returns true when the request header name gates debug output
+func (c *DebugHeaderConfig) IsAllowedHeader(name string) bool {
+	headers := defaultDebugAllowedHeaders
+	if c != nil && len(c.AllowedHeaders) > 0 {
+		headers = c.AllowedHeaders
+	}
+	return matchHeaderPatterns(headers, name)
+}
+
+// IsAllowedAcl returns true when the ACL name gates debug output
+func (c *DebugHeaderConfig) IsAllowedAcl(name string) bool {
+	if c == nil {
+		return false
+	}
+	for i := range c.AllowedAcls {
+		if c.AllowedAcls[i] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func matchHeaderPatterns(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for i := range patterns {
+		if ok, _ := path.Match(strings.ToLower(patterns[i]), name); ok { // nolint:errcheck
+			return true
+		}
+	}
+	return false
+}
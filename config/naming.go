@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Naming convention configuration.
+// Each field is a regular expression which the declared name must match, empty pattern means no restriction.
+type NamingConfig struct {
+	Subroutine string `yaml:"subroutine"`
+	Backend    string `yaml:"backend"`
+	Director   string `yaml:"director"`
+	Acl        string `yaml:"acl"`
+	Table      string `yaml:"table"`
+	// Pattern for local variable name without "var." prefix
+	Variable string `yaml:"variable"`
+}
+
+// Patterns returns compiled patterns keyed by declaration type
+func (c *NamingConfig) Patterns() (map[string]*regexp.Regexp, error) {
+	patterns := make(map[string]*regexp.Regexp)
+	if c == nil {
+		return patterns, nil
+	}
+
+	for kind, pattern := range map[string]string{
+		"subroutine": c.Subroutine,
+		"backend":    c.Backend,
+		"director":   c.Director,
+		"acl":        c.Acl,
+		"table":      c.Table,
+		"variable":   c.Variable,
+	} {
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid naming convention pattern for %s: %w", kind, err)
+		}
+		patterns[kind] = re
+	}
+	return patterns, nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestNamingConfigPatterns(t *testing.T) {
+	var nilConfig *NamingConfig
+	if patterns, err := nilConfig.Patterns(); err != nil || len(patterns) != 0 {
+		t.Errorf("Nil config should return empty patterns, got %v, %v", patterns, err)
+	}
+
+	patterns, err := (&NamingConfig{Backend: "^be_", Table: "^t_"}).Patterns()
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if len(patterns) != 2 || !patterns["backend"].MatchString("be_origin") {
+		t.Errorf("Unexpected patterns: %v", patterns)
+	}
+
+	if _, err := (&NamingConfig{Acl: "^acl_("}).Patterns(); err == nil {
+		t.Errorf("Expected error for invalid pattern but got nil")
+	}
+}
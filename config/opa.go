@@ -0,0 +1,20 @@
+package config
+
+// OPA policy evaluation configuration.
+// Collected facts about VCL are passed to "opa eval" command as input,
+// and violations which are returned from the query are reported as lint errors.
+type OpaConfig struct {
+	// Rego policy files or directories
+	Policies []string `cli:"rego" yaml:"policies"`
+	// Query to evaluate, result must be a set or an array of violations
+	Query string `yaml:"query" default:"data.falco.deny"`
+	// Path or name of opa command
+	Command string `yaml:"command" default:"opa"`
+	// File path to export facts as JSON
+	Facts string `cli:"facts" yaml:"facts"`
+}
+
+// IsEnabled returns true when Rego policies are provided
+func (c *OpaConfig) IsEnabled() bool {
+	return c != nil && len(c.Policies) > 0
+}
@@ -0,0 +1,25 @@
+package config
+
+// Statement policy configuration.
+// Policy prohibits statements, function calls and variable modifications which match the policy
+// in the specified phases, or outside of the allowed phases. Policy could be scoped by file glob patterns.
+type PolicyConfig struct {
+	Name    string `yaml:"name"`
+	Message string `yaml:"message"`
+	// Severity of policy violation, default is error
+	Severity string `yaml:"severity"`
+
+	// Prohibited statement keywords like "restart", "synthetic"
+	Statements []string `yaml:"statements"`
+	// Prohibited function names, accepts glob pattern like "digest.*"
+	Functions []string `yaml:"functions"`
+	// Variables which are prohibited to modify by set, unset, add and remove statements, accepts glob pattern
+	Variables []string `yaml:"variables"`
+
+	// Phases which policy is applied like "deliver". Policy is applied to all phases when both fields are empty
+	Phases []string `yaml:"phases"`
+	// Phases which policy is not applied, it means prohibited outside of these phases
+	ExceptPhases []string `yaml:"except_phases"`
+	// File glob patterns which policy is applied, policy is applied to all files when empty
+	Files []string `yaml:"files"`
+}
@@ -0,0 +1,11 @@
+package config
+
+// Protected header configuration.
+// Header names accept both variable name like "req.http.X-Internal-Auth" and bare header name like "X-Internal-Auth".
+// Bare header name is applied to all of req, bereq, beresp, resp and obj HTTP headers.
+type ProtectedHeaderConfig struct {
+	// Additional headers which must not be modified in addition to Fastly protected headers
+	Headers []string `yaml:"headers"`
+	// Fastly protected headers which are allowed to be modified
+	Allow []string `yaml:"allow"`
+}
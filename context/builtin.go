@@ -1893,11 +1893,13 @@ func predefinedVariables() Variables {
 				"area_code": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.IntegerType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-area-code/",
+						Get:         types.IntegerType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-area-code/",
+						Deprecated:  true,
+						Replacement: "client.geo.area_code",
 					},
 				},
 				"city": &Object{
@@ -1905,70 +1907,84 @@ func predefinedVariables() Variables {
 						"ascii": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city-ascii/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city-ascii/",
+								Deprecated:  true,
+								Replacement: "client.geo.city.ascii",
 							},
 						},
 						"latin1": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city-latin1/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city-latin1/",
+								Deprecated:  true,
+								Replacement: "client.geo.city.latin1",
 							},
 						},
 						"utf8": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city-utf8/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city-utf8/",
+								Deprecated:  true,
+								Replacement: "client.geo.city.utf8",
 							},
 						},
 					},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-city/",
+						Deprecated:  true,
+						Replacement: "client.geo.city",
 					},
 				},
 				"continent_code": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-continent-code/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-continent-code/",
+						Deprecated:  true,
+						Replacement: "client.geo.continent_code",
 					},
 				},
 				"country_code": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-code/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-code/",
+						Deprecated:  true,
+						Replacement: "client.geo.country_code",
 					},
 				},
 				"country_code3": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-code3/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-code3/",
+						Deprecated:  true,
+						Replacement: "client.geo.country_code3",
 					},
 				},
 				"country_name": &Object{
@@ -1976,90 +1992,108 @@ func predefinedVariables() Variables {
 						"ascii": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name-ascii/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name-ascii/",
+								Deprecated:  true,
+								Replacement: "client.geo.country_name.ascii",
 							},
 						},
 						"latin1": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name-latin1/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name-latin1/",
+								Deprecated:  true,
+								Replacement: "client.geo.country_name.latin1",
 							},
 						},
 						"utf8": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name-utf8/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name-utf8/",
+								Deprecated:  true,
+								Replacement: "client.geo.country_name.utf8",
 							},
 						},
 					},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-country-name/",
+						Deprecated:  true,
+						Replacement: "client.geo.country_name",
 					},
 				},
 				"ip_override": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.StringType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-ip-override/",
+						Get:         types.StringType,
+						Set:         types.StringType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-ip-override/",
+						Deprecated:  true,
+						Replacement: "client.geo.ip_override",
 					},
 				},
 				"latitude": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.FloatType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-latitude/",
+						Get:         types.FloatType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-latitude/",
+						Deprecated:  true,
+						Replacement: "client.geo.latitude",
 					},
 				},
 				"longitude": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.FloatType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-longitude/",
+						Get:         types.FloatType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-longitude/",
+						Deprecated:  true,
+						Replacement: "client.geo.longitude",
 					},
 				},
 				"metro_code": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.IntegerType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-metro-code/",
+						Get:         types.IntegerType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-metro-code/",
+						Deprecated:  true,
+						Replacement: "client.geo.metro_code",
 					},
 				},
 				"postal_code": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-postal-code/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-postal-code/",
+						Deprecated:  true,
+						Replacement: "client.geo.postal_code",
 					},
 				},
 				"region": &Object{
@@ -2067,50 +2101,59 @@ func predefinedVariables() Variables {
 						"ascii": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region-ascii/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region-ascii/",
+								Deprecated:  true,
+								Replacement: "client.geo.region.ascii",
 							},
 						},
 						"latin1": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region-latin1/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region-latin1/",
+								Deprecated:  true,
+								Replacement: "client.geo.region.latin1",
 							},
 						},
 						"utf8": &Object{
 							Items: map[string]*Object{},
 							Value: &Accessor{
-								Get:       types.StringType,
-								Set:       types.NeverType,
-								Unset:     false,
-								Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-								Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region-utf8/",
+								Get:         types.StringType,
+								Set:         types.NeverType,
+								Unset:       false,
+								Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+								Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region-utf8/",
+								Deprecated:  true,
+								Replacement: "client.geo.region.utf8",
 							},
 						},
 					},
 					Value: &Accessor{
-						Get:       types.StringType,
-						Set:       types.NeverType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region/",
+						Get:         types.StringType,
+						Set:         types.NeverType,
+						Unset:       false,
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-region/",
+						Deprecated:  true,
+						Replacement: "client.geo.region",
 					},
 				},
 				"use_x_forwarded_for": &Object{
 					Items: map[string]*Object{},
 					Value: &Accessor{
-						Get:       types.BoolType,
-						Set:       types.BoolType,
-						Unset:     false,
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-use-x-forwarded-for/",
+						Get:        types.BoolType,
+						Set:        types.BoolType,
+						Unset:      false,
+						Scopes:     RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:  "https://developer.fastly.com/reference/vcl/variables/geolocation/geoip-use-x-forwarded-for/",
+						Deprecated: true,
 					},
 				},
 			},
@@ -2528,7 +2571,7 @@ func predefinedVariables() Variables {
 						Get:       types.IntegerType,
 						Set:       types.NeverType,
 						Unset:     false,
-						Scopes:    HIT | DELIVER | LOG,
+						Scopes:    HIT | DELIVER | ERROR | LOG,
 						Reference: "https://developer.fastly.com/reference/vcl/variables/cache-object/obj-hits/",
 					},
 				},
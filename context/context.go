@@ -3,6 +3,7 @@ package context
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/resolver"
@@ -50,7 +51,7 @@ func ScopeString(s int) string {
 
 func ScopesString(s int) string {
 	var sb strings.Builder
-	for i := RECV; i != LOG; i <<= 4 {
+	for i := RECV; i <= LOG; i <<= 4 {
 		scope := ScopeString(s & i)
 		if scope != "UNKNOWN" {
 			sb.WriteString(scope)
@@ -60,6 +61,52 @@ func ScopesString(s int) string {
 	return sb.String()
 }
 
+// Legal return states of each scope, subroutine moves to the next state of the request lifecycle by the state.
+// https://developer.fastly.com/learning/vcl/using/#the-vcl-request-lifecycle
+var returnStates = map[int][]string{
+	RECV:    {"lookup", "pass", "error", "restart", "upgrade"},
+	HASH:    {"hash"},
+	HIT:     {"deliver", "pass", "error", "restart"},
+	MISS:    {"fetch", "deliver_stale", "pass", "error"},
+	PASS:    {"pass"},
+	FETCH:   {"deliver", "deliver_stale", "pass", "error", "restart"},
+	ERROR:   {"deliver", "deliver_stale", "restart"},
+	DELIVER: {"deliver", "restart"},
+	LOG:     {"deliver"},
+}
+
+// ReturnStates returns states which are legal in all scopes of the bitmap.
+// Subroutine could be called from multiple scopes, then only common states could be returned.
+func ReturnStates(scopes int) []string {
+	var states []string
+	var found bool
+	for i := RECV; i <= LOG; i <<= 4 {
+		if scopes&i == 0 {
+			continue
+		}
+		legal, ok := returnStates[i]
+		if !ok {
+			continue
+		}
+		if !found {
+			states = append(states, legal...)
+			found = true
+			continue
+		}
+		common := []string{}
+		for _, s := range states {
+			for _, l := range legal {
+				if s == l {
+					common = append(common, s)
+					break
+				}
+			}
+		}
+		states = common
+	}
+	return states
+}
+
 func CanAccessVariableInScope(objScope int, objReference, name string, currentScope int) error {
 	// objScope: is a bitmap of all the scopes that the variable is available in e.g. 0x100000001 is only available in RECV and LOG
 	// currentScope: is the bitmap of the current scope. In VCL state functions such as vcl_recv only one bit will be set.
@@ -107,6 +154,9 @@ type Accessor struct {
 	Unset     bool
 	Scopes    int
 	Reference string
+	// Deprecated variable may have the replacement variable
+	Deprecated  bool
+	Replacement string
 }
 
 type Context struct {
@@ -663,6 +713,90 @@ func (c *Context) Unset(name string) error {
 	return nil
 }
 
+// Deprecated returns the accessor of the variable when the variable is deprecated.
+// Unlike Get, this method does not mark the variable as used and does not care about the scope.
+func (c *Context) Deprecated(name string) (*Accessor, bool) {
+	first, remains := splitName(name)
+
+	obj, ok := c.Variables[first]
+	if !ok {
+		return nil, false
+	}
+	for _, key := range remains {
+		if obj, ok = obj.Items[key]; !ok {
+			return nil, false
+		}
+	}
+	if obj == nil || obj.Value == nil || !obj.Value.Deprecated {
+		return nil, false
+	}
+	return obj.Value, true
+}
+
+// Writable returns the accessor of the variable when the variable could be set in any scope.
+// Like Deprecated, this method does not mark the variable as used and does not care about the scope,
+// so the caller could know which scopes the variable is writable in.
+func (c *Context) Writable(name string) (*Accessor, bool) {
+	first, remains := splitName(name)
+
+	obj, ok := c.Variables[first]
+	if !ok || first == "re" {
+		return nil, false
+	}
+	for _, key := range remains {
+		v, ok := obj.Items[key]
+		if !ok {
+			// Variable of any key name is not defined until it is set
+			if v, ok = obj.Items["%any%"]; !ok {
+				return nil, false
+			}
+		}
+		obj = v
+	}
+	if obj == nil || obj.Value == nil || obj.Value.Set == types.NeverType {
+		return nil, false
+	}
+	return obj.Value, true
+}
+
+// Predefined names which user defined names should not collide with, keyed by the lower case name
+var (
+	predefinedNamesOnce sync.Once
+	predefinedNames     map[string]PredefinedName
+)
+
+// PredefinedName is a root of predefined variables like "req",
+// a builtin function namespace like "math" or a builtin function like "substr"
+type PredefinedName struct {
+	Name string
+	Kind string
+}
+
+// LookupPredefinedName finds the predefined name which the name collides with.
+// Names are compared case-insensitively because names which only differ in case are also confusing.
+func LookupPredefinedName(name string) (PredefinedName, bool) {
+	predefinedNamesOnce.Do(func() {
+		predefinedNames = make(map[string]PredefinedName)
+		for root, fn := range builtinFunctions() {
+			kind := "builtin function"
+			if fn.Value == nil {
+				kind = "builtin function namespace"
+			}
+			predefinedNames[strings.ToLower(root)] = PredefinedName{Name: root, Kind: kind}
+		}
+		for root := range predefinedVariables() {
+			// Namespace like "math" is also a root of variables, function namespace is more familiar
+			if _, ok := predefinedNames[strings.ToLower(root)]; ok {
+				continue
+			}
+			predefinedNames[strings.ToLower(root)] = PredefinedName{Name: root, Kind: "predefined variable"}
+		}
+	})
+
+	v, ok := predefinedNames[strings.ToLower(name)]
+	return v, ok
+}
+
 func (c *Context) GetFunction(name string) (*BuiltinFunction, error) {
 	first, remains := splitName(name)
 
@@ -3,6 +3,7 @@ package context
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/types"
 )
@@ -159,6 +160,34 @@ func TestContextGetSet(t *testing.T) {
 	})
 }
 
+func TestContextWritable(t *testing.T) {
+	c := New()
+	c.Scope(DELIVER)
+	tests := map[string]int{
+		"beresp.ttl":             FETCH,
+		"beresp.http.X-Foo":      FETCH,
+		"obj.status":             HIT | ERROR,
+		"backend.conn.is_tls":    0,
+		"re.group.1":             0,
+		"foo.bar":                0,
+		"req.http.Cookie:foobar": RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+	}
+	for name, expect := range tests {
+		accessor, ok := c.Writable(name)
+		if ok != (expect != 0) {
+			t.Errorf("%s: expect writable %t, got %t", name, expect != 0, ok)
+			continue
+		}
+		if ok && accessor.Scopes != expect {
+			t.Errorf("%s: expect %s, got %s", name, ScopesString(expect), ScopesString(accessor.Scopes))
+		}
+	}
+	// Lookup must not define the variable of %any% key
+	if _, ok := c.Variables["beresp"].Items["http"].Items["x-foo"]; ok {
+		t.Errorf("Writable must not define the variable")
+	}
+}
+
 func TestDynamicVariableExist(t *testing.T) {
 	t.Run("dynamic backend", func(t *testing.T) {
 		c := New()
@@ -208,3 +237,48 @@ func TestSplitName(t *testing.T) {
 		}
 	})
 }
+
+func TestReturnStates(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes int
+		expect []string
+	}{
+		{name: "single scope", scopes: HASH, expect: []string{"hash"}},
+		{name: "common states of multiple scopes", scopes: RECV | FETCH, expect: []string{"pass", "error", "restart"}},
+		{name: "no common state", scopes: HASH | LOG, expect: []string{}},
+		{name: "unknown scope", scopes: INIT, expect: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expect, ReturnStates(tt.scopes)); diff != "" {
+				t.Errorf("ReturnStates result mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestLookupPredefinedName(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect PredefinedName
+		found  bool
+	}{
+		{name: "req", expect: PredefinedName{Name: "req", Kind: "predefined variable"}, found: true},
+		{name: "Math", expect: PredefinedName{Name: "math", Kind: "builtin function namespace"}, found: true},
+		{name: "substr", expect: PredefinedName{Name: "substr", Kind: "builtin function"}, found: true},
+		{name: "custom_recv", found: false},
+	}
+
+	for _, tt := range tests {
+		actual, found := LookupPredefinedName(tt.name)
+		if found != tt.found {
+			t.Errorf("%s: found mismatch, expect=%t, got=%t", tt.name, tt.found, found)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: predefined name mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
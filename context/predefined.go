@@ -20,6 +20,9 @@ type BuiltinFunction struct {
 	Scopes                int
 	Reference             string
 	IsUserDefinedFunction bool
+	// Deprecated function may have the replacement function
+	Deprecated  bool
+	Replacement string
 }
 
 func builtinFunctions() Functions {
@@ -155,8 +158,10 @@ func builtinFunctions() Functions {
 						Arguments: [][]types.Type{
 							[]types.Type{types.StringType},
 						},
-						Scopes:    RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
-						Reference: "https://developer.fastly.com/reference/vcl/functions/query-string/boltsort-sort/",
+						Scopes:      RECV | HASH | HIT | MISS | PASS | FETCH | ERROR | DELIVER | LOG,
+						Reference:   "https://developer.fastly.com/reference/vcl/functions/query-string/boltsort-sort/",
+						Deprecated:  true,
+						Replacement: "querystring.sort",
 					},
 				},
 			},
@@ -0,0 +1,151 @@
+// Package crash provides the crash report of falco.
+// Entry points of the linter and interpreter wrap the panic with the AST node which was being processed,
+// and the command writes it as a crash bundle file so that CI could collect it as an artifact.
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Exit code when falco crashes, distinct from exit code 1 of lint or test failures
+const ExitCode = 3
+
+// Environment variable name of the directory which crash bundle is written to, temporary directory is used when empty
+const DirEnv = "FALCO_CRASH_DIR"
+
+// Count of source lines which are shown before and after the crashed node in the excerpt
+const excerptLines = 3
+
+// Panic wraps the recovered value with the stack trace and path of AST nodes which were being processed
+type Panic struct {
+	Value any
+	Stack []byte
+	Path  []ast.Node
+}
+
+// Wrap the recovered value with the path of AST nodes.
+// If the value has already been wrapped, returns it as it is because innermost path is the most accurate.
+func Wrap(v any, path ...ast.Node) *Panic {
+	if p, ok := v.(*Panic); ok {
+		return p
+	}
+	// Copy the path because caller may reuse the slice
+	nodes := make([]ast.Node, len(path))
+	copy(nodes, path)
+	return &Panic{
+		Value: v,
+		Stack: debug.Stack(),
+		Path:  nodes,
+	}
+}
+
+func (p *Panic) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Bundle is the crash report which is written as JSON file
+type Bundle struct {
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	Platform  string    `json:"platform"`
+	Command   []string  `json:"command"`
+	Time      time.Time `json:"time"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+	// AST node types and locations from outer to inner which were being processed
+	ASTPath []string `json:"ast_path"`
+	// Sanitized source code around the innermost node
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// NewBundle creates crash bundle from the recovered value
+func NewBundle(v any, version string, args []string) *Bundle {
+	p := Wrap(v)
+	b := &Bundle{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Command:   args,
+		Time:      time.Now(),
+		Panic:     fmt.Sprint(p.Value),
+		Stack:     string(p.Stack),
+		ASTPath:   []string{},
+	}
+	for _, node := range p.Path {
+		if node == nil {
+			continue
+		}
+		b.ASTPath = append(b.ASTPath, nodeLocation(node))
+	}
+	if len(p.Path) > 0 && p.Path[len(p.Path)-1] != nil {
+		b.Excerpt = Sanitize(excerpt(p.Path[len(p.Path)-1]))
+	}
+	return b
+}
+
+// Write the crash bundle to the directory and returns the file path
+func (b *Bundle) Write(dir string) (string, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	buf, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	file := filepath.Join(dir, fmt.Sprintf("falco-crash-%s.json", b.Time.Format("20060102-150405.000")))
+	if err := os.WriteFile(file, buf, 0o644); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return file, nil
+}
+
+func nodeLocation(node ast.Node) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+	tok := node.GetMeta().Token
+	file := tok.File
+	if file == "" {
+		file = "-"
+	}
+	return fmt.Sprintf("%s at %s:%d:%d", name, file, tok.Line, tok.Position)
+}
+
+// Get source lines around the node, or formatted node when the source file could not be read
+func excerpt(node ast.Node) string {
+	tok := node.GetMeta().Token
+	if tok.File != "" {
+		if buf, err := os.ReadFile(tok.File); err == nil {
+			lines := strings.Split(string(buf), "\n")
+			from := max(tok.Line-excerptLines, 1)
+			to := min(tok.Line+excerptLines, len(lines))
+			var out []string
+			for l := from; l <= to; l++ {
+				out = append(out, fmt.Sprintf("%d| %s", l, lines[l-1]))
+			}
+			return strings.Join(out, "\n")
+		}
+	}
+	return strings.TrimRight(node.String(), "\n")
+}
+
+var (
+	longStringLiteral = regexp.MustCompile(`\{"[\s\S]*?"\}`)
+	stringLiteral     = regexp.MustCompile(`"(?:[^"\\\n]|\\.)*"`)
+)
+
+// Sanitize masks string literals in the source which may contain secrets like API keys or credentials,
+// the structure of the code is kept in order to reproduce the crash.
+func Sanitize(src string) string {
+	src = longStringLiteral.ReplaceAllString(src, `{"***"}`)
+	return stringLiteral.ReplaceAllString(src, `"***"`)
+}
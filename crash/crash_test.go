@@ -0,0 +1,87 @@
+package crash
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestWrap(t *testing.T) {
+	inner := Wrap("inner", &ast.Ident{Meta: &ast.Meta{}, Value: "req.url"})
+	if outer := Wrap(inner); outer != inner {
+		t.Errorf("Wrap must keep the innermost panic")
+	}
+	if inner.Error() != "panic: inner" {
+		t.Errorf("Unexpected error message: %s", inner.Error())
+	}
+	if len(inner.Stack) == 0 {
+		t.Errorf("Stack trace must be recorded")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	input := `set req.http.Authorization = "Bearer \"secret\"";
+set req.http.Body = {"multi
+line"};`
+	expect := `set req.http.Authorization = "***";
+set req.http.Body = {"***"};`
+	if diff := cmp.Diff(expect, Sanitize(input)); diff != "" {
+		t.Errorf("Sanitize result mismatch, diff=%s", diff)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.vcl")
+	src := `sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Token = "secret";
+}`
+	if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %s", err)
+	}
+	vcl, err := parser.New(lexer.NewFromString(src, lexer.WithFile(file))).ParseVCL()
+	if err != nil {
+		t.Fatalf("Unexpected parser error: %s", err)
+	}
+	sub := vcl.Statements[0].(*ast.SubroutineDeclaration)
+
+	b := NewBundle(Wrap("boom", vcl, sub, sub.Block.Statements[0]), "v1.0.0", []string{"lint", "main.vcl"})
+	if b.Panic != "boom" || b.Version != "v1.0.0" {
+		t.Errorf("Unexpected bundle: %+v", b)
+	}
+	expectPath := []string{
+		"VCL at -:0:0",
+		"SubroutineDeclaration at " + file + ":1:1",
+		"SetStatement at " + file + ":3:3",
+	}
+	if diff := cmp.Diff(expectPath, b.ASTPath); diff != "" {
+		t.Errorf("AST path mismatch, diff=%s", diff)
+	}
+	if strings.Contains(b.Excerpt, "secret") || !strings.Contains(b.Excerpt, `3|   set req.http.Token = "***";`) {
+		t.Errorf("Unexpected excerpt: %s", b.Excerpt)
+	}
+
+	written, err := b.Write(dir)
+	if err != nil {
+		t.Fatalf("Failed to write bundle: %s", err)
+	}
+	buf, err := os.ReadFile(written)
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %s", err)
+	}
+	var decoded Bundle
+	if err := json.Unmarshal(buf, &decoded); err != nil {
+		t.Fatalf("Bundle must be JSON: %s", err)
+	}
+	if diff := cmp.Diff(b.ASTPath, decoded.ASTPath); diff != "" {
+		t.Errorf("Decoded bundle mismatch, diff=%s", diff)
+	}
+}
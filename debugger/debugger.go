@@ -1,10 +1,10 @@
 package debugger
 
 import (
-	"strings"
 	"time"
 
 	"github.com/rivo/tview"
+	"github.com/ysugimoto/falco/annotation"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/debugger/codeview"
 	"github.com/ysugimoto/falco/debugger/helpview"
@@ -14,7 +14,8 @@ import (
 	"github.com/ysugimoto/falco/token"
 )
 
-const debuggerMark = "@debugger"
+// Name of the annotation which marks the break point
+const debuggerAnnotation = "debugger"
 const highlightDeplay = 120
 
 type Debugger struct {
@@ -37,7 +38,8 @@ func (d *Debugger) Run(node ast.Node) interpreter.DebugState {
 		return d.breakPoint(node.GetMeta().Token)
 	default:
 		meta := node.GetMeta()
-		if !strings.Contains(meta.LeadingComment(), debuggerMark) {
+		annotations, _ := annotation.Parse(meta.Leading, annotation.AnyTarget)
+		if _, ok := annotations.Get(debuggerAnnotation); !ok {
 			return interpreter.DebugPass
 		}
 		return d.breakPoint(meta.Token)
@@ -0,0 +1,177 @@
+package timetravel
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+	"github.com/rivo/tview"
+	"github.com/ysugimoto/falco/debugger/codeview"
+	"github.com/ysugimoto/falco/debugger/colors"
+	"github.com/ysugimoto/falco/interpreter/process"
+)
+
+const helpText = " [Up/Down] Scrub | [Left/Right] Prev/Next change | [g/G] First/Last | [Esc/q] Quit"
+
+// Parse extracts time-travel log from the simulator process summary JSON
+func Parse(buf []byte) (*process.TimeTravel, error) {
+	var summary struct {
+		TimeTravel *process.TimeTravel `json:"time_travel"`
+	}
+	if err := json.Unmarshal(buf, &summary); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if summary.TimeTravel == nil || len(summary.TimeTravel.Steps) == 0 {
+		return nil, errors.New("Time-travel log is not found, run simulator with --time_travel option")
+	}
+	return summary.TimeTravel, nil
+}
+
+// Viewer is a terminal UI which scrubs backward and forward through the request evaluation
+type Viewer struct {
+	app       *tview.Application
+	log       *process.TimeTravel
+	code      *codeview.CodeView
+	steps     *tview.Table
+	changes   *tview.TextView
+	variables *tview.TextView
+}
+
+func New(log *process.TimeTravel) *Viewer {
+	code := codeview.New()
+	code.SetTitle(" VCL Time Travel ")
+
+	steps := tview.NewTable().SetSelectable(true, false)
+	steps.SetBackgroundColor(colors.Background)
+	steps.SetBorder(true).SetTitle(" Steps ")
+
+	changes := tview.NewTextView().SetDynamicColors(true)
+	changes.SetBackgroundColor(colors.Background)
+	changes.SetBorder(true).SetTitle(" Changes ")
+
+	variables := tview.NewTextView().SetDynamicColors(true)
+	variables.SetBackgroundColor(colors.Background)
+	variables.SetBorder(true).SetTitle(" Variables ")
+
+	help := tview.NewTextView().SetDynamicColors(true).SetText(colors.Bold("%s", tview.Escape(helpText)))
+	help.SetBackgroundColor(colors.Background)
+
+	// Keep the same bottom height as debugger console because codeview calculates its height from the screen
+	grid := tview.NewGrid().
+		SetRows(0, 16, 1).
+		SetColumns(0, 0, 0).
+		SetBorders(false)
+	grid.AddItem(code, 0, 0, 1, 3, 0, 0, false)
+	grid.AddItem(steps, 1, 0, 1, 1, 0, 0, true)
+	grid.AddItem(changes, 1, 1, 1, 1, 0, 0, false)
+	grid.AddItem(variables, 1, 2, 1, 1, 0, 0, false)
+	grid.AddItem(help, 2, 0, 1, 3, 0, 0, false)
+	grid.SetBackgroundColor(colors.Background)
+
+	v := &Viewer{
+		app:       tview.NewApplication().SetRoot(grid, true).SetFocus(steps),
+		log:       log,
+		code:      code,
+		steps:     steps,
+		changes:   changes,
+		variables: variables,
+	}
+
+	for i, step := range log.Steps {
+		cell := tview.NewTableCell(tview.Escape(fmt.Sprintf("%4d %-8s %s", i, step.Scope, step.Statement)))
+		if len(step.Changes) == 0 {
+			cell.SetTextColor(tcell.ColorGray)
+		}
+		steps.SetCell(i, 0, cell)
+	}
+	steps.SetSelectionChangedFunc(func(row, column int) {
+		v.show(row)
+	})
+	return v
+}
+
+func (v *Viewer) Run() error {
+	v.app.SetInputCapture(v.keyEventHandler)
+	v.show(0)
+	return v.app.Run()
+}
+
+func (v *Viewer) keyEventHandler(evt *tcell.EventKey) *tcell.EventKey {
+	row, _ := v.steps.GetSelection()
+
+	switch evt.Key() {
+	case tcell.KeyEscape:
+		v.app.Stop()
+	case tcell.KeyLeft:
+		v.steps.Select(v.findChangedStep(row, -1), 0)
+		return nil
+	case tcell.KeyRight:
+		v.steps.Select(v.findChangedStep(row, 1), 0)
+		return nil
+	case tcell.KeyRune:
+		if evt.Rune() == 'q' {
+			v.app.Stop()
+		}
+	}
+	return evt
+}
+
+// Find the nearest step which changes variables in the direction
+func (v *Viewer) findChangedStep(from, direction int) int {
+	for i := from + direction; i >= 0 && i < len(v.log.Steps); i += direction {
+		if len(v.log.Steps[i].Changes) > 0 {
+			return i
+		}
+	}
+	return from
+}
+
+func (v *Viewer) show(index int) {
+	if index < 0 || index >= len(v.log.Steps) {
+		return
+	}
+	step := v.log.Steps[index]
+	v.code.SetFile(step.File, step.Line)
+
+	changed := make(map[string]struct{})
+	v.changes.Clear()
+	fmt.Fprintf(v.changes, "%s\n", colors.Bold("%s", tview.Escape(step.Statement)))
+	if step.Subroutine != "" {
+		fmt.Fprintf(v.changes, "%s\n", colors.Gray("in %s (%s)", step.Subroutine, step.Scope))
+	}
+	fmt.Fprintln(v.changes)
+	if len(step.Changes) == 0 {
+		fmt.Fprintln(v.changes, colors.Gray("No changes"))
+	}
+	for _, c := range step.Changes {
+		changed[c.Name] = struct{}{}
+		fmt.Fprintln(v.changes, colors.Bold("%s", tview.Escape(c.Name)))
+		if c.Before != nil {
+			fmt.Fprintln(v.changes, colors.Red("  - %s", tview.Escape(*c.Before)))
+		}
+		if c.After != nil {
+			fmt.Fprintln(v.changes, colors.Green("  + %s", tview.Escape(*c.After)))
+		}
+	}
+	v.changes.ScrollToBeginning()
+
+	// Display variables after the step is executed, highlight changed ones
+	vars := v.log.Restore(index)
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	v.variables.Clear()
+	for _, name := range names {
+		line := tview.Escape(name + " = " + vars[name])
+		if _, ok := changed[name]; ok {
+			line = colors.Yellow("%s", line)
+		}
+		fmt.Fprintln(v.variables, line)
+	}
+	v.variables.ScrollToBeginning()
+}
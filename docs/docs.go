@@ -0,0 +1,11 @@
+// Package docs embeds documents which are shown by falco commands
+package docs
+
+import (
+	_ "embed"
+)
+
+// Rules is the content of rules.md which describes all linter rules
+//
+//go:embed rules.md
+var Rules string
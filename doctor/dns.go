@@ -0,0 +1,101 @@
+package doctor
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/types"
+)
+
+const defaultDNSTimeout = 5 * time.Second
+
+// DNSTarget is a backend host which must be resolved by DNS
+type DNSTarget struct {
+	// Backend name
+	Name string
+	Host string
+}
+
+// BackendDNSTargets returns DNS targets from backend declarations.
+// Backends which specify IP address as .host are not included because they do not need to be resolved.
+func BackendDNSTargets(backends map[string]*types.Backend) []*DNSTarget {
+	var targets []*DNSTarget
+	for name, b := range backends {
+		if b.BackendDecl == nil {
+			continue
+		}
+		props := make(map[string]ast.Expression)
+		for _, p := range b.BackendDecl.Properties {
+			props[p.Key.Value] = p.Value
+		}
+		host := stringProperty(props, "host")
+		if host == "" || net.ParseIP(host) != nil {
+			continue
+		}
+		targets = append(targets, &DNSTarget{Name: name, Host: host})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+	return targets
+}
+
+type DNSOption func(c *DNSChecker)
+
+// WithLookup uses the function to lookup hosts instead of the default resolver
+func WithLookup(lookup func(ctx context.Context, host string) ([]string, error)) DNSOption {
+	return func(c *DNSChecker) {
+		c.lookup = lookup
+	}
+}
+
+// DNSChecker verifies backend hosts could be resolved
+type DNSChecker struct {
+	lookup  func(ctx context.Context, host string) ([]string, error)
+	timeout time.Duration
+}
+
+func NewDNSChecker(opts ...DNSOption) *DNSChecker {
+	c := &DNSChecker{
+		lookup:  net.DefaultResolver.LookupHost,
+		timeout: defaultDNSTimeout,
+	}
+	for i := range opts {
+		opts[i](c)
+	}
+	return c
+}
+
+// Check resolves all targets concurrently, results are returned in the order of targets
+func (c *DNSChecker) Check(ctx context.Context, targets []*DNSTarget) []*Result {
+	results := make([]*Result, len(targets))
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.check(ctx, targets[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *DNSChecker) check(ctx context.Context, t *DNSTarget) *Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	addrs, err := c.lookup(ctx, t.Host)
+	if err != nil {
+		return fail(CategoryDNS, t.Name, "Failed to resolve %s: %s", t.Host, err)
+	}
+	if len(addrs) == 0 {
+		return fail(CategoryDNS, t.Name, "%s does not have any addresses", t.Host)
+	}
+	return pass(CategoryDNS, t.Name, "%s is resolved to %s", t.Host, strings.Join(addrs, ", "))
+}
@@ -0,0 +1,55 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/types"
+)
+
+func TestDNSChecker(t *testing.T) {
+	vcl, err := parser.New(lexer.NewFromString(`
+backend F_origin {
+  .host = "origin.example.com";
+}
+backend F_missing {
+  .host = "missing.example.com";
+}
+backend F_ip {
+  .host = "192.0.2.10";
+}`)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	backends := make(map[string]*types.Backend)
+	for _, stmt := range vcl.Statements {
+		if b, ok := stmt.(*ast.BackendDeclaration); ok {
+			backends[b.Name.Value] = &types.Backend{BackendDecl: b}
+		}
+	}
+
+	targets := BackendDNSTargets(backends)
+	if len(targets) != 2 {
+		t.Errorf("Expects 2 targets but got %d", len(targets))
+		return
+	}
+
+	checker := NewDNSChecker(WithLookup(func(ctx context.Context, host string) ([]string, error) {
+		if host == "origin.example.com" {
+			return []string{"192.0.2.1"}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}))
+	results := checker.Check(context.Background(), targets)
+	if results[0].Target != "F_missing" || results[0].Status != StatusFail {
+		t.Errorf("Unexpected result: %s", results[0])
+	}
+	if results[1].Target != "F_origin" || results[1].Status != StatusPass {
+		t.Errorf("Unexpected result: %s", results[1])
+	}
+}
@@ -0,0 +1,148 @@
+package doctor
+
+import (
+	"fmt"
+	"math"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Check categories
+const (
+	CategoryLint    = "lint"
+	CategoryInclude = "include"
+	CategoryDNS     = "dns"
+	CategoryTLS     = "tls"
+	CategoryLogging = "logging"
+	CategoryLimits  = "limits"
+)
+
+// Categories in the order of checks
+var Categories = []string{
+	CategoryLint,
+	CategoryInclude,
+	CategoryDNS,
+	CategoryTLS,
+	CategoryLogging,
+	CategoryLimits,
+}
+
+// Result is a result of single preflight check
+type Result struct {
+	Category string `json:"category"`
+	Target   string `json:"target"`
+	Status   Status `json:"status"`
+	Message  string `json:"message"`
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("[%s] %s %s: %s", r.Status, r.Category, r.Target, r.Message)
+}
+
+func pass(category, target, format string, args ...interface{}) *Result {
+	return &Result{Category: category, Target: target, Status: StatusPass, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(category, target, format string, args ...interface{}) *Result {
+	return &Result{Category: category, Target: target, Status: StatusWarn, Message: fmt.Sprintf(format, args...)}
+}
+
+func skip(category, target, format string, args ...interface{}) *Result {
+	return &Result{Category: category, Target: target, Status: StatusSkip, Message: fmt.Sprintf(format, args...)}
+}
+
+func fail(category, target, format string, args ...interface{}) *Result {
+	return &Result{Category: category, Target: target, Status: StatusFail, Message: fmt.Sprintf(format, args...)}
+}
+
+// Report is a collection of preflight check results
+type Report struct {
+	Results []*Result `json:"results"`
+}
+
+// Scorecard is a summary of results in the category
+type Scorecard struct {
+	Category string `json:"category"`
+	Pass     int    `json:"pass"`
+	Warn     int    `json:"warn"`
+	Fail     int    `json:"fail"`
+	Skip     int    `json:"skip"`
+}
+
+// Status returns the worst status in the category
+func (s *Scorecard) Status() Status {
+	switch {
+	case s.Fail > 0:
+		return StatusFail
+	case s.Warn > 0:
+		return StatusWarn
+	case s.Pass > 0:
+		return StatusPass
+	}
+	return StatusSkip
+}
+
+func (r *Report) Add(results ...*Result) {
+	r.Results = append(r.Results, results...)
+}
+
+// Count returns the number of results which have the status
+func (r *Report) Count(status Status) int {
+	var count int
+	for _, v := range r.Results {
+		if v.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns true when any of checks failed
+func (r *Report) Failed() bool {
+	return r.Count(StatusFail) > 0
+}
+
+// Scorecards returns summaries of categories which have any results
+func (r *Report) Scorecards() []*Scorecard {
+	var cards []*Scorecard
+	for _, category := range Categories {
+		card := &Scorecard{Category: category}
+		for _, v := range r.Results {
+			if v.Category != category {
+				continue
+			}
+			switch v.Status {
+			case StatusPass:
+				card.Pass++
+			case StatusWarn:
+				card.Warn++
+			case StatusFail:
+				card.Fail++
+			case StatusSkip:
+				card.Skip++
+			}
+		}
+		if card.Pass+card.Warn+card.Fail+card.Skip > 0 {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// Score returns the readiness percentage, warning counts as half and skipped checks are not counted.
+// Score is 100 when nothing is checked.
+func (r *Report) Score() int {
+	pass, warn, fail := r.Count(StatusPass), r.Count(StatusWarn), r.Count(StatusFail)
+	total := pass + warn + fail
+	if total == 0 {
+		return 100
+	}
+	return int(math.Floor((float64(pass) + float64(warn)*0.5) / float64(total) * 100))
+}
@@ -0,0 +1,87 @@
+package doctor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/linter"
+)
+
+func TestReportScore(t *testing.T) {
+	report := &Report{}
+	if v := report.Score(); v != 100 {
+		t.Errorf("Empty report score expects 100 but got %d", v)
+	}
+
+	report.Add(
+		pass(CategoryLint, "vcl", "ok"),
+		warn(CategoryDNS, "F_origin", "warn"),
+		pass(CategoryDNS, "F_backup", "ok"),
+		fail(CategoryTLS, "F_origin", "fail"),
+		skip(CategoryLogging, "endpoint", "skip"),
+	)
+	if v := report.Score(); v != 62 {
+		t.Errorf("Report score expects 62 but got %d", v)
+	}
+
+	expect := []*Scorecard{
+		{Category: CategoryLint, Pass: 1},
+		{Category: CategoryDNS, Pass: 1, Warn: 1},
+		{Category: CategoryTLS, Fail: 1},
+		{Category: CategoryLogging, Skip: 1},
+	}
+	cards := report.Scorecards()
+	if diff := cmp.Diff(expect, cards); diff != "" {
+		t.Errorf("Scorecards mismatch, diff=%s", diff)
+	}
+	if cards[1].Status() != StatusWarn || cards[3].Status() != StatusSkip {
+		t.Errorf("Unexpected scorecard status")
+	}
+}
+
+func TestLoggingResults(t *testing.T) {
+	logs := []*linter.LogFact{
+		{Endpoint: "access-log"},
+		{Endpoint: "access-log"},
+		{Endpoint: "missing-log"},
+		{Endpoint: "{req.http.Endpoint}"},
+	}
+
+	results := LoggingResults(logs, map[string]struct{}{"access-log": {}})
+	var statuses []Status
+	for _, r := range results {
+		statuses = append(statuses, r.Status)
+	}
+	if diff := cmp.Diff([]Status{StatusPass, StatusFail, StatusWarn}, statuses); diff != "" {
+		t.Errorf("Logging results mismatch, diff=%s", diff)
+	}
+
+	results = LoggingResults(logs[:1], nil)
+	if len(results) != 1 || results[0].Status != StatusSkip {
+		t.Errorf("Logging check should be skipped without endpoints: %v", results)
+	}
+}
+
+func TestLimitResults(t *testing.T) {
+	facts := &linter.Facts{
+		Backends: make([]*linter.BackendFact, 5),
+		Acls:     make([]*linter.AclFact, 1),
+		Tables: []*linter.TableFact{
+			{Name: "routes", Items: 1001},
+		},
+	}
+	files := map[string]int{"main.vcl": 900 * 1024}
+
+	var statuses []Status
+	for _, r := range LimitResults(facts, files, Limits{}) {
+		statuses = append(statuses, r.Status)
+	}
+	if diff := cmp.Diff([]Status{StatusWarn, StatusPass, StatusFail, StatusWarn}, statuses); diff != "" {
+		t.Errorf("Limit results mismatch, diff=%s", diff)
+	}
+
+	results := LimitResults(facts, nil, Limits{MaxBackends: 10})
+	if results[0].Status != StatusPass {
+		t.Errorf("Overridden backend limit should pass: %s", results[0])
+	}
+}
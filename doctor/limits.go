@@ -0,0 +1,59 @@
+package doctor
+
+import (
+	"sort"
+
+	"github.com/ysugimoto/falco/interpreter/limitations"
+	"github.com/ysugimoto/falco/linter"
+)
+
+const (
+	// Table items are limited to 1000 by default
+	maxTableItems = 1000
+	// Usage ratio which is reported as warning
+	limitWarningRatio = 0.8
+)
+
+// Limits is resource limits of the service, zero value uses Fastly defaults
+type Limits struct {
+	MaxBackends int
+	MaxAcls     int
+}
+
+// LimitResults estimates resource usages of the VCL against Fastly limits.
+// files is a map of VCL file name and its byte size.
+func LimitResults(facts *linter.Facts, files map[string]int, limits Limits) []*Result {
+	maxBackends := max(limits.MaxBackends, limitations.MaxBackendCounts)
+	maxAcls := max(limits.MaxAcls, limitations.MaxACLCounts)
+
+	results := []*Result{
+		limitResult("backends", "Backends", len(facts.Backends), maxBackends),
+		limitResult("acls", "ACLs", len(facts.Acls), maxAcls),
+	}
+	for _, t := range facts.Tables {
+		results = append(results, limitResult("table "+t.Name, "Table items", t.Items, maxTableItems))
+	}
+	for _, name := range sortedKeys(files) {
+		results = append(results, limitResult(name, "File size", files[name], limitations.MaxCustomVCLFileSize))
+	}
+	return results
+}
+
+func limitResult(target, resource string, usage, limit int) *Result {
+	switch {
+	case usage > limit:
+		return fail(CategoryLimits, target, "%s: %d exceeds the limit of %d", resource, usage, limit)
+	case float64(usage) > float64(limit)*limitWarningRatio:
+		return warn(CategoryLimits, target, "%s: %d is close to the limit of %d", resource, usage, limit)
+	}
+	return pass(CategoryLimits, target, "%s: %d of the limit %d", resource, usage, limit)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,36 @@
+package doctor
+
+import (
+	"github.com/ysugimoto/falco/linter"
+)
+
+// LintResult reports the linter result, any errors block the deploy
+func LintResult(errors, warnings int) *Result {
+	switch {
+	case errors > 0:
+		return fail(CategoryLint, "vcl", "Linter reported %d errors and %d warnings", errors, warnings)
+	case warnings > 0:
+		return warn(CategoryLint, "vcl", "Linter reported %d warnings", warnings)
+	}
+	return pass(CategoryLint, "vcl", "No lint errors")
+}
+
+// IncludeResults reports include statements which could not be resolved.
+// files is the number of VCL files which are loaded including the main VCL.
+func IncludeResults(files int, diagnostics []*linter.Diagnostic) []*Result {
+	var results []*Result
+	for _, d := range diagnostics {
+		switch d.Rule {
+		case linter.INCLUDE_STATEMENT_MODULE_NOT_FOUND, linter.INCLUDE_STATEMENT_MODULE_LOAD_FAILED:
+			target := d.Token.File
+			if target == "" {
+				target = "vcl"
+			}
+			results = append(results, fail(CategoryInclude, target, "%s at line %d", d.Message, d.Token.Line))
+		}
+	}
+	if len(results) > 0 {
+		return results
+	}
+	return []*Result{pass(CategoryInclude, "vcl", "All includes are resolved, %d files are loaded", files)}
+}
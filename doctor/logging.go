@@ -0,0 +1,37 @@
+package doctor
+
+import (
+	"strings"
+
+	"github.com/ysugimoto/falco/linter"
+)
+
+// LoggingResults validates logging endpoints which are referenced by log statements exist in the service.
+// endpoints is nil when falco could not fetch logging endpoints of the service, then checks are skipped.
+func LoggingResults(logs []*linter.LogFact, endpoints map[string]struct{}) []*Result {
+	var results []*Result
+	seen := make(map[string]struct{})
+	for _, l := range logs {
+		if _, ok := seen[l.Endpoint]; ok {
+			continue
+		}
+		seen[l.Endpoint] = struct{}{}
+
+		switch {
+		case strings.Contains(l.Endpoint, "{"):
+			results = append(results, warn(CategoryLogging, l.Endpoint,
+				"Endpoint name is determined at runtime in %s at line %d", l.File, l.Line))
+		case endpoints == nil:
+			results = append(results, skip(CategoryLogging, l.Endpoint,
+				"Logging endpoints are validated only with -r, --remote option"))
+		default:
+			if _, ok := endpoints[l.Endpoint]; ok {
+				results = append(results, pass(CategoryLogging, l.Endpoint, "Logging endpoint exists in the service"))
+			} else {
+				results = append(results, fail(CategoryLogging, l.Endpoint,
+					"Logging endpoint is not configured in the service but referenced in %s at line %d", l.File, l.Line))
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,236 @@
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/types"
+)
+
+const (
+	defaultTLSTimeout    = 5 * time.Second
+	defaultExpiryWarning = 30 * 24 * time.Hour
+)
+
+// TLSTarget is an endpoint which is verified by actual TLS handshake
+type TLSTarget struct {
+	// Backend name or domain name
+	Name string
+	Host string
+	Port int
+	// SNI hostname which is sent on handshake
+	ServerName string
+	// Hostname which the certificate must be valid for
+	CertHostname string
+	// Fastly does not verify the certificate when .ssl_check_cert is never
+	SkipVerify bool
+}
+
+func (t *TLSTarget) address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// BackendTLSTargets returns TLS targets from backend declarations which enable TLS.
+// Fastly sends .ssl_sni_hostname as SNI and verifies the certificate against .ssl_cert_hostname,
+// both fall back to .host when they are not specified.
+func BackendTLSTargets(backends map[string]*types.Backend) []*TLSTarget {
+	var targets []*TLSTarget
+	for name, b := range backends {
+		if b.BackendDecl == nil {
+			continue
+		}
+		props := make(map[string]ast.Expression)
+		for _, p := range b.BackendDecl.Properties {
+			props[p.Key.Value] = p.Value
+		}
+		if v, ok := props["ssl"].(*ast.Boolean); !ok || !v.Value {
+			continue
+		}
+		host := stringProperty(props, "host")
+		if host == "" {
+			continue
+		}
+
+		target := &TLSTarget{
+			Name:         name,
+			Host:         host,
+			Port:         443,
+			ServerName:   host,
+			CertHostname: host,
+			SkipVerify:   stringProperty(props, "ssl_check_cert") == "never",
+		}
+		if port, err := strconv.Atoi(stringProperty(props, "port")); err == nil {
+			target.Port = port
+		}
+		if v := stringProperty(props, "ssl_sni_hostname"); v != "" {
+			target.ServerName = v
+		}
+		if v := stringProperty(props, "ssl_cert_hostname"); v != "" {
+			target.CertHostname = v
+		}
+		targets = append(targets, target)
+	}
+	sortTargets(targets)
+	return targets
+}
+
+// DomainTLSTargets returns TLS targets for domains which are attached to the service
+func DomainTLSTargets(domains []string) []*TLSTarget {
+	var targets []*TLSTarget
+	for _, d := range domains {
+		// Wildcard domain could not be dialed
+		if strings.HasPrefix(d, "*") {
+			continue
+		}
+		targets = append(targets, &TLSTarget{
+			Name:         d,
+			Host:         d,
+			Port:         443,
+			ServerName:   d,
+			CertHostname: d,
+		})
+	}
+	sortTargets(targets)
+	return targets
+}
+
+func sortTargets(targets []*TLSTarget) {
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+}
+
+func stringProperty(props map[string]ast.Expression, key string) string {
+	switch v := props[key].(type) {
+	case *ast.String:
+		return v.Value
+	case *ast.Ident:
+		return v.Value
+	case *ast.Integer:
+		return strconv.FormatInt(v.Value, 10)
+	}
+	return ""
+}
+
+type TLSOption func(c *TLSChecker)
+
+// WithRoots uses the certificate pool to verify certificates instead of system roots
+func WithRoots(roots *x509.CertPool) TLSOption {
+	return func(c *TLSChecker) {
+		c.roots = roots
+	}
+}
+
+func WithNow(now func() time.Time) TLSOption {
+	return func(c *TLSChecker) {
+		c.now = now
+	}
+}
+
+func WithExpiryWarning(d time.Duration) TLSOption {
+	return func(c *TLSChecker) {
+		if d > 0 {
+			c.expiryWarning = d
+		}
+	}
+}
+
+// TLSChecker performs TLS handshake to targets and verifies the certificate hostname, chain and expiration
+type TLSChecker struct {
+	roots         *x509.CertPool
+	now           func() time.Time
+	timeout       time.Duration
+	expiryWarning time.Duration
+}
+
+func NewTLSChecker(opts ...TLSOption) *TLSChecker {
+	c := &TLSChecker{
+		now:           time.Now,
+		timeout:       defaultTLSTimeout,
+		expiryWarning: defaultExpiryWarning,
+	}
+	for i := range opts {
+		opts[i](c)
+	}
+	return c
+}
+
+// Check verifies all targets concurrently, results are returned in the order of targets
+func (c *TLSChecker) Check(ctx context.Context, targets []*TLSTarget) []*Result {
+	results := make([]*Result, len(targets))
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.check(ctx, targets[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *TLSChecker) check(ctx context.Context, t *TLSTarget) *Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	// Skip verification on handshake in order to inspect the certificate by ourselves
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName:         t.ServerName,
+			InsecureSkipVerify: true, // nolint:gosec
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", t.address())
+	if err != nil {
+		return fail(CategoryTLS, t.Name, "TLS handshake to %s failed: %s", t.address(), err)
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return fail(CategoryTLS, t.Name, "%s does not present any certificates", t.address())
+	}
+	leaf := certs[0]
+	now := c.now()
+
+	if now.After(leaf.NotAfter) {
+		return fail(CategoryTLS, t.Name, "Certificate of %s expired at %s", t.address(), leaf.NotAfter.Format(time.RFC3339))
+	}
+	if now.Before(leaf.NotBefore) {
+		return fail(CategoryTLS, t.Name, "Certificate of %s is not valid until %s", t.address(), leaf.NotBefore.Format(time.RFC3339))
+	}
+	if t.SkipVerify {
+		return warn(CategoryTLS, t.Name, "Certificate verification is disabled by .ssl_check_cert = never")
+	}
+	if err := leaf.VerifyHostname(t.CertHostname); err != nil {
+		return fail(CategoryTLS, t.Name, "Certificate of %s does not match %s: SANs are %s",
+			t.address(), t.CertHostname, strings.Join(leaf.DNSNames, ", "))
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         c.roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	}); err != nil {
+		return fail(CategoryTLS, t.Name, "Certificate of %s is not trusted: %s", t.address(), err)
+	}
+
+	days := int(leaf.NotAfter.Sub(now).Hours() / 24)
+	if leaf.NotAfter.Sub(now) < c.expiryWarning {
+		return warn(CategoryTLS, t.Name, "Certificate for %s expires in %d days", t.CertHostname, days)
+	}
+	return pass(CategoryTLS, t.Name, "Certificate is valid for %s, expires in %d days", t.CertHostname, days)
+}
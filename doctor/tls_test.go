@@ -0,0 +1,120 @@
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/types"
+)
+
+func TestBackendTLSTargets(t *testing.T) {
+	vcl, err := parser.New(lexer.NewFromString(`
+backend F_secure {
+  .host = "origin.example.com";
+  .port = "8443";
+  .ssl = true;
+  .ssl_cert_hostname = "cert.example.com";
+  .ssl_sni_hostname = "sni.example.com";
+}
+backend F_plain {
+  .host = "plain.example.com";
+  .port = "80";
+}
+backend F_nocheck {
+  .host = "nocheck.example.com";
+  .ssl = true;
+  .ssl_check_cert = never;
+}`)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	backends := make(map[string]*types.Backend)
+	for _, stmt := range vcl.Statements {
+		if b, ok := stmt.(*ast.BackendDeclaration); ok {
+			backends[b.Name.Value] = &types.Backend{BackendDecl: b}
+		}
+	}
+
+	targets := BackendTLSTargets(backends)
+	if len(targets) != 2 {
+		t.Errorf("Expects 2 targets but got %d", len(targets))
+		return
+	}
+	if v := targets[0]; v.Name != "F_nocheck" || v.Port != 443 || !v.SkipVerify {
+		t.Errorf("Unexpected target: %+v", v)
+	}
+	if v := targets[1]; v.Name != "F_secure" || v.Port != 8443 || v.ServerName != "sni.example.com" || v.CertHostname != "cert.example.com" {
+		t.Errorf("Unexpected target: %+v", v)
+	}
+}
+
+func TestTLSChecker(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	p, _ := strconv.Atoi(port) // nolint:errcheck
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	target := func(certHostname string) *TLSTarget {
+		return &TLSTarget{Name: "F_origin", Host: host, Port: p, ServerName: certHostname, CertHostname: certHostname}
+	}
+
+	tests := []struct {
+		name   string
+		target *TLSTarget
+		opts   []TLSOption
+		status Status
+	}{
+		{name: "valid", target: target("example.com"), opts: []TLSOption{WithRoots(roots)}, status: StatusPass},
+		{name: "hostname mismatch", target: target("www.example.net"), opts: []TLSOption{WithRoots(roots)}, status: StatusFail},
+		{name: "untrusted", target: target("example.com"), opts: []TLSOption{WithRoots(x509.NewCertPool())}, status: StatusFail},
+		{
+			name:   "expires soon",
+			target: target("example.com"),
+			opts: []TLSOption{WithRoots(roots), WithNow(func() time.Time {
+				return server.Certificate().NotAfter.Add(-24 * time.Hour)
+			})},
+			status: StatusWarn,
+		},
+		{
+			name:   "expired",
+			target: target("example.com"),
+			opts: []TLSOption{WithRoots(roots), WithNow(func() time.Time {
+				return server.Certificate().NotAfter.Add(time.Hour)
+			})},
+			status: StatusFail,
+		},
+		{
+			name:   "handshake failure",
+			target: &TLSTarget{Name: "F_closed", Host: host, Port: 1, ServerName: "example.com", CertHostname: "example.com"},
+			status: StatusFail,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := NewTLSChecker(tt.opts...).Check(context.Background(), []*TLSTarget{tt.target})
+			if len(results) != 1 {
+				t.Errorf("Expects 1 result but got %d", len(results))
+				return
+			}
+			if results[0].Status != tt.status {
+				t.Errorf("Expects status %s but got %s", tt.status, results[0])
+			}
+		})
+	}
+}
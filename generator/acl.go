@@ -0,0 +1,254 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/remote"
+)
+
+// LoadIPList loads IP addresses and CIDRs from the file path or URL, and returns aggregated prefixes.
+// The source accepts plain text which has an address or CIDR per line, and JSON like cloud provider's feed
+// which contains addresses or CIDRs as string values at any depth.
+func LoadIPList(source string) ([]netip.Prefix, error) {
+	var buf []byte
+	var err error
+
+	switch {
+	case source == "":
+		return nil, fmt.Errorf("IP list source must be specified")
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		buf, err = fetchSource(source)
+	default:
+		buf, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	prefixes, err := ParseIPList(buf)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return AggregatePrefixes(prefixes), nil
+}
+
+// ParseIPList parses IP list content as JSON or plain text
+func ParseIPList(buf []byte) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+
+	trimmed := bytes.TrimSpace(buf)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var v interface{}
+		if err := json.Unmarshal(trimmed, &v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		collectPrefixes(v, &prefixes)
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(buf))
+		for line := 1; scanner.Scan(); line++ {
+			text := scanner.Text()
+			// Both "#" and ";" are used as comment sign in popular feeds
+			if i := strings.IndexAny(text, "#;"); i != -1 {
+				text = text[:i]
+			}
+			for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			}) {
+				p, err := parsePrefix(field)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid IP address or CIDR %s at line %d", field, line)
+				}
+				prefixes = append(prefixes, p)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if len(prefixes) == 0 {
+		return nil, fmt.Errorf("No IP address is found in the list")
+	}
+	return prefixes, nil
+}
+
+// Collect string values which can be parsed as IP address or CIDR recursively
+func collectPrefixes(v interface{}, prefixes *[]netip.Prefix) {
+	switch t := v.(type) {
+	case string:
+		if p, err := parsePrefix(t); err == nil {
+			*prefixes = append(*prefixes, p)
+		}
+	case []interface{}:
+		for i := range t {
+			collectPrefixes(t[i], prefixes)
+		}
+	case map[string]interface{}:
+		for _, vv := range t {
+			collectPrefixes(vv, prefixes)
+		}
+	}
+}
+
+func parsePrefix(v string) (netip.Prefix, error) {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, "/") {
+		p, err := netip.ParsePrefix(v)
+		if err != nil {
+			return netip.Prefix{}, errors.WithStack(err)
+		}
+		if p.Addr().Is4In6() && p.Bits() >= 96 {
+			p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+		}
+		return p.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(v)
+	if err != nil {
+		return netip.Prefix{}, errors.WithStack(err)
+	}
+	addr = addr.Unmap().WithZone("")
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// AggregatePrefixes returns the minimal set of CIDRs which covers exactly the same addresses as given prefixes.
+// Overlapping and adjacent ranges are merged, and then each range is split into the largest aligned CIDRs.
+func AggregatePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	type addrRange struct {
+		from, to netip.Addr
+	}
+
+	ranges := make([]addrRange, 0, len(prefixes))
+	for _, p := range prefixes {
+		p = p.Masked()
+		ranges = append(ranges, addrRange{from: p.Addr(), to: lastAddr(p)})
+	}
+	// IPv4 addresses are always sorted before IPv6 addresses
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].from.Less(ranges[j].from)
+	})
+
+	var merged []addrRange
+	for _, r := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			next := last.to.Next()
+			if last.from.Is4() == r.from.Is4() && (r.from.Compare(last.to) <= 0 || (next.IsValid() && r.from == next)) {
+				if r.to.Compare(last.to) > 0 {
+					last.to = r.to
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	var result []netip.Prefix
+	for _, r := range merged {
+		from := r.from
+		for {
+			// Find the largest CIDR which starts from the address and does not exceed the range
+			var p netip.Prefix
+			for bits := 0; bits <= from.BitLen(); bits++ {
+				p = netip.PrefixFrom(from, bits)
+				if p.Masked().Addr() == from && lastAddr(p).Compare(r.to) <= 0 {
+					break
+				}
+			}
+			result = append(result, p)
+			last := lastAddr(p)
+			if last == r.to {
+				break
+			}
+			from = last.Next()
+		}
+	}
+	return result
+}
+
+// Calculate the last address of the prefix
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// GenerateACL generates ACL declaration from the prefixes
+func GenerateACL(name, source string, prefixes []netip.Prefix) string {
+	var buf strings.Builder
+
+	buf.WriteString("# This file is generated by \"falco generate acl\", DO NOT EDIT.\n")
+	buf.WriteString(fmt.Sprintf("# Source: %s\n\n", source))
+	buf.WriteString(fmt.Sprintf("acl %s {\n", name))
+	for _, p := range prefixes {
+		if p.IsSingleIP() {
+			buf.WriteString(fmt.Sprintf("  \"%s\";\n", p.Addr()))
+		} else {
+			buf.WriteString(fmt.Sprintf("  \"%s\"/%d;\n", p.Addr(), p.Bits()))
+		}
+	}
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+// DiffACLEntries calculates operations to make remote ACL entries equal to desired prefixes.
+// Remote entries which are negated, duplicated, or not in desired prefixes are deleted.
+func DiffACLEntries(prefixes []netip.Prefix, entries []*remote.AccessControlEntry) []*remote.AccessControlEntryOperation {
+	desired := make(map[netip.Prefix]struct{}, len(prefixes))
+	for _, p := range prefixes {
+		desired[p] = struct{}{}
+	}
+
+	var ops []*remote.AccessControlEntryOperation
+	exists := make(map[netip.Prefix]struct{})
+	for _, e := range entries {
+		p, err := entryPrefix(e)
+		if err == nil && e.Negated != "1" {
+			if _, ok := desired[p]; ok {
+				if _, dup := exists[p]; !dup {
+					exists[p] = struct{}{}
+					continue
+				}
+			}
+		}
+		ops = append(ops, &remote.AccessControlEntryOperation{
+			Op:      "delete",
+			Id:      e.Id,
+			Ip:      e.Ip,
+			Subnet:  e.Subnet,
+			Negated: e.Negated,
+		})
+	}
+	for _, p := range prefixes {
+		if _, ok := exists[p]; ok {
+			continue
+		}
+		subnet := int64(p.Bits())
+		ops = append(ops, &remote.AccessControlEntryOperation{
+			Op:      "create",
+			Ip:      p.Addr().String(),
+			Subnet:  &subnet,
+			Negated: "0",
+		})
+	}
+	return ops
+}
+
+func entryPrefix(e *remote.AccessControlEntry) (netip.Prefix, error) {
+	if e.Subnet == nil {
+		return parsePrefix(e.Ip)
+	}
+	return parsePrefix(e.Ip + "/" + strconv.FormatInt(*e.Subnet, 10))
+}
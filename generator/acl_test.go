@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/remote"
+)
+
+func TestAggregatePrefixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []string
+		expect []string
+	}{
+		{
+			name:   "adjacent CIDRs are merged",
+			input:  []string{"192.0.2.0/25", "192.0.2.128/25"},
+			expect: []string{"192.0.2.0/24"},
+		},
+		{
+			name:   "overlapped CIDRs are merged",
+			input:  []string{"10.0.0.0/8", "10.1.0.0/16", "10.255.255.255/32"},
+			expect: []string{"10.0.0.0/8"},
+		},
+		{
+			name:   "unaligned range is split",
+			input:  []string{"192.0.2.1/32", "192.0.2.2/31", "192.0.2.4/30"},
+			expect: []string{"192.0.2.1/32", "192.0.2.2/31", "192.0.2.4/30"},
+		},
+		{
+			name:   "single addresses are merged",
+			input:  []string{"198.51.100.3/32", "198.51.100.0/32", "198.51.100.2/32", "198.51.100.1/32"},
+			expect: []string{"198.51.100.0/30"},
+		},
+		{
+			name:   "IPv4 and IPv6 are not mixed",
+			input:  []string{"2001:db8::/33", "255.255.255.255/32", "2001:db8:8000::/33", "::/128"},
+			expect: []string{"255.255.255.255/32", "::/128", "2001:db8::/32"},
+		},
+	}
+
+	for _, tt := range tests {
+		var input []netip.Prefix
+		for _, v := range tt.input {
+			input = append(input, netip.MustParsePrefix(v))
+		}
+		var actual []string
+		for _, p := range AggregatePrefixes(input) {
+			actual = append(actual, p.String())
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: aggregated result mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
+
+func TestLoadIPList(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		expect  []string
+		isError bool
+	}{
+		{
+			name:    "plain text with comments",
+			content: "# office ranges\n192.0.2.0/25\n192.0.2.128/25 ; second floor\n\n203.0.113.10\n",
+			expect:  []string{"192.0.2.0/24", "203.0.113.10/32"},
+		},
+		{
+			name:    "JSON feed",
+			content: `{"syncToken":"1","prefixes":[{"ip_prefix":"192.0.2.0/24","region":"us-east-1"}],"ipv6_prefixes":[{"ipv6_prefix":"2001:db8::/32"}]}`,
+			expect:  []string{"192.0.2.0/24", "2001:db8::/32"},
+		},
+		{
+			name:    "invalid address",
+			content: "192.0.2.0/24\nexample.com\n",
+			isError: true,
+		},
+		{
+			name:    "empty list",
+			content: "# nothing\n",
+			isError: true,
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		file := filepath.Join(dir, "list.txt")
+		if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		prefixes, err := LoadIPList(file)
+		if tt.isError {
+			if err == nil {
+				t.Errorf("%s: expected error but got nil", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+			continue
+		}
+		var actual []string
+		for _, p := range prefixes {
+			actual = append(actual, p.String())
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: loaded result mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
+
+func TestGenerateACL(t *testing.T) {
+	acl := GenerateACL("office", "office.txt", []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("203.0.113.10/32"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	})
+	vcl, err := parser.New(lexer.NewFromString(acl)).ParseVCL()
+	if err != nil {
+		t.Errorf("Generated ACL could not be parsed: %s\n%s", err, acl)
+		return
+	}
+	if len(vcl.Statements) != 1 {
+		t.Errorf("Generated VCL should have only one declaration")
+		return
+	}
+	for _, v := range []string{`"192.0.2.0"/24;`, `"203.0.113.10";`, `"2001:db8::"/32;`} {
+		if !strings.Contains(acl, v) {
+			t.Errorf("Generated ACL should contain %s\n%s", v, acl)
+		}
+	}
+}
+
+func TestDiffACLEntries(t *testing.T) {
+	subnet := func(v int64) *int64 { return &v }
+	prefixes := []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("203.0.113.10/32"),
+	}
+	entries := []*remote.AccessControlEntry{
+		{Id: "keep", Ip: "192.0.2.0", Subnet: subnet(24), Negated: "0"},
+		{Id: "duplicate", Ip: "192.0.2.0", Subnet: subnet(24), Negated: "0"},
+		{Id: "negated", Ip: "203.0.113.10", Negated: "1"},
+		{Id: "stale", Ip: "198.51.100.0", Subnet: subnet(24), Negated: "0"},
+	}
+
+	var actual []string
+	for _, op := range DiffACLEntries(prefixes, entries) {
+		if op.Op == "delete" {
+			actual = append(actual, "delete "+op.Id)
+		} else {
+			actual = append(actual, "create "+netip.PrefixFrom(netip.MustParseAddr(op.Ip), int(*op.Subnet)).String())
+		}
+	}
+	expect := []string{"delete duplicate", "delete negated", "delete stale", "create 203.0.113.10/32"}
+	if diff := cmp.Diff(expect, actual); diff != "" {
+		t.Errorf("Diff operations mismatch, diff=%s", diff)
+	}
+}
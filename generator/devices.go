@@ -0,0 +1,321 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	_ "embed"
+
+	"github.com/go-yaml/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	DeviceSubroutineName = "normalize_device"
+	CrawlerTableName     = "normalize_device_crawlers"
+	DeviceHeader         = "X-UA-Device"
+	CrawlerHeader        = "X-UA-Crawler"
+
+	// Class name which is set when User-Agent is detected as crawler
+	crawlerClass = "crawler"
+	// Class name which is set when User-Agent does not match any classes
+	defaultClass = "desktop"
+	// Maximum alternatives in a single regular expression to keep the expression size reasonable
+	maxAlternatives = 50
+)
+
+//go:embed devices.yml
+var defaultDeviceList []byte
+
+// Crawler represents crawler definition.
+// Tokens are matched as case-insensitive literal substrings and compiled into combined regular expression
+// with lookup table, and Patterns are matched as case-insensitive regular expressions.
+type Crawler struct {
+	Name     string   `yaml:"name"`
+	Tokens   []string `yaml:"tokens"`
+	Patterns []string `yaml:"patterns"`
+	Examples []string `yaml:"examples"`
+}
+
+// DeviceClass represents device class definition
+type DeviceClass struct {
+	Class    string   `yaml:"class"`
+	Patterns []string `yaml:"patterns"`
+	Examples []string `yaml:"examples"`
+}
+
+type DeviceList struct {
+	Crawlers []*Crawler     `yaml:"crawlers"`
+	Devices  []*DeviceClass `yaml:"devices"`
+}
+
+// Generated result of device detection
+type Devices struct {
+	VCL  string
+	Test string
+}
+
+// LoadDeviceList loads device list from the file path or URL.
+// If source is empty, bundled default list is used.
+func LoadDeviceList(source string) (*DeviceList, error) {
+	var buf []byte
+	var err error
+
+	switch {
+	case source == "":
+		buf = defaultDeviceList
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		buf, err = fetchSource(source)
+	default:
+		buf, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var list DeviceList
+	if err := yaml.Unmarshal(buf, &list); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := list.validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &list, nil
+}
+
+// Fetch list content from HTTP(S) URL
+func fetchSource(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.WithStack(
+			fmt.Errorf("Failed to fetch %s, status code is %d", url, resp.StatusCode),
+		)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (d *DeviceList) validate() error {
+	for _, c := range d.Crawlers {
+		if c.Name == "" {
+			return fmt.Errorf("Crawler name must not be empty")
+		}
+		if len(c.Tokens) == 0 && len(c.Patterns) == 0 {
+			return fmt.Errorf("Crawler %s must have either tokens or patterns", c.Name)
+		}
+		for _, p := range c.Patterns {
+			if _, err := regexp.Compile("(?i)" + p); err != nil {
+				return fmt.Errorf("Crawler %s has invalid pattern %s: %s", c.Name, p, err)
+			}
+		}
+	}
+	for _, dc := range d.Devices {
+		if dc.Class == "" {
+			return fmt.Errorf("Device class must not be empty")
+		}
+		if dc.Class == crawlerClass || dc.Class == defaultClass {
+			return fmt.Errorf("Device class %s is reserved", dc.Class)
+		}
+		if len(dc.Patterns) == 0 {
+			return fmt.Errorf("Device class %s must have patterns", dc.Class)
+		}
+		for _, p := range dc.Patterns {
+			if _, err := regexp.Compile("(?i)" + p); err != nil {
+				return fmt.Errorf("Device class %s has invalid pattern %s: %s", dc.Class, p, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GenerateDevices generates device detection VCL and its falco test cases from the device list
+func GenerateDevices(list *DeviceList) *Devices {
+	return &Devices{
+		VCL:  generateDeviceVCL(list),
+		Test: generateDeviceTest(list),
+	}
+}
+
+func generateDeviceVCL(list *DeviceList) string {
+	var buf strings.Builder
+
+	buf.WriteString("# This file is generated by \"falco generate devices\", DO NOT EDIT.\n\n")
+
+	// Lookup table from lowercased crawler token to the crawler name
+	tokens := make(map[string]string)
+	for _, c := range list.Crawlers {
+		for _, t := range c.Tokens {
+			tokens[strings.ToLower(t)] = c.Name
+		}
+	}
+	keys := make([]string, 0, len(tokens))
+	for k := range tokens {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		buf.WriteString(fmt.Sprintf("table %s STRING {\n", CrawlerTableName))
+		for i, k := range keys {
+			buf.WriteString(fmt.Sprintf("  %s: %s", quote(k), quote(tokens[k])))
+			if i != len(keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	// Longer tokens are placed first in the alternation in order to match the most specific one
+	sort.SliceStable(keys, func(i, j int) bool {
+		return len(keys[i]) > len(keys[j])
+	})
+
+	var branches []string
+	for i := 0; i < len(keys); i += maxAlternatives {
+		end := i + maxAlternatives
+		if end > len(keys) {
+			end = len(keys)
+		}
+		alternatives := make([]string, end-i)
+		for j, k := range keys[i:end] {
+			alternatives[j] = regexp.QuoteMeta(k)
+		}
+		branches = append(branches, fmt.Sprintf(
+			"if (req.http.User-Agent ~ %s) {\n"+
+				"    set req.http.%s = \"%s\";\n"+
+				"    set req.http.%s = table.lookup(%s, std.tolower(re.group.1), \"unknown\");\n"+
+				"  }",
+			quote("(?i)("+strings.Join(alternatives, "|")+")"),
+			DeviceHeader, crawlerClass,
+			CrawlerHeader, CrawlerTableName,
+		))
+	}
+	for _, c := range list.Crawlers {
+		if len(c.Patterns) == 0 {
+			continue
+		}
+		branches = append(branches, fmt.Sprintf(
+			"if (req.http.User-Agent ~ %s) {\n"+
+				"    set req.http.%s = \"%s\";\n"+
+				"    set req.http.%s = %s;\n"+
+				"  }",
+			quote(combinePatterns(c.Patterns)),
+			DeviceHeader, crawlerClass,
+			CrawlerHeader, quote(c.Name),
+		))
+	}
+	for _, dc := range list.Devices {
+		branches = append(branches, fmt.Sprintf(
+			"if (req.http.User-Agent ~ %s) {\n"+
+				"    set req.http.%s = %s;\n"+
+				"  }",
+			quote(combinePatterns(dc.Patterns)),
+			DeviceHeader, quote(dc.Class),
+		))
+	}
+
+	buf.WriteString(fmt.Sprintf("sub %s {\n", DeviceSubroutineName))
+	buf.WriteString(fmt.Sprintf("  unset req.http.%s;\n", CrawlerHeader))
+	if len(branches) == 0 {
+		buf.WriteString(fmt.Sprintf("  set req.http.%s = \"%s\";\n", DeviceHeader, defaultClass))
+	} else {
+		buf.WriteString("  " + strings.Join(branches, " else "))
+		buf.WriteString(" else {\n")
+		buf.WriteString(fmt.Sprintf("    set req.http.%s = \"%s\";\n", DeviceHeader, defaultClass))
+		buf.WriteString("  }\n")
+	}
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+func generateDeviceTest(list *DeviceList) string {
+	var buf strings.Builder
+
+	buf.WriteString("# This file is generated by \"falco generate devices\", DO NOT EDIT.\n")
+
+	for _, c := range list.Crawlers {
+		for i, ex := range c.Examples {
+			buf.WriteString(fmt.Sprintf(
+				"\n// @scope: recv\n"+
+					"// @suite: %s is detected as %s crawler\n"+
+					"sub test_%s_crawler_%s_%d {\n"+
+					"  set req.http.User-Agent = %s;\n"+
+					"  testing.call_subroutine(\"%s\");\n"+
+					"  assert.equal(req.http.%s, \"%s\");\n"+
+					"  assert.equal(req.http.%s, %s);\n"+
+					"}\n",
+				suiteName(ex), c.Name,
+				DeviceSubroutineName, identName(c.Name), i+1,
+				quote(ex),
+				DeviceSubroutineName,
+				DeviceHeader, crawlerClass,
+				CrawlerHeader, quote(c.Name),
+			))
+		}
+	}
+	for _, dc := range list.Devices {
+		for i, ex := range dc.Examples {
+			buf.WriteString(fmt.Sprintf(
+				"\n// @scope: recv\n"+
+					"// @suite: %s is detected as %s\n"+
+					"sub test_%s_device_%s_%d {\n"+
+					"  set req.http.User-Agent = %s;\n"+
+					"  testing.call_subroutine(\"%s\");\n"+
+					"  assert.equal(req.http.%s, %s);\n"+
+					"  assert.is_notset(req.http.%s);\n"+
+					"}\n",
+				suiteName(ex), dc.Class,
+				DeviceSubroutineName, identName(dc.Class), i+1,
+				quote(ex),
+				DeviceSubroutineName,
+				DeviceHeader, quote(dc.Class),
+				CrawlerHeader,
+			))
+		}
+	}
+
+	return buf.String()
+}
+
+func combinePatterns(patterns []string) string {
+	if len(patterns) == 1 {
+		return "(?i)" + patterns[0]
+	}
+	return "(?i)(" + strings.Join(patterns, "|") + ")"
+}
+
+// Quote value as VCL string literal, use long string literal if the value contains double quote
+func quote(v string) string {
+	if strings.Contains(v, `"`) {
+		return `{"` + v + `"}`
+	}
+	return `"` + v + `"`
+}
+
+var nonIdentChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func identName(v string) string {
+	return strings.Trim(nonIdentChars.ReplaceAllString(strings.ToLower(v), "_"), "_")
+}
+
+// Suite name is written in a comment line so it should not contain any line breaks
+func suiteName(v string) string {
+	v = strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+	if len(v) > 60 {
+		return v[:60] + "..."
+	}
+	return v
+}
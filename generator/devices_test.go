@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ysugimoto/falco/config"
+	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/tester"
+)
+
+func TestGenerateDevices(t *testing.T) {
+	list, err := LoadDeviceList("")
+	if err != nil {
+		t.Errorf("Unexpected error on loading default list: %s", err)
+		return
+	}
+	devices := GenerateDevices(list)
+
+	for _, v := range []string{devices.VCL, devices.Test} {
+		if _, err := parser.New(lexer.NewFromString(v)).ParseVCL(); err != nil {
+			t.Errorf("Generated VCL could not be parsed: %s\n%s", err, v)
+			return
+		}
+	}
+
+	// Run generated tests against generated VCL
+	dir := t.TempDir()
+	main := filepath.Join(dir, "devices.vcl")
+	if err := os.WriteFile(main, []byte(devices.VCL), 0o644); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "devices.test.vcl"), []byte(devices.Test), 0o644); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	resolvers, err := resolver.NewFileResolvers(main, []string{dir})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	factory, err := tester.New(&config.TestConfig{
+		Filter:       "*.test.vcl",
+		IncludePaths: []string{dir},
+	}, []icontext.Option{icontext.WithResolver(resolvers[0])}).Run(main)
+	if err != nil {
+		t.Errorf("Unexpected error on running tests: %s", err)
+		return
+	}
+	if factory.Statistics.Passes == 0 {
+		t.Errorf("Generated tests are not run")
+	}
+	for _, r := range factory.Results {
+		for _, c := range r.Cases {
+			if c.Error != nil {
+				t.Errorf("Generated test %s failed: %s", c.Name, c.Error)
+			}
+		}
+	}
+}
+
+func TestLoadDeviceListValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{name: "crawler without tokens", yaml: "crawlers:\n  - name: Foo\n"},
+		{name: "reserved class", yaml: "devices:\n  - class: desktop\n    patterns: [\"foo\"]\n"},
+		{name: "invalid pattern", yaml: "devices:\n  - class: tablet\n    patterns: [\"android(?!.*mobile)\"]\n"},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		file := filepath.Join(dir, "list.yml")
+		if err := os.WriteFile(file, []byte(tt.yaml), 0o644); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		if _, err := LoadDeviceList(file); err == nil {
+			t.Errorf("%s: expected error but got nil", tt.name)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+const (
+	// Fastly convention of the error status which is handled as redirect in vcl_error
+	RedirectErrorStatus   = 618
+	RedirectErrorResponse = "redirect"
+)
+
+// Redirect status codes and reason phrases which could be specified in redirect map
+var redirectStatuses = map[int]string{
+	http.StatusMovedPermanently:  "Moved Permanently",
+	http.StatusFound:             "Found",
+	http.StatusSeeOther:          "See Other",
+	http.StatusTemporaryRedirect: "Temporary Redirect",
+	http.StatusPermanentRedirect: "Permanent Redirect",
+}
+
+// Redirect is an entry of redirect map, request path which equals to From is redirected to To.
+// Status is 301 when omitted.
+type Redirect struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Status int    `yaml:"status"`
+}
+
+// Generated result of redirect map
+type Redirects struct {
+	VCL  string
+	Test string
+	// Table names which are split into partitions because they have more items than the limit
+	SplitTables []string
+}
+
+// LoadRedirects reads redirect map file. CSV file has "from,to[,status]" columns in each row,
+// and YAML file is a list of objects which have from, to and status fields.
+func LoadRedirects(file string) ([]*Redirect, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var redirects []*Redirect
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		redirects, err = ParseRedirectsCSV(bytes.NewReader(buf))
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(buf, &redirects)
+	default:
+		return nil, fmt.Errorf("Unsupported redirect map file %s, must be CSV or YAML", file)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return ResolveRedirects(redirects)
+}
+
+// ParseRedirectsCSV parses CSV redirect map. Lines which start with "#" are ignored,
+// and the first row is treated as header when its first column is not a path.
+func ParseRedirectsCSV(r io.Reader) ([]*Redirect, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var redirects []*Redirect
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if row == 1 && !strings.HasPrefix(record[0], "/") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("Row %d must have from, to and optional status columns", row)
+		}
+		redirect := &Redirect{
+			From: strings.TrimSpace(record[0]),
+			To:   strings.TrimSpace(record[1]),
+		}
+		if len(record) == 3 && strings.TrimSpace(record[2]) != "" {
+			status, err := strconv.Atoi(strings.TrimSpace(record[2]))
+			if err != nil {
+				return nil, fmt.Errorf("Row %d has invalid status %s", row, record[2])
+			}
+			redirect.Status = status
+		}
+		redirects = append(redirects, redirect)
+	}
+	return redirects, nil
+}
+
+// ResolveRedirects validates redirects and resolves chains like "/a -> /b -> /c" to "/a -> /c"
+// in order to avoid extra round trips. Status of the first redirect is kept.
+func ResolveRedirects(redirects []*Redirect) ([]*Redirect, error) {
+	paths := make(map[string]*Redirect, len(redirects))
+	for _, r := range redirects {
+		if r.Status == 0 {
+			r.Status = http.StatusMovedPermanently
+		}
+		if _, ok := redirectStatuses[r.Status]; !ok {
+			return nil, fmt.Errorf("Redirect from %s has invalid status %d, must be one of 301, 302, 303, 307 or 308", r.From, r.Status)
+		}
+		if !strings.HasPrefix(r.From, "/") || strings.Contains(r.From, "?") {
+			return nil, fmt.Errorf("Redirect source %s must be a path which starts with / and does not have query string", r.From)
+		}
+		if r.To == "" {
+			return nil, fmt.Errorf("Redirect from %s must have target", r.From)
+		}
+		if _, ok := paths[r.From]; ok {
+			return nil, fmt.Errorf("Redirect from %s is duplicated", r.From)
+		}
+		paths[r.From] = r
+	}
+
+	resolved := make([]*Redirect, len(redirects))
+	for i, r := range redirects {
+		to := r.To
+		visited := map[string]struct{}{r.From: {}}
+		for {
+			next, ok := paths[to]
+			if !ok {
+				break
+			}
+			if _, ok := visited[to]; ok {
+				return nil, fmt.Errorf("Redirect loop is detected from %s", r.From)
+			}
+			visited[to] = struct{}{}
+			to = next.To
+		}
+		resolved[i] = &Redirect{From: r.From, To: to, Status: r.Status}
+	}
+	return resolved, nil
+}
+
+// GenerateRedirects generates redirect tables and handler subroutines, and their falco test cases.
+// Request path is looked up in the table in vcl_recv, then the redirect response is made in vcl_error.
+// Tables which have more items than the limit are split into partitioned tables.
+func GenerateRedirects(name string, redirects []*Redirect, limit int) (*Redirects, error) {
+	vcl := generateRedirectVCL(name, redirects)
+
+	var split []string
+	if limit <= 0 {
+		limit = DefaultTableItemLimit
+	}
+	if len(redirects) > limit {
+		parsed, err := parser.New(lexer.NewFromString(vcl)).ParseVCL()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		result, err := SplitLargeTables(parsed, limit)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		vcl = redirectHeader() + result.VCL
+		split = result.Tables
+	}
+
+	return &Redirects{
+		VCL:         vcl,
+		Test:        generateRedirectTest(name, redirects),
+		SplitTables: split,
+	}, nil
+}
+
+func redirectHeader() string {
+	return "# This file is generated by \"falco generate redirects\", DO NOT EDIT.\n\n"
+}
+
+func generateRedirectVCL(name string, redirects []*Redirect) string {
+	var buf strings.Builder
+
+	buf.WriteString(redirectHeader())
+
+	// Status table only has items which are not 301 to keep the table small
+	sorted := make([]*Redirect, len(redirects))
+	copy(sorted, redirects)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].From < sorted[j].From
+	})
+	var statuses []*Redirect
+	used := map[int]struct{}{http.StatusMovedPermanently: {}}
+	for _, r := range sorted {
+		if r.Status != http.StatusMovedPermanently {
+			statuses = append(statuses, r)
+			used[r.Status] = struct{}{}
+		}
+	}
+
+	buf.WriteString(fmt.Sprintf("table %s_targets STRING {\n", name))
+	for i, r := range sorted {
+		buf.WriteString(fmt.Sprintf("  %s: %s", quote(r.From), quote(r.To)))
+		if i != len(sorted)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n\n")
+
+	if len(statuses) > 0 {
+		buf.WriteString(fmt.Sprintf("table %s_statuses INTEGER {\n", name))
+		for i, r := range statuses {
+			buf.WriteString(fmt.Sprintf("  %s: %d", quote(r.From), r.Status))
+			if i != len(statuses)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString(fmt.Sprintf("sub %s_recv {\n", name))
+	buf.WriteString(fmt.Sprintf("  if (table.contains(%s_targets, req.url.path)) {\n", name))
+	buf.WriteString(fmt.Sprintf("    error %d \"%s\";\n", RedirectErrorStatus, RedirectErrorResponse))
+	buf.WriteString("  }\n")
+	buf.WriteString("}\n\n")
+
+	buf.WriteString(fmt.Sprintf("sub %s_error {\n", name))
+	buf.WriteString(fmt.Sprintf(
+		"  if (obj.status == %d && obj.response == \"%s\") {\n",
+		RedirectErrorStatus, RedirectErrorResponse,
+	))
+	if len(statuses) > 0 {
+		buf.WriteString(fmt.Sprintf(
+			"    set obj.status = table.lookup_integer(%s_statuses, req.url.path, %d);\n",
+			name, http.StatusMovedPermanently,
+		))
+	} else {
+		buf.WriteString(fmt.Sprintf("    set obj.status = %d;\n", http.StatusMovedPermanently))
+	}
+	buf.WriteString(fmt.Sprintf("    set obj.http.Location = table.lookup(%s_targets, req.url.path);\n", name))
+
+	codes := make([]int, 0, len(used))
+	for code := range used {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	if len(codes) == 1 {
+		buf.WriteString(fmt.Sprintf("    set obj.response = \"%s\";\n", redirectStatuses[codes[0]]))
+	} else {
+		branches := make([]string, len(codes))
+		for i, code := range codes {
+			branches[i] = fmt.Sprintf(
+				"if (obj.status == %d) {\n"+
+					"      set obj.response = \"%s\";\n"+
+					"    }",
+				code, redirectStatuses[code],
+			)
+		}
+		buf.WriteString("    " + strings.Join(branches, " else ") + "\n")
+	}
+	buf.WriteString("    synthetic {\"\"};\n")
+	buf.WriteString("    return(deliver);\n")
+	buf.WriteString("  }\n")
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
+
+func generateRedirectTest(name string, redirects []*Redirect) string {
+	var buf strings.Builder
+
+	buf.WriteString("# This file is generated by \"falco generate redirects\", DO NOT EDIT.\n")
+
+	for i, r := range redirects {
+		buf.WriteString(fmt.Sprintf(
+			"\n// @scope: recv\n"+
+				"// @suite: %s is redirected\n"+
+				"sub test_%s_recv_%d {\n"+
+				"  set req.url = %s;\n"+
+				"  testing.call_subroutine(\"%s_recv\");\n"+
+				"  assert.error(%d, \"%s\");\n"+
+				"}\n",
+			suiteName(r.From),
+			name, i+1,
+			quote(r.From),
+			name,
+			RedirectErrorStatus, RedirectErrorResponse,
+		))
+		buf.WriteString(fmt.Sprintf(
+			"\n// @scope: error\n"+
+				"// @suite: %s is redirected to %s with %d\n"+
+				"sub test_%s_error_%d {\n"+
+				"  set req.url = %s;\n"+
+				"  set obj.status = %d;\n"+
+				"  set obj.response = \"%s\";\n"+
+				"  testing.call_subroutine(\"%s_error\");\n"+
+				"  assert.equal(obj.status, %d);\n"+
+				"  assert.equal(obj.http.Location, %s);\n"+
+				"}\n",
+			suiteName(r.From), suiteName(r.To), r.Status,
+			name, i+1,
+			quote(r.From),
+			RedirectErrorStatus,
+			RedirectErrorResponse,
+			name,
+			r.Status,
+			quote(r.To),
+		))
+	}
+
+	// Query string is not a part of the lookup key
+	if len(redirects) > 0 {
+		buf.WriteString(fmt.Sprintf(
+			"\n// @scope: recv\n"+
+				"// @suite: Query string is ignored on matching\n"+
+				"sub test_%s_recv_query {\n"+
+				"  set req.url = %s;\n"+
+				"  testing.call_subroutine(\"%s_recv\");\n"+
+				"  assert.error(%d, \"%s\");\n"+
+				"}\n",
+			name,
+			quote(redirects[0].From+"?falco=1"),
+			name,
+			RedirectErrorStatus, RedirectErrorResponse,
+		))
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestParseRedirectsCSV(t *testing.T) {
+	input := `from,to,status
+# comment
+/old,/new
+/campaign, https://example.com/sale ,302
+`
+	redirects, err := ParseRedirectsCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expect := []*Redirect{
+		{From: "/old", To: "/new"},
+		{From: "/campaign", To: "https://example.com/sale", Status: 302},
+	}
+	if diff := cmp.Diff(expect, redirects); diff != "" {
+		t.Errorf("Redirects mismatch, diff=%s", diff)
+	}
+
+	if _, err := ParseRedirectsCSV(strings.NewReader("/old\n")); err == nil {
+		t.Errorf("Expected error for missing target column")
+	}
+	if _, err := ParseRedirectsCSV(strings.NewReader("/old,/new,moved\n")); err == nil {
+		t.Errorf("Expected error for invalid status")
+	}
+}
+
+func TestResolveRedirects(t *testing.T) {
+	t.Run("chains are resolved", func(t *testing.T) {
+		redirects, err := ResolveRedirects([]*Redirect{
+			{From: "/a", To: "/b", Status: 302},
+			{From: "/b", To: "/c"},
+			{From: "/c", To: "https://example.com/"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expect := []*Redirect{
+			{From: "/a", To: "https://example.com/", Status: 302},
+			{From: "/b", To: "https://example.com/", Status: 301},
+			{From: "/c", To: "https://example.com/", Status: 301},
+		}
+		if diff := cmp.Diff(expect, redirects); diff != "" {
+			t.Errorf("Redirects mismatch, diff=%s", diff)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		redirects []*Redirect
+	}{
+		{name: "loop", redirects: []*Redirect{{From: "/a", To: "/b"}, {From: "/b", To: "/a"}}},
+		{name: "self loop", redirects: []*Redirect{{From: "/a", To: "/a"}}},
+		{name: "duplicated", redirects: []*Redirect{{From: "/a", To: "/b"}, {From: "/a", To: "/c"}}},
+		{name: "invalid status", redirects: []*Redirect{{From: "/a", To: "/b", Status: 200}}},
+		{name: "not a path", redirects: []*Redirect{{From: "a", To: "/b"}}},
+		{name: "query string", redirects: []*Redirect{{From: "/a?b=c", To: "/b"}}},
+		{name: "empty target", redirects: []*Redirect{{From: "/a"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ResolveRedirects(tt.redirects); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func TestGenerateRedirects(t *testing.T) {
+	redirects, err := ResolveRedirects([]*Redirect{
+		{From: "/old", To: "/new"},
+		{From: "/campaign", To: "https://example.com/sale", Status: 302},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	result, err := GenerateRedirects("redirects", redirects, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for _, src := range []string{result.VCL, result.Test} {
+		if _, err := parser.New(lexer.NewFromString(src)).ParseVCL(); err != nil {
+			t.Errorf("Generated VCL could not be parsed: %s\n%s", err, src)
+		}
+	}
+	for _, expect := range []string{
+		`"/campaign": "https://example.com/sale",`,
+		`"/campaign": 302`,
+		`error 618 "redirect";`,
+		`set obj.status = table.lookup_integer(redirects_statuses, req.url.path, 301);`,
+		`set obj.response = "Found";`,
+	} {
+		if !strings.Contains(result.VCL, expect) {
+			t.Errorf("Generated VCL should contain %s\n%s", expect, result.VCL)
+		}
+	}
+	if strings.Contains(result.VCL, `"/old": 301`) {
+		t.Errorf("Status table should not contain 301 redirects")
+	}
+	if len(result.SplitTables) != 0 {
+		t.Errorf("Tables should not be split, got %v", result.SplitTables)
+	}
+
+	result, err = GenerateRedirects("redirects", redirects, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"redirects_targets"}, result.SplitTables); diff != "" {
+		t.Errorf("Split tables mismatch, diff=%s", diff)
+	}
+}
@@ -0,0 +1,521 @@
+package generator
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/token"
+)
+
+const (
+	// Fastly limits table items to 1000
+	DefaultTableItemLimit = 1000
+	// Request header which is used to pass the lookup key in generated tests
+	TableKeyHeader = "X-Falco-Table-Key"
+
+	// Maximum partitions, partition is decided by at most two hex digits of digest
+	maxTablePartitions = 256
+	// Length of MD5 hex digest
+	md5HexLength = 32
+	// Key which is used to verify missing key is not found in generated tests
+	missingTableKey = "falco_missing_key"
+)
+
+// Lookup function names and default values which are used in generated tests
+var tableLookupFunctions = map[string]string{
+	"STRING":  "table.lookup",
+	"INTEGER": "table.lookup_integer",
+	"FLOAT":   "table.lookup_float",
+	"BOOL":    "table.lookup_bool",
+	"RTIME":   "table.lookup_rtime",
+	"IP":      "table.lookup_ip",
+	"BACKEND": "table.lookup_backend",
+	"ACL":     "table.lookup_acl",
+}
+
+var tableLookupDefaults = map[string]string{
+	"INTEGER": "0",
+	"FLOAT":   "0.0",
+	"BOOL":    "false",
+	"RTIME":   "0s",
+}
+
+// Split result of large tables
+type SplitTables struct {
+	VCL    string
+	Test   string
+	Tables []string
+}
+
+type partitionedTable struct {
+	decl       *ast.TableDeclaration
+	partitions []*ast.TableDeclaration
+	digits     int
+}
+
+// SplitLargeTables splits tables which have more items than limit into partitioned tables.
+// Each item is placed into the partition which is decided by the prefix of MD5 digest of its key,
+// and all lookups for the table are rewritten to binary search chains over partitions:
+//
+// - table.lookup() is rewritten to nested if() expression
+// - table.contains() is rewritten to boolean expression
+// - other typed lookups like table.lookup_integer() are supported only as a right-hand value of set statement
+//
+// Note that key expression is evaluated per branch, so key should not have any side-effects.
+func SplitLargeTables(vcl *ast.VCL, limit int) (*SplitTables, error) {
+	if limit <= 0 {
+		limit = DefaultTableItemLimit
+	}
+
+	tables := make(map[string]*partitionedTable)
+	var names []string
+	for _, stmt := range vcl.Statements {
+		decl, ok := stmt.(*ast.TableDeclaration)
+		if !ok || len(decl.Properties) <= limit {
+			continue
+		}
+		pt, err := partitionTable(decl, limit)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		tables[decl.Name.Value] = pt
+		names = append(names, decl.Name.Value)
+	}
+
+	s := &tableSplitter{tables: tables}
+	var statements []ast.Statement
+	for _, stmt := range vcl.Statements {
+		switch t := stmt.(type) {
+		case *ast.TableDeclaration:
+			if pt, ok := tables[t.Name.Value]; ok {
+				for _, p := range pt.partitions {
+					statements = append(statements, p)
+				}
+				continue
+			}
+		case *ast.SubroutineDeclaration:
+			if err := s.rewriteBlock(t.Block); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		statements = append(statements, stmt)
+	}
+
+	return &SplitTables{
+		VCL:    (&ast.VCL{Statements: statements}).String(),
+		Test:   s.generateTest(names),
+		Tables: names,
+	}, nil
+}
+
+func partitionTable(decl *ast.TableDeclaration, limit int) (*partitionedTable, error) {
+	for count := 2; count <= maxTablePartitions; count *= 2 {
+		digits := 1
+		if count > 16 {
+			digits = 2
+		}
+		partitions := make([][]*ast.TableProperty, count)
+		for _, prop := range decl.Properties {
+			index := partitionIndex(prop.Key.Value, digits, count)
+			partitions[index] = append(partitions[index], prop)
+		}
+
+		var exceeded bool
+		for i := range partitions {
+			if len(partitions[i]) > limit {
+				exceeded = true
+				break
+			}
+		}
+		if exceeded {
+			continue
+		}
+
+		pt := &partitionedTable{decl: decl, digits: digits}
+		for i := range partitions {
+			pt.partitions = append(pt.partitions, &ast.TableDeclaration{
+				Meta:       ast.New(token.Null, 0),
+				Name:       newIdent(partitionName(decl.Name.Value, i)),
+				ValueType:  decl.ValueType,
+				Properties: partitions[i],
+			})
+		}
+		// Keep leading comments of original table
+		pt.partitions[0].Meta = decl.Meta
+		return pt, nil
+	}
+
+	return nil, fmt.Errorf("Table %s could not be split into %d partitions", decl.Name.Value, maxTablePartitions)
+}
+
+// Partition index is calculated from the first digits of MD5 hex digest of the key
+// as same as std.strtol(substr(digest.hash_md5(key), 0, digits - 32), 16) in VCL
+func partitionIndex(key string, digits, count int) int {
+	hex := fmt.Sprintf("%x", md5.Sum([]byte(key)))
+	v, _ := strconv.ParseInt(hex[:digits], 16, 64) // nolint:errcheck
+	return int(v) * count / bucketSize(digits)
+}
+
+func bucketSize(digits int) int {
+	if digits == 1 {
+		return 16
+	}
+	return 256
+}
+
+func partitionName(name string, index int) string {
+	return fmt.Sprintf("%s__%d", name, index)
+}
+
+func (pt *partitionedTable) valueType() string {
+	if pt.decl.ValueType == nil {
+		return "STRING"
+	}
+	return pt.decl.ValueType.Value
+}
+
+// Condition expression which is true when the key belongs to partitions lower than mid
+func (pt *partitionedTable) condition(key ast.Expression, mid int) ast.Expression {
+	// Negative length is used in order to take the first digits from fixed length MD5 hex digest
+	hash := newCall("std.strtol",
+		newCall("substr",
+			newCall("digest.hash_md5", key),
+			newInteger(0),
+			newInteger(int64(pt.digits-md5HexLength)),
+		),
+		newInteger(16),
+	)
+	return &ast.InfixExpression{
+		Meta:     ast.New(token.Null, 0),
+		Left:     hash,
+		Operator: "<",
+		Right:    newInteger(int64(mid * bucketSize(pt.digits) / len(pt.partitions))),
+	}
+}
+
+// Build nested if() expression for table.lookup()
+func (pt *partitionedTable) lookupExpression(fn string, args []ast.Expression, lo, hi int) ast.Expression {
+	if hi-lo == 1 {
+		return pt.partitionCall(fn, args, lo)
+	}
+	mid := (lo + hi) / 2
+	return &ast.IfExpression{
+		Meta:        ast.New(token.Null, 0),
+		Condition:   pt.condition(args[1], mid),
+		Consequence: pt.lookupExpression(fn, args, lo, mid),
+		Alternative: pt.lookupExpression(fn, args, mid, hi),
+	}
+}
+
+// Build boolean expression for table.contains()
+func (pt *partitionedTable) containsExpression(fn string, args []ast.Expression, lo, hi int) ast.Expression {
+	if hi-lo == 1 {
+		return pt.partitionCall(fn, args, lo)
+	}
+	mid := (lo + hi) / 2
+	cond := pt.condition(args[1], mid)
+	return &ast.InfixExpression{
+		Meta: ast.New(token.Null, 0),
+		Left: &ast.InfixExpression{
+			Meta:     ast.New(token.Null, 0),
+			Left:     cond,
+			Operator: "&&",
+			Right:    pt.containsExpression(fn, args, lo, mid),
+		},
+		Operator: "||",
+		Right: &ast.InfixExpression{
+			Meta: ast.New(token.Null, 0),
+			Left: &ast.PrefixExpression{
+				Meta:     ast.New(token.Null, 0),
+				Operator: "!",
+				Right:    cond,
+			},
+			Operator: "&&",
+			Right:    pt.containsExpression(fn, args, mid, hi),
+		},
+	}
+}
+
+// Build nested if statement which sets the lookup result for typed lookups
+func (pt *partitionedTable) lookupStatement(stmt *ast.SetStatement, call *ast.FunctionCallExpression, lo, hi, nest int) ast.Statement {
+	if hi-lo == 1 {
+		return &ast.SetStatement{
+			Meta:     ast.New(token.Null, nest),
+			Ident:    stmt.Ident,
+			Operator: stmt.Operator,
+			Value:    pt.partitionCall(call.Function.Value, call.Arguments, lo),
+		}
+	}
+	mid := (lo + hi) / 2
+	return &ast.IfStatement{
+		Meta:      ast.New(token.Null, nest),
+		Condition: pt.condition(call.Arguments[1], mid),
+		Consequence: &ast.BlockStatement{
+			Meta:       ast.New(token.Null, nest+1),
+			Statements: []ast.Statement{pt.lookupStatement(stmt, call, lo, mid, nest+1)},
+		},
+		Alternative: &ast.BlockStatement{
+			Meta:       ast.New(token.Null, nest+1),
+			Statements: []ast.Statement{pt.lookupStatement(stmt, call, mid, hi, nest+1)},
+		},
+	}
+}
+
+func (pt *partitionedTable) partitionCall(fn string, args []ast.Expression, index int) ast.Expression {
+	arguments := make([]ast.Expression, len(args))
+	copy(arguments, args)
+	arguments[0] = newIdent(pt.partitions[index].Name.Value)
+	return newCall(fn, arguments...)
+}
+
+type tableSplitter struct {
+	tables map[string]*partitionedTable
+}
+
+// Find partitioned table which is referred by table function call
+func (s *tableSplitter) lookupTarget(exp ast.Expression) (*partitionedTable, *ast.FunctionCallExpression) {
+	call, ok := exp.(*ast.FunctionCallExpression)
+	if !ok || !strings.HasPrefix(call.Function.Value, "table.") || len(call.Arguments) < 2 {
+		return nil, nil
+	}
+	ident, ok := call.Arguments[0].(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+	pt, ok := s.tables[ident.Value]
+	if !ok {
+		return nil, nil
+	}
+	return pt, call
+}
+
+func (s *tableSplitter) rewriteBlock(block *ast.BlockStatement) error {
+	if block == nil {
+		return nil
+	}
+	for i, stmt := range block.Statements {
+		rewritten, err := s.rewriteStatement(stmt)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		block.Statements[i] = rewritten
+	}
+	return nil
+}
+
+func (s *tableSplitter) rewriteStatement(stmt ast.Statement) (ast.Statement, error) {
+	var err error
+
+	switch t := stmt.(type) {
+	case *ast.BlockStatement:
+		err = s.rewriteBlock(t)
+	case *ast.IfStatement:
+		if t.Condition, err = s.rewriteExpression(t.Condition); err != nil {
+			return nil, err
+		}
+		if err = s.rewriteBlock(t.Consequence); err != nil {
+			return nil, err
+		}
+		for _, a := range t.Another {
+			if a.Condition, err = s.rewriteExpression(a.Condition); err != nil {
+				return nil, err
+			}
+			if err = s.rewriteBlock(a.Consequence); err != nil {
+				return nil, err
+			}
+		}
+		err = s.rewriteBlock(t.Alternative)
+	case *ast.SwitchStatement:
+		if t.Control, err = s.rewriteExpression(t.Control); err != nil {
+			return nil, err
+		}
+		for _, c := range t.Cases {
+			for i := range c.Statements {
+				if c.Statements[i], err = s.rewriteStatement(c.Statements[i]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case *ast.SetStatement:
+		// Typed lookup is rewritten to if statement chain
+		if pt, call := s.lookupTarget(t.Value); pt != nil && isTypedLookup(call.Function.Value) {
+			for i := range call.Arguments {
+				if call.Arguments[i], err = s.rewriteExpression(call.Arguments[i]); err != nil {
+					return nil, err
+				}
+			}
+			chain := pt.lookupStatement(t, call, 0, len(pt.partitions), t.Nest)
+			chain.GetMeta().Leading = t.Leading
+			chain.GetMeta().Trailing = t.Trailing
+			return chain, nil
+		}
+		t.Value, err = s.rewriteExpression(t.Value)
+	case *ast.AddStatement:
+		t.Value, err = s.rewriteExpression(t.Value)
+	case *ast.LogStatement:
+		t.Value, err = s.rewriteExpression(t.Value)
+	case *ast.SyntheticStatement:
+		t.Value, err = s.rewriteExpression(t.Value)
+	case *ast.SyntheticBase64Statement:
+		t.Value, err = s.rewriteExpression(t.Value)
+	case *ast.ErrorStatement:
+		if t.Code, err = s.rewriteExpression(t.Code); err != nil {
+			return nil, err
+		}
+		t.Argument, err = s.rewriteExpression(t.Argument)
+	case *ast.ReturnStatement:
+		if t.ReturnExpression != nil {
+			var exp ast.Expression
+			if exp, err = s.rewriteExpression(*t.ReturnExpression); err == nil {
+				t.ReturnExpression = &exp
+			}
+		}
+	case *ast.FunctionCallStatement:
+		for i := range t.Arguments {
+			if t.Arguments[i], err = s.rewriteExpression(t.Arguments[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+func (s *tableSplitter) rewriteExpression(exp ast.Expression) (ast.Expression, error) {
+	var err error
+
+	switch t := exp.(type) {
+	case *ast.PrefixExpression:
+		t.Right, err = s.rewriteExpression(t.Right)
+	case *ast.GroupedExpression:
+		t.Right, err = s.rewriteExpression(t.Right)
+	case *ast.InfixExpression:
+		if t.Left, err = s.rewriteExpression(t.Left); err != nil {
+			return nil, err
+		}
+		t.Right, err = s.rewriteExpression(t.Right)
+	case *ast.IfExpression:
+		if t.Condition, err = s.rewriteExpression(t.Condition); err != nil {
+			return nil, err
+		}
+		if t.Consequence, err = s.rewriteExpression(t.Consequence); err != nil {
+			return nil, err
+		}
+		t.Alternative, err = s.rewriteExpression(t.Alternative)
+	case *ast.FunctionCallExpression:
+		for i := range t.Arguments {
+			if t.Arguments[i], err = s.rewriteExpression(t.Arguments[i]); err != nil {
+				return nil, err
+			}
+		}
+		pt, call := s.lookupTarget(t)
+		if pt == nil {
+			break
+		}
+		switch call.Function.Value {
+		case "table.lookup":
+			return pt.lookupExpression(call.Function.Value, call.Arguments, 0, len(pt.partitions)), nil
+		case "table.contains":
+			return pt.containsExpression(call.Function.Value, call.Arguments, 0, len(pt.partitions)), nil
+		default:
+			return nil, fmt.Errorf(
+				"%s for table %s is supported only as a right-hand value of set statement, at line %d position %d",
+				call.Function.Value, pt.decl.Name.Value, call.Function.Token.Line, call.Function.Token.Position,
+			)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+func isTypedLookup(name string) bool {
+	return strings.HasPrefix(name, "table.lookup_")
+}
+
+// Generate falco test which verifies all items are found via partitioned lookups
+func (s *tableSplitter) generateTest(names []string) string {
+	var buf strings.Builder
+
+	buf.WriteString("# This file is generated by \"falco generate tables\", DO NOT EDIT.\n")
+
+	key := newIdent("req.http." + TableKeyHeader)
+	for _, name := range names {
+		pt := s.tables[name]
+		vt := pt.valueType()
+		fn := tableLookupFunctions[vt]
+
+		buf.WriteString(fmt.Sprintf(
+			"\n// @scope: recv\n"+
+				"// @suite: Partitioned table %s returns the same values as the original table\n"+
+				"sub test_split_table_%s {\n",
+			name, name,
+		))
+		if vt != "STRING" {
+			buf.WriteString(fmt.Sprintf("  declare local var.value %s;\n", vt))
+		}
+		for _, prop := range pt.decl.Properties {
+			buf.WriteString(fmt.Sprintf("  set req.http.%s = %s;\n", TableKeyHeader, quote(prop.Key.Value)))
+			contains := pt.containsExpression("table.contains", []ast.Expression{nil, key}, 0, len(pt.partitions))
+			buf.WriteString(fmt.Sprintf("  assert.true(%s);\n", contains.String()))
+
+			switch vt {
+			case "STRING":
+				lookup := pt.lookupExpression(fn, []ast.Expression{nil, key}, 0, len(pt.partitions))
+				buf.WriteString(fmt.Sprintf("  assert.equal(%s, %s);\n", lookup.String(), prop.Value.String()))
+			case "INTEGER", "FLOAT", "BOOL", "RTIME":
+				stmt := &ast.SetStatement{
+					Ident:    newIdent("var.value"),
+					Operator: &ast.Operator{Meta: ast.New(token.Null, 0), Operator: "="},
+				}
+				call := newCall(fn, nil, key, &ast.Ident{
+					Meta:  ast.New(token.Null, 0),
+					Value: tableLookupDefaults[vt],
+				})
+				buf.WriteString(pt.lookupStatement(stmt, call, 0, len(pt.partitions), 1).String())
+				buf.WriteString(fmt.Sprintf("  assert.equal(var.value, %s);\n", prop.Value.String()))
+			}
+		}
+		// Missing key must not be found in any partitions
+		if !hasTableKey(pt.decl, missingTableKey) {
+			buf.WriteString(fmt.Sprintf("  set req.http.%s = \"%s\";\n", TableKeyHeader, missingTableKey))
+			contains := pt.containsExpression("table.contains", []ast.Expression{nil, key}, 0, len(pt.partitions))
+			buf.WriteString(fmt.Sprintf("  assert.false(%s);\n", contains.String()))
+		}
+		buf.WriteString("}\n")
+	}
+
+	return buf.String()
+}
+
+func hasTableKey(decl *ast.TableDeclaration, key string) bool {
+	for _, prop := range decl.Properties {
+		if prop.Key.Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+func newIdent(v string) *ast.Ident {
+	return &ast.Ident{Meta: ast.New(token.Null, 0), Value: v}
+}
+
+func newInteger(v int64) *ast.Integer {
+	return &ast.Integer{Meta: ast.New(token.Null, 0), Value: v}
+}
+
+func newCall(name string, args ...ast.Expression) *ast.FunctionCallExpression {
+	return &ast.FunctionCallExpression{
+		Meta:      ast.New(token.Null, 0),
+		Function:  newIdent(name),
+		Arguments: args,
+	}
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/config"
+	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/tester"
+)
+
+func largeTable(name, valueType string, size int, value func(i int) string) string {
+	var buf strings.Builder
+	buf.WriteString(fmt.Sprintf("table %s %s {\n", name, valueType))
+	for i := 0; i < size; i++ {
+		buf.WriteString(fmt.Sprintf("  \"/path/%d\": %s,\n", i, value(i)))
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func TestSplitLargeTables(t *testing.T) {
+	input := largeTable("redirects", "STRING", 2500, func(i int) string {
+		return fmt.Sprintf(`"/new/%d"`, i)
+	}) + largeTable("weights", "INTEGER", 1200, func(i int) string {
+		return fmt.Sprint(i)
+	}) + largeTable("small", "STRING", 10, func(i int) string {
+		return fmt.Sprintf(`"%d"`, i)
+	}) + `
+sub vcl_recv {
+  #FASTLY RECV
+  if (table.contains(redirects, req.url.path)) {
+    set req.http.Location = table.lookup(redirects, req.url.path, "/");
+  }
+  set req.http.X-Small = table.lookup(small, req.url.path, "");
+  declare local var.weight INTEGER;
+  set var.weight = table.lookup_integer(weights, req.url.path, 0);
+  return (lookup);
+}
+`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	result, err := SplitLargeTables(vcl, 1000)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if len(result.Tables) != 2 {
+		t.Errorf("Split tables count unmatch, expect 2, got %v", result.Tables)
+		return
+	}
+
+	// Rewritten VCL must not contain original table references
+	for _, name := range []string{"redirects", "weights"} {
+		if strings.Contains(result.VCL, "table "+name+" ") || strings.Contains(result.VCL, "("+name+",") {
+			t.Errorf("Rewritten VCL still refers original table %s", name)
+		}
+	}
+	if !strings.Contains(result.VCL, "table.lookup(small, req.url.path") {
+		t.Errorf("Small table should not be split")
+	}
+
+	// Run generated equivalence tests against rewritten VCL
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.vcl")
+	if err := os.WriteFile(main, []byte(result.VCL), 0o644); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.tables.test.vcl"), []byte(result.Test), 0o644); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	resolvers, err := resolver.NewFileResolvers(main, []string{dir})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	factory, err := tester.New(&config.TestConfig{
+		Filter:       "*.test.vcl",
+		IncludePaths: []string{dir},
+	}, []icontext.Option{icontext.WithResolver(resolvers[0])}).Run(main)
+	if err != nil {
+		t.Errorf("Unexpected error on running tests: %s", err)
+		return
+	}
+	if factory.Statistics.Passes == 0 {
+		t.Errorf("Generated tests are not run")
+	}
+	for _, r := range factory.Results {
+		for _, c := range r.Cases {
+			if c.Error != nil {
+				t.Errorf("Generated test %s failed: %s", c.Name, c.Error)
+			}
+		}
+	}
+}
+
+func TestSplitLargeTablesUnsupportedLookup(t *testing.T) {
+	input := largeTable("weights", "INTEGER", 1200, func(i int) string {
+		return fmt.Sprint(i)
+	}) + `
+sub vcl_recv {
+  #FASTLY RECV
+  if (table.lookup_integer(weights, req.url.path, 0) > 10) {
+    set req.http.Heavy = "1";
+  }
+}
+`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	if _, err := SplitLargeTables(vcl, 1000); err == nil {
+		t.Errorf("Expected error but got nil")
+	}
+}
@@ -0,0 +1,156 @@
+// Package i18n translates diagnostic messages of linter and interpreter.
+//
+// Messages are written in English and formatted at the place where the diagnostic is reported,
+// so the catalog is keyed by the English format string and the formatted message is matched against it.
+// Arguments of the format are captured from the message and embedded to the translated message
+// as {1}, {2}... placeholders in order. Messages which are not found in the catalog are kept in English.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+const defaultLanguage = "en"
+
+// Message catalogs for each language, English format string to translated message
+var catalogs = map[string]map[string]string{
+	"ja": ja,
+}
+
+var active atomic.Pointer[Catalog]
+
+type entry struct {
+	pattern  *regexp.Regexp
+	template string
+}
+
+// Catalog translates formatted English messages into the language
+type Catalog struct {
+	Language string
+	entries  []*entry
+}
+
+func NewCatalog(language string, messages map[string]string) *Catalog {
+	formats := make([]string, 0, len(messages))
+	for format := range messages {
+		formats = append(formats, format)
+	}
+	// Longer format is more specific, try it first so that the result is stable
+	sort.Slice(formats, func(i, j int) bool {
+		if len(formats[i]) != len(formats[j]) {
+			return len(formats[i]) > len(formats[j])
+		}
+		return formats[i] < formats[j]
+	})
+
+	c := &Catalog{Language: language}
+	for _, format := range formats {
+		c.entries = append(c.entries, &entry{
+			pattern:  compile(format),
+			template: messages[format],
+		})
+	}
+	return c
+}
+
+// Translate returns translated message, returns the message as it is when not found in the catalog
+func (c *Catalog) Translate(message string) string {
+	if c == nil {
+		return message
+	}
+	for _, e := range c.entries {
+		matches := e.pattern.FindStringSubmatch(message)
+		if matches == nil {
+			continue
+		}
+		translated := e.template
+		for i := len(matches) - 1; i > 0; i-- {
+			translated = strings.ReplaceAll(translated, fmt.Sprintf("{%d}", i), matches[i])
+		}
+		return translated
+	}
+	return message
+}
+
+// Compile format string to the regular expression which captures each argument
+func compile(format string) *regexp.Regexp {
+	var buf strings.Builder
+	buf.WriteString("^")
+
+	var literal strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i == len(format)-1 {
+			literal.WriteByte(format[i])
+			continue
+		}
+		if format[i+1] == '%' {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+		// Skip flags, width and precision until the verb
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		buf.WriteString(regexp.QuoteMeta(literal.String()))
+		literal.Reset()
+		buf.WriteString("(.*?)")
+		i = j
+	}
+	buf.WriteString(regexp.QuoteMeta(literal.String()))
+	buf.WriteString("$")
+
+	return regexp.MustCompile(buf.String())
+}
+
+// Normalize language like "ja_JP.UTF-8" to "ja"
+func normalize(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "_-."); i >= 0 {
+		language = language[:i]
+	}
+	switch language {
+	case "", "c", "posix":
+		return defaultLanguage
+	}
+	return language
+}
+
+// Languages returns supported languages
+func Languages() []string {
+	languages := []string{defaultLanguage}
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages[1:])
+	return languages
+}
+
+// SetLanguage activates the catalog of the language for Translate, English is used when empty
+func SetLanguage(language string) error {
+	lang := normalize(language)
+	if lang == defaultLanguage {
+		active.Store(nil)
+		return nil
+	}
+	messages, ok := catalogs[lang]
+	if !ok {
+		return errors.WithStack(fmt.Errorf(
+			"Unsupported language %s, must be one of %s", language, strings.Join(Languages(), ", "),
+		))
+	}
+	active.Store(NewCatalog(lang, messages))
+	return nil
+}
+
+// Translate translates the message by the active catalog
+func Translate(message string) string {
+	return active.Load().Translate(message)
+}
@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCatalogTranslate(t *testing.T) {
+	c := NewCatalog("ja", map[string]string{
+		`Variable "%s" is unused`:                   `変数 "{1}" は使用されていません`,
+		"Function %s has %d arity, but %d provided": "関数 {1} の引数は {2} 個ですが、{3} 個渡されています",
+		"Progress %d%% done":                        "{1}% 完了",
+		"Subroutine %s is duplicated":               "サブルーチン {1} が重複しています",
+		"Subroutine %s is duplicated in %s":         "サブルーチン {1} が {2} で重複しています",
+	})
+
+	tests := []struct {
+		name    string
+		message string
+		expect  string
+	}{
+		{
+			name:    "single argument",
+			message: `Variable "var.foo" is unused`,
+			expect:  `変数 "var.foo" は使用されていません`,
+		},
+		{
+			name:    "multiple arguments",
+			message: "Function std.itoa has 1 arity, but 12 provided",
+			expect:  "関数 std.itoa の引数は 1 個ですが、12 個渡されています",
+		},
+		{
+			name:    "escaped percent",
+			message: "Progress 50% done",
+			expect:  "50% 完了",
+		},
+		{
+			name:    "longer format is preferred",
+			message: "Subroutine foo is duplicated in main.vcl",
+			expect:  "サブルーチン foo が main.vcl で重複しています",
+		},
+		{
+			name:    "fallback to English",
+			message: "Unknown message",
+			expect:  "Unknown message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expect, c.Translate(tt.message)); diff != "" {
+				t.Errorf("Translated message mismatch, diff=%s", diff)
+			}
+		})
+	}
+
+	var nilCatalog *Catalog
+	if v := nilCatalog.Translate("message"); v != "message" {
+		t.Errorf("nil catalog should return message as it is, got %s", v)
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	defer SetLanguage("") // nolint:errcheck
+
+	if err := SetLanguage("ja_JP.UTF-8"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if v := Translate(`Variable "var.foo" is unused`); v != `変数 "var.foo" は使用されていません` {
+		t.Errorf("Message should be translated in Japanese, got %s", v)
+	}
+
+	if err := SetLanguage("C"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if v := Translate(`Variable "var.foo" is unused`); v != `Variable "var.foo" is unused` {
+		t.Errorf("Message should be kept in English, got %s", v)
+	}
+
+	if err := SetLanguage("fr"); err == nil {
+		t.Errorf("Expected error for unsupported language")
+	}
+}
+
+func TestCatalogPlaceholders(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{(\d+)\}`)
+
+	for lang, messages := range catalogs {
+		for format, translated := range messages {
+			args := compile(format).NumSubexp()
+			for _, m := range placeholder.FindAllStringSubmatch(translated, -1) {
+				if n := int(m[1][0] - '0'); len(m[1]) > 1 || n < 1 || n > args {
+					t.Errorf("[%s] Placeholder %s is out of arguments of %s", lang, m[0], format)
+				}
+			}
+			if strings.TrimSpace(translated) == "" {
+				t.Errorf("[%s] Empty translation for %s", lang, format)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package i18n
+
+// Japanese message catalog
+var ja = map[string]string{
+	// Linter diagnostics
+	`ACL "%s" is not defined`:                                                     `ACL "{1}" は定義されていません`,
+	`Backend "%s" is not defined`:                                                 `バックエンド "{1}" は定義されていません`,
+	`Variable "%s" is not defined`:                                                `変数 "{1}" は定義されていません`,
+	`Variable "%s" is unused`:                                                     `変数 "{1}" は使用されていません`,
+	`Unused %s "%s"`:                                                              `{1} "{2}" は使用されていません`,
+	`Externally defined %s "%s" is unused`:                                        `外部で定義された {1} "{2}" は使用されていません`,
+	`Duplicated %s "%s"`:                                                          `{1} "{2}" が重複しています`,
+	`Ident %s has invalid name of "%s"`:                                           `{1} の名前 "{2}" は不正です`,
+	`Operator "%s" cannot be used for %s`:                                         `演算子 "{1}" は {2} に使用できません`,
+	`Return statement "%s" is invalid in %s, expected %s`:                         `return 文 "{1}" は {2} では不正です、{3} のいずれかを指定してください`,
+	"Could not access %s in scope %s":                                             "{2} スコープでは {1} にアクセスできません",
+	"%s could not %s":                                                             "{1} は {2} できません",
+	"%s is not a function":                                                        "{1} は関数ではありません",
+	"Expression has %s type, expected %s":                                         "式の型は {1} ですが、{2} が期待されています",
+	"Function %s is undefined":                                                    "関数 {1} は定義されていません",
+	"Function %s has %d arity, but %d arguments provided":                         "関数 {1} の引数は {2} 個ですが、{3} 個渡されています",
+	"Function %s expects argument %d%s as %s but applies %s":                      "関数 {1} の第{2}引数は {4} 型が期待されていますが、{5} 型が渡されています",
+	"Goto destination %s already in use":                                          "goto の飛び先 {1} は既に使用されています",
+	"Goto destination %s is not defined":                                          "goto の飛び先 {1} は定義されていません",
+	"HTTP header %s cannot not be modified":                                       "HTTP ヘッダ {1} は変更できません",
+	"Type mismatch between %s and %s":                                             "{1} と {2} の型が一致しません",
+	"Type mismatch: %s requires type %s but %s was assigned":                      "型が一致しません: {1} は {2} 型ですが {3} 型が代入されています",
+	"Condition %s is always %t":                                                   "条件 {1} は常に {2} です",
+	"Condition is the same as the previous branch, this branch is never executed": "条件が前の分岐と同じため、この分岐は実行されません",
+	"Previous condition is here":                                                  "前の条件はここです",
+	"Subroutine %s must return %s value but some paths reach the end without returning": "サブルーチン {1} は {2} の値を返す必要がありますが、値を返さずに終了する経路があります",
+	"regex string is invalid, %s":                                                                                                     "正規表現が不正です: {1}",
+	`%s "%s" has %d items which exceed the limit of %d`:                                                                               `{1} "{2}" の要素数 {3} が上限 {4} を超えています`,
+	`Key of %s "%s" has %d characters which exceed the limit of %d`:                                                                   `{1} "{2}" のキーの文字数 {3} が上限 {4} を超えています`,
+	`Value of %s "%s" has %d characters which exceed the limit of %d`:                                                                 `{1} "{2}" の値の文字数 {3} が上限 {4} を超えています`,
+	`ACL "%s" has %d entries which exceed the limit of %d`:                                                                            `ACL "{1}" のエントリ数 {2} が上限 {3} を超えています`,
+	`ACL entry %s is already covered by %s in ACL "%s"`:                                                                               `ACL エントリ {1} は ACL "{3}" の {2} に既に含まれています`,
+	`ACL entry %s is duplicated`:                                                                                                      `ACL エントリ {1} が重複しています`,
+	`ACL entry %s conflicts with negated entry of the same range`:                                                                     `ACL エントリ {1} は同じ範囲の否定エントリと矛盾しています`,
+	"%s is referenced but the latest regex matching has only %d capture groups":                                                       "{1} が参照されていますが、直前の正規表現のキャプチャグループは {2} 個しかありません",
+	"Type %s implicit conversion to %s on string concatenation":                                                                       "文字列結合で {1} 型が {2} 型に暗黙的に変換されます",
+	"%s value is stringified as %s when assigned to %s":                                                                               "{3} に代入すると {1} 型の値は {2} として文字列化されます",
+	"Type conversion failed, must be able to cast as %s":                                                                              "型変換に失敗しました、{1} 型に変換できる値を指定してください",
+	"Undefined backend property %s specified":                                                                                         "未定義のバックエンドプロパティ {1} が指定されています",
+	"Undefined director property %s for director type %s specified":                                                                   "ディレクタ種別 {2} に未定義のプロパティ {1} が指定されています",
+	"Undefined table type %s for %s":                                                                                                  "{2} のテーブル型 {1} は定義されていません",
+	"At least one backend must be declared":                                                                                           "バックエンドを1つ以上宣言してください",
+	"Empty return is disallowed in state-machine method":                                                                              "ステートマシンのサブルーチンでは空の return は使用できません",
+	"Only string literals may be passed to log directly.":                                                                             "log には文字列リテラルのみを直接渡せます",
+	"ACL or BACKEND type cannot use in string concatenation":                                                                          "ACL 型と BACKEND 型は文字列結合に使用できません",
+	"error statement is available in RECV, HIT, MISS, PASS and FETCH scopes only":                                                     "error 文は RECV, HIT, MISS, PASS, FETCH スコープでのみ使用できます",
+	"synthetic statement is available in ERROR scope only":                                                                            "synthetic 文は ERROR スコープでのみ使用できます",
+	"restart statement unavailable in scope %s":                                                                                       "restart 文は {1} スコープでは使用できません",
+	`Subroutine "%s" is missing Fastly boilerplate comment "%s" inside definition`:                                                    `サブルーチン "{1}" に Fastly ボイラープレートコメント "{2}" がありません`,
+	`Statement "%s" is executed before Fastly boilerplate comment "%s" in %s, %s`:                                                     `文 "{1}" は {3} の Fastly ボイラープレートコメント "{2}" より前に実行されます、{4}`,
+	"Snippet %s was not found among Fastly managed snippets":                                                                          "スニペット {1} は Fastly 管理スニペットに見つかりません",
+	"Variable %s is deprecated":                                                                                                       "変数 {1} は非推奨です",
+	"Variable %s is deprecated, use %s instead":                                                                                       "変数 {1} は非推奨です、代わりに {2} を使用してください",
+	"Function %s is deprecated":                                                                                                       "関数 {1} は非推奨です",
+	"Function %s is deprecated, use %s instead":                                                                                       "関数 {1} は非推奨です、代わりに {2} を使用してください",
+	"Unreachable code after %s statement, it is never executed":                                                                       "{1} 文の後のコードは到達不能で、実行されることはありません",
+	"Subroutine %s has cyclomatic complexity %d which exceeds %d, consider splitting into smaller subroutines":                        "サブルーチン {1} の循環的複雑度 {2} が {3} を超えています、より小さなサブルーチンへの分割を検討してください",
+	"Nesting depth %d in subroutine %s exceeds %d, consider early return or splitting into smaller subroutines":                       "サブルーチン {2} のネストの深さ {1} が {3} を超えています、早期リターンやより小さなサブルーチンへの分割を検討してください",
+	"restart statement is always executed in vcl_recv without checking req.restarts, the request restarts until it exceeds the limit": "restart 文が req.restarts を確認せずに vcl_recv で常に実行されるため、上限を超えるまでリクエストが再起動されます",
+	"beresp.ttl does not cache the response because return(pass) follows, the TTL is used as the lifetime of hit-for-pass object":     "後続の return(pass) によりレスポンスはキャッシュされず、beresp.ttl は hit-for-pass オブジェクトの有効期間として使われます",
+	"%s could only be modified in vcl_hash subroutine but it is set in %s scope":                                                      "{1} は vcl_hash サブルーチンでのみ変更できますが、{2} スコープで設定されています",
+	"Include cycle detected: %s":                                                                                                      "インクルードの循環が検出されました: {1}",
+	"vcl_hash does not add %s to req.hash, responses for different %s share the same cache object":                                    "vcl_hash で {1} が req.hash に追加されていないため、異なる {2} のレスポンスが同じキャッシュオブジェクトを共有します",
+	"Cached response is varied by %s in %s but the header is neither added to req.hash nor listed in Vary, cache could be poisoned":   "{2} でキャッシュされるレスポンスが {1} により変化しますが、ヘッダーが req.hash にも Vary にも含まれていないため、キャッシュが汚染される可能性があります",
+	`Variable "%s" could not be set in scope of %s, it is writable in scope of %s`:                                                    `変数 "{1}" は {2} スコープでは設定できません、{3} スコープで設定できます`,
+	`Variable "%s" could not be set in scope of %s, it is writable in scope of %s, annotate the subroutine with "@scope: %s" if it is called only in these scopes`: `変数 "{1}" は {2} スコープでは設定できません、{3} スコープで設定できます。これらのスコープからのみ呼び出す場合はサブルーチンに "@scope: {4}" アノテーションを指定してください`,
+
+	"Control expression of switch statement could not be a literal":                                "switch 文の制御式にリテラルは使用できません",
+	"Control expression type %s may not be used in switch statement, it must be STRING or INTEGER": "{1} 型の式は switch 文の制御式に使用できません、STRING 型または INTEGER 型を指定してください",
+	"Regex case could be used only for STRING control expression, got %s":                          "正規表現の case は STRING 型の制御式にのみ使用できますが、{1} 型が指定されています",
+	"Case value type %s does not match the control expression type %s":                             "case の値の型 {1} が制御式の型 {2} と一致しません",
+	"Case %s is the same as the previous case, this case is never matched":                         "case {1} が前の case と同じため、この case に一致することはありません",
+	"%s statement must be the last statement of the case":                                          "{1} 文は case の最後の文である必要があります",
+	"fallthrough statement could not be used in the last case of switch statement":                 "fallthrough 文は switch 文の最後の case では使用できません",
+	"Case must end with break or fallthrough statement, implicit fallthrough is not allowed":       "case は break 文または fallthrough 文で終了する必要があります、暗黙的な fallthrough は許可されていません",
+
+	"Response is cached for %s with Set-Cookie header, the cookie could be served to other clients. Pass the response or remove Set-Cookie header in vcl_fetch": "レスポンスは Set-Cookie ヘッダーを含んだまま {1} キャッシュされるため、Cookie が他のクライアントに配信される可能性があります。vcl_fetch でレスポンスを pass するか Set-Cookie ヘッダーを削除してください",
+
+	"Subfield accessor could be used only for HTTP header variables, %s is not a header variable": "サブフィールドアクセサは HTTP ヘッダー変数にのみ使用できます、{1} はヘッダー変数ではありません",
+	"Subfield name of %s is empty":                                     "{1} のサブフィールド名が空です",
+	"%s header holds a single value, subfield %s would never be found": "{1} ヘッダーは単一の値を持つため、サブフィールド {2} は見つかりません",
+
+	"strftime format string is invalid, %s":                                       "strftime のフォーマット文字列が不正です、{1}",
+	`"%s" could not be parsed as time, std.time always returns the fallback time`: `"{1}" は時刻として解析できないため、std.time は常にフォールバックの時刻を返します`,
+
+	"If statements are estimated to use %d bytes of workspace which exceeds the limit of %d bytes, consider using tables or switch statement instead of many conditions": "if 文は推定 {1} バイトのワークスペースを使用し、上限の {2} バイトを超えています。多数の条件の代わりにテーブルまたは switch 文の使用を検討してください",
+	"Subroutine %s exceeds the limit of %d subroutines":                                                          "サブルーチン {1} はサブルーチン数の上限 {2} を超えています",
+	"VCL is estimated to be %d bytes after resolving includes and snippets, which exceeds the limit of %d bytes": "include とスニペットを解決した後の VCL は推定 {1} バイトで、上限の {2} バイトを超えています",
+
+	"Number %s is assigned to %s %s, use %s to make the unit of time explicit":                   "数値 {1} が {2} 型の {3} に代入されています、時間の単位を明示するために {4} を使用してください",
+	"std.integer2time returns TIME of unix epoch but it is assigned to RTIME %s, use %s instead": "std.integer2time は UNIX エポックの TIME を返しますが RTIME 型の {1} に代入されています、代わりに {2} を使用してください",
+
+	`Return statement "%s" is invalid in %s, no state is legal in all of these scopes`: `return 文 "{1}" は {2} では不正です、これらすべてのスコープで有効なステートはありません`,
+
+	`Name "%s" of %s collides with %s "%s", consider renaming to avoid confusion`: `{2} の名前 "{1}" は {3} "{4}" と衝突しています、混乱を避けるために名前の変更を検討してください`,
+
+	"File starts with UTF-8 byte order mark, consider removing it":    "ファイルが UTF-8 の BOM で始まっています、削除を検討してください",
+	"%d lines end with CRLF, consider normalizing line endings to LF": "{1} 行が CRLF で終わっています、改行コードを LF に統一することを検討してください",
+	"Invalid UTF-8 byte 0x%02X is found at byte offset %d":            "不正な UTF-8 のバイト 0x{1} がバイトオフセット {2} に見つかりました",
+
+	"Unknown annotation @%s":                     "不明なアノテーション @{1} です",
+	"Annotation @%s is duplicated":               "アノテーション @{1} が重複しています",
+	`Value "%s" of annotation @%s is duplicated`: `アノテーション @{2} の値 "{1}" が重複しています`,
+	"Annotation @%s is only available for %s":    "アノテーション @{1} は {2} でのみ使用できます",
+	"Annotation @%s does not accept value":       "アノテーション @{1} は値を受け付けません",
+	"Annotation @%s requires a value":            "アノテーション @{1} には値が必要です",
+	"Annotation @%s requires at least one value": "アノテーション @{1} には少なくとも1つの値が必要です",
+	"Invalid value of annotation @%s: %s":        "アノテーション @{1} の値が不正です: {2}",
+
+	// Related information and fixes
+	"First declaration is here":           "最初の宣言はここです",
+	"Cached object is modified here":      "ここでキャッシュされるオブジェクトが変更されます",
+	"Module is included here":             "ここでモジュールがインクルードされます",
+	"Covering entry is here":              "含んでいるエントリはここです",
+	"Snippet is injected here":            "スニペットはここに挿入されます",
+	"Subroutine returns here":             "ここでサブルーチンから戻ります",
+	"Request moves to vcl_error here":     "ここで vcl_error に移ります",
+	"Request restarts here":               "ここでリクエストが再起動されます",
+	"Always jumps to %s here":             "ここで常に {1} へジャンプします",
+	"Subroutine %s is always called here": "ここでサブルーチン {1} が常に呼び出されます",
+	"Remove unused variable declaration":  "未使用の変数宣言を削除する",
+	"Rename variable to %s":               "変数名を {1} に変更する",
+	"Replace with %s":                     "{1} に置き換える",
+	"Add #%s comment":                     "#{1} コメントを追加する",
+	"Move statement to %s":                "文を {1} に移動する",
+	"Previous case is here":               "前の case はここです",
+
+	// Interpreter exceptions
+	"Subroutine %s is duplicated":                          "サブルーチン {1} が重複しています",
+	"Backend %s is duplicated":                             "バックエンド {1} が重複しています",
+	"ACL %s is duplicated":                                 "ACL {1} が重複しています",
+	"Table %s is duplicated":                               "テーブル {1} が重複しています",
+	"backend '%s' is not found":                            "バックエンド '{1}' が見つかりません",
+	"No backend determined in PASS":                        "PASS でバックエンドが決定されていません",
+	"No backend determined in MISS":                        "MISS でバックエンドが決定されていません",
+	"If condition returns not boolean":                     "if の条件式が真偽値を返しません",
+	"Failed to parse duration: %s":                         "期間の解析に失敗しました: {1}",
+	"Failed to retrieve backend response: %s":              "バックエンドのレスポンス取得に失敗しました: {1}",
+	"Failed to create backend request: %s":                 "バックエンドへのリクエスト作成に失敗しました: {1}",
+	"Failed to resolve backend host %s: %s":                "バックエンドホスト {1} の名前解決に失敗しました: {2}",
+	"Failed to include VCL module '%s'":                    "VCL モジュール '{1}' の読み込みに失敗しました",
+	"Unexpected infix operator: %s":                        "予期しない中置演算子です: {1}",
+	"Unexpected prefix operator: %s":                       "予期しない前置演算子です: {1}",
+	"synthetic statement is only available in ERROR scope": "synthetic 文は ERROR スコープでのみ使用できます",
+}
@@ -0,0 +1,294 @@
+package imageopto
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Image Optimizer is enabled when this request header is set in vcl_recv
+const ApiHeader = "X-Fastly-Imageopto-Api"
+
+// Fit values
+const (
+	FitBounds = "bounds"
+	FitCover  = "cover"
+	FitCrop   = "crop"
+)
+
+// Size is a width or height parameter.
+// Relative size is a ratio of the original image size which is specified as percentage like "50p" or decimal like "0.5".
+type Size struct {
+	Value    float64
+	Relative bool
+}
+
+// Resolve returns pixel size for the original image size
+func (s *Size) Resolve(original int) int {
+	if s.Relative {
+		return int(float64(original)*s.Value + 0.5)
+	}
+	return int(s.Value)
+}
+
+// Params is the parsed Image Optimizer query parameters which the simulator could apply
+type Params struct {
+	Width   *Size
+	Height  *Size
+	Dpr     float64
+	Fit     string
+	Quality int
+	Format  string
+	Upscale bool
+}
+
+var (
+	hexColor    = regexp.MustCompile(`^(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	orientation = regexp.MustCompile(`^(?:[1-8]|r|l|h|v|hv|vh)$`)
+)
+
+var formats = []string{
+	"auto", "avif", "bjpg", "gif", "jpg", "jxl", "mp4", "pjpg", "pjxl", "png", "png8", "svg", "webp", "webpll", "webply",
+}
+
+// Validators of Image Optimizer query parameters.
+// Parameters which have complex syntax like crop or pad are only checked that the value is not empty.
+// See: https://developer.fastly.com/reference/io/
+var validators = map[string]func(v string) error{
+	"width":  validateSize,
+	"height": validateSize,
+	"dpr": func(v string) error {
+		return validateFloat(v, 1, 10)
+	},
+	"fit": func(v string) error {
+		return validateEnum(v, FitBounds, FitCover, FitCrop)
+	},
+	"quality": func(v string) error {
+		// Quality could specify the fallback quality like "85,65"
+		for _, q := range strings.Split(v, ",") {
+			if err := validateInt(q, 1, 100); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"format": func(v string) error {
+		return validateEnum(v, formats...)
+	},
+	"auto": func(v string) error {
+		for _, f := range strings.Split(v, ",") {
+			if err := validateEnum(f, "avif", "webp"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"orient": func(v string) error {
+		if !orientation.MatchString(v) {
+			return fmt.Errorf("must be one of 1-8, r, l, h, v, hv or vh")
+		}
+		return nil
+	},
+	"blur": func(v string) error {
+		if p, ok := strings.CutSuffix(v, "p"); ok {
+			return validateFloat(p, 0, 100)
+		}
+		return validateFloat(v, 0.5, 1000)
+	},
+	"brightness": func(v string) error {
+		return validateFloat(v, -100, 100)
+	},
+	"contrast": func(v string) error {
+		return validateFloat(v, -100, 100)
+	},
+	"saturation": func(v string) error {
+		return validateFloat(v, -100, 100)
+	},
+	"sharpen": func(v string) error {
+		// Sharpen is specified like "a5,r2,t0" which means amount, radius and threshold
+		spec := strings.Split(v, ",")
+		if len(spec) != 3 {
+			return fmt.Errorf("must be a[amount],r[radius],t[threshold] format")
+		}
+		limits := []struct {
+			prefix   string
+			min, max float64
+		}{
+			{prefix: "a", min: 0, max: 10},
+			{prefix: "r", min: 0.5, max: 1000},
+			{prefix: "t", min: 0, max: 255},
+		}
+		for i, l := range limits {
+			n, ok := strings.CutPrefix(spec[i], l.prefix)
+			if !ok {
+				return fmt.Errorf("must be a[amount],r[radius],t[threshold] format")
+			}
+			if err := validateFloat(n, l.min, l.max); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"bg-color": func(v string) error {
+		if hexColor.MatchString(v) {
+			return nil
+		}
+		rgba := strings.Split(v, ",")
+		if len(rgba) != 3 && len(rgba) != 4 {
+			return fmt.Errorf("must be hex color or r,g,b(,a) format")
+		}
+		for i, c := range rgba {
+			if i == 3 {
+				return validateFloat(c, 0, 1)
+			}
+			if err := validateInt(c, 0, 255); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	"disable": func(v string) error {
+		return validateEnum(v, "upscale")
+	},
+	"enable": func(v string) error {
+		return validateEnum(v, "upscale")
+	},
+	"frame": func(v string) error {
+		return validateEnum(v, "1")
+	},
+	"optimize": func(v string) error {
+		return validateEnum(v, "low", "medium", "high")
+	},
+	"profile": func(v string) error {
+		return validateEnum(v, "baseline", "main", "high")
+	},
+	"resize-filter": func(v string) error {
+		return validateEnum(v, "nearest", "bilinear", "linear", "bicubic", "cubic", "lanczos2", "lanczos3", "lanczos")
+	},
+	"crop":       validateNotEmpty,
+	"precrop":    validateNotEmpty,
+	"pad":        validateNotEmpty,
+	"canvas":     validateNotEmpty,
+	"trim":       validateNotEmpty,
+	"trim-color": validateNotEmpty,
+	"level":      validateNotEmpty,
+	"metadata":   validateNotEmpty,
+}
+
+// IsParameter returns true when the name is Image Optimizer query parameter
+func IsParameter(name string) bool {
+	_, ok := validators[name]
+	return ok
+}
+
+// Validate validates the value of Image Optimizer query parameter.
+// Unknown parameters are always valid because Image Optimizer ignores them.
+func Validate(name, value string) error {
+	fn, ok := validators[name]
+	if !ok {
+		return nil
+	}
+	if err := fn(value); err != nil {
+		return fmt.Errorf("Invalid Image Optimizer parameter %s=%s: %w", name, value, err)
+	}
+	return nil
+}
+
+// ParseQuery validates all Image Optimizer parameters in query and returns parameters to transform the image
+func ParseQuery(query url.Values) (*Params, error) {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := &Params{Dpr: 1, Fit: FitBounds}
+	for _, name := range names {
+		v := query.Get(name)
+		if err := Validate(name, v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		switch name {
+		case "width":
+			p.Width = parseSize(v)
+		case "height":
+			p.Height = parseSize(v)
+		case "dpr":
+			p.Dpr, _ = strconv.ParseFloat(v, 64) // nolint:errcheck
+		case "fit":
+			p.Fit = v
+		case "quality":
+			q, _, _ := strings.Cut(v, ",")
+			p.Quality, _ = strconv.Atoi(q) // nolint:errcheck
+		case "format":
+			p.Format = v
+		case "enable":
+			p.Upscale = true
+		}
+	}
+	return p, nil
+}
+
+func parseSize(v string) *Size {
+	if p, ok := strings.CutSuffix(v, "p"); ok {
+		f, _ := strconv.ParseFloat(p, 64) // nolint:errcheck
+		return &Size{Value: f / 100, Relative: true}
+	}
+	f, _ := strconv.ParseFloat(v, 64) // nolint:errcheck
+	return &Size{Value: f, Relative: f < 1}
+}
+
+func validateSize(v string) error {
+	if p, ok := strings.CutSuffix(v, "p"); ok {
+		return validateFloat(p, 0, 100)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 {
+		return fmt.Errorf("must be pixels, percentage or ratio between 0 and 1")
+	}
+	if f >= 1 && f != float64(int(f)) {
+		return fmt.Errorf("pixels must be an integer")
+	}
+	if f > 8192 {
+		return fmt.Errorf("must be less than or equal to 8192 pixels")
+	}
+	return nil
+}
+
+func validateInt(v string, min, max int) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return fmt.Errorf("must be an integer between %d and %d", min, max)
+	}
+	return nil
+}
+
+func validateFloat(v string, min, max float64) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < min || f > max {
+		return fmt.Errorf("must be a number between %g and %g", min, max)
+	}
+	return nil
+}
+
+func validateEnum(v string, values ...string) error {
+	for i := range values {
+		if v == values[i] {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+}
+
+func validateNotEmpty(v string) error {
+	if v == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package imageopto
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		isError bool
+	}{
+		{name: "width", value: "300"},
+		{name: "width", value: "50p"},
+		{name: "width", value: "0.5"},
+		{name: "width", value: "abc", isError: true},
+		{name: "width", value: "10.5", isError: true},
+		{name: "height", value: "0", isError: true},
+		{name: "dpr", value: "2"},
+		{name: "dpr", value: "11", isError: true},
+		{name: "fit", value: "cover"},
+		{name: "fit", value: "contain", isError: true},
+		{name: "quality", value: "85,65"},
+		{name: "quality", value: "101", isError: true},
+		{name: "format", value: "webp"},
+		{name: "format", value: "tiff", isError: true},
+		{name: "auto", value: "avif,webp"},
+		{name: "orient", value: "hv"},
+		{name: "orient", value: "9", isError: true},
+		{name: "sharpen", value: "a5,r2,t0"},
+		{name: "sharpen", value: "5,2,0", isError: true},
+		{name: "bg-color", value: "fff"},
+		{name: "bg-color", value: "255,0,0,0.5"},
+		{name: "bg-color", value: "256,0,0", isError: true},
+		{name: "enable", value: "upscale"},
+		{name: "crop", value: "", isError: true},
+		{name: "unknown", value: "anything"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.name, tt.value)
+		if tt.isError && err == nil {
+			t.Errorf("%s=%s: expects error but got nil", tt.name, tt.value)
+		} else if !tt.isError && err != nil {
+			t.Errorf("%s=%s: unexpected error: %s", tt.name, tt.value, err)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	q, err := url.ParseQuery("width=50p&height=200&fit=crop&quality=70,50&format=png&enable=upscale&foo=bar")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	p, err := ParseQuery(q)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if p.Width == nil || !p.Width.Relative || p.Width.Value != 0.5 {
+		t.Errorf("Unexpected width: %v", p.Width)
+	}
+	if p.Height == nil || p.Height.Relative || p.Height.Value != 200 {
+		t.Errorf("Unexpected height: %v", p.Height)
+	}
+	if p.Fit != FitCrop || p.Quality != 70 || p.Format != "png" || !p.Upscale || p.Dpr != 1 {
+		t.Errorf("Unexpected params: %+v", p)
+	}
+
+	if _, err := ParseQuery(url.Values{"fit": {"contain"}}); err == nil {
+		t.Errorf("Expects error for invalid parameter")
+	}
+}
@@ -0,0 +1,163 @@
+package imageopto
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Response header which describes the transformation like Fastly Image Optimizer responds
+const InfoHeader = "Fastly-Io-Info"
+
+const defaultQuality = 85
+
+// Transform applies basic transformations to the image response.
+// The simulator supports resizing and fit, and encoding to JPEG, PNG or GIF by the standard image library.
+// Other formats like WebP or AVIF could not be encoded so the original format is kept.
+// Responses which are not decodable images are not modified.
+func Transform(resp *http.Response, p *Params) error {
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(buf))
+
+	src, format, err := image.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil
+	}
+
+	bounds := src.Bounds()
+	width, height, crop := p.dimension(bounds.Dx(), bounds.Dy())
+	dst := resize(src, width, height)
+	if crop != nil {
+		dst = cropCenter(dst, crop.X, crop.Y)
+	}
+
+	output := outputFormat(p.Format, format)
+	var out bytes.Buffer
+	switch output {
+	case "jpeg":
+		quality := p.Quality
+		if quality == 0 {
+			quality = defaultQuality
+		}
+		err = jpeg.Encode(&out, dst, &jpeg.Options{Quality: quality})
+	case "png":
+		err = png.Encode(&out, dst)
+	case "gif":
+		err = gif.Encode(&out, dst, nil)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	size := dst.Bounds().Size()
+	resp.Header.Set("Content-Type", "image/"+output)
+	resp.Header.Set("Content-Length", strconv.Itoa(out.Len()))
+	resp.Header.Set(InfoHeader, fmt.Sprintf(
+		"ifsz=%d idim=%dx%d ifmt=%s ofsz=%d odim=%dx%d ofmt=%s",
+		len(buf), bounds.Dx(), bounds.Dy(), format, out.Len(), size.X, size.Y, output,
+	))
+	resp.ContentLength = int64(out.Len())
+	resp.Body = io.NopCloser(bytes.NewReader(out.Bytes()))
+	return nil
+}
+
+// Calculate resized dimension and the crop size for the original image size.
+// Image is never upscaled unless enable=upscale is specified.
+func (p *Params) dimension(ow, oh int) (int, int, *image.Point) {
+	if p.Width == nil && p.Height == nil && p.Dpr == 1 {
+		return ow, oh, nil
+	}
+
+	width, height := float64(ow), float64(oh)
+	if p.Width != nil {
+		width = float64(p.Width.Resolve(ow))
+	}
+	if p.Height != nil {
+		height = float64(p.Height.Resolve(oh))
+	}
+	width *= p.Dpr
+	height *= p.Dpr
+
+	var crop *image.Point
+	scaleX, scaleY := width/float64(ow), height/float64(oh)
+	scale := scaleX
+	switch {
+	case p.Width == nil:
+		scale = scaleY
+	case p.Height == nil:
+		scale = scaleX
+	case p.Fit == FitCover || p.Fit == FitCrop:
+		scale = max(scaleX, scaleY)
+		if p.Fit == FitCrop {
+			crop = &image.Point{X: int(width + 0.5), Y: int(height + 0.5)}
+		}
+	default:
+		scale = min(scaleX, scaleY)
+	}
+	if scale > 1 && !p.Upscale {
+		scale = 1
+	}
+
+	w, h := int(float64(ow)*scale+0.5), int(float64(oh)*scale+0.5)
+	if crop != nil {
+		crop.X, crop.Y = min(crop.X, w), min(crop.Y, h)
+	}
+	return max(w, 1), max(h, 1), crop
+}
+
+func outputFormat(format, original string) string {
+	switch format {
+	case "jpg", "pjpg", "bjpg":
+		return "jpeg"
+	case "png", "png8":
+		return "png"
+	case "gif":
+		return "gif"
+	}
+	return original
+}
+
+// Resize the image by nearest neighbor, it is enough to verify the image pipeline
+func resize(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/width
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+func cropCenter(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return src
+	}
+	x := bounds.Min.X + (bounds.Dx()-width)/2
+	y := bounds.Min.Y + (bounds.Dy()-height)/2
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for dy := 0; dy < height; dy++ {
+		for dx := 0; dx < width; dx++ {
+			dst.Set(dx, dy, src.At(x+dx, y+dy))
+		}
+	}
+	return dst
+}
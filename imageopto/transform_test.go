@@ -0,0 +1,91 @@
+package imageopto
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func imageResponse(t *testing.T, width, height int) *http.Response {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"image/png"}},
+		Body:       io.NopCloser(&buf),
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		query  string
+		width  int
+		height int
+		format string
+	}{
+		{query: "width=100", width: 100, height: 50, format: "png"},
+		{query: "height=25p", width: 100, height: 50, format: "png"},
+		{query: "width=100&height=100", width: 100, height: 50, format: "png"},
+		{query: "width=100&height=100&fit=cover", width: 200, height: 100, format: "png"},
+		{query: "width=100&height=100&fit=crop", width: 100, height: 100, format: "png"},
+		{query: "width=100&dpr=2", width: 200, height: 100, format: "png"},
+		{query: "width=1000", width: 400, height: 200, format: "png"},
+		{query: "width=800&enable=upscale", width: 800, height: 400, format: "png"},
+		{query: "format=jpg&quality=50", width: 400, height: 200, format: "jpeg"},
+		{query: "format=webp", width: 400, height: 200, format: "png"},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query) // nolint:errcheck
+		p, err := ParseQuery(q)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.query, err)
+			continue
+		}
+		resp := imageResponse(t, 400, 200)
+		if err := Transform(resp, p); err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.query, err)
+			continue
+		}
+		img, format, err := image.Decode(resp.Body)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.query, err)
+			continue
+		}
+		if size := img.Bounds().Size(); size.X != tt.width || size.Y != tt.height {
+			t.Errorf("%s: expects %dx%d but got %dx%d", tt.query, tt.width, tt.height, size.X, size.Y)
+		}
+		if format != tt.format || resp.Header.Get("Content-Type") != "image/"+tt.format {
+			t.Errorf("%s: expects format %s but got %s", tt.query, tt.format, format)
+		}
+		if !strings.HasPrefix(resp.Header.Get(InfoHeader), "ifsz=") {
+			t.Errorf("%s: expects %s header", tt.query, InfoHeader)
+		}
+	}
+
+	t.Run("not an image", func(t *testing.T) {
+		resp := &http.Response{
+			Header: http.Header{},
+			Body:   io.NopCloser(strings.NewReader("plain text")),
+		}
+		if err := Transform(resp, &Params{Dpr: 1}); err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+		if body, _ := io.ReadAll(resp.Body); string(body) != "plain text" { // nolint:errcheck
+			t.Errorf("Body should not be modified but got %s", body)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+// Package acl provides IP address matching of ACL declaration.
+// Entries are stored in the binary prefix trie of each address family, and the IP address is matched
+// with the longest prefix entry like Fastly does, so the negated entry excludes the address from the wider entry.
+package acl
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+type node struct {
+	children [2]*node
+	// Entry which ends at this node, nil if no entry has this prefix
+	entry *ast.AclCidr
+}
+
+// Trie holds ACL entries as prefix trie, matching cost depends on the prefix length instead of the count of entries
+type Trie struct {
+	v4   *node
+	v6   *node
+	size int
+}
+
+// New builds the trie from ACL entries.
+// When entries have the same prefix, the first declared entry is used.
+func New(cidrs []*ast.AclCidr) (*Trie, error) {
+	t := &Trie{
+		v4: &node{},
+		v6: &node{},
+	}
+	for _, cidr := range cidrs {
+		prefix, err := Prefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		t.insert(prefix, cidr)
+	}
+	return t, nil
+}
+
+// Prefix parses the ACL entry as network prefix.
+// Mask is optional, the entry matches the exact address when the mask is omitted.
+func Prefix(cidr *ast.AclCidr) (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(cidr.IP.Value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("Failed to parse IP %s", cidr.IP.Value)
+	}
+	addr = addr.Unmap()
+	bits := addr.BitLen()
+	if cidr.Mask != nil {
+		bits = int(cidr.Mask.Value)
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("Failed to parse CIDR %s/%d", cidr.IP.Value, bits)
+	}
+	return prefix, nil
+}
+
+func (t *Trie) root(addr netip.Addr) *node {
+	if addr.Is4() {
+		return t.v4
+	}
+	return t.v6
+}
+
+func (t *Trie) insert(prefix netip.Prefix, cidr *ast.AclCidr) {
+	addr := prefix.Addr()
+	bytes, offset := addrBytes(addr)
+	n := t.root(addr)
+	for i := 0; i < prefix.Bits(); i++ {
+		b := bit(bytes, offset+i)
+		if n.children[b] == nil {
+			n.children[b] = &node{}
+		}
+		n = n.children[b]
+	}
+	if n.entry == nil {
+		n.entry = cidr
+		t.size++
+	}
+}
+
+// Lookup returns the longest prefix entry which contains the IP address, or nil if no entry contains it
+func (t *Trie) Lookup(ip net.IP) *ast.AclCidr {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return nil
+	}
+	addr = addr.Unmap()
+
+	bytes, offset := addrBytes(addr)
+	n := t.root(addr)
+	matched := n.entry
+	for i := 0; i < addr.BitLen(); i++ {
+		if n = n.children[bit(bytes, offset+i)]; n == nil {
+			break
+		}
+		if n.entry != nil {
+			matched = n.entry
+		}
+	}
+	return matched
+}
+
+// Match returns true if the longest prefix entry which contains the IP address is not negated
+func (t *Trie) Match(ip net.IP) bool {
+	entry := t.Lookup(ip)
+	return entry != nil && (entry.Inverse == nil || !entry.Inverse.Value)
+}
+
+// Len returns the count of unique prefixes in the trie
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// Get 16 bytes representation of the address and bit offset where the address starts.
+// IPv4 address is represented as IPv4-mapped IPv6 address, then the address starts at 96 bits.
+func addrBytes(addr netip.Addr) ([16]byte, int) {
+	if addr.Is4() {
+		return addr.As16(), 96
+	}
+	return addr.As16(), 0
+}
+
+func bit(b [16]byte, i int) int {
+	return int(b[i/8]>>(7-i%8)) & 1
+}
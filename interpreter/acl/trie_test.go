@@ -0,0 +1,207 @@
+package acl
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+func cidr(ip string, mask int64, inverse bool) *ast.AclCidr {
+	c := &ast.AclCidr{
+		IP: &ast.IP{Value: ip},
+	}
+	if mask >= 0 {
+		c.Mask = &ast.Integer{Value: mask}
+	}
+	if inverse {
+		c.Inverse = &ast.Boolean{Value: true}
+	}
+	return c
+}
+
+func TestTrieMatch(t *testing.T) {
+	trie, err := New([]*ast.AclCidr{
+		cidr("192.168.0.0", 16, false),
+		cidr("192.168.100.0", 24, true),
+		cidr("192.168.100.10", -1, false),
+		cidr("10.0.0.1", -1, false),
+		cidr("2001:db8::", 32, false),
+		cidr("2001:db8:ffff::", 48, true),
+		cidr("::1", -1, false),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	tests := []struct {
+		ip     string
+		expect bool
+	}{
+		{ip: "192.168.0.1", expect: true},
+		{ip: "192.168.255.255", expect: true},
+		{ip: "192.169.0.1", expect: false},
+		{ip: "192.168.100.1", expect: false},
+		{ip: "192.168.100.10", expect: true},
+		{ip: "10.0.0.1", expect: true},
+		{ip: "10.0.0.2", expect: false},
+		{ip: "::ffff:192.168.0.1", expect: true},
+		{ip: "::ffff:192.168.100.1", expect: false},
+		{ip: "2001:db8::1", expect: true},
+		{ip: "2001:db8:ffff::1", expect: false},
+		{ip: "2001:db9::1", expect: false},
+		{ip: "::1", expect: true},
+		{ip: "::2", expect: false},
+	}
+
+	for _, tt := range tests {
+		if actual := trie.Match(net.ParseIP(tt.ip)); actual != tt.expect {
+			t.Errorf("Match(%s) expects %t but got %t", tt.ip, tt.expect, actual)
+		}
+	}
+}
+
+func TestTrieDuplicatedPrefix(t *testing.T) {
+	trie, err := New([]*ast.AclCidr{
+		cidr("192.168.0.0", 16, false),
+		cidr("192.168.0.1", 16, true),
+		cidr("::ffff:10.0.0.1", -1, false),
+		cidr("10.0.0.1", 32, false),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if trie.Len() != 2 {
+		t.Errorf("Len() expects 2 but got %d", trie.Len())
+	}
+	if !trie.Match(net.ParseIP("192.168.0.1")) {
+		t.Errorf("First declared entry must be used for the same prefix")
+	}
+	if !trie.Match(net.ParseIP("10.0.0.1")) {
+		t.Errorf("IPv4-mapped entry must match IPv4 address")
+	}
+}
+
+func TestTrieInvalidEntry(t *testing.T) {
+	tests := []*ast.AclCidr{
+		cidr("192.168.0", 24, false),
+		cidr("192.168.0.0", 33, false),
+		cidr("2001:db8::", 129, false),
+	}
+	for _, tt := range tests {
+		if _, err := New([]*ast.AclCidr{tt}); err == nil {
+			t.Errorf("Expected error for entry %s/%d", tt.IP.Value, tt.Mask.Value)
+		}
+	}
+}
+
+// Reference implementation which scans all entries to find the longest prefix
+func linearLookup(prefixes []netip.Prefix, cidrs []*ast.AclCidr, ip net.IP) *ast.AclCidr {
+	addr, _ := netip.AddrFromSlice(ip)
+	addr = addr.Unmap()
+	var matched *ast.AclCidr
+	bits := -1
+	for i, p := range prefixes {
+		if p.Bits() > bits && p.Contains(addr) {
+			matched = cidrs[i]
+			bits = p.Bits()
+		}
+	}
+	return matched
+}
+
+func randomCIDRs(r *rand.Rand, n int) []*ast.AclCidr {
+	cidrs := make([]*ast.AclCidr, n)
+	for i := 0; i < n; i++ {
+		if r.Intn(4) == 0 {
+			ip := make(net.IP, net.IPv6len)
+			r.Read(ip) // nolint:errcheck
+			cidrs[i] = cidr(ip.String(), int64(r.Intn(129)), r.Intn(3) == 0)
+			continue
+		}
+		ip := net.IPv4(byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
+		cidrs[i] = cidr(ip.String(), int64(8+r.Intn(25)), r.Intn(3) == 0)
+	}
+	return cidrs
+}
+
+func randomIP(r *rand.Rand, cidrs []*ast.AclCidr) net.IP {
+	// Pick an address near the entry to have both of matched and unmatched addresses
+	ip := net.ParseIP(cidrs[r.Intn(len(cidrs))].IP.Value)
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	ip[len(ip)-1-r.Intn(3)] ^= byte(r.Intn(256))
+	return ip
+}
+
+func TestTrieMatchesLinearScan(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	cidrs := randomCIDRs(r, 5000)
+	trie, err := New(cidrs)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i := range cidrs {
+		prefixes[i], _ = Prefix(cidrs[i]) // nolint:errcheck
+	}
+
+	for i := 0; i < 10000; i++ {
+		ip := randomIP(r, cidrs)
+		expect := linearLookup(prefixes, cidrs, ip)
+		actual := trie.Lookup(ip)
+		// Compare prefixes because duplicated prefix could be declared
+		if (expect == nil) != (actual == nil) {
+			t.Errorf("Lookup(%s) expects %v but got %v", ip, expect, actual)
+			continue
+		}
+		if expect == nil {
+			continue
+		}
+		ep, _ := Prefix(expect) // nolint:errcheck
+		ap, _ := Prefix(actual) // nolint:errcheck
+		if ep != ap {
+			t.Errorf("Lookup(%s) expects %s but got %s", ip, ep, ap)
+		}
+	}
+}
+
+func BenchmarkTrieMatch(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		r := rand.New(rand.NewSource(1))
+		cidrs := randomCIDRs(r, n)
+		trie, err := New(cidrs)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ips := make([]net.IP, 1024)
+		for i := range ips {
+			ips[i] = randomIP(r, cidrs)
+		}
+
+		b.Run(fmt.Sprintf("trie/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				trie.Match(ips[i%len(ips)])
+			}
+		})
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			prefixes := make([]netip.Prefix, len(cidrs))
+			for i := range cidrs {
+				prefixes[i], _ = Prefix(cidrs[i]) // nolint:errcheck
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				linearLookup(prefixes, cidrs, ips[i%len(ips)])
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/variable"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// VCL which builds strings and manipulates headers like typical edge logic
+const allocationBenchmarkVCL = `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.key STRING;
+  set var.key = req.http.Host + ":" + req.url.path + "?" + req.url.qs;
+  set req.http.X-Key = var.key + ":" + req.http.User-Agent;
+  if (req.url.path ~ "^/api/(v[0-9]+)/") {
+    set req.http.X-Api-Version = re.group.1;
+  }
+  set req.http.X-Forwarded-Host = req.http.Host;
+  set req.http.X-Trace = req.http.X-Trace + "," + server.identity + "," + req.http.X-Api-Version;
+  error 600;
+}
+
+sub vcl_error {
+  #FASTLY ERROR
+  set obj.status = 200;
+  set obj.http.Content-Type = "text/plain";
+  synthetic req.http.X-Key + ":" + req.http.X-Trace;
+  return(deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set resp.http.X-Key = req.http.X-Key;
+  set resp.http.X-Api-Version = req.http.X-Api-Version;
+}
+`
+
+func BenchmarkServe(b *testing.B) {
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", allocationBenchmarkVCL)))
+	ip.Debugger = silentDebugger{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/api/v1/users?page=1", nil)
+		req.Header.Set("User-Agent", "falco-benchmark")
+		ip.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
+
+type silentDebugger struct{}
+
+func (d silentDebugger) Run(node ast.Node) DebugState { return DebugPass }
+func (d silentDebugger) Message(msg string)           {}
+
+func BenchmarkProcessConcatExpression(b *testing.B) {
+	ip := New()
+	ip.ctx = context.New()
+	ip.ctx.Request = httptest.NewRequest(http.MethodGet, "http://localhost/api/v1/users", nil)
+	ip.ctx.Request.Header.Set("User-Agent", "falco-benchmark")
+	ip.vars = variable.NewAllScopeVariables(ip.ctx)
+	ip.SetScope(context.RecvScope)
+
+	vcl, err := parser.New(lexer.NewFromString(`sub vcl_recv {
+  set req.http.X-Key = req.http.Host + ":" + req.url.path + ":" + req.http.User-Agent + ":" + req.method;
+}`)).ParseVCL()
+	if err != nil {
+		b.Fatal(err)
+	}
+	exp := vcl.Statements[0].(*ast.SubroutineDeclaration).Block.Statements[0].(*ast.SetStatement).Value // nolint:errcheck
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ip.ProcessExpression(exp, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
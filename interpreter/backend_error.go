@@ -0,0 +1,106 @@
+package interpreter
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+// Fastly defaults of backend timeouts
+const (
+	defaultConnectTimeout      = 1 * time.Second
+	defaultFirstByteTimeout    = 15 * time.Second
+	defaultBetweenBytesTimeout = 10 * time.Second
+)
+
+// backendError is the backend fetch failure which Fastly handles in vcl_error with 503 status,
+// the response is set to obj.response like "first byte timeout".
+type backendError struct {
+	Response string
+}
+
+func (e *backendError) Error() string {
+	return e.Response
+}
+
+// Classify the backend request error which is caused by timeout
+func timeoutError(err error) *backendError {
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		return nil
+	}
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op == "dial" {
+		return &backendError{Response: "Connection timed out"}
+	}
+	return &backendError{Response: "first byte timeout"}
+}
+
+// Read all of the body, the reading fails when the interval of received bytes exceeds the timeout
+func readBetweenBytes(body io.ReadCloser, timeout time.Duration) ([]byte, error) {
+	if timeout <= 0 {
+		return io.ReadAll(body)
+	}
+
+	type chunk struct {
+		data []byte
+		err  error
+	}
+	chunks := make(chan chunk)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			buf := make([]byte, 32*1024)
+			n, err := body.Read(buf)
+			select {
+			case chunks <- chunk{data: buf[:n], err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var out []byte
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case c := <-chunks:
+			out = append(out, c.data...)
+			if c.err == io.EOF {
+				return out, nil
+			} else if c.err != nil {
+				return nil, c.err
+			}
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+		case <-timer.C:
+			// Close the body in order to unblock the reading goroutine
+			body.Close()
+			return nil, &backendError{Response: "between bytes timeout"}
+		}
+	}
+}
+
+// Backend fetch failure moves to vcl_error with 503 status like Fastly does,
+// so VCL could handle it, for example retrying by restart.
+func (i *Interpreter) processBackendError(response string) error {
+	i.Debugger.Message(fmt.Sprintf("Backend fetch failed: %s", response))
+	i.ctx.BackendResponse = nil
+	i.ctx.ObjectStatus = &value.Integer{Value: http.StatusServiceUnavailable}
+	i.ctx.ObjectResponse = &value.String{Value: response}
+	i.Debugger.Message(fmt.Sprintf("Move state: %s -> ERROR", i.ctx.Scope))
+	return i.ProcessError()
+}
@@ -0,0 +1,95 @@
+package interpreter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestBackendTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Slow") {
+		case "first-byte":
+			time.Sleep(200 * time.Millisecond)
+		case "between-bytes":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("partial")) // nolint:errcheck
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	// Retry once on timeout without slow header
+	vcl := defaultBackend(parsed) + `
+sub vcl_recv {
+  if (req.restarts > 0) {
+    unset req.http.X-Slow;
+  }
+  return (pass);
+}
+
+sub vcl_pass {
+  set bereq.first_byte_timeout = 50ms;
+  set bereq.between_bytes_timeout = 50ms;
+}
+
+sub vcl_error {
+  if (obj.status == 503 && req.http.X-Retry && req.restarts < 1) {
+    restart;
+  }
+  set obj.http.X-Error = obj.response;
+  return (deliver);
+}
+`
+
+	tests := []struct {
+		name   string
+		slow   string
+		retry  bool
+		status int
+		error  string
+	}{
+		{name: "fast origin", status: http.StatusOK},
+		{name: "first byte timeout", slow: "first-byte", status: http.StatusServiceUnavailable, error: "first byte timeout"},
+		{name: "between bytes timeout", slow: "between-bytes", status: http.StatusServiceUnavailable, error: "between bytes timeout"},
+		// Slow header is removed on restart, so the retry succeeds
+		{name: "retry on timeout", slow: "first-byte", retry: true, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+			if tt.slow != "" {
+				req.Header.Set("X-Slow", tt.slow)
+			}
+			if tt.retry {
+				req.Header.Set("X-Retry", "1")
+			}
+
+			ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+			resp, err := ip.Serve(req)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expects status %d but got %d", tt.status, resp.StatusCode)
+			}
+			if v := resp.Header.Get("X-Error"); v != tt.error {
+				t.Errorf("Expects X-Error header %q but got %q", tt.error, v)
+			}
+		})
+	}
+}
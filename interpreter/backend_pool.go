@@ -0,0 +1,116 @@
+package interpreter
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Fastly default of .max_connections backend property
+const defaultMaxConnections = 200
+
+// Response of vcl_error when the backend has no more connections
+const maxConnectionsReached = "Backend.max_conn reached"
+
+// backendPool holds connections of the backend which are shared between requests.
+// Fastly keeps connections to the backend alive and reuses them,
+// and the request fails immediately without waiting when the connections reach .max_connections.
+type backendPool struct {
+	mu         sync.Mutex
+	active     int
+	transports map[transportKey]*http.Transport
+}
+
+// Connections could be reused only when the destination and timeouts are the same
+type transportKey struct {
+	scheme           string
+	addr             string
+	serverName       string
+	connectTimeout   time.Duration
+	firstByteTimeout time.Duration
+}
+
+// Pools of backends, keyed by backend name
+type backendPools struct {
+	mu    sync.Mutex
+	pools map[string]*backendPool
+}
+
+func newBackendPools() *backendPools {
+	return &backendPools{
+		pools: make(map[string]*backendPool),
+	}
+}
+
+func (p *backendPools) get(name string) *backendPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pool, ok := p.pools[name]
+	if !ok {
+		pool = &backendPool{
+			transports: make(map[transportKey]*http.Transport),
+		}
+		p.pools[name] = pool
+	}
+	return pool
+}
+
+// acquire takes a connection, returns false when connections reach the limit
+func (p *backendPool) acquire(max int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active >= max {
+		return false
+	}
+	p.active++
+	return true
+}
+
+func (p *backendPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active > 0 {
+		p.active--
+	}
+}
+
+// transport returns keep-alive transport which dials to the address instead of the host in URL
+func (p *backendPool) transport(key transportKey, max int) *http.Transport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if t, ok := p.transports[key]; ok {
+		return t
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   key.connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	t := &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			_, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, net.JoinHostPort(key.addr, port))
+		},
+		ResponseHeaderTimeout: key.firstByteTimeout,
+		MaxIdleConns:          max,
+		MaxIdleConnsPerHost:   max,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	if key.scheme == HTTPS_SCHEME {
+		t.TLSClientConfig = &tls.Config{
+			ServerName: key.serverName,
+		}
+	}
+	p.transports[key] = t
+	return t
+}
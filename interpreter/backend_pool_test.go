@@ -0,0 +1,94 @@
+package interpreter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestBackendMaxConnections(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	server.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+  .max_connections = 1;
+}
+
+sub vcl_recv {
+  return (pass);
+}
+
+sub vcl_error {
+  set obj.http.X-Error = obj.response;
+  return (deliver);
+}
+`, parsed.Hostname(), parsed.Port())
+
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	serve := func() *http.Response {
+		resp, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			t.FailNow()
+		}
+		return resp
+	}
+
+	t.Run("connection is reused", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			if resp := serve(); resp.StatusCode != http.StatusOK {
+				t.Errorf("Expects status 200 but got %d", resp.StatusCode)
+			}
+		}
+		if v := atomic.LoadInt32(&connections); v != 1 {
+			t.Errorf("Expects one connection is kept alive but %d connections are opened", v)
+		}
+	})
+
+	t.Run("saturated backend responds 503", func(t *testing.T) {
+		// Simulate in-flight request which uses the only connection
+		pool := ip.backends.get("example")
+		if !pool.acquire(1) {
+			t.Errorf("Connection must be acquired")
+			return
+		}
+		resp := serve()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expects status 503 but got %d", resp.StatusCode)
+		}
+		if v := resp.Header.Get("X-Error"); v != maxConnectionsReached {
+			t.Errorf("Expects X-Error header %q but got %q", maxConnectionsReached, v)
+		}
+
+		pool.release()
+		if resp := serve(); resp.StatusCode != http.StatusOK {
+			t.Errorf("Expects status 200 after the connection is released but got %d", resp.StatusCode)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package interpreter
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// sharedBody is the HTTP body which is read from immutable bytes.
+// Response is cloned at each state transition like vcl_fetch to vcl_deliver or cache hit,
+// and the body bytes are never modified in place, so cloned responses could share the same bytes
+// without copying them. Only the read position is owned by each body.
+type sharedBody struct {
+	*bytes.Reader
+	data []byte
+}
+
+func newSharedBody(data []byte) *sharedBody {
+	return &sharedBody{
+		Reader: bytes.NewReader(data),
+		data:   data,
+	}
+}
+
+func (b *sharedBody) Close() error {
+	return nil
+}
+
+// Clone the body which reads from the beginning.
+// If the body is not a shared body, all bytes are read once and the body is replaced with shared one,
+// so the following clones do not copy the bytes.
+func cloneBody(body *io.ReadCloser) io.ReadCloser {
+	if *body == nil {
+		return newSharedBody(nil)
+	}
+	if b, ok := (*body).(*sharedBody); ok {
+		return newSharedBody(b.data)
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(*body) // nolint: errcheck
+	(*body).Close()
+	*body = newSharedBody(buf.Bytes())
+	return newSharedBody(buf.Bytes())
+}
+
+// Read all bytes of the body, the body is replaced with shared one so that it could be read again.
+func readBody(body *io.ReadCloser) []byte {
+	if *body == nil || *body == http.NoBody {
+		return nil
+	}
+	cloneBody(body)
+	return (*body).(*sharedBody).data
+}
@@ -0,0 +1,37 @@
+package interpreter
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCloneBody(t *testing.T) {
+	var body io.ReadCloser = io.NopCloser(strings.NewReader("response body"))
+	first := cloneBody(&body)
+	second := cloneBody(&body)
+
+	// Original body is replaced with shared body in order not to read the bytes again
+	original, ok := body.(*sharedBody)
+	if !ok {
+		t.Errorf("Original body is not replaced with shared body: %T", body)
+		return
+	}
+	// Reading cloned body does not affect others
+	for _, b := range []io.ReadCloser{first, second, original} {
+		buf, err := io.ReadAll(b)
+		if err != nil {
+			t.Errorf("Unexpected read error: %s", err)
+			return
+		}
+		if diff := cmp.Diff("response body", string(buf)); diff != "" {
+			t.Errorf("Body mismatch, diff=%s", diff)
+		}
+	}
+	// Cloned bodies share the same bytes
+	if &first.(*sharedBody).data[0] != &original.data[0] { // nolint:errcheck
+		t.Errorf("Cloned body copies the bytes")
+	}
+}
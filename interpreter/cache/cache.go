@@ -2,6 +2,8 @@
 package cache
 
 import (
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,15 +18,38 @@ type CacheItem struct {
 	Response  *http.Response
 	Expires   time.Time
 	EntryTime time.Time
+	Grace     time.Duration
 	Hits      int
 	LastUsed  time.Duration
+	// Age of the response when it is stored, taken from Age header of the backend response
+	InitialAge           time.Duration
+	StaleWhileRevalidate time.Duration
 
 	// private
 	requestedTime time.Time
 }
 
+// Update sets remaining lifetime of the object, which is modified via obj.ttl
 func (i *CacheItem) Update(d time.Duration) {
-	i.Expires = i.EntryTime.Add(d)
+	i.Expires = time.Now().Add(d)
+}
+
+// TTL returns remaining lifetime of the object
+func (i *CacheItem) TTL() time.Duration {
+	if d := time.Until(i.Expires); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Entered returns elapsed time since the object is stored in the cache
+func (i *CacheItem) Entered() time.Duration {
+	return time.Since(i.EntryTime)
+}
+
+// Age returns the age of the object, includes the age when the response is stored
+func (i *CacheItem) Age() time.Duration {
+	return i.InitialAge + i.Entered()
 }
 
 type Cache struct {
@@ -63,6 +88,63 @@ func (c *Cache) Get(hash string) *CacheItem {
 	return item
 }
 
+// Range calls fn for each cached item which is not expired, stops iteration when fn returns false.
+// Unlike Get, Range does not update hit count and last used time of the item.
+func (c *Cache) Range(fn func(hash string, item *CacheItem) bool) {
+	now := time.Now()
+	c.storage.Range(func(key, v any) bool {
+		hash, ok := key.(string)
+		if !ok {
+			return true
+		}
+		item, ok := v.(*CacheItem)
+		if !ok || now.After(item.Expires) {
+			return true
+		}
+		return fn(hash, item)
+	})
+}
+
+// Inspect returns cached item which is not expired.
+// Unlike Get, Inspect does not update hit count and last used time of the item.
+func (c *Cache) Inspect(hash string) *CacheItem {
+	v, ok := c.storage.Load(hash)
+	if !ok {
+		return nil
+	}
+	item, ok := v.(*CacheItem)
+	if !ok || time.Now().After(item.Expires) {
+		return nil
+	}
+	return item
+}
+
+// Purge evicts cached item, returns true if the item is found
+func (c *Cache) Purge(hash string) bool {
+	_, ok := c.storage.LoadAndDelete(hash)
+	return ok
+}
+
+// PurgeAll evicts all cached items and returns the number of evicted items
+func (c *Cache) PurgeAll() int {
+	var purged int
+	c.storage.Range(func(key, v any) bool {
+		c.storage.Delete(key)
+		purged++
+		return true
+	})
+	return purged
+}
+
+// ParseAge parses Age header value in seconds, returns zero for invalid value
+func ParseAge(v string) time.Duration {
+	sec, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || sec < 0 {
+		return 0
+	}
+	return time.Duration(sec) * time.Second
+}
+
 // Fastly follows its own cache freshness rules
 // see: https://developer.fastly.com/learning/concepts/cache-freshness/
 var unCacheableStatusCodes = []int{200, 203, 300, 301, 302, 404, 410}
@@ -1,17 +1,24 @@
 package context
 
 import (
+	"net"
 	"net/http"
 	"time"
+	"unsafe"
 
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/config"
 	"github.com/ysugimoto/falco/interpreter/cache"
+	"github.com/ysugimoto/falco/interpreter/datafile"
+	"github.com/ysugimoto/falco/interpreter/ratelimit"
 	"github.com/ysugimoto/falco/interpreter/value"
 	"github.com/ysugimoto/falco/resolver"
 	"github.com/ysugimoto/falco/snippets"
 )
 
+// ServiceBackend sends the backend request to another simulated service and returns its client response
+type ServiceBackend func(*http.Request) (*http.Response, error)
+
 // Reserved vcl names in Fastly
 const (
 	FastlyVclNameRecv    = "vcl_recv"
@@ -50,6 +57,7 @@ type Context struct {
 	Subroutines         map[string]*ast.SubroutineDeclaration
 	Penaltyboxes        map[string]*ast.PenaltyboxDeclaration
 	Ratecounters        map[string]*ast.RatecounterDeclaration
+	RateLimit           *ratelimit.Store
 	Gotos               map[string]*ast.GotoStatement
 	SubroutineFunctions map[string]*ast.SubroutineDeclaration
 	OriginalHost        string
@@ -59,11 +67,36 @@ type Context struct {
 	OverrideRequest     *config.RequestConfig
 	OverrideBackends    map[string]*config.OverrideBackend
 
-	Request          *http.Request
-	BackendRequest   *http.Request
-	BackendResponse  *http.Response
-	Object           *http.Response
-	Response         *http.Response
+	// Add debug headers which summarize the process to the simulator response
+	DebugResponseHeaders bool
+	// Record time-travel execution log to the process summary
+	TimeTravel bool
+	// Record why the response is cached or not to the process summary
+	ExplainCache bool
+	// Table and ACL data which are reloaded from files
+	DataFiles *datafile.Watcher
+	// Tables which items are replaced by data files, keyed by table name
+	DataTables map[string]datafile.Table
+	// Hashed index of declared table items which is built on the first lookup
+	tableIndexes map[*ast.TableDeclaration]map[string]ast.Expression
+	// Backends which are served by other simulated services in-process
+	ServiceBackends map[string]ServiceBackend
+	// Map of hostname and pinned addresses which are used instead of DNS resolution
+	DNSOverrides map[string][]string
+	// Parsed main VCL which is used instead of parsing the main VCL of the resolver
+	VCL *ast.VCL
+	// Cache store which is shared with other interpreters
+	Cache *cache.Cache
+
+	Request         *http.Request
+	BackendRequest  *http.Request
+	BackendResponse *http.Response
+	// Resolved addresses of the backend host, the first one is connected
+	BackendAddrs []net.IP
+	Object       *http.Response
+	Response     *http.Response
+	// Header maps which are shared between HTTP objects and the number of objects which hold the map
+	sharedHeaders    map[unsafe.Pointer]int
 	Scope            Scope
 	RequestEndTime   time.Time
 	RequestStartTime time.Time
@@ -138,6 +171,8 @@ type Context struct {
 	ReturnState     *value.String
 	FixedTime       *time.Time
 	SubroutineCalls map[string]int
+	// Rendered lines of log statements which are executed
+	EmittedLogs []string
 
 	// Regex captured values like "re.group.N" and local declared variables are volatile,
 	// reset this when process is outgoing for each subroutines
@@ -155,13 +190,26 @@ type Context struct {
 }
 
 func New(options ...Option) *Context {
-	ctx := &Context{
+	ctx := newContext(newValueSlab())
+
+	// collect options
+	for i := range options {
+		options[i](ctx)
+	}
+
+	return ctx
+}
+
+// Create the context which values are allocated from the slab
+func newContext(s *valueSlab) *Context {
+	return &Context{
 		Acls:                make(map[string]*value.Acl),
 		Backends:            make(map[string]*value.Backend),
 		Tables:              make(map[string]*ast.TableDeclaration),
 		Subroutines:         make(map[string]*ast.SubroutineDeclaration),
 		Penaltyboxes:        make(map[string]*ast.PenaltyboxDeclaration),
 		Ratecounters:        make(map[string]*ast.RatecounterDeclaration),
+		RateLimit:           ratelimit.New(),
 		Gotos:               make(map[string]*ast.GotoStatement),
 		SubroutineFunctions: make(map[string]*ast.SubroutineDeclaration),
 		OverrideBackends:    make(map[string]*config.OverrideBackend),
@@ -171,74 +219,75 @@ func New(options ...Option) *Context {
 		State:                               "NONE",
 		Backend:                             nil,
 		ClientIdentity:                      nil,
-		MaxStaleIfError:                     &value.RTime{Value: defaultStaleDuration},
-		MaxStaleWhileRevalidate:             &value.RTime{Value: defaultStaleDuration},
-		Stale:                               &value.Boolean{},
-		StaleIsError:                        &value.Boolean{},
-		StaleIsRevalidating:                 &value.Boolean{},
-		StaleContents:                       &value.String{},
-		FastlyError:                         &value.String{},
-		ClientGeoIpOverride:                 &value.String{},
-		ClientSocketCongestionAlgorithm:     &value.String{Value: "cubic"},
-		ClientSocketCwnd:                    &value.Integer{Value: 60},
-		ClientSocketPace:                    &value.Integer{},
-		EsiAllowInsideCData:                 &value.Boolean{},
-		EnableRangeOnPass:                   &value.Boolean{},
-		EnableSegmentedCaching:              &value.Boolean{},
-		EnableSSI:                           &value.Boolean{},
-		HashAlwaysMiss:                      &value.Boolean{},
-		HashIgnoreBusy:                      &value.Boolean{},
-		SegmentedCacheingBlockSize:          &value.Integer{},
-		ESILevel:                            &value.Integer{},
-		RequestHash:                         &value.String{},
-		WafAnomalyScore:                     &value.Integer{},
-		WafBlocked:                          &value.Boolean{},
-		WafCounter:                          &value.Integer{},
-		WafExecuted:                         &value.Boolean{},
-		WafHttpViolationScore:               &value.Integer{},
-		WafInbouldAnomalyScore:              &value.Integer{},
-		WafLFIScore:                         &value.Integer{},
-		WafLogData:                          &value.String{},
-		WafLogged:                           &value.Boolean{},
-		WafMessage:                          &value.String{},
-		WafPassed:                           &value.Boolean{},
-		WafRFIScore:                         &value.Integer{},
-		WafRuleId:                           &value.Integer{},
-		WafSesionFixationScore:              &value.Integer{},
-		WafSeverity:                         &value.Integer{},
-		WafXSSScore:                         &value.Integer{},
-		BetweenBytesTimeout:                 &value.RTime{},
-		ConnectTimeout:                      &value.RTime{},
-		FirstByteTimeout:                    &value.RTime{Value: 15 * time.Second},
-		BackendResponseGzip:                 &value.Boolean{},
-		BackendResponseBrotli:               &value.Boolean{},
-		BackendResponseCacheable:            &value.Boolean{},
-		BackendResponseDoESI:                &value.Boolean{},
-		BackendResponseDoStream:             &value.Boolean{},
-		BackendResponseGrace:                &value.RTime{},
-		BackendResponseHipaa:                &value.Boolean{},
-		BackendResponsePCI:                  &value.Boolean{},
-		BackendResponseResponse:             &value.String{},
-		BackendResponseSaintMode:            &value.RTime{},
-		BackendResponseStaleIfError:         &value.RTime{},
-		BackendResponseStaleWhileRevalidate: &value.RTime{},
-		BackendResponseStatus:               &value.Integer{},
-		BackendResponseTTL:                  &value.RTime{},
-		ObjectGrace:                         &value.RTime{},
-		ObjectTTL:                           &value.RTime{},
-		ObjectStatus:                        &value.Integer{Value: 500},
-		ObjectResponse:                      &value.String{Value: "error"},
+		MaxStaleIfError:                     s.rtime(defaultStaleDuration),
+		MaxStaleWhileRevalidate:             s.rtime(defaultStaleDuration),
+		Stale:                               s.boolean(false),
+		StaleIsError:                        s.boolean(false),
+		StaleIsRevalidating:                 s.boolean(false),
+		StaleContents:                       s.string(""),
+		FastlyError:                         s.string(""),
+		ClientGeoIpOverride:                 s.string(""),
+		ClientSocketCongestionAlgorithm:     s.string("cubic"),
+		ClientSocketCwnd:                    s.integer(60),
+		ClientSocketPace:                    s.integer(0),
+		EsiAllowInsideCData:                 s.boolean(false),
+		EnableRangeOnPass:                   s.boolean(false),
+		EnableSegmentedCaching:              s.boolean(false),
+		EnableSSI:                           s.boolean(false),
+		HashAlwaysMiss:                      s.boolean(false),
+		HashIgnoreBusy:                      s.boolean(false),
+		SegmentedCacheingBlockSize:          s.integer(0),
+		ESILevel:                            s.integer(0),
+		RequestHash:                         s.string(""),
+		WafAnomalyScore:                     s.integer(0),
+		WafBlocked:                          s.boolean(false),
+		WafCounter:                          s.integer(0),
+		WafExecuted:                         s.boolean(false),
+		WafHttpViolationScore:               s.integer(0),
+		WafInbouldAnomalyScore:              s.integer(0),
+		WafLFIScore:                         s.integer(0),
+		WafLogData:                          s.string(""),
+		WafLogged:                           s.boolean(false),
+		WafMessage:                          s.string(""),
+		WafPassed:                           s.boolean(false),
+		WafRFIScore:                         s.integer(0),
+		WafRuleId:                           s.integer(0),
+		WafSesionFixationScore:              s.integer(0),
+		WafSeverity:                         s.integer(0),
+		WafXSSScore:                         s.integer(0),
+		BetweenBytesTimeout:                 s.rtime(0),
+		ConnectTimeout:                      s.rtime(0),
+		FirstByteTimeout:                    s.rtime(15 * time.Second),
+		BackendResponseGzip:                 s.boolean(false),
+		BackendResponseBrotli:               s.boolean(false),
+		BackendResponseCacheable:            s.boolean(false),
+		BackendResponseDoESI:                s.boolean(false),
+		BackendResponseDoStream:             s.boolean(false),
+		BackendResponseGrace:                s.rtime(0),
+		BackendResponseHipaa:                s.boolean(false),
+		BackendResponsePCI:                  s.boolean(false),
+		BackendResponseResponse:             s.string(""),
+		BackendResponseSaintMode:            s.rtime(0),
+		BackendResponseStaleIfError:         s.rtime(0),
+		BackendResponseStaleWhileRevalidate: s.rtime(0),
+		BackendResponseStatus:               s.integer(0),
+		BackendResponseTTL:                  s.rtime(0),
+		ObjectGrace:                         s.rtime(0),
+		ObjectTTL:                           s.rtime(0),
+		ObjectStatus:                        s.integer(500),
+		ObjectResponse:                      s.string("error"),
 		ReturnState:                         &value.String{IsNotSet: true},
-		IsLocallyGenerated:                  &value.Boolean{},
+		IsLocallyGenerated:                  s.boolean(false),
 
 		RegexMatchedValues: make(map[string]*value.String),
 		SubroutineCalls:    make(map[string]int),
 	}
+}
 
-	// collect options
-	for i := range options {
-		options[i](ctx)
+// Now returns the fixed time in testing, otherwise the current time
+func (c *Context) Now() time.Time {
+	if c.FixedTime != nil {
+		return *c.FixedTime
 	}
-
-	return ctx
+	return time.Now()
 }
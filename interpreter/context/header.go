@@ -0,0 +1,58 @@
+package context
+
+import (
+	"net/http"
+	"reflect"
+	"unsafe"
+)
+
+// Header maps of HTTP objects are shared on state transitions instead of being copied,
+// e.g. the backend request is created from the client request, and the client response is created
+// from the backend response or the cached object. Shared map is copied on the first modification,
+// so the code which modifies header of HTTP objects must get the header via WritableHeader.
+
+// Key of the header map, pointer of the map is used because the map is not comparable
+func headerKey(h http.Header) unsafe.Pointer {
+	return reflect.ValueOf(h).UnsafePointer()
+}
+
+// ShareHeader returns the header map to be held by the new HTTP object without copying it.
+func (c *Context) ShareHeader(h http.Header) http.Header {
+	if h == nil {
+		return nil
+	}
+	if c.sharedHeaders == nil {
+		c.sharedHeaders = make(map[unsafe.Pointer]int)
+	}
+	key := headerKey(h)
+	if n, ok := c.sharedHeaders[key]; ok {
+		c.sharedHeaders[key] = n + 1
+	} else {
+		// The map is held by the origin object and the new object
+		c.sharedHeaders[key] = 2
+	}
+	return h
+}
+
+// WritableHeader returns the header map of the HTTP object which could be modified.
+// When the map is shared with other objects, it is copied and replaced with the copy so that
+// the modification does not affect other objects.
+func (c *Context) WritableHeader(h *http.Header) http.Header {
+	if *h == nil {
+		*h = http.Header{}
+		return *h
+	}
+	key := headerKey(*h)
+	n, ok := c.sharedHeaders[key]
+	if !ok {
+		return *h
+	}
+	// Remaining object becomes the only holder of the map, then it could be modified in place
+	if n <= 2 {
+		delete(c.sharedHeaders, key)
+	} else {
+		c.sharedHeaders[key] = n - 1
+	}
+	*h = h.Clone()
+	return *h
+}
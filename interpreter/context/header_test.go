@@ -0,0 +1,70 @@
+package context
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWritableHeader(t *testing.T) {
+	t.Run("shared header is copied on the first write", func(t *testing.T) {
+		ctx := New()
+		origin := http.Header{"X-Origin": {"1"}}
+		shared := ctx.ShareHeader(origin)
+
+		h := ctx.WritableHeader(&shared)
+		h.Set("X-Shared", "1")
+		if origin.Get("X-Shared") != "" {
+			t.Errorf("Origin header must not be modified")
+		}
+		if shared.Get("X-Shared") != "1" || shared.Get("X-Origin") != "1" {
+			t.Errorf("Copied header must be modified, got=%v", shared)
+		}
+
+		// Origin becomes the only holder of the map so it is modified in place
+		before := headerKey(origin)
+		ctx.WritableHeader(&origin).Set("X-Origin", "2")
+		if headerKey(origin) != before {
+			t.Errorf("Header which is held by only one object must not be copied")
+		}
+		if shared.Get("X-Origin") != "1" {
+			t.Errorf("Copied header must not be modified, got=%v", shared)
+		}
+	})
+
+	t.Run("header shared by three objects", func(t *testing.T) {
+		ctx := New()
+		origin := http.Header{"X-Origin": {"1"}}
+		first := ctx.ShareHeader(origin)
+		second := ctx.ShareHeader(origin)
+
+		ctx.WritableHeader(&first).Set("X-First", "1")
+		before := headerKey(second)
+		ctx.WritableHeader(&second).Set("X-Second", "1")
+		if headerKey(second) == before {
+			t.Errorf("Header which is still shared with origin must be copied")
+		}
+		ctx.WritableHeader(&origin).Set("X-Origin", "2")
+
+		if first.Get("X-Second") != "" || second.Get("X-First") != "" {
+			t.Errorf("Modifications must not be shared, first=%v, second=%v", first, second)
+		}
+		if origin.Get("X-First") != "" || origin.Get("X-Second") != "" {
+			t.Errorf("Origin header must not be modified, got=%v", origin)
+		}
+		if first.Get("X-Origin") != "1" || second.Get("X-Origin") != "1" {
+			t.Errorf("Copied headers must not be modified by origin, first=%v, second=%v", first, second)
+		}
+	})
+
+	t.Run("nil header", func(t *testing.T) {
+		ctx := New()
+		var h http.Header
+		if ctx.ShareHeader(h) != nil {
+			t.Errorf("nil header must be shared as nil")
+		}
+		ctx.WritableHeader(&h).Set("X-Header", "1")
+		if h.Get("X-Header") != "1" {
+			t.Errorf("nil header must be initialized on write")
+		}
+	})
+}
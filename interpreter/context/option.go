@@ -1,7 +1,10 @@
 package context
 
 import (
+	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter/cache"
+	"github.com/ysugimoto/falco/interpreter/datafile"
 	"github.com/ysugimoto/falco/resolver"
 	"github.com/ysugimoto/falco/snippets"
 )
@@ -49,3 +52,51 @@ func WithOverrideHost(host string) Option {
 		c.OriginalHost = host
 	}
 }
+
+func WithDebugResponseHeaders() Option {
+	return func(c *Context) {
+		c.DebugResponseHeaders = true
+	}
+}
+
+func WithTimeTravel() Option {
+	return func(c *Context) {
+		c.TimeTravel = true
+	}
+}
+
+func WithExplainCache() Option {
+	return func(c *Context) {
+		c.ExplainCache = true
+	}
+}
+
+func WithDataFiles(w *datafile.Watcher) Option {
+	return func(c *Context) {
+		c.DataFiles = w
+	}
+}
+
+func WithServiceBackends(backends map[string]ServiceBackend) Option {
+	return func(c *Context) {
+		c.ServiceBackends = backends
+	}
+}
+
+func WithDNSOverrides(hosts map[string][]string) Option {
+	return func(c *Context) {
+		c.DNSOverrides = hosts
+	}
+}
+
+func WithVCL(vcl *ast.VCL) Option {
+	return func(c *Context) {
+		c.VCL = vcl
+	}
+}
+
+func WithCache(store *cache.Cache) Option {
+	return func(c *Context) {
+		c.Cache = store
+	}
+}
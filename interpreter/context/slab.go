@@ -0,0 +1,69 @@
+package context
+
+import (
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+// valueSlab allocates boxed values of the context in chunks in order to reduce allocations per request.
+// Each value is still referenced by its own pointer, so that it could be modified or replaced individually.
+type valueSlab struct {
+	booleans []value.Boolean
+	integers []value.Integer
+	strings  []value.String
+	rtimes   []value.RTime
+}
+
+// Capacities of the slab are measured by creating the context once,
+// so that they always match the counts of values which the context allocates.
+var slabCapacity = measureValueSlab()
+
+type valueSlabCapacity struct {
+	booleans int
+	integers int
+	strings  int
+	rtimes   int
+}
+
+func measureValueSlab() valueSlabCapacity {
+	s := &valueSlab{}
+	newContext(s)
+	return valueSlabCapacity{
+		booleans: len(s.booleans),
+		integers: len(s.integers),
+		strings:  len(s.strings),
+		rtimes:   len(s.rtimes),
+	}
+}
+
+func newValueSlab() *valueSlab {
+	return &valueSlab{
+		booleans: make([]value.Boolean, 0, slabCapacity.booleans),
+		integers: make([]value.Integer, 0, slabCapacity.integers),
+		strings:  make([]value.String, 0, slabCapacity.strings),
+		rtimes:   make([]value.RTime, 0, slabCapacity.rtimes),
+	}
+}
+
+// Note that values which have been returned are kept valid even if append grows the slice,
+// because they point to the previous backing array which is not released while referenced.
+func (s *valueSlab) boolean(v bool) *value.Boolean {
+	s.booleans = append(s.booleans, value.Boolean{Value: v})
+	return &s.booleans[len(s.booleans)-1]
+}
+
+func (s *valueSlab) integer(v int64) *value.Integer {
+	s.integers = append(s.integers, value.Integer{Value: v})
+	return &s.integers[len(s.integers)-1]
+}
+
+func (s *valueSlab) string(v string) *value.String {
+	s.strings = append(s.strings, value.String{Value: v})
+	return &s.strings[len(s.strings)-1]
+}
+
+func (s *valueSlab) rtime(v time.Duration) *value.RTime {
+	s.rtimes = append(s.rtimes, value.RTime{Value: v})
+	return &s.rtimes[len(s.rtimes)-1]
+}
@@ -0,0 +1,44 @@
+package context
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValueSlab(t *testing.T) {
+	s := newValueSlab()
+	ctx := newContext(s)
+
+	// Collect pointers which are held by the context fields
+	held := make(map[uintptr]int)
+	rv := reflect.ValueOf(ctx).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		if f.Kind() == reflect.Ptr && !f.IsNil() {
+			held[f.Pointer()]++
+		}
+	}
+
+	slabs := []struct {
+		name     string
+		values   reflect.Value
+		capacity int
+	}{
+		{name: "booleans", values: reflect.ValueOf(s.booleans), capacity: slabCapacity.booleans},
+		{name: "integers", values: reflect.ValueOf(s.integers), capacity: slabCapacity.integers},
+		{name: "strings", values: reflect.ValueOf(s.strings), capacity: slabCapacity.strings},
+		{name: "rtimes", values: reflect.ValueOf(s.rtimes), capacity: slabCapacity.rtimes},
+	}
+	for _, slab := range slabs {
+		if slab.values.Len() != slab.capacity || slab.values.Cap() != slab.capacity {
+			t.Errorf("Slab of %s must be allocated at once, capacity=%d, len=%d, cap=%d",
+				slab.name, slab.capacity, slab.values.Len(), slab.values.Cap())
+		}
+		// Each value in the slab must be held by exactly one field
+		for i := 0; i < slab.values.Len(); i++ {
+			if n := held[slab.values.Index(i).Addr().Pointer()]; n != 1 {
+				t.Errorf("Value %d of %s slab is held by %d fields", i, slab.name, n)
+			}
+		}
+	}
+}
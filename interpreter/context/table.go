@@ -0,0 +1,44 @@
+package context
+
+import (
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/token"
+)
+
+// LookupTable finds the item of the table by key.
+// If the table is replaced by the data file, the item is looked up from the data file,
+// otherwise hashed index of declared items is built on the first lookup in order to look up items in constant time.
+// Note that the index must be reset by ResetTableIndex when table items are modified.
+func (c *Context) LookupTable(table *ast.TableDeclaration, key string) (ast.Expression, bool) {
+	if table.Name != nil {
+		if t, ok := c.DataTables[table.Name.Value]; ok {
+			v, found := t.Lookup(key)
+			if !found {
+				return nil, false
+			}
+			return &ast.String{Meta: ast.New(token.Null, 1), Value: v}, true
+		}
+	}
+
+	if c.tableIndexes == nil {
+		c.tableIndexes = make(map[*ast.TableDeclaration]map[string]ast.Expression)
+	}
+	index, ok := c.tableIndexes[table]
+	if !ok {
+		index = make(map[string]ast.Expression, len(table.Properties))
+		for _, prop := range table.Properties {
+			// First item wins like linear search when the key is duplicated
+			if _, ok := index[prop.Key.Value]; !ok {
+				index[prop.Key.Value] = prop.Value
+			}
+		}
+		c.tableIndexes[table] = index
+	}
+	v, ok := index[key]
+	return v, ok
+}
+
+// ResetTableIndex discards the index of the table which items are modified
+func (c *Context) ResetTableIndex(table *ast.TableDeclaration) {
+	delete(c.tableIndexes, table)
+}
@@ -0,0 +1,13 @@
+package interpreter
+
+import (
+	"github.com/ysugimoto/falco/crash"
+)
+
+// Re-panic with the statement being processed, so that the crash report could tell where the interpreter crashes.
+// This function must be deferred at the entry points of the interpreter.
+func (i *Interpreter) wrapPanic() {
+	if r := recover(); r != nil {
+		panic(crash.Wrap(r, i.statement))
+	}
+}
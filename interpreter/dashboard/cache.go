@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/cache"
+)
+
+// CachePath is the path of cache inspection API
+const CachePath = Prefix + "api/cache"
+
+type CacheEntry struct {
+	Hash       string            `json:"hash"`
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	EntryTime  time.Time         `json:"entry_time"`
+	Expires    time.Time         `json:"expires"`
+	// Remaining TTL and grace in seconds
+	TTL   float64 `json:"ttl"`
+	Grace float64 `json:"grace"`
+	Hits  int     `json:"hits"`
+	// Response body is only included on inspecting single entry
+	Body *string `json:"body,omitempty"`
+}
+
+func newCacheEntry(hash string, item *cache.CacheItem, now time.Time) *CacheEntry {
+	entry := &CacheEntry{
+		Hash:      hash,
+		Headers:   make(map[string]string),
+		EntryTime: item.EntryTime,
+		Expires:   item.Expires,
+		TTL:       item.Expires.Sub(now).Seconds(),
+		Grace:     item.Grace.Seconds(),
+		Hits:      item.Hits,
+	}
+	if item.Response != nil {
+		entry.StatusCode = item.Response.StatusCode
+		for key, values := range item.Response.Header {
+			entry.Headers[strings.ToLower(key)] = strings.Join(values, ", ")
+		}
+	}
+	return entry
+}
+
+func cacheEntries(c *cache.Cache) []*CacheEntry {
+	entries := []*CacheEntry{}
+	now := time.Now()
+	c.Range(func(hash string, item *cache.CacheItem) bool {
+		entries = append(entries, newCacheEntry(hash, item, now))
+		return true
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].EntryTime.After(entries[j].EntryTime)
+	})
+	return entries
+}
+
+// CacheHandler returns http.Handler which inspects and manipulates simulator cache:
+//   - GET    : list cache entries, or inspect single entry if "hash" query is provided
+//   - DELETE : purge the entry of "hash" query, or purge all entries if "hash" query is not provided
+func CacheHandler(c *cache.Cache) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		hash := query.Get("hash")
+
+		switch r.Method {
+		case http.MethodGet:
+			if !query.Has("hash") {
+				writeJSON(w, http.StatusOK, cacheEntries(c))
+				return
+			}
+			item := c.Inspect(hash)
+			if item == nil {
+				writeError(w, http.StatusNotFound, "Cache entry is not found")
+				return
+			}
+			entry := newCacheEntry(hash, item, time.Now())
+			if item.Response != nil && item.Response.Body != nil {
+				// rewind body reader
+				var buf bytes.Buffer
+				buf.ReadFrom(item.Response.Body) // nolint: errcheck
+				item.Response.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
+				body := buf.String()
+				entry.Body = &body
+			}
+			writeJSON(w, http.StatusOK, entry)
+		case http.MethodDelete:
+			if !query.Has("hash") {
+				writeJSON(w, http.StatusOK, map[string]int{"purged": c.PurgeAll()})
+				return
+			}
+			if !c.Purge(hash) {
+				writeError(w, http.StatusNotFound, "Cache entry is not found")
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]int{"purged": 1})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/cache"
+)
+
+func serveCache(c *cache.Cache, method, hash string) *httptest.ResponseRecorder {
+	target := CachePath
+	if hash != "" {
+		target += "?hash=" + url.QueryEscape(hash)
+	}
+	rec := httptest.NewRecorder()
+	CacheHandler(c).ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestCacheHandler(t *testing.T) {
+	c := cache.New()
+	now := time.Now()
+	for _, hash := range []string{"/foolocalhost", "/barlocalhost"} {
+		c.Set(hash, &cache.CacheItem{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("body of " + hash)),
+			},
+			Expires:   now.Add(time.Minute),
+			EntryTime: now,
+			Grace:     10 * time.Second,
+		})
+	}
+
+	t.Run("list entries", func(t *testing.T) {
+		var entries []*CacheEntry
+		json.Unmarshal(serveCache(c, http.MethodGet, "").Body.Bytes(), &entries) // nolint:errcheck
+		if len(entries) != 2 {
+			t.Errorf("Expected 2 entries, got %d", len(entries))
+		}
+		for _, e := range entries {
+			if e.Body != nil || e.TTL <= 0 || e.Grace != 10 {
+				t.Errorf("Unexpected entry: %+v", e)
+			}
+		}
+	})
+
+	t.Run("inspect entry", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			var entry CacheEntry
+			json.Unmarshal(serveCache(c, http.MethodGet, "/foolocalhost").Body.Bytes(), &entry) // nolint:errcheck
+			if entry.Body == nil || *entry.Body != "body of /foolocalhost" {
+				t.Errorf("Body should be readable repeatedly, got %v", entry.Body)
+			}
+			if entry.Hits != 0 || entry.Headers["content-type"] != "text/plain" {
+				t.Errorf("Unexpected entry: %+v", entry)
+			}
+		}
+		if rec := serveCache(c, http.MethodGet, "/notfound"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("purge entries", func(t *testing.T) {
+		if rec := serveCache(c, http.MethodDelete, "/foolocalhost"); rec.Code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", rec.Code)
+		}
+		if c.Inspect("/foolocalhost") != nil {
+			t.Errorf("Entry should be purged")
+		}
+		if rec := serveCache(c, http.MethodDelete, "/foolocalhost"); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for purged entry, got %d", rec.Code)
+		}
+		rec := serveCache(c, http.MethodDelete, "")
+		if strings.TrimSpace(rec.Body.String()) != `{"purged":1}` {
+			t.Errorf("Unexpected purge all response: %s", rec.Body.String())
+		}
+		if c.Inspect("/barlocalhost") != nil {
+			t.Errorf("All entries should be purged")
+		}
+	})
+}
@@ -0,0 +1,337 @@
+package dashboard
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/interpreter"
+)
+
+// Prefix is the path prefix which dashboard is served on the simulator server
+const Prefix = "/_falco/"
+
+const defaultHistorySize = 100
+
+//go:embed assets/index.html
+var indexHTML []byte
+
+// Record is a request which is processed by the simulator
+type Record struct {
+	ID         int64             `json:"id"`
+	ReplayOf   int64             `json:"replay_of,omitempty"`
+	Time       time.Time         `json:"time"`
+	Method     string            `json:"method"`
+	Host       string            `json:"host"`
+	URL        string            `json:"url"`
+	Headers    map[string]string `json:"headers"`
+	StatusCode int               `json:"status_code"`
+	// Process summary JSON which the simulator responds
+	Summary json.RawMessage `json:"summary,omitempty"`
+
+	header http.Header
+	body   []byte
+}
+
+// ReplayRequest is a payload to replay the recorded request with edited values.
+// Empty fields are filled by the recorded request, and headers replace recorded headers entirely.
+type ReplayRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+type Option func(d *Dashboard)
+
+func WithHistorySize(size int) Option {
+	return func(d *Dashboard) {
+		if size > 0 {
+			d.size = size
+		}
+	}
+}
+
+// Dashboard is a web UI for the simulator which shows recent requests,
+// their state transitions, cache contents, backends and ratecounters,
+// and replays the request with edited headers.
+type Dashboard struct {
+	ip      *interpreter.Interpreter
+	size    int
+	nextID  int64
+	records []*Record
+	// Interpreter could not process requests concurrently,
+	// so the lock serializes processing and also guards records
+	mu sync.Mutex
+}
+
+func New(ip *interpreter.Interpreter, opts ...Option) *Dashboard {
+	d := &Dashboard{
+		ip:   ip,
+		size: defaultHistorySize,
+	}
+	for i := range opts {
+		opts[i](d)
+	}
+	return d
+}
+
+// Simulator returns http.Handler which processes the request by the interpreter and records it
+func (d *Dashboard) Simulator() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, out := d.process(r, body, 0)
+		for key, values := range out.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(record.StatusCode)
+		w.Write(out.body.Bytes()) // nolint:errcheck
+	})
+}
+
+// Captured simulator response
+type captured struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (c *captured) Header() http.Header {
+	return c.header
+}
+
+func (c *captured) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	return c.body.Write(b)
+}
+
+func (c *captured) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+func (d *Dashboard) process(r *http.Request, body []byte, replayOf int64) (*Record, *captured) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := &captured{header: http.Header{}}
+	d.ip.ServeHTTP(out, r)
+	if out.status == 0 {
+		out.status = http.StatusOK
+	}
+
+	d.nextID++
+	record := &Record{
+		ID:         d.nextID,
+		ReplayOf:   replayOf,
+		Time:       time.Now(),
+		Method:     r.Method,
+		Host:       r.Host,
+		URL:        r.URL.RequestURI(),
+		Headers:    make(map[string]string),
+		StatusCode: out.status,
+		header:     r.Header.Clone(),
+		body:       body,
+	}
+	for key, values := range r.Header {
+		record.Headers[strings.ToLower(key)] = strings.Join(values, ", ")
+	}
+	if json.Valid(out.body.Bytes()) {
+		record.Summary = json.RawMessage(out.body.Bytes())
+	}
+
+	d.records = append(d.records, record)
+	if len(d.records) > d.size {
+		d.records = d.records[len(d.records)-d.size:]
+	}
+	return record, out
+}
+
+func (d *Dashboard) find(id int64) *Record {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, r := range d.records {
+		if r.ID == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// Replay processes the recorded request again with edited values
+func (d *Dashboard) Replay(id int64, rr *ReplayRequest) (*Record, error) {
+	record := d.find(id)
+	if record == nil {
+		return nil, errors.Errorf("Request #%d is not found", id)
+	}
+
+	method := record.Method
+	if rr.Method != "" {
+		method = rr.Method
+	}
+	uri := record.URL
+	if rr.URL != "" {
+		uri = rr.URL
+	}
+	req, err := http.NewRequest(method, "http://"+record.Host+uri, bytes.NewReader(record.body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Host = record.Host
+	if rr.Headers != nil {
+		for key, val := range rr.Headers {
+			req.Header.Set(key, val)
+		}
+	} else {
+		req.Header = record.header.Clone()
+	}
+
+	replayed, _ := d.process(req, record.body, id)
+	return replayed, nil
+}
+
+// ServeHTTP serves dashboard UI and API under the Prefix
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, Prefix), "/")
+	segments := strings.Split(path, "/")
+
+	switch {
+	case path == "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexHTML) // nolint:errcheck
+	case path == "api/requests":
+		d.mu.Lock()
+		records := make([]*Record, len(d.records))
+		// Newest first
+		for i := range d.records {
+			records[len(d.records)-1-i] = d.records[i]
+		}
+		d.mu.Unlock()
+		writeJSON(w, http.StatusOK, records)
+	case len(segments) >= 3 && segments[0] == "api" && segments[1] == "requests":
+		d.serveRequest(w, r, segments[2:])
+	case path == "api/cache":
+		CacheHandler(d.ip.Cache()).ServeHTTP(w, r)
+	case path == "api/backends":
+		writeJSON(w, http.StatusOK, d.backends())
+	case path == "api/ratecounters":
+		writeJSON(w, http.StatusOK, d.ratecounters())
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (d *Dashboard) serveRequest(w http.ResponseWriter, r *http.Request, segments []string) {
+	id, err := strconv.ParseInt(segments[0], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request id "+segments[0])
+		return
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		record := d.find(id)
+		if record == nil {
+			writeError(w, http.StatusNotFound, "Request is not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, record)
+	case len(segments) == 2 && segments[1] == "replay" && r.Method == http.MethodPost:
+		rr := &ReplayRequest{}
+		if err := json.NewDecoder(r.Body).Decode(rr); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, "Invalid replay request: "+err.Error())
+			return
+		}
+		record, err := d.Replay(id, rr)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, record)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+type BackendStatus struct {
+	Name    string `json:"name"`
+	Host    string `json:"host"`
+	Healthy bool   `json:"healthy"`
+}
+
+func (d *Dashboard) backends() []*BackendStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	backends := []*BackendStatus{}
+	for name, b := range d.ip.Backends() {
+		status := &BackendStatus{Name: name, Healthy: true}
+		if b.Healthy != nil {
+			status.Healthy = b.Healthy.Load()
+		}
+		if b.Value != nil {
+			for _, p := range b.Value.Properties {
+				if p.Key.Value == "host" {
+					status.Host = strings.Trim(p.Value.String(), `"`)
+				}
+			}
+		}
+		backends = append(backends, status)
+	}
+	sort.Slice(backends, func(i, j int) bool {
+		return backends[i].Name < backends[j].Name
+	})
+	return backends
+}
+
+type RatecounterStatus struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (d *Dashboard) ratecounters() []*RatecounterStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ratecounters := []*RatecounterStatus{}
+	for name, rc := range d.ip.Ratecounters() {
+		token := rc.GetMeta().Token
+		ratecounters = append(ratecounters, &RatecounterStatus{
+			Name: name,
+			File: token.File,
+			Line: token.Line,
+		})
+	}
+	sort.Slice(ratecounters, func(i, j int) bool {
+		return ratecounters[i].Name < ratecounters[j].Name
+	})
+	return ratecounters
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) // nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
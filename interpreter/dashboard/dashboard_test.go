@@ -0,0 +1,137 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func newTestDashboard(t *testing.T) (*Dashboard, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Test server URL parsing error: %s", err)
+	}
+
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+ratecounter counter {}
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.X-Pass) {
+    return (pass);
+  }
+  return (lookup);
+}
+`, parsed.Hostname(), parsed.Port())
+
+	ip := interpreter.New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	return New(ip, WithHistorySize(2)), server.Close
+}
+
+func get[T any](t *testing.T, d *Dashboard, path string) T {
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Prefix+path, nil))
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("Failed to decode %s response: %s", path, err)
+	}
+	return v
+}
+
+type summary struct {
+	CacheAction string `json:"cache_action"`
+}
+
+func cacheAction(r *Record) string {
+	var s summary
+	json.Unmarshal(r.Summary, &s) // nolint:errcheck
+	return s.CacheAction
+}
+
+func TestDashboard(t *testing.T) {
+	d, teardown := newTestDashboard(t)
+	defer teardown()
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		d.Simulator().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Simulator should respond 200, got %d", rec.Code)
+		}
+	}
+
+	records := get[[]*Record](t, d, "api/requests")
+	if len(records) != 2 {
+		t.Fatalf("Only latest 2 requests should be recorded, got %d", len(records))
+	}
+	if records[0].ID != 3 || cacheAction(records[0]) != "HIT" {
+		t.Errorf("Latest request should be served from cache, got id=%d, cache=%s", records[0].ID, cacheAction(records[0]))
+	}
+
+	entries := get[[]*CacheEntry](t, d, "api/cache")
+	if len(entries) != 1 || entries[0].Hits != 2 {
+		t.Errorf("Cache should have one entry with 2 hits, got %v", entries)
+	}
+	backends := get[[]*BackendStatus](t, d, "api/backends")
+	if len(backends) != 1 || backends[0].Name != "example" || !backends[0].Healthy {
+		t.Errorf("Unexpected backends: %v", backends)
+	}
+	ratecounters := get[[]*RatecounterStatus](t, d, "api/ratecounters")
+	if len(ratecounters) != 1 || ratecounters[0].Name != "counter" {
+		t.Errorf("Unexpected ratecounters: %v", ratecounters)
+	}
+
+	t.Run("replay with edited headers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest(
+			http.MethodPost,
+			Prefix+"api/requests/3/replay",
+			strings.NewReader(`{"headers": {"X-Pass": "1"}}`),
+		))
+		var replayed Record
+		if err := json.Unmarshal(rec.Body.Bytes(), &replayed); err != nil {
+			t.Fatalf("Failed to decode replay response: %s", err)
+		}
+		if replayed.ReplayOf != 3 || replayed.URL != "/foo" || replayed.Headers["x-pass"] != "1" {
+			t.Errorf("Unexpected replayed request: %+v", replayed)
+		}
+		if action := cacheAction(&replayed); action != "PASS" {
+			t.Errorf("Replayed request should pass, got %s", action)
+		}
+	})
+
+	t.Run("replay request which is not found", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Prefix+"api/requests/1/replay", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for evicted request, got %d", rec.Code)
+		}
+	})
+
+	t.Run("serve UI", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Prefix, nil))
+		if !strings.Contains(rec.Body.String(), "falco simulator dashboard") {
+			t.Errorf("Dashboard UI should be served")
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/exception"
+	"github.com/ysugimoto/falco/interpreter/value"
+	"github.com/ysugimoto/falco/token"
+)
+
+// Apply table and ACL data which are loaded from files.
+// Data files replace items of the declared table and entries of the declared ACL,
+// or declare them if not declared in VCL like Fastly generates declarations for edge dictionaries and ACLs.
+func (i *Interpreter) applyDataFiles() error {
+	if i.ctx.DataFiles == nil {
+		return nil
+	}
+
+	i.ctx.DataTables = i.ctx.DataFiles.Tables()
+	for name := range i.ctx.DataTables {
+		table, ok := i.ctx.Tables[name]
+		if !ok {
+			table = &ast.TableDeclaration{
+				Meta: ast.New(token.Null, 0),
+				Name: &ast.Ident{Meta: ast.New(token.Null, 0), Value: name},
+			}
+		} else if table.ValueType != nil && table.ValueType.Value != "STRING" {
+			return exception.Runtime(
+				&table.Token,
+				"Table %s is %s type but data file could only be applied to STRING table",
+				name, table.ValueType.Value,
+			)
+		}
+		// Items are looked up from the data file directly instead of building declaration properties,
+		// so huge data files are not copied for each request.
+		// Copy declaration in order not to modify parsed AST
+		i.ctx.Tables[name] = &ast.TableDeclaration{
+			Meta:      table.Meta,
+			Name:      table.Name,
+			ValueType: table.ValueType,
+		}
+	}
+
+	for name, cidrs := range i.ctx.DataFiles.Acls() {
+		decl := &ast.AclDeclaration{
+			Meta:  ast.New(token.Null, 0),
+			Name:  &ast.Ident{Meta: ast.New(token.Null, 0), Value: name},
+			CIDRs: cidrs,
+		}
+		if acl, ok := i.ctx.Acls[name]; ok {
+			decl.Meta = acl.Value.Meta
+			decl.Name = acl.Value.Name
+		}
+		i.ctx.Acls[name] = &value.Acl{Value: decl, Literal: true}
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package datafile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/token"
+)
+
+const defaultInterval = time.Second
+
+// Data kinds
+const (
+	KindTable = "table"
+	KindAcl   = "acl"
+)
+
+// Event is reported when the data file is reloaded
+type Event struct {
+	Kind  string
+	Name  string
+	File  string
+	Error error
+}
+
+func (e *Event) String() string {
+	if e.Error != nil {
+		return fmt.Sprintf("Failed to reload %s %s from %s, previous data is kept: %s", e.Kind, e.Name, e.File, e.Error)
+	}
+	return fmt.Sprintf("Reloaded %s %s from %s", e.Kind, e.Name, e.File)
+}
+
+type Option func(w *Watcher)
+
+func WithInterval(d time.Duration) Option {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.interval = d
+		}
+	}
+}
+
+func WithReporter(fn func(*Event)) Option {
+	return func(w *Watcher) {
+		w.onReload = fn
+	}
+}
+
+type entry struct {
+	kind    string
+	name    string
+	file    string
+	modTime time.Time
+	table   Table
+	acl     []*ast.AclCidr
+}
+
+// Watcher loads table and ACL data files and reloads them when files are modified.
+// Reloaded data is applied to following requests immediately like Fastly edge dictionaries and ACLs,
+// which are updated without activating a new service version.
+type Watcher struct {
+	entries  []*entry
+	interval time.Duration
+	onReload func(*Event)
+	mu       sync.RWMutex
+}
+
+// New loads all data files, returns an error if any file is invalid on startup
+func New(c *config.DataFilesConfig, opts ...Option) (*Watcher, error) {
+	w := &Watcher{
+		interval: defaultInterval,
+	}
+	for i := range opts {
+		opts[i](w)
+	}
+
+	if c != nil {
+		for name, file := range c.Tables {
+			w.entries = append(w.entries, &entry{kind: KindTable, name: name, file: file})
+		}
+		for name, file := range c.Acls {
+			w.entries = append(w.entries, &entry{kind: KindAcl, name: name, file: file})
+		}
+	}
+	sort.Slice(w.entries, func(i, j int) bool {
+		if w.entries[i].kind != w.entries[j].kind {
+			return w.entries[i].kind > w.entries[j].kind
+		}
+		return w.entries[i].name < w.entries[j].name
+	})
+
+	for _, e := range w.entries {
+		if err := e.load(); err != nil {
+			return nil, errors.WithStack(fmt.Errorf("Failed to load %s %s from %s: %w", e.kind, e.name, e.file, err))
+		}
+	}
+	return w, nil
+}
+
+// Watch polls modification of data files until the context is canceled
+func (w *Watcher) Watch(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Reload()
+		}
+	}
+}
+
+// Reload reloads modified data files.
+// When the file is invalid, previous data is kept and the error is reported.
+func (w *Watcher) Reload() {
+	for _, e := range w.entries {
+		stat, err := os.Stat(e.file)
+		if err == nil && stat.ModTime().Equal(e.modTime) {
+			continue
+		}
+
+		next := &entry{kind: e.kind, name: e.name, file: e.file}
+		if err == nil {
+			err = next.load()
+		}
+		if err == nil {
+			w.mu.Lock()
+			*e = *next
+			w.mu.Unlock()
+		} else if stat != nil {
+			// Avoid reporting the same error until the file is modified again
+			e.modTime = stat.ModTime()
+		}
+
+		if w.onReload != nil {
+			w.onReload(&Event{Kind: e.kind, Name: e.name, File: e.file, Error: err})
+		}
+	}
+}
+
+// Tables returns current table items
+func (w *Watcher) Tables() map[string]Table {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	tables := make(map[string]Table)
+	for _, e := range w.entries {
+		if e.kind == KindTable {
+			tables[e.name] = e.table
+		}
+	}
+	return tables
+}
+
+// Acls returns current ACL entries
+func (w *Watcher) Acls() map[string][]*ast.AclCidr {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	acls := make(map[string][]*ast.AclCidr)
+	for _, e := range w.entries {
+		if e.kind == KindAcl {
+			acls[e.name] = e.acl
+		}
+	}
+	return acls
+}
+
+func (e *entry) load() error {
+	stat, err := os.Stat(e.file)
+	if err != nil {
+		return err
+	}
+	if e.kind == KindTable && isIndexedTableFile(e.file) {
+		if e.table, err = LoadIndexedTable(e.file); err != nil {
+			return err
+		}
+		e.modTime = stat.ModTime()
+		return nil
+	}
+
+	buf, err := os.ReadFile(e.file)
+	if err != nil {
+		return err
+	}
+
+	switch e.kind {
+	case KindTable:
+		var items map[string]string
+		items, err = ParseTable(buf)
+		e.table = MapTable(items)
+	case KindAcl:
+		e.acl, err = ParseAcl(buf)
+	}
+	if err != nil {
+		return err
+	}
+	e.modTime = stat.ModTime()
+	return nil
+}
+
+// ParseTable parses table data from JSON object.
+// Scalar values are stored as string because edge dictionary only has string values.
+func ParseTable(buf []byte) (map[string]string, error) {
+	var data map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("Table data must be a JSON object: %w", err)
+	}
+
+	table := make(map[string]string, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			table[key] = v
+		case json.Number:
+			table[key] = v.String()
+		case bool:
+			table[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("Value of %s must be a string, number or boolean", key)
+		}
+	}
+	return table, nil
+}
+
+// ParseAcl parses ACL entries from JSON array of string like ["192.168.0.0/16", "!192.168.100.1"]
+func ParseAcl(buf []byte) ([]*ast.AclCidr, error) {
+	var data []string
+	if err := json.Unmarshal(buf, &data); err != nil {
+		return nil, fmt.Errorf("ACL data must be a JSON array of string: %w", err)
+	}
+
+	cidrs := make([]*ast.AclCidr, len(data))
+	for i, v := range data {
+		cidr := &ast.AclCidr{Meta: ast.New(token.Null, 1)}
+		if strings.HasPrefix(v, "!") {
+			cidr.Inverse = &ast.Boolean{Meta: ast.New(token.Null, 1), Value: true}
+			v = v[1:]
+		}
+		ip, mask, found := strings.Cut(v, "/")
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("Invalid IP address %s in ACL entry", ip)
+		}
+		cidr.IP = &ast.IP{Meta: ast.New(token.Null, 1), Value: ip}
+		if found {
+			m, err := strconv.ParseInt(mask, 10, 64)
+			if err != nil || m < 0 || m > 128 {
+				return nil, fmt.Errorf("Invalid mask %s in ACL entry", mask)
+			}
+			cidr.Mask = &ast.Integer{Meta: ast.New(token.Null, 1), Value: m}
+		}
+		cidrs[i] = cidr
+	}
+	return cidrs, nil
+}
@@ -0,0 +1,117 @@
+package datafile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+)
+
+func writeFile(t *testing.T, file, data string, modTime time.Time) {
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %s", err)
+	}
+	// Change modification time explicitly because file system timestamp resolution may be coarse
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatalf("Failed to change modification time: %s", err)
+	}
+}
+
+func TestParseAcl(t *testing.T) {
+	cidrs, err := ParseAcl([]byte(`["192.168.0.0/16", "!192.168.100.1", "2001:db8::/32"]`))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	var actual []string
+	for _, c := range cidrs {
+		actual = append(actual, strings.TrimSpace(c.String()))
+	}
+	expect := []string{`"192.168.0.0"/16;`, `!"192.168.100.1";`, `"2001:db8::"/32;`}
+	if diff := cmp.Diff(expect, actual); diff != "" {
+		t.Errorf("ACL entries mismatch, diff=%s", diff)
+	}
+
+	for _, invalid := range []string{`{}`, `["example.com"]`, `["192.168.0.0/abc"]`, `["192.168.0.0/129"]`} {
+		if _, err := ParseAcl([]byte(invalid)); err == nil {
+			t.Errorf("Expected error for %s", invalid)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	tableFile := filepath.Join(dir, "redirects.json")
+	aclFile := filepath.Join(dir, "internal.json")
+	now := time.Now()
+	writeFile(t, tableFile, `{"/old": "/new", "/count": 10}`, now)
+	writeFile(t, aclFile, `["10.0.0.0/8"]`, now)
+
+	c := &config.DataFilesConfig{
+		Tables: map[string]string{"redirects": tableFile},
+		Acls:   map[string]string{"internal": aclFile},
+	}
+
+	t.Run("invalid file on startup", func(t *testing.T) {
+		_, err := New(&config.DataFilesConfig{
+			Tables: map[string]string{"notfound": filepath.Join(dir, "notfound.json")},
+		})
+		if err == nil {
+			t.Errorf("Expected error for not found file")
+		}
+	})
+
+	var events []*Event
+	w, err := New(c, WithReporter(func(e *Event) {
+		events = append(events, e)
+	}))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if diff := cmp.Diff(MapTable{"/old": "/new", "/count": "10"}, w.Tables()["redirects"]); diff != "" {
+		t.Errorf("Table items mismatch, diff=%s", diff)
+	}
+	if len(w.Acls()["internal"]) != 1 {
+		t.Errorf("ACL entries should be loaded, got %v", w.Acls())
+	}
+
+	t.Run("not modified", func(t *testing.T) {
+		w.Reload()
+		if len(events) != 0 {
+			t.Errorf("Unmodified files should not be reloaded, got %v", events)
+		}
+	})
+
+	t.Run("reload modified file", func(t *testing.T) {
+		writeFile(t, tableFile, `{"/old": "/newer"}`, now.Add(time.Second))
+		w.Reload()
+		if len(events) != 1 || events[0].Name != "redirects" || events[0].Error != nil {
+			t.Errorf("Unexpected reload events: %v", events)
+		}
+		if diff := cmp.Diff(MapTable{"/old": "/newer"}, w.Tables()["redirects"]); diff != "" {
+			t.Errorf("Table items mismatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("keep previous data on invalid file", func(t *testing.T) {
+		events = nil
+		writeFile(t, aclFile, `["invalid"]`, now.Add(2*time.Second))
+		w.Reload()
+		if len(events) != 1 || events[0].Name != "internal" || events[0].Error == nil {
+			t.Errorf("Unexpected reload events: %v", events)
+		}
+		if len(w.Acls()["internal"]) != 1 {
+			t.Errorf("Previous ACL entries should be kept, got %v", w.Acls())
+		}
+		// Same error is not reported until the file is modified again
+		w.Reload()
+		if len(events) != 1 {
+			t.Errorf("Same error should not be reported repeatedly, got %v", events)
+		}
+	})
+}
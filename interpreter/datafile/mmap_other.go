@@ -0,0 +1,16 @@
+//go:build !unix
+
+package datafile
+
+import (
+	"os"
+)
+
+// Memory mapping is not supported on the platform, read all data instead
+func mapFile(file string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+package datafile
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// Map the file to memory as read-only, returns the function which releases the mapped data.
+// The file could be overwritten in place while the data is mapped, then reading pages beyond the new end of the file
+// raises SIGBUS which crashes the process, and other pages return torn data.
+// So the private copy of the file is mapped instead, which is removed immediately and never modified.
+func mapFile(file string) ([]byte, func() error, error) {
+	src, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer src.Close()
+
+	fp, err := os.CreateTemp("", "falco-datafile-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fp.Close()
+	// Mapped data is still available after the file is removed
+	os.Remove(fp.Name()) // nolint:errcheck
+
+	size, err := io.Copy(fp, src)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Empty file could not be mapped
+	if size == 0 {
+		return nil, func() error { return nil }, nil
+	}
+	data, err := syscall.Mmap(int(fp.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,146 @@
+package datafile
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Table is the items of table data file, values are always string like edge dictionary
+type Table interface {
+	Lookup(key string) (string, bool)
+	Len() int
+}
+
+// MapTable is the table which is parsed from JSON object
+type MapTable map[string]string
+
+func (t MapTable) Lookup(key string) (string, bool) {
+	v, ok := t[key]
+	return v, ok
+}
+
+func (t MapTable) Len() int {
+	return len(t)
+}
+
+// IndexedTable is the table which is parsed from TSV file that each line is "key<TAB>value".
+// Keys and values are not copied to the heap, the table only holds hashed index of line offsets in the raw data,
+// and the raw data is memory-mapped from the private copy of the file where it is supported.
+// So huge edge dictionaries do not dominate the memory of simulator.
+type IndexedTable struct {
+	data []byte
+	// Open addressing hash slots which hold line offset + 1, zero means empty slot
+	slots []uint32
+	size  int
+}
+
+// LoadIndexedTable maps the TSV file and builds hashed index.
+// Mapped data is released when the table is garbage collected,
+// because requests in process may still look up the table after the file is reloaded.
+func LoadIndexedTable(file string) (*IndexedTable, error) {
+	data, release, err := mapFile(file)
+	if err != nil {
+		return nil, err
+	}
+	t, err := NewIndexedTable(data)
+	if err != nil {
+		release() // nolint:errcheck
+		return nil, err
+	}
+	runtime.SetFinalizer(t, func(*IndexedTable) {
+		release() // nolint:errcheck
+	})
+	return t, nil
+}
+
+// NewIndexedTable builds hashed index of TSV data.
+// Empty lines and lines which start with "#" are ignored.
+func NewIndexedTable(data []byte) (*IndexedTable, error) {
+	if len(data) >= math.MaxUint32 {
+		return nil, fmt.Errorf("Table data is too large, must be less than %d bytes", uint32(math.MaxUint32))
+	}
+
+	var offsets []uint32
+	for offset, line := 0, 1; offset < len(data); line++ {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			end = len(data) - offset
+		}
+		row := data[offset : offset+end]
+		if len(bytes.TrimSpace(row)) > 0 && row[0] != '#' {
+			if bytes.IndexByte(row, '\t') < 0 {
+				return nil, fmt.Errorf("Line %d must be TAB separated key and value", line)
+			}
+			offsets = append(offsets, uint32(offset))
+		}
+		offset += end + 1
+	}
+
+	// Keep load factor under 0.5 so that probing sequence is short
+	size := 1
+	for size < len(offsets)*2 {
+		size <<= 1
+	}
+	t := &IndexedTable{
+		data:  data,
+		slots: make([]uint32, size),
+	}
+	for _, offset := range offsets {
+		key, _ := t.row(offset)
+		slot, found := t.find(key)
+		if found {
+			return nil, fmt.Errorf("Key %s is duplicated", key)
+		}
+		t.slots[slot] = offset + 1
+		t.size++
+	}
+	return t, nil
+}
+
+// Get key and value of the line which starts at the offset, value is trimmed carriage return
+func (t *IndexedTable) row(offset uint32) ([]byte, []byte) {
+	line := t.data[offset:]
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	key, value, _ := bytes.Cut(line, []byte{'\t'})
+	return key, bytes.TrimSuffix(value, []byte{'\r'})
+}
+
+// Find the slot of the key, returns empty slot to insert if not found
+func (t *IndexedTable) find(key []byte) (int, bool) {
+	h := fnv.New64a()
+	h.Write(key) // nolint:errcheck
+	mask := len(t.slots) - 1
+	for slot := int(h.Sum64()) & mask; ; slot = (slot + 1) & mask {
+		if t.slots[slot] == 0 {
+			return slot, false
+		}
+		if k, _ := t.row(t.slots[slot] - 1); bytes.Equal(k, key) {
+			return slot, true
+		}
+	}
+}
+
+func (t *IndexedTable) Lookup(key string) (string, bool) {
+	slot, found := t.find([]byte(key))
+	if !found {
+		return "", false
+	}
+	_, value := t.row(t.slots[slot] - 1)
+	return string(value), true
+}
+
+func (t *IndexedTable) Len() int {
+	return t.size
+}
+
+// Table data is TSV when the file has .tsv extension, otherwise JSON object
+func isIndexedTableFile(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".tsv")
+}
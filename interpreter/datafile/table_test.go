@@ -0,0 +1,105 @@
+package datafile
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIndexedTable(t *testing.T) {
+	table, err := NewIndexedTable([]byte("# redirects\n/old\t/new\r\n\n/empty\t\n/tab\ta\tb\n/last\tvalue"))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if table.Len() != 4 {
+		t.Errorf("Table should have 4 items, got %d", table.Len())
+	}
+
+	tests := []struct {
+		key   string
+		value string
+		found bool
+	}{
+		{key: "/old", value: "/new", found: true},
+		{key: "/empty", value: "", found: true},
+		{key: "/tab", value: "a\tb", found: true},
+		{key: "/last", value: "value", found: true},
+		{key: "# redirects"},
+		{key: "/notfound"},
+	}
+	for _, tt := range tests {
+		v, found := table.Lookup(tt.key)
+		if v != tt.value || found != tt.found {
+			t.Errorf("Lookup(%q) expects (%q, %t) but got (%q, %t)", tt.key, tt.value, tt.found, v, found)
+		}
+	}
+
+	for _, invalid := range []string{"/old /new", "/old\t/new\n/old\t/newer"} {
+		if _, err := NewIndexedTable([]byte(invalid)); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestLoadIndexedTable(t *testing.T) {
+	const size = 100000
+
+	var data strings.Builder
+	for i := 0; i < size; i++ {
+		data.WriteString(fmt.Sprintf("/path/%d\t/redirect/%d\n", i, i))
+	}
+	file := filepath.Join(t.TempDir(), "redirects.tsv")
+	writeFile(t, file, data.String(), time.Now())
+
+	table, err := LoadIndexedTable(file)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if table.Len() != size {
+		t.Errorf("Table should have %d items, got %d", size, table.Len())
+	}
+	for _, i := range []int{0, size / 2, size - 1} {
+		if v, _ := table.Lookup(fmt.Sprintf("/path/%d", i)); v != fmt.Sprintf("/redirect/%d", i) {
+			t.Errorf("Unexpected value for /path/%d: %q", i, v)
+		}
+	}
+	if _, found := table.Lookup(fmt.Sprintf("/path/%d", size)); found {
+		t.Errorf("Item which is not in the file should not be found")
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.tsv")
+	writeFile(t, empty, "", time.Now())
+	if table, err := LoadIndexedTable(empty); err != nil || table.Len() != 0 {
+		t.Errorf("Empty file should be loaded as empty table, got %v, %v", table, err)
+	}
+}
+
+func TestLoadIndexedTableOverwrittenInPlace(t *testing.T) {
+	const size = 100000
+
+	var data strings.Builder
+	for i := 0; i < size; i++ {
+		data.WriteString(fmt.Sprintf("/path/%d\t/redirect/%d\n", i, i))
+	}
+	file := filepath.Join(t.TempDir(), "redirects.tsv")
+	writeFile(t, file, data.String(), time.Now())
+
+	table, err := LoadIndexedTable(file)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	// Truncate and overwrite the file in place like editors or shell redirection do,
+	// the loaded table must not read the file which is shorter than mapped data
+	writeFile(t, file, "/path/0\t/changed\n", time.Now())
+	for _, i := range []int{0, size / 2, size - 1} {
+		if v, _ := table.Lookup(fmt.Sprintf("/path/%d", i)); v != fmt.Sprintf("/redirect/%d", i) {
+			t.Errorf("Unexpected value for /path/%d: %q", i, v)
+		}
+	}
+}
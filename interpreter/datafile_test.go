@@ -0,0 +1,100 @@
+package interpreter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/datafile"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestApplyDataFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"redirects.json": `{"/old": "/new"}`,
+		"extra.tsv":      "enabled\ttrue\n",
+		"internal.json":  `["192.0.2.0/24"]`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Errorf("Failed to write file: %s", err)
+			return
+		}
+	}
+
+	w, err := datafile.New(&config.DataFilesConfig{
+		Tables: map[string]string{
+			"redirects": filepath.Join(dir, "redirects.json"),
+			"extra":     filepath.Join(dir, "extra.tsv"),
+		},
+		Acls: map[string]string{
+			"internal": filepath.Join(dir, "internal.json"),
+		},
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	vcl := `
+table redirects {
+  "/old": "/declared",
+  "/other": "/declared",
+}
+
+acl internal {
+  "198.51.100.0"/24;
+}
+
+sub vcl_recv {
+  set req.http.Location = table.lookup(redirects, req.url, "none");
+  set req.http.Other = table.lookup(redirects, "/other", "none");
+  set req.http.Extra = table.lookup(extra, "enabled", "false");
+  if (client.ip ~ internal) {
+    set req.http.Internal = "1";
+  }
+  error 600;
+}
+`
+	ip := New(
+		context.WithResolver(resolver.NewStaticResolver("main", vcl)),
+		context.WithDataFiles(w),
+	)
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/old", nil)
+	req.RemoteAddr = "192.0.2.10:12345"
+	ip.ServeHTTP(httptest.NewRecorder(), req)
+	if ip.process.Error != nil {
+		t.Errorf("Did not expect error but got %s", ip.process.Error)
+		return
+	}
+
+	expects := map[string]string{
+		// Data file replaces all items of declared table
+		"Location": "/new",
+		"Other":    "none",
+		// Table which is not declared in VCL, loaded from TSV file
+		"Extra": "true",
+		// Data file replaces all entries of declared ACL
+		"Internal": "1",
+	}
+	for key, expect := range expects {
+		if v := ip.ctx.Request.Header.Get(key); v != expect {
+			t.Errorf("%s header expects %s but got %s", key, expect, v)
+		}
+	}
+
+	t.Run("non-string table", func(t *testing.T) {
+		ip := New(
+			context.WithResolver(resolver.NewStaticResolver("main", `table redirects BOOL {}`)),
+			context.WithDataFiles(w),
+		)
+		if err := ip.ProcessInit(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err == nil {
+			t.Errorf("Expected error for non-STRING table")
+		}
+	})
+}
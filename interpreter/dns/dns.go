@@ -0,0 +1,127 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TTL which is used when the TTL could not be determined, for example the host is resolved by hosts file
+const DefaultTTL = 60 * time.Second
+
+// Record is the result of DNS resolution
+type Record struct {
+	Addrs []net.IP
+	TTL   time.Duration
+}
+
+type LookupFunc func(ctx context.Context, host string) (*Record, error)
+
+type Option func(r *Resolver)
+
+// WithLookup uses the function to lookup hosts instead of the system resolver
+func WithLookup(lookup LookupFunc) Option {
+	return func(r *Resolver) {
+		r.lookup = lookup
+	}
+}
+
+func WithNow(now func() time.Time) Option {
+	return func(r *Resolver) {
+		r.now = now
+	}
+}
+
+type entry struct {
+	addrs   []net.IP
+	expires time.Time
+}
+
+// Resolver resolves backend hosts and caches results like Fastly does.
+// Hosts of static backends are resolved once when the service is loaded and never re-resolved,
+// and hosts of dynamic backends (.dynamic = true) are re-resolved after the TTL expires.
+type Resolver struct {
+	lookup LookupFunc
+	now    func() time.Time
+
+	mu      sync.Mutex
+	static  map[string][]net.IP
+	dynamic map[string]*entry
+}
+
+func New(opts ...Option) *Resolver {
+	r := &Resolver{
+		lookup:  SystemLookup,
+		now:     time.Now,
+		static:  make(map[string][]net.IP),
+		dynamic: make(map[string]*entry),
+	}
+	for i := range opts {
+		opts[i](r)
+	}
+	return r
+}
+
+// Resolve returns addresses of the host, IPv4 addresses are ordered first.
+func (r *Resolver) Resolve(ctx context.Context, host string, dynamic bool) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if dynamic {
+		if e, ok := r.dynamic[host]; ok && now.Before(e.expires) {
+			return e.addrs, nil
+		}
+	} else if addrs, ok := r.static[host]; ok {
+		return addrs, nil
+	}
+
+	record, err := r.lookup(ctx, host)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(record.Addrs) == 0 {
+		return nil, errors.Errorf("Host %s does not have any addresses", host)
+	}
+	addrs := sortAddrs(record.Addrs)
+
+	if dynamic {
+		r.dynamic[host] = &entry{addrs: addrs, expires: now.Add(record.TTL)}
+	} else {
+		r.static[host] = addrs
+	}
+	return addrs, nil
+}
+
+// ParseAddrs parses pinned addresses and orders them in the same way as resolved addresses
+func ParseAddrs(values []string) ([]net.IP, error) {
+	var addrs []net.IP
+	for _, v := range values {
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return nil, errors.Errorf("%s is not an IP address", v)
+		}
+		addrs = append(addrs, ip)
+	}
+	return sortAddrs(addrs), nil
+}
+
+// Backends connect over IPv4 unless the host only has IPv6 addresses
+func sortAddrs(addrs []net.IP) []net.IP {
+	var v4, v6 []net.IP
+	for _, ip := range addrs {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+		} else {
+			v6 = append(v6, ip)
+		}
+	}
+	return append(v4, v6...)
+}
@@ -0,0 +1,133 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var lookups int
+	lookup := func(ctx context.Context, host string) (*Record, error) {
+		lookups++
+		return &Record{
+			Addrs: []net.IP{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.2")},
+			TTL:   30 * time.Second,
+		}, nil
+	}
+
+	t.Run("IP address is not looked up", func(t *testing.T) {
+		lookups = 0
+		r := New(WithLookup(lookup))
+		addrs, err := r.Resolve(context.Background(), "192.0.2.100", true)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		if diff := cmp.Diff([]string{"192.0.2.100"}, toStrings(addrs)); diff != "" {
+			t.Errorf("Addresses mismatch, diff=%s", diff)
+		}
+		if lookups != 0 {
+			t.Errorf("Lookup should not be called, called %d times", lookups)
+		}
+	})
+
+	t.Run("static host is never re-resolved", func(t *testing.T) {
+		lookups = 0
+		current := now
+		r := New(WithLookup(lookup), WithNow(func() time.Time { return current }))
+		for i := 0; i < 3; i++ {
+			addrs, err := r.Resolve(context.Background(), "example.com", false)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff([]string{"192.0.2.1", "192.0.2.2", "2001:db8::1"}, toStrings(addrs)); diff != "" {
+				t.Errorf("Addresses mismatch, diff=%s", diff)
+			}
+			current = current.Add(time.Hour)
+		}
+		if lookups != 1 {
+			t.Errorf("Lookup should be called once, called %d times", lookups)
+		}
+	})
+
+	t.Run("dynamic host is re-resolved after TTL", func(t *testing.T) {
+		lookups = 0
+		current := now
+		r := New(WithLookup(lookup), WithNow(func() time.Time { return current }))
+
+		resolve := func() {
+			if _, err := r.Resolve(context.Background(), "example.com", true); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		}
+		resolve()
+		current = current.Add(29 * time.Second)
+		resolve()
+		if lookups != 1 {
+			t.Errorf("Cached addresses should be used within TTL, lookup called %d times", lookups)
+		}
+		current = current.Add(time.Second)
+		resolve()
+		if lookups != 2 {
+			t.Errorf("Host should be re-resolved after TTL, lookup called %d times", lookups)
+		}
+	})
+
+	t.Run("host without addresses", func(t *testing.T) {
+		r := New(WithLookup(func(ctx context.Context, host string) (*Record, error) {
+			return &Record{TTL: DefaultTTL}, nil
+		}))
+		if _, err := r.Resolve(context.Background(), "example.com", false); err == nil {
+			t.Errorf("Expected error but got nil")
+		}
+	})
+}
+
+func TestTTLObserver(t *testing.T) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true})
+	b.EnableCompression()
+	name := dnsmessage.MustNewName("example.com.")
+	if err := b.StartQuestions(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Question(dnsmessage.Question{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.StartAnswers(); err != nil {
+		t.Fatal(err)
+	}
+	for _, ttl := range []uint32{300, 120, 600} {
+		header := dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: ttl}
+		if err := b.AResource(header, dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o := &ttlObserver{}
+	if o.get() != DefaultTTL {
+		t.Errorf("Default TTL should be used when nothing is observed, got %s", o.get())
+	}
+	o.observe(msg)
+	if o.get() != 120*time.Second {
+		t.Errorf("Minimum TTL should be used, got %s", o.get())
+	}
+}
+
+func toStrings(addrs []net.IP) []string {
+	var ret []string
+	for _, ip := range addrs {
+		ret = append(ret, ip.String())
+	}
+	return ret
+}
@@ -0,0 +1,124 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// SystemLookup resolves the host by the system configuration, hosts file and DNS servers.
+// The standard resolver does not expose TTL, so DNS responses are observed on the connection
+// and the minimum TTL of answers is used.
+func SystemLookup(ctx context.Context, host string) (*Record, error) {
+	observer := &ttlObserver{}
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, network, address)
+			if err != nil {
+				return nil, err
+			}
+			// Go resolver uses packet round trip only when the connection is net.PacketConn
+			if udp, ok := conn.(*net.UDPConn); ok {
+				return &packetConn{UDPConn: udp, observer: observer}, nil
+			}
+			return &streamConn{Conn: conn, observer: observer}, nil
+		},
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	record := &Record{TTL: observer.get()}
+	for _, addr := range addrs {
+		record.Addrs = append(record.Addrs, addr.IP)
+	}
+	return record, nil
+}
+
+// Observe minimum TTL of A and AAAA answers
+type ttlObserver struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	observed bool
+}
+
+func (o *ttlObserver) observe(msg []byte) {
+	var p dnsmessage.Parser
+	if _, err := p.Start(msg); err != nil {
+		return
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for {
+		h, err := p.AnswerHeader()
+		if err != nil {
+			return
+		}
+		if h.Type == dnsmessage.TypeA || h.Type == dnsmessage.TypeAAAA {
+			ttl := time.Duration(h.TTL) * time.Second
+			if !o.observed || ttl < o.ttl {
+				o.ttl = ttl
+				o.observed = true
+			}
+		}
+		if err := p.SkipAnswer(); err != nil {
+			return
+		}
+	}
+}
+
+func (o *ttlObserver) get() time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.observed {
+		return DefaultTTL
+	}
+	return o.ttl
+}
+
+// UDP connection, each read is a whole DNS message
+type packetConn struct {
+	*net.UDPConn
+	observer *ttlObserver
+}
+
+func (c *packetConn) Read(b []byte) (int, error) {
+	n, err := c.UDPConn.Read(b)
+	if n > 0 {
+		c.observer.observe(b[:n])
+	}
+	return n, err
+}
+
+// TCP connection, DNS message is prefixed by two bytes length and may be read partially
+type streamConn struct {
+	net.Conn
+	observer *ttlObserver
+	buf      []byte
+}
+
+func (c *streamConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.buf = append(c.buf, b[:n]...)
+	for len(c.buf) >= 2 {
+		size := int(binary.BigEndian.Uint16(c.buf))
+		if len(c.buf) < size+2 {
+			break
+		}
+		c.observer.observe(c.buf[2 : size+2])
+		c.buf = c.buf[size+2:]
+	}
+	return n, err
+}
@@ -0,0 +1,69 @@
+package interpreter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestPinnedBackendDNS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Host", r.Host)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "origin.example.test";
+  .port = "%s";
+  .ssl = false;
+  .dynamic = true;
+}
+
+sub vcl_recv {
+  return (pass);
+}
+
+sub vcl_fetch {
+  set beresp.http.X-Backend-IP = beresp.backend.ip;
+  set beresp.http.X-Alternate-IPs = beresp.backend.alternate_ips;
+}
+`, parsed.Port())
+
+	ip := New(
+		context.WithResolver(resolver.NewStaticResolver("main", vcl)),
+		context.WithDNSOverrides(map[string][]string{
+			"origin.example.test": {"::1", parsed.Hostname(), "192.0.2.1"},
+		}),
+	)
+	resp, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expects status 200 but got %d", resp.StatusCode)
+	}
+	expects := map[string]string{
+		"X-Host":          "origin.example.test:" + parsed.Port(),
+		"X-Backend-IP":    parsed.Hostname(),
+		"X-Alternate-IPs": "192.0.2.1,::1",
+	}
+	for key, expect := range expects {
+		if v := resp.Header.Get(key); v != expect {
+			t.Errorf("Expects %s header %q but got %q", key, expect, v)
+		}
+	}
+}
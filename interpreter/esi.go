@@ -3,7 +3,6 @@ package interpreter
 import (
 	"bytes"
 	"context"
-	"io"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -83,7 +82,7 @@ func (i *Interpreter) executeESI() error {
 	if len(body) > 0 {
 		parsed = append(parsed, body...)
 	}
-	resp.Body = io.NopCloser(bytes.NewReader(parsed))
+	resp.Body = newSharedBody(parsed)
 	return nil
 }
 
@@ -3,6 +3,7 @@ package exception
 import (
 	"fmt"
 
+	"github.com/ysugimoto/falco/i18n"
 	"github.com/ysugimoto/falco/token"
 )
 
@@ -48,13 +49,13 @@ func Runtime(t *token.Token, format string, args ...interface{}) *Exception {
 	return &Exception{
 		Type:    RuntimeType,
 		Token:   t,
-		Message: fmt.Sprintf(format, args...),
+		Message: i18n.Translate(fmt.Sprintf(format, args...)),
 	}
 }
 
 func System(format string, args ...interface{}) *Exception {
 	return &Exception{
 		Type:    SystemType,
-		Message: fmt.Sprintf(format, args...),
+		Message: i18n.Translate(fmt.Sprintf(format, args...)),
 	}
 }
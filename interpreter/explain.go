@@ -0,0 +1,124 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/process"
+)
+
+// Explain the statement if it affects caching behavior and the explanation is enabled
+func (i *Interpreter) explainStatement(stmt ast.Statement) {
+	if i.process == nil || i.process.CacheExplanation == nil {
+		return
+	}
+
+	var reason string
+	switch t := stmt.(type) {
+	case *ast.SetStatement:
+		reason = explainSetStatement(i.ctx.Scope, t)
+	case *ast.ReturnStatement:
+		reason = explainReturnState(i.ctx.Scope, i.ProcessReturnStatement(t))
+	case *ast.ErrorStatement:
+		reason = "Response is generated in vcl_error, it is not stored in the cache"
+	}
+	if reason == "" {
+		return
+	}
+	i.process.CacheExplanation.Decide(i.ctx.Scope, stmt, stepStatement(stmt), reason)
+}
+
+// Explain the decision which is not made by VCL statement like backend response headers
+func (i *Interpreter) explain(source, reason string) {
+	if i.process == nil || i.process.CacheExplanation == nil {
+		return
+	}
+	i.process.CacheExplanation.Decide(i.ctx.Scope, nil, source, reason)
+}
+
+func (i *Interpreter) concludeExplanation(format string, args ...interface{}) {
+	if i.process == nil || i.process.CacheExplanation == nil {
+		return
+	}
+	i.process.CacheExplanation.Conclude(format, args...)
+}
+
+// CacheExplanation returns the explanation of caching decision, returns nil unless enabled.
+// On testing, the request does not reach the end so the result is concluded from current variables.
+func (i *Interpreter) CacheExplanation() *process.CacheExplanation {
+	if i.process == nil || i.process.CacheExplanation == nil {
+		return nil
+	}
+	e := i.process.CacheExplanation
+	if e.Result == "" && i.ctx.BackendResponse != nil {
+		if !i.ctx.BackendResponseCacheable.Value {
+			e.Conclude("beresp.cacheable is false, the response would not be stored")
+		} else if ttl := i.ctx.BackendResponseTTL.Value; ttl > 0 {
+			e.Conclude("beresp.ttl is %s, the response would be stored unless vcl_fetch returns pass", ttl)
+		} else {
+			e.Conclude("beresp.ttl is %s, the response would not be stored", ttl)
+		}
+	}
+	return e
+}
+
+func explainSetStatement(scope context.Scope, stmt *ast.SetStatement) string {
+	name := strings.ToLower(stmt.Ident.Value)
+	switch name {
+	case "beresp.ttl":
+		return "beresp.ttl is the lifetime of the cache object"
+	case "beresp.cacheable":
+		if v, ok := stmt.Value.(*ast.Boolean); ok && !v.Value {
+			return "Response is not stored in the cache"
+		}
+		return "beresp.cacheable decides whether the response is stored in the cache"
+	case "beresp.grace", "beresp.stale_while_revalidate", "beresp.stale_if_error":
+		return "Stale object is kept in the cache for the period after TTL expires"
+	case "obj.ttl", "obj.grace":
+		return "Lifetime of the cached object is changed"
+	case "req.hash":
+		return "Cache key is changed, requests which have different key do not share the cache object"
+	case "beresp.http.cache-control", "beresp.http.surrogate-control", "beresp.http.expires":
+		if scope == context.FetchScope {
+			return "beresp.ttl is not changed because TTL is determined from the backend response header before vcl_fetch"
+		}
+	}
+	return ""
+}
+
+func explainReturnState(scope context.Scope, state State) string {
+	switch scope {
+	case context.RecvScope:
+		switch state {
+		case PASS:
+			return "Request bypasses the cache, the response is not stored"
+		case LOOKUP:
+			return "Cache is looked up"
+		}
+	case context.HitScope:
+		switch state {
+		case PASS:
+			return "Cached object is not used and the request is passed to the backend"
+		case DELIVER:
+			return "Cached object is delivered"
+		}
+	case context.MissScope:
+		switch state {
+		case PASS:
+			return "Request is passed to the backend, the response is not stored"
+		case DELIVER_STALE:
+			return "Stale object is delivered instead of fetching from the backend"
+		}
+	case context.FetchScope:
+		switch state {
+		case PASS:
+			return "Response is not stored, Fastly creates hit-for-pass object which makes following requests pass for beresp.ttl"
+		case DELIVER:
+			return "Response is stored when beresp.cacheable is true and beresp.ttl is positive"
+		case DELIVER_STALE:
+			return "Stale object is delivered instead of the response"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package interpreter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestExplainCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	tests := []struct {
+		name     string
+		recv     string
+		fetch    string
+		result   string
+		decision string
+	}{
+		{
+			name:     "response is stored with header TTL",
+			result:   "stored in the cache for 1h0m0s",
+			decision: "Cache-Control: max-age=3600",
+		},
+		{
+			name:     "request is passed in vcl_recv",
+			recv:     "return(pass);",
+			result:   "PASS:",
+			decision: "Request bypasses the cache, the response is not stored",
+		},
+		{
+			name:     "response is passed in vcl_fetch",
+			fetch:    "return(pass);",
+			result:   "not stored because vcl_fetch returned pass",
+			decision: "hit-for-pass",
+		},
+		{
+			name:     "response is not cacheable",
+			fetch:    "set beresp.cacheable = false;",
+			result:   "not stored because beresp.cacheable is false",
+			decision: "Response is not stored in the cache",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  %s
+}
+
+sub vcl_fetch {
+  %s
+}
+`, parsed.Hostname(), parsed.Port(), tt.recv, tt.fetch)
+
+			ip := New(
+				context.WithResolver(resolver.NewStaticResolver("main", vcl)),
+				context.WithExplainCache(),
+			)
+			if _, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			explanation := ip.CacheExplanation()
+			if explanation == nil {
+				t.Errorf("Cache explanation must be recorded")
+				return
+			}
+			if !strings.Contains(explanation.Result, tt.result) {
+				t.Errorf("Result should contain %q but got %q", tt.result, explanation.Result)
+			}
+			if out := explanation.String(); !strings.Contains(out, tt.decision) {
+				t.Errorf("Decisions should contain %q but got\n%s", tt.decision, out)
+			}
+		})
+	}
+}
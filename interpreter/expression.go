@@ -8,7 +8,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/interpreter/exception"
-	"github.com/ysugimoto/falco/interpreter/function"
 	"github.com/ysugimoto/falco/interpreter/operator"
 	"github.com/ysugimoto/falco/interpreter/value"
 	"github.com/ysugimoto/falco/types"
@@ -229,7 +228,7 @@ func (i *Interpreter) ProcessFunctionCallExpression(exp *ast.FunctionCallExpress
 		}
 		return v, nil
 	}
-	fn, err := function.Exists(i.ctx.Scope, exp.Function.Value)
+	fn, err := i.findFunction(exp.Function.Value)
 	if err != nil {
 		return value.Null, errors.WithStack(err)
 	}
@@ -262,6 +261,10 @@ func (i *Interpreter) ProcessFunctionCallExpression(exp *ast.FunctionCallExpress
 }
 
 func (i *Interpreter) ProcessInfixExpression(exp *ast.InfixExpression, withCondition bool) (value.Value, error) {
+	if exp.Operator == "+" {
+		return i.ProcessConcatExpression(exp, withCondition)
+	}
+
 	left, err := i.ProcessExpression(exp.Left, withCondition)
 	if err != nil {
 		return value.Null, errors.WithStack(err)
@@ -295,9 +298,6 @@ func (i *Interpreter) ProcessInfixExpression(exp *ast.InfixExpression, withCondi
 		result, opErr = operator.LogicalOr(left, right)
 	case "&&":
 		result, opErr = operator.LogicalAnd(left, right)
-	// "+" means string concatenation
-	case "+":
-		result, opErr = operator.Concat(left, right)
 	default:
 		return value.Null, errors.WithStack(
 			exception.Runtime(&exp.GetMeta().Token, "Unexpected infix operator: %s", exp.Operator),
@@ -312,3 +312,44 @@ func (i *Interpreter) ProcessInfixExpression(exp *ast.InfixExpression, withCondi
 
 	return result, nil
 }
+
+// Count of operands in the concatenation chain which are processed without heap allocation
+const concatChainSize = 8
+
+// ProcessConcatExpression processes chained string concatenation like `a + b + c` at once.
+// Operands are evaluated from left to right and concatenated without intermediate strings of each "+" operator.
+func (i *Interpreter) ProcessConcatExpression(exp *ast.InfixExpression, withCondition bool) (value.Value, error) {
+	// Concatenation is left associative, so the chain is nested in the left expression.
+	// Most chains are short, then backing arrays are kept on the stack.
+	var operandsArray [concatChainSize]ast.Expression
+	operands := append(operandsArray[:0], exp.Right)
+	left := exp.Left
+	for {
+		infix, ok := left.(*ast.InfixExpression)
+		if !ok || infix.Operator != "+" {
+			break
+		}
+		operands = append(operands, infix.Right)
+		left = infix.Left
+	}
+	operands = append(operands, left)
+
+	var valuesArray [concatChainSize]value.Value
+	values := valuesArray[:0]
+	// Operands are collected from right to left
+	for j := len(operands) - 1; j >= 0; j-- {
+		v, err := i.ProcessExpression(operands[j], withCondition)
+		if err != nil {
+			return value.Null, errors.WithStack(err)
+		}
+		values = append(values, v)
+	}
+
+	result, err := operator.ConcatValues(values...)
+	if err != nil {
+		return value.Null, errors.WithStack(
+			exception.Runtime(&exp.GetMeta().Token, err.Error()),
+		)
+	}
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/interpreter/function"
+)
+
+// InjectFunctions adds functions which are available only in this interpreter.
+// Unlike function.Inject, injected functions do not affect other interpreters so that interpreters could run concurrently.
+func (i *Interpreter) InjectFunctions(fns map[string]*function.Function) {
+	if i.functions == nil {
+		i.functions = make(map[string]*function.Function)
+	}
+	for key, fn := range fns {
+		i.functions[key] = fn
+	}
+}
+
+// Find function which is injected to this interpreter, then find builtin functions
+func (i *Interpreter) findFunction(name string) (*function.Function, error) {
+	fn, ok := i.functions[name]
+	if !ok {
+		return function.Exists(i.ctx.Scope, name)
+	}
+	if (fn.Scope & i.ctx.Scope) == 0 {
+		return nil, errors.WithStack(
+			fmt.Errorf("Function %s could not call on %s scope", name, i.ctx.Scope.String()),
+		)
+	}
+	return fn, nil
+}
@@ -83,23 +83,23 @@ func Header_filter(ctx *context.Context, args ...value.Value) (value.Value, erro
 	switch where.Value {
 	case "req":
 		if ctx.Request != nil {
-			ctx.Request.Header, err = header_filter_delete(ctx.Request.Header, names)
+			ctx.Request.Header, err = header_filter_delete(ctx.WritableHeader(&ctx.Request.Header), names)
 		}
 	case "resp":
 		if ctx.Response != nil {
-			ctx.Response.Header, err = header_filter_delete(ctx.Response.Header, names)
+			ctx.Response.Header, err = header_filter_delete(ctx.WritableHeader(&ctx.Response.Header), names)
 		}
 	case "obj":
 		if ctx.Object != nil {
-			ctx.Object.Header, err = header_filter_delete(ctx.Object.Header, names)
+			ctx.Object.Header, err = header_filter_delete(ctx.WritableHeader(&ctx.Object.Header), names)
 		}
 	case "bereq":
 		if ctx.BackendRequest != nil {
-			ctx.BackendRequest.Header, err = header_filter_delete(ctx.BackendRequest.Header, names)
+			ctx.BackendRequest.Header, err = header_filter_delete(ctx.WritableHeader(&ctx.BackendRequest.Header), names)
 		}
 	case "beresp":
 		if ctx.BackendResponse != nil {
-			ctx.BackendResponse.Header, err = header_filter_delete(ctx.BackendResponse.Header, names)
+			ctx.BackendResponse.Header, err = header_filter_delete(ctx.WritableHeader(&ctx.BackendResponse.Header), names)
 		}
 	}
 
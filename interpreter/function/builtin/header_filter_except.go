@@ -84,23 +84,23 @@ func Header_filter_except(ctx *context.Context, args ...value.Value) (value.Valu
 	switch where.Value {
 	case "req":
 		if ctx.Request != nil {
-			ctx.Request.Header = header_filter_except_delete(ctx.Request.Header, filter)
+			ctx.Request.Header = header_filter_except_delete(ctx.WritableHeader(&ctx.Request.Header), filter)
 		}
 	case "resp":
 		if ctx.Response != nil {
-			ctx.Response.Header = header_filter_except_delete(ctx.Response.Header, filter)
+			ctx.Response.Header = header_filter_except_delete(ctx.WritableHeader(&ctx.Response.Header), filter)
 		}
 	case "obj":
 		if ctx.Object != nil {
-			ctx.Object.Header = header_filter_except_delete(ctx.Object.Header, filter)
+			ctx.Object.Header = header_filter_except_delete(ctx.WritableHeader(&ctx.Object.Header), filter)
 		}
 	case "bereq":
 		if ctx.BackendRequest != nil {
-			ctx.BackendRequest.Header = header_filter_except_delete(ctx.BackendRequest.Header, filter)
+			ctx.BackendRequest.Header = header_filter_except_delete(ctx.WritableHeader(&ctx.BackendRequest.Header), filter)
 		}
 	case "beresp":
 		if ctx.BackendResponse != nil {
-			ctx.BackendResponse.Header = header_filter_except_delete(ctx.BackendResponse.Header, filter)
+			ctx.BackendResponse.Header = header_filter_except_delete(ctx.WritableHeader(&ctx.BackendResponse.Header), filter)
 		}
 	}
 	return value.Null, nil
@@ -73,23 +73,23 @@ func Header_set(ctx *context.Context, args ...value.Value) (value.Value, error)
 	switch where.Value {
 	case "req":
 		if ctx.Request != nil {
-			header_set(ctx.Request.Header, name.Value, val.Value)
+			header_set(ctx.WritableHeader(&ctx.Request.Header), name.Value, val.Value)
 		}
 	case "resp":
 		if ctx.Response != nil {
-			header_set(ctx.Response.Header, name.Value, val.Value)
+			header_set(ctx.WritableHeader(&ctx.Response.Header), name.Value, val.Value)
 		}
 	case "obj":
 		if ctx.Object != nil {
-			header_set(ctx.Object.Header, name.Value, val.Value)
+			header_set(ctx.WritableHeader(&ctx.Object.Header), name.Value, val.Value)
 		}
 	case "bereq":
 		if ctx.BackendRequest != nil {
-			header_set(ctx.BackendRequest.Header, name.Value, val.Value)
+			header_set(ctx.WritableHeader(&ctx.BackendRequest.Header), name.Value, val.Value)
 		}
 	case "beresp":
 		if ctx.BackendResponse != nil {
-			header_set(ctx.BackendResponse.Header, name.Value, val.Value)
+			header_set(ctx.WritableHeader(&ctx.BackendResponse.Header), name.Value, val.Value)
 		}
 	}
 
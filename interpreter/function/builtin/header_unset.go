@@ -77,23 +77,23 @@ func Header_unset(ctx *context.Context, args ...value.Value) (value.Value, error
 	switch where.Value {
 	case "req":
 		if ctx.Request != nil {
-			header_unset(ctx.Request.Header, name.Value)
+			header_unset(ctx.WritableHeader(&ctx.Request.Header), name.Value)
 		}
 	case "resp":
 		if ctx.Response != nil {
-			header_unset(ctx.Response.Header, name.Value)
+			header_unset(ctx.WritableHeader(&ctx.Response.Header), name.Value)
 		}
 	case "obj":
 		if ctx.Object != nil {
-			header_unset(ctx.Object.Header, name.Value)
+			header_unset(ctx.WritableHeader(&ctx.Object.Header), name.Value)
 		}
 	case "bereq":
 		if ctx.BackendRequest != nil {
-			header_unset(ctx.BackendRequest.Header, name.Value)
+			header_unset(ctx.WritableHeader(&ctx.BackendRequest.Header), name.Value)
 		}
 	case "beresp":
 		if ctx.BackendResponse != nil {
-			header_unset(ctx.BackendResponse.Header, name.Value)
+			header_unset(ctx.WritableHeader(&ctx.BackendResponse.Header), name.Value)
 		}
 	}
 
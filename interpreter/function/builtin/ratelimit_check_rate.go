@@ -3,6 +3,8 @@
 package builtin
 
 import (
+	"time"
+
 	"github.com/ysugimoto/falco/interpreter/context"
 	"github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/interpreter/value"
@@ -13,15 +15,7 @@ const Ratelimit_check_rate_Name = "ratelimit.check_rate"
 var Ratelimit_check_rate_ArgumentTypes = []value.Type{value.StringType, value.IdentType, value.IntegerType, value.IntegerType, value.IntegerType, value.IdentType, value.RTimeType}
 
 func Ratelimit_check_rate_Validate(args []value.Value) error {
-	if len(args) != 7 {
-		return errors.ArgumentNotEnough(Ratelimit_check_rate_Name, 7, args)
-	}
-	for i := range args {
-		if args[i].Type() != Ratelimit_check_rate_ArgumentTypes[i] {
-			return errors.TypeMismatch(Ratelimit_check_rate_Name, i+1, Ratelimit_check_rate_ArgumentTypes[i], args[i].Type())
-		}
-	}
-	return nil
+	return ValidateRatelimitArguments(Ratelimit_check_rate_Name, Ratelimit_check_rate_ArgumentTypes, 0, args)
 }
 
 // Fastly built-in function implementation of ratelimit.check_rate
@@ -34,6 +28,52 @@ func Ratelimit_check_rate(ctx *context.Context, args ...value.Value) (value.Valu
 		return value.Null, err
 	}
 
-	// TODO: Needs to be implemented
-	return &value.Boolean{Value: false}, nil
+	entry := args[0].String()
+	exceeded, err := ratelimitIncrementAndCheck(
+		ctx, Ratelimit_check_rate_Name, entry,
+		value.Unwrap[*value.Ident](args[1]).Value,
+		value.Unwrap[*value.Integer](args[2]).Value,
+		value.Unwrap[*value.Integer](args[3]).Value,
+		value.Unwrap[*value.Integer](args[4]).Value,
+	)
+	if err != nil {
+		return value.Null, err
+	}
+
+	pb := ctx.RateLimit.Penaltybox(value.Unwrap[*value.Ident](args[5]).Value)
+	if exceeded {
+		pb.Add(entry, value.Unwrap[*value.RTime](args[6]).Value, ctx.Now())
+	}
+	return &value.Boolean{Value: pb.Has(entry, ctx.Now())}, nil
+}
+
+// Increment the ratecounter and check the rate of the window exceeds the limit.
+// Fastly only accepts 1, 10 and 60 seconds for the window.
+func ratelimitIncrementAndCheck(ctx *context.Context, name, entry, rc string, delta, window, limit int64) (bool, error) {
+	switch window {
+	case 1, 10, 60:
+	default:
+		return false, errors.New(name, "Window must be one of 1, 10 or 60 but %d is specified", window)
+	}
+
+	counter := ctx.RateLimit.Ratecounter(rc)
+	counter.Increment(entry, delta, ctx.Now())
+	return counter.Rate(entry, time.Duration(window)*time.Second, ctx.Now()) > float64(limit), nil
+}
+
+// ValidateRatelimitArguments validates arguments of ratelimit functions which are also used for testing functions.
+// Entry is usually client.ip, so IP type is also accepted as the entry string at the entry index.
+func ValidateRatelimitArguments(name string, types []value.Type, entry int, args []value.Value) error {
+	if len(args) != len(types) {
+		return errors.ArgumentNotEnough(name, len(types), args)
+	}
+	for i := range args {
+		if i == entry && args[i].Type() == value.IpType {
+			continue
+		}
+		if args[i].Type() != types[i] {
+			return errors.TypeMismatch(name, i+1, types[i], args[i].Type())
+		}
+	}
+	return nil
 }
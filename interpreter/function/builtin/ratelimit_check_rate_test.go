@@ -4,8 +4,10 @@ package builtin
 
 import (
 	"testing"
-	// "github.com/ysugimoto/falco/interpreter/context"
-	// "github.com/ysugimoto/falco/interpreter/value"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
 )
 
 // Fastly built-in function testing implementation of ratelimit.check_rate
@@ -13,5 +15,61 @@ import (
 // - STRING, ID, INTEGER, INTEGER, INTEGER, ID, RTIME
 // Reference: https://developer.fastly.com/reference/vcl/functions/rate-limiting/ratelimit-check-rate/
 func Test_Ratelimit_check_rate(t *testing.T) {
-	t.Skip("Test Builtin function ratelimit.check_rate should be impelemented")
+	now := time.Unix(1700000000, 0)
+	ctx := context.New()
+	ctx.FixedTime = &now
+
+	checkRate := func(entry string) bool {
+		ret, err := Ratelimit_check_rate(
+			ctx,
+			&value.String{Value: entry},
+			&value.Ident{Value: "rc", Literal: true},
+			&value.Integer{Value: 1},
+			&value.Integer{Value: 10},
+			&value.Integer{Value: 1},
+			&value.Ident{Value: "pb", Literal: true},
+			&value.RTime{Value: 2 * time.Minute},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return value.Unwrap[*value.Boolean](ret).Value
+	}
+
+	// 10 requests in 10 seconds is exactly 1 rps, not exceeded
+	for i := 0; i < 10; i++ {
+		if checkRate("192.0.2.1") {
+			t.Fatalf("Expected not to be limited on request %d", i+1)
+		}
+	}
+	if !checkRate("192.0.2.1") {
+		t.Errorf("Expected to be limited when the rate exceeds the limit")
+	}
+	if checkRate("192.0.2.2") {
+		t.Errorf("Expected other entry not to be limited")
+	}
+
+	// Rate goes down after the window, but the entry stays in the penaltybox until ttl expires
+	now = now.Add(time.Minute)
+	if !checkRate("192.0.2.1") {
+		t.Errorf("Expected to be limited while the entry is in the penaltybox")
+	}
+	now = now.Add(time.Minute)
+	if checkRate("192.0.2.1") {
+		t.Errorf("Expected not to be limited after the penaltybox ttl expires")
+	}
+
+	_, err := Ratelimit_check_rate(
+		ctx,
+		&value.String{Value: "192.0.2.1"},
+		&value.Ident{Value: "rc", Literal: true},
+		&value.Integer{Value: 1},
+		&value.Integer{Value: 5},
+		&value.Integer{Value: 1},
+		&value.Ident{Value: "pb", Literal: true},
+		&value.RTime{Value: 2 * time.Minute},
+	)
+	if err == nil {
+		t.Errorf("Expected error for unsupported window")
+	}
 }
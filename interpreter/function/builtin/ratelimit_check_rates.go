@@ -4,7 +4,6 @@ package builtin
 
 import (
 	"github.com/ysugimoto/falco/interpreter/context"
-	"github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -13,15 +12,7 @@ const Ratelimit_check_rates_Name = "ratelimit.check_rates"
 var Ratelimit_check_rates_ArgumentTypes = []value.Type{value.StringType, value.IdentType, value.IntegerType, value.IntegerType, value.IntegerType, value.IdentType, value.IntegerType, value.IntegerType, value.IntegerType, value.IdentType, value.RTimeType}
 
 func Ratelimit_check_rates_Validate(args []value.Value) error {
-	if len(args) != 11 {
-		return errors.ArgumentNotEnough(Ratelimit_check_rates_Name, 11, args)
-	}
-	for i := range args {
-		if args[i].Type() != Ratelimit_check_rates_ArgumentTypes[i] {
-			return errors.TypeMismatch(Ratelimit_check_rates_Name, i+1, Ratelimit_check_rates_ArgumentTypes[i], args[i].Type())
-		}
-	}
-	return nil
+	return ValidateRatelimitArguments(Ratelimit_check_rates_Name, Ratelimit_check_rates_ArgumentTypes, 0, args)
 }
 
 // Fastly built-in function implementation of ratelimit.check_rates
@@ -34,6 +25,31 @@ func Ratelimit_check_rates(ctx *context.Context, args ...value.Value) (value.Val
 		return value.Null, err
 	}
 
-	// TODO: Needs to be implemented
-	return &value.Boolean{Value: false}, nil
+	entry := args[0].String()
+	exceeded1, err := ratelimitIncrementAndCheck(
+		ctx, Ratelimit_check_rates_Name, entry,
+		value.Unwrap[*value.Ident](args[1]).Value,
+		value.Unwrap[*value.Integer](args[2]).Value,
+		value.Unwrap[*value.Integer](args[3]).Value,
+		value.Unwrap[*value.Integer](args[4]).Value,
+	)
+	if err != nil {
+		return value.Null, err
+	}
+	exceeded2, err := ratelimitIncrementAndCheck(
+		ctx, Ratelimit_check_rates_Name, entry,
+		value.Unwrap[*value.Ident](args[5]).Value,
+		value.Unwrap[*value.Integer](args[6]).Value,
+		value.Unwrap[*value.Integer](args[7]).Value,
+		value.Unwrap[*value.Integer](args[8]).Value,
+	)
+	if err != nil {
+		return value.Null, err
+	}
+
+	pb := ctx.RateLimit.Penaltybox(value.Unwrap[*value.Ident](args[9]).Value)
+	if exceeded1 || exceeded2 {
+		pb.Add(entry, value.Unwrap[*value.RTime](args[10]).Value, ctx.Now())
+	}
+	return &value.Boolean{Value: pb.Has(entry, ctx.Now())}, nil
 }
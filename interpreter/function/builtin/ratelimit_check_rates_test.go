@@ -4,8 +4,10 @@ package builtin
 
 import (
 	"testing"
-	// "github.com/ysugimoto/falco/interpreter/context"
-	// "github.com/ysugimoto/falco/interpreter/value"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
 )
 
 // Fastly built-in function testing implementation of ratelimit.check_rates
@@ -13,5 +15,39 @@ import (
 // - STRING, ID, INTEGER, INTEGER, INTEGER, ID, INTEGER, INTEGER, INTEGER, ID, RTIME
 // Reference: https://developer.fastly.com/reference/vcl/functions/rate-limiting/ratelimit-check-rates/
 func Test_Ratelimit_check_rates(t *testing.T) {
-	t.Skip("Test Builtin function ratelimit.check_rates should be impelemented")
+	now := time.Unix(1700000000, 0)
+	ctx := context.New()
+	ctx.FixedTime = &now
+
+	checkRates := func() bool {
+		ret, err := Ratelimit_check_rates(
+			ctx,
+			&value.String{Value: "192.0.2.1"},
+			&value.Ident{Value: "rc1", Literal: true},
+			&value.Integer{Value: 1},
+			&value.Integer{Value: 1},
+			&value.Integer{Value: 100},
+			&value.Ident{Value: "rc2", Literal: true},
+			&value.Integer{Value: 5},
+			&value.Integer{Value: 60},
+			&value.Integer{Value: 1},
+			&value.Ident{Value: "pb", Literal: true},
+			&value.RTime{Value: time.Minute},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return value.Unwrap[*value.Boolean](ret).Value
+	}
+
+	// Second ratecounter is incremented by 5, 60 requests in 60 seconds exceeds 1 rps on 12th request
+	for i := 0; i < 12; i++ {
+		if checkRates() {
+			t.Fatalf("Expected not to be limited on request %d", i+1)
+		}
+		now = now.Add(time.Second)
+	}
+	if !checkRates() {
+		t.Errorf("Expected to be limited when the rate of second ratecounter exceeds the limit")
+	}
 }
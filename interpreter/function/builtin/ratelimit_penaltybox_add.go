@@ -4,7 +4,6 @@ package builtin
 
 import (
 	"github.com/ysugimoto/falco/interpreter/context"
-	"github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -13,15 +12,7 @@ const Ratelimit_penaltybox_add_Name = "ratelimit.penaltybox_add"
 var Ratelimit_penaltybox_add_ArgumentTypes = []value.Type{value.IdentType, value.StringType, value.RTimeType}
 
 func Ratelimit_penaltybox_add_Validate(args []value.Value) error {
-	if len(args) != 3 {
-		return errors.ArgumentNotEnough(Ratelimit_penaltybox_add_Name, 3, args)
-	}
-	for i := range args {
-		if args[i].Type() != Ratelimit_penaltybox_add_ArgumentTypes[i] {
-			return errors.TypeMismatch(Ratelimit_penaltybox_add_Name, i+1, Ratelimit_penaltybox_add_ArgumentTypes[i], args[i].Type())
-		}
-	}
-	return nil
+	return ValidateRatelimitArguments(Ratelimit_penaltybox_add_Name, Ratelimit_penaltybox_add_ArgumentTypes, 1, args)
 }
 
 // Fastly built-in function implementation of ratelimit.penaltybox_add
@@ -34,6 +25,10 @@ func Ratelimit_penaltybox_add(ctx *context.Context, args ...value.Value) (value.
 		return value.Null, err
 	}
 
-	// TODO: Needs to be implemented
+	name := value.Unwrap[*value.Ident](args[0]).Value
+	entry := args[1].String()
+	ttl := value.Unwrap[*value.RTime](args[2]).Value
+
+	ctx.RateLimit.Penaltybox(name).Add(entry, ttl, ctx.Now())
 	return value.Null, nil
 }
@@ -3,9 +3,12 @@
 package builtin
 
 import (
+	"net"
 	"testing"
-	// "github.com/ysugimoto/falco/interpreter/context"
-	// "github.com/ysugimoto/falco/interpreter/value"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
 )
 
 // Fastly built-in function testing implementation of ratelimit.penaltybox_add
@@ -13,5 +16,53 @@ import (
 // - ID, STRING, RTIME
 // Reference: https://developer.fastly.com/reference/vcl/functions/rate-limiting/ratelimit-penaltybox-add/
 func Test_Ratelimit_penaltybox_add(t *testing.T) {
-	t.Skip("Test Builtin function ratelimit.penaltybox_add should be impelemented")
+	now := time.Unix(1700000000, 0)
+	ctx := context.New()
+	ctx.FixedTime = &now
+
+	_, err := Ratelimit_penaltybox_add(
+		ctx,
+		&value.Ident{Value: "pb", Literal: true},
+		&value.String{Value: "192.0.2.1"},
+		&value.RTime{Value: 90 * time.Second},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// ttl is truncated to minutes, so the entry expires after 1m instead of 90s
+	pb := ctx.RateLimit.Penaltybox("pb")
+	if ttl := pb.TTL("192.0.2.1", now); ttl != time.Minute {
+		t.Errorf("Unexpected ttl, expect=1m0s, got=%s", ttl)
+	}
+	if !pb.Has("192.0.2.1", now.Add(59*time.Second)) {
+		t.Errorf("Expected the entry to be in the penaltybox before 1m")
+	}
+	if pb.Has("192.0.2.1", now.Add(time.Minute)) {
+		t.Errorf("Expected the entry to expire after 1m")
+	}
+
+	// ttl which is less than a minute is truncated to zero, and IP is accepted as the entry
+	ip := &value.IP{Value: net.ParseIP("192.0.2.2")}
+	_, err = Ratelimit_penaltybox_add(
+		ctx,
+		&value.Ident{Value: "pb", Literal: true},
+		ip,
+		&value.RTime{Value: 30 * time.Second},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if pb.Has(ip.String(), now) {
+		t.Errorf("Expected the entry not to be in the penaltybox for ttl less than a minute")
+	}
+
+	_, err = Ratelimit_penaltybox_add(
+		ctx,
+		&value.Ident{Value: "pb", Literal: true},
+		&value.Integer{Value: 1},
+		&value.RTime{Value: time.Minute},
+	)
+	if err == nil {
+		t.Errorf("Expected error for INTEGER entry")
+	}
 }
@@ -4,7 +4,6 @@ package builtin
 
 import (
 	"github.com/ysugimoto/falco/interpreter/context"
-	"github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -13,15 +12,7 @@ const Ratelimit_penaltybox_has_Name = "ratelimit.penaltybox_has"
 var Ratelimit_penaltybox_has_ArgumentTypes = []value.Type{value.IdentType, value.StringType}
 
 func Ratelimit_penaltybox_has_Validate(args []value.Value) error {
-	if len(args) != 2 {
-		return errors.ArgumentNotEnough(Ratelimit_penaltybox_has_Name, 2, args)
-	}
-	for i := range args {
-		if args[i].Type() != Ratelimit_penaltybox_has_ArgumentTypes[i] {
-			return errors.TypeMismatch(Ratelimit_penaltybox_has_Name, i+1, Ratelimit_penaltybox_has_ArgumentTypes[i], args[i].Type())
-		}
-	}
-	return nil
+	return ValidateRatelimitArguments(Ratelimit_penaltybox_has_Name, Ratelimit_penaltybox_has_ArgumentTypes, 1, args)
 }
 
 // Fastly built-in function implementation of ratelimit.penaltybox_has
@@ -34,6 +25,10 @@ func Ratelimit_penaltybox_has(ctx *context.Context, args ...value.Value) (value.
 		return value.Null, err
 	}
 
-	// TODO: Needs to be implemented
-	return &value.Boolean{Value: false}, nil
+	name := value.Unwrap[*value.Ident](args[0]).Value
+	entry := args[1].String()
+
+	return &value.Boolean{
+		Value: ctx.RateLimit.Penaltybox(name).Has(entry, ctx.Now()),
+	}, nil
 }
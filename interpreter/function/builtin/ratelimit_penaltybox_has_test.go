@@ -4,8 +4,10 @@ package builtin
 
 import (
 	"testing"
-	// "github.com/ysugimoto/falco/interpreter/context"
-	// "github.com/ysugimoto/falco/interpreter/value"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
 )
 
 // Fastly built-in function testing implementation of ratelimit.penaltybox_has
@@ -13,5 +15,35 @@ import (
 // - ID, STRING
 // Reference: https://developer.fastly.com/reference/vcl/functions/rate-limiting/ratelimit-penaltybox-has/
 func Test_Ratelimit_penaltybox_has(t *testing.T) {
-	t.Skip("Test Builtin function ratelimit.penaltybox_has should be impelemented")
+	now := time.Unix(1700000000, 0)
+	ctx := context.New()
+	ctx.FixedTime = &now
+	ctx.RateLimit.Penaltybox("pb").Add("192.0.2.1", time.Minute, now)
+
+	tests := []struct {
+		entry  string
+		after  time.Duration
+		expect bool
+	}{
+		{entry: "192.0.2.1", expect: true},
+		{entry: "192.0.2.2", expect: false},
+		{entry: "192.0.2.1", after: 59 * time.Second, expect: true},
+		{entry: "192.0.2.1", after: time.Minute, expect: false},
+	}
+
+	for i, tt := range tests {
+		current := now.Add(tt.after)
+		ctx.FixedTime = &current
+		ret, err := Ratelimit_penaltybox_has(
+			ctx,
+			&value.Ident{Value: "pb", Literal: true},
+			&value.String{Value: tt.entry},
+		)
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %s", i, err)
+		}
+		if v := value.Unwrap[*value.Boolean](ret).Value; v != tt.expect {
+			t.Errorf("[%d] Unexpected return value, expect=%t, got=%t", i, tt.expect, v)
+		}
+	}
 }
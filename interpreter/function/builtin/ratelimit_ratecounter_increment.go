@@ -4,7 +4,6 @@ package builtin
 
 import (
 	"github.com/ysugimoto/falco/interpreter/context"
-	"github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -13,15 +12,7 @@ const Ratelimit_ratecounter_increment_Name = "ratelimit.ratecounter_increment"
 var Ratelimit_ratecounter_increment_ArgumentTypes = []value.Type{value.IdentType, value.StringType, value.IntegerType}
 
 func Ratelimit_ratecounter_increment_Validate(args []value.Value) error {
-	if len(args) != 3 {
-		return errors.ArgumentNotEnough(Ratelimit_ratecounter_increment_Name, 3, args)
-	}
-	for i := range args {
-		if args[i].Type() != Ratelimit_ratecounter_increment_ArgumentTypes[i] {
-			return errors.TypeMismatch(Ratelimit_ratecounter_increment_Name, i+1, Ratelimit_ratecounter_increment_ArgumentTypes[i], args[i].Type())
-		}
-	}
-	return nil
+	return ValidateRatelimitArguments(Ratelimit_ratecounter_increment_Name, Ratelimit_ratecounter_increment_ArgumentTypes, 1, args)
 }
 
 // Fastly built-in function implementation of ratelimit.ratecounter_increment
@@ -34,6 +25,10 @@ func Ratelimit_ratecounter_increment(ctx *context.Context, args ...value.Value)
 		return value.Null, err
 	}
 
-	// TODO: Needs to be implemented
+	name := value.Unwrap[*value.Ident](args[0]).Value
+	entry := args[1].String()
+	delta := value.Unwrap[*value.Integer](args[2]).Value
+
+	ctx.RateLimit.Ratecounter(name).Increment(entry, delta, ctx.Now())
 	return &value.Integer{Value: 0}, nil
 }
@@ -4,8 +4,10 @@ package builtin
 
 import (
 	"testing"
-	// "github.com/ysugimoto/falco/interpreter/context"
-	// "github.com/ysugimoto/falco/interpreter/value"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
 )
 
 // Fastly built-in function testing implementation of ratelimit.ratecounter_increment
@@ -13,5 +15,28 @@ import (
 // - ID, STRING, INTEGER
 // Reference: https://developer.fastly.com/reference/vcl/functions/rate-limiting/ratelimit-ratecounter-increment/
 func Test_Ratelimit_ratecounter_increment(t *testing.T) {
-	t.Skip("Test Builtin function ratelimit.ratecounter_increment should be impelemented")
+	now := time.Unix(1700000000, 0)
+	ctx := context.New()
+	ctx.FixedTime = &now
+
+	for _, delta := range []int64{1, 2, 3} {
+		_, err := Ratelimit_ratecounter_increment(
+			ctx,
+			&value.Ident{Value: "rc", Literal: true},
+			&value.String{Value: "192.0.2.1"},
+			&value.Integer{Value: delta},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		now = now.Add(5 * time.Second)
+	}
+
+	rc := ctx.RateLimit.Ratecounter("rc")
+	if v := rc.Bucket("192.0.2.1", 10*time.Second, now); v != 3 {
+		t.Errorf("Unexpected bucket count, expect=3, got=%d", v)
+	}
+	if v := rc.Bucket("192.0.2.1", 60*time.Second, now); v != 6 {
+		t.Errorf("Unexpected bucket count, expect=6, got=%d", v)
+	}
 }
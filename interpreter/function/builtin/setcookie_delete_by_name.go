@@ -72,9 +72,10 @@ func Setcookie_delete_by_name(ctx *context.Context, args ...value.Value) (value.
 	}
 
 	// Replace Set-Cookie headers
-	resp.Header.Del("Set-Cookie")
+	h := ctx.WritableHeader(&resp.Header)
+	h.Del("Set-Cookie")
 	for _, c := range cookies {
-		resp.Header.Add("Set-Cookie", c)
+		h.Add("Set-Cookie", c)
 	}
 	return &value.Boolean{Value: true}, nil
 }
@@ -44,10 +44,8 @@ func Table_contains(ctx *context.Context, args ...value.Value) (value.Value, err
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			return &value.Boolean{Value: true}, nil
-		}
+	if _, ok := ctx.LookupTable(table, key); ok {
+		return &value.Boolean{Value: true}, nil
 	}
 	return &value.Boolean{Value: false}, nil
 }
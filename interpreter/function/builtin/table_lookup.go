@@ -52,16 +52,14 @@ func Table_lookup(ctx *context.Context, args ...value.Value) (value.Value, error
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.String)
-			if !ok {
-				return &value.String{IsNotSet: true}, errors.New(Table_lookup_Name,
-					"table %s value could not cast to STRING type", id,
-				)
-			}
-			return &value.String{Value: v.Value}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.String)
+		if !ok {
+			return &value.String{IsNotSet: true}, errors.New(Table_lookup_Name,
+				"table %s value could not cast to STRING type", id,
+			)
 		}
+		return &value.String{Value: v.Value}, nil
 	}
 	return defaultValue, nil
 }
@@ -51,16 +51,14 @@ func Table_lookup_acl(ctx *context.Context, args ...value.Value) (value.Value, e
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.AclDeclaration)
-			if !ok {
-				return &value.Acl{Value: defaultAcl}, errors.New(Table_lookup_acl_Name,
-					"table %s value could not cast to ACL type", id,
-				)
-			}
-			return &value.Acl{Value: v}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.AclDeclaration)
+		if !ok {
+			return &value.Acl{Value: defaultAcl}, errors.New(Table_lookup_acl_Name,
+				"table %s value could not cast to ACL type", id,
+			)
 		}
+		return &value.Acl{Value: v}, nil
 	}
 	return &value.Acl{Value: defaultAcl}, nil
 }
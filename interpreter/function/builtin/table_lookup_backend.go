@@ -51,16 +51,14 @@ func Table_lookup_backend(ctx *context.Context, args ...value.Value) (value.Valu
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.BackendDeclaration)
-			if !ok {
-				return &value.Backend{Value: defaultBackend}, errors.New(Table_lookup_backend_Name,
-					"table %s value could not cast to BACKEND type", id,
-				)
-			}
-			return &value.Backend{Value: v}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.BackendDeclaration)
+		if !ok {
+			return &value.Backend{Value: defaultBackend}, errors.New(Table_lookup_backend_Name,
+				"table %s value could not cast to BACKEND type", id,
+			)
 		}
+		return &value.Backend{Value: v}, nil
 	}
 	return &value.Backend{Value: defaultBackend}, nil
 }
@@ -51,16 +51,14 @@ func Table_lookup_bool(ctx *context.Context, args ...value.Value) (value.Value,
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.Boolean)
-			if !ok {
-				return &value.Boolean{Value: defaultValue}, errors.New(Table_lookup_bool_Name,
-					"table %s value could not cast to BOOL type", id,
-				)
-			}
-			return &value.Boolean{Value: v.Value}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.Boolean)
+		if !ok {
+			return &value.Boolean{Value: defaultValue}, errors.New(Table_lookup_bool_Name,
+				"table %s value could not cast to BOOL type", id,
+			)
 		}
+		return &value.Boolean{Value: v.Value}, nil
 	}
 	return &value.Boolean{Value: defaultValue}, nil
 }
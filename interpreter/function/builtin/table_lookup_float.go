@@ -51,16 +51,14 @@ func Table_lookup_float(ctx *context.Context, args ...value.Value) (value.Value,
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.Float)
-			if !ok {
-				return &value.Float{Value: defaultValue}, errors.New(Table_lookup_float_Name,
-					"table %s value could not cast to FLOAT type", id,
-				)
-			}
-			return &value.Float{Value: v.Value}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.Float)
+		if !ok {
+			return &value.Float{Value: defaultValue}, errors.New(Table_lookup_float_Name,
+				"table %s value could not cast to FLOAT type", id,
+			)
 		}
+		return &value.Float{Value: v.Value}, nil
 	}
 	return &value.Float{Value: defaultValue}, nil
 }
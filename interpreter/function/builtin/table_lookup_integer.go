@@ -51,16 +51,14 @@ func Table_lookup_integer(ctx *context.Context, args ...value.Value) (value.Valu
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.Integer)
-			if !ok {
-				return &value.Integer{Value: defaultValue}, errors.New(Table_lookup_integer_Name,
-					"table %s value could not cast to INTEGER type", id,
-				)
-			}
-			return &value.Integer{Value: v.Value}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.Integer)
+		if !ok {
+			return &value.Integer{Value: defaultValue}, errors.New(Table_lookup_integer_Name,
+				"table %s value could not cast to INTEGER type", id,
+			)
 		}
+		return &value.Integer{Value: v.Value}, nil
 	}
 	return &value.Integer{Value: defaultValue}, nil
 }
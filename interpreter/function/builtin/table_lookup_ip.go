@@ -53,16 +53,14 @@ func Table_lookup_ip(ctx *context.Context, args ...value.Value) (value.Value, er
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.IP)
-			if !ok {
-				return &value.IP{Value: defaultValue}, errors.New(Table_lookup_ip_Name,
-					"table %s value could not cast to IP type", id,
-				)
-			}
-			return &value.IP{Value: net.ParseIP(v.Value)}, nil
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.IP)
+		if !ok {
+			return &value.IP{Value: defaultValue}, errors.New(Table_lookup_ip_Name,
+				"table %s value could not cast to IP type", id,
+			)
 		}
+		return &value.IP{Value: net.ParseIP(v.Value)}, nil
 	}
 	return &value.IP{Value: defaultValue}, nil
 }
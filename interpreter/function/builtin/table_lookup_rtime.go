@@ -54,30 +54,28 @@ func Table_lookup_rtime(ctx *context.Context, args ...value.Value) (value.Value,
 		)
 	}
 
-	for _, prop := range table.Properties {
-		if prop.Key.Value == key {
-			v, ok := prop.Value.(*ast.RTime)
-			if !ok {
-				return &value.RTime{Value: defaultValue}, errors.New(Table_lookup_rtime_Name,
-					"table %s value could not cast to RTIME type", id,
-				)
-			}
+	if item, ok := ctx.LookupTable(table, key); ok {
+		v, ok := item.(*ast.RTime)
+		if !ok {
+			return &value.RTime{Value: defaultValue}, errors.New(Table_lookup_rtime_Name,
+				"table %s value could not cast to RTIME type", id,
+			)
+		}
 
-			var val time.Duration
-			switch {
-			case strings.HasSuffix(v.Value, "d"):
-				num := strings.TrimSuffix(v.Value, "d")
-				val, _ = time.ParseDuration(num + "h")
-				val *= 24
-			case strings.HasSuffix(v.Value, "y"):
-				num := strings.TrimSuffix(v.Value, "y")
-				val, _ = time.ParseDuration(num + "h")
-				val *= 24 * 365
-			default:
-				val, _ = time.ParseDuration(v.Value)
-			}
-			return &value.RTime{Value: val}, nil
+		var val time.Duration
+		switch {
+		case strings.HasSuffix(v.Value, "d"):
+			num := strings.TrimSuffix(v.Value, "d")
+			val, _ = time.ParseDuration(num + "h")
+			val *= 24
+		case strings.HasSuffix(v.Value, "y"):
+			num := strings.TrimSuffix(v.Value, "y")
+			val, _ = time.ParseDuration(num + "h")
+			val *= 24 * 365
+		default:
+			val, _ = time.ParseDuration(v.Value)
 		}
+		return &value.RTime{Value: val}, nil
 	}
 	return &value.RTime{Value: defaultValue}, nil
 }
@@ -59,6 +59,9 @@ type AssertionError struct {
 	Token   token.Token
 	Actual  value.Value
 	Message string
+	// Structural diff lines between expected and actual values if exists.
+	// Each line is prefixed with " " for equal, "-" for expected and "+" for actual value
+	Diff []string
 }
 
 func NewAssertionError(actual value.Value, format string, args ...any) *AssertionError {
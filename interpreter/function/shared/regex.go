@@ -0,0 +1,39 @@
+package shared
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Maximum count of compiled regular expressions to be cached.
+// Patterns are literal in most VCLs so the cache rarely fills up, but dynamic patterns must not grow it infinitely.
+const maxRegexCacheSize = 1024
+
+var regexCache = struct {
+	sync.RWMutex
+	patterns map[string]*regexp.Regexp
+}{
+	patterns: make(map[string]*regexp.Regexp),
+}
+
+// CompileRegex compiles the regular expression with caching, because the same pattern is evaluated on every request.
+// Compiled regexp is safe for concurrent use so it could be shared between interpreters.
+func CompileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.RLock()
+	re, ok := regexCache.patterns[pattern]
+	regexCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Lock()
+	if len(regexCache.patterns) < maxRegexCacheSize {
+		regexCache.patterns[pattern] = re
+	}
+	regexCache.Unlock()
+	return re, nil
+}
@@ -0,0 +1,20 @@
+package shared
+
+import "testing"
+
+func TestCompileRegex(t *testing.T) {
+	re, err := CompileRegex(`^/api/(v[0-9]+)/`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	cached, err := CompileRegex(`^/api/(v[0-9]+)/`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if re != cached {
+		t.Errorf("Compiled regexp must be cached")
+	}
+	if _, err := CompileRegex(`^/api/(`); err == nil {
+		t.Errorf("Expects error for invalid pattern")
+	}
+}
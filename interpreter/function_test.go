@@ -0,0 +1,59 @@
+package interpreter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function"
+	"github.com/ysugimoto/falco/interpreter/value"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestInjectFunctions(t *testing.T) {
+	vcl := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Injected = injected.value();
+}`
+	setup := func(ret string) *Interpreter {
+		ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+		ip.InjectFunctions(map[string]*function.Function{
+			"injected.value": {
+				Scope: context.RecvScope,
+				Call: func(ctx *context.Context, args ...value.Value) (value.Value, error) {
+					return &value.String{Value: ret}, nil
+				},
+				IsIdentArgument: func(i int) bool { return false },
+			},
+		})
+		if err := ip.ProcessInit(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return ip
+	}
+
+	// Injected functions are isolated per interpreter
+	foo, bar := setup("foo"), setup("bar")
+	for expect, ip := range map[string]*Interpreter{"foo": foo, "bar": bar} {
+		ip.SetScope(context.RecvScope)
+		if _, err := ip.ProcessSubroutine(ip.ctx.Subroutines["vcl_recv"], DebugPass); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if v := ip.ctx.Request.Header.Get("Injected"); v != expect {
+			t.Errorf("Injected function returns unexpected value, expect=%s, actual=%s", expect, v)
+		}
+	}
+
+	// Injected functions are not available in another interpreter
+	if _, err := function.Exists(context.RecvScope, "injected.value"); err == nil {
+		t.Errorf("Injected function must not be registered as builtin function")
+	}
+
+	// Scope is checked for injected functions
+	foo.SetScope(context.LogScope)
+	if _, err := foo.findFunction("injected.value"); err == nil {
+		t.Errorf("Injected function must not be called in out of scope")
+	}
+}
@@ -1,7 +1,10 @@
 package interpreter
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/interpreter/exception"
@@ -13,6 +16,13 @@ func (i *Interpreter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	i.Debugger.Message("Request Incoming =========>")
 	defer i.Debugger.Message("<========= Request finished")
 
+	// Fastly rejects the request which overflows limitations before processing VCL
+	if le := limitations.CheckFastlyClientRequestLimit(r); le != nil {
+		i.Debugger.Message(le.Error())
+		http.Error(w, le.Message, le.StatusCode)
+		return
+	}
+
 	if err := i.ProcessInit(r); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -34,8 +44,17 @@ func (i *Interpreter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		handleError(err)
 	}
 
+	i.finishTimeTravel()
+	if e := i.process.CacheExplanation; e != nil {
+		i.Debugger.Message(e.String())
+	}
 	i.process.Restarts = i.ctx.Restarts
 	i.process.Backend = i.ctx.Backend
+	if i.ctx.DebugResponseHeaders {
+		for key, values := range i.process.DebugHeaders() {
+			w.Header()[key] = values
+		}
+	}
 	if i.process.Error != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 	} else {
@@ -48,3 +67,37 @@ func (i *Interpreter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Write(out) // nolint:errcheck
 }
+
+// Serve processes the request and returns the client response like Fastly edge responds.
+// It is used when the service is chained as a backend of another service.
+func (i *Interpreter) Serve(r *http.Request) (*http.Response, error) {
+	if le := limitations.CheckFastlyClientRequestLimit(r); le != nil {
+		i.Debugger.Message(le.Error())
+		return errorResponse(r, le.StatusCode, le.Message), nil
+	}
+	if err := i.ProcessInit(r); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := i.ProcessRecv(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := limitations.CheckFastlyResponseLimit(i.ctx.Response); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return i.ctx.Response, nil
+}
+
+// Create plain text response which is responded without processing VCL
+func errorResponse(r *http.Request, code int, msg string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		Body:          io.NopCloser(strings.NewReader(msg)),
+		ContentLength: int64(len(msg)),
+		Request:       r,
+	}
+}
@@ -0,0 +1,151 @@
+package interpreter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/process"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestDebugResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := defaultBackend(parsed) + `
+sub vcl_recv {
+  if (req.url ~ "^/admin") {
+    error 403;
+  } else if (req.url ~ "^/api") {
+    return (pass);
+  }
+  return (lookup);
+}
+`
+
+	t.Run("disabled by default", func(t *testing.T) {
+		ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+		rec := httptest.NewRecorder()
+		ip.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/api", nil))
+		if v := rec.Header().Get(process.DebugHeaderStates); v != "" {
+			t.Errorf("Debug headers should not be added, got %s", v)
+		}
+	})
+
+	t.Run("add debug headers", func(t *testing.T) {
+		ip := New(
+			context.WithResolver(resolver.NewStaticResolver("main", vcl)),
+			context.WithDebugResponseHeaders(),
+		)
+		rec := httptest.NewRecorder()
+		ip.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/api", nil))
+		if ip.process.Error != nil {
+			t.Errorf("Did not expect error but got %s", ip.process.Error)
+			return
+		}
+
+		expects := map[string]string{
+			process.DebugHeaderStates:     "RECV(pass), HASH(hash), PASS(pass), FETCH(deliver), DELIVER(log), LOG(end)",
+			process.DebugHeaderCache:      "PASS",
+			process.DebugHeaderRestarts:   "0",
+			process.DebugHeaderBackend:    "example",
+			process.DebugHeaderConditions: "main:11:10",
+		}
+		for key, expect := range expects {
+			if v := rec.Header().Get(key); v != expect {
+				t.Errorf("%s header expects %s but got %s", key, expect, v)
+			}
+		}
+		if rec.Header().Get(process.DebugHeaderElapsed) == "" {
+			t.Errorf("%s header should be added", process.DebugHeaderElapsed)
+		}
+	})
+}
+
+func TestFastlyHeaderLimits(t *testing.T) {
+	var headerCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < headerCount; i++ {
+			w.Header().Set(fmt.Sprintf("X-Header-%d", i), "value")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := defaultBackend(parsed) + `
+sub vcl_recv {
+  return (pass);
+}
+
+sub vcl_error {
+  set obj.http.X-Error = obj.response;
+  return (deliver);
+}
+`
+
+	tests := []struct {
+		name          string
+		path          string
+		header        http.Header
+		backendHeader int
+		status        int
+		errorHeader   string
+	}{
+		{name: "within limits", path: "/", status: http.StatusOK},
+		{name: "too long URL", path: "/" + strings.Repeat("a", 8*1024), status: http.StatusRequestURITooLong},
+		{name: "too large header", path: "/", header: http.Header{"X-Large": {strings.Repeat("a", 8*1024)}}, status: http.StatusBadRequest},
+		{name: "too many headers", path: "/", header: manyHeaders(100), status: http.StatusBadRequest},
+		{name: "too many backend response headers", path: "/", backendHeader: 100, status: http.StatusServiceUnavailable, errorHeader: "backend read error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headerCount = tt.backendHeader
+			req := httptest.NewRequest(http.MethodGet, "http://localhost"+tt.path, nil)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+
+			ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+			resp, err := ip.Serve(req)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expects status %d but got %d", tt.status, resp.StatusCode)
+			}
+			if v := resp.Header.Get("X-Error"); v != tt.errorHeader {
+				t.Errorf("Expects X-Error header %q but got %q", tt.errorHeader, v)
+			}
+		})
+	}
+}
+
+func manyHeaders(n int) http.Header {
+	h := http.Header{}
+	for i := 0; i < n; i++ {
+		h.Set(fmt.Sprintf("X-Header-%d", i), "value")
+	}
+	return h
+}
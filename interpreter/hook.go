@@ -0,0 +1,126 @@
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/process"
+)
+
+type HookType string
+
+const (
+	HookBeforeState HookType = "before_state"
+	HookAfterState  HookType = "after_state"
+	HookBeforeFetch HookType = "before_fetch"
+	HookAfterFetch  HookType = "after_fetch"
+)
+
+// HookEvent is passed to registered hooks.
+// Snapshot contains copied req/bereq/beresp/resp/obj values so hooks could not modify them.
+type HookEvent struct {
+	Type     HookType
+	Scope    context.Scope
+	Restarts int
+	// State returned from the subroutine, only set on HookAfterState
+	State State
+	// Backend name, only set on HookBeforeFetch and HookAfterFetch
+	Backend string
+	// Body of the backend request, only set on HookBeforeFetch and HookAfterFetch
+	BackendRequestBody []byte
+	// Body of the backend response, only set on HookAfterFetch
+	BackendResponseBody []byte
+	// True when the backend response body has been transparently decompressed by Go HTTP client,
+	// then Content-Encoding and Content-Length headers are removed from the response. Only set on HookAfterFetch
+	BackendResponseUncompressed bool
+	Snapshot                    *process.Snapshot
+}
+
+// Hook is called on each state transition and backend fetch.
+// If the hook returns an error, the interpreter stops processing with the error
+// so embedders can implement custom assertions.
+type Hook func(*HookEvent) error
+
+func (i *Interpreter) AddHook(h Hook) {
+	i.hooks = append(i.hooks, h)
+}
+
+func (i *Interpreter) runHooks(t HookType, state State) error {
+	// Record state transitions for the process summary
+	if t == HookAfterState {
+		i.process.Transition(i.ctx.Scope, state.String())
+	}
+	// Record steps which the simulator processes between subroutines for the time-travel log
+	switch t {
+	case HookBeforeState:
+		i.recordStep(process.StepState, nil, "state "+i.ctx.Scope.String())
+	case HookBeforeFetch:
+		statement := "fetch"
+		if i.ctx.Backend != nil {
+			statement += " " + i.ctx.Backend.String()
+		}
+		i.recordStep(process.StepFetch, nil, statement)
+	}
+	if len(i.hooks) == 0 {
+		return nil
+	}
+
+	e := &HookEvent{
+		Type:     t,
+		Scope:    i.ctx.Scope,
+		Restarts: i.ctx.Restarts,
+		State:    state,
+		Snapshot: process.NewSnapshot(i.ctx),
+	}
+	if t == HookBeforeFetch || t == HookAfterFetch {
+		if i.ctx.Backend != nil {
+			e.Backend = i.ctx.Backend.String()
+		}
+		if i.ctx.BackendRequest != nil {
+			e.BackendRequestBody = readBody(&i.ctx.BackendRequest.Body)
+		}
+	}
+	if t == HookAfterFetch && i.ctx.BackendResponse != nil {
+		e.BackendResponseBody = readBody(&i.ctx.BackendResponse.Body)
+		e.BackendResponseUncompressed = i.ctx.BackendResponse.Uncompressed
+	}
+	for _, h := range i.hooks {
+		if err := h(e); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// DebugHeaderLeakHook returns the hook which verifies debug headers are not leaked to the client response.
+// Debug headers are allowed only when the request has allowlisted debug header.
+func DebugHeaderLeakHook(c *config.DebugHeaderConfig) Hook {
+	return func(e *HookEvent) error {
+		if e.Type != HookAfterState || e.Scope != context.LogScope {
+			return nil
+		}
+		if e.Snapshot.Request == nil || e.Snapshot.Response == nil {
+			return nil
+		}
+		for name := range e.Snapshot.Request.Headers {
+			if c.IsAllowedHeader(name) {
+				return nil
+			}
+		}
+
+		var leaked []string
+		for name := range e.Snapshot.Response.Headers {
+			if c.IsDebugHeader(name) {
+				leaked = append(leaked, name)
+			}
+		}
+		if len(leaked) == 0 {
+			return nil
+		}
+		sort.Strings(leaked)
+		return fmt.Errorf("Debug headers leaked to the client response: %v", leaked)
+	}
+}
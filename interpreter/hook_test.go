@@ -0,0 +1,134 @@
+package interpreter
+
+import (
+	"fmt"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := defaultBackend(parsed) + `
+sub vcl_recv {
+  set req.http.X-Hook = "recv";
+  return (pass);
+}
+`
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+
+	var events []string
+	var header string
+	ip.AddHook(func(e *HookEvent) error {
+		events = append(events, fmt.Sprintf("%s:%s:%s", e.Type, e.Scope, e.State))
+		if e.Type == HookBeforeFetch {
+			header = e.Snapshot.BackendRequest.Headers["x-hook"]
+		}
+		return nil
+	})
+	ip.ServeHTTP(
+		httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "http://localhost", nil),
+	)
+	if ip.process.Error != nil {
+		t.Errorf("Did not expect error but got %s", ip.process.Error)
+		return
+	}
+
+	expects := []string{
+		"before_state:RECV:",
+		"after_state:RECV:pass",
+		"before_state:HASH:",
+		"after_state:HASH:hash",
+		"before_state:PASS:",
+		"after_state:PASS:pass",
+		"before_state:FETCH:",
+		"before_fetch:FETCH:",
+		"after_fetch:FETCH:",
+		"after_state:FETCH:deliver",
+		"before_state:DELIVER:",
+		"after_state:DELIVER:log",
+		"before_state:LOG:",
+		"after_state:LOG:end",
+	}
+	if diff := cmp.Diff(expects, events); diff != "" {
+		t.Errorf("Hook events unmatch, diff: %s", diff)
+	}
+	if header != "recv" {
+		t.Errorf("Snapshot header unmatch, expect recv, got %s", header)
+	}
+}
+
+func TestHookError(t *testing.T) {
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", `
+sub vcl_recv {
+  error 600;
+}
+`)))
+	ip.AddHook(func(e *HookEvent) error {
+		if e.Type == HookAfterState && e.State == ERROR {
+			return fmt.Errorf("error state is not allowed")
+		}
+		return nil
+	})
+	ip.ServeHTTP(
+		httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodGet, "http://localhost", nil),
+	)
+	if ip.process.Error == nil {
+		t.Error("Expected error but got nil")
+	}
+}
+
+func TestDebugHeaderLeakHook(t *testing.T) {
+	vcl := `
+sub vcl_recv {
+  error 600;
+}
+sub vcl_error {
+  set obj.http.X-Debug-Foo = "bar";
+  return (deliver);
+}
+`
+	tests := []struct {
+		name    string
+		header  string
+		isError bool
+	}{
+		{name: "leaked without debug header", isError: true},
+		{name: "allowed with debug header", header: "Fastly-Debug", isError: false},
+	}
+
+	for _, tt := range tests {
+		ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+		ip.AddHook(DebugHeaderLeakHook(nil))
+
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, "1")
+		}
+		ip.ServeHTTP(httptest.NewRecorder(), req)
+		if tt.isError && ip.process.Error == nil {
+			t.Errorf("%s: expected error but got nil", tt.name)
+		} else if !tt.isError && ip.process.Error != nil {
+			t.Errorf("%s: did not expect error but got %s", tt.name, ip.process.Error)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package interpreter
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ysugimoto/falco/imageopto"
+	"github.com/ysugimoto/falco/interpreter/exception"
+)
+
+// Image Optimizer is enabled when x-fastly-imageopto-api header is set in vcl_recv
+func (i *Interpreter) isImageOptimizerEnabled() bool {
+	return i.ctx.Request.Header.Get(imageopto.ApiHeader) != ""
+}
+
+// Apply Image Optimizer transformations to the backend response by the query parameters of backend request.
+// Image Optimizer responds 400 Bad Request when the query parameter is invalid.
+func (i *Interpreter) optimizeImage(req *http.Request, resp *http.Response) (*http.Response, error) {
+	params, err := imageopto.ParseQuery(req.URL.Query())
+	if err != nil {
+		i.Debugger.Message(err.Error())
+		return errorResponse(req, http.StatusBadRequest, err.Error()), nil
+	}
+
+	if err := imageopto.Transform(resp, params); err != nil {
+		return nil, exception.Runtime(nil, "Failed to optimize image: %s", err)
+	}
+	if info := resp.Header.Get(imageopto.InfoHeader); info != "" {
+		i.Debugger.Message(fmt.Sprintf("Image Optimizer transformed the image: %s", info))
+	}
+	return resp, nil
+}
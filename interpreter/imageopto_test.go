@@ -0,0 +1,80 @@
+package interpreter
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/imageopto"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestImageOptimizer(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200))); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes()) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := func(enabled bool) string {
+		recv := "return (pass);"
+		if enabled {
+			recv = `set req.http.x-fastly-imageopto-api = "fastly";` + recv
+		}
+		return defaultBackend(parsed) + `
+sub vcl_recv {
+  ` + recv + `
+}
+`
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		path    string
+		status  int
+		info    string
+	}{
+		{name: "disabled", path: "/image.png?width=100", status: http.StatusOK},
+		{name: "resize", enabled: true, path: "/image.png?width=100", status: http.StatusOK, info: "odim=100x50 ofmt=png"},
+		{name: "convert", enabled: true, path: "/image.png?format=jpg", status: http.StatusOK, info: "odim=400x200 ofmt=jpeg"},
+		{name: "invalid parameter", enabled: true, path: "/image.png?fit=contain", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl(tt.enabled))))
+			resp, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost"+tt.path, nil))
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expects status %d but got %d", tt.status, resp.StatusCode)
+			}
+			info := resp.Header.Get(imageopto.InfoHeader)
+			if tt.info == "" && info != "" {
+				t.Errorf("Image should not be transformed but got %s", info)
+			} else if !strings.HasSuffix(info, tt.info) {
+				t.Errorf("Expects %s header has %s but got %s", imageopto.InfoHeader, tt.info, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package interpreter
+
+import (
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/cache"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+// Following functions expose the interpreter states in order to inspect them from outside
+// like simulator dashboard. Note that declarations are evaluated on each request,
+// so they return nil before the first request is processed.
+
+// Cache returns in-memory cache storage which is shared across requests
+func (i *Interpreter) Cache() *cache.Cache {
+	return i.cache
+}
+
+// Backends returns backends which are declared in the last processed request
+func (i *Interpreter) Backends() map[string]*value.Backend {
+	if i.ctx == nil {
+		return nil
+	}
+	return i.ctx.Backends
+}
+
+// Ratecounters returns ratecounters which are declared in the last processed request
+func (i *Interpreter) Ratecounters() map[string]*ast.RatecounterDeclaration {
+	if i.ctx == nil {
+		return nil
+	}
+	return i.ctx.Ratecounters
+}
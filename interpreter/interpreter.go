@@ -2,7 +2,6 @@ package interpreter
 
 import (
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"sync/atomic"
@@ -12,9 +11,12 @@ import (
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/interpreter/cache"
 	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/dns"
 	"github.com/ysugimoto/falco/interpreter/exception"
+	"github.com/ysugimoto/falco/interpreter/function"
 	"github.com/ysugimoto/falco/interpreter/limitations"
 	"github.com/ysugimoto/falco/interpreter/process"
+	"github.com/ysugimoto/falco/interpreter/ratelimit"
 	"github.com/ysugimoto/falco/interpreter/value"
 	"github.com/ysugimoto/falco/interpreter/variable"
 	"github.com/ysugimoto/falco/lexer"
@@ -30,8 +32,16 @@ type Interpreter struct {
 	ctx           *context.Context
 	process       *process.Process
 	cache         *cache.Cache
+	ratelimit     *ratelimit.Store
+	dns           *dns.Resolver
+	backends      *backendPools
 	Debugger      Debugger
 	IdentResolver func(v string) value.Value
+	hooks         []Hook
+	// Functions which are injected only to this interpreter like testing functions
+	functions map[string]*function.Function
+	// Statement which is being processed, it is reported when the interpreter crashes
+	statement ast.Statement
 
 	TestingState State
 }
@@ -40,6 +50,9 @@ func New(options ...context.Option) *Interpreter {
 	return &Interpreter{
 		options:      options,
 		cache:        cache.New(),
+		ratelimit:    ratelimit.New(),
+		dns:          dns.New(),
+		backends:     newBackendPools(),
 		localVars:    variable.LocalVariables{},
 		Debugger:     DefaultDebugger{},
 		TestingState: NONE,
@@ -75,6 +88,7 @@ func (i *Interpreter) restart() error {
 	i.Debugger.Message(fmt.Sprintf("Restarted (%d) time", i.ctx.Restarts))
 	i.ctx.BackendRequest = nil
 	i.ctx.BackendResponse = nil
+	i.ctx.BackendAddrs = nil
 	i.ctx.Object = nil
 	i.ctx.Response = nil
 
@@ -85,26 +99,32 @@ func (i *Interpreter) restart() error {
 }
 
 func (i *Interpreter) ProcessInit(r *http.Request) error {
+	defer i.wrapPanic()
 	ctx := context.New(i.options...)
 
-	main, err := ctx.Resolver.MainVCL()
-	if err != nil {
-		i.Debugger.Message(err.Error())
-		return err
-	}
-	if err := limitations.CheckFastlyVCLLimitation(main.Data); err != nil {
-		i.Debugger.Message(err.Error())
-		return err
-	}
-	vcl, err := parser.New(
-		lexer.NewFromString(main.Data, lexer.WithFile(main.Name)),
-	).ParseVCL()
-	if err != nil {
-		// parse error
-		i.Debugger.Message(err.Error())
-		return err
+	vcl := ctx.VCL
+	if vcl == nil {
+		main, err := ctx.Resolver.MainVCL()
+		if err != nil {
+			i.Debugger.Message(err.Error())
+			return err
+		}
+		if err := limitations.CheckFastlyVCLLimitation(main.Data); err != nil {
+			i.Debugger.Message(err.Error())
+			return err
+		}
+		vcl, err = parser.New(
+			lexer.NewFromString(main.Data, lexer.WithFile(main.Name)),
+		).ParseVCL()
+		if err != nil {
+			// parse error
+			i.Debugger.Message(err.Error())
+			return err
+		}
 	}
 
+	// Parsed VCL may be shared with other interpreters so that statements must not be modified
+	statements := vcl.Statements
 	// If remote snippets exists, prepare parse and prepend to main VCL
 	if ctx.FastlySnippets != nil {
 		for _, snip := range ctx.FastlySnippets.EmbedSnippets() {
@@ -116,12 +136,17 @@ func (i *Interpreter) ProcessInit(r *http.Request) error {
 				i.Debugger.Message(err.Error())
 				return err
 			}
-			vcl.Statements = append(s.Statements, vcl.Statements...)
+			statements = append(s.Statements, statements...)
 		}
 	}
 	ctx.RequestStartTime = time.Now()
 	i.ctx = ctx
 	i.ctx.Request = r
+	// Ratecounters and penaltyboxes are shared between requests like the cache
+	i.ctx.RateLimit = i.ratelimit
+	if i.ctx.Cache != nil {
+		i.cache = i.ctx.Cache
+	}
 
 	// OriginalHost value may be overridden. If not empty, set the request value
 	if i.ctx.OriginalHost == "" {
@@ -129,16 +154,25 @@ func (i *Interpreter) ProcessInit(r *http.Request) error {
 	}
 
 	i.process = process.New()
+	if i.ctx.TimeTravel {
+		i.process.TimeTravel = process.NewTimeTravel()
+	}
+	if i.ctx.ExplainCache {
+		i.process.CacheExplanation = process.NewCacheExplanation()
+	}
 	i.ctx.Scope = context.InitScope
 	i.vars = variable.NewAllScopeVariables(i.ctx)
 
-	statements, err := i.resolveIncludeStatement(vcl.Statements, true)
+	statements, err := i.resolveIncludeStatement(statements, true)
 	if err != nil {
 		return err
 	}
 	if err := i.ProcessDeclarations(statements); err != nil {
 		return err
 	}
+	if err := i.applyDataFiles(); err != nil {
+		return err
+	}
 	if err := limitations.CheckFastlyResourceLimit(i.ctx); err != nil {
 		return err
 	}
@@ -196,7 +230,12 @@ func (i *Interpreter) ProcessDeclarations(statements []ast.Statement) error {
 			// Duplicated fastly reserved subroutines should be concatenated
 			// ref: https://developer.fastly.com/reference/vcl/subroutines/#concatenation
 			if _, ok := context.FastlyReservedSubroutine[t.Name.Value]; ok {
-				exists.Block.Statements = append(exists.Block.Statements, t.Block.Statements...)
+				// Concatenate to the copy because declarations may be shared with other interpreters
+				block := *exists.Block
+				block.Statements = append(append([]ast.Statement{}, exists.Block.Statements...), t.Block.Statements...)
+				merged := *exists
+				merged.Block = &block
+				i.ctx.Subroutines[t.Name.Value] = &merged
 				continue
 			}
 			// Other custom user subroutine could not be duplicated
@@ -240,7 +279,11 @@ func (i *Interpreter) ProcessBackends(statements []ast.Statement) error {
 }
 
 func (i *Interpreter) ProcessRecv() error {
+	defer i.wrapPanic()
 	i.SetScope(context.RecvScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	// Simulate Fastly statement lifecycle
 	// see: https://developer.fastly.com/learning/vcl/using/#the-vcl-request-lifecycle
@@ -257,6 +300,10 @@ func (i *Interpreter) ProcessRecv() error {
 		state = PASS
 	}
 
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
+
 	switch state {
 	case PASS:
 		i.ctx.State = "MISS"
@@ -267,6 +314,7 @@ func (i *Interpreter) ProcessRecv() error {
 		i.Debugger.Message(fmt.Sprintf("Move state: %s -> PASS", i.ctx.Scope))
 		err = i.ProcessPass()
 	case ERROR:
+		i.concludeExplanation("NONE: the response is generated in vcl_error without looking up the cache")
 		i.Debugger.Message(fmt.Sprintf("Move state: %s -> ERROR", i.ctx.Scope))
 		err = i.ProcessError()
 	case RESTART:
@@ -277,13 +325,19 @@ func (i *Interpreter) ProcessRecv() error {
 			return errors.WithStack(err)
 		}
 		if v := i.cache.Get(i.ctx.RequestHash.Value); v != nil {
+			i.explain("Cache object of "+i.ctx.RequestHash.Value, "Cache hit, the object was stored at "+v.EntryTime.Format(time.RFC3339))
+			i.concludeExplanation("HIT: the response is served from the cache, the object expires in %s", time.Until(v.Expires).Round(time.Second))
 			i.process.Cached = true
 			i.ctx.State = "HIT"
 			i.ctx.CacheHitItem = v
 			i.ctx.Object = i.cloneResponse(v.Response)
+			// Object lifetime variables are restored from the cached object
+			i.ctx.ObjectTTL = &value.RTime{Value: v.TTL()}
+			i.ctx.ObjectGrace = &value.RTime{Value: v.Grace}
 			i.Debugger.Message(fmt.Sprintf("Move state: %s -> HIT", i.ctx.Scope))
 			err = i.ProcessHit()
 		} else {
+			i.explain("Cache object of "+i.ctx.RequestHash.Value, "Cache miss, the response is fetched from the backend")
 			i.ctx.State = "MISS"
 			i.Debugger.Message(fmt.Sprintf("Move state: %s -> MISS", i.ctx.Scope))
 			err = i.ProcessMiss()
@@ -306,6 +360,9 @@ func (i *Interpreter) ProcessRecv() error {
 
 func (i *Interpreter) ProcessHash() error {
 	i.SetScope(context.HashScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	// Make default VCL hash string
 	// https://developer.fastly.com/reference/vcl/subroutines/hash/
@@ -327,11 +384,14 @@ func (i *Interpreter) ProcessHash() error {
 			)
 		}
 	}
-	return nil
+	return i.runHooks(HookAfterState, HASH)
 }
 
 func (i *Interpreter) ProcessMiss() error {
 	i.SetScope(context.MissScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	if i.ctx.Backend == nil {
 		return exception.Runtime(nil, "No backend determined in MISS")
@@ -361,6 +421,10 @@ func (i *Interpreter) ProcessMiss() error {
 		}
 	}
 
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
+
 	switch state {
 	case DELIVER_STALE:
 		i.Debugger.Message(fmt.Sprintf("Move state: %s -> DELIVER", i.ctx.Scope))
@@ -390,6 +454,9 @@ func (i *Interpreter) ProcessMiss() error {
 
 func (i *Interpreter) ProcessHit() error {
 	i.SetScope(context.HitScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	// Simulate Fastly statement lifecycle
 	// see: https://developer.fastly.com/learning/vcl/using/#the-vcl-request-lifecycle
@@ -410,6 +477,11 @@ func (i *Interpreter) ProcessHit() error {
 	if i.ctx.ObjectTTL.Value > 0 {
 		i.ctx.CacheHitItem.Update(i.ctx.ObjectTTL.Value)
 	}
+	i.ctx.CacheHitItem.Grace = i.ctx.ObjectGrace.Value
+
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
 
 	switch state {
 	case DELIVER:
@@ -440,6 +512,9 @@ func (i *Interpreter) ProcessHit() error {
 
 func (i *Interpreter) ProcessPass() error {
 	i.SetScope(context.PassScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	if i.ctx.Backend == nil {
 		return exception.Runtime(nil, "No backend determined in PASS")
@@ -469,6 +544,10 @@ func (i *Interpreter) ProcessPass() error {
 		}
 	}
 
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
+
 	switch state {
 	case PASS:
 		i.Debugger.Message(fmt.Sprintf("Move state: %s -> FETCH", i.ctx.Scope))
@@ -492,16 +571,35 @@ func (i *Interpreter) ProcessPass() error {
 }
 
 func (i *Interpreter) ProcessFetch() error {
+	// The response of the request which goes through vcl_pass is never stored in the cache
+	passed := i.ctx.Scope == context.PassScope
 	i.SetScope(context.FetchScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	if i.ctx.BackendRequest == nil {
 		return exception.System("No backend determined on FETCH")
 	}
 
 	// Send request to backend
+	if err := i.runHooks(HookBeforeFetch, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 	var err error
 	i.ctx.BackendResponse, err = i.sendBackendRequest(i.ctx.Backend)
 	if err != nil {
+		if be, ok := err.(*backendError); ok {
+			return i.processBackendError(be.Response)
+		}
+		return errors.WithStack(err)
+	}
+	// Backend response which overflows limitations could not be read
+	if le := limitations.CheckFastlyBackendResponseLimit(i.ctx.BackendResponse); le != nil {
+		i.Debugger.Message(le.Error())
+		return i.processBackendError("backend read error")
+	}
+	if err := i.runHooks(HookAfterFetch, NONE); err != nil {
 		return errors.WithStack(err)
 	}
 
@@ -509,36 +607,24 @@ func (i *Interpreter) ProcessFetch() error {
 	i.ctx.RequestEndTime = time.Now()
 
 	// Set cacheable strategy
-	isCacheable := cache.IsCacheableStatusCode(i.ctx.BackendResponse.StatusCode)
+	statusCode := i.ctx.BackendResponse.StatusCode
+	isCacheable := cache.IsCacheableStatusCode(statusCode)
 	i.ctx.BackendResponseCacheable = &value.Boolean{Value: isCacheable}
 	if isCacheable {
+		ttl, source := i.determineCacheTTL(i.ctx.BackendResponse)
 		i.ctx.BackendResponseTTL = &value.RTime{
-			Value: i.determineCacheTTL(i.ctx.BackendResponse),
+			Value: ttl,
 		}
+		i.explain(source, fmt.Sprintf("beresp.ttl is set to %s", ttl))
+	} else {
+		i.explain(fmt.Sprintf("Status code %d", statusCode), "Status code is not cacheable by default, beresp.cacheable is false")
 	}
 	// TODO: consider stale-white-revalidate and stale-if-error TTL
 
-	// Consider cache, create client response from backend response
-	defer func() {
-		resp := i.cloneResponse(i.ctx.BackendResponse)
-		// Note: compare BackendResponseCacheable value
-		// because this value will be changed by user in vcl_fetch directive
-		if i.ctx.BackendResponseCacheable.Value {
-			if i.ctx.BackendResponseTTL.Value.Seconds() > 0 {
-				now := time.Now()
-				i.cache.Set(i.ctx.RequestHash.String(), &cache.CacheItem{
-					Response:  resp,
-					Expires:   now.Add(i.ctx.BackendResponseTTL.Value),
-					EntryTime: now,
-				})
-			}
-		}
-		i.ctx.Response = resp
-	}()
-
 	// Simulate Fastly statement lifecycle
 	// see: https://developer.fastly.com/learning/vcl/using/#the-vcl-request-lifecycle
 	state := DELIVER
+
 	sub, ok := i.ctx.Subroutines[context.FastlyVclNameFetch]
 	if ok {
 		state, err = i.ProcessSubroutine(sub, DebugPass)
@@ -550,6 +636,11 @@ func (i *Interpreter) ProcessFetch() error {
 		}
 	}
 
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
+	i.storeCache(state, passed)
+
 	switch state {
 	case DELIVER, DELIVER_STALE, PASS:
 		i.Debugger.Message(fmt.Sprintf("Move state: %s -> DELIVER", i.ctx.Scope))
@@ -573,8 +664,42 @@ func (i *Interpreter) ProcessFetch() error {
 	return nil
 }
 
+// Store the backend response in the cache before moving to the next state of vcl_fetch.
+// Cached object owns the cloned response because the client response is modified in vcl_deliver,
+// and the client response is created from the backend response in vcl_deliver.
+func (i *Interpreter) storeCache(state State, passed bool) {
+	// Note: compare BackendResponseCacheable value
+	// because this value will be changed by user in vcl_fetch directive
+	switch {
+	case passed:
+		i.concludeExplanation("PASS: the response is not stored because the request is passed")
+	case state != DELIVER:
+		i.concludeExplanation("MISS: the response is not stored because vcl_fetch returned %s", state)
+	case !i.ctx.BackendResponseCacheable.Value:
+		i.concludeExplanation("MISS: the response is not stored because beresp.cacheable is false")
+	case i.ctx.BackendResponseTTL.Value.Seconds() <= 0:
+		i.concludeExplanation("MISS: the response is not stored because beresp.ttl is %s", i.ctx.BackendResponseTTL.Value)
+	default:
+		resp := i.cloneResponse(i.ctx.BackendResponse)
+		now := time.Now()
+		i.cache.Set(i.ctx.RequestHash.String(), &cache.CacheItem{
+			Response:  resp,
+			Expires:   now.Add(i.ctx.BackendResponseTTL.Value),
+			EntryTime: now,
+			Grace:     i.ctx.BackendResponseGrace.Value,
+			// Age header should be considered for obj.age on cache hit
+			InitialAge:           cache.ParseAge(resp.Header.Get("Age")),
+			StaleWhileRevalidate: i.ctx.BackendResponseStaleWhileRevalidate.Value,
+		})
+		i.concludeExplanation("MISS: the response is stored in the cache for %s", i.ctx.BackendResponseTTL.Value)
+	}
+}
+
 func (i *Interpreter) ProcessError() error {
 	i.SetScope(context.ErrorScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	// If process goes through the error directive, response will be generated locally
 	// @see: https://developer.fastly.com/reference/vcl/variables/client-response/resp-is-locally-generated/
@@ -584,7 +709,7 @@ func (i *Interpreter) ProcessError() error {
 		if i.ctx.BackendResponse != nil {
 			i.ctx.Object = i.cloneResponse(i.ctx.BackendResponse)
 			i.ctx.Object.StatusCode = int(i.ctx.ObjectStatus.Value)
-			i.ctx.Object.Body = io.NopCloser(strings.NewReader(i.ctx.ObjectResponse.Value))
+			i.ctx.Object.Body = newSharedBody([]byte(i.ctx.ObjectResponse.Value))
 		} else {
 			i.ctx.Object = &http.Response{
 				StatusCode: int(i.ctx.ObjectStatus.Value),
@@ -595,7 +720,7 @@ func (i *Interpreter) ProcessError() error {
 				Header: http.Header{
 					"Content-Type": {"text/plain"},
 				},
-				Body:          io.NopCloser(strings.NewReader(i.ctx.ObjectResponse.Value)),
+				Body:          newSharedBody([]byte(i.ctx.ObjectResponse.Value)),
 				ContentLength: int64(len(i.ctx.ObjectResponse.Value)),
 				Request:       i.ctx.Request,
 			}
@@ -617,6 +742,10 @@ func (i *Interpreter) ProcessError() error {
 		}
 	}
 
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
+
 	switch state {
 	case DELIVER:
 		i.Debugger.Message(fmt.Sprintf("Move state: %s -> DELIVER", i.ctx.Scope))
@@ -639,6 +768,9 @@ func (i *Interpreter) ProcessError() error {
 
 func (i *Interpreter) ProcessDeliver() error {
 	i.SetScope(context.DeliverScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	if i.ctx.Response == nil {
 		if i.ctx.BackendResponse != nil {
@@ -663,6 +795,10 @@ func (i *Interpreter) ProcessDeliver() error {
 		}
 	}
 
+	if err := i.runHooks(HookAfterState, state); err != nil {
+		return errors.WithStack(err)
+	}
+
 	switch state {
 	case RESTART:
 		err = i.restart()
@@ -675,19 +811,20 @@ func (i *Interpreter) ProcessDeliver() error {
 		}
 
 		// Add Fastly related server info but values are falco's one
-		i.ctx.Response.Header.Set("X-Served-By", cache.LocalDatacenterString)
-		i.ctx.Response.Header.Set("X-Cache", i.ctx.State)
+		header := i.ctx.WritableHeader(&i.ctx.Response.Header)
+		header.Set("X-Served-By", cache.LocalDatacenterString)
+		header.Set("X-Cache", i.ctx.State)
 
 		// Additionally set cache related headers
 		if i.ctx.CacheHitItem != nil {
-			i.ctx.Response.Header.Set("X-Cache-Hits", fmt.Sprint(i.ctx.CacheHitItem.Hits))
-			i.ctx.Response.Header.Set("Age", fmt.Sprintf("%.0f", time.Since(i.ctx.CacheHitItem.EntryTime).Seconds()))
+			header.Set("X-Cache-Hits", fmt.Sprint(i.ctx.CacheHitItem.Hits))
+			header.Set("Age", fmt.Sprintf("%.0f", i.ctx.CacheHitItem.Age().Seconds()))
 		} else {
-			i.ctx.Response.Header.Set("X-Cache-Hits", "0")
+			header.Set("X-Cache-Hits", "0")
 		}
 		// When Fastly-Debug header is present, add debug header but values are fakes
 		if i.ctx.Request.Header.Get("Fastly-Debug") != "" {
-			i.ctx.Response.Header.Set(
+			header.Set(
 				"Fastly-Debug-Path",
 				fmt.Sprintf("(D %s 0) (F %s 0)", cache.LocalDatacenterString, cache.LocalDatacenterString),
 			)
@@ -695,7 +832,7 @@ func (i *Interpreter) ProcessDeliver() error {
 			if i.ctx.State == "HIT" {
 				cacheHit = "H"
 			}
-			i.ctx.Response.Header.Set(
+			header.Set(
 				"Fastly-Debug-TTL",
 				fmt.Sprintf("(%s %s %.3f %.3f %d)", cacheHit, cache.LocalDatacenterString, 0.000, 0.000, 0),
 			)
@@ -719,6 +856,9 @@ func (i *Interpreter) ProcessDeliver() error {
 
 func (i *Interpreter) ProcessLog() error {
 	i.SetScope(context.LogScope)
+	if err := i.runHooks(HookBeforeState, NONE); err != nil {
+		return errors.WithStack(err)
+	}
 
 	if i.ctx.Response == nil {
 		if i.ctx.BackendResponse != nil {
@@ -737,35 +877,36 @@ func (i *Interpreter) ProcessLog() error {
 			return errors.WithStack(err)
 		}
 	}
-	return nil
+	return i.runHooks(HookAfterState, END)
 }
 
 var expiresValueLayout = "Mon, 02 Jan 2006 15:04:05 MST"
 
-func (i *Interpreter) determineCacheTTL(resp *http.Response) time.Duration {
+// Determine TTL from backend response headers, returns the header which decides TTL as well
+func (i *Interpreter) determineCacheTTL(resp *http.Response) (time.Duration, string) {
 	if v := resp.Header.Get("Surrogate-Control"); v != "" {
 		if strings.HasPrefix(v, "max-age=") {
 			if dur, err := time.ParseDuration(strings.TrimPrefix(v, "max-age=") + "s"); err == nil {
-				return dur
+				return dur, "Surrogate-Control: " + v
 			}
 		}
 	}
 	if v := resp.Header.Get("Cache-Control"); v != "" {
 		if strings.HasPrefix(v, "s-maxage=") {
 			if dur, err := time.ParseDuration(strings.TrimPrefix(v, "s-maxage=") + "s"); err == nil {
-				return dur
+				return dur, "Cache-Control: " + v
 			}
 		}
 		if strings.HasPrefix(v, "max-age=") {
 			if dur, err := time.ParseDuration(strings.TrimPrefix(v, "max-age=") + "s"); err == nil {
-				return dur
+				return dur, "Cache-Control: " + v
 			}
 		}
 	}
 	if v := resp.Header.Get("Expires"); v != "" {
 		if d, err := time.Parse(expiresValueLayout, v); err == nil {
-			return time.Until(d)
+			return time.Until(d), "Expires: " + v
 		}
 	}
-	return time.Duration(2 * time.Minute)
+	return time.Duration(2 * time.Minute), "Default TTL"
 }
@@ -28,6 +28,8 @@ const (
 	MaxRequestHeaderCount     = 96
 	MaxReponseHeaderCount     = 96
 	MaxRequestBodyPayloadSize = 8 * KB
+	// Size of a single header line which is inherited from Varnish http_req_hdr_len and http_resp_hdr_len
+	MaxHeaderSize = 8 * KB
 
 	// Surrogate key limitations but actually don't check these
 	MaxSurrogateKeySize       = 1 * KB
@@ -79,46 +81,95 @@ func CheckFastlyResourceLimit(ctx *context.Context) error {
 	return nil
 }
 
-// Validate limitation for the request
-func CheckFastlyRequestLimit(req *http.Request) error {
-	if len([]byte(req.URL.String())) > MaxURLSize {
-		return exception.System(
-			"URL size is limited under the %d bytes",
-			MaxURLSize,
+// LimitError is a violation of limitation which Fastly rejects with the status code
+type LimitError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *LimitError) Error() string {
+	return e.Message
+}
+
+func limitError(code int, format string, args ...interface{}) *LimitError {
+	return &LimitError{StatusCode: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validate limitation for the incoming client request.
+// Fastly rejects the request before vcl_recv, 414 for too long URL and 400 for header overflows.
+// Cookie which overflows the limitation is removed and Fastly-Cookie-Overflow header is added as Fastly does.
+func CheckFastlyClientRequestLimit(req *http.Request) *LimitError {
+	if size := len(req.URL.RequestURI()); size > MaxURLSize {
+		return limitError(
+			http.StatusRequestURITooLong,
+			"URL size %d bytes exceeds the limitation of %d bytes", size, MaxURLSize,
 		)
 	}
+	limitCookie(req)
+	if err := checkHeaderLimit(req.Header, "request", MaxRequestHeaderSize, MaxRequestHeaderCount); err != "" {
+		return limitError(http.StatusBadRequest, "%s", err)
+	}
+	return nil
+}
+
+// Validate limitation for the backend response.
+// Fastly could not read the response which overflows limitations and responds 503 backend read error.
+func CheckFastlyBackendResponseLimit(resp *http.Response) *LimitError {
+	if err := checkHeaderLimit(resp.Header, "backend response", MaxResponseHeaderSize, MaxReponseHeaderCount); err != "" {
+		return limitError(http.StatusServiceUnavailable, "%s", err)
+	}
+	return nil
+}
 
+// If total cookie size is greater than limitation, remove cookie header and add overflow header
+func limitCookie(req *http.Request) {
 	var cookieSize int
 	for _, c := range req.Cookies() {
 		cookieSize += len([]byte(c.Raw))
-		// If max cookie size is greater than limitation, remove cookit header
-		// and add overflow header
 		if cookieSize > MaxCookieSize {
 			req.Header.Del("Cookie")
 			req.Header.Set("Fastly-Cookie-Overflow", "1")
-			break
+			return
 		}
 	}
+}
 
+// Check header line size, total size and count, returns the violation message or empty string.
+// Each value of the same name is counted as a header line.
+func checkHeaderLimit(header http.Header, kind string, maxSize, maxCount int) string {
 	var headerSize, headerCount int
-	for key, values := range req.Header {
-		headerSize += len(
-			[]byte(fmt.Sprintf("%s: %s\n", key, strings.Join(values, ", "))),
-		)
-		if headerSize > MaxRequestHeaderSize {
-			return exception.System(
-				"Overflow request header size limitation of %d bytes",
-				MaxRequestHeaderSize,
-			)
-		}
-		headerCount++
-		if headerCount > MaxRequestHeaderCount {
-			return exception.System(
-				"Overflow request header count limitation of %d",
-				MaxRequestHeaderCount,
-			)
+	for key, values := range header {
+		for _, v := range values {
+			size := len(key) + len(v) + 4 // ": " and CRLF
+			if size > MaxHeaderSize {
+				return fmt.Sprintf("Overflow %s header %s size limitation of %d bytes", kind, key, MaxHeaderSize)
+			}
+			headerSize += size
+			headerCount++
 		}
 	}
+	if headerSize > maxSize {
+		return fmt.Sprintf("Overflow %s header size limitation of %d bytes", kind, maxSize)
+	}
+	if headerCount > maxCount {
+		return fmt.Sprintf("Overflow %s header count limitation of %d", kind, maxCount)
+	}
+	return ""
+}
+
+// Validate limitation for the request
+func CheckFastlyRequestLimit(req *http.Request) error {
+	if len([]byte(req.URL.String())) > MaxURLSize {
+		return exception.System(
+			"URL size is limited under the %d bytes",
+			MaxURLSize,
+		)
+	}
+
+	limitCookie(req)
+	if err := checkHeaderLimit(req.Header, "request", MaxRequestHeaderSize, MaxRequestHeaderCount); err != "" {
+		return exception.System("%s", err)
+	}
 
 	// Request body size check
 	if req.Method == http.MethodPost || req.Method == http.MethodPut || req.Method == http.MethodPatch {
@@ -140,25 +191,8 @@ func CheckFastlyRequestLimit(req *http.Request) error {
 
 // Validate limitation for the response
 func CheckFastlyResponseLimit(resp *http.Response) error {
-	var headerSize, headerCount int
-	for key, values := range resp.Header {
-		headerSize += len(
-			[]byte(fmt.Sprintf("%s: %s\n", key, strings.Join(values, ", "))),
-		)
-		if headerSize > MaxRequestHeaderSize {
-			return exception.System(
-				"Overflow response header size limitation of %d bytes",
-				MaxRequestHeaderSize,
-			)
-		}
-		headerCount++
-		if headerCount > MaxRequestHeaderCount {
-			return exception.System(
-				"Overflow response header count limitation of %d",
-				MaxRequestHeaderCount,
-			)
-		}
+	if err := checkHeaderLimit(resp.Header, "response", MaxResponseHeaderSize, MaxReponseHeaderCount); err != "" {
+		return exception.System("%s", err)
 	}
-
 	return nil
 }
@@ -0,0 +1,156 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Metric names which are compared with the baseline report
+const (
+	MetricCacheHitRatio  = "cache_hit_ratio"
+	MetricBackendFetches = "backend_fetches_per_request"
+)
+
+// Thresholds are allowed regressions against the baseline report
+type Thresholds struct {
+	// Percentage points which cache hit ratio may drop
+	MaxHitRatioDrop float64
+	// Percent which backend fetches per request may increase
+	MaxBackendIncrease float64
+}
+
+// Regression is a metric of the route which regressed beyond the threshold
+type Regression struct {
+	Route    string
+	Metric   string
+	Baseline float64
+	Current  float64
+}
+
+func (r *Regression) String() string {
+	switch r.Metric {
+	case MetricCacheHitRatio:
+		return fmt.Sprintf(
+			"Cache hit ratio of %s dropped from %.1f%% to %.1f%%",
+			r.Route, r.Baseline*100, r.Current*100,
+		)
+	default:
+		return fmt.Sprintf(
+			"Backend fetches of %s increased from %.3f to %.3f per request",
+			r.Route, r.Baseline, r.Current,
+		)
+	}
+}
+
+// Backend request volume is compared per request because the actual rate differs on each run
+func (r *RouteReport) fetchesPerRequest() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.BackendFetches) / float64(r.Requests)
+}
+
+// Compare finds regressions of the current report against the baseline report.
+// Routes are matched by the name, routes which do not have requests in either report are skipped.
+func Compare(baseline, current *Report, t Thresholds) []*Regression {
+	base := make(map[string]*RouteReport, len(baseline.Routes))
+	for _, r := range baseline.Routes {
+		base[r.Name] = r
+	}
+
+	var regressions []*Regression
+	compare := func(b, c *RouteReport) {
+		if b == nil || c == nil || b.Requests == 0 || c.Requests == 0 {
+			return
+		}
+		if (b.CacheHitRatio-c.CacheHitRatio)*100 > t.MaxHitRatioDrop {
+			regressions = append(regressions, &Regression{
+				Route:    c.Name,
+				Metric:   MetricCacheHitRatio,
+				Baseline: b.CacheHitRatio,
+				Current:  c.CacheHitRatio,
+			})
+		}
+		bf, cf := b.fetchesPerRequest(), c.fetchesPerRequest()
+		// Any fetch is a regression when the route did not fetch at all on the baseline
+		if (bf == 0 && cf > 0) || (bf > 0 && (cf-bf)/bf*100 > t.MaxBackendIncrease) {
+			regressions = append(regressions, &Regression{
+				Route:    c.Name,
+				Metric:   MetricBackendFetches,
+				Baseline: bf,
+				Current:  cf,
+			})
+		}
+	}
+
+	for _, r := range current.Routes {
+		compare(base[r.Name], r)
+	}
+	compare(baseline.Total, current.Total)
+	return regressions
+}
+
+// ReadReport reads the report file which is written by WriteReport
+func ReadReport(path string) (*Report, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var report Report
+	if err := json.Unmarshal(buf, &report); err != nil {
+		return nil, errors.WithStack(fmt.Errorf("Failed to parse report %s: %w", path, err))
+	}
+	if report.Total == nil {
+		return nil, errors.WithStack(fmt.Errorf("Report %s does not have total metrics", path))
+	}
+	return &report, nil
+}
+
+// WriteReport writes the report as JSON so that it could be stored as an artifact and compared later
+func WriteReport(path string, report *Report) error {
+	buf, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.WriteFile(path, append(buf, '\n'), 0o644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// UnmarshalJSON reads durations which are output by MarshalJSON
+func (r *RouteReport) UnmarshalJSON(b []byte) error {
+	type alias RouteReport
+	v := struct {
+		*alias
+		P50Latency int64 `json:"p50_latency_us"`
+		P99Latency int64 `json:"p99_latency_us"`
+	}{
+		alias: (*alias)(r),
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	r.P50Latency = time.Duration(v.P50Latency) * time.Microsecond
+	r.P99Latency = time.Duration(v.P99Latency) * time.Microsecond
+	return nil
+}
+
+func (r *Report) UnmarshalJSON(b []byte) error {
+	type alias Report
+	v := struct {
+		*alias
+		Elapsed int64 `json:"elapsed_ms"`
+	}{
+		alias: (*alias)(r),
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	r.Elapsed = time.Duration(v.Elapsed) * time.Millisecond
+	return nil
+}
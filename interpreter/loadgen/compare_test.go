@@ -0,0 +1,121 @@
+package loadgen
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func routeReport(name string, requests, hits, fetches int) *RouteReport {
+	r := &RouteReport{
+		Name:           name,
+		Requests:       requests,
+		CacheHits:      hits,
+		BackendFetches: fetches,
+	}
+	r.finalize(time.Second)
+	return r
+}
+
+func TestCompare(t *testing.T) {
+	thresholds := Thresholds{MaxHitRatioDrop: 5, MaxBackendIncrease: 10}
+	baseline := &Report{
+		Total: routeReport("total", 200, 100, 100),
+		Routes: []*RouteReport{
+			routeReport("home", 100, 90, 10),
+			routeReport("search", 100, 10, 90),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		current *Report
+		expect  []*Regression
+	}{
+		{
+			name: "within thresholds",
+			current: &Report{
+				Total: routeReport("total", 200, 95, 103),
+				Routes: []*RouteReport{
+					routeReport("home", 100, 86, 11),
+					routeReport("search", 100, 9, 92),
+				},
+			},
+		},
+		{
+			name: "hit ratio drops and backend fetches increase",
+			current: &Report{
+				Total: routeReport("total", 200, 80, 120),
+				Routes: []*RouteReport{
+					routeReport("home", 100, 70, 30),
+					routeReport("search", 100, 10, 90),
+				},
+			},
+			expect: []*Regression{
+				{Route: "home", Metric: MetricCacheHitRatio, Baseline: 0.9, Current: 0.7},
+				{Route: "home", Metric: MetricBackendFetches, Baseline: 0.1, Current: 0.3},
+				{Route: "total", Metric: MetricCacheHitRatio, Baseline: 0.5, Current: 0.4},
+				{Route: "total", Metric: MetricBackendFetches, Baseline: 0.5, Current: 0.6},
+			},
+		},
+		{
+			name: "routes which are not in the baseline or have no request are skipped",
+			current: &Report{
+				Total: routeReport("total", 200, 100, 100),
+				Routes: []*RouteReport{
+					routeReport("home", 0, 0, 0),
+					routeReport("login", 100, 0, 100),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Compare(baseline, tt.current, thresholds)
+			if diff := cmp.Diff(tt.expect, actual, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("Compare result mismatch, diff=%s", diff)
+			}
+		})
+	}
+
+	t.Run("route starts fetching from backends", func(t *testing.T) {
+		b := &Report{Total: routeReport("total", 100, 100, 0)}
+		c := &Report{Total: routeReport("total", 100, 99, 1)}
+		actual := Compare(b, c, thresholds)
+		if len(actual) != 1 || actual[0].Metric != MetricBackendFetches {
+			t.Errorf("Expected backend fetches regression, got=%v", actual)
+		}
+	})
+}
+
+func TestWriteAndReadReport(t *testing.T) {
+	report := &Report{
+		Elapsed: 1500 * time.Millisecond,
+		RPS:     100,
+		Total:   routeReport("total", 150, 75, 75),
+		Routes:  []*RouteReport{routeReport("home", 150, 75, 75)},
+	}
+	report.Total.P99Latency = 250 * time.Microsecond
+
+	file := filepath.Join(t.TempDir(), "report.json")
+	if err := WriteReport(file, report); err != nil {
+		t.Errorf("Unexpected error on writing report: %s", err)
+		return
+	}
+	actual, err := ReadReport(file)
+	if err != nil {
+		t.Errorf("Unexpected error on reading report: %s", err)
+		return
+	}
+	if diff := cmp.Diff(report, actual, cmpopts.IgnoreUnexported(RouteReport{})); diff != "" {
+		t.Errorf("Report mismatch after reading, diff=%s", diff)
+	}
+
+	if _, err := ReadReport(filepath.Join(t.TempDir(), "not_found.json")); err == nil {
+		t.Errorf("Expected error on reading report which does not exist")
+	}
+}
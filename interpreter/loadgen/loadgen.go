@@ -0,0 +1,254 @@
+// Package loadgen generates synthetic traffic against the simulator and aggregates
+// cache hit ratio, backend QPS and simulated latency for each route
+package loadgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter"
+)
+
+// Fields of the process summary which the simulator responds
+type processSummary struct {
+	Cached         bool  `json:"cached"`
+	ElapsedTimeUs  int64 `json:"elapsed_time_us"`
+	ClientResponse struct {
+		StatusCode int `json:"status_code"`
+	} `json:"client_response"`
+}
+
+// RouteReport is the aggregated result of the route
+type RouteReport struct {
+	Name           string        `json:"name"`
+	Requests       int           `json:"requests"`
+	CacheHits      int           `json:"cache_hits"`
+	CacheHitRatio  float64       `json:"cache_hit_ratio"`
+	BackendFetches int           `json:"backend_fetches"`
+	BackendQPS     float64       `json:"backend_qps"`
+	Errors         int           `json:"errors"`
+	P50Latency     time.Duration `json:"-"`
+	P99Latency     time.Duration `json:"-"`
+
+	latencies []time.Duration
+}
+
+func (r *RouteReport) add(summary *processSummary, fetches int, failed bool) {
+	r.Requests++
+	if summary.Cached {
+		r.CacheHits++
+	}
+	r.BackendFetches += fetches
+	if failed {
+		r.Errors++
+	}
+	r.latencies = append(r.latencies, time.Duration(summary.ElapsedTimeUs)*time.Microsecond)
+}
+
+func (r *RouteReport) finalize(elapsed time.Duration) {
+	if r.Requests > 0 {
+		r.CacheHitRatio = float64(r.CacheHits) / float64(r.Requests)
+	}
+	if elapsed > 0 {
+		r.BackendQPS = float64(r.BackendFetches) / elapsed.Seconds()
+	}
+	sort.Slice(r.latencies, func(i, j int) bool {
+		return r.latencies[i] < r.latencies[j]
+	})
+	r.P50Latency = percentile(r.latencies, 50)
+	r.P99Latency = percentile(r.latencies, 99)
+}
+
+// Nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Report is the result of the load, routes are ordered as declared in the profile
+type Report struct {
+	Elapsed time.Duration  `json:"-"`
+	RPS     float64        `json:"rps"`
+	Total   *RouteReport   `json:"total"`
+	Routes  []*RouteReport `json:"routes"`
+}
+
+// MarshalJSON outputs durations as numbers of the unit which is described in the field name
+func (r *RouteReport) MarshalJSON() ([]byte, error) {
+	type alias RouteReport
+	return json.Marshal(struct {
+		*alias
+		P50Latency int64 `json:"p50_latency_us"`
+		P99Latency int64 `json:"p99_latency_us"`
+	}{
+		alias:      (*alias)(r),
+		P50Latency: r.P50Latency.Microseconds(),
+		P99Latency: r.P99Latency.Microseconds(),
+	})
+}
+
+func (r *Report) MarshalJSON() ([]byte, error) {
+	type alias Report
+	return json.Marshal(struct {
+		*alias
+		Elapsed int64 `json:"elapsed_ms"`
+	}{
+		alias:   (*alias)(r),
+		Elapsed: r.Elapsed.Milliseconds(),
+	})
+}
+
+// LoadGenerator sends generated requests to the interpreter of simulator.
+// The interpreter processes a request at once, so requests are sent sequentially at the rate.
+type LoadGenerator struct {
+	interpreter *interpreter.Interpreter
+	profile     *Profile
+	rnd         *rand.Rand
+	// Backend fetches of the request which is being processed
+	fetches int
+}
+
+func New(i *interpreter.Interpreter, p *Profile) *LoadGenerator {
+	g := &LoadGenerator{
+		interpreter: i,
+		profile:     p,
+		rnd:         rand.New(rand.NewSource(p.Seed)), // nolint:gosec
+	}
+	// Process messages of each request are too noisy on the load
+	i.Debugger = silentDebugger{}
+	i.AddHook(g.countFetch)
+	return g
+}
+
+type silentDebugger struct{}
+
+func (d silentDebugger) Run(node ast.Node) interpreter.DebugState {
+	return interpreter.DebugPass
+}
+func (d silentDebugger) Message(msg string) {}
+
+func (g *LoadGenerator) countFetch(e *interpreter.HookEvent) error {
+	if e.Type == interpreter.HookBeforeFetch {
+		g.fetches++
+	}
+	return nil
+}
+
+// Run sends requests at rps during the duration and returns the aggregated report.
+// If the simulator could not keep up the rate, requests are sent without waiting
+// and the actual rate is reported.
+func (g *LoadGenerator) Run(rps int, duration time.Duration) (*Report, error) {
+	if rps <= 0 {
+		return nil, errors.WithStack(fmt.Errorf("RPS must be greater than zero"))
+	}
+	total := int(float64(rps) * duration.Seconds())
+	interval := time.Second / time.Duration(rps)
+
+	routes := make([]*RouteReport, len(g.profile.Routes))
+	weights := make([]int, len(g.profile.Routes))
+	for i, r := range g.profile.Routes {
+		routes[i] = &RouteReport{Name: r.Name}
+		weights[i] = r.Weight
+	}
+	all := &RouteReport{Name: "total"}
+
+	start := time.Now()
+	for n := 0; n < total; n++ {
+		if wait := time.Until(start.Add(time.Duration(n) * interval)); wait > 0 {
+			time.Sleep(wait)
+		}
+		idx := pickWeighted(g.rnd, weights)
+		req, err := g.request(g.profile.Routes[idx])
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		summary, fetches, failed, err := g.send(req)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		routes[idx].add(summary, fetches, failed)
+		all.add(summary, fetches, failed)
+	}
+	elapsed := time.Since(start)
+
+	for _, r := range routes {
+		r.finalize(elapsed)
+	}
+	all.finalize(elapsed)
+	report := &Report{
+		Elapsed: elapsed,
+		Total:   all,
+		Routes:  routes,
+	}
+	if elapsed > 0 {
+		report.RPS = float64(total) / elapsed.Seconds()
+	}
+	return report, nil
+}
+
+// Build the request of the route with picked headers and client IP
+func (g *LoadGenerator) request(route *Route) (*http.Request, error) {
+	req, err := http.NewRequest(route.Method, "http://"+g.profile.Host+route.Path, nil)
+	if err != nil {
+		return nil, errors.WithStack(fmt.Errorf("Failed to create request of route %s: %w", route.Name, err))
+	}
+
+	// Sort header names to pick values in the same order for the same seed
+	names := make([]string, 0, len(g.profile.Headers))
+	for name := range g.profile.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := g.profile.Headers[name]
+		if len(values) == 0 {
+			continue
+		}
+		weights := make([]int, len(values))
+		for i := range values {
+			weights[i] = values[i].Weight
+		}
+		req.Header.Set(name, values[pickWeighted(g.rnd, weights)].Value)
+	}
+	for name, value := range route.Headers {
+		req.Header.Set(name, value)
+	}
+
+	addr := "127.0.0.1"
+	if len(g.profile.ClientIPs) > 0 {
+		addr = pickAddress(g.rnd, g.profile.ClientIPs[g.rnd.Intn(len(g.profile.ClientIPs))])
+	}
+	req.RemoteAddr = addr + ":0"
+	return req, nil
+}
+
+// Send the request to the interpreter and parse the process summary
+func (g *LoadGenerator) send(req *http.Request) (*processSummary, int, bool, error) {
+	g.fetches = 0
+	w := httptest.NewRecorder()
+	g.interpreter.ServeHTTP(w, req)
+
+	var summary processSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		// Simulator responds plain text error when the VCL could not be processed
+		return nil, 0, false, errors.WithStack(
+			fmt.Errorf("Failed to process request %s %s: %s", req.Method, req.URL.Path, strings.TrimSpace(w.Body.String())),
+		)
+	}
+	failed := w.Code != http.StatusOK || summary.ClientResponse.StatusCode >= http.StatusInternalServerError
+	return &summary, g.fetches, failed, nil
+}
@@ -0,0 +1,167 @@
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/interpreter"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestLoadGenerator(t *testing.T) {
+	var mu sync.Mutex
+	encodings := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		encodings[r.Header.Get("Accept-Encoding")]++
+		mu.Unlock()
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Test server URL parsing error: %s", err)
+	}
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  if (req.url ~ "^/api/") {
+    return (pass);
+  }
+  return (lookup);
+}
+
+sub vcl_hash {
+  set req.hash += req.url;
+  return (hash);
+}
+`, parsed.Hostname(), parsed.Port())
+
+	i := interpreter.New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	g := New(i, &Profile{
+		Host: "localhost",
+		Seed: 1,
+		Routes: []*Route{
+			{Name: "static", Method: http.MethodGet, Path: "/static/app.js", Weight: 3},
+			{Name: "api", Method: http.MethodGet, Path: "/api/items", Weight: 1},
+		},
+		Headers: map[string][]*WeightedValue{
+			"Accept-Encoding": {
+				{Value: "gzip", Weight: 1},
+				{Value: "br", Weight: 1},
+			},
+		},
+	})
+
+	report, err := g.Run(1000, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	static, api := report.Routes[0], report.Routes[1]
+	if static.Requests+api.Requests != 100 || report.Total.Requests != 100 {
+		t.Errorf("Unexpected number of requests, static=%d, api=%d, total=%d", static.Requests, api.Requests, report.Total.Requests)
+	}
+	// Only the first request of static route fetches the backend
+	if static.BackendFetches != 1 || static.CacheHits != static.Requests-1 {
+		t.Errorf("Unexpected cache result of static route, fetches=%d, hits=%d", static.BackendFetches, static.CacheHits)
+	}
+	if api.BackendFetches != api.Requests || api.CacheHits != 0 {
+		t.Errorf("Unexpected cache result of api route, fetches=%d, hits=%d", api.BackendFetches, api.CacheHits)
+	}
+	if report.Total.BackendFetches != 1+api.Requests || report.Total.Errors != 0 {
+		t.Errorf("Unexpected total result, fetches=%d, errors=%d", report.Total.BackendFetches, report.Total.Errors)
+	}
+	if report.Total.P99Latency < report.Total.P50Latency {
+		t.Errorf("p99 latency must not be less than p50, p50=%s, p99=%s", report.Total.P50Latency, report.Total.P99Latency)
+	}
+	if encodings["gzip"] == 0 || encodings["br"] == 0 {
+		t.Errorf("Header values should be distributed, got=%v", encodings)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write profile: %s", err)
+		}
+		return file
+	}
+
+	t.Run("Fill default values", func(t *testing.T) {
+		p, err := LoadProfile(write("valid.yaml", `
+routes:
+  - path: /
+  - name: search
+    method: post
+    path: /search?q=falco
+    weight: 5
+client_ips:
+  - 192.0.2.0/24
+  - 2001:db8::1
+`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expect := &Profile{
+			Host: "localhost",
+			Routes: []*Route{
+				{Name: "GET /", Method: "GET", Path: "/", Weight: 1},
+				{Name: "search", Method: "POST", Path: "/search?q=falco", Weight: 5},
+			},
+			ClientIPs: []string{"192.0.2.0/24", "2001:db8::1"},
+		}
+		if diff := cmp.Diff(expect, p); diff != "" {
+			t.Errorf("Profile unmatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("Invalid profiles", func(t *testing.T) {
+		tests := []string{
+			"host: example.com",
+			"routes:\n  - path: relative",
+			"routes:\n  - path: /\n    weight: -1",
+			"routes:\n  - path: /\nclient_ips:\n  - invalid",
+		}
+		for i, tt := range tests {
+			if _, err := LoadProfile(write("invalid.yaml", tt)); err == nil {
+				t.Errorf("[%d] Expected error but got nil", i)
+			}
+		}
+	})
+}
+
+func TestPickAddress(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24") // nolint:errcheck
+	rnd := rand.New(rand.NewSource(1))             // nolint:gosec
+	for i := 0; i < 100; i++ {
+		addr := pickAddress(rnd, "192.0.2.0/24")
+		if !network.Contains(net.ParseIP(addr)) {
+			t.Errorf("Address %s is not in the network %s", addr, network)
+		}
+	}
+	if addr := pickAddress(rnd, "198.51.100.1"); addr != "198.51.100.1" {
+		t.Errorf("Single address should be picked as it is, got=%s", addr)
+	}
+}
@@ -0,0 +1,136 @@
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"github.com/pkg/errors"
+)
+
+// Profile describes the shape of synthetic traffic
+type Profile struct {
+	// Host header of requests, localhost is used when empty
+	Host string `yaml:"host"`
+	// Seed of the random generator, requests are reproducible with the same seed
+	Seed int64 `yaml:"seed"`
+	// URL mix of requests, each route is picked by the weight
+	Routes []*Route `yaml:"routes"`
+	// Header value distributions which are applied to all routes
+	Headers map[string][]*WeightedValue `yaml:"headers"`
+	// Client IP pools of addresses or CIDRs, 127.0.0.1 is used when empty
+	ClientIPs []string `yaml:"client_ips"`
+}
+
+// Route is a kind of request which is aggregated in the report
+type Route struct {
+	Name    string            `yaml:"name"`
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Weight  int               `yaml:"weight"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// WeightedValue is a header value which is picked by the weight
+type WeightedValue struct {
+	Value  string `yaml:"value"`
+	Weight int    `yaml:"weight"`
+}
+
+// LoadProfile reads and validates the traffic profile file
+func LoadProfile(path string) (*Profile, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var p Profile
+	if err := yaml.Unmarshal(buf, &p); err != nil {
+		return nil, errors.WithStack(fmt.Errorf("Failed to parse profile %s: %w", path, err))
+	}
+	if err := p.normalize(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &p, nil
+}
+
+// Fill default values and validate the profile
+func (p *Profile) normalize() error {
+	if len(p.Routes) == 0 {
+		return fmt.Errorf("Profile must have at least one route")
+	}
+	if p.Host == "" {
+		p.Host = "localhost"
+	}
+	for i, r := range p.Routes {
+		if r.Path == "" || !strings.HasPrefix(r.Path, "/") {
+			return fmt.Errorf("Path of route %d must start with /", i+1)
+		}
+		if r.Method == "" {
+			r.Method = http.MethodGet
+		}
+		r.Method = strings.ToUpper(r.Method)
+		if r.Name == "" {
+			r.Name = r.Method + " " + r.Path
+		}
+		if r.Weight < 0 {
+			return fmt.Errorf("Weight of route %s must not be negative", r.Name)
+		} else if r.Weight == 0 {
+			r.Weight = 1
+		}
+	}
+	for name, values := range p.Headers {
+		for _, v := range values {
+			if v.Weight < 0 {
+				return fmt.Errorf("Weight of header %s value %s must not be negative", name, v.Value)
+			} else if v.Weight == 0 {
+				v.Weight = 1
+			}
+		}
+	}
+	for _, ip := range p.ClientIPs {
+		if net.ParseIP(ip) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			return fmt.Errorf("Client IP %s must be an address or CIDR", ip)
+		}
+	}
+	return nil
+}
+
+// Pick an index of the weighted items
+func pickWeighted(rnd *rand.Rand, weights []int) int {
+	var total int
+	for _, w := range weights {
+		total += w
+	}
+	n := rnd.Intn(total)
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return len(weights) - 1
+}
+
+// Pick a random address from the address or CIDR
+func pickAddress(rnd *rand.Rand, pool string) string {
+	if ip := net.ParseIP(pool); ip != nil {
+		return ip.String()
+	}
+	_, network, err := net.ParseCIDR(pool)
+	if err != nil {
+		return pool
+	}
+	ip := make(net.IP, len(network.IP))
+	for i := range ip {
+		// Host bits are randomized, network bits are kept
+		ip[i] = network.IP[i] | (byte(rnd.Intn(256)) &^ network.Mask[i])
+	}
+	return ip.String()
+}
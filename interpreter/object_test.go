@@ -0,0 +1,238 @@
+package interpreter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/interpreter/cache"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestCacheObjectVariables(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Header().Set("Age", "100")
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  return(lookup);
+}
+
+sub vcl_fetch {
+  set beresp.ttl = 1h;
+  set beresp.grace = 10m;
+  set beresp.stale_while_revalidate = 30s;
+}
+
+sub vcl_hit {
+  set req.http.X-Hits = obj.hits;
+  if (obj.ttl > 59m && obj.ttl <= 1h) {
+    set req.http.X-TTL = "ok";
+  }
+  if (obj.age >= 100s && obj.entered < 100s) {
+    set req.http.X-Age = "ok";
+  }
+  if (obj.grace == 10m && obj.stale_if_error == 10m && obj.stale_while_revalidate == 30s) {
+    set req.http.X-Stale = "ok";
+  }
+}
+
+sub vcl_deliver {
+  set resp.http.X-Hits = req.http.X-Hits;
+  set resp.http.X-TTL = req.http.X-TTL;
+  set resp.http.X-Age = req.http.X-Age;
+  set resp.http.X-Stale = req.http.X-Stale;
+}
+`, parsed.Hostname(), parsed.Port())
+
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	// First request stores the response to the cache
+	if _, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	for _, hits := range []string{"1", "2"} {
+		resp, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		expects := map[string]string{
+			"X-Cache": "HIT",
+			"X-Hits":  hits,
+			"X-TTL":   "ok",
+			"X-Age":   "ok",
+			"X-Stale": "ok",
+		}
+		for name, expect := range expects {
+			if v := resp.Header.Get(name); v != expect {
+				t.Errorf("%s header expects %q but got %q", name, expect, v)
+			}
+		}
+		if v := resp.Header.Get("Age"); v != "100" {
+			t.Errorf("Age header should include the age of backend response, got %q", v)
+		}
+	}
+}
+
+func TestCachedObjectIsStoredBeforeDeliver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Header().Set("X-Origin", "example")
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  return(lookup);
+}
+
+sub vcl_fetch {
+  set beresp.ttl = 1h;
+}
+
+sub vcl_deliver {
+  add resp.http.X-Delivered = "1";
+  unset resp.http.X-Origin;
+}
+`, parsed.Hostname(), parsed.Port())
+
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	ip.Debugger = silentDebugger{}
+	for _, state := range []string{"MISS", "HIT"} {
+		resp, err := ip.Serve(httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		if err != nil {
+			t.Errorf("%s: unexpected serve error: %s", state, err)
+			return
+		}
+		// Modifications in vcl_deliver are applied to the client response once
+		if diff := cmp.Diff([]string{"1"}, resp.Header.Values("X-Delivered")); diff != "" {
+			t.Errorf("%s: X-Delivered header mismatch, diff=%s", state, diff)
+		}
+		if v := resp.Header.Get("X-Origin"); v != "" {
+			t.Errorf("%s: X-Origin header should be unset in vcl_deliver, got %q", state, v)
+		}
+
+		// Cached object keeps the backend response as it is
+		var items int
+		ip.cache.Range(func(hash string, item *cache.CacheItem) bool {
+			items++
+			if v := item.Response.Header.Values("X-Delivered"); len(v) > 0 {
+				t.Errorf("%s: vcl_deliver modification leaks into the cached object: %v", state, v)
+			}
+			if v := item.Response.Header.Get("X-Origin"); v != "example" {
+				t.Errorf("%s: X-Origin header of the cached object expects %q but got %q", state, "example", v)
+			}
+			return true
+		})
+		if items != 1 {
+			t.Errorf("%s: expected one cached object, got %d", state, items)
+		}
+	}
+}
+
+func TestHeaderModificationsAreNotShared(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Client", r.Header.Get("X-Client"))
+		w.Header().Set("X-Seen-Miss", r.Header.Get("X-Miss"))
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  return(lookup);
+}
+
+sub vcl_miss {
+  set bereq.http.X-Miss = "1";
+  unset bereq.http.X-Client;
+}
+
+sub vcl_fetch {
+  set beresp.http.X-Fetch = "1";
+  set beresp.ttl = 1h;
+}
+
+sub vcl_deliver {
+  if (req.http.X-Miss) {
+    set resp.http.X-Req-Miss = "leaked";
+  }
+  set resp.http.X-Req-Client = req.http.X-Client;
+}
+`, parsed.Hostname(), parsed.Port())
+
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	ip.Debugger = silentDebugger{}
+	req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	req.Header.Set("X-Client", "1")
+	resp, err := ip.Serve(req)
+	if err != nil {
+		t.Errorf("Unexpected serve error: %s", err)
+		return
+	}
+
+	expects := map[string]string{
+		// Backend receives the modified backend request
+		"X-Seen-Client": "",
+		"X-Seen-Miss":   "1",
+		// Client request is not affected by the modifications of the backend request
+		"X-Req-Client": "1",
+		"X-Req-Miss":   "",
+		"X-Fetch":      "1",
+	}
+	for name, expect := range expects {
+		if v := resp.Header.Get(name); v != expect {
+			t.Errorf("%s header expects %q but got %q", name, expect, v)
+		}
+	}
+	if v := req.Header.Get("X-Miss"); v != "" {
+		t.Errorf("Incoming request header must not be modified, got %q", v)
+	}
+}
@@ -1,13 +1,16 @@
 package operator
 
 import (
+	"bytes"
 	"fmt"
 	"net"
-	"regexp"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/shared"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -635,7 +638,7 @@ func Regex(ctx *context.Context, left, right value.Value) (value.Value, error) {
 		switch right.Type() {
 		case value.StringType:
 			rv := value.Unwrap[*value.String](right)
-			re, err := regexp.Compile(rv.Value)
+			re, err := shared.CompileRegex(rv.Value)
 			if err != nil {
 				return value.Null, errors.WithStack(
 					fmt.Errorf("Failed to compile regular expression from string %s", rv.Value),
@@ -643,7 +646,7 @@ func Regex(ctx *context.Context, left, right value.Value) (value.Value, error) {
 			}
 			if matches := re.FindStringSubmatch(lv.Value); matches != nil {
 				for j, m := range matches {
-					ctx.RegexMatchedValues[fmt.Sprint(j)] = &value.String{Value: m}
+					ctx.RegexMatchedValues[strconv.Itoa(j)] = &value.String{Value: m}
 				}
 				return &value.Boolean{Value: true}, nil
 			}
@@ -656,7 +659,7 @@ func Regex(ctx *context.Context, left, right value.Value) (value.Value, error) {
 					fmt.Errorf("Failed to parse IP from string %s", lv.Value),
 				)
 			}
-			res, err := matchesAcl(*rv, ip)
+			res, err := rv.Match(ip)
 			if err != nil {
 				return value.Null, errors.WithStack(err)
 			}
@@ -673,7 +676,7 @@ func Regex(ctx *context.Context, left, right value.Value) (value.Value, error) {
 		switch right.Type() {
 		case value.AclType:
 			rv := value.Unwrap[*value.Acl](right)
-			res, err := matchesAcl(*rv, lv.Value)
+			res, err := rv.Match(lv.Value)
 			if err != nil {
 				return value.Null, errors.WithStack(err)
 			}
@@ -692,27 +695,6 @@ func Regex(ctx *context.Context, left, right value.Value) (value.Value, error) {
 	}
 }
 
-func matchesAcl(acl value.Acl, ip net.IP) (bool, error) {
-	for _, entry := range acl.Value.CIDRs {
-		var mask int64 = 32
-		if entry.Mask != nil {
-			mask = entry.Mask.Value
-		}
-
-		cidr := fmt.Sprintf("%s/%d", entry.IP.Value, mask)
-		_, ipnet, err := net.ParseCIDR(cidr)
-		if err != nil {
-			return false, fmt.Errorf("Failed to parse CIDR %s", cidr)
-		}
-		if ipnet.Contains(ip) {
-			return true, nil
-		} else if entry.Inverse != nil && entry.Inverse.Value {
-			return true, nil
-		}
-	}
-	return false, nil
-}
-
 func NotRegex(ctx *context.Context, left, right value.Value) (value.Value, error) {
 	b, err := Regex(ctx, left, right)
 	if err != nil {
@@ -808,36 +790,56 @@ func LogicalOr(left, right value.Value) (value.Value, error) {
 }
 
 func Concat(left, right value.Value) (value.Value, error) {
-	switch left.Type() {
-	case value.AclType, value.IdentType:
-		return value.Null, errors.WithStack(
-			fmt.Errorf("%s type could not use for left concatenation expression", left.Type()),
-		)
-	case value.StringType, value.BooleanType:
-		break
-	default:
-		if left.IsLiteral() {
-			return value.Null, errors.WithStack(
-				fmt.Errorf("%s type could not use as literal for left concatenation expression", left.Type()),
-			)
+	return ConcatValues(left, right)
+}
+
+// Scratch buffers to build concatenated string, which are reused between expressions
+var concatBuffers = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// ConcatValues concatenates values at once like chained "+" operators,
+// the first value is validated as the left operand and others are validated as the right operand.
+// It avoids allocating intermediate strings of each operator.
+func ConcatValues(values ...value.Value) (value.Value, error) {
+	for i, v := range values {
+		side := "right"
+		if i == 0 {
+			side = "left"
+		}
+		if err := validateConcatOperand(v, side); err != nil {
+			return value.Null, err
 		}
 	}
-	switch right.Type() {
+
+	buf := concatBuffers.Get().(*bytes.Buffer) // nolint:errcheck
+	buf.Reset()
+	for _, v := range values {
+		buf.WriteString(v.String())
+	}
+	// String() copies the bytes so the buffer could be reused safely
+	concatenated := buf.String()
+	concatBuffers.Put(buf)
+
+	return &value.String{Value: concatenated}, nil
+}
+
+func validateConcatOperand(v value.Value, side string) error {
+	switch v.Type() {
 	case value.AclType, value.IdentType:
-		return value.Null, errors.WithStack(
-			fmt.Errorf("%s type could not unse for right concatenation expression", right.Type()),
+		return errors.WithStack(
+			fmt.Errorf("%s type could not use for %s concatenation expression", v.Type(), side),
 		)
 	case value.StringType, value.BooleanType:
-		break
+		return nil
 	default:
-		if right.IsLiteral() {
-			return value.Null, errors.WithStack(
-				fmt.Errorf("%s type could not use as literal for right concatenation expression", right.Type()),
+		if v.IsLiteral() {
+			return errors.WithStack(
+				fmt.Errorf("%s type could not use as literal for %s concatenation expression", v.Type(), side),
 			)
 		}
+		return nil
 	}
-
-	return &value.String{
-		Value: left.String() + right.String(),
-	}, nil
 }
@@ -393,6 +393,15 @@ func TestRegexOperator(t *testing.T) {
 					IP:      &ast.IP{Value: "127.0.0.0"},
 					Mask:    &ast.Integer{Value: 16},
 				},
+				{
+					Inverse: &ast.Boolean{Value: true},
+					IP:      &ast.IP{Value: "127.0.1.0"},
+					Mask:    &ast.Integer{Value: 24},
+				},
+				{
+					IP:   &ast.IP{Value: "2001:db8::"},
+					Mask: &ast.Integer{Value: 32},
+				},
 			},
 		}
 		tests := []struct {
@@ -415,6 +424,9 @@ func TestRegexOperator(t *testing.T) {
 			{left: &value.IP{Value: v}, right: &value.Boolean{Value: true, Literal: true}, isError: true},
 			{left: &value.IP{Value: v}, right: &value.IP{Value: net.ParseIP("127.0.0.1")}, isError: true},
 			{left: &value.IP{Value: v}, right: &value.Acl{Value: acl}, expect: true},
+			{left: &value.IP{Value: net.ParseIP("127.0.1.1")}, right: &value.Acl{Value: acl}, expect: false},
+			{left: &value.IP{Value: net.ParseIP("2001:db8::1")}, right: &value.Acl{Value: acl}, expect: true},
+			{left: &value.IP{Value: net.ParseIP("2001:db9::1")}, right: &value.Acl{Value: acl}, expect: false},
 		}
 
 		for i, tt := range tests {
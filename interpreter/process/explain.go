@@ -0,0 +1,79 @@
+package process
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/context"
+)
+
+// Decision is a statement or backend response header which affects caching behavior
+type Decision struct {
+	Scope    string `json:"scope"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Position int    `json:"position,omitempty"`
+	// Statement or header which decides
+	Source string `json:"source"`
+	// Human-readable effect of the source
+	Reason string `json:"reason"`
+}
+
+// CacheExplanation explains why the response is cached or not, like EXPLAIN of query planner.
+// Decisions are recorded in evaluation order so the later decision may override the former.
+type CacheExplanation struct {
+	Decisions []*Decision `json:"decisions"`
+	Result    string      `json:"result"`
+}
+
+func NewCacheExplanation() *CacheExplanation {
+	return &CacheExplanation{
+		Decisions: []*Decision{},
+	}
+}
+
+// Decide records the decision, node is nil when the decision is not made by VCL like backend response header
+func (e *CacheExplanation) Decide(scope context.Scope, node ast.Node, source, reason string) {
+	if e == nil {
+		return
+	}
+	d := &Decision{
+		Scope:  scope.String(),
+		Source: source,
+		Reason: reason,
+	}
+	if node != nil {
+		token := node.GetMeta().Token
+		d.File = token.File
+		d.Line = token.Line
+		d.Position = token.Position
+	}
+	e.Decisions = append(e.Decisions, d)
+}
+
+// Conclude sets the final result, the last conclusion wins when the request is restarted
+func (e *CacheExplanation) Conclude(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	e.Result = fmt.Sprintf(format, args...)
+}
+
+func (e *CacheExplanation) String() string {
+	var buf bytes.Buffer
+
+	result := e.Result
+	if result == "" {
+		result = "not concluded"
+	}
+	buf.WriteString("Cache decision: " + result + "\n")
+	for i, d := range e.Decisions {
+		buf.WriteString(fmt.Sprintf("  %d. [%s] %s", i+1, d.Scope, d.Source))
+		if d.File != "" || d.Line > 0 {
+			buf.WriteString(fmt.Sprintf(" at %s:%d:%d", d.File, d.Line, d.Position))
+		}
+		buf.WriteString("\n     -> " + d.Reason + "\n")
+	}
+	return buf.String()
+}
@@ -12,21 +12,29 @@ import (
 )
 
 type Process struct {
-	Flows     []*Flow
-	Logs      []*Log
-	Restarts  int
-	Backend   *value.Backend
-	Cached    bool
-	Error     error
-	StartTime int64
-	Response  *http.Response
+	Flows       []*Flow
+	Logs        []*Log
+	Transitions []*Transition
+	Conditions  []*Condition
+	Restarts    int
+	Backend     *value.Backend
+	Cached      bool
+	Error       error
+	StartTime   int64
+	Response    *http.Response
+	// Time-travel execution log, only recorded when enabled
+	TimeTravel *TimeTravel
+	// Explanation of caching decision, only recorded when enabled
+	CacheExplanation *CacheExplanation
 }
 
 func New() *Process {
 	return &Process{
-		Flows:     []*Flow{},
-		Logs:      []*Log{},
-		StartTime: time.Now().UnixMicro(),
+		Flows:       []*Flow{},
+		Logs:        []*Log{},
+		Transitions: []*Transition{},
+		Conditions:  []*Condition{},
+		StartTime:   time.Now().UnixMicro(),
 	}
 }
 
@@ -57,28 +65,38 @@ func (p *Process) Finalize(resp *http.Response) ([]byte, error) {
 	}
 
 	return json.MarshalIndent(struct {
-		Flows          []*Flow `json:"flows"`
-		Logs           []*Log  `json:"logs"`
-		Restarts       int     `json:"restarts"`
-		Backend        string  `json:"backend"`
-		Cached         bool    `json:"cached"`
-		ElapsedTimeUs  int64   `json:"elapsed_time_us"`
-		ElapsedTimeMs  int64   `json:"elapsed_time_ms"`
-		Error          error   `json:"error,omitempty"`
-		ClientResponse struct {
+		Flows             []*Flow           `json:"flows"`
+		Logs              []*Log            `json:"logs"`
+		Transitions       []*Transition     `json:"transitions"`
+		MatchedConditions []*Condition      `json:"matched_conditions"`
+		Restarts          int               `json:"restarts"`
+		Backend           string            `json:"backend"`
+		Cached            bool              `json:"cached"`
+		CacheAction       string            `json:"cache_action"`
+		ElapsedTimeUs     int64             `json:"elapsed_time_us"`
+		ElapsedTimeMs     int64             `json:"elapsed_time_ms"`
+		Error             error             `json:"error,omitempty"`
+		TimeTravel        *TimeTravel       `json:"time_travel,omitempty"`
+		CacheExplanation  *CacheExplanation `json:"cache_explanation,omitempty"`
+		ClientResponse    struct {
 			StatusCode    int               `json:"status_code"`
 			ResponseBytes int               `json:"body_bytes"`
 			Headers       map[string]string `json:"headers"`
 		} `json:"client_response"`
 	}{
-		Flows:         p.Flows,
-		Logs:          p.Logs,
-		Restarts:      p.Restarts,
-		Backend:       backend,
-		Cached:        false,
-		ElapsedTimeUs: time.Now().UnixMicro() - p.StartTime,
-		ElapsedTimeMs: time.Now().UnixMilli() - (p.StartTime / 1000),
-		Error:         p.Error,
+		Flows:             p.Flows,
+		Logs:              p.Logs,
+		Transitions:       p.Transitions,
+		MatchedConditions: p.Conditions,
+		Restarts:          p.Restarts,
+		Backend:           backend,
+		Cached:            p.Cached,
+		CacheAction:       p.CacheAction(),
+		ElapsedTimeUs:     time.Now().UnixMicro() - p.StartTime,
+		ElapsedTimeMs:     time.Now().UnixMilli() - (p.StartTime / 1000),
+		Error:             p.Error,
+		TimeTravel:        p.TimeTravel,
+		CacheExplanation:  p.CacheExplanation,
 		ClientResponse: struct {
 			StatusCode    int               `json:"status_code"`
 			ResponseBytes int               `json:"body_bytes"`
@@ -0,0 +1,35 @@
+package process
+
+import (
+	icontext "github.com/ysugimoto/falco/interpreter/context"
+)
+
+// Snapshot holds copied HTTP request/response values at the moment
+type Snapshot struct {
+	Request         *HttpFlow `json:"req,omitempty"`
+	BackendRequest  *HttpFlow `json:"bereq,omitempty"`
+	BackendResponse *HttpFlow `json:"beresp,omitempty"`
+	Response        *HttpFlow `json:"resp,omitempty"`
+	Object          *HttpFlow `json:"object,omitempty"`
+}
+
+// Flow values are built by reading current values, so the request is not cloned
+func NewSnapshot(ctx *icontext.Context) *Snapshot {
+	s := &Snapshot{}
+	if ctx.Request != nil {
+		s.Request = newFlowRequest(ctx.Request)
+	}
+	if ctx.BackendRequest != nil {
+		s.BackendRequest = newFlowRequest(ctx.BackendRequest)
+	}
+	if ctx.BackendResponse != nil {
+		s.BackendResponse = newFlowResponse(ctx.BackendResponse)
+	}
+	if ctx.Response != nil {
+		s.Response = newFlowResponse(ctx.Response)
+	}
+	if ctx.Object != nil {
+		s.Object = newFlowResponse(ctx.Object)
+	}
+	return s
+}
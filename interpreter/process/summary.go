@@ -0,0 +1,120 @@
+package process
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/context"
+)
+
+// Debug response header names which summarize the request process
+const (
+	DebugHeaderStates     = "Falco-Debug-States"
+	DebugHeaderCache      = "Falco-Debug-Cache"
+	DebugHeaderElapsed    = "Falco-Debug-Elapsed"
+	DebugHeaderRestarts   = "Falco-Debug-Restarts"
+	DebugHeaderBackend    = "Falco-Debug-Backend"
+	DebugHeaderConditions = "Falco-Debug-Conditions"
+)
+
+// Transition is a state which is returned from the subroutine of the scope
+type Transition struct {
+	Scope string `json:"scope"`
+	State string `json:"state"`
+}
+
+func (t *Transition) String() string {
+	if t.State == "" {
+		return t.Scope
+	}
+	return fmt.Sprintf("%s(%s)", t.Scope, t.State)
+}
+
+// Condition is matched condition of if or else if statement
+type Condition struct {
+	Scope      string `json:"scope"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Position   int    `json:"position"`
+	Expression string `json:"expression"`
+}
+
+func NewCondition(stmt *ast.IfStatement, scope context.Scope) *Condition {
+	token := stmt.GetMeta().Token
+	return &Condition{
+		Scope:      scope.String(),
+		File:       token.File,
+		Line:       token.Line,
+		Position:   token.Position,
+		Expression: strings.TrimSpace(stmt.Condition.String()),
+	}
+}
+
+func (p *Process) Transition(scope context.Scope, state string) {
+	if p == nil {
+		return
+	}
+	p.Transitions = append(p.Transitions, &Transition{Scope: scope.String(), State: state})
+}
+
+func (p *Process) MatchCondition(stmt *ast.IfStatement, scope context.Scope) {
+	if p == nil {
+		return
+	}
+	p.Conditions = append(p.Conditions, NewCondition(stmt, scope))
+}
+
+// CacheAction returns cache action of the request, one of HIT, MISS, PASS and NONE.
+// NONE means the response is generated without looking up the cache like error statement in vcl_recv.
+func (p *Process) CacheAction() string {
+	if p.Cached {
+		return "HIT"
+	}
+	action := "NONE"
+	for _, t := range p.Transitions {
+		switch t.Scope {
+		case context.PassScope.String():
+			return "PASS"
+		case context.MissScope.String():
+			action = "MISS"
+		}
+	}
+	return action
+}
+
+func (p *Process) ElapsedTime() time.Duration {
+	return time.Duration(time.Now().UnixMicro()-p.StartTime) * time.Microsecond
+}
+
+// DebugHeaders returns response headers which summarize the request process like Fastly debug headers
+func (p *Process) DebugHeaders() http.Header {
+	states := make([]string, len(p.Transitions))
+	for i := range p.Transitions {
+		states[i] = p.Transitions[i].String()
+	}
+	conditions := make([]string, len(p.Conditions))
+	for i, c := range p.Conditions {
+		conditions[i] = fmt.Sprintf("%s:%d:%d", c.File, c.Line, c.Position)
+	}
+	var backend string
+	if p.Backend != nil {
+		backend = p.Backend.Value.Name.Value
+	}
+
+	h := http.Header{}
+	h.Set(DebugHeaderStates, strings.Join(states, ", "))
+	h.Set(DebugHeaderCache, p.CacheAction())
+	h.Set(DebugHeaderElapsed, p.ElapsedTime().String())
+	h.Set(DebugHeaderRestarts, strconv.Itoa(p.Restarts))
+	if backend != "" {
+		h.Set(DebugHeaderBackend, backend)
+	}
+	if len(conditions) > 0 {
+		h.Set(DebugHeaderConditions, strings.Join(conditions, ", "))
+	}
+	return h
+}
@@ -0,0 +1,136 @@
+package process
+
+import (
+	"sort"
+)
+
+// Step kinds of time-travel log
+const (
+	StepState      = "state"
+	StepFetch      = "fetch"
+	StepSubroutine = "subroutine"
+	StepStatement  = "statement"
+)
+
+// Change is a delta of the variable which is made by the step.
+// Before or After is nil when the variable is not set.
+type Change struct {
+	Name   string  `json:"name"`
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+// Step is an evaluation step of the request
+type Step struct {
+	Index      int       `json:"index"`
+	Kind       string    `json:"kind"`
+	Scope      string    `json:"scope"`
+	Subroutine string    `json:"subroutine,omitempty"`
+	File       string    `json:"file,omitempty"`
+	Line       int       `json:"line,omitempty"`
+	Position   int       `json:"position,omitempty"`
+	Statement  string    `json:"statement"`
+	Changes    []*Change `json:"changes"`
+}
+
+// TimeTravel is an execution log which each step stores variable deltas,
+// so the viewer could scrub backward and forward through the request evaluation.
+type TimeTravel struct {
+	// Variables before the first step
+	Initial map[string]string `json:"initial"`
+	Steps   []*Step           `json:"steps"`
+
+	current     map[string]string
+	subroutines []string
+}
+
+func NewTimeTravel() *TimeTravel {
+	return &TimeTravel{
+		Initial: map[string]string{},
+		Steps:   []*Step{},
+	}
+}
+
+// Record appends the step with variables which are captured just before the step is executed.
+// The delta from the previous capture is made by the previous step.
+func (t *TimeTravel) Record(step *Step, vars map[string]string) {
+	if len(t.Steps) == 0 {
+		t.Initial = vars
+	} else {
+		t.Steps[len(t.Steps)-1].Changes = Diff(t.current, vars)
+	}
+	if step.Subroutine == "" && len(t.subroutines) > 0 {
+		step.Subroutine = t.subroutines[len(t.subroutines)-1]
+	}
+	step.Index = len(t.Steps)
+	step.Changes = []*Change{}
+	t.Steps = append(t.Steps, step)
+	t.current = vars
+}
+
+// Finish records the delta of the last step
+func (t *TimeTravel) Finish(vars map[string]string) {
+	if len(t.Steps) == 0 {
+		return
+	}
+	t.Steps[len(t.Steps)-1].Changes = Diff(t.current, vars)
+	t.current = vars
+}
+
+// Enter and Leave track the subroutine which following steps belong to
+func (t *TimeTravel) Enter(subroutine string) {
+	t.subroutines = append(t.subroutines, subroutine)
+}
+
+func (t *TimeTravel) Leave() {
+	if len(t.subroutines) > 0 {
+		t.subroutines = t.subroutines[:len(t.subroutines)-1]
+	}
+}
+
+// Restore returns variables after the step of index is executed.
+// Negative index returns the initial variables.
+func (t *TimeTravel) Restore(index int) map[string]string {
+	vars := make(map[string]string, len(t.Initial))
+	for k, v := range t.Initial {
+		vars[k] = v
+	}
+	for i := 0; i <= index && i < len(t.Steps); i++ {
+		for _, c := range t.Steps[i].Changes {
+			if c.After == nil {
+				delete(vars, c.Name)
+			} else {
+				vars[c.Name] = *c.After
+			}
+		}
+	}
+	return vars
+}
+
+// Diff returns changes between two variable sets in name order
+func Diff(before, after map[string]string) []*Change {
+	changes := []*Change{}
+	for name, a := range after {
+		if b, ok := before[name]; ok && a == b {
+			continue
+		}
+		c := &Change{Name: name, After: stringPtr(a)}
+		if b, ok := before[name]; ok {
+			c.Before = stringPtr(b)
+		}
+		changes = append(changes, c)
+	}
+	for name, b := range before {
+		if _, ok := after[name]; !ok {
+			changes = append(changes, &Change{Name: name, Before: stringPtr(b)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
@@ -0,0 +1,165 @@
+// Falco's interpreter edge rate limiting is simply in-memory,
+// counts are stored in one second buckets so that windows are computed from the simulated clock
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Counts older than the largest window are never read
+const maxWindow = 60 * time.Second
+
+// Ratecounter counts requests of each entry like client IP
+type Ratecounter struct {
+	mu      sync.Mutex
+	buckets map[string]map[int64]int64
+	// Entry which is incremented at last, ratecounter variables refer this entry
+	lastEntry string
+}
+
+func NewRatecounter() *Ratecounter {
+	return &Ratecounter{
+		buckets: make(map[string]map[int64]int64),
+	}
+}
+
+// Increment adds delta to the bucket of the entry at the time
+func (r *Ratecounter) Increment(entry string, delta int64, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buckets, ok := r.buckets[entry]
+	if !ok {
+		buckets = make(map[int64]int64)
+		r.buckets[entry] = buckets
+	}
+	buckets[now.Unix()] += delta
+	r.lastEntry = entry
+
+	// Drop expired buckets not to grow the memory on long simulation
+	for sec := range buckets {
+		if sec <= now.Add(-maxWindow).Unix() {
+			delete(buckets, sec)
+		}
+	}
+}
+
+func (r *Ratecounter) count(entry string, from, to time.Time) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var total int64
+	for sec, v := range r.buckets[entry] {
+		if sec > from.Unix() && sec <= to.Unix() {
+			total += v
+		}
+	}
+	return total
+}
+
+// Bucket returns total count of the entry in the duration window which ends at the time
+func (r *Ratecounter) Bucket(entry string, window time.Duration, now time.Time) int64 {
+	return r.count(entry, now.Add(-window), now)
+}
+
+// Rate returns requests per second of the entry in the window
+func (r *Ratecounter) Rate(entry string, window time.Duration, now time.Time) float64 {
+	if window <= 0 {
+		return 0
+	}
+	return float64(r.Bucket(entry, window, now)) / window.Seconds()
+}
+
+// LastEntry returns the entry which is incremented at last
+func (r *Ratecounter) LastEntry() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastEntry
+}
+
+// Penaltybox holds entries which exceeded the rate until they expire
+type Penaltybox struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func NewPenaltybox() *Penaltybox {
+	return &Penaltybox{
+		entries: make(map[string]time.Time),
+	}
+}
+
+// Add puts the entry into the penaltybox for the ttl.
+// Fastly penaltybox has minute granularity, so ttl is truncated to whole minutes like 90s to 1m,
+// and the entry is not added when ttl is less than a minute.
+func (p *Penaltybox) Add(entry string, ttl time.Duration, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ttl = ttl.Truncate(time.Minute)
+	if ttl <= 0 {
+		return
+	}
+	p.entries[entry] = now.Add(ttl)
+}
+
+// Has returns true if the entry is in the penaltybox at the time
+func (p *Penaltybox) Has(entry string, now time.Time) bool {
+	return p.TTL(entry, now) > 0
+}
+
+// TTL returns remaining duration until the entry expires, zero if the entry is not in the penaltybox
+func (p *Penaltybox) TTL(entry string, now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	expires, ok := p.entries[entry]
+	if !ok {
+		return 0
+	}
+	if !now.Before(expires) {
+		delete(p.entries, entry)
+		return 0
+	}
+	return expires.Sub(now)
+}
+
+// Store holds ratecounters and penaltyboxes which are shared between requests
+type Store struct {
+	mu           sync.Mutex
+	ratecounters map[string]*Ratecounter
+	penaltyboxes map[string]*Penaltybox
+}
+
+func New() *Store {
+	return &Store{
+		ratecounters: make(map[string]*Ratecounter),
+		penaltyboxes: make(map[string]*Penaltybox),
+	}
+}
+
+// Ratecounter returns the ratecounter of the name, created on first access
+func (s *Store) Ratecounter(name string) *Ratecounter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rc, ok := s.ratecounters[name]; ok {
+		return rc
+	}
+	rc := NewRatecounter()
+	s.ratecounters[name] = rc
+	return rc
+}
+
+// Penaltybox returns the penaltybox of the name, created on first access
+func (s *Store) Penaltybox(name string) *Penaltybox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pb, ok := s.penaltyboxes[name]; ok {
+		return pb
+	}
+	pb := NewPenaltybox()
+	s.penaltyboxes[name] = pb
+	return pb
+}
@@ -0,0 +1,354 @@
+package shadow
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter"
+)
+
+// Following headers always differ between backends so ignore them on comparison
+var defaultIgnoreHeaders = []string{
+	"date",
+	"age",
+	"server",
+	"connection",
+	"keep-alive",
+	"transfer-encoding",
+	"x-request-id",
+}
+
+// Following headers differ when Go HTTP client has transparently decompressed gzip response of either backend,
+// so ignore them on comparison in that case. Bodies are compared after decompression.
+var compressionHeaders = []string{
+	"content-encoding",
+	"content-length",
+}
+
+// Length of bytes which are reported from the first different offset of bodies
+const bodyDiffLength = 32
+
+// HeaderDiff represents header value difference between primary and shadow response
+type HeaderDiff struct {
+	Name    string `json:"name"`
+	Primary string `json:"primary"`
+	Shadow  string `json:"shadow"`
+}
+
+// BodyDiff represents body difference between primary and shadow response.
+// Primary and Shadow are bytes from Offset which are set only when body diff is enabled.
+type BodyDiff struct {
+	PrimarySize   int    `json:"primary_size"`
+	ShadowSize    int    `json:"shadow_size"`
+	PrimaryDigest string `json:"primary_digest"`
+	ShadowDigest  string `json:"shadow_digest"`
+	Offset        int    `json:"offset"`
+	Primary       string `json:"primary,omitempty"`
+	Shadow        string `json:"shadow,omitempty"`
+}
+
+// Report is the comparison result of primary and shadow backend responses
+type Report struct {
+	Method        string        `json:"method"`
+	URL           string        `json:"url"`
+	Backend       string        `json:"backend"`
+	PrimaryStatus int           `json:"primary_status"`
+	ShadowStatus  int           `json:"shadow_status"`
+	Headers       []*HeaderDiff `json:"headers,omitempty"`
+	Body          *BodyDiff     `json:"body,omitempty"`
+	Error         string        `json:"error,omitempty"`
+}
+
+func (r *Report) IsMatched() bool {
+	return r.Error == "" && r.PrimaryStatus == r.ShadowStatus && len(r.Headers) == 0 && r.Body == nil
+}
+
+func (r *Report) String() string {
+	if r.Error != "" {
+		return fmt.Sprintf("[SHADOW] %s %s: %s", r.Method, r.URL, r.Error)
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[SHADOW] %s %s", r.Method, r.URL))
+	if r.IsMatched() {
+		b.WriteString(": matched")
+		return b.String()
+	}
+	if r.PrimaryStatus != r.ShadowStatus {
+		b.WriteString(fmt.Sprintf("\n  status: %d (primary) != %d (shadow)", r.PrimaryStatus, r.ShadowStatus))
+	}
+	for _, h := range r.Headers {
+		b.WriteString(fmt.Sprintf("\n  %s: %q (primary) != %q (shadow)", h.Name, h.Primary, h.Shadow))
+	}
+	if r.Body != nil {
+		b.WriteString(fmt.Sprintf(
+			"\n  body: %d bytes, sha256:%s (primary) != %d bytes, sha256:%s (shadow)",
+			r.Body.PrimarySize, r.Body.PrimaryDigest, r.Body.ShadowSize, r.Body.ShadowDigest,
+		))
+		if r.Body.Primary != "" || r.Body.Shadow != "" {
+			b.WriteString(fmt.Sprintf(
+				"\n  body at byte %d: %q (primary) != %q (shadow)",
+				r.Body.Offset, r.Body.Primary, r.Body.Shadow,
+			))
+		}
+	}
+	return b.String()
+}
+
+// Shadow mirrors backend requests to the shadow backend asynchronously
+// and compares shadow response with primary one.
+// Mirroring never affects the response of the primary backend.
+type Shadow struct {
+	backend       *url.URL
+	paths         []glob.Glob
+	ignoreHeaders map[string]struct{}
+	bodyDiff      bool
+	client        *http.Client
+	onReport      func(*Report)
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	reports []*Report
+}
+
+type Option func(s *Shadow)
+
+// WithReporter sets the function which is called when comparison has completed
+func WithReporter(fn func(*Report)) Option {
+	return func(s *Shadow) {
+		s.onReport = fn
+	}
+}
+
+// WithClient overrides HTTP client to send the shadow request
+func WithClient(c *http.Client) Option {
+	return func(s *Shadow) {
+		s.client = c
+	}
+}
+
+func New(c *config.ShadowConfig, opts ...Option) (*Shadow, error) {
+	backend, err := url.Parse(c.Backend)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if backend.Scheme == "" || backend.Host == "" {
+		return nil, errors.WithStack(
+			fmt.Errorf("Shadow backend must be an absolute URL, got %s", c.Backend),
+		)
+	}
+
+	s := &Shadow{
+		backend:       backend,
+		ignoreHeaders: make(map[string]struct{}),
+		bodyDiff:      c.BodyDiff,
+		client:        &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, p := range c.Paths {
+		g, err := glob.Compile(p)
+		if err != nil {
+			return nil, errors.WithStack(
+				fmt.Errorf("Invalid glob pattern is provided: %s, %s", p, err),
+			)
+		}
+		s.paths = append(s.paths, g)
+	}
+	for _, h := range append(defaultIgnoreHeaders, c.IgnoreHeaders...) {
+		s.ignoreHeaders[strings.ToLower(h)] = struct{}{}
+	}
+	for i := range opts {
+		opts[i](s)
+	}
+	return s, nil
+}
+
+// Hook implements interpreter.Hook, mirror the backend request after the primary fetch
+func (s *Shadow) Hook(e *interpreter.HookEvent) error {
+	if e.Type != interpreter.HookAfterFetch {
+		return nil
+	}
+	if e.Snapshot.BackendRequest == nil || e.Snapshot.BackendResponse == nil {
+		return nil
+	}
+	if !s.match(e.Snapshot.BackendRequest.URL) {
+		return nil
+	}
+
+	s.wg.Add(1)
+	go func(e *interpreter.HookEvent) {
+		defer s.wg.Done()
+		s.add(s.mirror(e))
+	}(e)
+	return nil
+}
+
+// Wait waits for all in-flight shadow requests
+func (s *Shadow) Wait() {
+	s.wg.Wait()
+}
+
+// Reports returns all comparison reports
+func (s *Shadow) Reports() []*Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reports := make([]*Report, len(s.reports))
+	copy(reports, s.reports)
+	return reports
+}
+
+func (s *Shadow) match(u string) bool {
+	if len(s.paths) == 0 {
+		return true
+	}
+	path := u
+	if idx := strings.Index(path, "?"); idx != -1 {
+		path = path[:idx]
+	}
+	for _, p := range s.paths {
+		if p.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Shadow) add(r *Report) {
+	s.mu.Lock()
+	s.reports = append(s.reports, r)
+	s.mu.Unlock()
+
+	if s.onReport != nil {
+		s.onReport(r)
+	}
+}
+
+func (s *Shadow) mirror(e *interpreter.HookEvent) *Report {
+	bereq, beresp := e.Snapshot.BackendRequest, e.Snapshot.BackendResponse
+	report := &Report{
+		Method:        bereq.Method,
+		URL:           bereq.URL,
+		Backend:       e.Backend,
+		PrimaryStatus: beresp.StatusCode,
+	}
+
+	// Body which has been sent to the primary backend is replayed to the shadow backend
+	var reqBody io.Reader
+	if len(e.BackendRequestBody) > 0 {
+		reqBody = bytes.NewReader(e.BackendRequestBody)
+	}
+	req, err := http.NewRequest(bereq.Method, strings.TrimSuffix(s.backend.String(), "/")+bereq.URL, reqBody)
+	if err != nil {
+		report.Error = fmt.Sprintf("Failed to create shadow request: %s", err)
+		return report
+	}
+	for key, val := range bereq.Headers {
+		req.Header.Set(key, val)
+	}
+	// Host header should be the same as primary request
+	if v, ok := bereq.Headers["host"]; ok {
+		req.Host = v
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		report.Error = fmt.Sprintf("Failed to retrieve shadow response: %s", err)
+		return report
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		report.Error = fmt.Sprintf("Failed to read shadow response body: %s", err)
+		return report
+	}
+
+	report.ShadowStatus = resp.StatusCode
+	report.Headers = s.compareHeaders(beresp.Headers, resp.Header, e.BackendResponseUncompressed || resp.Uncompressed)
+	report.Body = s.compareBodies(e.BackendResponseBody, body)
+	return report
+}
+
+func (s *Shadow) compareHeaders(primary map[string]string, shadow http.Header, uncompressed bool) []*HeaderDiff {
+	shadowHeaders := make(map[string]string)
+	for key, values := range shadow {
+		shadowHeaders[strings.ToLower(key)] = strings.Join(values, ", ")
+	}
+
+	names := make(map[string]struct{})
+	for key := range primary {
+		names[key] = struct{}{}
+	}
+	for key := range shadowHeaders {
+		names[key] = struct{}{}
+	}
+
+	var diffs []*HeaderDiff
+	for name := range names {
+		if _, ok := s.ignoreHeaders[name]; ok {
+			continue
+		}
+		if uncompressed && isCompressionHeader(name) {
+			continue
+		}
+		if primary[name] == shadowHeaders[name] {
+			continue
+		}
+		diffs = append(diffs, &HeaderDiff{
+			Name:    name,
+			Primary: primary[name],
+			Shadow:  shadowHeaders[name],
+		})
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Name < diffs[j].Name
+	})
+	return diffs
+}
+
+func isCompressionHeader(name string) bool {
+	for _, v := range compressionHeaders {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Shadow) compareBodies(primary, shadow []byte) *BodyDiff {
+	if bytes.Equal(primary, shadow) {
+		return nil
+	}
+	diff := &BodyDiff{
+		PrimarySize:   len(primary),
+		ShadowSize:    len(shadow),
+		PrimaryDigest: digest(primary),
+		ShadowDigest:  digest(shadow),
+	}
+	if !s.bodyDiff {
+		return diff
+	}
+
+	// Find the first different byte, it is the end of shorter body when one is the prefix of another
+	for diff.Offset < len(primary) && diff.Offset < len(shadow) && primary[diff.Offset] == shadow[diff.Offset] {
+		diff.Offset++
+	}
+	diff.Primary = string(primary[diff.Offset:min(diff.Offset+bodyDiffLength, len(primary))])
+	diff.Shadow = string(shadow[diff.Offset:min(diff.Offset+bodyDiffLength, len(shadow))])
+	return diff
+}
+
+func digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
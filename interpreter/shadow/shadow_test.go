@@ -0,0 +1,235 @@
+package shadow
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/interpreter"
+	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/process"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestShadow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Version", "v2")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s, err := New(&config.ShadowConfig{
+		Backend: server.URL,
+		Paths:   []string{"/api/**", "/missing"},
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	event := func(url string) *interpreter.HookEvent {
+		return &interpreter.HookEvent{
+			Type:    interpreter.HookAfterFetch,
+			Backend: "example",
+			Snapshot: &process.Snapshot{
+				BackendRequest: &process.HttpFlow{
+					Method:  http.MethodGet,
+					URL:     url,
+					Headers: map[string]string{},
+				},
+				BackendResponse: &process.HttpFlow{
+					StatusCode: http.StatusOK,
+					Headers: map[string]string{
+						"x-version":      "v1",
+						"content-length": "0",
+						"date":           "Mon, 02 Jan 2006 15:04:05 GMT",
+					},
+				},
+			},
+		}
+	}
+
+	for _, url := range []string{"/api/v1/foo?bar=baz", "/missing", "/other"} {
+		if err := s.Hook(event(url)); err != nil {
+			t.Errorf("Unexpected hook error: %s", err)
+			return
+		}
+	}
+	s.Wait()
+
+	reports := s.Reports()
+	if len(reports) != 2 {
+		t.Errorf("Reports count unmatch, expect 2, got %d", len(reports))
+		return
+	}
+	for _, r := range reports {
+		if r.IsMatched() {
+			t.Errorf("Report should not be matched: %s", r.String())
+		}
+		if diff := cmp.Diff([]*HeaderDiff{
+			{Name: "x-version", Primary: "v1", Shadow: "v2"},
+		}, r.Headers); diff != "" {
+			t.Errorf("Header diff unmatch, diff: %s", diff)
+		}
+		switch r.URL {
+		case "/missing":
+			if r.ShadowStatus != http.StatusNotFound {
+				t.Errorf("Shadow status unmatch, expect 404, got %d", r.ShadowStatus)
+			}
+		default:
+			if r.ShadowStatus != http.StatusOK {
+				t.Errorf("Shadow status unmatch, expect 200, got %d", r.ShadowStatus)
+			}
+		}
+	}
+}
+
+func TestShadowRequestBody(t *testing.T) {
+	// Record the request body which each backend receives
+	bodies := make(chan string, 2)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body) // nolint:errcheck
+		bodies <- r.Method + " " + string(b)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok")) // nolint:errcheck
+	}
+	primary := httptest.NewServer(http.HandlerFunc(handler))
+	defer primary.Close()
+	shadow := httptest.NewServer(http.HandlerFunc(handler))
+	defer shadow.Close()
+
+	parsed, err := url.Parse(primary.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+	vcl := fmt.Sprintf(`
+backend example {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  return(pass);
+}`, parsed.Hostname(), parsed.Port())
+
+	s, err := New(&config.ShadowConfig{Backend: shadow.URL})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	ip := interpreter.New(icontext.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	ip.AddHook(s.Hook)
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/api", strings.NewReader(`{"foo":"bar"}`))
+	if _, err := ip.Serve(req); err != nil {
+		t.Errorf("Unexpected serve error: %s", err)
+		return
+	}
+	s.Wait()
+
+	expect := `POST {"foo":"bar"}`
+	for _, name := range []string{"primary", "shadow"} {
+		select {
+		case body := <-bodies:
+			if body != expect {
+				t.Errorf("Request body unmatch, expect=%s, got=%s", expect, body)
+			}
+		default:
+			t.Errorf("Request is not sent to the %s backend", name)
+		}
+	}
+	// Response body of the primary backend is compared with the shadow one
+	if reports := s.Reports(); len(reports) != 1 || !reports[0].IsMatched() {
+		t.Errorf("Unexpected reports: %v", reports)
+	}
+}
+
+func TestShadowBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Write([]byte("hello shadow")) // nolint:errcheck
+			return
+		}
+		// Response is transparently decompressed by Go HTTP client
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write([]byte("hello world")) // nolint:errcheck
+		gz.Close()
+	}))
+	defer server.Close()
+
+	s, err := New(&config.ShadowConfig{Backend: server.URL, BodyDiff: true})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	event := func(headers map[string]string) *interpreter.HookEvent {
+		return &interpreter.HookEvent{
+			Type:                interpreter.HookAfterFetch,
+			Backend:             "example",
+			BackendResponseBody: []byte("hello world"),
+			Snapshot: &process.Snapshot{
+				BackendRequest: &process.HttpFlow{
+					Method:  http.MethodGet,
+					URL:     "/",
+					Headers: headers,
+				},
+				BackendResponse: &process.HttpFlow{
+					StatusCode: http.StatusOK,
+					Headers: map[string]string{
+						"content-type":   "text/plain",
+						"content-length": "11",
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("compressed response is compared after decompression", func(t *testing.T) {
+		report := s.mirror(event(map[string]string{}))
+		if !report.IsMatched() {
+			t.Errorf("Report should be matched: %s", report.String())
+		}
+	})
+
+	t.Run("different body", func(t *testing.T) {
+		report := s.mirror(event(map[string]string{"accept-encoding": "identity"}))
+		if diff := cmp.Diff([]*HeaderDiff{
+			{Name: "content-length", Primary: "11", Shadow: "12"},
+		}, report.Headers); diff != "" {
+			t.Errorf("Header diff unmatch, diff: %s", diff)
+		}
+		if diff := cmp.Diff(&BodyDiff{
+			PrimarySize:   11,
+			ShadowSize:    12,
+			PrimaryDigest: digest([]byte("hello world")),
+			ShadowDigest:  digest([]byte("hello shadow")),
+			Offset:        6,
+			Primary:       "world",
+			Shadow:        "shadow",
+		}, report.Body); diff != "" {
+			t.Errorf("Body diff unmatch, diff: %s", diff)
+		}
+	})
+}
+
+func TestShadowInvalidBackend(t *testing.T) {
+	if _, err := New(&config.ShadowConfig{Backend: "localhost"}); err == nil {
+		t.Errorf("Expected error for relative backend URL")
+	}
+}
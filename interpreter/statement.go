@@ -1,7 +1,6 @@
 package interpreter
 
 import (
-	"io"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -9,9 +8,9 @@ import (
 	"github.com/ysugimoto/falco/interpreter/assign"
 	"github.com/ysugimoto/falco/interpreter/context"
 	"github.com/ysugimoto/falco/interpreter/exception"
-	"github.com/ysugimoto/falco/interpreter/function"
 	fe "github.com/ysugimoto/falco/interpreter/function/errors"
 	"github.com/ysugimoto/falco/interpreter/limitations"
+	"github.com/ysugimoto/falco/interpreter/operator"
 	"github.com/ysugimoto/falco/interpreter/process"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
@@ -22,10 +21,13 @@ func (i *Interpreter) ProcessBlockStatement(statements []ast.Statement, ds Debug
 	var debugState DebugState = ds
 
 	for _, stmt := range statements {
+		i.statement = stmt
 		// Call debugger
 		if debugState != DebugStepOut {
 			debugState = i.Debugger.Run(stmt)
 		}
+		i.recordStatementStep(stmt)
+		i.explainStatement(stmt)
 
 		switch t := stmt.(type) {
 		// Common logic statements (nothing to change state)
@@ -90,6 +92,13 @@ func (i *Interpreter) ProcessBlockStatement(statements []ast.Statement, ds Debug
 				return state, DebugPass, nil
 			}
 
+		case *ast.SwitchStatement:
+			var state State
+			state, err = i.ProcessSwitchStatement(t, debugState)
+			if state != NONE {
+				return state, DebugPass, nil
+			}
+
 		case *ast.RestartStatement:
 			if !i.ctx.Scope.Is(context.RecvScope, context.HitScope, context.FetchScope, context.ErrorScope, context.DeliverScope) {
 				return NONE, DebugPass, exception.Runtime(
@@ -317,6 +326,7 @@ func (i *Interpreter) ProcessLogStatement(stmt *ast.LogStatement) error {
 	}
 
 	i.process.Logs = append(i.process.Logs, process.NewLog(stmt, i.ctx.Scope, line))
+	i.ctx.EmittedLogs = append(i.ctx.EmittedLogs, line)
 	i.Debugger.Message(line)
 	return nil
 }
@@ -331,7 +341,7 @@ func (i *Interpreter) ProcessSyntheticStatement(stmt *ast.SyntheticStatement) er
 	if err := assign.Assign(v, val); err != nil {
 		return exception.Runtime(&stmt.GetMeta().Token, err.Error())
 	}
-	i.ctx.Object.Body = io.NopCloser(strings.NewReader(v.Value))
+	i.ctx.Object.Body = newSharedBody([]byte(v.Value))
 	return nil
 }
 
@@ -344,7 +354,7 @@ func (i *Interpreter) ProcessSyntheticBase64Statement(stmt *ast.SyntheticBase64S
 	if err := assign.Assign(v, val); err != nil {
 		return exception.Runtime(&stmt.GetMeta().Token, err.Error())
 	}
-	i.ctx.Object.Body = io.NopCloser(strings.NewReader(v.Value))
+	i.ctx.Object.Body = newSharedBody([]byte(v.Value))
 	return nil
 }
 
@@ -358,7 +368,7 @@ func (i *Interpreter) ProcessFunctionCallStatement(stmt *ast.FunctionCallStateme
 	}
 
 	// Builtin function will not change any state
-	fn, err := function.Exists(i.ctx.Scope, stmt.Function.Value)
+	fn, err := i.findFunction(stmt.Function.Value)
 	if err != nil {
 		return NONE, exception.Runtime(&stmt.GetMeta().Token, err.Error())
 	}
@@ -423,6 +433,7 @@ func (i *Interpreter) ProcessIfStatement(stmt *ast.IfStatement, ds DebugState) (
 	switch t := cond.(type) {
 	case *value.Boolean:
 		if t.Value {
+			i.process.MatchCondition(stmt, i.ctx.Scope)
 			state, _, err := i.ProcessBlockStatement(stmt.Consequence.Statements, ds)
 			if err != nil {
 				return NONE, errors.WithStack(err)
@@ -431,6 +442,7 @@ func (i *Interpreter) ProcessIfStatement(stmt *ast.IfStatement, ds DebugState) (
 		}
 	case *value.String:
 		if !t.IsNotSet {
+			i.process.MatchCondition(stmt, i.ctx.Scope)
 			state, _, err := i.ProcessBlockStatement(stmt.Consequence.Statements, ds)
 			if err != nil {
 				return NONE, errors.WithStack(err)
@@ -460,6 +472,7 @@ func (i *Interpreter) ProcessIfStatement(stmt *ast.IfStatement, ds DebugState) (
 		switch t := cond.(type) {
 		case *value.Boolean:
 			if t.Value {
+				i.process.MatchCondition(ei, i.ctx.Scope)
 				state, _, err := i.ProcessBlockStatement(ei.Consequence.Statements, ds)
 				if err != nil {
 					return NONE, errors.WithStack(err)
@@ -468,6 +481,7 @@ func (i *Interpreter) ProcessIfStatement(stmt *ast.IfStatement, ds DebugState) (
 			}
 		case *value.String:
 			if !t.IsNotSet {
+				i.process.MatchCondition(ei, i.ctx.Scope)
 				state, _, err := i.ProcessBlockStatement(ei.Consequence.Statements, ds)
 				if err != nil {
 					return NONE, errors.WithStack(err)
@@ -494,3 +508,66 @@ func (i *Interpreter) ProcessIfStatement(stmt *ast.IfStatement, ds DebugState) (
 	}
 	return NONE, nil
 }
+
+func (i *Interpreter) ProcessSwitchStatement(stmt *ast.SwitchStatement, ds DebugState) (State, error) {
+	// Control expression is evaluated only once and compared with case values in order
+	control, err := i.ProcessExpression(stmt.Control, false)
+	if err != nil {
+		return NONE, errors.WithStack(err)
+	}
+
+	matched := stmt.Default
+	for index, c := range stmt.Cases {
+		if c.IsDefault() {
+			continue
+		}
+		v, err := i.ProcessExpression(c.Value, false)
+		if err != nil {
+			return NONE, errors.WithStack(err)
+		}
+
+		var result value.Value
+		if c.Operator == "~" {
+			result, err = operator.Regex(i.ctx, control, v)
+		} else {
+			result, err = operator.Equal(control, v)
+		}
+		if err != nil {
+			return NONE, exception.Runtime(&c.Value.GetMeta().Token, err.Error())
+		}
+		if b, ok := result.(*value.Boolean); ok && b.Value {
+			matched = index
+			break
+		}
+	}
+	if matched < 0 {
+		return NONE, nil
+	}
+
+	// Statements of the following case are executed while the case ends with fallthrough statement
+	for _, c := range stmt.Cases[matched:] {
+		statements, broken := caseStatements(c)
+		state, _, err := i.ProcessBlockStatement(statements, ds)
+		if err != nil {
+			return NONE, errors.WithStack(err)
+		}
+		if state != NONE {
+			return state, nil
+		}
+		if broken || !c.IsFallthrough() {
+			break
+		}
+	}
+	return NONE, nil
+}
+
+// Statements of the case which are executed, break statement stops the switch statement
+// and following statements are never executed
+func caseStatements(c *ast.CaseStatement) ([]ast.Statement, bool) {
+	for index, stmt := range c.Statements {
+		if _, ok := stmt.(*ast.BreakStatement); ok {
+			return c.Statements[:index], true
+		}
+	}
+	return c.Statements, false
+}
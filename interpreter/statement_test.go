@@ -373,3 +373,128 @@ func TestIfStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestSwitchStatement(t *testing.T) {
+	tests := []struct {
+		name       string
+		vcl        string
+		assertions map[string]value.Value
+		isError    bool
+	}{
+		{
+			name: "Exact matching case",
+			vcl: `
+			sub vcl_recv {
+				set req.http.Foo = "bar";
+				switch (req.http.Foo) {
+				case "foo":
+					set req.http.Result = "foo";
+					break;
+				case "bar":
+					set req.http.Result = "bar";
+					break;
+				default:
+					set req.http.Result = "default";
+					break;
+				}
+			}`,
+			assertions: map[string]value.Value{
+				"req.http.Result": &value.String{Value: "bar"},
+			},
+		},
+		{
+			name: "Regex matching case sets captured values",
+			vcl: `
+			sub vcl_recv {
+				set req.http.Foo = "/api/v1";
+				switch (req.http.Foo) {
+				case ~ "^/api/(v\d+)":
+					set req.http.Result = re.group.1;
+					break;
+				}
+			}`,
+			assertions: map[string]value.Value{
+				"req.http.Result": &value.String{Value: "v1"},
+			},
+		},
+		{
+			name: "Fallthrough executes the following case",
+			vcl: `
+			sub vcl_recv {
+				declare local var.N INTEGER;
+				set var.N = 1;
+				switch (var.N) {
+				case 1:
+					set req.http.First = "1";
+					fallthrough;
+				case 2:
+					set req.http.Second = "1";
+					break;
+				default:
+					set req.http.Default = "1";
+					break;
+				}
+			}`,
+			assertions: map[string]value.Value{
+				"req.http.First":   &value.String{Value: "1"},
+				"req.http.Second":  &value.String{Value: "1"},
+				"req.http.Default": &value.String{IsNotSet: true},
+			},
+		},
+		{
+			name: "Default case is placed before other cases",
+			vcl: `
+			sub vcl_recv {
+				switch (req.http.Foo) {
+				default:
+					set req.http.Result = "default";
+					break;
+				case "foo":
+					set req.http.Result = "foo";
+					break;
+				}
+			}`,
+			assertions: map[string]value.Value{
+				"req.http.Result": &value.String{Value: "default"},
+			},
+		},
+		{
+			name: "Break stops the switch before following statements and fallthrough",
+			vcl: `
+			sub vcl_recv {
+				switch (req.http.Foo) {
+				default:
+					set req.http.First = "1";
+					break;
+					set req.http.Second = "1";
+					fallthrough;
+				case "foo":
+					set req.http.Third = "1";
+					break;
+				}
+			}`,
+			assertions: map[string]value.Value{
+				"req.http.First":  &value.String{Value: "1"},
+				"req.http.Second": &value.String{IsNotSet: true},
+				"req.http.Third":  &value.String{IsNotSet: true},
+			},
+		},
+		{
+			name: "Case value type does not match",
+			vcl: `
+			sub vcl_recv {
+				switch (req.http.Foo) {
+				case 1:
+					break;
+				}
+			}`,
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInterpreter(t, tt.vcl, context.RecvScope, tt.assertions, tt.isError)
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package interpreter
 
 import (
-	"strings"
-
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/interpreter/context"
@@ -10,10 +8,16 @@ import (
 	"github.com/ysugimoto/falco/interpreter/process"
 	"github.com/ysugimoto/falco/interpreter/value"
 	"github.com/ysugimoto/falco/interpreter/variable"
+	"github.com/ysugimoto/falco/snippets"
 )
 
 func (i *Interpreter) ProcessTestSubroutine(scope context.Scope, sub *ast.SubroutineDeclaration) error {
+	defer i.wrapPanic()
 	i.SetScope(scope)
+	// Cache decision is explained per test case
+	if i.process.CacheExplanation != nil {
+		i.process.CacheExplanation = process.NewCacheExplanation()
+	}
 	if _, err := i.ProcessSubroutine(sub, DebugPass); err != nil {
 		return errors.WithStack(err)
 	}
@@ -22,8 +26,10 @@ func (i *Interpreter) ProcessTestSubroutine(scope context.Scope, sub *ast.Subrou
 
 func (i *Interpreter) ProcessSubroutine(sub *ast.SubroutineDeclaration, ds DebugState) (State, error) {
 	i.process.Flows = append(i.process.Flows, process.NewFlow(i.ctx, sub))
+	i.enterSubroutineStep(sub)
 	// reset all local values and regex capture values
 	defer func() {
+		i.leaveSubroutineStep()
 		i.ctx.RegexMatchedValues = make(map[string]*value.String)
 		i.localVars = variable.LocalVariables{}
 		i.ctx.SubroutineCalls[sub.Name.Value]++
@@ -46,6 +52,7 @@ func (i *Interpreter) ProcessSubroutine(sub *ast.SubroutineDeclaration, ds Debug
 
 func (i *Interpreter) ProcessFunctionSubroutine(sub *ast.SubroutineDeclaration, ds DebugState) (value.Value, State, error) {
 	i.process.Flows = append(i.process.Flows, process.NewFlow(i.ctx, sub))
+	i.enterSubroutineStep(sub)
 
 	// Store the current values and restore after subroutine has ended
 	regex := i.ctx.RegexMatchedValues
@@ -54,6 +61,7 @@ func (i *Interpreter) ProcessFunctionSubroutine(sub *ast.SubroutineDeclaration,
 	i.localVars = variable.LocalVariables{}
 
 	defer func() {
+		i.leaveSubroutineStep()
 		i.ctx.RegexMatchedValues = regex
 		i.localVars = local
 	}()
@@ -62,6 +70,7 @@ func (i *Interpreter) ProcessFunctionSubroutine(sub *ast.SubroutineDeclaration,
 	var debugState DebugState = ds
 
 	for _, stmt := range sub.Block.Statements {
+		i.statement = stmt
 		// Call debugger
 		if debugState != DebugStepOut {
 			debugState = i.Debugger.Run(stmt)
@@ -118,6 +127,12 @@ func (i *Interpreter) ProcessFunctionSubroutine(sub *ast.SubroutineDeclaration,
 			if state != NONE {
 				return value.Null, state, nil
 			}
+		case *ast.SwitchStatement:
+			var state State
+			state, err = i.ProcessSwitchStatement(t, debugState)
+			if state != NONE {
+				return value.Null, state, nil
+			}
 		case *ast.RestartStatement:
 			// restart statement force change state to RESTART
 			return value.Null, RESTART, nil
@@ -202,53 +217,22 @@ func (i *Interpreter) extractBoilerplateMacro(sub *ast.SubroutineDeclaration) er
 	if !ok {
 		return nil
 	}
-	snippets, ok := i.ctx.FastlySnippets.ScopedSnippets[macro]
-	if !ok || len(snippets) == 0 {
+	scoped, ok := i.ctx.FastlySnippets.ScopedSnippets[macro]
+	if !ok || len(scoped) == 0 {
 		return nil
 	}
 
-	macroName := strings.ToUpper("fastly " + macro)
-
-	var resolved []ast.Statement
-	// Find "FASTLY [macro]" comment and extract in infix comment of block statement
-	if hasFastlyBoilerplateMacro(sub.Block.InfixComment(), macroName) {
-		for _, s := range snippets {
+	// Inject snippets at the place of "FASTLY [macro]" comment as Fastly does
+	_, _, err := snippets.InjectMacro(sub.Block, snippets.MacroPhrase(macro), func() ([]ast.Statement, error) {
+		var resolved []ast.Statement
+		for _, s := range scoped {
 			statements, err := loadStatementVCL(s.Name, s.Data)
 			if err != nil {
-				return errors.WithStack(err)
+				return nil, errors.WithStack(err)
 			}
 			resolved = append(resolved, statements...)
 		}
-		// Prevent to block statements
-		sub.Block.Statements = append(resolved, sub.Block.Statements...)
-		return nil
-	}
-
-	// Find "FASTLY [macro]" comment and extract inside block statement
-	var found bool // guard flag, embedding macro should do only once
-	for _, stmt := range sub.Block.Statements {
-		if hasFastlyBoilerplateMacro(stmt.LeadingComment(), macroName) && !found {
-			for _, s := range snippets {
-				statements, err := loadStatementVCL(s.Name, s.Data)
-				if err != nil {
-					return errors.WithStack(err)
-				}
-				resolved = append(resolved, statements...)
-			}
-			found = true // guard for only once
-		}
-		resolved = append(resolved, stmt) // don't forget to append original statement
-	}
-	sub.Block.Statements = resolved
-	return nil
-}
-
-func hasFastlyBoilerplateMacro(commentText, macroName string) bool {
-	for _, c := range strings.Split(commentText, "\n") {
-		c = strings.TrimLeft(c, " */#")
-		if strings.HasPrefix(strings.ToUpper(c), macroName) {
-			return true
-		}
-	}
-	return false
+		return resolved, nil
+	})
+	return err
 }
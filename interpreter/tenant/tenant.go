@@ -0,0 +1,183 @@
+package tenant
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/interpreter/context"
+)
+
+// Response header which indicates the service that processes the request
+const ServiceHeader = "Falco-Service"
+
+// Fastly-FF header is appended on each hop between Fastly services, the entry format is "[service]![datacenter]![server]".
+// Use the same datacenter and server identity as server.datacenter and server.identity variables.
+const (
+	FastlyFFHeader  = "Fastly-FF"
+	fastlyFFSuffix  = "!FALCO!cache-localsimulator"
+	loopDetectedMsg = "Loop detected"
+)
+
+// Service is an independent VCL service which is simulated side by side with other services.
+// Each service must have its own handler in order to isolate cache and context.
+type Service struct {
+	Name    string
+	Hosts   []string
+	Handler http.Handler
+	// Edge processes the request and returns the client response when the service is chained as a backend
+	Edge func(*http.Request) (*http.Response, error)
+
+	// Interpreter could not process requests concurrently, so the lock serializes requests per service
+	mu sync.Mutex
+}
+
+func (s *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(ServiceHeader, s.Name)
+	if s.visited(r) {
+		http.Error(w, loopDetectedMsg, http.StatusServiceUnavailable)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Handler.ServeHTTP(w, r)
+}
+
+// Backend returns the function which sends the backend request of the service "from" to this service in-process.
+// The Fastly-FF entry of the "from" service is appended to the request like Fastly does,
+// and the request which has already passed through this service is responded with 503 Loop detected.
+func (s *Service) Backend(from string) context.ServiceBackend {
+	return func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		entry := from + fastlyFFSuffix
+		if ff := r.Header.Get(FastlyFFHeader); ff != "" {
+			entry = ff + ", " + entry
+		}
+		r.Header.Set(FastlyFFHeader, entry)
+
+		// Check the loop before acquiring the lock, the upstream service still holds its lock
+		if s.visited(r) {
+			return loopDetectedResponse(r), nil
+		}
+		if s.Edge == nil {
+			return nil, errors.WithStack(fmt.Errorf("Service %s could not be chained as a backend", s.Name))
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		resp, err := s.Edge(r)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		resp.Header.Set(ServiceHeader, s.Name)
+		return resp, nil
+	}
+}
+
+// Returns true when Fastly-FF header has the entry of this service
+func (s *Service) visited(r *http.Request) bool {
+	for _, ff := range r.Header.Values(FastlyFFHeader) {
+		for _, entry := range strings.Split(ff, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(entry), "!")
+			if name == s.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func loopDetectedResponse(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusServiceUnavailable, loopDetectedMsg),
+		StatusCode:    http.StatusServiceUnavailable,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		Body:          io.NopCloser(strings.NewReader(loopDetectedMsg)),
+		ContentLength: int64(len(loopDetectedMsg)),
+		Request:       r,
+	}
+}
+
+// Router dispatches requests to the service which matches the request hostname.
+// Hostname accepts the wildcard prefix like "*.example.com".
+// When only one service is served on the port, all requests are dispatched to the service.
+type Router struct {
+	services []*Service
+	exact    map[string]*Service
+	// Wildcard suffixes, longer one is matched first
+	wildcards []string
+	wildcard  map[string]*Service
+}
+
+func NewRouter(services ...*Service) (*Router, error) {
+	r := &Router{
+		services: services,
+		exact:    make(map[string]*Service),
+		wildcard: make(map[string]*Service),
+	}
+
+	for _, s := range services {
+		for _, host := range s.Hosts {
+			host = strings.ToLower(host)
+			if suffix, ok := strings.CutPrefix(host, "*"); ok {
+				if exists, ok := r.wildcard[suffix]; ok {
+					return nil, errors.WithStack(
+						fmt.Errorf("Host %s is duplicated in service %s and %s", host, exists.Name, s.Name),
+					)
+				}
+				r.wildcard[suffix] = s
+				r.wildcards = append(r.wildcards, suffix)
+				continue
+			}
+			if exists, ok := r.exact[host]; ok {
+				return nil, errors.WithStack(
+					fmt.Errorf("Host %s is duplicated in service %s and %s", host, exists.Name, s.Name),
+				)
+			}
+			r.exact[host] = s
+		}
+	}
+	sort.Slice(r.wildcards, func(i, j int) bool {
+		return len(r.wildcards[i]) > len(r.wildcards[j])
+	})
+	return r, nil
+}
+
+// Find returns the service which matches the hostname
+func (r *Router) Find(host string) *Service {
+	if len(r.services) == 1 {
+		return r.services[0]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+
+	if s, ok := r.exact[host]; ok {
+		return s
+	}
+	for _, suffix := range r.wildcards {
+		if strings.HasSuffix(host, suffix) {
+			return r.wildcard[suffix]
+		}
+	}
+	return nil
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s := r.Find(req.Host)
+	if s == nil {
+		http.Error(w, fmt.Sprintf("No service is found for host %s", req.Host), http.StatusNotFound)
+		return
+	}
+	s.ServeHTTP(w, req)
+}
@@ -0,0 +1,232 @@
+package tenant
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func named(name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name)) // nolint:errcheck
+	})
+}
+
+func TestRouter(t *testing.T) {
+	router, err := NewRouter(
+		&Service{Name: "web", Hosts: []string{"www.example.com", "*.example.com"}, Handler: named("web")},
+		&Service{Name: "api", Hosts: []string{"api.example.com", "*.api.example.com"}, Handler: named("api")},
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	tests := []struct {
+		host   string
+		expect string
+		status int
+	}{
+		{host: "www.example.com", expect: "web", status: http.StatusOK},
+		{host: "WWW.EXAMPLE.COM:3124", expect: "web", status: http.StatusOK},
+		{host: "api.example.com", expect: "api", status: http.StatusOK},
+		{host: "static.example.com", expect: "web", status: http.StatusOK},
+		{host: "v1.api.example.com", expect: "api", status: http.StatusOK},
+		{host: "example.net", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.Host = tt.host
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expects status %d but got %d", tt.host, tt.status, rec.Code)
+			continue
+		}
+		if tt.expect != "" {
+			if body := rec.Body.String(); body != tt.expect {
+				t.Errorf("%s: expects service %s but got %s", tt.host, tt.expect, body)
+			}
+			if v := rec.Header().Get(ServiceHeader); v != tt.expect {
+				t.Errorf("%s: expects %s header %s but got %s", tt.host, ServiceHeader, tt.expect, v)
+			}
+		}
+	}
+
+	t.Run("single service receives all hosts", func(t *testing.T) {
+		router, err := NewRouter(&Service{Name: "web", Hosts: []string{"www.example.com"}, Handler: named("web")})
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		if s := router.Find("example.net"); s == nil || s.Name != "web" {
+			t.Errorf("Single service should receive all requests, got %v", s)
+		}
+	})
+
+	t.Run("duplicated hosts", func(t *testing.T) {
+		_, err := NewRouter(
+			&Service{Name: "web", Hosts: []string{"www.example.com"}, Handler: named("web")},
+			&Service{Name: "api", Hosts: []string{"WWW.example.com"}, Handler: named("api")},
+		)
+		if err == nil {
+			t.Errorf("Expected error for duplicated hosts")
+		}
+	})
+}
+
+func TestServiceIsolation(t *testing.T) {
+	vcl := func(name string) string {
+		return `
+sub vcl_recv {
+  error 600;
+}
+sub vcl_deliver {
+  set resp.http.X-Service = "` + name + `";
+}
+`
+	}
+	newService := func(name, host string) *Service {
+		return &Service{
+			Name:    name,
+			Hosts:   []string{host},
+			Handler: interpreter.New(context.WithResolver(resolver.NewStaticResolver("main", vcl(name)))),
+		}
+	}
+
+	router, err := NewRouter(newService("web", "www.example.com"), newService("api", "api.example.com"))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	for _, host := range []string{"www.example.com", "api.example.com", "www.example.com"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		var summary struct {
+			ClientResponse struct {
+				Headers map[string]string `json:"headers"`
+			} `json:"client_response"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&summary)
+		resp.Body.Close()
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		// Each service processes the request with its own VCL
+		expect := resp.Header.Get(ServiceHeader)
+		if v := summary.ClientResponse.Headers["x-service"]; expect == "" || v != expect {
+			t.Errorf("%s: expects response of service %s but got %s", host, expect, v)
+		}
+	}
+}
+
+func TestServiceChaining(t *testing.T) {
+	edgeVCL := `
+backend F_origin {
+  .host = "origin.example.com";
+}
+sub vcl_recv {
+  set req.backend = F_origin;
+  return(pass);
+}
+`
+	shieldVCL := `
+sub vcl_recv {
+  error 600;
+}
+sub vcl_error {
+  set obj.status = 200;
+  set obj.http.X-FF = req.http.Fastly-FF;
+  synthetic "shield";
+  return(deliver);
+}
+`
+	services := make(map[string]*Service)
+	newService := func(name, host, vcl string, backends map[string]string) *Service {
+		chained := make(map[string]context.ServiceBackend)
+		for backend, to := range backends {
+			from, to := name, to
+			chained[backend] = func(r *http.Request) (*http.Response, error) {
+				return services[to].Backend(from)(r)
+			}
+		}
+		i := interpreter.New(
+			context.WithResolver(resolver.NewStaticResolver("main", vcl)),
+			context.WithServiceBackends(chained),
+		)
+		s := &Service{Name: name, Hosts: []string{host}, Handler: i, Edge: i.Serve}
+		services[name] = s
+		return s
+	}
+
+	router, err := NewRouter(
+		newService("edge", "www.example.com", edgeVCL, map[string]string{"F_origin": "shield"}),
+		newService("shield", "shield.example.com", shieldVCL, nil),
+		newService("loop", "loop.example.com", edgeVCL, map[string]string{"F_origin": "loop"}),
+	)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	tests := []struct {
+		host   string
+		status int
+		ff     string
+	}{
+		{host: "www.example.com", status: http.StatusOK, ff: "edge!FALCO!cache-localsimulator"},
+		{host: "loop.example.com", status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.Host = tt.host
+		router.ServeHTTP(rec, req)
+
+		var summary struct {
+			ClientResponse struct {
+				StatusCode int               `json:"status_code"`
+				Headers    map[string]string `json:"headers"`
+			} `json:"client_response"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&summary); err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.host, err)
+			continue
+		}
+		if summary.ClientResponse.StatusCode != tt.status {
+			t.Errorf("%s: expects status %d but got %d", tt.host, tt.status, summary.ClientResponse.StatusCode)
+		}
+		if v := summary.ClientResponse.Headers["x-ff"]; v != tt.ff {
+			t.Errorf("%s: expects Fastly-FF %q but got %q", tt.host, tt.ff, v)
+		}
+	}
+
+	t.Run("looped request is rejected", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		req.Host = "shield.example.com"
+		req.Header.Set(FastlyFFHeader, "edge!FALCO!cache-localsimulator, shield!FALCO!cache-localsimulator")
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expects status 503 but got %d", rec.Code)
+		}
+	})
+}
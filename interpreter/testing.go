@@ -5,13 +5,19 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/cache"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
-const testBackendResponseBody = "falco_test_response"
+const (
+	testBackendResponseBody = "falco_test_response"
+	// Lifetime of the cache object which is stored in testing when beresp.ttl is not set
+	testCacheObjectTTL = time.Hour
+)
 
 func (i *Interpreter) TestProcessInit(r *http.Request) error {
 	var err error
@@ -57,3 +63,47 @@ func (i *Interpreter) TestProcessInit(r *http.Request) error {
 	i.ctx.Object = i.cloneResponse(i.ctx.BackendResponse)
 	return nil
 }
+
+// TestCacheHit simulates the request hits the cache object of the hash in testing.
+// The object is stored from the backend response on the first call, and hit count of the object increases on each call,
+// so the logic which depends on obj.hits could be tested by repeating the call in the test case.
+// When hash is empty, the request hash or URL is used.
+func (i *Interpreter) TestCacheHit(hash string) *cache.CacheItem {
+	if hash == "" {
+		hash = i.ctx.RequestHash.Value
+	}
+	if hash == "" {
+		hash = i.ctx.Request.URL.String()
+	}
+
+	// Lifetime of the object may be changed in vcl_hit of the previous hit
+	if prev := i.ctx.CacheHitItem; prev != nil {
+		if i.ctx.ObjectTTL.Value > 0 {
+			prev.Update(i.ctx.ObjectTTL.Value)
+		}
+		prev.Grace = i.ctx.ObjectGrace.Value
+	}
+
+	if i.cache.Inspect(hash) == nil {
+		ttl := i.ctx.BackendResponseTTL.Value
+		if ttl <= 0 {
+			ttl = testCacheObjectTTL
+		}
+		now := time.Now()
+		i.cache.Set(hash, &cache.CacheItem{
+			Response:             i.cloneResponse(i.ctx.BackendResponse),
+			Expires:              now.Add(ttl),
+			EntryTime:            now,
+			Grace:                i.ctx.BackendResponseGrace.Value,
+			StaleWhileRevalidate: i.ctx.BackendResponseStaleWhileRevalidate.Value,
+		})
+	}
+
+	item := i.cache.Get(hash)
+	i.ctx.State = "HIT"
+	i.ctx.CacheHitItem = item
+	i.ctx.Object = i.cloneResponse(item.Response)
+	i.ctx.ObjectTTL = &value.RTime{Value: item.TTL()}
+	i.ctx.ObjectGrace = &value.RTime{Value: item.Grace}
+	return item
+}
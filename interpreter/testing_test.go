@@ -0,0 +1,53 @@
+package interpreter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestTestCacheHit(t *testing.T) {
+	vcl := `
+sub vcl_hit {
+  #FASTLY HIT
+  if (obj.hits >= 3) {
+    set obj.ttl = 24h;
+  }
+}`
+	ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+	if err := ip.TestProcessInit(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for hits := 1; hits <= 3; hits++ {
+		item := ip.TestCacheHit("")
+		if item.Hits != hits {
+			t.Errorf("Hit count expects %d but got %d", hits, item.Hits)
+		}
+		if ip.ctx.ObjectTTL.Value > testCacheObjectTTL {
+			t.Errorf("obj.ttl must not be promoted before third hit, got %s", ip.ctx.ObjectTTL.Value)
+		}
+		ip.SetScope(context.HitScope)
+		if _, err := ip.ProcessSubroutine(ip.ctx.Subroutines["vcl_hit"], DebugPass); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	// Lifetime which is promoted in the previous hit is kept in the object
+	item := ip.TestCacheHit("")
+	if item.Hits != 4 {
+		t.Errorf("Hit count expects 4 but got %d", item.Hits)
+	}
+	if ip.ctx.ObjectTTL.Value < 23*time.Hour {
+		t.Errorf("obj.ttl must be promoted to 24h, got %s", ip.ctx.ObjectTTL.Value)
+	}
+
+	// Hit count is tracked per object
+	if item := ip.TestCacheHit("/other"); item.Hits != 1 {
+		t.Errorf("Hit count of other object expects 1 but got %d", item.Hits)
+	}
+}
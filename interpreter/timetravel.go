@@ -0,0 +1,135 @@
+package interpreter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/process"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+// Record the step to the time-travel log if enabled
+func (i *Interpreter) recordStep(kind string, node ast.Node, statement string) {
+	if i.process == nil || i.process.TimeTravel == nil {
+		return
+	}
+
+	step := &process.Step{
+		Kind:      kind,
+		Scope:     i.ctx.Scope.String(),
+		Statement: statement,
+	}
+	if node != nil {
+		token := node.GetMeta().Token
+		step.File = token.File
+		step.Line = token.Line
+		step.Position = token.Position
+	}
+	i.process.TimeTravel.Record(step, i.timeTravelVariables())
+}
+
+func (i *Interpreter) recordStatementStep(stmt ast.Statement) {
+	if i.process == nil || i.process.TimeTravel == nil {
+		return
+	}
+	// Block statement is just a container, its statements are recorded individually
+	if _, ok := stmt.(*ast.BlockStatement); ok {
+		return
+	}
+	i.recordStep(process.StepStatement, stmt, stepStatement(stmt))
+}
+
+func (i *Interpreter) enterSubroutineStep(sub *ast.SubroutineDeclaration) {
+	if i.process == nil || i.process.TimeTravel == nil {
+		return
+	}
+	i.process.TimeTravel.Enter(sub.Name.Value)
+	i.recordStep(process.StepSubroutine, sub, "sub "+sub.Name.Value)
+}
+
+func (i *Interpreter) leaveSubroutineStep() {
+	if i.process == nil || i.process.TimeTravel == nil {
+		return
+	}
+	i.process.TimeTravel.Leave()
+}
+
+func (i *Interpreter) finishTimeTravel() {
+	if i.process == nil || i.process.TimeTravel == nil {
+		return
+	}
+	i.process.TimeTravel.Finish(i.timeTravelVariables())
+}
+
+// Returns the first line of the statement without comments
+func stepStatement(stmt ast.Statement) string {
+	meta := stmt.GetMeta()
+	s := strings.TrimPrefix(stmt.String(), meta.LeadingComment())
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "\n"), meta.TrailingComment())
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+// Capture variables which are tracked in the time-travel log.
+// HTTP headers, major request/response properties and local variables are captured as string.
+func (i *Interpreter) timeTravelVariables() map[string]string {
+	vars := make(map[string]string)
+
+	captureRequest := func(prefix string, r *http.Request) {
+		if r == nil {
+			return
+		}
+		vars[prefix+".method"] = r.Method
+		if r.URL != nil {
+			vars[prefix+".url"] = r.URL.RequestURI()
+		}
+		captureHeaders(vars, prefix, r.Header)
+	}
+	captureResponse := func(prefix string, r *http.Response) {
+		if r == nil {
+			return
+		}
+		vars[prefix+".status"] = strconv.Itoa(r.StatusCode)
+		captureHeaders(vars, prefix, r.Header)
+	}
+	captureValue := func(name string, v value.Value) {
+		if v == nil {
+			return
+		}
+		vars[name] = v.String()
+	}
+
+	captureRequest("req", i.ctx.Request)
+	vars["req.restarts"] = strconv.Itoa(i.ctx.Restarts)
+	if i.ctx.Backend != nil {
+		vars["req.backend"] = i.ctx.Backend.String()
+	}
+	if i.ctx.RequestHash != nil && i.ctx.RequestHash.Value != "" {
+		vars["req.hash"] = i.ctx.RequestHash.Value
+	}
+	captureRequest("bereq", i.ctx.BackendRequest)
+	if i.ctx.BackendResponse != nil {
+		captureResponse("beresp", i.ctx.BackendResponse)
+		captureValue("beresp.ttl", i.ctx.BackendResponseTTL)
+		captureValue("beresp.grace", i.ctx.BackendResponseGrace)
+		captureValue("beresp.cacheable", i.ctx.BackendResponseCacheable)
+	}
+	if i.ctx.Object != nil {
+		captureResponse("obj", i.ctx.Object)
+		captureValue("obj.ttl", i.ctx.ObjectTTL)
+	}
+	captureResponse("resp", i.ctx.Response)
+
+	for name, v := range i.localVars {
+		captureValue(name, v)
+	}
+	return vars
+}
+
+func captureHeaders(vars map[string]string, prefix string, h http.Header) {
+	for key, values := range h {
+		vars[prefix+".http."+key] = strings.Join(values, ", ")
+	}
+}
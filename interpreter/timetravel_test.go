@@ -0,0 +1,120 @@
+package interpreter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/process"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func TestTimeTravel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Errorf("Test server URL parsing error: %s", err)
+		return
+	}
+
+	vcl := defaultBackend(parsed) + `
+sub vcl_recv {
+  declare local var.tier STRING;
+  set var.tier = "gold"; # assign tier
+  set req.http.X-Tier = var.tier;
+  return (pass);
+}
+
+sub vcl_deliver {
+  unset resp.http.Cache-Control;
+  set resp.http.X-Tier = req.http.X-Tier;
+}
+`
+
+	t.Run("disabled by default", func(t *testing.T) {
+		ip := New(context.WithResolver(resolver.NewStaticResolver("main", vcl)))
+		ip.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		if ip.process.TimeTravel != nil {
+			t.Errorf("Time-travel log should not be recorded")
+		}
+	})
+
+	t.Run("record steps", func(t *testing.T) {
+		ip := New(
+			context.WithResolver(resolver.NewStaticResolver("main", vcl)),
+			context.WithTimeTravel(),
+		)
+		ip.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		if ip.process.Error != nil {
+			t.Errorf("Did not expect error but got %s", ip.process.Error)
+			return
+		}
+
+		tt := ip.process.TimeTravel
+		if tt.Initial["req.url"] != "/" {
+			t.Errorf("Initial variables should contain request URL, got %v", tt.Initial)
+		}
+
+		find := func(statement string) *process.Step {
+			for _, s := range tt.Steps {
+				if s.Statement == statement {
+					return s
+				}
+			}
+			t.Errorf("Step %s is not recorded", statement)
+			return nil
+		}
+
+		step := find(`set var.tier = "gold";`)
+		if step == nil {
+			return
+		}
+		if step.Kind != process.StepStatement || step.Subroutine != "vcl_recv" || step.Scope != "RECV" || step.Line != 10 {
+			t.Errorf("Unexpected step: %+v", step)
+		}
+		if len(step.Changes) != 1 || step.Changes[0].Name != "var.tier" || *step.Changes[0].After != "gold" {
+			t.Errorf("Unexpected changes of step: %+v", step.Changes)
+		}
+		if v := tt.Restore(step.Index)["var.tier"]; v != "gold" {
+			t.Errorf("Restored var.tier expects gold but got %s", v)
+		}
+		// Local variables are released after the subroutine
+		if _, ok := tt.Restore(len(tt.Steps) - 1)["var.tier"]; ok {
+			t.Errorf("Local variable should be removed after subroutine ends")
+		}
+
+		if step = find("fetch example"); step == nil {
+			return
+		}
+		if step.Kind != process.StepFetch {
+			t.Errorf("Unexpected step kind: %s", step.Kind)
+		}
+
+		if step = find("unset resp.http.Cache-Control;"); step == nil {
+			return
+		}
+		if len(step.Changes) != 1 || step.Changes[0].After != nil || *step.Changes[0].Before != "max-age=60" {
+			t.Errorf("Unexpected changes of unset step: %+v", step.Changes)
+		}
+		vars := tt.Restore(step.Index - 1)
+		if vars["resp.http.Cache-Control"] != "max-age=60" {
+			t.Errorf("Scrubbing backward should restore removed header, got %v", vars)
+		}
+
+		// The last step is finished on the end of request
+		if step = find("set resp.http.X-Tier = req.http.X-Tier;"); step == nil {
+			return
+		}
+		if v := tt.Restore(step.Index)["resp.http.X-Tier"]; v != "gold" {
+			t.Errorf("Restored resp.http.X-Tier expects gold but got %s", v)
+		}
+	})
+}
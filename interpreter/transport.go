@@ -1,13 +1,11 @@
 package interpreter
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io"
+	"net"
 	"time"
 
-	"crypto/tls"
 	"net/http"
 
 	"github.com/gobwas/glob"
@@ -15,6 +13,7 @@ import (
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/config"
 	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/dns"
 	"github.com/ysugimoto/falco/interpreter/exception"
 	"github.com/ysugimoto/falco/interpreter/limitations"
 	"github.com/ysugimoto/falco/interpreter/value"
@@ -118,47 +117,89 @@ func (i *Interpreter) createBackendRequest(ctx *icontext.Context, backend *value
 	if err != nil {
 		return nil, exception.Runtime(nil, "Failed to create backend request: %s", err)
 	}
-	req.Header = i.ctx.Request.Header.Clone()
+	// Header is copied when either request modifies it
+	req.Header = i.ctx.ShareHeader(i.ctx.Request.Header)
 
 	if alwaysHost {
-		req.Header.Set("Host", host)
+		i.ctx.WritableHeader(&req.Header).Set("Host", host)
+	}
+
+	// bereq timeout variables are initialized by backend properties, and could be overridden in vcl_miss or vcl_pass
+	for _, t := range []struct {
+		property string
+		v        *value.RTime
+		fallback time.Duration
+	}{
+		{property: "connect_timeout", v: ctx.ConnectTimeout, fallback: defaultConnectTimeout},
+		{property: "first_byte_timeout", v: ctx.FirstByteTimeout, fallback: defaultFirstByteTimeout},
+		{property: "between_bytes_timeout", v: ctx.BetweenBytesTimeout, fallback: defaultBetweenBytesTimeout},
+	} {
+		t.v.Value = t.fallback
+		if v, err := i.getBackendProperty(backend.Value.Properties, t.property); err != nil {
+			return nil, errors.WithStack(err)
+		} else if v != nil {
+			t.v.Value = value.Unwrap[*value.RTime](v).Value
+		}
 	}
 	return req, nil
 }
 
 func (i *Interpreter) sendBackendRequest(backend *value.Backend) (*http.Response, error) {
-	fbt, err := i.getBackendProperty(backend.Value.Properties, "first_byte_timeout")
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	firstByteTimeout := 15 * time.Second
-	if fbt != nil {
-		firstByteTimeout = value.Unwrap[*value.RTime](fbt).Value
-	}
-
-	ctx, timeout := context.WithTimeout(i.ctx.Request.Context(), firstByteTimeout)
-	defer timeout()
+	ctx, cancel := context.WithCancel(i.ctx.Request.Context())
+	defer cancel()
 
 	req := i.ctx.BackendRequest.Clone(ctx)
+	// Body is buffered once and replayed so that the body is not consumed by sending the request
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = cloneBody(&i.ctx.BackendRequest.Body)
+	}
 
 	// Check Fastly limitations
 	if err := limitations.CheckFastlyRequestLimit(req); err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	client := http.DefaultClient
-	if req.URL.Scheme == HTTPS_SCHEME {
-		client = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					ServerName: req.URL.Hostname(),
-				},
-			},
+	// Fastly fails immediately when the backend connections reach .max_connections
+	maxConnections := defaultMaxConnections
+	if v, err := i.getBackendProperty(backend.Value.Properties, "max_connections"); err != nil {
+		return nil, errors.WithStack(err)
+	} else if v != nil {
+		maxConnections = int(value.Unwrap[*value.Integer](v).Value)
+	}
+	pool := i.backends.get(backend.Value.Name.Value)
+	if !pool.acquire(maxConnections) {
+		return nil, &backendError{Response: maxConnectionsReached}
+	}
+	defer pool.release()
+
+	var resp *http.Response
+	var err error
+	if chained, ok := i.ctx.ServiceBackends[backend.Value.Name.Value]; ok {
+		// Backend is another simulated service, process the request in-process
+		i.Debugger.Message(fmt.Sprintf("Backend (%s) is served by the chained service", backend.Value.Name.Value))
+		resp, err = chained(req)
+	} else {
+		var addrs []net.IP
+		addrs, err = i.resolveBackendHost(ctx, backend, req.URL.Hostname())
+		if err != nil {
+			return nil, errors.WithStack(err)
 		}
+		i.ctx.BackendAddrs = addrs
+		// Connect to the first address, other addresses are exposed as beresp.backend.alternate_ips.
+		// Transport is reused between requests in order to keep connections alive
+		transport := pool.transport(transportKey{
+			scheme:           req.URL.Scheme,
+			addr:             addrs[0].String(),
+			serverName:       req.URL.Hostname(),
+			connectTimeout:   i.ctx.ConnectTimeout.Value,
+			firstByteTimeout: i.ctx.FirstByteTimeout.Value,
+		}, maxConnections)
+		resp, err = (&http.Client{Transport: transport}).Do(req)
 	}
-	resp, err := client.Do(req)
 	if err != nil {
+		if be := timeoutError(err); be != nil {
+			return nil, be
+		}
 		return nil, exception.Runtime(nil, "Failed to retrieve backend response: %s", err)
 	}
 
@@ -166,15 +207,52 @@ func (i *Interpreter) sendBackendRequest(backend *value.Backend) (*http.Response
 	i.Debugger.Message(fmt.Sprintf("Backend (%s) responds status code %d", backend.Value.Name.Value, resp.StatusCode))
 
 	// read all response body to suppress memory leak
-	var buf bytes.Buffer
-	if _, err = buf.ReadFrom(resp.Body); err != nil {
+	buf, err := readBetweenBytes(resp.Body, i.ctx.BetweenBytesTimeout.Value)
+	resp.Body.Close()
+	if err != nil {
+		if be, ok := err.(*backendError); ok {
+			return nil, be
+		}
 		return nil, errors.WithStack(err)
 	}
-	resp.Body.Close()
-	resp.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
+	resp.Body = newSharedBody(buf)
+
+	if i.isImageOptimizerEnabled() && resp.StatusCode == http.StatusOK {
+		return i.optimizeImage(req, resp)
+	}
 	return resp, nil
 }
 
+// Resolve backend host, the pinned addresses by configuration are used when exists.
+// Hosts of dynamic backends are re-resolved after the TTL expires.
+func (i *Interpreter) resolveBackendHost(ctx context.Context, backend *value.Backend, host string) ([]net.IP, error) {
+	if pinned, ok := i.ctx.DNSOverrides[host]; ok {
+		addrs, err := dns.ParseAddrs(pinned)
+		if err != nil {
+			return nil, exception.System("Invalid address is pinned for host %s: %s", host, err)
+		}
+		if len(addrs) > 0 {
+			return addrs, nil
+		}
+	}
+
+	var dynamic bool
+	if v, err := i.getBackendProperty(backend.Value.Properties, "dynamic"); err != nil {
+		return nil, errors.WithStack(err)
+	} else if v != nil {
+		dynamic = value.Unwrap[*value.Boolean](v).Value
+	}
+
+	addrs, err := i.dns.Resolve(ctx, host, dynamic)
+	if err != nil {
+		return nil, exception.Runtime(nil, "Failed to resolve backend host %s: %s", host, err)
+	}
+	if dynamic {
+		i.Debugger.Message(fmt.Sprintf("Dynamic backend (%s) host %s is resolved to %s", backend.Value.Name.Value, host, addrs[0]))
+	}
+	return addrs, nil
+}
+
 func (i *Interpreter) getBackendProperty(props []*ast.BackendProperty, key string) (value.Value, error) {
 	var prop ast.Expression
 	for _, v := range props {
@@ -196,19 +274,14 @@ func (i *Interpreter) getBackendProperty(props []*ast.BackendProperty, key strin
 }
 
 func (i *Interpreter) cloneResponse(resp *http.Response) *http.Response {
-	// rewind body reader
-	var buf bytes.Buffer
-	buf.ReadFrom(resp.Body) // nolint: errcheck
-	resp.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
-
 	return &http.Response{
 		StatusCode:       resp.StatusCode,
 		Status:           resp.Status,
 		Proto:            resp.Proto,
 		ProtoMajor:       resp.ProtoMajor,
 		ProtoMinor:       resp.ProtoMinor,
-		Header:           resp.Header.Clone(),
-		Body:             io.NopCloser(bytes.NewReader(buf.Bytes())),
+		Header:           i.ctx.ShareHeader(resp.Header),
+		Body:             cloneBody(&resp.Body),
 		ContentLength:    resp.ContentLength,
 		TransferEncoding: resp.TransferEncoding,
 		Close:            resp.Close,
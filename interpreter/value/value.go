@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/acl"
 )
 
 type Type string
@@ -247,6 +248,9 @@ func (v *Backend) Copy() Value {
 type Acl struct {
 	Value   *ast.AclDeclaration
 	Literal bool
+
+	// Prefix trie of ACL entries, built lazily at the first match
+	trie *acl.Trie
 }
 
 func (v *Acl) String() string {
@@ -257,4 +261,22 @@ func (v *Acl) String() string {
 }
 func (v *Acl) Type() Type      { return AclType }
 func (v *Acl) IsLiteral() bool { return v.Literal }
-func (v *Acl) Copy() Value     { return &Acl{Value: v.Value, Literal: v.Literal} }
+func (v *Acl) Copy() Value {
+	return &Acl{Value: v.Value, Literal: v.Literal, trie: v.trie}
+}
+
+// Match returns true if the IP address matches the ACL entries.
+// The address is matched with the longest prefix entry, and false is returned when the entry is negated.
+func (v *Acl) Match(ip net.IP) (bool, error) {
+	if v.Value == nil {
+		return false, nil
+	}
+	if v.trie == nil {
+		trie, err := acl.New(v.Value.CIDRs)
+		if err != nil {
+			return false, err
+		}
+		v.trie = trie
+	}
+	return v.trie.Match(ip), nil
+}
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"crypto/md5"
-	"crypto/sha256"
 	"encoding/base64"
 	"net/http"
 	"net/url"
@@ -455,18 +454,12 @@ func (v *AllScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 			return &value.String{Value: ""}, nil
 		}
 	case REQ_DIGEST:
-		if v.ctx.RequestHash.Value == "" {
-			return &value.String{
-				Value: strings.Repeat("0", 64),
-			}, nil
-		}
-		// Simply we generate hash with sha256 because hashing algorithm is undocumented (or maybe secret)
-		// But it seems to be upper case hex string
+		// Digest is upper case hex string
 		return &value.String{
-			Value: strings.ToUpper(
-				fmt.Sprintf("%x", sha256.Sum256([]byte(v.ctx.RequestHash.Value))),
-			),
+			Value: strings.ToUpper(fmt.Sprintf("%x", RequestDigest(v.ctx))),
 		}, nil
+	case REQ_DIGEST_RATIO:
+		return &value.Float{Value: RequestDigestRatio(v.ctx)}, nil
 	case REQ_METHOD:
 		return &value.String{Value: req.Method}, nil
 	case REQ_POSTBODY:
@@ -601,6 +594,10 @@ func (v *AllScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 		return val, nil
 	}
 
+	if val, ok, err := getCustomVariable(v.ctx, s, name); ok {
+		return val, err
+	}
+
 	if injectedVariable != nil {
 		if val, err := injectedVariable.Get(v.ctx, s, name); err == nil {
 			return val, nil
@@ -621,28 +618,29 @@ func (v *AllScopeVariables) getFromRegex(name string) value.Value {
 	}
 
 	// HTTP request header matching
-	if match := requestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := requestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return getRequestHeaderValue(v.ctx.Request, match[1])
 	}
 
-	// Ratecounter variable matching
+	// Ratecounter variable matching, values are calculated for the entry which is incremented at last
 	if match := rateCounterRegex.FindStringSubmatch(name); match != nil {
-		var val float64
-		// all ratecounter variable value returns 1.0 fixed value
-		switch match[1] {
-		case "rate_10s",
-			"rate_1s",
-			"rate.60s",
-			"bucket.10s",
+		rc := v.ctx.RateLimit.Ratecounter(match[1])
+		entry, now := rc.LastEntry(), v.ctx.Now()
+		switch match[2] {
+		case "rate.1s":
+			return &value.Float{Value: rc.Rate(entry, time.Second, now)}
+		case "rate.10s":
+			return &value.Float{Value: rc.Rate(entry, 10*time.Second, now)}
+		case "rate.60s":
+			return &value.Float{Value: rc.Rate(entry, 60*time.Second, now)}
+		case "bucket.10s",
 			"bucket.20s",
 			"bucket.30s",
 			"bucket.40s",
 			"bucket.50s",
 			"bucket.60s":
-			val = 1.0
-		}
-		return &value.Float{
-			Value: val,
+			window, _ := time.ParseDuration(strings.TrimPrefix(match[2], "bucket.")) // nolint:errcheck
+			return &value.Integer{Value: rc.Bucket(entry, window, now)}
 		}
 	}
 	return nil
@@ -727,7 +725,7 @@ func (v *AllScopeVariables) Set(s context.Scope, name, operator string, val valu
 		return nil
 	}
 
-	if match := requestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := requestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
@@ -736,10 +734,14 @@ func (v *AllScopeVariables) Set(s context.Scope, name, operator string, val valu
 				"BACKEND literal %s cannot be assigned to %s in scope: %s", v.String(), name, s.String(),
 			))
 		}
-		setRequestHeaderValue(v.ctx.Request, match[1], val)
+		setRequestHeaderValue(v.ctx, v.ctx.Request, match[1], val)
 		return nil
 	}
 
+	if ok, err := setCustomVariable(v.ctx, s, name, operator, val); ok {
+		return err
+	}
+
 	if injectedVariable != nil {
 		if err := injectedVariable.Set(v.ctx, s, name, operator, val); err == nil {
 			return nil
@@ -753,7 +755,7 @@ func (v *AllScopeVariables) Set(s context.Scope, name, operator string, val valu
 
 func (v *AllScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	match := requestHttpHeaderRegex.FindStringSubmatch(name)
+	match := requestHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		return errors.WithStack(fmt.Errorf(
 			"Variable %s is not found or could not add. Normally add statement could use for HTTP header", name,
@@ -763,7 +765,7 @@ func (v *AllScopeVariables) Add(s context.Scope, name string, val value.Value) e
 		return errors.WithStack(err)
 	}
 
-	v.ctx.Request.Header.Add(match[1], val.String())
+	v.ctx.WritableHeader(&v.ctx.Request.Header).Add(match[1], val.String())
 	return nil
 }
 
@@ -772,7 +774,7 @@ func (v *AllScopeVariables) Unset(s context.Scope, name string) error {
 		v.ctx.FastlyError.Value = ""
 		return nil
 	}
-	match := requestHttpHeaderRegex.FindStringSubmatch(name)
+	match := requestHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		return errors.WithStack(fmt.Errorf(
 			"Variable %s is not found or could not unset", name,
@@ -782,7 +784,7 @@ func (v *AllScopeVariables) Unset(s context.Scope, name string) error {
 		return errors.WithStack(err)
 	}
 
-	unsetRequestHeaderValue(v.ctx.Request, match[1])
+	unsetRequestHeaderValue(v.ctx, v.ctx.Request, match[1])
 	return nil
 }
 
@@ -0,0 +1,103 @@
+package variable
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+// CustomVariable is the variable which is provided by embedders.
+// Get and Set delegate to Go callbacks so that dynamic values
+// (e.g. feature flag service, current deployment color) can be surfaced to VCL.
+type CustomVariable struct {
+	// Scope is a bitmask of the scopes which the variable is accessible, AnyScope if zero
+	Scope context.Scope
+	// Get returns the current value, required
+	Get func(ctx *context.Context) (value.Value, error)
+	// Set receives the value after the assignment operator is applied.
+	// The variable is treated as read-only when Set is nil.
+	Set func(ctx *context.Context, val value.Value) error
+}
+
+var (
+	customVariables   = map[string]*CustomVariable{}
+	customVariablesMu sync.RWMutex
+)
+
+// Register registers custom variable with the name.
+// Already registered variable will be overridden.
+func Register(name string, v *CustomVariable) error {
+	if v == nil || v.Get == nil {
+		return errors.WithStack(fmt.Errorf("Custom variable %s must have Get callback", name))
+	}
+	customVariablesMu.Lock()
+	defer customVariablesMu.Unlock()
+	customVariables[name] = v
+	return nil
+}
+
+// Unregister removes the registered custom variable
+func Unregister(name string) {
+	customVariablesMu.Lock()
+	defer customVariablesMu.Unlock()
+	delete(customVariables, name)
+}
+
+func lookupCustomVariable(s context.Scope, name string) (*CustomVariable, error) {
+	customVariablesMu.RLock()
+	cv, ok := customVariables[name]
+	customVariablesMu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+	if cv.Scope != 0 && !s.Is(cv.Scope) {
+		return nil, errors.WithStack(fmt.Errorf(
+			"Variable %s could not access in scope: %s", name, s.String(),
+		))
+	}
+	return cv, nil
+}
+
+func getCustomVariable(ctx *context.Context, s context.Scope, name string) (value.Value, bool, error) {
+	cv, err := lookupCustomVariable(s, name)
+	if err != nil {
+		return value.Null, true, err
+	} else if cv == nil {
+		return value.Null, false, nil
+	}
+	val, err := cv.Get(ctx)
+	if err != nil {
+		return value.Null, true, errors.WithStack(err)
+	}
+	return val, true, nil
+}
+
+func setCustomVariable(ctx *context.Context, s context.Scope, name, operator string, val value.Value) (bool, error) {
+	cv, err := lookupCustomVariable(s, name)
+	if err != nil {
+		return true, err
+	} else if cv == nil {
+		return false, nil
+	}
+	if cv.Set == nil {
+		return true, errors.WithStack(fmt.Errorf(
+			"Variable %s is read-only", name,
+		))
+	}
+	current, err := cv.Get(ctx)
+	if err != nil {
+		return true, errors.WithStack(err)
+	}
+	// Apply operator to the copied value in order to keep the value which is returned from Get
+	left := current.Copy()
+	if err := doAssign(left, operator, val); err != nil {
+		return true, errors.WithStack(err)
+	}
+	if err := cv.Set(ctx, left); err != nil {
+		return true, errors.WithStack(err)
+	}
+	return true, nil
+}
@@ -0,0 +1,77 @@
+package variable
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+func TestCustomVariable(t *testing.T) {
+	color := &value.String{Value: "blue"}
+	err := Register("custom.deployment.color", &CustomVariable{
+		Get: func(ctx *context.Context) (value.Value, error) {
+			return color, nil
+		},
+		Set: func(ctx *context.Context, val value.Value) error {
+			color = value.Unwrap[*value.String](val)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Errorf("Unexpected register error: %s", err)
+		return
+	}
+	defer Unregister("custom.deployment.color")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost", nil) // nolint:errcheck
+	vars := NewAllScopeVariables(&context.Context{Request: req})
+
+	v, err := vars.Get(context.RecvScope, "custom.deployment.color")
+	if err != nil {
+		t.Errorf("Unexpected get error: %s", err)
+		return
+	}
+	if v.String() != "blue" {
+		t.Errorf("Value unmatch: expect blue, got %s", v.String())
+		return
+	}
+
+	if err := vars.Set(context.RecvScope, "custom.deployment.color", "=", &value.String{Value: "green"}); err != nil {
+		t.Errorf("Unexpected set error: %s", err)
+		return
+	}
+	if color.Value != "green" {
+		t.Errorf("Value unmatch: expect green, got %s", color.Value)
+		return
+	}
+}
+
+func TestCustomVariableScopeAndReadOnly(t *testing.T) {
+	err := Register("custom.feature.enabled", &CustomVariable{
+		Scope: context.RecvScope,
+		Get: func(ctx *context.Context) (value.Value, error) {
+			return &value.Boolean{Value: true}, nil
+		},
+	})
+	if err != nil {
+		t.Errorf("Unexpected register error: %s", err)
+		return
+	}
+	defer Unregister("custom.feature.enabled")
+
+	vars := NewAllScopeVariables(&context.Context{})
+	if _, err := vars.Get(context.DeliverScope, "custom.feature.enabled"); err == nil {
+		t.Errorf("Expected error when accessing out of scope")
+	}
+	if err := vars.Set(context.RecvScope, "custom.feature.enabled", "=", &value.Boolean{}); err == nil {
+		t.Errorf("Expected error when setting read-only variable")
+	}
+}
+
+func TestRegisterCustomVariableWithoutGetter(t *testing.T) {
+	if err := Register("custom.invalid", &CustomVariable{}); err == nil {
+		t.Errorf("Expected error when Get callback is nil")
+	}
+}
@@ -78,17 +78,16 @@ func (v *DeliverScopeVariables) Get(s context.Scope, name string) (value.Value,
 	case FASTLY_INFO_IS_CLUSTER_EDGE:
 		return &value.Boolean{Value: false}, nil
 
-	// TODO: should be able to get from context after object checked
 	case OBJ_AGE:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Age()}, nil
 		}
 		return &value.RTime{Value: 0}, nil // 0s
 	case OBJ_CACHEABLE:
 		return v.ctx.BackendResponseCacheable, nil
 	case OBJ_ENTERED:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Entered()}, nil
 		}
 		return &value.RTime{Value: 0}, nil
 	case OBJ_GRACE:
@@ -215,10 +214,6 @@ func (v *DeliverScopeVariables) Get(s context.Scope, name string) (value.Value,
 		return &value.String{
 			Value: fmt.Sprint(v.ctx.RequestEndTime.UnixMicro()),
 		}, nil
-
-	// Digest ratio will return fixed value
-	case REQ_DIGEST_RATIO:
-		return &value.Float{Value: 0.4}, nil
 	}
 
 	// Look up shared variables
@@ -262,7 +257,7 @@ func (v *DeliverScopeVariables) Get(s context.Scope, name string) (value.Value,
 
 func (v *DeliverScopeVariables) getFromRegex(name string) value.Value {
 	// HTTP response header matching
-	match := responseHttpHeaderRegex.FindStringSubmatch(name)
+	match := responseHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		return v.base.getFromRegex(name)
 	}
@@ -319,12 +314,12 @@ func (v *DeliverScopeVariables) Set(s context.Scope, name, operator string, val
 		return nil
 	}
 
-	if match := responseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := responseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
 
-		setResponseHeaderValue(v.ctx.Response, match[1], val)
+		setResponseHeaderValue(v.ctx, v.ctx.Response, match[1], val)
 		return nil
 	}
 
@@ -334,7 +329,7 @@ func (v *DeliverScopeVariables) Set(s context.Scope, name, operator string, val
 
 func (v *DeliverScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	match := responseHttpHeaderRegex.FindStringSubmatch(name)
+	match := responseHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to add in DELIVER, pass to base
 		return v.base.Add(s, name, val)
@@ -343,12 +338,12 @@ func (v *DeliverScopeVariables) Add(s context.Scope, name string, val value.Valu
 	if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 		return errors.WithStack(err)
 	}
-	v.ctx.Response.Header.Add(match[1], val.String())
+	v.ctx.WritableHeader(&v.ctx.Response.Header).Add(match[1], val.String())
 	return nil
 }
 
 func (v *DeliverScopeVariables) Unset(s context.Scope, name string) error {
-	match := responseHttpHeaderRegex.FindStringSubmatch(name)
+	match := responseHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to unset in DELIVER, pass to base
 		return v.base.Unset(s, name)
@@ -357,7 +352,7 @@ func (v *DeliverScopeVariables) Unset(s context.Scope, name string) error {
 		return errors.WithStack(err)
 	}
 
-	unsetResponseHeaderValue(v.ctx.Response, match[1])
+	unsetResponseHeaderValue(v.ctx, v.ctx.Response, match[1])
 	return nil
 }
 
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/interpreter/context"
@@ -44,17 +43,16 @@ func (v *ErrorScopeVariables) Get(s context.Scope, name string) (value.Value, er
 	case ESI_ALLOW_INSIDE_CDATA:
 		return v.ctx.EsiAllowInsideCData, nil
 
-	// TODO: should be able to get from context after object checked
 	case OBJ_AGE:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Age()}, nil
 		}
 		return &value.RTime{Value: 0}, nil // 0s
 	case OBJ_CACHEABLE:
 		return v.ctx.BackendResponseCacheable, nil
 	case OBJ_ENTERED:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Entered()}, nil
 		}
 		return &value.RTime{Value: 0}, nil
 	case OBJ_GRACE:
@@ -79,8 +77,10 @@ func (v *ErrorScopeVariables) Get(s context.Scope, name string) (value.Value, er
 		// alias for obj.grace
 		return v.ctx.ObjectGrace, nil
 	case OBJ_STALE_WHILE_REVALIDATE:
-		// Return fixed value because we don't support SWR yet
-		return &value.RTime{Value: 60 * time.Second}, nil
+		if v.ctx.CacheHitItem != nil {
+			return &value.RTime{Value: v.ctx.CacheHitItem.StaleWhileRevalidate}, nil
+		}
+		return &value.RTime{Value: 0}, nil
 	case OBJ_STATUS:
 		return &value.Integer{Value: int64(v.ctx.Object.StatusCode)}, nil
 	case OBJ_TTL:
@@ -119,10 +119,6 @@ func (v *ErrorScopeVariables) Get(s context.Scope, name string) (value.Value, er
 	case REQ_HASH:
 		return v.ctx.RequestHash, nil
 
-	// Digest ratio will return fixed value
-	case REQ_DIGEST_RATIO:
-		return &value.Float{Value: 0.4}, nil
-
 	// Limited waf related variables could get
 	case WAF_BLOCKED:
 		return v.ctx.WafBlocked, nil
@@ -157,7 +153,7 @@ func (v *ErrorScopeVariables) Get(s context.Scope, name string) (value.Value, er
 
 func (v *ErrorScopeVariables) getFromRegex(name string) value.Value {
 	// HTTP response header matching
-	match := objectHttpHeaderRegex.FindStringSubmatch(name)
+	match := objectHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		return v.base.getFromRegex(name)
 	}
@@ -243,11 +239,11 @@ func (v *ErrorScopeVariables) Set(s context.Scope, name, operator string, val va
 		return nil
 	}
 
-	if match := objectHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := objectHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		setResponseHeaderValue(v.ctx.Object, match[1], val)
+		setResponseHeaderValue(v.ctx, v.ctx.Object, match[1], val)
 		return nil
 	}
 
@@ -257,7 +253,7 @@ func (v *ErrorScopeVariables) Set(s context.Scope, name, operator string, val va
 
 func (v *ErrorScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	match := objectHttpHeaderRegex.FindStringSubmatch(name)
+	match := objectHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to add in ERROR, pass to base
 		return v.base.Add(s, name, val)
@@ -266,12 +262,12 @@ func (v *ErrorScopeVariables) Add(s context.Scope, name string, val value.Value)
 	if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 		return errors.WithStack(err)
 	}
-	v.ctx.Object.Header.Add(match[1], val.String())
+	v.ctx.WritableHeader(&v.ctx.Object.Header).Add(match[1], val.String())
 	return nil
 }
 
 func (v *ErrorScopeVariables) Unset(s context.Scope, name string) error {
-	match := objectHttpHeaderRegex.FindStringSubmatch(name)
+	match := objectHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to unset in ERROR, pass to base
 		return v.base.Unset(s, name)
@@ -280,6 +276,6 @@ func (v *ErrorScopeVariables) Unset(s context.Scope, name string) error {
 		return errors.WithStack(err)
 	}
 
-	unsetResponseHeaderValue(v.ctx.Object, match[1])
+	unsetResponseHeaderValue(v.ctx, v.ctx.Object, match[1])
 	return nil
 }
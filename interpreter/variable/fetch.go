@@ -143,10 +143,16 @@ func (v *FetchScopeVariables) Get(s context.Scope, name string) (value.Value, er
 		return &value.String{Value: bereq.URL.RawQuery}, nil
 
 	case BERESP_BACKEND_ALTERNATE_IPS:
-		return &value.String{Value: ""}, nil
-	// FIXME should be able to get from actual backend request
+		var ips []string
+		for i := 1; i < len(v.ctx.BackendAddrs); i++ {
+			ips = append(ips, v.ctx.BackendAddrs[i].String())
+		}
+		return &value.String{Value: strings.Join(ips, ",")}, nil
 	case BERESP_BACKEND_IP:
-		return &value.String{Value: ""}, nil
+		if len(v.ctx.BackendAddrs) == 0 {
+			return &value.IP{IsNotSet: true}, nil
+		}
+		return &value.IP{Value: v.ctx.BackendAddrs[0]}, nil
 	case BERESP_BACKEND_NAME:
 		return &value.String{Value: v.ctx.Backend.Value.Name.Value}, nil
 	case BERESP_BACKEND_PORT:
@@ -215,10 +221,6 @@ func (v *FetchScopeVariables) Get(s context.Scope, name string) (value.Value, er
 	// Always true because simulator could not simulate origin-shielding
 	case REQ_BACKEND_IS_ORIGIN:
 		return &value.Boolean{Value: true}, nil
-	// Digest ratio will return fixed value
-	case REQ_DIGEST_RATIO:
-		return &value.Float{Value: 0.4}, nil
-
 	case REQ_ESI:
 		return v.ctx.EnableSSI, nil
 	}
@@ -237,11 +239,11 @@ func (v *FetchScopeVariables) Get(s context.Scope, name string) (value.Value, er
 
 func (v *FetchScopeVariables) getFromRegex(name string) value.Value {
 	// HTTP request header matching
-	if match := backendRequestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return getRequestHeaderValue(v.ctx.BackendRequest, match[1])
 	}
 
-	if match := backendResponseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendResponseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return getResponseHeaderValue(v.ctx.BackendResponse, match[1])
 	}
 	return v.base.getFromRegex(name)
@@ -384,11 +386,11 @@ func (v *FetchScopeVariables) Set(s context.Scope, name, operator string, val va
 	} else if ok {
 		return nil
 	}
-	if match := backendResponseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendResponseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		setResponseHeaderValue(v.ctx.BackendResponse, match[1], val)
+		setResponseHeaderValue(v.ctx, v.ctx.BackendResponse, match[1], val)
 		return nil
 	}
 
@@ -398,16 +400,16 @@ func (v *FetchScopeVariables) Set(s context.Scope, name, operator string, val va
 
 func (v *FetchScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	if match := backendRequestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		v.ctx.BackendRequest.Header.Add(match[1], val.String())
-	} else if match := backendResponseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+		v.ctx.WritableHeader(&v.ctx.BackendRequest.Header).Add(match[1], val.String())
+	} else if match := backendResponseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		v.ctx.BackendResponse.Header.Add(match[1], val.String())
+		v.ctx.WritableHeader(&v.ctx.BackendResponse.Header).Add(match[1], val.String())
 	} else {
 		return v.base.Add(s, name, val)
 	}
@@ -417,20 +419,20 @@ func (v *FetchScopeVariables) Add(s context.Scope, name string, val value.Value)
 
 func (v *FetchScopeVariables) Unset(s context.Scope, name string) error {
 	// Backend Request
-	if match := backendRequestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		unsetRequestHeaderValue(v.ctx.BackendRequest, match[1])
+		unsetRequestHeaderValue(v.ctx, v.ctx.BackendRequest, match[1])
 		return nil
 	}
 
 	// Backend Response
-	if match := backendResponseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendResponseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		unsetResponseHeaderValue(v.ctx.BackendResponse, match[1])
+		unsetResponseHeaderValue(v.ctx, v.ctx.BackendResponse, match[1])
 		return nil
 	}
 
@@ -6,6 +6,7 @@ import (
 	"net/textproto"
 	"strings"
 
+	"github.com/ysugimoto/falco/interpreter/context"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -19,22 +20,15 @@ func getRequestHeaderValue(r *http.Request, name string) *value.String {
 		return &value.String{Value: v}
 	}
 	spl := strings.SplitN(name, ":", 2)
-	// Request header can modify cookie, then we need to retrieve value from Cookie pointer
+	// Request header can modify cookie, then we need to retrieve value from Cookie pointer.
+	// Cookie() parses only the cookie which has the name, not all cookies in the header
 	if strings.EqualFold(spl[0], "cookie") {
-		for _, c := range r.Cookies() {
-			if c.Name == spl[1] {
-				return &value.String{Value: c.Value}
-			}
+		if c, err := r.Cookie(spl[1]); err == nil {
+			return &value.String{Value: c.Value}
 		}
 	}
 
-	for _, hv := range r.Header.Values(spl[0]) {
-		kvs := strings.SplitN(hv, "=", 2)
-		if kvs[0] == spl[1] {
-			return &value.String{Value: kvs[1]}
-		}
-	}
-	return &value.String{IsNotSet: true}
+	return getSubfieldValue(r.Header.Values(spl[0]), spl[1])
 }
 
 func getResponseHeaderValue(r *http.Response, name string) *value.String {
@@ -48,16 +42,12 @@ func getResponseHeaderValue(r *http.Response, name string) *value.String {
 	}
 
 	spl := strings.SplitN(name, ":", 2)
-	for _, hv := range r.Header.Values(spl[0]) {
-		kvs := strings.SplitN(hv, "=", 2)
-		if kvs[0] == spl[1] {
-			return &value.String{Value: kvs[1]}
-		}
-	}
-	return &value.String{IsNotSet: true}
+	return getSubfieldValue(r.Header.Values(spl[0]), spl[1])
 }
 
-func setRequestHeaderValue(r *http.Request, name string, val value.Value) {
+// Header setters modify the header via WritableHeader because the header map could be shared with other HTTP objects
+func setRequestHeaderValue(ctx *context.Context, r *http.Request, name string, val value.Value) {
+	ctx.WritableHeader(&r.Header)
 	if !strings.Contains(name, ":") {
 		r.Header.Set(name, val.String())
 		return
@@ -74,10 +64,11 @@ func setRequestHeaderValue(r *http.Request, name string, val value.Value) {
 		r.AddCookie(c)
 		return
 	}
-	r.Header.Add(spl[0], fmt.Sprintf("%s=%s", spl[1], val.String()))
+	setHeaderSubfield(r.Header, spl[0], spl[1], val.String())
 }
 
-func setResponseHeaderValue(r *http.Response, name string, val value.Value) {
+func setResponseHeaderValue(ctx *context.Context, r *http.Response, name string, val value.Value) {
+	ctx.WritableHeader(&r.Header)
 	if !strings.Contains(name, ":") {
 		r.Header.Set(name, val.String())
 		return
@@ -85,10 +76,11 @@ func setResponseHeaderValue(r *http.Response, name string, val value.Value) {
 
 	// If name contains ":" like req.http.VARS:xxx, add with key-value format
 	spl := strings.SplitN(name, ":", 2)
-	r.Header.Add(spl[0], fmt.Sprintf("%s=%s", spl[1], val.String()))
+	setHeaderSubfield(r.Header, spl[0], spl[1], val.String())
 }
 
-func unsetRequestHeaderValue(r *http.Request, name string) {
+func unsetRequestHeaderValue(ctx *context.Context, r *http.Request, name string) {
+	ctx.WritableHeader(&r.Header)
 	if !strings.Contains(name, ":") {
 		r.Header.Del(name)
 		return
@@ -102,20 +94,7 @@ func unsetRequestHeaderValue(r *http.Request, name string) {
 		return
 	}
 
-	var filtered []string
-	for _, hv := range r.Header.Values(spl[0]) {
-		kvs := strings.SplitN(hv, "=", 2)
-		if kvs[0] == spl[1] {
-			continue
-		}
-		filtered = append(filtered, hv)
-	}
-
-	if len(filtered) > 0 {
-		r.Header[spl[0]] = filtered
-	} else {
-		r.Header.Del(name)
-	}
+	unsetHeaderSubfield(r.Header, spl[0], spl[1])
 }
 
 // removeCookieByName removes a part of Cookie headers that name is matched.
@@ -158,7 +137,8 @@ func removeCookieByName(r *http.Request, cookieName string) {
 	}
 }
 
-func unsetResponseHeaderValue(r *http.Response, name string) {
+func unsetResponseHeaderValue(ctx *context.Context, r *http.Response, name string) {
+	ctx.WritableHeader(&r.Header)
 	if !strings.Contains(name, ":") {
 		r.Header.Del(name)
 		return
@@ -166,18 +146,108 @@ func unsetResponseHeaderValue(r *http.Response, name string) {
 
 	// Header name contains ":" character, then filter value by key
 	spl := strings.SplitN(name, ":", 2)
-	var filtered []string
-	for _, hv := range r.Header.Values(spl[0]) {
-		kvs := strings.SplitN(hv, "=", 2)
-		if kvs[0] == spl[1] {
+	unsetHeaderSubfield(r.Header, spl[0], spl[1])
+}
+
+// Header value is tokenized as comma separated list of key-value pairs on subfield access
+// like beresp.http.Cache-Control:max-age. The value could be quoted string,
+// and the key without value like "private" is treated as the empty string.
+// Note that Cookie request header is separated by semicolon and handled by net/http cookie parser.
+type subfield struct {
+	key   string
+	value string
+	raw   string
+}
+
+func parseSubfields(v string) []subfield {
+	var fields []subfield
+	var quoted bool
+	start := 0
+	for i := 0; i <= len(v); i++ {
+		if i < len(v) {
+			if v[i] == '"' {
+				quoted = !quoted
+			}
+			if v[i] != ',' || quoted {
+				continue
+			}
+		}
+		raw := strings.TrimSpace(v[start:i])
+		start = i + 1
+		if raw == "" {
 			continue
 		}
-		filtered = append(filtered, hv)
+		key, val, _ := strings.Cut(raw, "=")
+		val = strings.TrimSpace(val)
+		if len(val) > 1 && val[0] == '"' && val[len(val)-1] == '"' {
+			val = val[1 : len(val)-1]
+		}
+		fields = append(fields, subfield{key: strings.TrimSpace(key), value: val, raw: raw})
+	}
+	return fields
+}
+
+func getSubfieldValue(values []string, key string) *value.String {
+	for _, hv := range values {
+		for _, f := range parseSubfields(hv) {
+			if f.key == key {
+				return &value.String{Value: f.value}
+			}
+		}
+	}
+	return &value.String{IsNotSet: true}
+}
+
+// Replace the value of the key if it exists, otherwise append the key-value pair to the header
+func setHeaderSubfield(h http.Header, name, key, val string) {
+	pair := fmt.Sprintf("%s=%s", key, val)
+	values := h.Values(name)
+	if len(values) == 0 {
+		h.Set(name, pair)
+		return
+	}
+
+	var replaced bool
+	for i, hv := range values {
+		fields := parseSubfields(hv)
+		tokens := make([]string, len(fields))
+		for j, f := range fields {
+			tokens[j] = f.raw
+			if !replaced && f.key == key {
+				tokens[j] = pair
+				replaced = true
+			}
+		}
+		values[i] = strings.Join(tokens, ", ")
+	}
+	if !replaced {
+		last := len(values) - 1
+		if values[last] == "" {
+			values[last] = pair
+		} else {
+			values[last] += ", " + pair
+		}
+	}
+	h[textproto.CanonicalMIMEHeaderKey(name)] = values
+}
+
+func unsetHeaderSubfield(h http.Header, name, key string) {
+	var filtered []string
+	for _, hv := range h.Values(name) {
+		var tokens []string
+		for _, f := range parseSubfields(hv) {
+			if f.key != key {
+				tokens = append(tokens, f.raw)
+			}
+		}
+		if len(tokens) > 0 {
+			filtered = append(filtered, strings.Join(tokens, ", "))
+		}
 	}
 
 	if len(filtered) > 0 {
-		r.Header[spl[0]] = filtered
+		h[textproto.CanonicalMIMEHeaderKey(name)] = filtered
 	} else {
-		r.Header.Del(name)
+		h.Del(name)
 	}
 }
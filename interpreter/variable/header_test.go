@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/interpreter/context"
 	"github.com/ysugimoto/falco/interpreter/value"
 )
 
@@ -72,7 +73,7 @@ func TestSetRequestHeaderValue(t *testing.T) {
 
 	for _, tt := range tests {
 		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
-		setRequestHeaderValue(req, tt.name, &value.String{Value: tt.value})
+		setRequestHeaderValue(context.New(), req, tt.name, &value.String{Value: tt.value})
 		ret := getRequestHeaderValue(req, tt.name)
 		if ret.Value != tt.value {
 			t.Errorf("Return value unmatch, expect=%s, got=%s", tt.value, ret.Value)
@@ -91,7 +92,7 @@ func TestSetResponseHeaderValue(t *testing.T) {
 
 	for _, tt := range tests {
 		resp := &http.Response{Header: http.Header{}}
-		setResponseHeaderValue(resp, tt.name, &value.String{Value: tt.value})
+		setResponseHeaderValue(context.New(), resp, tt.name, &value.String{Value: tt.value})
 		ret := getResponseHeaderValue(resp, tt.name)
 		if ret.Value != tt.value {
 			t.Errorf("Return value unmatch, expect=%s, got=%s", tt.value, ret.Value)
@@ -117,7 +118,7 @@ func TestUnsetRequestHeaderValue(t *testing.T) {
 	req.Header.Set("Cookie", "foo=bar")
 
 	for _, tt := range tests {
-		unsetRequestHeaderValue(req, tt.name)
+		unsetRequestHeaderValue(context.New(), req, tt.name)
 		ret := getRequestHeaderValue(req, tt.name)
 		if diff := cmp.Diff(ret, &value.String{IsNotSet: true}); diff != "" {
 			t.Errorf("Unset value still not empty, diff=%s", diff)
@@ -141,7 +142,7 @@ func TestUnsetResponseHeaderValue(t *testing.T) {
 	resp := &http.Response{Header: header}
 
 	for _, tt := range tests {
-		unsetResponseHeaderValue(resp, tt.name)
+		unsetResponseHeaderValue(context.New(), resp, tt.name)
 		ret := getResponseHeaderValue(resp, tt.name)
 		if diff := cmp.Diff(ret, &value.String{IsNotSet: true}); diff != "" {
 			t.Errorf("Unset value still not empty, diff=%s", diff)
@@ -149,6 +150,57 @@ func TestUnsetResponseHeaderValue(t *testing.T) {
 	}
 }
 
+func TestHeaderSubfield(t *testing.T) {
+	t.Run("get subfield from comma separated list", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			expect *value.String
+		}{
+			{name: "Cache-Control:max-age", expect: &value.String{Value: "10"}},
+			{name: "Cache-Control:private", expect: &value.String{Value: ""}},
+			{name: "Cache-Control:no-cache", expect: &value.String{Value: "Set-Cookie, Authorization"}},
+			{name: "Cache-Control:s-maxage", expect: &value.String{IsNotSet: true}},
+			{name: "Edge-Control:cache-maxage", expect: &value.String{Value: "1h"}},
+		}
+		header := http.Header{}
+		header.Set("Cache-Control", `max-age=10, private, no-cache="Set-Cookie, Authorization"`)
+		header.Set("Edge-Control", "!no-store, cache-maxage=1h")
+		resp := &http.Response{Header: header}
+
+		for _, tt := range tests {
+			ret := getResponseHeaderValue(resp, tt.name)
+			if diff := cmp.Diff(tt.expect, ret); diff != "" {
+				t.Errorf("Return value of %s unmatch, diff=%s", tt.name, diff)
+			}
+		}
+	})
+
+	t.Run("set subfield replaces existing key", func(t *testing.T) {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Cache-Control", "max-age=10, private")
+		setResponseHeaderValue(context.New(), resp, "Cache-Control:max-age", &value.String{Value: "60"})
+		setResponseHeaderValue(context.New(), resp, "Cache-Control:s-maxage", &value.String{Value: "3600"})
+		expect := "max-age=60, private, s-maxage=3600"
+		if v := resp.Header.Get("Cache-Control"); v != expect {
+			t.Errorf("Header value unmatch, expect=%s, got=%s", expect, v)
+		}
+	})
+
+	t.Run("unset subfield removes the key only", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+		req.Header.Set("X-Flags", "a=1, b, c=3")
+		unsetRequestHeaderValue(context.New(), req, "X-Flags:b")
+		if v := req.Header.Get("X-Flags"); v != "a=1, c=3" {
+			t.Errorf("Header value unmatch, got=%s", v)
+		}
+		unsetRequestHeaderValue(context.New(), req, "X-Flags:a")
+		unsetRequestHeaderValue(context.New(), req, "X-Flags:c")
+		if _, ok := req.Header["X-Flags"]; ok {
+			t.Errorf("Header should be removed when all keys are removed")
+		}
+	})
+}
+
 func TestRemoveCookieByName(t *testing.T) {
 	tests := []struct {
 		name   string
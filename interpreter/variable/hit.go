@@ -3,7 +3,6 @@ package variable
 import (
 	"io"
 	"strings"
-	"time"
 
 	"net/http"
 
@@ -28,17 +27,16 @@ func NewHitScopeVariables(ctx *context.Context) *HitScopeVariables {
 
 func (v *HitScopeVariables) Get(s context.Scope, name string) (value.Value, error) {
 	switch name {
-	// FIXME should be able to get from actual backend request
 	case OBJ_AGE:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Age()}, nil
 		}
 		return &value.RTime{Value: 0}, nil // 0s
 	case OBJ_CACHEABLE:
 		return v.ctx.BackendResponseCacheable, nil
 	case OBJ_ENTERED:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Entered()}, nil
 		}
 		return &value.RTime{Value: 0}, nil
 	case OBJ_GRACE:
@@ -63,15 +61,14 @@ func (v *HitScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 		// alias for obj.grace
 		return v.ctx.ObjectGrace, nil
 	case OBJ_STALE_WHILE_REVALIDATE:
-		// Return fixed value because we don't support SWR yet
-		return &value.RTime{Value: 60 * time.Second}, nil
+		if v.ctx.CacheHitItem != nil {
+			return &value.RTime{Value: v.ctx.CacheHitItem.StaleWhileRevalidate}, nil
+		}
+		return &value.RTime{Value: 0}, nil
 	case OBJ_STATUS:
 		return &value.Integer{Value: int64(v.ctx.Object.StatusCode)}, nil
 	case OBJ_TTL:
 		return v.ctx.ObjectTTL, nil
-	// Digest ratio will return fixed value
-	case REQ_DIGEST_RATIO:
-		return &value.Float{Value: 0.4}, nil
 	}
 
 	if val := v.getFromRegex(name); val != nil {
@@ -88,7 +85,7 @@ func (v *HitScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 
 func (v *HitScopeVariables) getFromRegex(name string) value.Value {
 	// HTTP request header matching
-	if match := objectHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := objectHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return getResponseHeaderValue(v.ctx.Object, match[1])
 	}
 	return v.base.getFromRegex(name)
@@ -122,11 +119,11 @@ func (v *HitScopeVariables) Set(s context.Scope, name, operator string, val valu
 		return nil
 	}
 
-	if match := objectHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := objectHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		setResponseHeaderValue(v.ctx.Object, match[1], val)
+		setResponseHeaderValue(v.ctx, v.ctx.Object, match[1], val)
 		return nil
 	}
 
@@ -136,7 +133,7 @@ func (v *HitScopeVariables) Set(s context.Scope, name, operator string, val valu
 
 func (v *HitScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	match := objectHttpHeaderRegex.FindStringSubmatch(name)
+	match := objectHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to add in PASS, pass to base
 		return v.base.Add(s, name, val)
@@ -145,12 +142,12 @@ func (v *HitScopeVariables) Add(s context.Scope, name string, val value.Value) e
 		return errors.WithStack(err)
 	}
 
-	v.ctx.Object.Header.Add(match[1], val.String())
+	v.ctx.WritableHeader(&v.ctx.Object.Header).Add(match[1], val.String())
 	return nil
 }
 
 func (v *HitScopeVariables) Unset(s context.Scope, name string) error {
-	match := objectHttpHeaderRegex.FindStringSubmatch(name)
+	match := objectHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to unset in PASS, pass to base
 		return v.base.Unset(s, name)
@@ -158,6 +155,6 @@ func (v *HitScopeVariables) Unset(s context.Scope, name string) error {
 	if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 		return errors.WithStack(err)
 	}
-	unsetResponseHeaderValue(v.ctx.Object, match[1])
+	unsetResponseHeaderValue(v.ctx, v.ctx.Object, match[1])
 	return nil
 }
@@ -80,14 +80,14 @@ func (v *LogScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 
 	case OBJ_AGE:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Age()}, nil
 		}
 		return &value.RTime{Value: 0}, nil // 0s
 	case OBJ_CACHEABLE:
 		return v.ctx.BackendResponseCacheable, nil
 	case OBJ_ENTERED:
 		if v.ctx.CacheHitItem != nil {
-			return &value.RTime{Value: time.Since(v.ctx.CacheHitItem.EntryTime)}, nil
+			return &value.RTime{Value: v.ctx.CacheHitItem.Entered()}, nil
 		}
 		return &value.RTime{Value: 0}, nil
 	case OBJ_GRACE:
@@ -108,8 +108,10 @@ func (v *LogScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 		// alias for obj.grace
 		return v.ctx.ObjectGrace, nil
 	case OBJ_STALE_WHILE_REVALIDATE:
-		// Return fixed value because we don't support SWR yet
-		return &value.RTime{Value: 60 * time.Second}, nil
+		if v.ctx.CacheHitItem != nil {
+			return &value.RTime{Value: v.ctx.CacheHitItem.StaleWhileRevalidate}, nil
+		}
+		return &value.RTime{Value: 0}, nil
 	case OBJ_TTL:
 		return v.ctx.ObjectTTL, nil
 
@@ -174,10 +176,6 @@ func (v *LogScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 		req.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
 		readBytes += n
 		return &value.Integer{Value: readBytes}, nil
-	// Digest ratio will return fixed value
-	case REQ_DIGEST_RATIO:
-		return &value.Float{Value: 0.4}, nil
-
 	// FIXME: We need to send actual request to the backend
 	case RESP_BODY_BYTES_WRITTEN:
 		return &value.Integer{Value: 0}, nil
@@ -324,12 +322,12 @@ func (v *LogScopeVariables) Get(s context.Scope, name string) (value.Value, erro
 
 func (v *LogScopeVariables) getFromRegex(name string) value.Value {
 	// HTTP response header matching
-	if match := responseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := responseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return &value.String{
 			Value: v.ctx.Response.Header.Get(match[1]),
 		}
 	}
-	if match := backendRequestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return &value.String{
 			Value: v.ctx.BackendRequest.Header.Get(match[1]),
 		}
@@ -396,11 +394,11 @@ func (v *LogScopeVariables) Set(s context.Scope, name, operator string, val valu
 		return nil
 	}
 
-	if match := responseHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := responseHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return errors.WithStack(err)
 		}
-		v.ctx.Response.Header.Set(match[1], val.String())
+		v.ctx.WritableHeader(&v.ctx.Response.Header).Set(match[1], val.String())
 		return nil
 	}
 
@@ -410,7 +408,7 @@ func (v *LogScopeVariables) Set(s context.Scope, name, operator string, val valu
 
 func (v *LogScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	match := responseHttpHeaderRegex.FindStringSubmatch(name)
+	match := responseHttpHeaderPrefix.FindStringSubmatch(name)
 	if match != nil {
 		// Nothing values to be enable to add in PASS, pass to base
 		return v.base.Add(s, name, val)
@@ -419,12 +417,12 @@ func (v *LogScopeVariables) Add(s context.Scope, name string, val value.Value) e
 		return errors.WithStack(err)
 	}
 
-	v.ctx.Response.Header.Add(match[1], val.String())
+	v.ctx.WritableHeader(&v.ctx.Response.Header).Add(match[1], val.String())
 	return nil
 }
 
 func (v *LogScopeVariables) Unset(s context.Scope, name string) error {
-	match := responseHttpHeaderRegex.FindStringSubmatch(name)
+	match := responseHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to unset in PASS, pass to base
 		return v.base.Unset(s, name)
@@ -432,6 +430,6 @@ func (v *LogScopeVariables) Unset(s context.Scope, name string) error {
 	if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 		return errors.WithStack(err)
 	}
-	v.ctx.Response.Header.Del(match[1])
+	v.ctx.WritableHeader(&v.ctx.Response.Header).Del(match[1])
 	return nil
 }
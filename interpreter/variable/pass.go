@@ -70,9 +70,6 @@ func (v *PassScopeVariables) Get(s context.Scope, name string) (value.Value, err
 	// We simulate request is always pass to the origin, not consider shielding
 	case REQ_BACKEND_IS_ORIGIN:
 		return &value.Boolean{Value: true}, nil
-	// Digest ratio will return fixed value
-	case REQ_DIGEST_RATIO:
-		return &value.Float{Value: 0.4}, nil
 	}
 
 	if val, err := GetWafVariables(v.ctx, name); err != nil {
@@ -95,7 +92,7 @@ func (v *PassScopeVariables) Get(s context.Scope, name string) (value.Value, err
 
 func (v *PassScopeVariables) getFromRegex(name string) value.Value {
 	// HTTP request header matching
-	if match := backendRequestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		return getRequestHeaderValue(v.ctx.BackendRequest, match[1])
 	}
 	return v.base.getFromRegex(name)
@@ -170,7 +167,7 @@ func (v *PassScopeVariables) Set(s context.Scope, name, operator string, val val
 
 func (v *PassScopeVariables) Add(s context.Scope, name string, val value.Value) error {
 	// Add statement could be use only for HTTP header
-	match := backendRequestHttpHeaderRegex.FindStringSubmatch(name)
+	match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to add in PASS, pass to base
 		return v.base.Add(s, name, val)
@@ -179,12 +176,12 @@ func (v *PassScopeVariables) Add(s context.Scope, name string, val value.Value)
 		return errors.WithStack(err)
 	}
 
-	v.ctx.BackendRequest.Header.Add(match[1], val.String())
+	v.ctx.WritableHeader(&v.ctx.BackendRequest.Header).Add(match[1], val.String())
 	return nil
 }
 
 func (v *PassScopeVariables) Unset(s context.Scope, name string) error {
-	match := backendRequestHttpHeaderRegex.FindStringSubmatch(name)
+	match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name)
 	if match == nil {
 		// Nothing values to be enable to unset in PASS, pass to base
 		return v.base.Unset(s, name)
@@ -192,6 +189,6 @@ func (v *PassScopeVariables) Unset(s context.Scope, name string) error {
 	if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 		return errors.WithStack(err)
 	}
-	unsetRequestHeaderValue(v.ctx.BackendRequest, match[1])
+	unsetRequestHeaderValue(v.ctx, v.ctx.BackendRequest, match[1])
 	return nil
 }
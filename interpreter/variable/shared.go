@@ -1,7 +1,9 @@
 package variable
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/binary"
 	"fmt"
 	"time"
 
@@ -154,6 +156,23 @@ func GetTLSVariable(s *tls.ConnectionState, name string) (value.Value, error) {
 	return nil, nil
 }
 
+// Calculate req.digest from req.hash.
+// Fastly does not document the hashing algorithm so we use sha256 which has the same digest length.
+// Digest is filled with zero before the hash is computed in vcl_hash.
+func RequestDigest(ctx *context.Context) [sha256.Size]byte {
+	if ctx.RequestHash == nil || ctx.RequestHash.Value == "" {
+		return [sha256.Size]byte{}
+	}
+	return sha256.Sum256([]byte(ctx.RequestHash.Value))
+}
+
+// Calculate req.digest.ratio from the first 4 bytes of req.digest, the ratio is between 0 and 1.
+// The same req.hash always returns the same ratio so sampling logic like req.digest.ratio < 0.1 is deterministic.
+func RequestDigestRatio(ctx *context.Context) float64 {
+	digest := RequestDigest(ctx)
+	return float64(binary.BigEndian.Uint32(digest[:4])) / float64(1<<32)
+}
+
 // Shared WAF relation variables.
 // Note that we could not sumulate Fastly legacy waf behavior, returns fake values.
 // If user write logic which corresponds to following value, process may be unexpected.
@@ -202,11 +221,11 @@ func GetWafVariables(ctx *context.Context, name string) (value.Value, error) {
 }
 
 func SetBackendRequestHeader(ctx *context.Context, name string, val value.Value) (bool, error) {
-	if match := backendRequestHttpHeaderRegex.FindStringSubmatch(name); match != nil {
+	if match := backendRequestHttpHeaderPrefix.FindStringSubmatch(name); match != nil {
 		if err := limitations.CheckProtectedHeader(match[1]); err != nil {
 			return true, errors.WithStack(err)
 		}
-		setRequestHeaderValue(ctx.BackendRequest, match[1], val)
+		setRequestHeaderValue(ctx, ctx.BackendRequest, match[1], val)
 		return true, nil
 	}
 	return false, nil
@@ -0,0 +1,46 @@
+package variable
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+func TestRequestDigest(t *testing.T) {
+	tests := []struct {
+		hash   string
+		digest string
+		ratio  float64
+	}{
+		{
+			hash:   "",
+			digest: "0000000000000000000000000000000000000000000000000000000000000000",
+			ratio:  0,
+		},
+		{
+			hash:   "/",
+			digest: "8A5EDAB282632443219E051E4ADE2D1D5BBC671C781051BF1437897CBDFEA0F1",
+			ratio:  0.5405098614282906,
+		},
+		{
+			hash:   "/foo",
+			digest: "6F64C6E6261F492AC220B0A4CD9A14C6373181B92A4A8040C1FCDE5DB31FFC94",
+			ratio:  0.43513148417696357,
+		},
+	}
+
+	for _, tt := range tests {
+		ctx := context.New()
+		ctx.RequestHash = &value.String{Value: tt.hash}
+		digest := fmt.Sprintf("%X", RequestDigest(ctx))
+		if digest != tt.digest {
+			t.Errorf("Digest of %q unmatch, expect=%s, actual=%s", tt.hash, tt.digest, digest)
+		}
+		ratio := RequestDigestRatio(ctx)
+		if ratio != tt.ratio {
+			t.Errorf("Digest ratio of %q unmatch, expect=%f, actual=%f", tt.hash, tt.ratio, ratio)
+		}
+	}
+}
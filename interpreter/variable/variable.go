@@ -2,6 +2,7 @@ package variable
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/ysugimoto/falco/interpreter/assign"
 	"github.com/ysugimoto/falco/interpreter/context"
@@ -16,15 +17,27 @@ type Variable interface {
 }
 
 var (
-	requestHttpHeaderRegex         = regexp.MustCompile(`^req\.http\.(.+)`)
-	backendRequestHttpHeaderRegex  = regexp.MustCompile(`^bereq\.http\.(.+)`)
-	backendResponseHttpHeaderRegex = regexp.MustCompile(`^beresp\.http\.(.+)`)
-	responseHttpHeaderRegex        = regexp.MustCompile(`^resp\.http\.(.+)`)
-	objectHttpHeaderRegex          = regexp.MustCompile(`^obj\.http\.(.+)`)
-	rateCounterRegex               = regexp.MustCompile(`ratecounter\.([^\.]+)\.(.+)`)
-	regexMatchedRegex              = regexp.MustCompile(`re\.group\.([0-9]+)`)
+	requestHttpHeaderPrefix         = headerVariablePrefix("req.http.")
+	backendRequestHttpHeaderPrefix  = headerVariablePrefix("bereq.http.")
+	backendResponseHttpHeaderPrefix = headerVariablePrefix("beresp.http.")
+	responseHttpHeaderPrefix        = headerVariablePrefix("resp.http.")
+	objectHttpHeaderPrefix          = headerVariablePrefix("obj.http.")
+	rateCounterRegex                = regexp.MustCompile(`ratecounter\.([^\.]+)\.(.+)`)
+	regexMatchedRegex               = regexp.MustCompile(`re\.group\.([0-9]+)`)
 )
 
+// Header variables are looked up on every access, so the name is matched by prefix
+// instead of regular expression which allocates matching state.
+// It has the same interface as regexp in order to be used in place of it.
+type headerVariablePrefix string
+
+func (p headerVariablePrefix) FindStringSubmatch(name string) []string {
+	if len(name) <= len(p) || !strings.HasPrefix(name, string(p)) {
+		return nil
+	}
+	return []string{name, name[len(p):]}
+}
+
 func doAssign(left value.Value, operator string, right value.Value) error {
 	switch operator {
 	case "+=":
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/ysugimoto/falco/token"
 )
@@ -19,6 +20,31 @@ type Lexer struct {
 	file   string
 	peeks  []token.Token
 	isEOF  bool
+	// Byte offset of the next character from the beginning of the source
+	offset   int
+	encoding Encoding
+}
+
+// UTF-8 byte order mark which some editors put at the beginning of the file
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// Encoding describes the source encoding which lexer tolerates.
+// BOM and CR of CRLF are skipped so that token positions and lines are the same as LF only source.
+type Encoding struct {
+	BOM bool
+	// Lines which end with CRLF
+	CRLFLines []int
+	// Bytes which are not valid UTF-8, they are read as U+FFFD replacement character
+	InvalidBytes []InvalidByte
+}
+
+// InvalidByte is a byte which is not valid UTF-8 with its position
+type InvalidByte struct {
+	Byte     byte
+	Line     int
+	Position int
+	// Byte offset from the beginning of the source, BOM is included
+	Offset int
 }
 
 func New(r io.Reader, opts ...OptionFunc) *Lexer {
@@ -29,6 +55,11 @@ func New(r io.Reader, opts ...OptionFunc) *Lexer {
 		buffer: new(bytes.Buffer),
 		file:   o.Filename,
 	}
+	if b, err := l.r.Peek(len(bom)); err == nil && bytes.Equal(b, bom) {
+		l.r.Discard(len(bom)) // nolint:errcheck
+		l.offset = len(bom)
+		l.encoding.BOM = true
+	}
 	l.readChar()
 	return l
 }
@@ -38,16 +69,36 @@ func NewFromString(input string, opts ...OptionFunc) *Lexer {
 }
 
 func (l *Lexer) readChar() {
-	r, _, err := l.r.ReadRune()
+	// First byte is kept to record it when the rune is not valid UTF-8
+	var first byte
+	if b, err := l.r.Peek(1); err == nil {
+		first = b[0]
+	}
+	r, size, err := l.r.ReadRune()
 	if err != nil {
 		l.char = 0x00
 		l.index += 1
 		return
 	}
+	// CR of CRLF is skipped as if the line ends with LF
+	if b, err := l.r.Peek(1); err == nil && r == 0x0D && b[0] == 0x0A {
+		l.offset += size
+		l.encoding.CRLFLines = append(l.encoding.CRLFLines, l.line)
+		r, size, _ = l.r.ReadRune()
+	}
 	if l.char == 0x0A { // LF
 		l.NewLine()
 	}
 	l.index += 1
+	if r == utf8.RuneError && size == 1 {
+		l.encoding.InvalidBytes = append(l.encoding.InvalidBytes, InvalidByte{
+			Byte:     first,
+			Line:     l.line,
+			Position: l.index,
+			Offset:   l.offset,
+		})
+	}
+	l.offset += size
 	l.char = r
 	l.buffer.WriteRune(r)
 }
@@ -57,6 +108,12 @@ func (l *Lexer) peekChar() rune {
 	if err != nil {
 		return 0x00
 	}
+	// CRLF is peeked as LF because CR is skipped on reading
+	if b[0] == 0x0D {
+		if b, err := l.r.Peek(2); err == nil && b[1] == 0x0A {
+			return 0x0A
+		}
+	}
 	return rune(b[0])
 }
 
@@ -74,6 +131,11 @@ func (l *Lexer) GetLine(n int) (string, bool) {
 	return l.stack[n-1], true
 }
 
+// Encoding returns the source encoding which is found while lexing
+func (l *Lexer) Encoding() Encoding {
+	return l.encoding
+}
+
 func (l *Lexer) LineCount() int {
 	return l.line - 1
 }
@@ -614,3 +614,52 @@ func TestPeekToken(t *testing.T) {
 		t.Errorf(`Assertion failed, diff= %s`, diff)
 	}
 }
+
+func TestLexerEncoding(t *testing.T) {
+	t.Run("BOM and CRLF are skipped in positions", func(t *testing.T) {
+		input := "\xEF\xBB\xBF# comment\r\nsub vcl_recv {\r\n}"
+		expects := []token.Token{
+			{Type: token.COMMENT, Literal: "# comment", Line: 1, Position: 1},
+			{Type: token.LF, Literal: "\n", Line: 1, Position: 10},
+			{Type: token.SUBROUTINE, Literal: "sub", Line: 2, Position: 1},
+			{Type: token.IDENT, Literal: "vcl_recv", Line: 2, Position: 5},
+			{Type: token.LEFT_BRACE, Literal: "{", Line: 2, Position: 14},
+			{Type: token.LF, Literal: "\n", Line: 2, Position: 15},
+			{Type: token.RIGHT_BRACE, Literal: "}", Line: 3, Position: 1},
+			{Type: token.EOF, Literal: "", Line: 3, Position: 2},
+		}
+
+		l := NewFromString(input)
+		for i, tt := range expects {
+			tok := l.NextToken()
+			if diff := cmp.Diff(tt, tok, cmpopts.IgnoreFields(token.Token{}, "Offset")); diff != "" {
+				t.Errorf(`Tests[%d] failed, diff= %s`, i, diff)
+			}
+		}
+		if line, _ := l.GetLine(1); line != "# comment" {
+			t.Errorf("CR should not be stored in the line, got=%q", line)
+		}
+
+		expect := Encoding{BOM: true, CRLFLines: []int{1, 2}}
+		if diff := cmp.Diff(expect, l.Encoding()); diff != "" {
+			t.Errorf("Encoding mismatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("invalid UTF-8 bytes are recorded with byte offset", func(t *testing.T) {
+		input := "set req.http.Foo = \"é\xFF\";\n# \xC3"
+		l := NewFromString(input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+
+		expect := Encoding{
+			InvalidBytes: []InvalidByte{
+				{Byte: 0xFF, Line: 1, Position: 22, Offset: 22},
+				{Byte: 0xC3, Line: 2, Position: 3, Offset: 28},
+			},
+		}
+		if diff := cmp.Diff(expect, l.Encoding()); diff != "" {
+			t.Errorf("Encoding mismatch, diff=%s", diff)
+		}
+	})
+}
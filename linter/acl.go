@@ -0,0 +1,126 @@
+package linter
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// ACL entries are limited to 1000 by default
+// https://docs.fastly.com/en/guides/resource-limits#acl-and-edge-dictionary-limits
+const maxAclEntries = 1000
+
+type aclEntry struct {
+	cidr   *ast.AclCidr
+	prefix netip.Prefix
+}
+
+func (e aclEntry) inverse() bool {
+	return e.cidr.Inverse != nil && e.cidr.Inverse.Value
+}
+
+// Format entry as it is written in VCL without comments
+func (e aclEntry) String() string {
+	s := `"` + e.cidr.IP.Value + `"`
+	if e.cidr.Mask != nil {
+		s += "/" + e.cidr.Mask.String()
+	}
+	if e.inverse() {
+		s = "!" + s
+	}
+	return s
+}
+
+// Parse ACL entry as network prefix, IP address without mask is treated as single host network
+func parseAclEntry(cidr *ast.AclCidr) (netip.Prefix, bool) {
+	addr, err := netip.ParseAddr(cidr.IP.Value)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	bits := addr.BitLen()
+	if cidr.Mask != nil {
+		bits = int(cidr.Mask.Value)
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	return prefix, true
+}
+
+func (l *Linter) lintAclEntries(decl *ast.AclDeclaration) {
+	if len(decl.CIDRs) > maxAclEntries {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    decl.Name.GetMeta().Token,
+			Message: fmt.Sprintf(
+				`ACL "%s" has %d entries which exceed the limit of %d`,
+				decl.Name.Value, len(decl.CIDRs), maxAclEntries,
+			),
+		}
+		l.Error(err.Match(ACL_ENTRY_LIMITATION))
+	}
+
+	var entries []aclEntry
+	for _, cidr := range decl.CIDRs {
+		// Invalid entries are reported by acl/syntax rule
+		if prefix, ok := parseAclEntry(cidr); ok {
+			entries = append(entries, aclEntry{cidr: cidr, prefix: prefix})
+		}
+	}
+
+	for i, entry := range entries {
+		// Find the nearest entry which covers this entry because ACL matches the longest prefix.
+		// Negated entry inside the other entry is an exception of the range, so it is not redundant.
+		var nearest *aclEntry
+		for j := range entries {
+			if i == j {
+				continue
+			}
+			other := entries[j]
+			if other.prefix.Bits() > entry.prefix.Bits() || !other.prefix.Contains(entry.prefix.Addr()) {
+				continue
+			}
+			if other.prefix.Bits() == entry.prefix.Bits() {
+				// Duplicated entry is reported at the latter one
+				if j < i {
+					l.lintDuplicatedAclEntry(entry, other)
+					nearest = nil
+					break
+				}
+				continue
+			}
+			if nearest == nil || other.prefix.Bits() > nearest.prefix.Bits() {
+				nearest = &entries[j]
+			}
+		}
+		if nearest == nil || nearest.inverse() != entry.inverse() {
+			continue
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    entry.cidr.GetMeta().Token,
+			Message: fmt.Sprintf(
+				`ACL entry %s is already covered by %s in ACL "%s"`,
+				entry, nearest, decl.Name.Value,
+			),
+		}
+		err.Relate(nearest.cidr.GetMeta().Token, "Covering entry is here")
+		l.Error(err.Match(ACL_OVERLAPPED_CIDR))
+	}
+}
+
+func (l *Linter) lintDuplicatedAclEntry(entry, first aclEntry) {
+	message := fmt.Sprintf(`ACL entry %s is duplicated`, entry)
+	if entry.inverse() != first.inverse() {
+		message = fmt.Sprintf(`ACL entry %s conflicts with negated entry of the same range`, entry)
+	}
+	err := &LintError{
+		Severity: WARNING,
+		Token:    entry.cidr.GetMeta().Token,
+		Message:  message,
+	}
+	relateFirstDeclaration(err, first.cidr.GetMeta().Token)
+	l.Error(err.Match(ACL_OVERLAPPED_CIDR))
+}
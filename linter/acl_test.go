@@ -0,0 +1,127 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintAclEntries(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []int
+	}{
+		{
+			name: "no overlap",
+			input: `
+acl internal {
+  "192.168.0.0"/24;
+  "192.168.1.0"/24;
+  "2001:db8::"/32;
+}`,
+		},
+		{
+			name: "host covered by network",
+			input: `
+acl internal {
+  "192.168.0.0"/24;
+  "192.168.0.10";
+}`,
+			expect: []int{4},
+		},
+		{
+			name: "duplicated entry",
+			input: `
+acl internal {
+  "10.0.0.0"/8;
+  "10.0.0.1"/8;
+}`,
+			expect: []int{4},
+		},
+		{
+			name: "negated entry is an exception of the range",
+			input: `
+acl internal {
+  "10.0.0.0"/8;
+  !"10.1.0.0"/16;
+  "10.1.1.0"/24;
+  "10.2.0.0"/16;
+}`,
+			expect: []int{6},
+		},
+		{
+			name: "conflicted with negated entry",
+			input: `
+acl internal {
+  "10.0.0.0"/8;
+  !"10.0.0.0"/8;
+}`,
+			expect: []int{4},
+		},
+		{
+			name: "IPv4 and IPv6 are not compared",
+			input: `
+acl internal {
+  "::"/0;
+  "192.168.0.1";
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.Lint(vcl, context.New())
+
+			var lines []int
+			for _, d := range l.Diagnostics {
+				if d.Rule == ACL_OVERLAPPED_CIDR {
+					lines = append(lines, d.Token.Line)
+				}
+			}
+			if diff := cmp.Diff(tt.expect, lines); diff != "" {
+				t.Errorf("Overlapped CIDR lines unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLintAclEntryLimitation(t *testing.T) {
+	var entries []string
+	for i := 0; i <= maxAclEntries; i++ {
+		entries = append(entries, fmt.Sprintf(`  "10.0.%d.%d";`, i/256, i%256))
+	}
+	input := "acl internal {\n" + strings.Join(entries, "\n") + "\n}"
+
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		return
+	}
+	l := New()
+	l.Lint(vcl, context.New())
+
+	var count int
+	for _, d := range l.Diagnostics {
+		switch d.Rule {
+		case ACL_ENTRY_LIMITATION:
+			count++
+		case ACL_OVERLAPPED_CIDR:
+			t.Errorf("Unexpected overlapped CIDR at line %d", d.Token.Line)
+		}
+	}
+	if count != 1 {
+		t.Errorf("Entry limitation count unmatch, expect=1, got=%d", count)
+	}
+}
@@ -0,0 +1,31 @@
+package linter
+
+import (
+	"github.com/ysugimoto/falco/annotation"
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Lint annotations in leading comments of the subroutine or the statement
+func (l *Linter) lintAnnotations(comments ast.Comments, target annotation.Target) {
+	if len(comments) == 0 {
+		return
+	}
+
+	_, errs := l.annotations.Parse(comments, target)
+	for _, e := range errs {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    e.Token,
+			Message:  e.Message,
+		}
+		switch e.Kind {
+		case annotation.Unknown:
+			l.Error(err.Match(ANNOTATION_UNKNOWN))
+		case annotation.Duplicated:
+			l.Error(err.Match(ANNOTATION_DUPLICATED))
+		case annotation.Invalid:
+			err.Severity = ERROR
+			l.Error(err.Match(ANNOTATION_INVALID))
+		}
+	}
+}
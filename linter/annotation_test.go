@@ -0,0 +1,102 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintAnnotations(t *testing.T) {
+	input := `
+// @scopes: recv
+sub custom_a {
+  set req.http.A = "1";
+}
+
+// @scope: recv, recv
+sub custom_b {
+  // @scope: recv
+  set req.http.B = "1";
+  // @debugger
+  // @owner: platform-team
+  set req.http.C = "1";
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  call custom_a;
+  call custom_b;
+}`
+
+	tests := []struct {
+		name    string
+		options []Option
+		expect  []Rule
+	}{
+		{
+			name: "unknown, duplicated and invalid annotations",
+			expect: []Rule{
+				ANNOTATION_UNKNOWN,
+				ANNOTATION_DUPLICATED,
+				ANNOTATION_INVALID,
+				ANNOTATION_UNKNOWN,
+			},
+		},
+		{
+			name:    "annotations which are allowed by the option",
+			options: []Option{WithAnnotations([]string{"scopes", "owner"})},
+			expect: []Rule{
+				ANNOTATION_DUPLICATED,
+				ANNOTATION_INVALID,
+			},
+		},
+	}
+
+	t.Run("free-form text in doc comments", func(t *testing.T) {
+		input := `
+# Normalize the request
+# @see https://developer.fastly.com/reference/vcl/
+/**
+ * @author someone
+ */
+sub custom_c {
+  # @todo remove this header
+  set req.http.C = "1";
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  call custom_c;
+}`
+		assertNoError(t, input)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Fatalf("Unexpected parser error: %s", err)
+			}
+			l := New(tt.options...)
+			l.Lint(vcl, context.New())
+
+			var actual []Rule
+			for _, d := range l.Diagnostics {
+				switch d.Rule {
+				case ANNOTATION_UNKNOWN, ANNOTATION_DUPLICATED, ANNOTATION_INVALID:
+					actual = append(actual, d.Rule)
+				}
+			}
+			if len(actual) != len(tt.expect) {
+				t.Fatalf("Annotation errors mismatch, expect=%v, got=%v", tt.expect, actual)
+			}
+			for i := range actual {
+				if actual[i] != tt.expect[i] {
+					t.Errorf("Rule mismatch, expect=%s, got=%s", tt.expect[i], actual[i])
+				}
+			}
+		})
+	}
+}
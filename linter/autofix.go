@@ -0,0 +1,393 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/snippets"
+	"github.com/ysugimoto/falco/token"
+)
+
+// Suggest inserting boilerplate macro comment at the beginning of the subroutine
+func boilerplateMacroFix(sub *ast.SubroutineDeclaration, phrase string) *Fix {
+	return &Fix{
+		Message: "Add #" + phrase + " comment",
+		Edits:   []*TextEdit{insertAfterBrace(sub.Block, "#"+phrase)},
+	}
+}
+
+// Edit which inserts the line right after the opening brace of the block with the indent of following statement
+func insertAfterBrace(block *ast.BlockStatement, text string) *TextEdit {
+	brace := tokenRange(block.GetMeta().Token)
+
+	indent := "  "
+	var next string
+	var first *token.Token
+	for _, stmt := range block.Statements {
+		// Skip statements which are injected from other files like Fastly managed snippets
+		if t := stmt.GetMeta().Token; t.File == brace.File {
+			first = &t
+			break
+		}
+	}
+	if first != nil {
+		if first.Line == brace.Start.Line {
+			// Statement follows the brace on the same line, break the line after the text
+			next = "\n" + indent
+		} else {
+			indent = strings.Repeat(" ", first.Position-1)
+		}
+	} else {
+		next = "\n"
+	}
+
+	return &TextEdit{
+		Range: Range{
+			File:  brace.File,
+			Start: brace.End,
+			End:   brace.End,
+		},
+		NewText: "\n" + indent + text + next,
+	}
+}
+
+// Suggest removing the line of local variable declaration.
+// Nothing is suggested when the declaration may share the line with other statements or braces
+// because the end of statement could not be determined from AST.
+func unusedVariableFix(sub *ast.SubroutineDeclaration, m *ast.Meta) *Fix {
+	var fix *Fix
+	walkBlocks(sub.Block, func(block *ast.BlockStatement) {
+		for i, stmt := range block.Statements {
+			decl, ok := stmt.(*ast.DeclareStatement)
+			if !ok || decl.GetMeta() != m {
+				continue
+			}
+			line := m.Token.Line
+			if decl.ValueType.GetMeta().Token.Line != line {
+				return
+			}
+			if block.GetMeta().Token.Line == line {
+				return
+			}
+			if i > 0 && block.Statements[i-1].GetMeta().Token.Line == line {
+				return
+			}
+			// Closing brace may follow the last statement on the same line
+			if i == len(block.Statements)-1 || block.Statements[i+1].GetMeta().Token.Line == line {
+				return
+			}
+			for _, c := range m.Leading {
+				if c.Token.Line == line {
+					return
+				}
+			}
+			fix = &Fix{
+				Message: "Remove unused variable declaration",
+				Edits: []*TextEdit{
+					{
+						Range: Range{
+							File:  m.Token.File,
+							Start: Position{Line: line, Column: 1},
+							End:   Position{Line: line + 1, Column: 1},
+						},
+					},
+				},
+			}
+			return
+		}
+	})
+	return fix
+}
+
+// Suggest moving the set statement to the Fastly reserved subroutine of the phase which the variable is writable in.
+// The statement is inserted after the boilerplate macro of the destination so that it is executed after Fastly's logic.
+// Nothing is suggested when the statement is nested in other statements because its condition would be lost,
+// when it refers local variables, or when it may share the line with other statements.
+func moveStatementFix(sub *ast.SubroutineDeclaration, stmt *ast.SetStatement, dst *ast.SubroutineDeclaration) *Fix {
+	if dst == nil || dst == sub {
+		return nil
+	}
+	m := stmt.GetMeta()
+	line := m.Token.Line
+	if dst.Block.GetMeta().Token.File != m.Token.File || !isStatementOnOwnLine(sub.Block, stmt) {
+		return nil
+	}
+	if !expressionOnLine(stmt.Value, line) {
+		return nil
+	}
+	for _, ident := range collectIdents(stmt.Value) {
+		if strings.HasPrefix(ident.Value, "var.") {
+			return nil
+		}
+	}
+
+	text := fmt.Sprintf("set %s %s %s;", stmt.Ident.Value, stmt.Operator.Operator, stmt.Value.String())
+	fix := &Fix{
+		Message: "Move statement to " + dst.Name.Value,
+		Edits: []*TextEdit{
+			{
+				Range: Range{
+					File:  m.Token.File,
+					Start: Position{Line: line, Column: 1},
+					End:   Position{Line: line + 1, Column: 1},
+				},
+			},
+		},
+	}
+
+	phrase := snippets.MacroPhrase(getFastlySubroutineScope(dst.Name.Value))
+	for _, s := range dst.Block.Statements {
+		t := s.GetMeta().Token
+		if t.File != m.Token.File || !hasMacroComment(s.GetMeta().Leading, phrase) {
+			continue
+		}
+		// Insert before the line of the statement which follows the macro
+		if s.GetMeta().Leading[len(s.GetMeta().Leading)-1].Token.Line < t.Line {
+			fix.Edits = append(fix.Edits, &TextEdit{
+				Range: Range{
+					File:  t.File,
+					Start: Position{Line: t.Line, Column: 1},
+					End:   Position{Line: t.Line, Column: 1},
+				},
+				NewText: strings.Repeat(" ", t.Position-1) + text + "\n",
+			})
+			return fix
+		}
+		break
+	}
+	fix.Edits = append(fix.Edits, insertAfterBrace(dst.Block, text))
+	return fix
+}
+
+// Returns true if the statement does not share the line with other statements, braces and leading comments.
+// The last statement is never on its own line because the closing brace could not be determined from AST.
+func isStatementOnOwnLine(block *ast.BlockStatement, stmt ast.Statement) bool {
+	m := stmt.GetMeta()
+	line := m.Token.Line
+	if block.GetMeta().Token.Line == line {
+		return false
+	}
+
+	index := -1
+	for i, s := range block.Statements {
+		if s == stmt {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return false
+	}
+	if index > 0 && block.Statements[index-1].GetMeta().Token.Line == line {
+		return false
+	}
+	// Closing brace may follow the last statement on the same line
+	if index == len(block.Statements)-1 || block.Statements[index+1].GetMeta().Token.Line == line {
+		return false
+	}
+	for _, c := range m.Leading {
+		if c.Token.Line == line {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if all tokens of the expression are placed on the line
+func expressionOnLine(exp ast.Expression, line int) bool {
+	switch t := exp.(type) {
+	case *ast.PrefixExpression:
+		return t.GetMeta().Token.Line == line && expressionOnLine(t.Right, line)
+	case *ast.GroupedExpression:
+		return t.GetMeta().Token.Line == line && expressionOnLine(t.Right, line)
+	case *ast.InfixExpression:
+		return expressionOnLine(t.Left, line) && expressionOnLine(t.Right, line)
+	case *ast.FunctionCallExpression:
+		for _, arg := range t.Arguments {
+			if !expressionOnLine(arg, line) {
+				return false
+			}
+		}
+		return t.Function.GetMeta().Token.Line == line
+	case *ast.IfExpression:
+		return false
+	default:
+		return exp.GetMeta().Token.Line == line
+	}
+}
+
+// Suggest renaming local variable to the name which matches naming convention.
+// All references in the subroutine are renamed together, nothing is suggested
+// when the subroutine includes other modules because references in them could not be renamed.
+func renameVariableFix(sub *ast.SubroutineDeclaration, ident *ast.Ident, name string) *Fix {
+	var refs []*ast.Ident
+	declared := map[string]struct{}{}
+	resolvable := walkStatements(sub.Block, func(stmt ast.Statement) bool {
+		if _, ok := stmt.(*ast.IncludeStatement); ok {
+			return false
+		}
+		if decl, ok := stmt.(*ast.DeclareStatement); ok && decl.Name != ident {
+			declared[strings.ToLower(decl.Name.Value)] = struct{}{}
+		}
+		for _, v := range statementIdents(stmt) {
+			if strings.EqualFold(v.Value, ident.Value) {
+				refs = append(refs, v)
+			}
+		}
+		return true
+	})
+	if !resolvable {
+		return nil
+	}
+
+	renamed := "var." + name
+	if _, ok := declared[strings.ToLower(renamed)]; ok {
+		return nil
+	}
+
+	fix := &Fix{
+		Message: "Rename variable to " + renamed,
+	}
+	for _, v := range refs {
+		fix.Edits = append(fix.Edits, &TextEdit{
+			Range:   tokenRange(v.GetMeta().Token),
+			NewText: renamed,
+		})
+	}
+	return fix
+}
+
+// Suggest replacing the identifier with the name
+func replaceIdentFix(ident *ast.Ident, name string) *Fix {
+	return &Fix{
+		Message: "Replace with " + name,
+		Edits: []*TextEdit{
+			{
+				Range:   tokenRange(ident.GetMeta().Token),
+				NewText: name,
+			},
+		},
+	}
+}
+
+// Candidates of the name in other letter cases, in order of preference
+func nameCandidates(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			words = append(words, string(word))
+			word = nil
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			// Split camelCase and the last capital of acronym like "HTTPHeader"
+			prev := runes[i-1]
+			if !unicode.IsUpper(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	words = append(words, string(word))
+
+	var lower, camel, pascal []string
+	for i, w := range words {
+		if w == "" {
+			continue
+		}
+		w = strings.ToLower(w)
+		lower = append(lower, w)
+		title := strings.ToUpper(w[:1]) + w[1:]
+		pascal = append(pascal, title)
+		if i == 0 {
+			camel = append(camel, w)
+		} else {
+			camel = append(camel, title)
+		}
+	}
+
+	snake := strings.Join(lower, "_")
+	return []string{
+		snake,
+		strings.ToUpper(snake),
+		strings.Join(lower, ""),
+		strings.Join(camel, ""),
+		strings.Join(pascal, ""),
+		strings.Join(lower, "-"),
+	}
+}
+
+// Collect idents which are directly used in the statement, nested blocks are not included
+func statementIdents(stmt ast.Statement) []*ast.Ident {
+	var idents []*ast.Ident
+
+	switch t := stmt.(type) {
+	case *ast.DeclareStatement:
+		idents = append(idents, t.Name)
+	case *ast.SetStatement:
+		idents = append(idents, t.Ident)
+		idents = append(idents, collectIdents(t.Value)...)
+	case *ast.AddStatement:
+		idents = append(idents, t.Ident)
+		idents = append(idents, collectIdents(t.Value)...)
+	case *ast.UnsetStatement:
+		idents = append(idents, t.Ident)
+	case *ast.RemoveStatement:
+		idents = append(idents, t.Ident)
+	case *ast.IfStatement:
+		idents = append(idents, collectIdents(t.Condition)...)
+		for _, a := range t.Another {
+			idents = append(idents, collectIdents(a.Condition)...)
+		}
+	case *ast.SwitchStatement:
+		idents = append(idents, collectIdents(t.Control)...)
+	case *ast.LogStatement:
+		idents = append(idents, collectIdents(t.Value)...)
+	case *ast.SyntheticStatement:
+		idents = append(idents, collectIdents(t.Value)...)
+	case *ast.SyntheticBase64Statement:
+		idents = append(idents, collectIdents(t.Value)...)
+	case *ast.ErrorStatement:
+		idents = append(idents, collectIdents(t.Code)...)
+		idents = append(idents, collectIdents(t.Argument)...)
+	case *ast.ReturnStatement:
+		if t.ReturnExpression != nil {
+			idents = append(idents, collectIdents(*t.ReturnExpression)...)
+		}
+	case *ast.FunctionCallStatement:
+		for _, arg := range t.Arguments {
+			idents = append(idents, collectIdents(arg)...)
+		}
+	}
+
+	return idents
+}
+
+// Walk the block and all nested blocks including if statement branches
+func walkBlocks(block *ast.BlockStatement, fn func(block *ast.BlockStatement)) {
+	if block == nil {
+		return
+	}
+	fn(block)
+	for _, stmt := range block.Statements {
+		switch t := stmt.(type) {
+		case *ast.BlockStatement:
+			walkBlocks(t, fn)
+		case *ast.IfStatement:
+			walkBlocks(t.Consequence, fn)
+			for _, a := range t.Another {
+				walkBlocks(a.Consequence, fn)
+			}
+			walkBlocks(t.Alternative, fn)
+		case *ast.SwitchStatement:
+			for _, c := range t.Cases {
+				walkBlocks(caseBlock(c), fn)
+			}
+		}
+	}
+}
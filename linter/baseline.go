@@ -0,0 +1,136 @@
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ysugimoto/falco/lexer"
+)
+
+// BaselineEntry is a lint error which is accepted by the baseline file.
+// The entry is matched by rule, file and the source line which the error is reported at,
+// so that the entry keeps matching even if lines are inserted or removed above it.
+type BaselineEntry struct {
+	Rule   Rule   `json:"rule"`
+	File   string `json:"file"`
+	Source string `json:"source"`
+	// Line and Message are recorded for readability, they are not used for matching
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e *BaselineEntry) key() string {
+	return strings.Join([]string{string(e.Rule), e.File, e.Source}, "\x00")
+}
+
+// Baseline holds existing lint errors in order to report only new ones
+type Baseline struct {
+	Entries []*BaselineEntry `json:"entries"`
+}
+
+// NewBaseline creates baseline which accepts all of the diagnostics
+func NewBaseline(diagnostics []*Diagnostic, lexers map[string]*lexer.Lexer) *Baseline {
+	b := &Baseline{
+		Entries: []*BaselineEntry{},
+	}
+	for _, d := range diagnostics {
+		b.Entries = append(b.Entries, baselineEntry(d, lexers))
+	}
+	// Sort entries to keep the file diff minimum on regeneration
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.File != c.File {
+			return a.File < c.File
+		}
+		if a.Line != c.Line {
+			return a.Line < c.Line
+		}
+		return a.Rule < c.Rule
+	})
+	return b
+}
+
+// LoadBaseline reads baseline file
+func LoadBaseline(file string) (*Baseline, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read baseline file %s: %w", file, err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(buf, &b); err != nil {
+		return nil, fmt.Errorf("Failed to decode baseline file %s: %w", file, err)
+	}
+	return &b, nil
+}
+
+// Save writes baseline to the file
+func (b *Baseline) Save(file string) error {
+	buf, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(file, append(buf, '\n'), 0o644); err != nil {
+		return fmt.Errorf("Failed to write baseline file %s: %w", file, err)
+	}
+	return nil
+}
+
+// Filter returns diagnostics which are not accepted by the baseline,
+// and count of diagnostics which are suppressed.
+// Each entry suppresses one diagnostic, so the same error which is newly added is reported.
+func (b *Baseline) Filter(diagnostics []*Diagnostic, lexers map[string]*lexer.Lexer) ([]*Diagnostic, int) {
+	counts := make(map[string]int)
+	for _, e := range b.Entries {
+		counts[e.key()]++
+	}
+
+	var remains []*Diagnostic
+	var suppressed int
+	for _, d := range diagnostics {
+		key := baselineEntry(d, lexers).key()
+		if counts[key] > 0 {
+			counts[key]--
+			suppressed++
+			continue
+		}
+		remains = append(remains, d)
+	}
+	return remains, suppressed
+}
+
+func baselineEntry(d *Diagnostic, lexers map[string]*lexer.Lexer) *BaselineEntry {
+	var source string
+	if lx, ok := lexers[d.Token.File]; ok {
+		if line, ok := lx.GetLine(d.Token.Line); ok {
+			source = strings.TrimSpace(line)
+		}
+	}
+	return &BaselineEntry{
+		Rule:    d.Rule,
+		File:    baselinePath(d.Token.File),
+		Source:  source,
+		Line:    d.Token.Line,
+		Message: d.Message,
+	}
+}
+
+// Record file path as relative from the working directory,
+// then the baseline file could be shared between machines
+func baselinePath(file string) string {
+	if !filepath.IsAbs(file) {
+		return filepath.ToSlash(file)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(file)
+	}
+	rel, err := filepath.Rel(cwd, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(file)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,67 @@
+package linter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func lintForBaseline(t *testing.T, input string) ([]*Diagnostic, map[string]*lexer.Lexer) {
+	lx := lexer.NewFromString(input, lexer.WithFile("main.vcl"))
+	vcl, err := parser.New(lx).ParseVCL()
+	if err != nil {
+		t.Fatalf("unexpected parser error: %s", err)
+	}
+	lx.NewLine()
+	l := New()
+	l.Lint(vcl, context.New())
+	return l.Diagnostics, map[string]*lexer.Lexer{"main.vcl": lx}
+}
+
+func TestBaseline(t *testing.T) {
+	diagnostics, lexers := lintForBaseline(t, `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.foo STRING;
+}`)
+	baseline := NewBaseline(diagnostics, lexers)
+	if len(baseline.Entries) != 1 {
+		t.Fatalf("Baseline should have 1 entry, got %d", len(baseline.Entries))
+	}
+
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	if err := baseline.Save(file); err != nil {
+		t.Fatalf("Failed to save baseline: %s", err)
+	}
+	loaded, err := LoadBaseline(file)
+	if err != nil {
+		t.Fatalf("Failed to load baseline: %s", err)
+	}
+	if diff := cmp.Diff(baseline, loaded); diff != "" {
+		t.Errorf("Loaded baseline mismatch, diff=%s", diff)
+	}
+
+	// Existing error is moved by inserted lines, and new error is added
+	diagnostics, lexers = lintForBaseline(t, `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.bar STRING;
+  set req.http.Foo = "foo";
+  declare local var.foo STRING;
+}`)
+	remains, suppressed := loaded.Filter(diagnostics, lexers)
+	if suppressed != 1 {
+		t.Errorf("Suppressed count should be 1, got %d", suppressed)
+	}
+	var messages []string
+	for _, d := range remains {
+		messages = append(messages, d.Message)
+	}
+	if diff := cmp.Diff([]string{`Variable "bar" is unused`}, messages); diff != "" {
+		t.Errorf("Remaining diagnostics mismatch, diff=%s", diff)
+	}
+}
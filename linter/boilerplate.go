@@ -0,0 +1,130 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/snippets"
+)
+
+// Consequences of skipping Fastly injected logic for each phase
+var boilerplateConsequences = map[string]string{
+	"recv":    "request settings, conditions, shielding and backend selection configured in the service are not applied",
+	"hash":    "req.vcl.generation is not added to the cache key so purge all does not work",
+	"hit":     "cache hit handling configured in the service is not applied",
+	"miss":    "backend request settings, conditions and shielding configured in the service are not applied",
+	"pass":    "backend request settings, conditions and shielding configured in the service are not applied",
+	"fetch":   "cache settings, response conditions, gzip and surrogate key handling configured in the service are not applied",
+	"error":   "synthetic responses configured in the service are not served",
+	"deliver": "response headers, response conditions and shielding clean-up configured in the service are not applied",
+	"log":     "logging endpoints configured in the service do not receive any logs",
+}
+
+// Lint return and error statements which could be executed before the Fastly boilerplate macro.
+// Fastly expands its own logic at the place of macro so these statements skip the logic silently.
+func (l *Linter) lintStatementsBeforeMacro(sub *ast.SubroutineDeclaration, scope string) {
+	phrase := snippets.MacroPhrase(scope)
+	exits, reached := findExitsBeforeMacro(sub.Block, phrase)
+	if !reached {
+		return
+	}
+
+	for _, stmt := range exits {
+		var statement string
+		switch stmt.(type) {
+		case *ast.ReturnStatement:
+			statement = "return"
+		case *ast.ErrorStatement:
+			statement = "error"
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    stmt.GetMeta().Token,
+			Message: fmt.Sprintf(
+				`Statement "%s" is executed before Fastly boilerplate comment "%s" in %s, %s`,
+				statement, phrase, sub.Name.Value, boilerplateConsequences[scope],
+			),
+		}
+		l.Error(err.Match(SUBROUTINE_EXIT_BEFORE_MACRO))
+	}
+}
+
+// Find return and error statements before the macro in source order.
+// Returns true as second value if the macro is reached in the block.
+func findExitsBeforeMacro(block *ast.BlockStatement, phrase string) ([]ast.Statement, bool) {
+	var exits []ast.Statement
+	if block == nil {
+		return exits, false
+	}
+
+	for _, stmt := range block.Statements {
+		if hasMacroComment(stmt.GetMeta().Leading, phrase) {
+			return exits, true
+		}
+
+		switch t := stmt.(type) {
+		case *ast.ReturnStatement, *ast.ErrorStatement:
+			exits = append(exits, t)
+		case *ast.BlockStatement:
+			nested, reached := findExitsBeforeMacro(t, phrase)
+			exits = append(exits, nested...)
+			if reached {
+				return exits, true
+			}
+		case *ast.IfStatement:
+			// Branches of the if statement are exclusive, so exits in other branches
+			// do not skip the macro which is placed in the branch
+			branches := []*ast.BlockStatement{t.Consequence}
+			for _, a := range t.Another {
+				branches = append(branches, a.Consequence)
+			}
+			if t.Alternative != nil {
+				branches = append(branches, t.Alternative)
+			}
+			nested, reached := findExitsInBranches(branches, phrase)
+			exits = append(exits, nested...)
+			if reached {
+				return exits, true
+			}
+		case *ast.SwitchStatement:
+			// Cases of the switch statement are exclusive as well
+			branches := make([]*ast.BlockStatement, len(t.Cases))
+			for i, c := range t.Cases {
+				branches[i] = caseBlock(c)
+			}
+			nested, reached := findExitsInBranches(branches, phrase)
+			exits = append(exits, nested...)
+			if reached {
+				return exits, true
+			}
+		}
+
+		if hasMacroComment(stmt.GetMeta().Trailing, phrase) {
+			return exits, true
+		}
+	}
+	return exits, hasMacroComment(block.Infix, phrase)
+}
+
+// Find exits in the exclusive branches. When the macro is placed in the branch,
+// only exits before the macro in that branch are returned.
+func findExitsInBranches(branches []*ast.BlockStatement, phrase string) ([]ast.Statement, bool) {
+	var exits []ast.Statement
+	for _, b := range branches {
+		nested, reached := findExitsBeforeMacro(b, phrase)
+		if reached {
+			return nested, true
+		}
+		exits = append(exits, nested...)
+	}
+	return exits, false
+}
+
+func hasMacroComment(comments ast.Comments, phrase string) bool {
+	for _, c := range comments {
+		if snippets.HasMacro(c.Value, phrase) {
+			return true
+		}
+	}
+	return false
+}
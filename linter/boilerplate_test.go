@@ -0,0 +1,94 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintStatementsBeforeMacro(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []int
+	}{
+		{
+			name: "exits after macro",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.url ~ "^/api/") {
+    return (pass);
+  }
+  return (lookup);
+}`,
+		},
+		{
+			name: "exits before macro",
+			input: `
+sub vcl_recv {
+  if (req.url ~ "^/api/") {
+    return (pass);
+  } else if (req.url ~ "^/admin/") {
+    error 403;
+  }
+  #FASTLY RECV
+  return (lookup);
+}`,
+			expect: []int{4, 6},
+		},
+		{
+			name: "macro at the end of subroutine",
+			input: `
+sub vcl_hash {
+  set req.hash += req.url;
+  return (hash);
+  #FASTLY HASH
+}`,
+			expect: []int{4},
+		},
+		{
+			name: "macro in the trailing comment",
+			input: `
+sub vcl_deliver {
+  set resp.http.X-Foo = "bar"; #FASTLY DELIVER
+  return (deliver);
+}`,
+		},
+		{
+			name: "exits in other branches of macro",
+			input: `
+sub vcl_recv {
+  if (req.http.Debug) {
+    error 600;
+  } else {
+    #FASTLY RECV
+  }
+  return (lookup);
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+		if err != nil {
+			t.Errorf("%s: unexpected parser error: %s", tt.name, err)
+			continue
+		}
+		l := New()
+		l.Lint(vcl, context.New())
+
+		var actual []int
+		for _, d := range l.Diagnostics {
+			if d.Rule == SUBROUTINE_EXIT_BEFORE_MACRO {
+				actual = append(actual, d.Token.Line)
+			}
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: reported lines mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package linter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Recommended range of hit-for-pass TTL.
+// return(pass) in vcl_fetch creates hit-for-pass object which lives for beresp.ttl.
+const (
+	minHitForPassTTL = time.Second
+	maxHitForPassTTL = time.Hour
+)
+
+// Lint cache directives in fetch scope subroutine do not conflict with each other.
+// Directives are checked in the same block because the branch may change the effective behavior.
+func (l *Linter) lintCacheDirectives(decl *ast.SubroutineDeclaration, scope int) {
+	if scope&context.FETCH == 0 {
+		return
+	}
+
+	l.lintCacheDirectivesBlock(decl.Block)
+	walkStatements(decl.Block, func(stmt ast.Statement) bool {
+		switch t := stmt.(type) {
+		case *ast.BlockStatement:
+			l.lintCacheDirectivesBlock(t)
+		case *ast.IfStatement:
+			l.lintCacheDirectivesBlock(t.Consequence)
+			for _, a := range t.Another {
+				l.lintCacheDirectivesBlock(a.Consequence)
+			}
+			l.lintCacheDirectivesBlock(t.Alternative)
+		case *ast.SwitchStatement:
+			for _, c := range t.Cases {
+				l.lintCacheDirectivesBlock(caseBlock(c))
+			}
+		}
+		return true
+	})
+}
+
+func (l *Linter) lintCacheDirectivesBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+
+	var ttl *ast.SetStatement
+	var stale []*ast.SetStatement
+	// Statement which makes the response uncacheable
+	var uncacheable string
+
+	for _, stmt := range block.Statements {
+		switch t := stmt.(type) {
+		case *ast.SetStatement:
+			switch strings.ToLower(t.Ident.Value) {
+			case "beresp.ttl":
+				ttl = t
+			case "beresp.stale_while_revalidate", "beresp.stale_if_error":
+				stale = append(stale, t)
+			case "beresp.cacheable":
+				if v, ok := t.Value.(*ast.Boolean); ok && !v.Value {
+					uncacheable = "beresp.cacheable = false"
+				} else {
+					uncacheable = ""
+				}
+			}
+		case *ast.ReturnStatement:
+			if !isReturnState(t, "pass") {
+				continue
+			}
+			uncacheable = "return(pass)"
+			if ttl != nil {
+				l.lintTTLBeforePass(ttl)
+			}
+		}
+	}
+
+	if uncacheable == "" {
+		return
+	}
+	for _, s := range stale {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    s.Ident.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"%s has no effect because the response is not cached by %s, stale content could not be served",
+				s.Ident.Value, uncacheable,
+			),
+		}
+		l.Error(err.Match(CACHE_STALE_UNCACHEABLE))
+	}
+}
+
+func (l *Linter) lintTTLBeforePass(ttl *ast.SetStatement) {
+	if v, ok := ttl.Value.(*ast.RTime); ok {
+		if d, ok := parseRTime(v.Value); ok && (d < minHitForPassTTL || d > maxHitForPassTTL) {
+			message := fmt.Sprintf(
+				"Hit-for-pass TTL %s is too long, the response is not cached until the hit-for-pass object expires even if the backend returns cacheable response",
+				v.Value,
+			)
+			if d < minHitForPassTTL {
+				message = fmt.Sprintf(
+					"Hit-for-pass TTL %s is too short, hit-for-pass object expires immediately and following requests wait for this response by request collapsing",
+					v.Value,
+				)
+			}
+			err := &LintError{
+				Severity: WARNING,
+				Token:    v.GetMeta().Token,
+				Message:  message + fmt.Sprintf(", recommended range is %s to %s", minHitForPassTTL, maxHitForPassTTL),
+			}
+			l.Error(err.Match(CACHE_HIT_FOR_PASS_TTL))
+			return
+		}
+	}
+
+	err := &LintError{
+		Severity: INFO,
+		Token:    ttl.Ident.GetMeta().Token,
+		Message:  "beresp.ttl does not cache the response because return(pass) follows, the TTL is used as the lifetime of hit-for-pass object",
+	}
+	l.Error(err.Match(CACHE_TTL_BEFORE_PASS))
+}
+
+// Parse RTIME literal like 10s, 5m, 1d or 1y
+func parseRTime(v string) (time.Duration, bool) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(v, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(v, "y"):
+		unit = 365 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+	num, err := strconv.ParseFloat(v[:len(v)-1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(num * float64(unit)), true
+}
@@ -0,0 +1,105 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintCacheDirectives(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []Rule
+	}{
+		{
+			name: "consistent directives",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.http.Set-Cookie) {
+    return(pass);
+  }
+  set beresp.ttl = 1h;
+  set beresp.stale_while_revalidate = 60s;
+  return(deliver);
+}`,
+		},
+		{
+			name: "ttl before pass",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.http.Cache-Control ~ "private") {
+    set beresp.ttl = 120s;
+    return(pass);
+  }
+}`,
+			expect: []Rule{CACHE_TTL_BEFORE_PASS},
+		},
+		{
+			name: "hit-for-pass ttl out of range",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.http.Cache-Control ~ "private") {
+    set beresp.ttl = 0s;
+    return(pass);
+  }
+  if (beresp.http.Set-Cookie) {
+    set beresp.ttl = 1d;
+    return(pass);
+  }
+}`,
+			expect: []Rule{CACHE_HIT_FOR_PASS_TTL, CACHE_HIT_FOR_PASS_TTL},
+		},
+		{
+			name: "stale with uncacheable response",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.stale_while_revalidate = 60s;
+  if (beresp.status >= 500) {
+    set beresp.stale_if_error = 1d;
+    set beresp.cacheable = false;
+  }
+  if (beresp.http.Set-Cookie) {
+    set beresp.stale_while_revalidate = 30s;
+    return(pass);
+  }
+}`,
+			expect: []Rule{CACHE_STALE_UNCACHEABLE, CACHE_STALE_UNCACHEABLE},
+		},
+		{
+			name: "not in fetch scope",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  return(pass);
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+
+			var rules []Rule
+			for _, d := range l.Diagnostics {
+				rules = append(rules, d.Rule)
+			}
+			if diff := cmp.Diff(tt.expect, rules); diff != "" {
+				t.Errorf("Lint rules unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
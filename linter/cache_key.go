@@ -0,0 +1,77 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Values which Fastly adds to req.hash in the default vcl_hash subroutine.
+// Cache key is shared between different URLs or hosts if any of them is not added.
+var defaultCacheKeys = []struct {
+	name     string
+	resource string
+}{
+	{name: "req.url", resource: "URLs"},
+	{name: "req.http.host", resource: "hosts"},
+}
+
+func isReqHash(name string) bool {
+	return strings.EqualFold(name, "req.hash")
+}
+
+// req.hash could be modified only in vcl_hash, Fastly rejects the VCL otherwise.
+// Returns true if the statement is reported in order to skip the following assignment checks
+// which are meaningless for the inaccessible variable.
+func (l *Linter) lintReqHashScope(stmt *ast.SetStatement, ctx *context.Context) bool {
+	if !isReqHash(stmt.Ident.Value) || ctx.Mode()&(context.HASH|context.ERROR) > 0 {
+		return false
+	}
+	err := &LintError{
+		Severity: ERROR,
+		Token:    stmt.Ident.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"%s could only be modified in vcl_hash subroutine but it is set in %s scope",
+			stmt.Ident.Value, strings.TrimSpace(context.ScopesString(ctx.Mode())),
+		),
+	}
+	l.Error(err.Match(CACHE_HASH_SCOPE))
+	return true
+}
+
+// Lint overridden vcl_hash subroutine keeps default cache keys.
+// Without req.url or req.http.host, different resources share the same cache object silently.
+func (l *Linter) lintCacheKeys(decl *ast.SubroutineDeclaration) {
+	if decl.Name.Value != "vcl_hash" {
+		return
+	}
+
+	added := map[string]bool{}
+	walkStatements(decl.Block, func(stmt ast.Statement) bool {
+		set, ok := stmt.(*ast.SetStatement)
+		if !ok || !isReqHash(set.Ident.Value) {
+			return true
+		}
+		for _, ident := range collectIdents(set.Value) {
+			added[strings.ToLower(ident.Value)] = true
+		}
+		return true
+	})
+
+	for _, key := range defaultCacheKeys {
+		if added[key.name] {
+			continue
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    decl.Name.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"vcl_hash does not add %s to req.hash, responses for different %s share the same cache object",
+				key.name, key.resource,
+			),
+		}
+		l.Error(err.Match(CACHE_HASH_MISSING_KEY))
+	}
+}
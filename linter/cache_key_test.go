@@ -0,0 +1,99 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintCacheKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []Rule
+	}{
+		{
+			name: "default cache keys",
+			input: `
+sub vcl_hash {
+  set req.hash += req.url;
+  set req.hash += req.http.host;
+  #FASTLY HASH
+  return(hash);
+}`,
+		},
+		{
+			name: "cache keys in condition",
+			input: `
+sub vcl_hash {
+  if (req.http.X-Vary) {
+    set req.hash += req.url req.http.Host;
+  } else {
+    set req.hash += req.url;
+    set req.hash += req.http.host;
+  }
+  #FASTLY HASH
+  return(hash);
+}`,
+		},
+		{
+			name: "missing host",
+			input: `
+sub vcl_hash {
+  set req.hash += req.url;
+  #FASTLY HASH
+  return(hash);
+}`,
+			expect: []Rule{CACHE_HASH_MISSING_KEY},
+		},
+		{
+			name: "missing all keys",
+			input: `
+sub vcl_hash {
+  #FASTLY HASH
+  return(hash);
+}`,
+			expect: []Rule{CACHE_HASH_MISSING_KEY, CACHE_HASH_MISSING_KEY},
+		},
+		{
+			name: "modify req.hash outside vcl_hash",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.hash += req.http.Accept-Language;
+}`,
+			expect: []Rule{CACHE_HASH_SCOPE},
+		},
+		{
+			name: "modify req.hash in hash scope subroutine",
+			input: `
+// @scope: hash
+sub custom_hash {
+  set req.hash += req.http.Accept-Language;
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+
+			var rules []Rule
+			for _, d := range l.Diagnostics {
+				rules = append(rules, d.Rule)
+			}
+			if diff := cmp.Diff(tt.expect, rules); diff != "" {
+				t.Errorf("Lint rules unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
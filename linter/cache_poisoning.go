@@ -0,0 +1,274 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Request headers which are included in the cache key of Fastly's default vcl_hash
+var defaultHashHeaders = []string{"host"}
+
+// Response which is stored in the cache is varied by the request header when vcl_fetch or vcl_miss branches on the header.
+// If the header is neither added to req.hash nor listed in Vary header, the first response is served for any header value,
+// so attacker could poison the cache by sending crafted header.
+// Note that vcl_deliver is not checked because modifications in it are not stored in the cache.
+func (l *Linter) lintCachePoisoning(ctx *context.Context) {
+	keys, ok := cacheKeyHeaders(ctx)
+	if !ok {
+		return
+	}
+	vary, ok := varyHeaders(ctx)
+	if !ok {
+		return
+	}
+
+	reported := map[string]bool{}
+	for _, decl := range sortedSubroutines(ctx) {
+		scope := getSubroutineCallScope(decl)
+		if scope&(context.FETCH|context.MISS) == 0 {
+			continue
+		}
+		walkStatements(decl.Block, func(stmt ast.Statement) bool {
+			t, ok := stmt.(*ast.IfStatement)
+			if !ok {
+				return true
+			}
+			modified := cachedObjectModification(t, scope)
+			if modified == nil {
+				return true
+			}
+			for _, ident := range ifConditionIdents(t) {
+				name, ok := requestHeaderName(ident.Value)
+				if !ok || keys[name] || vary[name] || reported[name] {
+					continue
+				}
+				reported[name] = true
+				err := &LintError{
+					Severity: WARNING,
+					Token:    ident.GetMeta().Token,
+					Message: fmt.Sprintf(
+						"Cached response is varied by %s in %s but the header is neither added to req.hash nor listed in Vary, cache could be poisoned",
+						ident.Value, decl.Name.Value,
+					),
+				}
+				err.Relate(modified.GetMeta().Token, "Cached object is modified here")
+				l.Error(err.Match(CACHE_POISONING))
+			}
+			return true
+		})
+	}
+}
+
+// Collect request header names which are added to req.hash in vcl_hash.
+// Returns false if the cache key could not be determined statically.
+func cacheKeyHeaders(ctx *context.Context) (map[string]bool, bool) {
+	keys := map[string]bool{}
+	hash, ok := ctx.Subroutines["vcl_hash"]
+	if !ok || hash.Decl == nil {
+		for _, h := range defaultHashHeaders {
+			keys[h] = true
+		}
+		return keys, true
+	}
+
+	determined := true
+	walkStatements(hash.Decl.Block, func(stmt ast.Statement) bool {
+		switch t := stmt.(type) {
+		case *ast.SetStatement:
+			if !isReqHash(t.Ident.Value) {
+				return true
+			}
+			for _, ident := range collectIdents(t.Value) {
+				if name, ok := requestHeaderName(ident.Value); ok {
+					keys[name] = true
+				}
+			}
+		case *ast.CallStatement:
+			// Cache key may be added in the called subroutine
+			determined = false
+		}
+		return determined
+	})
+	return keys, determined
+}
+
+// Collect header names which are listed in Vary header of the backend response.
+// Returns false if Vary header is set dynamically.
+func varyHeaders(ctx *context.Context) (map[string]bool, bool) {
+	vary := map[string]bool{}
+	determined := true
+	for _, decl := range sortedSubroutines(ctx) {
+		walkStatements(decl.Block, func(stmt ast.Statement) bool {
+			var ident *ast.Ident
+			var value ast.Expression
+			switch t := stmt.(type) {
+			case *ast.SetStatement:
+				ident, value = t.Ident, t.Value
+			case *ast.AddStatement:
+				ident, value = t.Ident, t.Value
+			default:
+				return true
+			}
+			name := strings.ToLower(ident.Value)
+			if !isVaryHeader(name) {
+				return true
+			}
+			// Subfield accessor like beresp.http.Vary:Accept-Language adds the header to the list
+			if _, field, ok := strings.Cut(name, ":"); ok {
+				vary[field] = true
+				return true
+			}
+			for _, v := range collectStringValues(value) {
+				for _, h := range strings.Split(v, ",") {
+					vary[strings.ToLower(strings.TrimSpace(h))] = true
+				}
+			}
+			for _, id := range collectIdents(value) {
+				// Vary value is copied from another variable
+				if !isVaryHeader(strings.ToLower(id.Value)) {
+					determined = false
+				}
+			}
+			return determined
+		})
+	}
+	// Vary: * disables caching entirely
+	if vary["*"] {
+		return vary, false
+	}
+	return vary, determined
+}
+
+// Find the first statement in the branches of if statement which modifies the object stored in the cache.
+// Branches which make the response uncacheable are ignored.
+func cachedObjectModification(stmt *ast.IfStatement, scope int) ast.Statement {
+	prefix := "beresp."
+	if scope&context.FETCH == 0 {
+		prefix = "bereq."
+	}
+
+	branches := []*ast.BlockStatement{stmt.Consequence}
+	for _, a := range stmt.Another {
+		branches = append(branches, a.Consequence)
+	}
+	branches = append(branches, stmt.Alternative)
+
+	for _, block := range branches {
+		if block == nil || isUncacheableBranch(block) {
+			continue
+		}
+		var found ast.Statement
+		walkStatements(block, func(s ast.Statement) bool {
+			var ident *ast.Ident
+			switch t := s.(type) {
+			case *ast.SetStatement:
+				ident = t.Ident
+			case *ast.AddStatement:
+				ident = t.Ident
+			case *ast.UnsetStatement:
+				ident = t.Ident
+			case *ast.RemoveStatement:
+				ident = t.Ident
+			default:
+				return true
+			}
+			name := strings.ToLower(ident.Value)
+			// Cache control variables do not vary the content
+			if !strings.HasPrefix(name, prefix) || isVaryHeader(name) || isCacheControlVariable(name) {
+				return true
+			}
+			found = s
+			return false
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func isVaryHeader(name string) bool {
+	return name == "beresp.http.vary" || strings.HasPrefix(name, "beresp.http.vary:")
+}
+
+func isUncacheableBranch(block *ast.BlockStatement) bool {
+	var uncacheable bool
+	walkStatements(block, func(stmt ast.Statement) bool {
+		switch t := stmt.(type) {
+		case *ast.ReturnStatement:
+			uncacheable = isReturnState(t, "pass")
+		case *ast.ErrorStatement:
+			uncacheable = true
+		case *ast.SetStatement:
+			if strings.EqualFold(t.Ident.Value, "beresp.cacheable") {
+				if v, ok := t.Value.(*ast.Boolean); ok && !v.Value {
+					uncacheable = true
+				}
+			}
+		}
+		return !uncacheable
+	})
+	return uncacheable
+}
+
+func isCacheControlVariable(name string) bool {
+	switch name {
+	case "beresp.ttl", "beresp.grace", "beresp.stale_while_revalidate", "beresp.stale_if_error",
+		"beresp.cacheable", "beresp.http.cache-control", "beresp.http.surrogate-control",
+		"beresp.http.surrogate-key", "beresp.http.expires":
+		return true
+	}
+	return false
+}
+
+// Get lower-cased header name from the request header variable like req.http.X-Foo:bar
+func requestHeaderName(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if !strings.HasPrefix(name, "req.http.") {
+		return "", false
+	}
+	header, _, _ := strings.Cut(strings.TrimPrefix(name, "req.http."), ":")
+	return header, true
+}
+
+func ifConditionIdents(stmt *ast.IfStatement) []*ast.Ident {
+	idents := collectIdents(stmt.Condition)
+	for _, a := range stmt.Another {
+		idents = append(idents, collectIdents(a.Condition)...)
+	}
+	return idents
+}
+
+func collectStringValues(exp ast.Expression) []string {
+	switch t := exp.(type) {
+	case *ast.String:
+		return []string{t.Value}
+	case *ast.GroupedExpression:
+		return collectStringValues(t.Right)
+	case *ast.InfixExpression:
+		return append(collectStringValues(t.Left), collectStringValues(t.Right)...)
+	}
+	return nil
+}
+
+// Get declared subroutines in the source order to report problems deterministically
+func sortedSubroutines(ctx *context.Context) []*ast.SubroutineDeclaration {
+	var decls []*ast.SubroutineDeclaration
+	for _, s := range ctx.Subroutines {
+		if s.Decl != nil {
+			decls = append(decls, s.Decl)
+		}
+	}
+	sort.Slice(decls, func(i, j int) bool {
+		a, b := decls[i].GetMeta().Token, decls[j].GetMeta().Token
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return decls
+}
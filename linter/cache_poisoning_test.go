@@ -0,0 +1,138 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintCachePoisoning(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []Rule
+	}{
+		{
+			name: "branch on header which is not in cache key",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (req.http.X-Forwarded-Host) {
+    set beresp.http.Location = "https://" req.http.X-Forwarded-Host;
+  }
+  return(deliver);
+}`,
+			expect: []Rule{CACHE_POISONING},
+		},
+		{
+			name: "header is added to req.hash",
+			input: `
+sub vcl_hash {
+  set req.hash += req.url;
+  set req.hash += req.http.host;
+  set req.hash += req.http.X-Forwarded-Host;
+  #FASTLY HASH
+  return(hash);
+}
+
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (req.http.X-Forwarded-Host) {
+    set beresp.http.Location = "https://" req.http.X-Forwarded-Host;
+  }
+  return(deliver);
+}`,
+		},
+		{
+			name: "header is listed in Vary",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.http.Vary = "Accept-Encoding, Accept-Language";
+  if (req.http.Accept-Language ~ "^ja") {
+    set beresp.http.Content-Language = "ja";
+  }
+  if (req.http.X-Device) {
+    set beresp.http.Vary:X-Device = "";
+    set beresp.http.X-Layout = req.http.X-Device;
+  }
+  return(deliver);
+}`,
+		},
+		{
+			name: "uncacheable branch and cache control",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (req.http.Authorization) {
+    set beresp.http.X-Private = "1";
+    return(pass);
+  }
+  if (req.http.X-Long-Cache) {
+    set beresp.ttl = 1d;
+  }
+  return(deliver);
+}`,
+		},
+		{
+			name: "backend request is modified in vcl_miss",
+			input: `
+sub vcl_miss {
+  #FASTLY MISS
+  if (req.http.X-Api-Version == "2") {
+    set bereq.url = "/v2" req.url;
+  }
+  return(fetch);
+}`,
+			expect: []Rule{CACHE_POISONING},
+		},
+		{
+			name: "cache key is added in called subroutine",
+			input: `
+sub custom_hash {
+  set req.hash += req.http.X-Api-Version;
+}
+
+sub vcl_hash {
+  set req.hash += req.url;
+  set req.hash += req.http.host;
+  call custom_hash;
+  #FASTLY HASH
+  return(hash);
+}
+
+sub vcl_miss {
+  #FASTLY MISS
+  if (req.http.X-Api-Version == "2") {
+    set bereq.url = "/v2" req.url;
+  }
+  return(fetch);
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.Lint(vcl, context.New())
+
+			var rules []Rule
+			for _, d := range l.Diagnostics {
+				if d.Rule == CACHE_POISONING {
+					rules = append(rules, d.Rule)
+				}
+			}
+			if diff := cmp.Diff(tt.expect, rules); diff != "" {
+				t.Errorf("Lint rules unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
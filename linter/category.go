@@ -0,0 +1,106 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Category string
+
+const (
+	CORRECTNESS Category = "correctness"
+	STYLE       Category = "style"
+	PERFORMANCE Category = "performance"
+	SECURITY    Category = "security"
+)
+
+var allCategories = []Category{CORRECTNESS, STYLE, PERFORMANCE, SECURITY}
+
+// Rules which are not listed here are categorized as correctness
+var categories = map[Rule]Category{
+	TABLE_ITEM_LIMITATION:                  PERFORMANCE,
+	ACL_ENTRY_LIMITATION:                   PERFORMANCE,
+	COMPILATION_VCL_SIZE:                   PERFORMANCE,
+	COMPILATION_SUBROUTINES:                PERFORMANCE,
+	COMPILATION_WORKSPACE:                  PERFORMANCE,
+	TABLE_KEY_LENGTH:                       PERFORMANCE,
+	TABLE_VALUE_LENGTH:                     PERFORMANCE,
+	CACHE_TTL_BEFORE_PASS:                  PERFORMANCE,
+	CACHE_STALE_UNCACHEABLE:                PERFORMANCE,
+	CACHE_HIT_FOR_PASS_TTL:                 PERFORMANCE,
+	ERROR_STATEMENT_CODE:                   STYLE,
+	UNUSED_DECLARATION:                     STYLE,
+	UNUSED_VARIABLE:                        STYLE,
+	UNUSED_GOTO:                            STYLE,
+	DISALLOW_EMPTY_RETURN:                  STYLE,
+	NAMING_CONVENTION:                      STYLE,
+	NAMING_SHADOWING:                       STYLE,
+	SOURCE_ENCODING:                        STYLE,
+	ANNOTATION_DUPLICATED:                  STYLE,
+	COMPLEXITY_CYCLOMATIC:                  STYLE,
+	COMPLEXITY_NESTING:                     STYLE,
+	DEBUG_HEADER_LEAK:                      SECURITY,
+	CACHE_POISONING:                        SECURITY,
+	CACHE_SET_COOKIE:                       SECURITY,
+	COMPLIANCE_LOG_SENSITIVE_HEADER:        SECURITY,
+	COMPLIANCE_AUTHENTICATED_CACHE_CONTROL: SECURITY,
+	COMPLIANCE_BACKEND_TLS_VERSION:         SECURITY,
+}
+
+func (r Rule) Category() Category {
+	if v, ok := categories[r]; ok {
+		return v
+	}
+	return CORRECTNESS
+}
+
+// ParseCategories parses category names. Each value could contain comma separated names
+// like "correctness,security" as it is specified in CLI option.
+func ParseCategories(values []string) ([]Category, error) {
+	var parsed []Category
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if v == "" {
+				continue
+			}
+			c, err := parseCategory(v)
+			if err != nil {
+				return nil, err
+			}
+			parsed = append(parsed, c)
+		}
+	}
+	return parsed, nil
+}
+
+func parseCategory(v string) (Category, error) {
+	for _, c := range allCategories {
+		if string(c) == v {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf(`Unknown rule category "%s", category must be one of correctness, style, performance, security`, v)
+}
+
+// Category filter to run the subset of rules.
+// If only list is not empty, rules which do not belong to listed categories are not reported,
+// and rules which belong to skip list are not reported.
+type categoryFilter struct {
+	only map[Category]struct{}
+	skip map[Category]struct{}
+}
+
+func (f *categoryFilter) allow(r Rule) bool {
+	if f == nil {
+		return true
+	}
+	c := r.Category()
+	if len(f.only) > 0 {
+		if _, ok := f.only[c]; !ok {
+			return false
+		}
+	}
+	_, skip := f.skip[c]
+	return !skip
+}
@@ -0,0 +1,81 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestParseCategories(t *testing.T) {
+	actual, err := ParseCategories([]string{"correctness,Security", " style "})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if diff := cmp.Diff([]Category{CORRECTNESS, SECURITY, STYLE}, actual); diff != "" {
+		t.Errorf("Parsed categories mismatch, diff=%s", diff)
+	}
+
+	if _, err := ParseCategories([]string{"correctness,unknown"}); err == nil {
+		t.Errorf("Expected error for unknown category but got nil")
+	}
+}
+
+func TestLintWithCategories(t *testing.T) {
+	input := `
+acl unused_acl {
+  "192.0.2.0"/24;
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = 1;
+}`
+
+	tests := []struct {
+		name   string
+		only   []Category
+		skip   []Category
+		expect []Rule
+	}{
+		{
+			name:   "all categories",
+			expect: []Rule{OPERATOR_ASSIGNMENT, UNUSED_DECLARATION},
+		},
+		{
+			name:   "only style",
+			only:   []Category{STYLE},
+			expect: []Rule{UNUSED_DECLARATION},
+		},
+		{
+			name:   "skip style",
+			skip:   []Category{STYLE},
+			expect: []Rule{OPERATOR_ASSIGNMENT},
+		},
+		{
+			name: "only security",
+			only: []Category{SECURITY},
+		},
+	}
+
+	for _, tt := range tests {
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("Unexpected parser error: %s", err)
+			return
+		}
+		l := New(WithCategories(tt.only, tt.skip))
+		l.Lint(vcl, context.New())
+
+		var actual []Rule
+		for _, d := range l.Diagnostics {
+			actual = append(actual, d.Rule)
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: reported rules mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Default thresholds of Fastly compilation limits, they could be increased by contacting to Fastly support
+// https://docs.fastly.com/en/guides/resource-limits
+const (
+	defaultMaxVCLSize     = 1024 * 1024
+	defaultMaxSubroutines = 1000
+	defaultMaxWorkspace   = 128 * 1024
+)
+
+// Compiled if statement stores the evaluated condition in the workspace,
+// so the usage is estimated by the condition length with the fixed overhead of the branch.
+const ifStatementWorkspace = 64
+
+// Estimated usage which is accumulated while linting the main VCL
+type compilationEstimate struct {
+	// Size difference of include statements in subroutines and resolved statements
+	includedSize int
+	// Workspace usage of if statements
+	workspace         int
+	workspaceReported bool
+}
+
+func (l *Linter) compilationLimit() (maxVCLSize, maxSubroutines, maxWorkspace int) {
+	maxVCLSize, maxSubroutines, maxWorkspace = defaultMaxVCLSize, defaultMaxSubroutines, defaultMaxWorkspace
+	if l.compilationLimits == nil {
+		return
+	}
+	if l.compilationLimits.MaxVCLSize > 0 {
+		maxVCLSize = l.compilationLimits.MaxVCLSize
+	}
+	if l.compilationLimits.MaxSubroutines > 0 {
+		maxSubroutines = l.compilationLimits.MaxSubroutines
+	}
+	if l.compilationLimits.MaxWorkspace > 0 {
+		maxWorkspace = l.compilationLimits.MaxWorkspace
+	}
+	return
+}
+
+// Include statements in subroutines are resolved while linting the block,
+// so accumulate the size difference to estimate the size of generated VCL.
+func (l *Linter) estimateIncludedSize(original, resolved []ast.Statement) {
+	var included []ast.Statement
+	for _, stmt := range original {
+		if _, ok := stmt.(*ast.IncludeStatement); ok {
+			included = append(included, stmt)
+		}
+	}
+	if len(included) == 0 {
+		return
+	}
+
+	statements := make(map[ast.Statement]struct{}, len(original))
+	for _, stmt := range original {
+		statements[stmt] = struct{}{}
+	}
+	for _, stmt := range included {
+		l.compilation.includedSize -= len(stmt.String())
+	}
+	for _, stmt := range resolved {
+		if _, ok := statements[stmt]; !ok {
+			l.compilation.includedSize += len(stmt.String())
+		}
+	}
+}
+
+// Accumulate workspace usage of if statement and report once when it exceeds the limit
+func (l *Linter) estimateIfWorkspace(stmt *ast.IfStatement) {
+	l.compilation.workspace += ifStatementWorkspace + len(stmt.Condition.String())
+	for _, a := range stmt.Another {
+		l.compilation.workspace += ifStatementWorkspace + len(a.Condition.String())
+	}
+
+	_, _, maxWorkspace := l.compilationLimit()
+	if l.compilation.workspace <= maxWorkspace || l.compilation.workspaceReported {
+		return
+	}
+	l.compilation.workspaceReported = true
+	err := &LintError{
+		Severity: WARNING,
+		Token:    stmt.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"If statements are estimated to use %d bytes of workspace which exceeds the limit of %d bytes, "+
+				"consider using tables or switch statement instead of many conditions",
+			l.compilation.workspace, maxWorkspace,
+		),
+	}
+	l.Error(err.Match(COMPILATION_WORKSPACE))
+}
+
+// Lint the estimated size of generated VCL and total subroutine count after all statements are linted
+func (l *Linter) lintCompilationLimits(statements []ast.Statement) {
+	if len(statements) == 0 {
+		return
+	}
+	maxVCLSize, maxSubroutines, _ := l.compilationLimit()
+
+	size := l.compilation.includedSize
+	var subroutines int
+	for _, stmt := range statements {
+		size += len(stmt.String())
+		sub, ok := stmt.(*ast.SubroutineDeclaration)
+		if !ok {
+			continue
+		}
+		subroutines++
+		if subroutines != maxSubroutines+1 {
+			continue
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    sub.Name.GetMeta().Token,
+			Message:  fmt.Sprintf("Subroutine %s exceeds the limit of %d subroutines", sub.Name.Value, maxSubroutines),
+		}
+		l.Error(err.Match(COMPILATION_SUBROUTINES))
+	}
+
+	if size > maxVCLSize {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    statements[0].GetMeta().Token,
+			Message: fmt.Sprintf(
+				"VCL is estimated to be %d bytes after resolving includes and snippets, which exceeds the limit of %d bytes",
+				size, maxVCLSize,
+			),
+		}
+		l.Error(err.Match(COMPILATION_VCL_SIZE))
+	}
+}
@@ -0,0 +1,82 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintCompilationLimits(t *testing.T) {
+	recv := `
+sub vcl_recv {
+  #FASTLY RECV
+  include "routes";
+  call foo;
+  call bar;
+}`
+	subroutines := `
+sub foo {
+  set req.http.Foo = "1";
+}
+
+sub bar {
+  set req.http.Bar = "1";
+}`
+	var routes strings.Builder
+	for i := 0; i < 10; i++ {
+		routes.WriteString(fmt.Sprintf("if (req.url.path == \"/route/%d\") {\n  set req.http.Route = \"%d\";\n}\n", i, i))
+	}
+
+	tests := []struct {
+		name   string
+		config *config.CompilationLimitsConfig
+		expect []string
+	}{
+		{
+			name: "within default limits",
+		},
+		{
+			name:   "included statements exceed VCL size",
+			config: &config.CompilationLimitsConfig{MaxVCLSize: len(recv) + len(subroutines)},
+			expect: []string{"2:compilation/vcl-size"},
+		},
+		{
+			name:   "subroutine count exceeds the limit",
+			config: &config.CompilationLimitsConfig{MaxSubroutines: 2},
+			expect: []string{"12:compilation/subroutines"},
+		},
+		{
+			name:   "if statements exceed workspace",
+			config: &config.CompilationLimitsConfig{MaxWorkspace: 500},
+			expect: []string{"16:compilation/workspace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(recv + subroutines)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			r := &mockResolver{dependency: map[string]string{"routes": routes.String()}}
+			l := New(WithCompilationLimits(tt.config))
+			l.lint(vcl, context.New(context.WithResolver(r)))
+			assertRuleMatched(t, l)
+
+			var actual []string
+			for _, d := range l.Diagnostics {
+				actual = append(actual, fmt.Sprintf("%d:%s", d.Token.Line, d.Rule))
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Diagnostics unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Default thresholds which are used when the configuration is not specified
+const (
+	defaultMaxCyclomatic = 20
+	defaultMaxNesting    = 5
+)
+
+// SubroutineMetrics is complexity metrics of the subroutine
+type SubroutineMetrics struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Number of linearly independent paths, 1 + number of branches and logical operators
+	Cyclomatic int `json:"cyclomatic"`
+	// Maximum depth of nested if and block statements
+	Nesting int `json:"nesting"`
+
+	// Statement which opens the deepest block
+	deepest ast.Node
+}
+
+// Complexity computes complexity metrics of the subroutine
+func Complexity(sub *ast.SubroutineDeclaration) *SubroutineMetrics {
+	token := sub.Name.GetMeta().Token
+	m := &SubroutineMetrics{
+		Name:       sub.Name.Value,
+		File:       token.File,
+		Line:       token.Line,
+		Cyclomatic: 1,
+	}
+	m.walk(sub.Block, 0)
+	return m
+}
+
+func (m *SubroutineMetrics) walk(block *ast.BlockStatement, depth int) {
+	if block == nil {
+		return
+	}
+	for _, stmt := range block.Statements {
+		for _, exp := range statementExpressions(stmt) {
+			m.Cyclomatic += decisions(exp)
+		}
+
+		switch t := stmt.(type) {
+		case *ast.BlockStatement:
+			m.nest(t, depth)
+			m.walk(t, depth+1)
+		case *ast.IfStatement:
+			m.Cyclomatic += 1 + len(t.Another)
+			m.nest(t, depth)
+			m.walk(t.Consequence, depth+1)
+			for _, a := range t.Another {
+				m.walk(a.Consequence, depth+1)
+			}
+			m.walk(t.Alternative, depth+1)
+		case *ast.SwitchStatement:
+			m.nest(t, depth)
+			for _, c := range t.Cases {
+				if !c.IsDefault() {
+					m.Cyclomatic++
+				}
+				m.walk(caseBlock(c), depth+1)
+			}
+		}
+	}
+}
+
+func (m *SubroutineMetrics) nest(node ast.Node, depth int) {
+	if depth+1 > m.Nesting {
+		m.Nesting = depth + 1
+		m.deepest = node
+	}
+}
+
+// Count logical operators and if expressions which branch the evaluation
+func decisions(exp ast.Expression) int {
+	switch t := exp.(type) {
+	case *ast.PrefixExpression:
+		return decisions(t.Right)
+	case *ast.GroupedExpression:
+		return decisions(t.Right)
+	case *ast.InfixExpression:
+		n := decisions(t.Left) + decisions(t.Right)
+		if t.Operator == "&&" || t.Operator == "||" {
+			n++
+		}
+		return n
+	case *ast.IfExpression:
+		return 1 + decisions(t.Condition) + decisions(t.Consequence) + decisions(t.Alternative)
+	case *ast.FunctionCallExpression:
+		var n int
+		for i := range t.Arguments {
+			n += decisions(t.Arguments[i])
+		}
+		return n
+	}
+	return 0
+}
+
+// Collect expressions which are directly used in the statement, nested blocks are not included
+func statementExpressions(stmt ast.Statement) []ast.Expression {
+	switch t := stmt.(type) {
+	case *ast.SetStatement:
+		return []ast.Expression{t.Value}
+	case *ast.AddStatement:
+		return []ast.Expression{t.Value}
+	case *ast.IfStatement:
+		exps := []ast.Expression{t.Condition}
+		for _, a := range t.Another {
+			exps = append(exps, a.Condition)
+		}
+		return exps
+	case *ast.SwitchStatement:
+		return []ast.Expression{t.Control}
+	case *ast.LogStatement:
+		return []ast.Expression{t.Value}
+	case *ast.SyntheticStatement:
+		return []ast.Expression{t.Value}
+	case *ast.SyntheticBase64Statement:
+		return []ast.Expression{t.Value}
+	case *ast.ReturnStatement:
+		if t.ReturnExpression != nil {
+			return []ast.Expression{*t.ReturnExpression}
+		}
+	case *ast.FunctionCallStatement:
+		return t.Arguments
+	}
+	return nil
+}
+
+func (l *Linter) lintComplexity(sub *ast.SubroutineDeclaration) {
+	m := Complexity(sub)
+	l.metrics = append(l.metrics, m)
+
+	maxCyclomatic, maxNesting := defaultMaxCyclomatic, defaultMaxNesting
+	if l.complexity != nil {
+		if l.complexity.MaxCyclomatic > 0 {
+			maxCyclomatic = l.complexity.MaxCyclomatic
+		}
+		if l.complexity.MaxNesting > 0 {
+			maxNesting = l.complexity.MaxNesting
+		}
+	}
+
+	if m.Cyclomatic > maxCyclomatic {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    sub.Name.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"Subroutine %s has cyclomatic complexity %d which exceeds %d, consider splitting into smaller subroutines",
+				m.Name, m.Cyclomatic, maxCyclomatic,
+			),
+		}
+		l.Error(err.Match(COMPLEXITY_CYCLOMATIC))
+	}
+	if m.Nesting > maxNesting {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    m.deepest.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"Nesting depth %d in subroutine %s exceeds %d, consider early return or splitting into smaller subroutines",
+				m.Nesting, m.Name, maxNesting,
+			),
+		}
+		l.Error(err.Match(COMPLEXITY_NESTING))
+	}
+}
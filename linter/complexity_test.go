@@ -0,0 +1,119 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestComplexity(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo && req.http.Bar) {
+    if (req.url ~ "^/api/") {
+      set req.http.Api = if(req.http.Baz, "1", "0");
+    } else if (req.url ~ "^/admin/") {
+      {
+        set req.http.Admin = "1";
+      }
+    } else {
+      set req.http.Other = "1";
+    }
+  }
+}
+
+sub simple {
+  set req.http.Foo = "foo";
+}`
+
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		return
+	}
+	l := New()
+	l.Lint(vcl, context.New())
+
+	var actual []SubroutineMetrics
+	for _, m := range l.Metrics() {
+		actual = append(actual, SubroutineMetrics{Name: m.Name, Line: m.Line, Cyclomatic: m.Cyclomatic, Nesting: m.Nesting})
+	}
+	expect := []SubroutineMetrics{
+		// 1 + if + && + if + else if + if expression
+		{Name: "vcl_recv", Line: 2, Cyclomatic: 6, Nesting: 3},
+		{Name: "simple", Line: 17, Cyclomatic: 1, Nesting: 0},
+	}
+	if diff := cmp.Diff(expect, actual, cmp.AllowUnexported(SubroutineMetrics{})); diff != "" {
+		t.Errorf("Metrics mismatch, diff=%s", diff)
+	}
+}
+
+func TestLintComplexity(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.A || req.http.B) {
+    if (req.http.C) {
+      if (req.http.D) {
+        set req.http.E = "1";
+      }
+    }
+  }
+}`
+
+	tests := []struct {
+		name   string
+		config *config.ComplexityConfig
+		expect []int
+		rules  []Rule
+	}{
+		{
+			name: "default thresholds",
+		},
+		{
+			name:   "cyclomatic threshold",
+			config: &config.ComplexityConfig{MaxCyclomatic: 4},
+			expect: []int{2},
+			rules:  []Rule{COMPLEXITY_CYCLOMATIC},
+		},
+		{
+			name:   "nesting threshold",
+			config: &config.ComplexityConfig{MaxNesting: 2},
+			expect: []int{6},
+			rules:  []Rule{COMPLEXITY_NESTING},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New(WithComplexity(tt.config))
+			l.Lint(vcl, context.New())
+
+			var lines []int
+			var rules []Rule
+			for _, d := range l.Diagnostics {
+				if d.Rule != COMPLEXITY_CYCLOMATIC && d.Rule != COMPLEXITY_NESTING {
+					continue
+				}
+				lines = append(lines, d.Token.Line)
+				rules = append(rules, d.Rule)
+			}
+			if diff := cmp.Diff(tt.expect, lines); diff != "" {
+				t.Errorf("Complexity lines unmatch, diff: %s", diff)
+			}
+			if diff := cmp.Diff(tt.rules, rules); diff != "" {
+				t.Errorf("Complexity rules unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
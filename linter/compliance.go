@@ -0,0 +1,198 @@
+package linter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Compliance rules are opt-in, all functions in this file do nothing unless compliance rule pack is enabled.
+
+// Lint log statement does not write sensitive header values like Authorization or Cookie.
+func (l *Linter) lintComplianceLogStatement(stmt *ast.LogStatement) {
+	if !l.compliance.IsEnabled() {
+		return
+	}
+
+	for _, ident := range collectIdents(stmt.Value) {
+		header := httpHeaderName(ident.Value)
+		if header == "" || !l.compliance.IsSensitiveHeader(header) {
+			continue
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    ident.GetMeta().Token,
+			Message:  fmt.Sprintf("Sensitive header value %s is written to the log", ident.Value),
+		}
+		l.Error(err.Match(COMPLIANCE_LOG_SENSITIVE_HEADER))
+	}
+}
+
+// Lint branches for authenticated requests pass the request or set Cache-Control: private.
+func (l *Linter) lintComplianceAuthenticatedPath(cond ast.Expression, consequence *ast.BlockStatement, ctx *context.Context) {
+	if !l.compliance.IsEnabled() {
+		return
+	}
+	if ctx.Mode()&(context.RECV|context.FETCH|context.DELIVER) == 0 {
+		return
+	}
+	if !hasIdent(cond, "req.http.authorization") {
+		return
+	}
+
+	var handled bool
+	walkStatements(consequence, func(stmt ast.Statement) bool {
+		if isReturnState(stmt, "pass") {
+			handled = true
+		} else if s, ok := stmt.(*ast.SetStatement); ok && isPrivateCacheControl(s) {
+			handled = true
+		}
+		return !handled
+	})
+	if handled {
+		return
+	}
+
+	err := &LintError{
+		Severity: WARNING,
+		Token:    cond.GetMeta().Token,
+		Message:  "Authenticated request may be cached, pass the request or set Cache-Control: private",
+	}
+	l.Error(err.Match(COMPLIANCE_AUTHENTICATED_CACHE_CONTROL))
+}
+
+// Lint backend enables TLS and specifies min_tls_version which satisfies the configured floor.
+func (l *Linter) lintComplianceBackendTLS(decl *ast.BackendDeclaration) {
+	if !l.compliance.IsEnabled() {
+		return
+	}
+
+	var ssl bool
+	var minVersion *ast.String
+	for _, prop := range decl.Properties {
+		switch prop.Key.Value {
+		case "ssl":
+			if v, ok := prop.Value.(*ast.Boolean); ok {
+				ssl = v.Value
+			}
+		case "min_tls_version":
+			if v, ok := prop.Value.(*ast.String); ok {
+				minVersion = v
+			}
+		}
+	}
+
+	floor := l.compliance.TLSFloor()
+	var err *LintError
+	switch {
+	case !ssl:
+		err = &LintError{
+			Severity: WARNING,
+			Token:    decl.Name.GetMeta().Token,
+			Message:  fmt.Sprintf("Backend %s does not enable TLS", decl.Name.Value),
+		}
+	case minVersion == nil:
+		err = &LintError{
+			Severity: WARNING,
+			Token:    decl.Name.GetMeta().Token,
+			Message:  fmt.Sprintf("Backend %s does not specify min_tls_version, %s or later is required", decl.Name.Value, floor),
+		}
+	case compareTLSVersion(minVersion.Value, floor) < 0:
+		err = &LintError{
+			Severity: WARNING,
+			Token:    minVersion.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"Backend %s allows TLS version %s, %s or later is required", decl.Name.Value, minVersion.Value, floor,
+			),
+		}
+	default:
+		return
+	}
+	l.Error(err.Match(COMPLIANCE_BACKEND_TLS_VERSION))
+}
+
+// Returns header name when the variable name is HTTP header access like req.http.Cookie:name
+func httpHeaderName(name string) string {
+	idx := strings.Index(strings.ToLower(name), ".http.")
+	if idx == -1 {
+		return ""
+	}
+	header := name[idx+len(".http."):]
+	if i := strings.Index(header, ":"); i != -1 {
+		header = header[:i]
+	}
+	return header
+}
+
+func hasIdent(exp ast.Expression, name string) bool {
+	for _, ident := range collectIdents(exp) {
+		if strings.EqualFold(ident.Value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isReturnState(stmt ast.Statement, state string) bool {
+	r, ok := stmt.(*ast.ReturnStatement)
+	if !ok || r.ReturnExpression == nil {
+		return false
+	}
+	return (*r.ReturnExpression).String() == state
+}
+
+func preventsSetCookieCache(block *ast.BlockStatement) bool {
+	var prevented bool
+	walkStatements(block, func(stmt ast.Statement) bool {
+		switch t := stmt.(type) {
+		case *ast.ReturnStatement:
+			prevented = isReturnState(t, "pass")
+		case *ast.SetStatement:
+			if strings.EqualFold(t.Ident.Value, "beresp.cacheable") {
+				if v, ok := t.Value.(*ast.Boolean); ok && !v.Value {
+					prevented = true
+				}
+			}
+		case *ast.UnsetStatement:
+			prevented = strings.EqualFold(t.Ident.Value, "beresp.http.set-cookie")
+		case *ast.RemoveStatement:
+			prevented = strings.EqualFold(t.Ident.Value, "beresp.http.set-cookie")
+		}
+		return !prevented
+	})
+	return prevented
+}
+
+func isPrivateCacheControl(stmt *ast.SetStatement) bool {
+	if !strings.EqualFold(httpHeaderName(stmt.Ident.Value), "cache-control") {
+		return false
+	}
+	v, ok := stmt.Value.(*ast.String)
+	if !ok {
+		return false
+	}
+	value := strings.ToLower(v.Value)
+	return strings.Contains(value, "private") || strings.Contains(value, "no-store")
+}
+
+// Compare TLS version string like "1.2", returns negative value when a is older than b
+func compareTLSVersion(a, b string) int {
+	as := strings.SplitN(a, ".", 2)
+	bs := strings.SplitN(b, ".", 2)
+	for i := 0; i < 2; i++ {
+		var av, bv int
+		if i < len(as) {
+			av, _ = strconv.Atoi(as[i]) // nolint:errcheck
+		}
+		if i < len(bs) {
+			bv, _ = strconv.Atoi(bs[i]) // nolint:errcheck
+		}
+		if av != bv {
+			return av - bv
+		}
+	}
+	return 0
+}
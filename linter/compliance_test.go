@@ -0,0 +1,188 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func assertComplianceRules(t *testing.T, input string, c *config.ComplianceConfig, expects ...Rule) {
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		t.FailNow()
+	}
+
+	l := New(WithCompliance(c))
+	l.lint(vcl, context.New())
+
+	var rules []Rule
+	for _, d := range l.Diagnostics {
+		rules = append(rules, d.Rule)
+	}
+	if diff := cmp.Diff(expects, rules); diff != "" {
+		t.Errorf("Lint rules unmatch, diff: %s", diff)
+	}
+}
+
+func TestLintCompliance(t *testing.T) {
+	enabled := &config.ComplianceConfig{Enable: true}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		input := `
+backend example {
+  .host = "example.com";
+}
+sub vcl_log {
+  #FASTLY LOG
+  log req.http.Authorization;
+}`
+		assertComplianceRules(t, input, nil)
+	})
+
+	t.Run("log sensitive header", func(t *testing.T) {
+		input := `
+sub vcl_log {
+  #FASTLY LOG
+  log "syslog " req.service_id " logger :: " req.http.Cookie:session;
+  log req.http.User-Agent;
+}`
+		assertComplianceRules(t, input, enabled, COMPLIANCE_LOG_SENSITIVE_HEADER)
+	})
+
+	t.Run("Set-Cookie response may be cached", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  return (deliver);
+}`
+		assertComplianceRules(t, input, enabled, CACHE_SET_COOKIE)
+	})
+
+	t.Run("Set-Cookie response which is cached by TTL is reported once", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.ttl = 1h;
+  return (deliver);
+}`
+		assertComplianceRules(t, input, enabled, CACHE_SET_COOKIE)
+	})
+
+	t.Run("all responses are passed", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  return (pass);
+}`
+		assertComplianceRules(t, input, enabled)
+	})
+
+	t.Run("Set-Cookie response is passed", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.status == 200) {
+    if (beresp.http.Set-Cookie) {
+      set req.http.Fastly-Cachetype = "SETCOOKIE";
+      return (pass);
+    }
+  }
+  return (deliver);
+}`
+		assertComplianceRules(t, input, enabled)
+	})
+
+	t.Run("Set-Cookie header is removed", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.http.Set-Cookie) {
+    unset beresp.http.Set-Cookie;
+  }
+  return (deliver);
+}`
+		assertComplianceRules(t, input, enabled)
+	})
+
+	t.Run("authenticated request is not passed", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Authorization) {
+    set req.http.X-Authenticated = "1";
+  }
+  return (lookup);
+}`
+		assertComplianceRules(t, input, enabled, COMPLIANCE_AUTHENTICATED_CACHE_CONTROL)
+	})
+
+	t.Run("authenticated request is passed", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Authorization) {
+    return (pass);
+  }
+  return (lookup);
+}`
+		assertComplianceRules(t, input, enabled)
+	})
+
+	t.Run("authenticated response has private Cache-Control", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+  #FASTLY DELIVER
+  if (req.http.Authorization) {
+    set resp.http.Cache-Control = "private, no-store";
+  }
+  return (deliver);
+}`
+		assertComplianceRules(t, input, enabled)
+	})
+
+	t.Run("backend TLS version", func(t *testing.T) {
+		input := `
+backend plain {
+  .host = "example.com";
+}
+backend no_version {
+  .host = "example.com";
+  .ssl = true;
+}
+backend old_version {
+  .host = "example.com";
+  .ssl = true;
+  .min_tls_version = "1.0";
+}
+backend modern {
+  .host = "example.com";
+  .ssl = true;
+  .min_tls_version = "1.2";
+}`
+		assertComplianceRules(
+			t, input, enabled,
+			COMPLIANCE_BACKEND_TLS_VERSION,
+			COMPLIANCE_BACKEND_TLS_VERSION,
+			COMPLIANCE_BACKEND_TLS_VERSION,
+		)
+	})
+
+	t.Run("backend TLS floor is configurable", func(t *testing.T) {
+		input := `
+backend example {
+  .host = "example.com";
+  .ssl = true;
+  .min_tls_version = "1.2";
+}`
+		assertComplianceRules(
+			t, input,
+			&config.ComplianceConfig{Enable: true, MinTLSVersion: "1.3"},
+			COMPLIANCE_BACKEND_TLS_VERSION,
+		)
+	})
+}
@@ -0,0 +1,108 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Hostname patterns which accept the wildcard prefix like "*.example.com"
+type hostPatterns []string
+
+func (p hostPatterns) match(host string) bool {
+	host = strings.ToLower(host)
+	for _, h := range p {
+		h = strings.ToLower(h)
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == h {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint subroutine does not assume Compute-backed hostnames are served by VCL services.
+// Compute hosts are hostnames which are served by Compute services in the mixed-platform account.
+// Branches for Compute-backed hosts are never executed, and rewriting Host header to the Compute-backed host
+// and then restart does not route the request to the Compute service.
+func (l *Linter) lintComputeHosts(decl *ast.SubroutineDeclaration) {
+	if len(l.computeHosts) == 0 {
+		return
+	}
+
+	var restart bool
+	var rewrites []*ast.String
+	walkStatements(decl.Block, func(stmt ast.Statement) bool {
+		switch t := stmt.(type) {
+		case *ast.RestartStatement:
+			restart = true
+		case *ast.SetStatement:
+			if v, ok := t.Value.(*ast.String); ok && strings.EqualFold(t.Ident.Value, "req.http.host") && l.computeHosts.match(v.Value) {
+				rewrites = append(rewrites, v)
+			}
+		case *ast.IfStatement:
+			for _, host := range collectIfHostComparisons(t) {
+				if !l.computeHosts.match(host.Value) {
+					continue
+				}
+				err := &LintError{
+					Severity: WARNING,
+					Token:    host.GetMeta().Token,
+					Message:  fmt.Sprintf("Host %s is served by Compute service, requests for the host are never handled by this VCL service", host.Value),
+				}
+				l.Error(err.Match(COMPUTE_HOST_ASSUMPTION))
+			}
+		}
+		return true
+	})
+
+	if !restart {
+		return
+	}
+	for _, v := range rewrites {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    v.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"Host %s is served by Compute service, restart is processed by this VCL service and never reaches the Compute service",
+				v.Value,
+			),
+		}
+		l.Error(err.Match(COMPUTE_HOST_ASSUMPTION))
+	}
+}
+
+// Collect hostname literals which are compared with Host header in all conditions of if statement
+func collectIfHostComparisons(stmt *ast.IfStatement) []*ast.String {
+	hosts := collectHostComparisons(stmt.Condition)
+	for _, a := range stmt.Another {
+		hosts = append(hosts, collectHostComparisons(a.Condition)...)
+	}
+	return hosts
+}
+
+// Collect hostname literals like req.http.host == "www.example.com" in the condition
+func collectHostComparisons(cond ast.Expression) []*ast.String {
+	switch t := cond.(type) {
+	case *ast.GroupedExpression:
+		return collectHostComparisons(t.Right)
+	case *ast.PrefixExpression:
+		return collectHostComparisons(t.Right)
+	case *ast.InfixExpression:
+		if t.Operator != "==" {
+			return append(collectHostComparisons(t.Left), collectHostComparisons(t.Right)...)
+		}
+		ident, ok := t.Left.(*ast.Ident)
+		if !ok || !strings.EqualFold(ident.Value, "req.http.host") {
+			return nil
+		}
+		if v, ok := t.Right.(*ast.String); ok {
+			return []*ast.String{v}
+		}
+	}
+	return nil
+}
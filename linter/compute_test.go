@@ -0,0 +1,74 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintComputeHosts(t *testing.T) {
+	lintWithComputeHosts := func(t *testing.T, input string) *Linter {
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Fatalf("unexpected parser error: %s", err)
+		}
+		l := New(WithComputeHosts([]string{"api.example.com", "*.compute.example.com"}))
+		l.lint(vcl, context.New())
+		return l
+	}
+
+	t.Run("branch for compute host", func(t *testing.T) {
+		l := lintWithComputeHosts(t, `
+sub vcl_recv {
+   #FASTLY RECV
+   if (req.http.Host == "www.example.com" || req.http.Host == "v1.compute.example.com") {
+     set req.http.X-Tier = "edge";
+   }
+}`)
+		if len(l.Diagnostics) != 1 || l.Diagnostics[0].Rule != COMPUTE_HOST_ASSUMPTION {
+			t.Errorf("Expects one %s error but got %v", COMPUTE_HOST_ASSUMPTION, l.Diagnostics)
+		}
+	})
+
+	t.Run("restart with compute host", func(t *testing.T) {
+		l := lintWithComputeHosts(t, `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (resp.status == 404 && req.restarts == 0) {
+     set req.http.Host = "api.example.com";
+     restart;
+   }
+}`)
+		if len(l.Diagnostics) != 1 || l.Diagnostics[0].Rule != COMPUTE_HOST_ASSUMPTION {
+			t.Errorf("Expects one %s error but got %v", COMPUTE_HOST_ASSUMPTION, l.Diagnostics)
+		}
+	})
+
+	t.Run("compute host as backend request host", func(t *testing.T) {
+		l := lintWithComputeHosts(t, `
+sub vcl_miss {
+   #FASTLY MISS
+   set bereq.http.Host = "api.example.com";
+}
+sub vcl_recv {
+   #FASTLY RECV
+   set req.http.Host = "api.example.com";
+}`)
+		if len(l.Diagnostics) > 0 {
+			t.Errorf("Unexpected lint error: %v", l.Diagnostics)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		assertNoError(t, `
+sub vcl_recv {
+   #FASTLY RECV
+   if (req.http.Host == "api.example.com") {
+     set req.http.Host = "api.example.com";
+     restart;
+   }
+}`)
+	})
+}
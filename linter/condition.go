@@ -0,0 +1,311 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// INTEGER variables which never be negative
+var nonNegativeVariables = map[string]struct{}{
+	"req.restarts":  {},
+	"req.esi_level": {},
+	"obj.hits":      {},
+}
+
+// Lint condition which never changes the outcome by folding constant expressions.
+// The outermost constant expression is reported, for example (req.http.Foo || true) is reported as always true.
+func (l *Linter) lintConstantCondition(cond ast.Expression) {
+	// Literal in the first expression is already reported by condition/literal rule
+	if isValidConditionExpression(cond) != nil {
+		return
+	}
+	l.reportConstantCondition(cond)
+}
+
+func (l *Linter) reportConstantCondition(exp ast.Expression) {
+	// Boolean literal like if (false) is often used to switch the block intentionally
+	if isBooleanSwitch(exp) {
+		return
+	}
+	if v, ok := foldCondition(exp); ok {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    exp.GetMeta().Token,
+			Message:  fmt.Sprintf("Condition %s is always %t", exp.String(), v),
+		}
+		l.Error(err.Match(CONDITION_CONSTANT))
+		return
+	}
+
+	switch t := exp.(type) {
+	case *ast.GroupedExpression:
+		l.reportConstantCondition(t.Right)
+	case *ast.PrefixExpression:
+		l.reportConstantCondition(t.Right)
+	case *ast.InfixExpression:
+		if t.Operator == "&&" || t.Operator == "||" {
+			l.reportConstantCondition(t.Left)
+			l.reportConstantCondition(t.Right)
+		}
+	}
+}
+
+func isBooleanSwitch(exp ast.Expression) bool {
+	switch t := exp.(type) {
+	case *ast.Boolean:
+		return true
+	case *ast.GroupedExpression:
+		return isBooleanSwitch(t.Right)
+	case *ast.PrefixExpression:
+		return t.Operator == "!" && isBooleanSwitch(t.Right)
+	}
+	return false
+}
+
+// Fold the condition expression, returns false as second value when the result depends on runtime values
+func foldCondition(exp ast.Expression) (bool, bool) {
+	switch t := exp.(type) {
+	case *ast.Boolean:
+		return t.Value, true
+	case *ast.GroupedExpression:
+		return foldCondition(t.Right)
+	case *ast.PrefixExpression:
+		if t.Operator != "!" {
+			return false, false
+		}
+		v, ok := foldCondition(t.Right)
+		return !v, ok
+	case *ast.InfixExpression:
+		return foldInfixCondition(t)
+	}
+	return false, false
+}
+
+func foldInfixCondition(exp *ast.InfixExpression) (bool, bool) {
+	switch exp.Operator {
+	case "&&", "||":
+		left, lok := foldCondition(exp.Left)
+		right, rok := foldCondition(exp.Right)
+		// Short circuit, one of operands determines the result
+		if exp.Operator == "&&" && ((lok && !left) || (rok && !right)) {
+			return false, true
+		}
+		if exp.Operator == "||" && ((lok && left) || (rok && right)) {
+			return true, true
+		}
+		if lok && rok {
+			return left, true
+		}
+		return false, false
+	case "~", "!~":
+		// Empty pattern matches any string
+		if s, ok := exp.Right.(*ast.String); ok && s.Value == "" {
+			return exp.Operator == "~", true
+		}
+		return false, false
+	}
+
+	if isSameOperand(exp.Left, exp.Right) {
+		switch exp.Operator {
+		case "==", ">=", "<=":
+			return true, true
+		case "!=", ">", "<":
+			return false, true
+		}
+		return false, false
+	}
+
+	if isLiteralExpression(exp.Left) && isLiteralExpression(exp.Right) {
+		return compareLiterals(exp.Left, exp.Operator, exp.Right)
+	}
+
+	// Compare non-negative variable with zero or negative value
+	if ident, ok := exp.Left.(*ast.Ident); ok {
+		if _, ok := nonNegativeVariables[ident.Value]; !ok {
+			return false, false
+		}
+		i, ok := exp.Right.(*ast.Integer)
+		if !ok {
+			return false, false
+		}
+		switch {
+		case exp.Operator == ">=" && i.Value <= 0, exp.Operator == ">" && i.Value < 0:
+			return true, true
+		case exp.Operator == "<" && i.Value <= 0, exp.Operator == "<=" && i.Value < 0:
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// Operands are the same variable or literal, function calls are not the same because they may return different values
+func isSameOperand(left, right ast.Expression) bool {
+	switch l := left.(type) {
+	case *ast.Ident:
+		r, ok := right.(*ast.Ident)
+		return ok && l.Value == r.Value
+	case *ast.GroupedExpression:
+		r, ok := right.(*ast.GroupedExpression)
+		return ok && isSameOperand(l.Right, r.Right)
+	}
+	return isLiteralExpression(left) && isLiteralExpression(right) && left.String() == right.String()
+}
+
+func compareLiterals(left ast.Expression, operator string, right ast.Expression) (bool, bool) {
+	switch l := left.(type) {
+	case *ast.String:
+		r, ok := right.(*ast.String)
+		if !ok {
+			return false, false
+		}
+		switch operator {
+		case "==":
+			return l.Value == r.Value, true
+		case "!=":
+			return l.Value != r.Value, true
+		}
+	case *ast.Integer:
+		r, ok := right.(*ast.Integer)
+		if !ok {
+			return false, false
+		}
+		return compareNumbers(float64(l.Value), operator, float64(r.Value))
+	case *ast.Float:
+		r, ok := right.(*ast.Float)
+		if !ok {
+			return false, false
+		}
+		return compareNumbers(l.Value, operator, r.Value)
+	}
+	return false, false
+}
+
+func compareNumbers(left float64, operator string, right float64) (bool, bool) {
+	switch operator {
+	case "==":
+		return left == right, true
+	case "!=":
+		return left != right, true
+	case ">":
+		return left > right, true
+	case ">=":
+		return left >= right, true
+	case "<":
+		return left < right, true
+	case "<=":
+		return left <= right, true
+	}
+	return false, false
+}
+
+// Lint conditions which are the same as the previous branch in if/else if chain.
+// Later branch is never executed because the former branch always matches first.
+func (l *Linter) lintDuplicateConditions(stmt *ast.IfStatement) {
+	seen := map[string]ast.Expression{}
+	conditions := []ast.Expression{stmt.Condition}
+	for _, a := range stmt.Another {
+		conditions = append(conditions, a.Condition)
+	}
+
+	for _, cond := range conditions {
+		key, ok := canonicalCondition(cond)
+		if !ok {
+			continue
+		}
+		first, found := seen[key]
+		if !found {
+			seen[key] = cond
+			continue
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    cond.GetMeta().Token,
+			Message:  "Condition is the same as the previous branch, this branch is never executed",
+		}
+		err.Relate(first.GetMeta().Token, "Previous condition is here")
+		l.Error(err.Match(CONDITION_DUPLICATED))
+	}
+}
+
+// Operators which result does not change when operands are swapped
+var commutativeOperators = map[string]struct{}{
+	"==": {},
+	"!=": {},
+	"&&": {},
+	"||": {},
+}
+
+// Render the condition in canonical form which ignores whitespaces, comments, parentheses
+// and operand order of commutative operators.
+// Returns false as second value when the condition may have a different result on each evaluation.
+func canonicalCondition(exp ast.Expression) (string, bool) {
+	switch t := exp.(type) {
+	case *ast.GroupedExpression:
+		return canonicalCondition(t.Right)
+	case *ast.PrefixExpression:
+		right, ok := canonicalCondition(t.Right)
+		return t.Operator + right, ok
+	case *ast.InfixExpression:
+		if _, ok := commutativeOperators[t.Operator]; !ok {
+			left, lok := canonicalCondition(t.Left)
+			right, rok := canonicalCondition(t.Right)
+			return "(" + left + " " + t.Operator + " " + right + ")", lok && rok
+		}
+		var operands []string
+		for _, operand := range flattenOperands(t, t.Operator) {
+			v, ok := canonicalCondition(operand)
+			if !ok {
+				return "", false
+			}
+			operands = append(operands, v)
+		}
+		sort.Strings(operands)
+		return "(" + strings.Join(operands, " "+t.Operator+" ") + ")", true
+	case *ast.FunctionCallExpression:
+		name := t.Function.Value
+		if strings.HasPrefix(name, "random") || strings.HasPrefix(name, "uuid.") {
+			return "", false
+		}
+		args := make([]string, len(t.Arguments))
+		for i := range t.Arguments {
+			v, ok := canonicalCondition(t.Arguments[i])
+			if !ok {
+				return "", false
+			}
+			args[i] = v
+		}
+		return name + "(" + strings.Join(args, ", ") + ")", true
+	case *ast.Ident:
+		return t.Value, true
+	case *ast.String:
+		return strconv.Quote(t.Value), true
+	case *ast.IP:
+		return t.Value, true
+	case *ast.Boolean:
+		return strconv.FormatBool(t.Value), true
+	case *ast.Integer:
+		return strconv.FormatInt(t.Value, 10), true
+	case *ast.Float:
+		return strconv.FormatFloat(t.Value, 'f', -1, 64), true
+	case *ast.RTime:
+		return t.Value, true
+	}
+	return "", false
+}
+
+// Collect operands of the chained operator like a && b && c
+func flattenOperands(exp ast.Expression, operator string) []ast.Expression {
+	switch t := exp.(type) {
+	case *ast.GroupedExpression:
+		return flattenOperands(t.Right, operator)
+	case *ast.InfixExpression:
+		if t.Operator == operator {
+			return append(flattenOperands(t.Left, operator), flattenOperands(t.Right, operator)...)
+		}
+	}
+	return []ast.Expression{exp}
+}
@@ -0,0 +1,141 @@
+package linter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintConstantCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		expect    string
+	}{
+		{name: "compare variable to itself", condition: `req.http.Foo == req.http.Foo`, expect: "always true"},
+		{name: "not equal to itself", condition: `req.http.Foo != req.http.Foo`, expect: "always false"},
+		{name: "match against empty pattern", condition: `req.url ~ ""`, expect: "always true"},
+		{name: "not match against empty pattern", condition: `req.url !~ ""`, expect: "always false"},
+		{name: "restarts is never negative", condition: `req.restarts >= 0`, expect: "always true"},
+		{name: "restarts is never less than zero", condition: `req.restarts < 0`, expect: "always false"},
+		{name: "literal comparison in group", condition: `req.http.Foo && (1 > 2)`, expect: "always false"},
+		{name: "or with true", condition: `req.http.Foo || true`, expect: "always true"},
+		{name: "and with false", condition: `req.http.Foo && !true`, expect: "always false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (` + tt.condition + `) {
+    set req.http.Bar = "1";
+  }
+}`
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Fatalf("unexpected parser error: %s", err)
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			if len(l.Diagnostics) != 1 {
+				t.Fatalf("Expect one diagnostic but got %d: %v", len(l.Diagnostics), l.Diagnostics)
+			}
+			d := l.Diagnostics[0]
+			if d.Rule != CONDITION_CONSTANT {
+				t.Errorf("Rule expects %s but got %s", CONDITION_CONSTANT, d.Rule)
+			}
+			if !strings.HasSuffix(d.Message, tt.expect) {
+				t.Errorf("Message should end with %q, got %q", tt.expect, d.Message)
+			}
+		})
+	}
+
+	t.Run("runtime conditions are not reported", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.restarts > 0 && req.http.Foo == req.http.Bar) {
+    set req.http.Baz = "1";
+  } else if (randombool(1, 2) == randombool(1, 2) || req.url ~ "^/foo") {
+    set req.http.Baz = "2";
+  } else if (false) {
+    set req.http.Baz = "3";
+  }
+}`
+		assertNoError(t, input)
+	})
+}
+
+func TestLintDuplicateConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  string
+		second string
+	}{
+		{name: "identical condition", first: `req.url ~ "^/foo"`, second: `req.url ~ "^/foo"`},
+		{name: "whitespace and parentheses", first: `req.http.A == "1" && req.http.B`, second: `(req.http.A=="1") && (req.http.B)`},
+		{name: "swapped operands of equality", first: `req.http.A == req.http.B`, second: `req.http.B == req.http.A`},
+		{name: "reordered chain of and", first: `req.http.A && req.http.B && req.http.C`, second: `req.http.C && (req.http.A && req.http.B)`},
+		{name: "long string literal", first: `req.http.A == "foo"`, second: `req.http.A == {"foo"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (` + tt.first + `) {
+    set req.http.X = "1";
+  } else if (req.http.Other) {
+    set req.http.X = "2";
+  } else if (` + tt.second + `) {
+    set req.http.X = "3";
+  }
+}`
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Fatalf("unexpected parser error: %s", err)
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			if len(l.Diagnostics) != 1 {
+				t.Fatalf("Expect one diagnostic but got %d: %v", len(l.Diagnostics), l.Diagnostics)
+			}
+			d := l.Diagnostics[0]
+			if d.Rule != CONDITION_DUPLICATED {
+				t.Errorf("Rule expects %s but got %s", CONDITION_DUPLICATED, d.Rule)
+			}
+			if d.Token.Line != 8 {
+				t.Errorf("Diagnostic should be reported at line 8, got %d", d.Token.Line)
+			}
+			if len(d.Related) != 1 || d.Related[0].Token.Line != 4 {
+				t.Errorf("Previous condition at line 4 should be related, got %v", d.Related)
+			}
+		})
+	}
+
+	t.Run("different conditions are not reported", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.restarts > client.requests) {
+    set req.http.X = "1";
+  } else if (client.requests > req.restarts) {
+    set req.http.X = "2";
+  } else if (req.http.A && (req.http.B || req.http.C)) {
+    set req.http.X = "3";
+  } else if ((req.http.A && req.http.B) || req.http.C) {
+    set req.http.X = "4";
+  } else if (randombool(1, 2)) {
+    set req.http.X = "5";
+  } else if (randombool(1, 2)) {
+    set req.http.X = "6";
+  }
+}`
+		assertNoError(t, input)
+	})
+}
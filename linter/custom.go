@@ -0,0 +1,77 @@
+package linter
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// CustomRule is the lint rule which is implemented outside of linter package,
+// like organization-specific naming conventions or mandatory headers.
+// Note that Rule type is the rule ID so the interface is named as CustomRule.
+type CustomRule interface {
+	// Name returns the rule ID like "org/mandatory-header", which is used to override the severity
+	Name() Rule
+	// Check is called for every node with the context at the node.
+	// Errors which do not have the rule are reported as the rule of Name.
+	Check(node ast.Node, ctx *context.Context) []*LintError
+}
+
+var (
+	customRulesMu sync.RWMutex
+	customRules   []CustomRule
+)
+
+// RegisterRule registers the custom rule which is applied to all linters.
+// It is typically called in init() of the package which is compiled in,
+// and panics when the rule which has the same name is already registered.
+func RegisterRule(rule CustomRule) {
+	customRulesMu.Lock()
+	defer customRulesMu.Unlock()
+
+	for _, r := range customRules {
+		if r.Name() == rule.Name() {
+			panic(fmt.Sprintf("Custom rule %s is already registered", rule.Name()))
+		}
+	}
+	customRules = append(customRules, rule)
+}
+
+// RegisteredRules returns custom rules which are registered by RegisterRule
+func RegisteredRules() []CustomRule {
+	customRulesMu.RLock()
+	defer customRulesMu.RUnlock()
+
+	return append([]CustomRule{}, customRules...)
+}
+
+func (l *Linter) lintCustomRules(node ast.Node, ctx *context.Context) {
+	if len(l.customRules) == 0 {
+		return
+	}
+	l.profiler.checks++
+	if l.profiler.checks%ruleReorderInterval == 0 {
+		l.reorderCustomRules()
+	}
+
+	for _, rule := range l.customRules {
+		if l.stopped {
+			return
+		}
+		start := time.Now()
+		errs := rule.Check(node, ctx)
+		l.profiler.record(rule.Name(), start)
+		for _, err := range errs {
+			if err == nil {
+				continue
+			}
+			if err.Rule == "" {
+				err.Match(rule.Name())
+			}
+			l.Error(err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+// Organization rule which requires X-Request-ID header to be set in vcl_recv
+type mandatoryHeaderRule struct{}
+
+func (r *mandatoryHeaderRule) Name() Rule {
+	return "org/mandatory-header"
+}
+
+func (r *mandatoryHeaderRule) Check(node ast.Node, ctx *context.Context) []*LintError {
+	sub, ok := node.(*ast.SubroutineDeclaration)
+	if !ok || sub.Name.Value != "vcl_recv" {
+		return nil
+	}
+	for _, stmt := range sub.Block.Statements {
+		if s, ok := stmt.(*ast.SetStatement); ok && s.Ident.Value == "req.http.X-Request-ID" {
+			return nil
+		}
+	}
+	return []*LintError{
+		{
+			Severity: WARNING,
+			Token:    sub.GetMeta().Token,
+			Message:  "vcl_recv must set req.http.X-Request-ID",
+		},
+	}
+}
+
+// Rule which reports the statement in the scope
+type scopeRule struct{}
+
+func (r *scopeRule) Name() Rule {
+	return "org/no-esi"
+}
+
+func (r *scopeRule) Check(node ast.Node, ctx *context.Context) []*LintError {
+	if _, ok := node.(*ast.EsiStatement); !ok || ctx.Mode() != context.FETCH {
+		return nil
+	}
+	return []*LintError{
+		(&LintError{
+			Severity: ERROR,
+			Token:    node.GetMeta().Token,
+			Message:  "ESI is not allowed",
+		}).Match("org/esi"),
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = "bar";
+}
+
+sub vcl_fetch {
+  #FASTLY FETCH
+  esi;
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		return
+	}
+
+	tests := []struct {
+		name    string
+		options []Option
+		expect  map[Rule]Severity
+	}{
+		{
+			name:    "report custom rules",
+			options: []Option{WithCustomRules(&mandatoryHeaderRule{}, &scopeRule{})},
+			expect: map[Rule]Severity{
+				"org/mandatory-header": WARNING,
+				"org/esi":              ERROR,
+			},
+		},
+		{
+			name: "override severity of custom rule",
+			options: []Option{
+				WithCustomRules(&mandatoryHeaderRule{}, &scopeRule{}),
+				WithSeverities(map[Rule]Severity{"org/mandatory-header": ERROR, "org/esi": IGNORE}),
+			},
+			expect: map[Rule]Severity{
+				"org/mandatory-header": ERROR,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.options...)
+			l.Lint(vcl, context.New())
+			actual := make(map[Rule]Severity)
+			for _, d := range l.Diagnostics {
+				actual[d.Rule] = d.Severity
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Diagnostics mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	defer func() {
+		customRules = nil
+	}()
+
+	RegisterRule(&mandatoryHeaderRule{})
+	if l := New(); len(l.customRules) != 1 {
+		t.Errorf("Registered rule must be applied to the linter")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Registering duplicated rule must panic")
+		}
+	}()
+	RegisterRule(&mandatoryHeaderRule{})
+}
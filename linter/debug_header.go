@@ -0,0 +1,107 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Variables which expose internal information like backend names or cache keys
+var internalVariablePrefixes = []string{
+	"req.backend",
+	"bereq.backend",
+	"beresp.backend.",
+	"req.hash",
+	"req.digest",
+}
+
+// Lint debug header leakage for client response.
+// Debug headers and internal values are allowed to be set only inside conditions
+// which are gated by allowlisted debug request header or ACL.
+func (l *Linter) lintDebugHeaderLeak(ident *ast.Ident, value ast.Expression) {
+	name := strings.ToLower(ident.Value)
+	if !strings.HasPrefix(name, "resp.http.") {
+		return
+	}
+	header := ident.Value[len("resp.http."):]
+
+	var reason string
+	if l.debugHeader.IsDebugHeader(header) {
+		reason = fmt.Sprintf("Debug header %s is set on client response", header)
+	} else if v := findInternalVariable(value); v != "" {
+		reason = fmt.Sprintf("Internal value %s is exposed via %s on client response", v, header)
+	} else {
+		return
+	}
+
+	if l.isDebugGated() {
+		return
+	}
+
+	err := &LintError{
+		Severity: WARNING,
+		Token:    ident.GetMeta().Token,
+		Message:  reason + " without being gated by allowlisted debug header or ACL",
+	}
+	l.Error(err.Match(DEBUG_HEADER_LEAK))
+}
+
+func findInternalVariable(exp ast.Expression) string {
+	for _, ident := range collectIdents(exp) {
+		for _, prefix := range internalVariablePrefixes {
+			if strings.HasPrefix(ident.Value, prefix) {
+				return ident.Value
+			}
+		}
+	}
+	return ""
+}
+
+// Check any of enclosing conditions is gated by allowlisted debug header or ACL
+func (l *Linter) isDebugGated() bool {
+	for _, cond := range l.conditions {
+		if l.isGatingCondition(cond) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check the condition is satisfied only when allowlisted debug header or ACL matches.
+// Negated expressions like "!req.http.Fastly-Debug" or "req.http.Fastly-Debug != "1""
+// are satisfied when the debug header is absent so they do not gate,
+// and "||" gates only when both operands gate because either one could satisfy the condition.
+func (l *Linter) isGatingCondition(exp ast.Expression) bool {
+	switch t := exp.(type) {
+	case *ast.PrefixExpression:
+		if t.Operator == "!" {
+			return false
+		}
+		return l.isGatingCondition(t.Right)
+	case *ast.GroupedExpression:
+		return l.isGatingCondition(t.Right)
+	case *ast.InfixExpression:
+		switch t.Operator {
+		case "!=", "!~":
+			return false
+		case "||":
+			return l.isGatingCondition(t.Left) && l.isGatingCondition(t.Right)
+		}
+		return l.isGatingCondition(t.Left) || l.isGatingCondition(t.Right)
+	default:
+		for _, ident := range collectIdents(exp) {
+			if l.isGatingIdent(ident) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (l *Linter) isGatingIdent(ident *ast.Ident) bool {
+	if strings.HasPrefix(strings.ToLower(ident.Value), "req.http.") {
+		return l.debugHeader.IsAllowedHeader(ident.Value[len("req.http."):])
+	}
+	return l.debugHeader.IsAllowedAcl(ident.Value)
+}
@@ -0,0 +1,172 @@
+package linter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintDebugHeaderLeak(t *testing.T) {
+	t.Run("debug header without gate", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   set resp.http.X-Debug-Foo = "bar";
+}`
+		assertErrorWithSeverity(t, input, WARNING)
+	})
+
+	t.Run("internal value without gate", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   set resp.http.X-Origin = req.digest;
+}`
+		assertErrorWithSeverity(t, input, WARNING)
+	})
+
+	t.Run("gated by debug header", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.http.Fastly-Debug) {
+     set resp.http.X-Debug-Foo = "bar";
+     set resp.http.X-Cache-Key = req.digest;
+   }
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("gated by both operands of or condition", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.http.Fastly-Debug == "1" || (req.url ~ "^/debug" && req.http.Fastly-Debug)) {
+     set resp.http.X-Debug-Foo = "bar";
+   }
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("gated by allowlisted ACL", func(t *testing.T) {
+		input := `
+acl internal {
+  "192.168.0.1";
+}
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (client.ip ~ internal) {
+     set resp.http.X-Debug-Foo = "bar";
+   }
+}`
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("unexpected parser error: %s", err)
+			return
+		}
+		l := New(WithDebugHeader(&config.DebugHeaderConfig{
+			AllowedAcls: []string{"internal"},
+		}))
+		l.lint(vcl, context.New())
+		if len(l.Errors) > 0 {
+			t.Errorf("Lint error: %s", l.Errors)
+		}
+	})
+
+	t.Run("custom pattern", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   set resp.http.X-Internal-Foo = "bar";
+}`
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("unexpected parser error: %s", err)
+			return
+		}
+		l := New(WithDebugHeader(&config.DebugHeaderConfig{
+			Patterns: []string{"X-Internal-*"},
+		}))
+		l.lint(vcl, context.New())
+		if len(l.Diagnostics) != 1 {
+			t.Errorf("Expect one lint error but got %d", len(l.Diagnostics))
+			return
+		}
+		if l.Diagnostics[0].Rule != DEBUG_HEADER_LEAK {
+			t.Errorf("Unexpected lint error: %s", l.Diagnostics[0])
+		}
+	})
+
+	t.Run("branches which are not gated by the condition", func(t *testing.T) {
+		tests := map[string]string{
+			"else branch": `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.http.Fastly-Debug) {
+     set resp.http.X-Debug-Bar = "1";
+   } else {
+     set resp.http.X-Debug-Foo = "1";
+   }
+}`,
+			"else if branch": `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.http.Fastly-Debug) {
+     set resp.http.X-Debug-Bar = "1";
+   } else if (req.url ~ "^/api") {
+     set resp.http.X-Debug-Foo = "1";
+   }
+}`,
+			"negated condition": `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (!req.http.Fastly-Debug) {
+     set resp.http.X-Debug-Foo = "1";
+   }
+}`,
+			"not equal condition": `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.http.Fastly-Debug != "1") {
+     set resp.http.X-Debug-Foo = "1";
+   }
+}`,
+			"not match condition": `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.url ~ "^/api" && req.http.Fastly-Debug !~ "^1$") {
+     set resp.http.X-Debug-Foo = "1";
+   }
+}`,
+			"or condition with ungated operand": `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   if (req.http.Fastly-Debug || req.url ~ "^/") {
+     set resp.http.X-Debug-Foo = "1";
+   }
+}`,
+		}
+		for name, input := range tests {
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Errorf("%s: unexpected parser error: %s", name, err)
+				continue
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			var leaks []string
+			for _, d := range l.Diagnostics {
+				if d.Rule == DEBUG_HEADER_LEAK {
+					leaks = append(leaks, d.Message)
+				}
+			}
+			if len(leaks) != 1 || !strings.Contains(leaks[0], "X-Debug-Foo") {
+				t.Errorf("%s: expect X-Debug-Foo leak to be reported, got=%v", name, leaks)
+			}
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+func DeprecatedVariable(m *ast.Meta, name, replacement string) *LintError {
+	message := fmt.Sprintf("Variable %s is deprecated", name)
+	if replacement != "" {
+		message = fmt.Sprintf("Variable %s is deprecated, use %s instead", name, replacement)
+	}
+	return &LintError{
+		Severity: WARNING,
+		Token:    m.Token,
+		Message:  message,
+	}
+}
+
+func DeprecatedFunction(m *ast.Meta, name, replacement string) *LintError {
+	message := fmt.Sprintf("Function %s is deprecated", name)
+	if replacement != "" {
+		message = fmt.Sprintf("Function %s is deprecated, use %s instead", name, replacement)
+	}
+	return &LintError{
+		Severity: WARNING,
+		Token:    m.Token,
+		Message:  message,
+	}
+}
+
+// Report the variable which is marked as deprecated in the predefined definitions,
+// the replacement is suggested as the fix because it is compatible with the deprecated one.
+func (l *Linter) lintDeprecatedVariable(ident *ast.Ident, ctx *context.Context) {
+	accessor, ok := ctx.Deprecated(ident.Value)
+	if !ok {
+		return
+	}
+	err := DeprecatedVariable(ident.GetMeta(), ident.Value, accessor.Replacement)
+	if accessor.Replacement != "" {
+		err.Fix = replaceIdentFix(ident, accessor.Replacement)
+	}
+	l.Error(err.Match(DEPRECATED_VARIABLE).Ref(accessor.Reference))
+}
+
+func (l *Linter) lintDeprecatedFunction(ident *ast.Ident, fn *context.BuiltinFunction) {
+	if !fn.Deprecated {
+		return
+	}
+	err := DeprecatedFunction(ident.GetMeta(), ident.Value, fn.Replacement)
+	if fn.Replacement != "" {
+		err.Fix = replaceIdentFix(ident, fn.Replacement)
+	}
+	l.Error(err.Match(DEPRECATED_FUNCTION).Ref(fn.Reference))
+}
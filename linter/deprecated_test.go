@@ -0,0 +1,124 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintDeprecated(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{
+			name: "deprecated variable in expression",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.X-Country = geoip.country_code;
+}`,
+			expect: []string{"Variable geoip.country_code is deprecated, use client.geo.country_code instead"},
+		},
+		{
+			name: "deprecated variable in set statement",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set geoip.ip_override = req.http.X-Forwarded-For;
+}`,
+			expect: []string{"Variable geoip.ip_override is deprecated, use client.geo.ip_override instead"},
+		},
+		{
+			name: "deprecated variable without replacement",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set geoip.use_x_forwarded_for = true;
+}`,
+			expect: []string{"Variable geoip.use_x_forwarded_for is deprecated"},
+		},
+		{
+			name: "deprecated function",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.url = boltsort.sort(req.url);
+}`,
+			expect: []string{"Function boltsort.sort is deprecated, use querystring.sort instead"},
+		},
+		{
+			name: "replacements are not reported",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.X-Country = client.geo.country_code;
+  set req.url = querystring.sort(req.url);
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.Lint(vcl, context.New())
+
+			var messages []string
+			for _, d := range l.Diagnostics {
+				if d.Rule != DEPRECATED_VARIABLE && d.Rule != DEPRECATED_FUNCTION {
+					continue
+				}
+				if d.Reference == "" {
+					t.Errorf("Reference of the deprecated definition should be set")
+				}
+				messages = append(messages, d.Message)
+			}
+			if diff := cmp.Diff(tt.expect, messages); diff != "" {
+				t.Errorf("Deprecation messages unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDeprecatedFix(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.X-City = geoip.city;
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		return
+	}
+	l := New()
+	l.Lint(vcl, context.New())
+
+	for _, d := range l.Diagnostics {
+		if d.Rule != DEPRECATED_VARIABLE {
+			continue
+		}
+		if d.Fix == nil || len(d.Fix.Edits) != 1 {
+			t.Errorf("Replacement should be suggested as the fix")
+			return
+		}
+		expect := &TextEdit{
+			Range:   Range{Start: Position{Line: 4, Column: 25}, End: Position{Line: 4, Column: 35}},
+			NewText: "client.geo.city",
+		}
+		if diff := cmp.Diff(expect, d.Fix.Edits[0]); diff != "" {
+			t.Errorf("Fix unmatch, diff: %s", diff)
+		}
+		return
+	}
+	t.Errorf("Deprecated variable should be reported")
+}
@@ -0,0 +1,181 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ysugimoto/falco/docs"
+)
+
+// Base URL of rule documents, the anchor is the rule name without slashes like GitHub generates
+const ruleDocumentURL = "https://github.com/ysugimoto/falco/blob/main/docs/rules.md#"
+
+var (
+	ruleHeading      = regexp.MustCompile(`^## ([a-z0-9\-]+(?:/[a-z0-9\-]+)?)\s*$`)
+	fastlyDocument   = regexp.MustCompile(`(?i)^Fastly Document\s*:\s*(\S+)\s*$`)
+	ruleDocuments    map[Rule]*RuleDocument
+	ruleDocumentOnce sync.Once
+)
+
+// RuleExample is a code example of the rule, Title is the text before the code like "Problem" or "Fix"
+type RuleExample struct {
+	Title string `json:"title"`
+	Code  string `json:"code"`
+}
+
+// RuleDocument is structured documentation of the rule which is parsed from docs/rules.md
+type RuleDocument struct {
+	Rule     Rule     `json:"rule"`
+	Category Category `json:"category"`
+	// First paragraph of the document
+	Summary string `json:"summary"`
+	// Rest of paragraphs which explain why the rule exists and how to fix it
+	Rationale  string         `json:"rationale"`
+	Examples   []*RuleExample `json:"examples"`
+	References []string       `json:"references"`
+}
+
+// Describe returns documentation of the rule
+func Describe(rule Rule) (*RuleDocument, error) {
+	ruleDocumentOnce.Do(func() {
+		ruleDocuments = parseRuleDocuments(docs.Rules)
+	})
+	doc, ok := ruleDocuments[rule]
+	if !ok {
+		return nil, fmt.Errorf("Rule %s is not found", rule)
+	}
+	return doc, nil
+}
+
+// DescribedRules returns sorted rules which have documentation
+func DescribedRules() []Rule {
+	ruleDocumentOnce.Do(func() {
+		ruleDocuments = parseRuleDocuments(docs.Rules)
+	})
+	rules := make([]Rule, 0, len(ruleDocuments))
+	for rule := range ruleDocuments {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i] < rules[j]
+	})
+	return rules
+}
+
+// Split markdown into rule sections by "## rule-name" headings
+func parseRuleDocuments(markdown string) map[Rule]*RuleDocument {
+	documents := make(map[Rule]*RuleDocument)
+
+	var rule Rule
+	var lines []string
+	flush := func() {
+		if rule != "" {
+			documents[rule] = parseRuleDocument(rule, lines)
+		}
+		rule, lines = "", nil
+	}
+	var inCode bool
+	for _, line := range strings.Split(markdown, "\n") {
+		if isCodeFence(line) {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "## ") {
+			flush()
+			if m := ruleHeading.FindStringSubmatch(line); m != nil {
+				rule = Rule(m[1])
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return documents
+}
+
+// Parse rule section into paragraphs and code blocks.
+// The line which ends with colon just before the code block like "Problem:" is the title of the example,
+// code block without title continues the previous example, or explains the paragraph when no examples appear yet.
+func parseRuleDocument(rule Rule, lines []string) *RuleDocument {
+	doc := &RuleDocument{
+		Rule:     rule,
+		Category: rule.Category(),
+	}
+	var references []string
+	if ref := rule.Reference(); ref != "" {
+		references = append(references, ref)
+	}
+
+	var paragraphs []string
+	var paragraph, code []string
+	var inCode bool
+	endParagraph := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraph, "\n"))
+			paragraph = nil
+		}
+	}
+	for _, line := range lines {
+		switch {
+		case isCodeFence(line) && !inCode:
+			inCode = true
+			code = nil
+		case isCodeFence(line):
+			inCode = false
+			if n := len(paragraph); n > 0 && strings.HasSuffix(strings.TrimSpace(paragraph[n-1]), ":") {
+				title := strings.TrimSuffix(strings.TrimSpace(paragraph[n-1]), ":")
+				paragraph = paragraph[:n-1]
+				endParagraph()
+				doc.Examples = append(doc.Examples, &RuleExample{
+					Title: title,
+					Code:  strings.Join(code, "\n"),
+				})
+				continue
+			}
+			endParagraph()
+			if n := len(doc.Examples); n > 0 {
+				doc.Examples[n-1].Code += "\n\n" + strings.Join(code, "\n")
+				continue
+			}
+			for i := range code {
+				code[i] = "    " + code[i]
+			}
+			paragraphs = append(paragraphs, strings.Join(code, "\n"))
+		case inCode:
+			code = append(code, line)
+		case strings.TrimSpace(line) == "":
+			endParagraph()
+		default:
+			if m := fastlyDocument.FindStringSubmatch(line); m != nil {
+				references = append(references, m[1])
+				continue
+			}
+			paragraph = append(paragraph, line)
+		}
+	}
+	endParagraph()
+
+	if len(paragraphs) > 0 {
+		doc.Summary = paragraphs[0]
+		doc.Rationale = strings.Join(paragraphs[1:], "\n\n")
+	}
+	references = append(references, ruleDocumentURL+strings.ReplaceAll(string(rule), "/", ""))
+
+	// Remove duplicated references which are linked from both of rule definition and document
+	seen := make(map[string]struct{})
+	for _, ref := range references {
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		doc.References = append(doc.References, ref)
+	}
+	return doc
+}
+
+// Code fence could be indented like list items
+func isCodeFence(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), "```")
+}
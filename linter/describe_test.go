@@ -0,0 +1,155 @@
+package linter
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Collect rule constants which are declared in rules.go
+func declaredRules(t *testing.T) []Rule {
+	f, err := parser.ParseFile(token.NewFileSet(), "rules.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse rules.go: %s", err)
+	}
+	var rules []Rule
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for _, v := range vs.Values {
+				lit, ok := v.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				value, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("Failed to unquote %s: %s", lit.Value, err)
+				}
+				rules = append(rules, Rule(value))
+			}
+		}
+	}
+	return rules
+}
+
+func TestDescribeAllRules(t *testing.T) {
+	rules := declaredRules(t)
+	if len(rules) == 0 {
+		t.Fatalf("No rules are found in rules.go")
+	}
+	for _, rule := range rules {
+		doc, err := Describe(rule)
+		if err != nil {
+			t.Errorf("Rule %s does not have documentation", rule)
+			continue
+		}
+		if doc.Summary == "" {
+			t.Errorf("Rule %s does not have summary", rule)
+		}
+		if len(doc.References) == 0 {
+			t.Errorf("Rule %s does not have references", rule)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	doc, err := Describe(DISALLOW_EMPTY_RETURN)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if doc.Category != STYLE {
+		t.Errorf("Category expects %s but got %s", STYLE, doc.Category)
+	}
+	if len(doc.Examples) != 2 || doc.Examples[0].Title != "Problem" || doc.Examples[1].Title != "Fix" {
+		t.Errorf("Examples must have Problem and Fix, got %v", doc.Examples)
+	}
+	expects := []string{
+		"https://developer.fastly.com/reference/vcl/subroutines#returning-a-state",
+		ruleDocumentURL + "disallow-empty-return",
+	}
+	if len(doc.References) != len(expects) {
+		t.Errorf("References expects %v but got %v", expects, doc.References)
+		return
+	}
+	for i := range expects {
+		if doc.References[i] != expects[i] {
+			t.Errorf("References[%d] expects %s but got %s", i, expects[i], doc.References[i])
+		}
+	}
+
+	if _, err := Describe(Rule("unknown/rule")); err == nil {
+		t.Errorf("Expected error for unknown rule but got nil")
+	}
+}
+
+func TestParseRuleDocument(t *testing.T) {
+	markdown := strings.Join([]string{
+		"## example/rule",
+		"",
+		"Summary of the rule.",
+		"Configured via the field:",
+		"",
+		"```yaml",
+		"foo: bar",
+		"```",
+		"",
+		"Rest of the explanation.",
+		"",
+		"Problem:",
+		"```yaml",
+		"policy: true",
+		"```",
+		"",
+		"```vcl",
+		"sub vcl_recv {}",
+		"```",
+		"",
+		"Fix:",
+		"  ```vcl",
+		"sub vcl_recv {",
+		"  #FASTLY RECV",
+		"}",
+		"  ```",
+		"",
+		"Fastly Document: https://developer.fastly.com/reference/vcl/",
+		"",
+		"## other/rule",
+		"",
+		"Other summary.",
+	}, "\n")
+
+	docs := parseRuleDocuments(markdown)
+	if len(docs) != 2 {
+		t.Fatalf("Expected 2 documents but got %d", len(docs))
+	}
+	expect := &RuleDocument{
+		Rule:      Rule("example/rule"),
+		Category:  CORRECTNESS,
+		Summary:   "Summary of the rule.\nConfigured via the field:",
+		Rationale: "    foo: bar\n\nRest of the explanation.",
+		Examples: []*RuleExample{
+			{Title: "Problem", Code: "policy: true\n\nsub vcl_recv {}"},
+			{Title: "Fix", Code: "sub vcl_recv {\n  #FASTLY RECV\n}"},
+		},
+		References: []string{
+			"https://developer.fastly.com/reference/vcl/",
+			ruleDocumentURL + "examplerule",
+		},
+	}
+	if diff := cmp.Diff(expect, docs[Rule("example/rule")]); diff != "" {
+		t.Errorf("Parsed document mismatch, diff=%s", diff)
+	}
+	if docs[Rule("other/rule")].Summary != "Other summary." {
+		t.Errorf("Summary of the next rule expects %q but got %q", "Other summary.", docs[Rule("other/rule")].Summary)
+	}
+}
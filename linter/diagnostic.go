@@ -0,0 +1,109 @@
+package linter
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/ysugimoto/falco/token"
+)
+
+// Position is a location in the source file, Line and Column start from 1
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Range is a span of the source file, End points to the next position of the last character
+type Range struct {
+	File  string
+	Start Position
+	End   Position
+}
+
+// RelatedInformation represents secondary location of the diagnostic
+// like the first declaration of duplicated one
+type RelatedInformation struct {
+	Token   token.Token
+	Message string
+}
+
+func (r *RelatedInformation) Range() Range {
+	return tokenRange(r.Token)
+}
+
+// TextEdit replaces the text in the range with NewText
+type TextEdit struct {
+	Range   Range
+	NewText string
+}
+
+// Fix is suggested edits to resolve the diagnostic, applied by --fix option of lint command
+type Fix struct {
+	Message string
+	Edits   []*TextEdit
+}
+
+// Diagnostic is a single lint result reported by linter
+type Diagnostic struct {
+	Severity  Severity
+	Token     token.Token
+	Message   string
+	Reference string
+	Rule      Rule
+	Related   []*RelatedInformation `json:",omitempty"`
+	Fix       *Fix                  `json:",omitempty"`
+}
+
+func (e *Diagnostic) Match(r Rule) *Diagnostic {
+	e.Rule = r
+	e.Reference = r.Reference()
+	return e
+}
+
+func (e *Diagnostic) Ref(url string) *Diagnostic {
+	e.Reference = url
+	return e
+}
+
+// Relate attaches secondary location to the diagnostic
+func (e *Diagnostic) Relate(t token.Token, message string) *Diagnostic {
+	e.Related = append(e.Related, &RelatedInformation{
+		Token:   t,
+		Message: message,
+	})
+	return e
+}
+
+// Range returns the span of the token which the diagnostic is reported at
+func (e *Diagnostic) Range() Range {
+	return tokenRange(e.Token)
+}
+
+func (e *Diagnostic) Error() string {
+	var rule, ref, file string
+
+	if e.Rule != "" {
+		rule = fmt.Sprintf(" (%s)", e.Rule)
+	}
+	if e.Reference != "" {
+		ref = "\nSee reference documentation: " + e.Reference
+	}
+	if e.Token.File != "" {
+		file = " in" + e.Token.File
+	}
+
+	msg := fmt.Sprintf(
+		"[%s] %s%s%s at line: %d, position: %d%s",
+		e.Severity, e.Message, rule, file, e.Token.Line, e.Token.Position, ref,
+	)
+	return msg
+}
+
+func tokenRange(t token.Token) Range {
+	return Range{
+		File:  t.File,
+		Start: Position{Line: t.Line, Column: t.Position},
+		// Offset is the length of quotes for string token
+		End: Position{Line: t.Line, Column: t.Position + utf8.RuneCountInString(t.Literal) + t.Offset},
+	}
+}
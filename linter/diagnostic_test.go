@@ -0,0 +1,72 @@
+package linter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/token"
+)
+
+func TestDiagnostics(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = 1;
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	l := New()
+	l.lint(vcl, context.New())
+	l.Error(errors.New("plain error"))
+
+	if len(l.Diagnostics) != 2 {
+		t.Errorf("Expect two diagnostics but got %d", len(l.Diagnostics))
+		return
+	}
+	// Deprecated Errors field keeps the same values
+	for i := range l.Diagnostics {
+		if le, ok := l.Errors[i].(*LintError); !ok || le != l.Diagnostics[i] {
+			t.Errorf("Errors field should hold the same diagnostic at %d", i)
+		}
+	}
+	if l.Diagnostics[1].Severity != ERROR || l.Diagnostics[1].Message != "plain error" {
+		t.Errorf("Plain error should be converted to diagnostic, got %v", l.Diagnostics[1])
+	}
+}
+
+func TestDiagnosticRange(t *testing.T) {
+	tests := []struct {
+		token  token.Token
+		expect Range
+	}{
+		{
+			token: token.Token{Type: token.IDENT, Literal: "req.http.Foo", Line: 3, Position: 7, File: "main.vcl"},
+			expect: Range{
+				File:  "main.vcl",
+				Start: Position{Line: 3, Column: 7},
+				End:   Position{Line: 3, Column: 19},
+			},
+		},
+		{
+			token: token.Token{Type: token.STRING, Literal: "foo", Line: 1, Position: 1, Offset: 2},
+			expect: Range{
+				Start: Position{Line: 1, Column: 1},
+				End:   Position{Line: 1, Column: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		d := &Diagnostic{Severity: ERROR, Token: tt.token}
+		if diff := cmp.Diff(tt.expect, d.Range()); diff != "" {
+			t.Errorf("Range mismatch, diff=%s", diff)
+		}
+	}
+}
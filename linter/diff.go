@@ -0,0 +1,202 @@
+package linter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ChangedLines holds line numbers of each file which are added or modified in the unified diff.
+// File paths are absolute so that they could be compared with files of diagnostics.
+type ChangedLines map[string]map[int]struct{}
+
+// ParseDiff reads unified diff like the output of "git diff" and collects added lines of new files, context lines are not changed.
+// File paths in the diff are resolved from the root directory, "a/" and "b/" prefixes of git are stripped.
+// Removed lines do not exist in the new file, so lines around them are treated as changed.
+func ParseDiff(r io.Reader, root string) (ChangedLines, error) {
+	changes := ChangedLines{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var file string
+	// Line number of the new file and remaining line counts of old and new file in the current hunk
+	var line, oldRemains, newRemains int
+	mark := func(lines ...int) {
+		if file == "" {
+			return
+		}
+		for _, l := range lines {
+			changes[file][l] = struct{}{}
+		}
+	}
+	// Lines are removed just before the current line, and not replaced by added lines
+	var removed bool
+	flush := func() {
+		// Removed lines do not exist in the new file, treat lines around them as changed
+		if removed {
+			mark(line-1, line)
+			removed = false
+		}
+	}
+	for scanner.Scan() {
+		text := scanner.Text()
+		if oldRemains > 0 || newRemains > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				removed = false
+				mark(line)
+				line++
+				newRemains--
+			case strings.HasPrefix(text, "-"):
+				removed = true
+				oldRemains--
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file"
+			default:
+				// Context line
+				flush()
+				line++
+				oldRemains--
+				newRemains--
+			}
+			if oldRemains <= 0 && newRemains <= 0 {
+				flush()
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = diffPath(strings.TrimPrefix(text, "+++ "), root)
+			if _, ok := changes[file]; file != "" && !ok {
+				changes[file] = make(map[int]struct{})
+			}
+		case strings.HasPrefix(text, "@@ "):
+			var err error
+			if oldRemains, line, newRemains, err = parseHunkHeader(text); err != nil {
+				return nil, err
+			}
+			// Start line points to the line before the hunk when no lines are in the new file
+			if newRemains == 0 {
+				line++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read diff: %w", err)
+	}
+	return changes, nil
+}
+
+// Get file path from "+++" line, returns empty string if the file is deleted
+func diffPath(name, root string) string {
+	// Timestamp may follow the file name after TAB
+	if idx := strings.Index(name, "\t"); idx >= 0 {
+		name = name[:idx]
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		name = unquoted
+	}
+	name = strings.TrimPrefix(name, "b/")
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(root, name)
+	}
+	return filepath.Clean(name)
+}
+
+// Parse line count of the old file, start line and line count of the new file from hunk header like "@@ -1,2 +3,4 @@"
+func parseHunkHeader(line string) (int, int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("Invalid hunk header: %s", line)
+	}
+	_, oldCount, err := parseHunkRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid hunk header: %s", line)
+	}
+	start, newCount, err := parseHunkRange(fields[2][1:])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Invalid hunk header: %s", line)
+	}
+	return oldCount, start, newCount, nil
+}
+
+// Parse range like "3,4", line count is 1 when omitted
+func parseHunkRange(r string) (int, int, error) {
+	start, count, found := strings.Cut(r, ",")
+	s, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !found {
+		return s, 1, nil
+	}
+	c, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, 0, err
+	}
+	return s, c, nil
+}
+
+// Contains returns true if the line of the file is changed
+func (c ChangedLines) Contains(file string, line int) bool {
+	if file == "" {
+		return false
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	lines, ok := c[file]
+	if !ok {
+		return false
+	}
+	_, ok = lines[line]
+	return ok
+}
+
+// Filter returns diagnostics which intersect changed lines, and count of diagnostics which are not reported.
+// The diagnostic intersects when the reported line, the related locations or the range of suggested fix is changed.
+func (c ChangedLines) Filter(diagnostics []*Diagnostic) ([]*Diagnostic, int) {
+	var remains []*Diagnostic
+	var skipped int
+	for _, d := range diagnostics {
+		if c.intersects(d) {
+			remains = append(remains, d)
+			continue
+		}
+		skipped++
+	}
+	return remains, skipped
+}
+
+func (c ChangedLines) intersects(d *Diagnostic) bool {
+	if c.Contains(d.Token.File, d.Token.Line) {
+		return true
+	}
+	for _, r := range d.Related {
+		if c.Contains(r.Token.File, r.Token.Line) {
+			return true
+		}
+	}
+	if d.Fix != nil {
+		for _, edit := range d.Fix.Edits {
+			// End position is exclusive, the edit does not touch the end line when it ends at the line head
+			end := edit.Range.End.Line
+			if edit.Range.End.Column <= 1 && end > edit.Range.Start.Line {
+				end--
+			}
+			for line := edit.Range.Start.Line; line <= end; line++ {
+				if c.Contains(edit.Range.File, line) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
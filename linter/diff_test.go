@@ -0,0 +1,110 @@
+package linter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDiff(t *testing.T) {
+	diff := `diff --git a/main.vcl b/main.vcl
+index 1111111..2222222 100644
+--- a/main.vcl
++++ b/main.vcl
+@@ -2,0 +3,2 @@ sub vcl_recv {
++  declare local var.foo STRING;
++  declare local var.bar STRING;
+@@ -10 +12 @@ sub vcl_recv {
+-  set req.http.Foo = "foo";
++  set req.http.Foo = "bar";
+@@ -20,3 +21,0 @@ sub vcl_fetch {
+-  esi;
+-  esi;
+-  esi;
+diff --git a/removed.vcl b/removed.vcl
+deleted file mode 100644
+--- a/removed.vcl
++++ /dev/null
+@@ -1,2 +0,0 @@
+-sub foo {
+-}
+`
+	changes, err := ParseDiff(strings.NewReader(diff), "/path/to")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expect := ChangedLines{
+		"/path/to/main.vcl": {3: {}, 4: {}, 12: {}, 21: {}, 22: {}},
+	}
+	if d := cmp.Diff(expect, changes); d != "" {
+		t.Errorf("Changed lines mismatch, diff=%s", d)
+	}
+
+	// Context lines are not changed
+	diff = `--- a/main.vcl
++++ b/main.vcl
+@@ -1,6 +1,6 @@
+ sub vcl_recv {
+   #FASTLY RECV
+-  declare local var.foo STRING;
+   declare local var.bar STRING;
++  declare local var.baz STRING;
+   set req.http.Foo = "foo";
+ }
+`
+	changes, err = ParseDiff(strings.NewReader(diff), "/path/to")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expect = ChangedLines{
+		"/path/to/main.vcl": {2: {}, 3: {}, 4: {}},
+	}
+	if d := cmp.Diff(expect, changes); d != "" {
+		t.Errorf("Changed lines mismatch, diff=%s", d)
+	}
+
+	if _, err := ParseDiff(strings.NewReader("+++ b/main.vcl\n@@ -1 +a @@\n"), "/path/to"); err == nil {
+		t.Errorf("Expected error for invalid hunk header")
+	}
+}
+
+func TestChangedLinesFilter(t *testing.T) {
+	diagnostics, _ := lintForBaseline(t, `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.foo STRING;
+  declare local var.bar STRING;
+}`)
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %d", len(diagnostics))
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	changes := ChangedLines{
+		filepath.Join(cwd, "main.vcl"): {5: {}},
+	}
+	remains, skipped := changes.Filter(diagnostics)
+	if skipped != 1 {
+		t.Errorf("Expected 1 skipped diagnostic, got %d", skipped)
+	}
+	if len(remains) != 1 || remains[0].Token.Line != 5 {
+		t.Errorf("Only diagnostic on changed line should be reported, got %v", remains)
+	}
+
+	// Diagnostic is reported when the related location is changed
+	// Unused variables are reported in random order
+	unchanged, changed := diagnostics[0], diagnostics[1]
+	if unchanged.Token.Line == 5 {
+		unchanged, changed = changed, unchanged
+	}
+	unchanged.Relate(changed.Token, "related")
+	if remains, _ := changes.Filter(diagnostics); len(remains) != 2 {
+		t.Errorf("Diagnostic which relates to changed line should be reported, got %d", len(remains))
+	}
+}
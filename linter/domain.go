@@ -0,0 +1,35 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Lint hostnames which are compared with Host header are attached to the service.
+// Domains are fetched from Fastly in remote mode, the branch for the host which is not attached to the service
+// is never executed, for example after the domain is migrated to another service.
+func (l *Linter) lintServiceDomains(decl *ast.SubroutineDeclaration) {
+	if len(l.serviceDomains) == 0 {
+		return
+	}
+
+	walkStatements(decl.Block, func(stmt ast.Statement) bool {
+		ifStmt, ok := stmt.(*ast.IfStatement)
+		if !ok {
+			return true
+		}
+		for _, host := range collectIfHostComparisons(ifStmt) {
+			if l.serviceDomains.match(host.Value) {
+				continue
+			}
+			err := &LintError{
+				Severity: WARNING,
+				Token:    host.GetMeta().Token,
+				Message:  fmt.Sprintf("Host %s is not attached to the service, the branch is never executed", host.Value),
+			}
+			l.Error(err.Match(DOMAIN_NOT_ATTACHED))
+		}
+		return true
+	})
+}
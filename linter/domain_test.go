@@ -0,0 +1,41 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintServiceDomains(t *testing.T) {
+	input := `
+sub vcl_recv {
+   #FASTLY RECV
+   if (req.http.Host == "www.example.com") {
+     set req.http.X-Site = "www";
+   } else if (req.http.Host == "old.example.com" || req.http.Host == "static.cdn.example.com") {
+     set req.http.X-Site = "legacy";
+   }
+}`
+
+	t.Run("host is not attached to the service", func(t *testing.T) {
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Fatalf("unexpected parser error: %s", err)
+		}
+		l := New(WithServiceDomains([]string{"www.example.com", "*.cdn.example.com"}))
+		l.lint(vcl, context.New())
+		if len(l.Diagnostics) != 1 {
+			t.Errorf("Expect one lint error but got %d", len(l.Diagnostics))
+			return
+		}
+		if l.Diagnostics[0].Rule != DOMAIN_NOT_ATTACHED {
+			t.Errorf("Unexpected lint error: %s", l.Diagnostics[0])
+		}
+	})
+
+	t.Run("disabled without domains", func(t *testing.T) {
+		assertNoError(t, input)
+	})
+}
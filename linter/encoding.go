@@ -0,0 +1,65 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/token"
+)
+
+// LintSourceEncoding reports the source encoding which lexer tolerates.
+// BOM and CRLF line endings work as they are but worth normalizing because other tools may not tolerate them,
+// and invalid UTF-8 bytes are reported with the byte offset because most editors do not show them.
+func (l *Linter) LintSourceEncoding(file string, lx *lexer.Lexer) {
+	if lx == nil {
+		return
+	}
+	enc := lx.Encoding()
+
+	if enc.BOM {
+		err := &LintError{
+			Severity: INFO,
+			Token:    token.Token{File: file, Line: 1, Position: 1},
+			Message:  "File starts with UTF-8 byte order mark, consider removing it",
+		}
+		l.Error(err.Match(SOURCE_ENCODING))
+	}
+	if len(enc.CRLFLines) > 0 {
+		err := &LintError{
+			Severity: INFO,
+			Token:    token.Token{File: file, Line: enc.CRLFLines[0], Position: 1},
+			Message: fmt.Sprintf(
+				"%d lines end with CRLF, consider normalizing line endings to LF", len(enc.CRLFLines),
+			),
+		}
+		l.Error(err.Match(SOURCE_ENCODING))
+	}
+
+	// Invalid bytes often continue like Latin-1 text, so report the first one in each line
+	line := 0
+	for _, b := range enc.InvalidBytes {
+		if b.Line == line {
+			continue
+		}
+		line = b.Line
+		err := &LintError{
+			Severity: WARNING,
+			Token:    token.Token{File: file, Line: b.Line, Position: b.Position, Literal: "�"},
+			Message:  fmt.Sprintf("Invalid UTF-8 byte 0x%02X is found at byte offset %d", b.Byte, b.Offset),
+		}
+		l.Error(err.Match(SOURCE_INVALID_UTF8))
+	}
+}
+
+// Lint source encoding of included modules which are parsed in the linter
+func (l *Linter) lintIncludedSourceEncodings() {
+	files := make([]string, 0, len(l.includexLexers))
+	for file := range l.includexLexers {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		l.LintSourceEncoding(file, l.includexLexers[file])
+	}
+}
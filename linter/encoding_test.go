@@ -0,0 +1,61 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/token"
+)
+
+func TestLintSourceEncoding(t *testing.T) {
+	type reported struct {
+		Rule     Rule
+		Severity Severity
+		Line     int
+		Position int
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		expect []reported
+	}{
+		{
+			name:  "UTF-8 with LF",
+			input: "sub vcl_recv {\n  #FASTLY RECV\n}\n",
+		},
+		{
+			name:  "BOM and CRLF",
+			input: "\xEF\xBB\xBFsub vcl_recv {\r\n  #FASTLY RECV\r\n}\r\n",
+			expect: []reported{
+				{Rule: SOURCE_ENCODING, Severity: INFO, Line: 1, Position: 1},
+				{Rule: SOURCE_ENCODING, Severity: INFO, Line: 1, Position: 1},
+			},
+		},
+		{
+			name:  "first invalid byte in each line",
+			input: "sub vcl_recv {\n  set req.http.X = \"\xE9\xE9\";\n  set req.http.Y = \"\xFF\";\n}\n",
+			expect: []reported{
+				{Rule: SOURCE_INVALID_UTF8, Severity: WARNING, Line: 2, Position: 21},
+				{Rule: SOURCE_INVALID_UTF8, Severity: WARNING, Line: 3, Position: 21},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		lx := lexer.NewFromString(tt.input, lexer.WithFile("main.vcl"))
+		for tok := lx.NextToken(); tok.Type != token.EOF; tok = lx.NextToken() {
+		}
+
+		l := New()
+		l.LintSourceEncoding("main.vcl", lx)
+		var actual []reported
+		for _, d := range l.Diagnostics {
+			actual = append(actual, reported{Rule: d.Rule, Severity: d.Severity, Line: d.Token.Line, Position: d.Token.Position})
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: diagnostics mismatch, diff=%s", tt.name, diff)
+		}
+	}
+}
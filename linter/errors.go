@@ -6,7 +6,6 @@ import (
 
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/lexer"
-	"github.com/ysugimoto/falco/token"
 	"github.com/ysugimoto/falco/types"
 )
 
@@ -19,45 +18,24 @@ const (
 	IGNORE  Severity = "Ignore"
 )
 
-type LintError struct {
-	Severity  Severity
-	Token     token.Token
-	Message   string
-	Reference string
-	Rule      Rule
-}
-
-func (l *LintError) Match(r Rule) *LintError {
-	l.Rule = r
-	l.Reference = r.Reference()
-	return l
-}
-
-func (e *LintError) Ref(url string) *LintError {
-	e.Reference = url
-	return e
-}
-
-func (e *LintError) Error() string {
-	var rule, ref, file string
-
-	if e.Rule != "" {
-		rule = fmt.Sprintf(" (%s)", e.Rule)
-	}
-	if e.Reference != "" {
-		ref = "\nSee reference documentation: " + e.Reference
+// ParseSeverity parses severity name case-insensitively, which is specified in the configuration
+func ParseSeverity(v string) (Severity, error) {
+	switch strings.ToUpper(v) {
+	case "ERROR":
+		return ERROR, nil
+	case "WARNING":
+		return WARNING, nil
+	case "INFO":
+		return INFO, nil
+	case "IGNORE":
+		return IGNORE, nil
 	}
-	if e.Token.File != "" {
-		file = " in" + e.Token.File
-	}
-
-	msg := fmt.Sprintf(
-		"[%s] %s%s%s at line: %d, position: %d%s",
-		e.Severity, e.Message, rule, file, e.Token.Line, e.Token.Position, ref,
-	)
-	return msg
+	return "", fmt.Errorf(`Unknown severity "%s", severity must be one of ERROR, WARNING, INFO, IGNORE`, v)
 }
 
+// LintError is kept for backward compatibility, use Diagnostic instead
+type LintError = Diagnostic
+
 func InvalidName(m *ast.Meta, name, ident string) *LintError {
 	return &LintError{
 		Severity: ERROR,
@@ -270,6 +248,13 @@ func FunctionArgumentTypeMismatch(m *ast.Meta, name string, num int, expect, act
 }
 
 func InvalidReturnState(m *ast.Meta, scope, state string, expects ...string) *LintError {
+	if len(expects) == 0 {
+		return &LintError{
+			Severity: ERROR,
+			Token:    m.Token,
+			Message:  fmt.Sprintf(`Return statement "%s" is invalid in %s, no state is legal in all of these scopes`, state, scope),
+		}
+	}
 	return &LintError{
 		Severity: ERROR,
 		Token:    m.Token,
@@ -349,7 +334,31 @@ func ProtectedHTTPHeader(m *ast.Meta, name string) *LintError {
 	}
 }
 
+// IncludeCycle reports the last include statement of the cycle, chain is ordered from the outermost include statement
+func IncludeCycle(chain []*ast.IncludeStatement, module string) *LintError {
+	files := make([]string, 0, len(chain)+1)
+	for _, include := range chain {
+		files = append(files, include.GetMeta().Token.File)
+	}
+	files = append(files, module)
+
+	last := chain[len(chain)-1]
+	e := &LintError{
+		Severity: ERROR,
+		Token:    last.GetMeta().Token,
+		Message:  fmt.Sprintf("Include cycle detected: %s", strings.Join(files, " -> ")),
+	}
+	for _, include := range chain[:len(chain)-1] {
+		e.Relate(include.GetMeta().Token, "Module is included here")
+	}
+	return e
+}
+
 type FatalError struct {
 	Lexer *lexer.Lexer
 	Error error
+	// Statement or declaration being processed when the fatal error occurs
+	Statement ast.Node
+	// Stack trace when the linter panics
+	Stack []byte
 }
@@ -0,0 +1,328 @@
+package linter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/token"
+)
+
+// Facts is a normalized fact set about the linted VCL.
+// Facts are exported as JSON in order to evaluate organizational policies by external policy engine like OPA.
+type Facts struct {
+	Backends     []*BackendFact     `json:"backends"`
+	Directors    []*DirectorFact    `json:"directors"`
+	Acls         []*AclFact         `json:"acls"`
+	Tables       []*TableFact       `json:"tables"`
+	Subroutines  []*SubroutineFact  `json:"subroutines"`
+	Calls        []*CallFact        `json:"calls"`
+	Functions    []*FunctionFact    `json:"functions"`
+	HeaderWrites []*HeaderWriteFact `json:"header_writes"`
+	Logs         []*LogFact         `json:"logs"`
+}
+
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type BackendFact struct {
+	Location
+	Name       string            `json:"name"`
+	Properties map[string]string `json:"properties"`
+}
+
+type DirectorFact struct {
+	Location
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Backends []string `json:"backends"`
+}
+
+type AclFact struct {
+	Location
+	Name    string   `json:"name"`
+	Entries []string `json:"entries"`
+}
+
+type TableFact struct {
+	Location
+	Name      string `json:"name"`
+	ValueType string `json:"value_type"`
+	Items     int    `json:"items"`
+}
+
+type SubroutineFact struct {
+	Location
+	Name       string   `json:"name"`
+	Phases     []string `json:"phases"`
+	ReturnType string   `json:"return_type,omitempty"`
+}
+
+type CallFact struct {
+	Location
+	Subroutine string   `json:"subroutine"`
+	Phases     []string `json:"phases"`
+	Target     string   `json:"target"`
+}
+
+type FunctionFact struct {
+	Location
+	Subroutine string   `json:"subroutine"`
+	Phases     []string `json:"phases"`
+	Name       string   `json:"name"`
+}
+
+type HeaderWriteFact struct {
+	Location
+	Subroutine string   `json:"subroutine"`
+	Phases     []string `json:"phases"`
+	// Statement keyword which writes the header, one of set, unset, add and remove
+	Statement string `json:"statement"`
+	Variable  string `json:"variable"`
+	// Object of header like req, bereq, beresp, resp and obj
+	Object string `json:"object"`
+	Header string `json:"header"`
+}
+
+type LogFact struct {
+	Location
+	Subroutine string   `json:"subroutine"`
+	Phases     []string `json:"phases"`
+	// Logging endpoint name, runtime values are represented as placeholders like "{req.service_id}"
+	Endpoint string `json:"endpoint"`
+}
+
+func newFacts() *Facts {
+	// Initialize with empty slices in order to be encoded as empty array instead of null
+	return &Facts{
+		Backends:     []*BackendFact{},
+		Directors:    []*DirectorFact{},
+		Acls:         []*AclFact{},
+		Tables:       []*TableFact{},
+		Subroutines:  []*SubroutineFact{},
+		Calls:        []*CallFact{},
+		Functions:    []*FunctionFact{},
+		HeaderWrites: []*HeaderWriteFact{},
+		Logs:         []*LogFact{},
+	}
+}
+
+func location(t token.Token) Location {
+	return Location{
+		File:   t.File,
+		Line:   t.Line,
+		Column: t.Position,
+	}
+}
+
+// Returns literal value of the expression without comments
+func factValue(exp ast.Expression) string {
+	switch t := exp.(type) {
+	case *ast.String:
+		return t.Value
+	case *ast.Ident:
+		return t.Value
+	case *ast.Integer:
+		return strconv.FormatInt(t.Value, 10)
+	case *ast.Float:
+		return strconv.FormatFloat(t.Value, 'f', -1, 64)
+	case *ast.Boolean:
+		return strconv.FormatBool(t.Value)
+	case *ast.RTime:
+		return t.Value
+	default:
+		return strings.TrimSpace(exp.String())
+	}
+}
+
+// Returns phase names in the order of request lifecycle
+func phaseNames(mode int) []string {
+	names := []string{}
+	for _, name := range []string{"recv", "hash", "hit", "miss", "pass", "fetch", "error", "deliver", "log"} {
+		if mode&policyPhases[name] > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Collect facts from the node, called for all nodes which are linted.
+// Note that included modules and snippets have already been resolved at this point.
+func (l *Linter) collectFacts(node ast.Node, ctx *context.Context) {
+	if l.facts == nil {
+		return
+	}
+
+	var subroutine string
+	if ctx.CurrentSubroutine != nil {
+		subroutine = ctx.CurrentSubroutine.Name.Value
+	}
+
+	switch t := node.(type) {
+	case *ast.BackendDeclaration:
+		fact := &BackendFact{
+			Location:   location(t.Name.GetMeta().Token),
+			Name:       t.Name.Value,
+			Properties: make(map[string]string),
+		}
+		for _, p := range t.Properties {
+			// Probe is an object, expose only its existence
+			if p.Key.Value == "probe" {
+				fact.Properties[p.Key.Value] = "true"
+				continue
+			}
+			fact.Properties[p.Key.Value] = factValue(p.Value)
+		}
+		l.facts.Backends = append(l.facts.Backends, fact)
+	case *ast.DirectorDeclaration:
+		fact := &DirectorFact{
+			Location: location(t.Name.GetMeta().Token),
+			Name:     t.Name.Value,
+			Type:     t.DirectorType.Value,
+			Backends: []string{},
+		}
+		for _, p := range t.Properties {
+			obj, ok := p.(*ast.DirectorBackendObject)
+			if !ok {
+				continue
+			}
+			for _, v := range obj.Values {
+				if v.Key.Value == "backend" {
+					fact.Backends = append(fact.Backends, factValue(v.Value))
+				}
+			}
+		}
+		l.facts.Directors = append(l.facts.Directors, fact)
+	case *ast.AclDeclaration:
+		fact := &AclFact{
+			Location: location(t.Name.GetMeta().Token),
+			Name:     t.Name.Value,
+			Entries:  []string{},
+		}
+		for _, cidr := range t.CIDRs {
+			entry := cidr.IP.Value
+			if cidr.Mask != nil {
+				entry += "/" + strconv.FormatInt(cidr.Mask.Value, 10)
+			}
+			if cidr.Inverse != nil && cidr.Inverse.Value {
+				entry = "!" + entry
+			}
+			fact.Entries = append(fact.Entries, entry)
+		}
+		l.facts.Acls = append(l.facts.Acls, fact)
+	case *ast.TableDeclaration:
+		fact := &TableFact{
+			Location:  location(t.Name.GetMeta().Token),
+			Name:      t.Name.Value,
+			ValueType: "STRING",
+			Items:     len(t.Properties),
+		}
+		if t.ValueType != nil {
+			fact.ValueType = t.ValueType.Value
+		}
+		l.facts.Tables = append(l.facts.Tables, fact)
+	case *ast.SubroutineDeclaration:
+		fact := &SubroutineFact{
+			Location: location(t.Name.GetMeta().Token),
+			Name:     t.Name.Value,
+			Phases:   phaseNames(getSubroutineCallScope(t)),
+		}
+		if t.ReturnType != nil {
+			fact.ReturnType = t.ReturnType.Value
+		}
+		l.facts.Subroutines = append(l.facts.Subroutines, fact)
+	case *ast.CallStatement:
+		l.facts.Calls = append(l.facts.Calls, &CallFact{
+			Location:   location(t.GetMeta().Token),
+			Subroutine: subroutine,
+			Phases:     phaseNames(ctx.Mode()),
+			Target:     t.Subroutine.Value,
+		})
+	case *ast.FunctionCallStatement:
+		l.facts.Functions = append(l.facts.Functions, &FunctionFact{
+			Location:   location(t.Function.GetMeta().Token),
+			Subroutine: subroutine,
+			Phases:     phaseNames(ctx.Mode()),
+			Name:       t.Function.Value,
+		})
+	case *ast.FunctionCallExpression:
+		l.facts.Functions = append(l.facts.Functions, &FunctionFact{
+			Location:   location(t.Function.GetMeta().Token),
+			Subroutine: subroutine,
+			Phases:     phaseNames(ctx.Mode()),
+			Name:       t.Function.Value,
+		})
+	case *ast.SetStatement:
+		l.collectHeaderWrite("set", t.Ident, subroutine, ctx)
+	case *ast.UnsetStatement:
+		l.collectHeaderWrite("unset", t.Ident, subroutine, ctx)
+	case *ast.AddStatement:
+		l.collectHeaderWrite("add", t.Ident, subroutine, ctx)
+	case *ast.RemoveStatement:
+		l.collectHeaderWrite("remove", t.Ident, subroutine, ctx)
+	case *ast.LogStatement:
+		endpoint := logEndpoint(t.Value)
+		if endpoint == "" {
+			return
+		}
+		l.facts.Logs = append(l.facts.Logs, &LogFact{
+			Location:   location(t.GetMeta().Token),
+			Subroutine: subroutine,
+			Phases:     phaseNames(ctx.Mode()),
+			Endpoint:   endpoint,
+		})
+	}
+}
+
+// Log line is formatted as "syslog [service_id] [endpoint] :: [message]"
+var logEndpointPattern = regexp.MustCompile(`^syslog\s+\S+\s+(.+?)\s+::`)
+
+// Returns logging endpoint name of the log statement, or empty string when the log line is not formatted for endpoints
+func logEndpoint(exp ast.Expression) string {
+	match := logEndpointPattern.FindStringSubmatch(flattenLogLine(exp))
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// Flatten string concatenation, runtime values are replaced with placeholders like "{req.service_id}"
+func flattenLogLine(exp ast.Expression) string {
+	switch t := exp.(type) {
+	case *ast.String:
+		return t.Value
+	case *ast.InfixExpression:
+		if t.Operator == "+" {
+			return flattenLogLine(t.Left) + flattenLogLine(t.Right)
+		}
+	case *ast.PrefixExpression:
+		// Explicit concatenation operator is parsed as prefix of the right operand
+		if t.Operator == "+" {
+			return flattenLogLine(t.Right)
+		}
+	case *ast.GroupedExpression:
+		return flattenLogLine(t.Right)
+	}
+	return "{" + factValue(exp) + "}"
+}
+
+func (l *Linter) collectHeaderWrite(statement string, ident *ast.Ident, subroutine string, ctx *context.Context) {
+	object, header, found := strings.Cut(ident.Value, ".http.")
+	if !found {
+		return
+	}
+	l.facts.HeaderWrites = append(l.facts.HeaderWrites, &HeaderWriteFact{
+		Location:   location(ident.GetMeta().Token),
+		Subroutine: subroutine,
+		Phases:     phaseNames(ctx.Mode()),
+		Statement:  statement,
+		Variable:   ident.Value,
+		Object:     object,
+		Header:     header,
+	})
+}
@@ -0,0 +1,148 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestCollectFacts(t *testing.T) {
+	input := `
+backend origin {
+  .host = "example.com";
+  .port = "443";
+  .ssl = true;
+}
+
+director cluster random {
+  { .backend = origin; .weight = 1; }
+}
+
+acl internal {
+  "192.0.2.0"/24;
+  !"192.0.2.1";
+}
+
+table routes {
+  "/": "root",
+}
+
+// @scope: recv, deliver
+sub set_debug {
+  set req.http.X-Debug = "1";
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  call set_debug;
+  if (client.ip ~ internal) {
+    set req.http.X-Route = table.lookup(routes, req.url.path, "");
+  }
+  set req.backend = cluster;
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  unset resp.http.Server;
+}`
+
+	vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile("main.vcl"))).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	l := New(WithFacts())
+	l.Lint(vcl, context.New())
+	facts := l.Facts()
+
+	expectBackends := []*BackendFact{
+		{
+			Location:   Location{File: "main.vcl", Line: 2, Column: 9},
+			Name:       "origin",
+			Properties: map[string]string{"host": "example.com", "port": "443", "ssl": "true"},
+		},
+	}
+	if diff := cmp.Diff(expectBackends, facts.Backends); diff != "" {
+		t.Errorf("Backend facts mismatch, diff=%s", diff)
+	}
+	if diff := cmp.Diff([]string{"origin"}, facts.Directors[0].Backends); diff != "" {
+		t.Errorf("Director facts mismatch, diff=%s", diff)
+	}
+	if diff := cmp.Diff([]string{"192.0.2.0/24", "!192.0.2.1"}, facts.Acls[0].Entries); diff != "" {
+		t.Errorf("ACL facts mismatch, diff=%s", diff)
+	}
+	if facts.Tables[0].Name != "routes" || facts.Tables[0].Items != 1 || facts.Tables[0].ValueType != "STRING" {
+		t.Errorf("Unexpected table facts: %+v", facts.Tables[0])
+	}
+
+	var subroutines []string
+	for _, s := range facts.Subroutines {
+		subroutines = append(subroutines, s.Name)
+	}
+	if diff := cmp.Diff([]string{"set_debug", "vcl_recv", "vcl_deliver"}, subroutines); diff != "" {
+		t.Errorf("Subroutine facts mismatch, diff=%s", diff)
+	}
+	if diff := cmp.Diff([]string{"recv", "deliver"}, facts.Subroutines[0].Phases); diff != "" {
+		t.Errorf("Subroutine phases mismatch, diff=%s", diff)
+	}
+
+	if len(facts.Calls) != 1 || facts.Calls[0].Subroutine != "vcl_recv" || facts.Calls[0].Target != "set_debug" {
+		t.Errorf("Unexpected call facts: %+v", facts.Calls)
+	}
+	if len(facts.Functions) != 1 || facts.Functions[0].Name != "table.lookup" || facts.Functions[0].Line != 30 {
+		t.Errorf("Unexpected function facts: %+v", facts.Functions)
+	}
+
+	var writes []string
+	for _, w := range facts.HeaderWrites {
+		writes = append(writes, w.Subroutine+" "+w.Statement+" "+w.Object+" "+w.Header)
+	}
+	expectWrites := []string{
+		"set_debug set req X-Debug",
+		"vcl_recv set req X-Route",
+		"vcl_deliver unset resp Server",
+	}
+	if diff := cmp.Diff(expectWrites, writes); diff != "" {
+		t.Errorf("Header write facts mismatch, diff=%s", diff)
+	}
+}
+
+func TestFactsDisabled(t *testing.T) {
+	l := New()
+	if l.Facts() != nil {
+		t.Errorf("Facts should not be collected without WithFacts option")
+	}
+}
+
+func TestCollectLogFacts(t *testing.T) {
+	input := `
+sub vcl_log {
+  #FASTLY LOG
+  log "syslog " req.service_id " access-log :: " req.url;
+  log "syslog " + req.service_id + " " + req.http.Endpoint + " :: " req.url;
+  log "not formatted for endpoints";
+}`
+
+	vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile("main.vcl"))).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	l := New(WithFacts())
+	l.Lint(vcl, context.New())
+
+	var endpoints []string
+	for _, v := range l.Facts().Logs {
+		endpoints = append(endpoints, v.Subroutine+" "+v.Endpoint)
+	}
+	expect := []string{
+		"vcl_log access-log",
+		"vcl_log {req.http.Endpoint}",
+	}
+	if diff := cmp.Diff(expect, endpoints); diff != "" {
+		t.Errorf("Log facts mismatch, diff=%s", diff)
+	}
+}
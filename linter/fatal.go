@@ -0,0 +1,59 @@
+package linter
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/parser"
+)
+
+// Count of source lines which are shown before the parse error position in the snippet
+const fatalSnippetLines = 5
+
+// Lint statement with recovering the panic.
+// The panic is recorded as FatalError and the linter continues to lint the rest of statements,
+// so partial results could be reported with the context of the fatal error.
+func (l *Linter) lintStatementWithRecover(s ast.Statement, ctx *context.Context) {
+	depth := len(l.path)
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		// Path is left when the linter panics
+		l.path = l.path[:depth]
+		// Keep the first fatal error which is the origin of the problem
+		if l.FatalError != nil {
+			return
+		}
+		l.FatalError = &FatalError{
+			Lexer:     l.includexLexers[s.GetMeta().Token.File],
+			Error:     fmt.Errorf("Linter panics: %v", r),
+			Statement: s,
+			Stack:     debug.Stack(),
+		}
+	}()
+	l.lintStatement(s, ctx)
+}
+
+// Snippet returns VCL source code which reproduces the fatal error.
+// For the parse error, returns source lines around the error position.
+// Otherwise returns the formatted statement which was being processed.
+func (f *FatalError) Snippet() string {
+	if pe, ok := f.Error.(*parser.ParseError); ok && f.Lexer != nil {
+		var lines []string
+		for l := pe.Token.Line - fatalSnippetLines; l <= pe.Token.Line; l++ {
+			if line, ok := f.Lexer.GetLine(l); ok {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	if f.Statement != nil {
+		return strings.TrimRight(f.Statement.String(), "\n")
+	}
+	return ""
+}
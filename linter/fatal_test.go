@@ -0,0 +1,97 @@
+package linter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/crash"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+// Rule which panics while checking the subroutine
+type panicRule struct{}
+
+func (r *panicRule) Name() Rule {
+	return "test/panic"
+}
+
+func (r *panicRule) Check(node ast.Node, ctx *context.Context) []*LintError {
+	if sub, ok := node.(*ast.SubroutineDeclaration); ok && sub.Name.Value == "vcl_recv" {
+		panic("unexpected node")
+	}
+	return nil
+}
+
+func TestExplainFatal(t *testing.T) {
+	input := `
+sub vcl_recv {
+	#FASTLY RECV
+	set req.http.Foo = "bar";
+}
+
+sub vcl_deliver {
+	#FASTLY DELIVER
+	call undefined_subroutine;
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Fatalf("unexpected parser error: %s", err)
+	}
+
+	l := New(WithCustomRules(&panicRule{}), WithExplainFatal(true))
+	l.Lint(vcl, context.New())
+
+	if l.FatalError == nil {
+		t.Fatalf("Expects fatal error is recorded")
+	}
+	sub, ok := l.FatalError.Statement.(*ast.SubroutineDeclaration)
+	if !ok || sub.Name.Value != "vcl_recv" {
+		t.Errorf("Expects vcl_recv is recorded as the statement, got %v", l.FatalError.Statement)
+	}
+	if len(l.FatalError.Stack) == 0 {
+		t.Errorf("Expects stack trace is recorded")
+	}
+	if snippet := l.FatalError.Snippet(); !strings.HasPrefix(snippet, "sub vcl_recv {") {
+		t.Errorf("Unexpected reproduction snippet: %s", snippet)
+	}
+
+	// Rest of declarations are linted
+	var found bool
+	for _, d := range l.Diagnostics {
+		if d.Rule == CALL_STATEMENT_SUBROUTINE_NOTFOUND {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expects vcl_deliver is linted after the fatal error, got %v", l.Diagnostics)
+	}
+}
+
+func TestLintPanicIsWrapped(t *testing.T) {
+	input := `
+sub vcl_recv {
+	#FASTLY RECV
+	set req.http.Foo = "bar";
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Fatalf("unexpected parser error: %s", err)
+	}
+
+	defer func() {
+		p, ok := recover().(*crash.Panic)
+		if !ok {
+			t.Fatalf("Expects panic is wrapped as *crash.Panic")
+		}
+		if len(p.Path) != 2 {
+			t.Fatalf("Expects VCL and subroutine in the path, got %v", p.Path)
+		}
+		if _, ok := p.Path[1].(*ast.SubroutineDeclaration); !ok {
+			t.Errorf("Expects innermost node is the subroutine, got %T", p.Path[1])
+		}
+	}()
+	New(WithCustomRules(&panicRule{})).Lint(vcl, context.New())
+}
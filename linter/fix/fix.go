@@ -0,0 +1,147 @@
+package fix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ysugimoto/falco/linter"
+)
+
+// Result of applying fixes to the source
+type Result struct {
+	Source []byte
+	// Fixes which are applied to the source
+	Applied []*linter.Fix
+	// Fixes which are not applied because their edits conflict with applied ones
+	Skipped []*linter.Fix
+}
+
+// Files groups fixes of the diagnostics by the file which their edits are applied to.
+// Fixes which edit multiple files or the file which is not on the filesystem like remote snippet are dropped.
+func Files(diagnostics []*linter.Diagnostic) map[string][]*linter.Fix {
+	files := make(map[string][]*linter.Fix)
+	for _, d := range diagnostics {
+		if d.Fix == nil || len(d.Fix.Edits) == 0 {
+			continue
+		}
+		file := d.Fix.Edits[0].Range.File
+		if file == "" || strings.HasPrefix(file, "snippet::") {
+			continue
+		}
+		for _, e := range d.Fix.Edits[1:] {
+			if e.Range.File != file {
+				file = ""
+				break
+			}
+		}
+		if file == "" {
+			continue
+		}
+		files[file] = append(files[file], d.Fix)
+	}
+	return files
+}
+
+// Offset range of the edit in bytes
+type span struct {
+	start int
+	end   int
+	text  string
+}
+
+func (s span) overlaps(v span) bool {
+	// Insertions at the same offset are conflicted because the order could not be determined
+	if s.start == v.start {
+		return true
+	}
+	return s.start < v.end && v.start < s.end
+}
+
+// Apply applies edits of the fixes to the source in order.
+// All edits of a fix are applied together, and the fix is skipped when any of edits overlaps applied ones
+// so that the result is always same as applying fixes one by one.
+func Apply(src []byte, fixes []*linter.Fix) (*Result, error) {
+	lines := lineOffsets(src)
+	result := &Result{}
+
+	var applied []span
+	for _, f := range fixes {
+		var spans []span
+		for _, e := range f.Edits {
+			start, err := offset(src, lines, e.Range.Start)
+			if err != nil {
+				return nil, err
+			}
+			end, err := offset(src, lines, e.Range.End)
+			if err != nil {
+				return nil, err
+			}
+			if end < start {
+				return nil, fmt.Errorf("Invalid edit range %d:%d-%d:%d",
+					e.Range.Start.Line, e.Range.Start.Column, e.Range.End.Line, e.Range.End.Column,
+				)
+			}
+			spans = append(spans, span{start: start, end: end, text: e.NewText})
+		}
+
+		if conflicts(spans, applied) {
+			result.Skipped = append(result.Skipped, f)
+			continue
+		}
+		applied = append(applied, spans...)
+		result.Applied = append(result.Applied, f)
+	}
+
+	// Apply from the end of the source so that offsets of remaining edits are not shifted
+	sort.Slice(applied, func(i, j int) bool {
+		return applied[i].start > applied[j].start
+	})
+	out := append([]byte{}, src...)
+	for _, s := range applied {
+		out = append(out[:s.start], append([]byte(s.text), out[s.end:]...)...)
+	}
+	result.Source = out
+	return result, nil
+}
+
+// Check spans overlap each other or applied spans
+func conflicts(spans, applied []span) bool {
+	for i, s := range spans {
+		for _, v := range append(spans[i+1:len(spans):len(spans)], applied...) {
+			if s.overlaps(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Byte offsets of each line start
+func lineOffsets(src []byte) []int {
+	offsets := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// Convert position to byte offset, column is counted in characters as lexer does.
+// The column could point the end of the line.
+func offset(src []byte, lines []int, p linter.Position) (int, error) {
+	if p.Line < 1 || p.Line > len(lines) || p.Column < 1 {
+		return 0, fmt.Errorf("Position %d:%d is out of the source", p.Line, p.Column)
+	}
+	pos := lines[p.Line-1]
+	for col := 1; col < p.Column; col++ {
+		if pos >= len(src) || src[pos] == '\n' {
+			return 0, fmt.Errorf("Position %d:%d is out of the source", p.Line, p.Column)
+		}
+		_, size := utf8.DecodeRune(src[pos:])
+		pos += size
+	}
+	return pos, nil
+}
@@ -0,0 +1,271 @@
+package fix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func edit(sl, sc, el, ec int, text string) *linter.TextEdit {
+	return &linter.TextEdit{
+		Range: linter.Range{
+			File:  "main.vcl",
+			Start: linter.Position{Line: sl, Column: sc},
+			End:   linter.Position{Line: el, Column: ec},
+		},
+		NewText: text,
+	}
+}
+
+func TestApply(t *testing.T) {
+	src := "sub vcl_recv {\n  set req.http.Foo = \"ほげ\";\n  set req.http.Bar = \"bar\";\n}\n"
+
+	t.Run("non-conflicting edits are applied", func(t *testing.T) {
+		fixes := []*linter.Fix{
+			{Edits: []*linter.TextEdit{edit(2, 23, 2, 25, "fuga")}},
+			{Edits: []*linter.TextEdit{edit(3, 1, 4, 1, "")}},
+			{Edits: []*linter.TextEdit{edit(1, 15, 1, 15, "\n  #FASTLY RECV")}},
+		}
+		result, err := Apply([]byte(src), fixes)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		expect := "sub vcl_recv {\n  #FASTLY RECV\n  set req.http.Foo = \"fuga\";\n}\n"
+		if diff := cmp.Diff(expect, string(result.Source)); diff != "" {
+			t.Errorf("Fixed source mismatch, diff=%s", diff)
+		}
+		if len(result.Applied) != 3 || len(result.Skipped) != 0 {
+			t.Errorf("All fixes should be applied, applied=%d, skipped=%d", len(result.Applied), len(result.Skipped))
+		}
+	})
+
+	t.Run("conflicting fix is skipped as a whole", func(t *testing.T) {
+		fixes := []*linter.Fix{
+			{Edits: []*linter.TextEdit{edit(2, 7, 2, 19, "req.http.Baz")}},
+			// Second edit overlaps the first fix, so the first edit of this fix is not applied either
+			{Edits: []*linter.TextEdit{edit(3, 7, 3, 19, "req.http.Qux"), edit(2, 15, 2, 18, "Qux")}},
+		}
+		result, err := Apply([]byte(src), fixes)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			return
+		}
+		expect := "sub vcl_recv {\n  set req.http.Baz = \"ほげ\";\n  set req.http.Bar = \"bar\";\n}\n"
+		if diff := cmp.Diff(expect, string(result.Source)); diff != "" {
+			t.Errorf("Fixed source mismatch, diff=%s", diff)
+		}
+		if len(result.Applied) != 1 || len(result.Skipped) != 1 {
+			t.Errorf("Second fix should be skipped, applied=%d, skipped=%d", len(result.Applied), len(result.Skipped))
+		}
+	})
+
+	t.Run("out of range position", func(t *testing.T) {
+		fixes := []*linter.Fix{
+			{Edits: []*linter.TextEdit{edit(2, 100, 2, 101, "")}},
+		}
+		if _, err := Apply([]byte(src), fixes); err == nil {
+			t.Errorf("Expected error but got nil")
+		}
+	})
+}
+
+func TestFixLintErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name: "missing boilerplate macro",
+			input: `sub vcl_recv {
+  set req.http.Foo = "foo";
+}
+`,
+			expect: `sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = "foo";
+}
+`,
+		},
+		{
+			name: "missing boilerplate macro in empty subroutine",
+			input: `sub vcl_deliver {}
+`,
+			expect: `sub vcl_deliver {
+  #FASTLY DELIVER
+}
+`,
+		},
+		{
+			name: "unused variable",
+			input: `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.l_unused STRING;
+  declare local var.l_used STRING; # trailing comment
+  set var.l_used = "foo";
+  set req.http.Foo = var.l_used;
+}
+`,
+			expect: `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.l_used STRING; # trailing comment
+  set var.l_used = "foo";
+  set req.http.Foo = var.l_used;
+}
+`,
+		},
+		{
+			name: "unused variable shares the line",
+			input: `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.l_unused STRING; set req.http.Foo = "foo";
+}
+`,
+			expect: `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.l_unused STRING; set req.http.Foo = "foo";
+}
+`,
+		},
+		{
+			name: "statement is moved to writable phase",
+			input: `sub vcl_fetch {
+  #FASTLY FETCH
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set beresp.ttl = 10s;
+  set resp.http.Foo = "foo";
+}
+`,
+			expect: `sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.ttl = 10s;
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set resp.http.Foo = "foo";
+}
+`,
+		},
+		{
+			name: "nested statement is not moved",
+			input: `sub vcl_fetch {
+  #FASTLY FETCH
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  if (req.http.Foo) {
+    set beresp.ttl = 10s;
+  }
+  set resp.http.Foo = "foo";
+}
+`,
+			expect: `sub vcl_fetch {
+  #FASTLY FETCH
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  if (req.http.Foo) {
+    set beresp.ttl = 10s;
+  }
+  set resp.http.Foo = "foo";
+}
+`,
+		},
+		{
+			name: "variable name is normalized with references",
+			input: `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.cacheKey STRING;
+  set var.cacheKey = req.url.path;
+  if (var.cacheKey ~ "^/api/") {
+    set req.http.Key = "api:" var.cacheKey;
+  }
+}
+`,
+			expect: `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.cache_key STRING;
+  set var.cache_key = req.url.path;
+  if (var.cache_key ~ "^/api/") {
+    set req.http.Key = "api:" var.cache_key;
+  }
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input, lexer.WithFile("main.vcl"))).ParseVCL()
+			if err != nil {
+				t.Errorf("Unexpected parser error: %s", err)
+				return
+			}
+			l := linter.New(linter.WithNamingConvention(&config.NamingConfig{
+				Variable: "^[a-z_]+$",
+			}))
+			l.Lint(vcl, context.New())
+
+			fixes := Files(l.Diagnostics)["main.vcl"]
+			result, err := Apply([]byte(tt.input), fixes)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if diff := cmp.Diff(tt.expect, string(result.Source)); diff != "" {
+				t.Errorf("Fixed source mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
+
+func TestNameCandidatesViaLinter(t *testing.T) {
+	// Snake case is preferred and acronym is kept as a word
+	input := `sub vcl_recv {
+  #FASTLY RECV
+  declare local var.HTTPHeader STRING;
+  set req.http.Foo = var.HTTPHeader;
+}
+`
+	vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile("main.vcl"))).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	l := linter.New(linter.WithNamingConvention(&config.NamingConfig{
+		Variable: "^[a-z]+(_[a-z]+)*$",
+	}))
+	l.Lint(vcl, context.New())
+
+	for _, d := range l.Diagnostics {
+		if d.Rule != linter.NAMING_CONVENTION {
+			continue
+		}
+		if d.Fix == nil {
+			t.Errorf("Naming convention error should have fix")
+			return
+		}
+		if !strings.Contains(d.Fix.Message, "var.http_header") {
+			t.Errorf("Variable should be renamed to var.http_header, got %s", d.Fix.Message)
+		}
+		return
+	}
+	t.Errorf("Naming convention error is not reported")
+}
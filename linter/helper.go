@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ysugimoto/falco/annotation"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/context"
 	"github.com/ysugimoto/falco/types"
@@ -297,26 +298,6 @@ func expectState(cur string, expects ...string) bool {
 	return false
 }
 
-func annotations(comments ast.Comments) []string {
-	var rv []string
-	for i := range comments {
-		l := strings.TrimLeft(comments[i].Value, " */#")
-		if strings.HasPrefix(l, "@") {
-			var an []string
-			if strings.HasPrefix(l, "@scope:") {
-				an = strings.Split(strings.TrimPrefix(l, "@scope:"), ",")
-			} else {
-				an = strings.Split(strings.TrimPrefix(l, "@"), ",")
-			}
-			for _, s := range an {
-				rv = append(rv, strings.TrimSpace(s))
-			}
-		}
-	}
-
-	return rv
-}
-
 func getSubroutineCallScope(s *ast.SubroutineDeclaration) int {
 	// Detect phase from subroutine name
 	switch {
@@ -343,7 +324,8 @@ func getSubroutineCallScope(s *ast.SubroutineDeclaration) int {
 	// If could not via subroutine name, find by annotations
 	// typically defined is module file
 	scopes := 0
-	for _, a := range annotations(s.Leading) {
+	annotations, _ := annotation.Parse(s.Leading, annotation.Subroutine)
+	for _, a := range annotations.Values("scope") {
 		switch strings.ToUpper(a) {
 		case "RECV":
 			scopes |= context.RECV
@@ -395,17 +377,6 @@ func getFastlySubroutineScope(name string) string {
 	return ""
 }
 
-func hasFastlyBoilerPlateMacro(commentText, phrase string) bool {
-	comments := strings.Split(commentText, "\n")
-	for _, c := range comments {
-		c = strings.TrimLeft(c, " */#")
-		if strings.HasPrefix(strings.ToUpper(c), phrase) {
-			return true
-		}
-	}
-	return false
-}
-
 // According to fastly if a prober is configured with initial < threshold
 // then the prober will be marked as unhealthy at the beginning which is can
 // cause issues at startup.
@@ -462,27 +433,76 @@ func isTypeLiteral(node ast.Node) bool {
 	}
 }
 
-// Some HTTP Headers is protected in Fastly.
-// @see https://developer.fastly.com/reference/http/http-headers/
-// Consider the character case, we always treat header names as lower-case.
-var protectedHeaderNames = map[string]struct{}{
-	"req.http.proxy-authenticate":  {},
-	"req.http.proxy-authorization": {},
-	"req.http.content-length":      {},
-	"req.http.content-range":       {},
-	"req.http.te":                  {},
-	"req.http.trailer":             {},
-	"req.http.expect":              {},
-	"req.http.transfer-encoding":   {},
-	"req.http.upgrade":             {},
-	"req.http.fastly-ff":           {},
+// Collect all idents which are used in the expression recursively
+func collectIdents(exp ast.Expression) []*ast.Ident {
+	var idents []*ast.Ident
+
+	switch t := exp.(type) {
+	case *ast.Ident:
+		idents = append(idents, t)
+	case *ast.PrefixExpression:
+		idents = append(idents, collectIdents(t.Right)...)
+	case *ast.GroupedExpression:
+		idents = append(idents, collectIdents(t.Right)...)
+	case *ast.InfixExpression:
+		idents = append(idents, collectIdents(t.Left)...)
+		idents = append(idents, collectIdents(t.Right)...)
+	case *ast.IfExpression:
+		idents = append(idents, collectIdents(t.Condition)...)
+		idents = append(idents, collectIdents(t.Consequence)...)
+		idents = append(idents, collectIdents(t.Alternative)...)
+	case *ast.FunctionCallExpression:
+		for i := range t.Arguments {
+			idents = append(idents, collectIdents(t.Arguments[i])...)
+		}
+	}
+
+	return idents
 }
 
-// Check header name exists in protected header names
-func isProtectedHTTPHeaderName(name string) bool {
-	lower := strings.ToLower(name)
-	if _, ok := protectedHeaderNames[lower]; ok {
+// Walk all statements in the block recursively including nested if statement branches.
+// Walking is stopped when fn returns false.
+func walkStatements(block *ast.BlockStatement, fn func(stmt ast.Statement) bool) bool {
+	if block == nil {
 		return true
 	}
-	return false
+	for _, stmt := range block.Statements {
+		if !fn(stmt) {
+			return false
+		}
+		switch t := stmt.(type) {
+		case *ast.BlockStatement:
+			if !walkStatements(t, fn) {
+				return false
+			}
+		case *ast.IfStatement:
+			if !walkStatements(t.Consequence, fn) {
+				return false
+			}
+			for _, a := range t.Another {
+				if !walkStatements(a.Consequence, fn) {
+					return false
+				}
+			}
+			if !walkStatements(t.Alternative, fn) {
+				return false
+			}
+		case *ast.SwitchStatement:
+			for _, c := range t.Cases {
+				if !walkStatements(caseBlock(c), fn) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Statements of the case are not enclosed by braces,
+// wrap them with the block so that they could be treated as the branch like if statement.
+func caseBlock(c *ast.CaseStatement) *ast.BlockStatement {
+	return &ast.BlockStatement{
+		Meta:       c.Meta,
+		Statements: c.Statements,
+	}
 }
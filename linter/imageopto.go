@@ -0,0 +1,93 @@
+package linter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/imageopto"
+)
+
+// Lint x-fastly-imageopto-api header which enables Image Optimizer.
+// The header must be set in vcl_recv and the value must start with "fastly" like "fastly" or "fastly; qp=*".
+func (l *Linter) lintImageOptimizerHeader(ident *ast.Ident, value ast.Expression, ctx *context.Context) {
+	if !strings.EqualFold(ident.Value, "req.http."+imageopto.ApiHeader) {
+		return
+	}
+
+	if ctx.Mode()&context.RECV == 0 {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    ident.GetMeta().Token,
+			Message:  fmt.Sprintf("%s header should be set in vcl_recv, Image Optimizer is not enabled in other subroutines", ident.Value),
+		}
+		l.Error(err.Match(IMAGE_OPTIMIZER_API_HEADER))
+	}
+
+	if v, ok := value.(*ast.String); ok {
+		api, _, _ := strings.Cut(v.Value, ";")
+		if strings.TrimSpace(api) != "fastly" {
+			err := &LintError{
+				Severity: ERROR,
+				Token:    v.GetMeta().Token,
+				Message:  fmt.Sprintf(`%s header value must be "fastly" but got "%s"`, ident.Value, v.Value),
+			}
+			l.Error(err.Match(IMAGE_OPTIMIZER_API_HEADER))
+		}
+	}
+}
+
+// Lint Image Optimizer query parameters in URL literal like set req.url = "/image.jpg?width=abc"
+func (l *Linter) lintImageOptimizerURL(ident *ast.Ident, value ast.Expression) {
+	name := strings.ToLower(ident.Value)
+	if name != "req.url" && name != "bereq.url" {
+		return
+	}
+	v, ok := value.(*ast.String)
+	if !ok {
+		return
+	}
+	_, query, found := strings.Cut(v.Value, "?")
+	if !found {
+		return
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return
+	}
+	for key := range values {
+		l.lintImageOptimizerParameter(v, key, values.Get(key))
+	}
+}
+
+// Lint Image Optimizer query parameters which are manipulated by querystring functions
+func (l *Linter) lintImageOptimizerQueryFunction(exp *ast.FunctionCallExpression) {
+	switch exp.Function.Value {
+	case "querystring.set", "querystring.add":
+	default:
+		return
+	}
+	if len(exp.Arguments) != 3 {
+		return
+	}
+	key, ok := exp.Arguments[1].(*ast.String)
+	if !ok {
+		return
+	}
+	if v, ok := exp.Arguments[2].(*ast.String); ok {
+		l.lintImageOptimizerParameter(v, key.Value, v.Value)
+	}
+}
+
+func (l *Linter) lintImageOptimizerParameter(node ast.Node, key, value string) {
+	if err := imageopto.Validate(key, value); err != nil {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    node.GetMeta().Token,
+			Message:  err.Error(),
+		}
+		l.Error(err.Match(IMAGE_OPTIMIZER_QUERY))
+	}
+}
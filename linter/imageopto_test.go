@@ -0,0 +1,53 @@
+package linter
+
+import (
+	"testing"
+)
+
+func TestLintImageOptimizer(t *testing.T) {
+	t.Run("enable in vcl_recv", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.http.x-fastly-imageopto-api = "fastly; qp=*";
+   set req.url = querystring.set(req.url, "width", "300");
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("enable outside vcl_recv", func(t *testing.T) {
+		input := `
+sub vcl_miss {
+   #FASTLY MISS
+   set req.http.x-fastly-imageopto-api = "fastly";
+}`
+		assertErrorWithSeverity(t, input, WARNING)
+	})
+
+	t.Run("invalid api value", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.http.X-Fastly-Imageopto-Api = "imgix";
+}`
+		assertErrorWithSeverity(t, input, ERROR)
+	})
+
+	t.Run("invalid parameter in querystring function", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.url = querystring.set(req.url, "fit", "contain");
+}`
+		assertErrorWithSeverity(t, input, WARNING)
+	})
+
+	t.Run("invalid parameter in URL literal", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.url = "/image.jpg?width=abc&foo=bar";
+}`
+		assertErrorWithSeverity(t, input, WARNING)
+	})
+}
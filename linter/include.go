@@ -0,0 +1,262 @@
+package linter
+
+import (
+	"crypto/sha256"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Included module is parsed as root VCL or snippet VCL depending on the place of include statement,
+// so the cache key needs both of module name and parse mode
+type includeKey struct {
+	module string
+	isRoot bool
+}
+
+// Resolved and parsed result of included module
+type includeModule struct {
+	done chan struct{}
+
+	name       string
+	lexer      *lexer.Lexer
+	statements []ast.Statement
+	// Error of module resolution
+	resolveErr error
+	// Error of parsing module
+	parseErr error
+}
+
+// includeCache memoizes parsed modules in order to parse shared modules only once,
+// and modules can be parsed concurrently with preloading.
+type includeCache struct {
+	mu      sync.Mutex
+	modules map[includeKey]*includeModule
+	// Semaphore to limit concurrent parsing
+	sem chan struct{}
+	// Parsed modules which are shared with other linters, may be nil
+	shared *ModuleCache
+}
+
+func newIncludeCache() *includeCache {
+	return &includeCache{
+		modules: make(map[includeKey]*includeModule),
+		sem:     make(chan struct{}, runtime.GOMAXPROCS(0)),
+	}
+}
+
+// Get cached module or register new module. Second return value is true when the module is newly registered,
+// then caller must load the module and close done channel.
+func (c *includeCache) acquire(key includeKey) (*includeModule, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if m, ok := c.modules[key]; ok {
+		return m, false
+	}
+	m := &includeModule{done: make(chan struct{})}
+	c.modules[key] = m
+	return m, true
+}
+
+// Resolve and parse module synchronously if it has not been preloaded, or wait for preloading
+func (c *includeCache) load(key includeKey, r resolver.Resolver) *includeModule {
+	m, ok := c.acquire(key)
+	if ok {
+		m.load(key, r, c.shared)
+	}
+	<-m.done
+	return m
+}
+
+func (m *includeModule) load(key includeKey, r resolver.Resolver, shared *ModuleCache) {
+	defer close(m.done)
+
+	module, err := r.Resolve(&ast.IncludeStatement{
+		Module: &ast.String{Value: key.module},
+	})
+	if err != nil {
+		m.resolveErr = err
+		return
+	}
+	m.name = module.Name
+
+	var parsed *parsedModule
+	if shared != nil {
+		parsed = shared.parse(module, key.isRoot)
+	} else {
+		parsed = parseModule(module, key.isRoot)
+	}
+	m.lexer = parsed.lexer
+	m.statements = parsed.statements
+	m.parseErr = parsed.err
+}
+
+// Parsed result of resolved module, it is never modified after parsing so that it can be shared between linters
+type parsedModule struct {
+	done chan struct{}
+
+	lexer      *lexer.Lexer
+	statements []ast.Statement
+	err        error
+}
+
+func parseModule(module *resolver.VCL, isRoot bool) *parsedModule {
+	m := &parsedModule{
+		lexer: lexer.NewFromString(module.Data, lexer.WithFile(module.Name)),
+	}
+
+	var err error
+	p := parser.New(m.lexer)
+	if isRoot {
+		var vcl *ast.VCL
+		if vcl, err = p.ParseVCL(); err == nil {
+			m.statements = vcl.Statements
+		}
+	} else {
+		m.statements, err = p.ParseSnippetVCL()
+	}
+	if err != nil {
+		m.lexer.NewLine()
+		m.err = errors.Cause(err)
+		m.statements = []ast.Statement{}
+	}
+	return m
+}
+
+// Shared modules are keyed by resolved file name and the content,
+// then entry VCLs which resolve the same module name to the different file never share the module
+type moduleKey struct {
+	name   string
+	isRoot bool
+	sum    [sha256.Size]byte
+}
+
+// ModuleCache shares parsed modules between linters, like multiple entry VCLs of multi-service repository
+// which include the same files. It is safe for concurrent use.
+type ModuleCache struct {
+	mu      sync.Mutex
+	modules map[moduleKey]*parsedModule
+}
+
+func NewModuleCache() *ModuleCache {
+	return &ModuleCache{
+		modules: make(map[moduleKey]*parsedModule),
+	}
+}
+
+// Parse module once, other callers which parse the same module wait for the result
+func (c *ModuleCache) parse(module *resolver.VCL, isRoot bool) *parsedModule {
+	key := moduleKey{
+		name:   module.Name,
+		isRoot: isRoot,
+		sum:    sha256.Sum256([]byte(module.Data)),
+	}
+
+	c.mu.Lock()
+	m, ok := c.modules[key]
+	if !ok {
+		m = &parsedModule{done: make(chan struct{})}
+		c.modules[key] = m
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		parsed := parseModule(module, isRoot)
+		m.lexer, m.statements, m.err = parsed.lexer, parsed.statements, parsed.err
+		close(m.done)
+	}
+	<-m.done
+	return m
+}
+
+// Len returns the number of parsed modules in the cache
+func (c *ModuleCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.modules)
+}
+
+// preload resolves and parses all modules in the include graph concurrently before linting.
+// Linting still merges modules in the order of include statements so the result is deterministic.
+func (c *includeCache) preload(statements []ast.Statement, r resolver.Resolver) {
+	var wg sync.WaitGroup
+	c.preloadStatements(&wg, statements, r, true)
+	wg.Wait()
+}
+
+func (c *includeCache) preloadStatements(wg *sync.WaitGroup, statements []ast.Statement, r resolver.Resolver, isRoot bool) {
+	// Include statements in subroutine are parsed as snippet
+	preloadBlock := func(block *ast.BlockStatement) {
+		walkStatements(block, func(s ast.Statement) bool {
+			if include, ok := s.(*ast.IncludeStatement); ok {
+				c.preloadModule(wg, include, r, false)
+			}
+			return true
+		})
+	}
+
+	if !isRoot {
+		preloadBlock(&ast.BlockStatement{Statements: statements})
+		return
+	}
+	for _, stmt := range statements {
+		switch t := stmt.(type) {
+		case *ast.IncludeStatement:
+			c.preloadModule(wg, t, r, true)
+		case *ast.SubroutineDeclaration:
+			preloadBlock(t.Block)
+		}
+	}
+}
+
+func (c *includeCache) preloadModule(wg *sync.WaitGroup, include *ast.IncludeStatement, r resolver.Resolver, isRoot bool) {
+	// Fastly managed snippets are already in memory
+	if strings.HasPrefix(include.Module.Value, "snippet::") {
+		return
+	}
+	key := includeKey{module: include.Module.Value, isRoot: isRoot}
+	m, ok := c.acquire(key)
+	if !ok {
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		c.sem <- struct{}{}
+		m.load(key, r, c.shared)
+		<-c.sem
+
+		// Preload nested modules
+		c.preloadStatements(wg, m.statements, r, isRoot)
+	}()
+}
+
+// Find include chain which includes the module again from the module itself.
+// The chain is traced by parent include statements of the files, and ordered from the outermost include statement.
+// Returns nil if the include statement does not make a cycle.
+func (l *Linter) includeCycle(include *ast.IncludeStatement, module string) []*ast.IncludeStatement {
+	chain := []*ast.IncludeStatement{include}
+	file := include.GetMeta().Token.File
+	// Parents could not be longer than the count of included modules
+	for i := 0; i <= len(l.includeParents); i++ {
+		if file == module {
+			return chain
+		}
+		parent, ok := l.includeParents[file]
+		if !ok {
+			return nil
+		}
+		chain = append([]*ast.IncludeStatement{parent}, chain...)
+		file = parent.GetMeta().Token.File
+	}
+	return nil
+}
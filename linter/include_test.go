@@ -0,0 +1,207 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+type countingResolver struct {
+	mockResolver
+	mu    sync.Mutex
+	count map[string]int
+}
+
+func (c *countingResolver) Resolve(stmt *ast.IncludeStatement) (*resolver.VCL, error) {
+	c.mu.Lock()
+	c.count[stmt.Module.Value]++
+	c.mu.Unlock()
+	return c.mockResolver.Resolve(stmt)
+}
+
+func TestParallelIncludeResolution(t *testing.T) {
+	const size = 50
+
+	dependency := map[string]string{
+		"shared": `set req.http.Shared = "1";`,
+	}
+	var main strings.Builder
+	for i := 0; i < size; i++ {
+		name := fmt.Sprintf("mod%02d", i)
+		// Assigning INTEGER to header is reported as lint error in each module
+		dependency[name] = fmt.Sprintf(`
+sub %s {
+  include "shared";
+  set req.http.%s = %d;
+}`, name, name, i)
+		main.WriteString(fmt.Sprintf("include \"%s\";\n", name))
+	}
+	main.WriteString("sub vcl_recv {\n  #FASTLY RECV\n")
+	for i := 0; i < size; i++ {
+		main.WriteString(fmt.Sprintf("  call mod%02d;\n", i))
+	}
+	main.WriteString("}\n")
+
+	var expect []string
+	for i := 0; i < 3; i++ {
+		vcl, err := parser.New(lexer.NewFromString(main.String())).ParseVCL()
+		if err != nil {
+			t.Errorf("Unexpected parser error: %s", err)
+			return
+		}
+		r := &countingResolver{
+			mockResolver: mockResolver{dependency: dependency},
+			count:        make(map[string]int),
+		}
+		l := New()
+		l.lint(vcl, context.New(context.WithResolver(r)))
+		if l.FatalError != nil {
+			t.Errorf("Unexpected fatal error: %s", l.FatalError.Error)
+			return
+		}
+
+		for name, count := range r.count {
+			if count != 1 {
+				t.Errorf("Module %s should be resolved only once but resolved %d times", name, count)
+			}
+		}
+		if len(r.count) != size+1 {
+			t.Errorf("All modules should be resolved, expects=%d but got=%d", size+1, len(r.count))
+		}
+
+		var actual []string
+		for _, d := range l.Diagnostics {
+			actual = append(actual, fmt.Sprintf("%s:%d", d.Token.File, d.Token.Line))
+		}
+		if len(actual) != size {
+			t.Errorf("Lint errors should be reported for each module, expects=%d but got=%d", size, len(actual))
+		}
+		// Lint results must be the same order of include statements regardless of parsing order
+		if expect == nil {
+			expect = actual
+			for j := 0; j < size; j++ {
+				if actual[j] != fmt.Sprintf("mod%02d.vcl:4", j) {
+					t.Errorf("Lint errors should be ordered by include statements, got %v", actual)
+					return
+				}
+			}
+		} else if diff := cmp.Diff(expect, actual); diff != "" {
+			t.Errorf("Lint errors are not deterministic, diff=%s", diff)
+		}
+	}
+}
+
+func TestParallelIncludeResolutionError(t *testing.T) {
+	mock := &mockResolver{
+		dependency: map[string]string{
+			"deps01": `include "deps02";`,
+			"deps02": `sub foo {`,
+		},
+	}
+	input := `
+include "deps01";
+include "deps03";
+
+sub vcl_recv {
+   #FASTLY RECV
+}
+`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	l := New()
+	l.lint(vcl, context.New(context.WithResolver(mock)))
+	if l.FatalError == nil || l.FatalError.Lexer == nil {
+		t.Errorf("Parse error of nested module should be reported as fatal error")
+	}
+	if len(l.Errors) != 1 {
+		t.Errorf("Unresolved module should be reported, got %v", l.Errors)
+	}
+}
+
+func TestIncludeCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependency map[string]string
+		input      string
+		expect     []string
+	}{
+		{
+			name: "shared module is not a cycle",
+			dependency: map[string]string{
+				"mod01":  `include "shared";`,
+				"mod02":  `include "shared";`,
+				"shared": `acl internal { "127.0.0.1"; }`,
+			},
+			input: `
+include "mod01";
+include "mod02";
+sub vcl_recv {
+  #FASTLY RECV
+  if (client.ip ~ internal) {
+    return(pass);
+  }
+}`,
+		},
+		{
+			name: "root modules include each other",
+			dependency: map[string]string{
+				"mod01": `include "mod02";`,
+				"mod02": `include "mod01";`,
+			},
+			input: `
+include "mod01";
+sub vcl_recv {
+  #FASTLY RECV
+}`,
+			expect: []string{"Include cycle detected: mod01.vcl -> mod02.vcl -> mod01.vcl"},
+		},
+		{
+			name: "module includes itself in subroutine",
+			dependency: map[string]string{
+				"snippet": `
+if (req.http.X-Retry) {
+  include "snippet";
+}`,
+			},
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  include "snippet";
+}`,
+			expect: []string{"Include cycle detected: snippet.vcl -> snippet.vcl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("Unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New(context.WithResolver(&mockResolver{dependency: tt.dependency})))
+
+			var actual []string
+			for _, d := range l.Diagnostics {
+				if d.Rule == INCLUDE_STATEMENT_CYCLE {
+					actual = append(actual, d.Message)
+				}
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Include cycle errors mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package linter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JUnitGroup specifies how diagnostics are grouped into JUnit test cases
+type JUnitGroup string
+
+const (
+	JUnitGroupByFile JUnitGroup = "file"
+	JUnitGroupByRule JUnitGroup = "rule"
+)
+
+// ParseJUnitGroup parses group name which is specified in the configuration, file is used when empty
+func ParseJUnitGroup(v string) (JUnitGroup, error) {
+	switch JUnitGroup(strings.ToLower(v)) {
+	case "", JUnitGroupByFile:
+		return JUnitGroupByFile, nil
+	case JUnitGroupByRule:
+		return JUnitGroupByRule, nil
+	}
+	return "", fmt.Errorf(`Unknown JUnit group "%s", group must be one of file, rule`, v)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML so that CI services could display lint results in their test UI.
+// Each file or rule becomes a test case, which fails when it has error diagnostics.
+// Warnings and recommendations are written to system-out of the test case.
+func (r *Report) WriteJUnit(w io.Writer, group JUnitGroup) error {
+	groups := map[string][]*ReportDiagnostic{}
+	for _, d := range r.Diagnostics {
+		key := d.File
+		if group == JUnitGroupByRule {
+			key = string(d.Rule)
+			// Parse errors and errors reported by external policy engine may not have the rule
+			if key == "" {
+				key = "syntax"
+			}
+		}
+		groups[key] = append(groups[key], d)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	suite := &junitTestSuite{Name: "falco lint"}
+	for _, key := range keys {
+		suite.Cases = append(suite.Cases, junitCase(key, group, groups[key]))
+	}
+	// Test case should exist in order to be recognized as passed
+	if len(suite.Cases) == 0 {
+		suite.Cases = append(suite.Cases, &junitTestCase{
+			Name:      "lint",
+			ClassName: "falco.lint",
+		})
+	}
+	suite.Tests = len(suite.Cases)
+	for _, c := range suite.Cases {
+		if c.Failure != nil {
+			suite.Failures++
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithStack(err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&junitTestSuites{
+		Name:     "falco",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []*junitTestSuite{suite},
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return errors.WithStack(err)
+}
+
+func junitCase(name string, group JUnitGroup, diagnostics []*ReportDiagnostic) *junitTestCase {
+	c := &junitTestCase{
+		Name:      name,
+		ClassName: "falco.lint",
+	}
+	if group == JUnitGroupByRule && len(diagnostics) > 0 && diagnostics[0].Category != "" {
+		c.ClassName += "." + string(diagnostics[0].Category)
+	}
+
+	var failures, others []string
+	for _, d := range diagnostics {
+		line := fmt.Sprintf(
+			"%s:%d:%d [%s] %s",
+			d.File, d.Span.Start.Line, d.Span.Start.Column, d.Severity, d.Message,
+		)
+		if d.Rule != "" {
+			line += " (" + string(d.Rule) + ")"
+		}
+		if d.Severity == strings.ToLower(string(ERROR)) {
+			failures = append(failures, line)
+		} else {
+			others = append(others, line)
+		}
+	}
+	if len(failures) > 0 {
+		c.Failure = &junitFailure{
+			Message: fmt.Sprintf("%d lint errors", len(failures)),
+			Type:    "lint",
+			Body:    strings.Join(failures, "\n"),
+		}
+	}
+	if len(others) > 0 {
+		c.SystemOut = strings.Join(others, "\n")
+	}
+	return c
+}
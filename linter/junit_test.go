@@ -0,0 +1,101 @@
+package linter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	report := &Report{
+		Diagnostics: []*ReportDiagnostic{
+			{
+				Rule:     ACL_DUPLICATED,
+				Category: CORRECTNESS,
+				Severity: "error",
+				File:     "main.vcl",
+				Span:     ReportSpan{Start: ReportPosition{Line: 5, Column: 5}},
+				Message:  `Duplicate definition of ACL "internal"`,
+			},
+			{
+				Rule:     UNUSED_VARIABLE,
+				Category: STYLE,
+				Severity: "warning",
+				File:     "main.vcl",
+				Span:     ReportSpan{Start: ReportPosition{Line: 10, Column: 3}},
+				Message:  `Variable "foo" is unused`,
+			},
+			{
+				Rule:     UNUSED_VARIABLE,
+				Category: STYLE,
+				Severity: "warning",
+				File:     "recv.vcl",
+				Span:     ReportSpan{Start: ReportPosition{Line: 2, Column: 3}},
+				Message:  `Variable "bar" is unused`,
+			},
+		},
+	}
+
+	t.Run("group by file", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := report.WriteJUnit(&buf, JUnitGroupByFile); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="falco" tests="2" failures="1">
+  <testsuite name="falco lint" tests="2" failures="1" errors="0">
+    <testcase name="main.vcl" classname="falco.lint">
+      <failure message="1 lint errors" type="lint">main.vcl:5:5 [error] Duplicate definition of ACL &#34;internal&#34; (acl/duplicated)</failure>
+      <system-out>main.vcl:10:3 [warning] Variable &#34;foo&#34; is unused (unused/variable)</system-out>
+    </testcase>
+    <testcase name="recv.vcl" classname="falco.lint">
+      <system-out>recv.vcl:2:3 [warning] Variable &#34;bar&#34; is unused (unused/variable)</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+		if diff := cmp.Diff(expect, buf.String()); diff != "" {
+			t.Errorf("JUnit XML mismatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("group by rule", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := report.WriteJUnit(&buf, JUnitGroupByRule); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="falco" tests="2" failures="1">
+  <testsuite name="falco lint" tests="2" failures="1" errors="0">
+    <testcase name="acl/duplicated" classname="falco.lint.correctness">
+      <failure message="1 lint errors" type="lint">main.vcl:5:5 [error] Duplicate definition of ACL &#34;internal&#34; (acl/duplicated)</failure>
+    </testcase>
+    <testcase name="unused/variable" classname="falco.lint.style">
+      <system-out>main.vcl:10:3 [warning] Variable &#34;foo&#34; is unused (unused/variable)&#xA;recv.vcl:2:3 [warning] Variable &#34;bar&#34; is unused (unused/variable)</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+		if diff := cmp.Diff(expect, buf.String()); diff != "" {
+			t.Errorf("JUnit XML mismatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("no diagnostics", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&Report{}).WriteJUnit(&buf, JUnitGroupByFile); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="falco" tests="1" failures="0">
+  <testsuite name="falco lint" tests="1" failures="0" errors="0">
+    <testcase name="lint" classname="falco.lint"></testcase>
+  </testsuite>
+</testsuites>
+`
+		if diff := cmp.Diff(expect, buf.String()); diff != "" {
+			t.Errorf("JUnit XML mismatch, diff=%s", diff)
+		}
+	})
+}
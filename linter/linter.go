@@ -4,30 +4,96 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/annotation"
 	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/config"
 	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/crash"
+	"github.com/ysugimoto/falco/i18n"
 	"github.com/ysugimoto/falco/lexer"
 	"github.com/ysugimoto/falco/parser"
 	"github.com/ysugimoto/falco/snippets"
 	"github.com/ysugimoto/falco/token"
 	"github.com/ysugimoto/falco/types"
-	regexp "go.elara.ws/pcre"
 )
 
 type Linter struct {
-	Errors         []error
+	Diagnostics []*Diagnostic
+	// Errors holds the same values of Diagnostics as error interface.
+	// Deprecated: kept for backward compatibility, use Diagnostics instead.
+	Errors []error
+
 	FatalError     *FatalError
 	includexLexers map[string]*lexer.Lexer
+	includes       *includeCache
 	ignore         *ignore
-}
-
-func New() *Linter {
-	return &Linter{
+	// Injection sites of Fastly managed snippets, keyed by snippet file name
+	snippetSites map[string]token.Token
+	// Latest include statement which includes the module, keyed by resolved module name
+	includeParents map[string]*ast.IncludeStatement
+
+	// Stack of if conditions which encloses the current statement
+	conditions     []ast.Expression
+	debugHeader    *config.DebugHeaderConfig
+	compliance     *config.ComplianceConfig
+	categories     *categoryFilter
+	severities     map[Rule]Severity
+	customRules    []CustomRule
+	naming         namingPatterns
+	policies       []*policy
+	facts          *Facts
+	complexity     *config.ComplexityConfig
+	tableLimits    *config.TableLimitsConfig
+	metrics        []*SubroutineMetrics
+	computeHosts   hostPatterns
+	serviceDomains hostPatterns
+	protected      protectedHeaders
+	explainFatal   bool
+	failFast       bool
+	// Linting is stopped by the first error in fail-fast mode
+	stopped  bool
+	profiler *ruleProfiler
+	// Path of nodes which are being linted
+	path []ast.Node
+	// Capture group count of the latest regex matching in the current flow path
+	regexGroups int
+	// Estimated usage of Fastly compilation limits
+	compilationLimits *config.CompilationLimitsConfig
+	compilation       compilationEstimate
+	// Annotations which are recognized, builtin and registered annotations are cloned not to share registrations by option
+	annotations *annotation.Registry
+}
+
+func New(options ...Option) *Linter {
+	l := &Linter{
 		includexLexers: make(map[string]*lexer.Lexer),
+		includes:       newIncludeCache(),
 		ignore:         &ignore{},
+		snippetSites:   make(map[string]token.Token),
+		includeParents: make(map[string]*ast.IncludeStatement),
+		customRules:    RegisteredRules(),
+		protected:      defaultProtectedHeaders(),
+		regexGroups:    regexGroupsNone,
+		profiler:       newRuleProfiler(),
+		annotations:    annotation.Default().Clone(),
+	}
+	for i := range options {
+		options[i](l)
 	}
+	return l
+}
+
+// Metrics returns complexity metrics of linted subroutines
+func (l *Linter) Metrics() []*SubroutineMetrics {
+	return l.metrics
+}
+
+// Facts returns collected facts about linted VCL, returns nil unless linter is created with WithFacts option
+func (l *Linter) Facts() *Facts {
+	return l.facts
 }
 
 func (l *Linter) Lexers() map[string]*lexer.Lexer {
@@ -35,16 +101,44 @@ func (l *Linter) Lexers() map[string]*lexer.Lexer {
 }
 
 func (l *Linter) Error(err error) {
-	if le, ok := err.(*LintError); ok {
-		if !l.ignore.IsEnable() {
-			l.Errors = append(l.Errors, le)
-		}
-	} else {
-		l.Errors = append(l.Errors, &LintError{
+	if l.stopped {
+		return
+	}
+	d, ok := err.(*Diagnostic)
+	if !ok {
+		d = &Diagnostic{
 			Severity: ERROR,
 			Token:    token.Null,
 			Message:  err.Error(),
-		})
+		}
+	} else if l.ignore.IsEnable() {
+		return
+	}
+	if !l.categories.allow(d.Rule) {
+		return
+	}
+	if v, ok := l.severities[d.Rule]; ok {
+		if v == IGNORE {
+			return
+		}
+		d.Severity = v
+	}
+	// Diagnostics in Fastly managed snippet should point where the snippet is injected
+	if site, ok := l.snippetSites[d.Token.File]; ok {
+		d.Relate(site, "Snippet is injected here")
+	}
+	// Translate messages for the configured language, rule name is kept as it is
+	d.Message = i18n.Translate(d.Message)
+	for i := range d.Related {
+		d.Related[i].Message = i18n.Translate(d.Related[i].Message)
+	}
+	if d.Fix != nil {
+		d.Fix.Message = i18n.Translate(d.Fix.Message)
+	}
+	l.Diagnostics = append(l.Diagnostics, d)
+	l.Errors = append(l.Errors, d)
+	if l.failFast && d.Severity == ERROR {
+		l.stopped = true
 	}
 }
 
@@ -54,17 +148,42 @@ func (l *Linter) Lint(node ast.Node, ctx *context.Context) types.Type {
 	if ctx == nil {
 		ctx = context.New()
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			panic(crash.Wrap(r, l.path...))
+		}
+	}()
 
 	l.lint(node, ctx)
+	if l.stopped {
+		return types.NeverType
+	}
 
 	// After whole VCLs have been linted in main VCL, check all definitions are exactly used.
+	start := time.Now()
 	l.lintUnusedTables(ctx)
 	l.lintUnusedAcls(ctx)
 	l.lintUnusedBackends(ctx)
 	l.lintUnusedSubroutines(ctx)
+	l.profiler.record(UNUSED_DECLARATION, start)
+	start = time.Now()
 	l.lintUnusedGotos(ctx)
+	l.profiler.record(UNUSED_GOTO, start)
+	start = time.Now()
 	l.lintUnusedPenaltyboxes(ctx)
 	l.lintUnusedRatecounters(ctx)
+	l.profiler.record(UNUSED_DECLARATION, start)
+
+	// Flow analysis which needs all subroutines
+	start = time.Now()
+	l.lintRestartLoops(ctx)
+	l.profiler.record(RESTART_STATEMENT_LOOP, start)
+	start = time.Now()
+	l.lintCachePoisoning(ctx)
+	l.profiler.record(CACHE_POISONING, start)
+	start = time.Now()
+	l.lintIncludedSourceEncodings()
+	l.profiler.record(SOURCE_ENCODING, start)
 
 	return types.NeverType
 }
@@ -143,7 +262,7 @@ func (l *Linter) lintUnusedRatecounters(ctx *context.Context) {
 	}
 }
 
-func (l *Linter) lintUnusedVariables(ctx *context.Context) {
+func (l *Linter) lintUnusedVariables(sub *ast.SubroutineDeclaration, ctx *context.Context) {
 	v, ok := ctx.Variables["var"]
 	if !ok {
 		return
@@ -153,7 +272,9 @@ func (l *Linter) lintUnusedVariables(ctx *context.Context) {
 		if o.IsUsed {
 			continue
 		}
-		l.Error(UnusedVariable(o.Meta, k).Match(UNUSED_VARIABLE))
+		err := UnusedVariable(o.Meta, k)
+		err.Fix = unusedVariableFix(sub, o.Meta)
+		l.Error(err.Match(UNUSED_VARIABLE))
 	}
 }
 
@@ -168,6 +289,23 @@ func (l *Linter) lintUnusedGotos(ctx *context.Context) {
 }
 
 func (l *Linter) lint(node ast.Node, ctx *context.Context) types.Type {
+	if l.stopped {
+		return types.NeverType
+	}
+	// Path is not popped when the linter panics, so that the crash report could tell which node causes it
+	l.path = append(l.path, node)
+	l.profiler.enter(node)
+	t := l.lintNode(node, ctx)
+	l.profiler.leave()
+	l.path = l.path[:len(l.path)-1]
+	return t
+}
+
+func (l *Linter) lintNode(node ast.Node, ctx *context.Context) types.Type {
+	l.lintPolicies(node, ctx)
+	l.lintCustomRules(node, ctx)
+	l.collectFacts(node, ctx)
+
 	switch t := node.(type) {
 	// Root program
 	case *ast.VCL:
@@ -176,7 +314,7 @@ func (l *Linter) lint(node ast.Node, ctx *context.Context) types.Type {
 	// Declarations
 	// Note: root declaration has already added in linter context.
 	case *ast.AclDeclaration:
-		return l.lintAclDeclaration(t)
+		return l.lintAclDeclaration(t, ctx)
 	case *ast.BackendDeclaration:
 		return l.lintBackendDeclaration(t, ctx)
 	case *ast.DirectorDeclaration:
@@ -207,6 +345,11 @@ func (l *Linter) lint(node ast.Node, ctx *context.Context) types.Type {
 		return l.lintRemoveStatement(t, ctx)
 	case *ast.IfStatement:
 		return l.lintIfStatement(t, ctx)
+	case *ast.SwitchStatement:
+		return l.lintSwitchStatement(t, ctx)
+	case *ast.BreakStatement, *ast.FallthroughStatement:
+		// Position of break and fallthrough statements are checked in switch statement
+		return types.NeverType
 	case *ast.RestartStatement:
 		return l.lintRestartStatement(t, ctx)
 	case *ast.EsiStatement:
@@ -264,7 +407,10 @@ func (l *Linter) lint(node ast.Node, ctx *context.Context) types.Type {
 }
 
 func (l *Linter) lintVCL(vcl *ast.VCL, ctx *context.Context) types.Type {
-	// Resolve module, snippet inclusion
+	l.compilation = compilationEstimate{}
+
+	// Parse all included modules concurrently, and then resolve module, snippet inclusion in order
+	l.includes.preload(vcl.Statements, ctx.Resolver())
 	statements := l.resolveIncludeStatements(vcl.Statements, ctx, true)
 
 	// https://github.com/ysugimoto/falco/issues/50
@@ -273,8 +419,13 @@ func (l *Linter) lintVCL(vcl *ast.VCL, ctx *context.Context) types.Type {
 
 	// Lint each statement/declaration logics
 	for _, s := range statements {
+		if l.explainFatal {
+			l.lintStatementWithRecover(s, ctx)
+			continue
+		}
 		l.lintStatement(s, ctx)
 	}
+	l.lintCompilationLimits(statements)
 
 	return types.NeverType
 }
@@ -329,9 +480,14 @@ func (l *Linter) resolveIncludeStatements(statements []ast.Statement, ctx *conte
 		// Check snippet inclusion
 		if strings.HasPrefix(include.Module.Value, "snippet::") {
 			resolved = append(resolved, l.resolveSnippetInclusion(include, ctx, isRoot)...)
-			continue
+		} else {
+			resolved = append(resolved, l.resolveFileInclusion(include, ctx, isRoot)...)
+		}
+
+		// Record the include statement which causes fatal error, nested inclusion records the innermost one
+		if l.FatalError != nil && l.FatalError.Statement == nil {
+			l.FatalError.Statement = include
 		}
-		resolved = append(resolved, l.resolveFileInclusion(include, ctx, isRoot)...)
 	}
 
 	return resolved
@@ -360,6 +516,8 @@ func (l *Linter) resolveSnippetInclusion(
 		return statements
 	}
 
+	l.snippetSites[include.Module.Value] = include.GetMeta().Token
+
 	// snippet could not have nested include statement
 	if isRoot {
 		return l.loadVCL(include.Module.Value, snip.Data)
@@ -375,23 +533,36 @@ func (l *Linter) resolveFileInclusion(
 ) []ast.Statement {
 
 	var statements []ast.Statement
-	module, err := ctx.Restore().Resolver().Resolve(include)
-	if err != nil {
+	// Module may be already parsed by preloading or other include statement
+	module := l.includes.load(
+		includeKey{module: include.Module.Value, isRoot: isRoot},
+		ctx.Restore().Resolver(),
+	)
+	if module.resolveErr != nil {
 		e := &LintError{
 			Severity: ERROR,
 			Token:    include.GetMeta().Token,
-			Message:  err.Error(),
+			Message:  module.resolveErr.Error(),
 		}
 		l.Error(e.Match(INCLUDE_STATEMENT_MODULE_LOAD_FAILED))
 		return statements
 	}
 
-	if isRoot {
-		statements = l.loadVCL(module.Name, module.Data)
-	} else {
-		statements = l.loadSnippetVCL(module.Name, module.Data)
+	if chain := l.includeCycle(include, module.name); chain != nil {
+		l.Error(IncludeCycle(chain, module.name).Match(INCLUDE_STATEMENT_CYCLE))
+		return statements
+	}
+	l.includeParents[module.name] = include
+
+	l.includexLexers[module.name] = module.lexer
+	if module.parseErr != nil {
+		l.FatalError = &FatalError{
+			Lexer: module.lexer,
+			Error: module.parseErr,
+		}
+		return statements
 	}
-	return l.resolveIncludeStatements(statements, ctx, isRoot)
+	return l.resolveIncludeStatements(module.statements, ctx, isRoot)
 }
 
 //nolint:gocognit,funlen
@@ -406,6 +577,9 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 					Token:    t.Name.GetMeta().Token,
 					Message:  err.Error(),
 				}
+				if first, ok := ctx.Acls[t.Name.Value]; ok && first.Decl != nil {
+					relateFirstDeclaration(e, first.Decl.GetMeta().Token)
+				}
 				l.Error(e.Match(ACL_DUPLICATED))
 			}
 			factory = append(factory, stmt)
@@ -416,6 +590,9 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 					Token:    t.Name.GetMeta().Token,
 					Message:  err.Error(),
 				}
+				if first, ok := backendTokens(ctx)[t.Name.Value]; ok {
+					relateFirstDeclaration(e, first)
+				}
 				l.Error(e.Match(BACKEND_DUPLICATED))
 			}
 			factory = append(factory, stmt)
@@ -429,6 +606,9 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 					Token:    t.Name.GetMeta().Token,
 					Message:  err.Error(),
 				}
+				if first, ok := ctx.Directors[t.Name.Value]; ok && first.Decl != nil {
+					relateFirstDeclaration(e, first.Decl.GetMeta().Token)
+				}
 				l.Error(e.Match(DIRECTOR_DUPLICATED))
 			}
 			factory = append(factory, stmt)
@@ -459,6 +639,9 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 					Token:    t.Name.GetMeta().Token,
 					Message:  err.Error(),
 				}
+				if first, ok := ctx.Tables[t.Name.Value]; ok && first.Decl != nil {
+					relateFirstDeclaration(e, first.Decl.GetMeta().Token)
+				}
 				l.Error(e.Match(TABLE_DUPLICATED))
 			}
 			factory = append(factory, stmt)
@@ -489,6 +672,9 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 						Token:    t.Name.GetMeta().Token,
 						Message:  err.Error(),
 					}
+					if first, ok := ctx.Subroutines[t.Name.Value]; ok && first.Decl != nil {
+						relateFirstDeclaration(err, first.Decl.GetMeta().Token)
+					}
 					l.Error(err.Match(SUBROUTINE_DUPLICATED))
 				}
 			} else {
@@ -499,6 +685,9 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 						Token:    t.Name.GetMeta().Token,
 						Message:  err.Error(),
 					}
+					if first, ok := ctx.Subroutines[t.Name.Value]; ok && first.Decl != nil {
+						relateFirstDeclaration(e, first.Decl.GetMeta().Token)
+					}
 					l.Error(e.Match(SUBROUTINE_DUPLICATED))
 				}
 			}
@@ -530,11 +719,12 @@ func (l *Linter) factoryRootDeclarations(statements []ast.Statement, ctx *contex
 	return factory
 }
 
-func (l *Linter) lintAclDeclaration(decl *ast.AclDeclaration) types.Type {
+func (l *Linter) lintAclDeclaration(decl *ast.AclDeclaration, ctx *context.Context) types.Type {
 	// validate ACL name
 	if !isValidName(decl.Name.Value) {
 		l.Error(InvalidName(decl.Name.GetMeta(), decl.Name.Value, "acl").Match(ACL_SYNTAX))
 	}
+	l.lintNamingConvention(decl.Name, "acl", ctx)
 
 	// CIDRs validity
 	for _, cidr := range decl.CIDRs {
@@ -554,6 +744,7 @@ func (l *Linter) lintAclDeclaration(decl *ast.AclDeclaration) types.Type {
 			l.Error(InvalidValue(cidr.GetMeta(), "CIDR", c).Match(ACL_SYNTAX))
 		}
 	}
+	l.lintAclEntries(decl)
 
 	return types.NeverType
 }
@@ -563,11 +754,13 @@ func (l *Linter) lintBackendDeclaration(decl *ast.BackendDeclaration, ctx *conte
 	if !isValidName(decl.Name.Value) {
 		l.Error(InvalidName(decl.Name.GetMeta(), decl.Name.Value, "backend").Match(BACKEND_SYNTAX))
 	}
+	l.lintNamingConvention(decl.Name, "backend", ctx)
 
 	// lint property definitions
 	for i := range decl.Properties {
 		l.lintBackendProperty(decl.Properties[i], ctx)
 	}
+	l.lintComplianceBackendTLS(decl)
 
 	return types.NeverType
 }
@@ -637,6 +830,7 @@ func (l *Linter) lintDirectorDeclaration(decl *ast.DirectorDeclaration, ctx *con
 	if !isValidName(decl.Name.Value) {
 		l.Error(InvalidName(decl.Name.GetMeta(), decl.Name.Value, "director").Match(DIRECTOR_SYNTAX))
 	}
+	l.lintNamingConvention(decl.Name, "director", ctx)
 
 	l.lintDirectorProperty(decl, ctx)
 
@@ -653,6 +847,8 @@ func (l *Linter) lintDirectorProperty(decl *ast.DirectorDeclaration, ctx *contex
 			Message:  "Unexpected director type: " + decl.DirectorType.Value,
 		}
 		l.Error(err.Match(DIRECTOR_SYNTAX))
+		// Properties could not be checked for unknown type, report them as syntax error
+		dps.Rule = DIRECTOR_SYNTAX
 	}
 
 	// at least one backend must be declared
@@ -684,6 +880,7 @@ func (l *Linter) lintDirectorProperty(decl *ast.DirectorDeclaration, ctx *contex
 							Token:    v.Token,
 							Message:  fmt.Sprintf("Backend %s is not declared", ident.Value),
 						}
+						relateSimilarName(err, ident.Value, backendTokens(ctx))
 						l.Error(err.Match(BACKEND_NOTFOUND))
 					}
 				} else {
@@ -743,18 +940,12 @@ func (l *Linter) lintTableDeclaration(decl *ast.TableDeclaration, ctx *context.C
 	if !isValidName(decl.Name.Value) {
 		l.Error(InvalidName(decl.Name.GetMeta(), decl.Name.Value, "table").Match(TABLE_SYNTAX))
 	}
+	l.lintNamingConvention(decl.Name, "table", ctx)
 
-	// Table item is limited under 1000 by default
+	// Table items and key length are limited by default
 	// https://developer.fastly.com/reference/vcl/declarations/table/#limitations
 	// But user can increase limitation by contacting to support.
-	if len(decl.Properties) > 1000 {
-		err := &LintError{
-			Severity: WARNING,
-			Token:    decl.Name.GetMeta().Token,
-			Message:  fmt.Sprintf(`Table "%s" items are limited to 1000`, decl.Name.Value),
-		}
-		l.Error(err.Match(TABLE_ITEM_LIMITATION))
-	}
+	l.lintTableLimits(decl)
 
 	// table value type
 	var valueType types.Type
@@ -781,7 +972,7 @@ func (l *Linter) lintTableProperty(prop *ast.TableProperty, tableType types.Type
 			return
 		}
 		if a, ok := ctx.Acls[ident.Value]; !ok {
-			l.Error(UndefinedAcl(ident.GetMeta(), ident.Value))
+			l.Error(relateSimilarName(UndefinedAcl(ident.GetMeta(), ident.Value), ident.Value, aclTokens(ctx)).Match(ACL_NOTFOUND))
 		} else {
 			a.IsUsed = true
 		}
@@ -792,14 +983,14 @@ func (l *Linter) lintTableProperty(prop *ast.TableProperty, tableType types.Type
 			return
 		}
 		if b, ok := ctx.Backends[ident.Value]; !ok {
-			l.Error(UndefinedBackend(ident.GetMeta(), ident.Value))
+			l.Error(relateSimilarName(UndefinedBackend(ident.GetMeta(), ident.Value), ident.Value, backendTokens(ctx)).Match(BACKEND_NOTFOUND))
 		} else {
 			b.IsUsed = true
 		}
 	default:
 		vt := l.lint(prop.Value, ctx)
 		if vt != tableType {
-			l.Error(InvalidType(prop.Value.GetMeta(), prop.Key.Value, tableType, vt))
+			l.Error(InvalidType(prop.Value.GetMeta(), prop.Key.Value, tableType, vt).Match(TABLE_TYPE_VARIATION))
 		}
 	}
 }
@@ -809,6 +1000,9 @@ func (l *Linter) lintSubRoutineDeclaration(decl *ast.SubroutineDeclaration, ctx
 	if !isValidName(decl.Name.Value) {
 		l.Error(InvalidName(decl.Name.GetMeta(), decl.Name.Value, "sub").Match(SUBROUTINE_SYNTAX))
 	}
+	l.lintNamingConvention(decl.Name, "subroutine", ctx)
+	l.lintShadowing(decl.Name, "subroutine")
+	l.lintAnnotations(decl.Leading, annotation.Subroutine)
 
 	scope := getSubroutineCallScope(decl)
 	var cc *context.Context
@@ -822,7 +1016,7 @@ func (l *Linter) lintSubRoutineDeclaration(decl *ast.SubroutineDeclaration, ctx
 	// Switch context mode which corredponds to call scope and restore after linting block statements
 	defer func() {
 		// Lint declared variables are used
-		l.lintUnusedVariables(ctx)
+		l.lintUnusedVariables(decl, ctx)
 		cc.Restore()
 	}()
 
@@ -834,12 +1028,20 @@ func (l *Linter) lintSubRoutineDeclaration(decl *ast.SubroutineDeclaration, ctx
 
 	// Store current subroutine in order to be able to access via statements inside
 	ctx.CurrentSubroutine = decl
+	l.regexGroups = regexGroupsNone
 	defer func() {
 		// Release it on subroutine linting has ended
 		ctx.CurrentSubroutine = nil
 	}()
 
 	l.lint(decl.Block, cc)
+	l.lintMissingReturn(decl)
+	l.lintComplexity(decl)
+	l.lintCacheDirectives(decl, scope)
+	l.lintSetCookieCache(decl, scope, ctx)
+	l.lintCacheKeys(decl)
+	l.lintComputeHosts(decl)
+	l.lintServiceDomains(decl)
 
 	// We are done linting inside the previous scope so
 	// we dont need the return type anymore
@@ -869,14 +1071,16 @@ func (l *Linter) lintGotoStatement(stmt *ast.GotoStatement, ctx *context.Context
 func (l *Linter) lintGotoDestinationStatement(stmt *ast.GotoDestinationStatement, ctx *context.Context) types.Type {
 	if gd, ok := ctx.Gotos[stmt.Name.Value]; ok {
 		if gd.IsUsed {
-			l.Error(DuplicatedUseForGotoDestination(stmt.GetMeta(), stmt.Name.Value))
+			l.Error(DuplicatedUseForGotoDestination(stmt.GetMeta(), stmt.Name.Value).Match(GOTO_DUPLICATED))
 			return types.NullType
 		}
 
 		gd.IsUsed = true
+		// Destination is reached from multiple flow paths
+		l.regexGroups = regexGroupsUnknown
 		return types.GotoType
 	} else {
-		l.Error(UndefinedGotoDestination(stmt.GetMeta(), stmt.Name.Value))
+		l.Error(UndefinedGotoDestination(stmt.GetMeta(), stmt.Name.Value).Match(GOTO_NOTFOUND))
 	}
 
 	return types.NullType
@@ -909,39 +1113,24 @@ func (l *Linter) lintRatecounterDeclaration(decl *ast.RatecounterDeclaration) ty
 }
 
 func (l *Linter) lintFastlyBoilerPlateMacro(sub *ast.SubroutineDeclaration, ctx *context.Context, scope string) {
-	phrase := strings.ToUpper("FASTLY " + scope)
+	phrase := snippets.MacroPhrase(scope)
 
-	// prepare scoped snippets
-	scopedSnippets, ok := ctx.Snippets().ScopedSnippets[scope]
-	if !ok {
-		scopedSnippets = []snippets.SnippetItem{}
-	}
+	// Lint before injecting snippets because snippets could be injected before the statement which has the macro
+	l.lintStatementsBeforeMacro(sub, scope)
 
-	var resolved []ast.Statement
-	// visit all statement comments and find "FASTLY [phase]" comment
-	if hasFastlyBoilerPlateMacro(sub.Block.InfixComment(), phrase) {
+	// Inject scoped snippets at the place of "FASTLY [phase]" comment as Fastly does
+	scopedSnippets := ctx.Snippets().ScopedSnippets[scope]
+	site, found, _ := snippets.InjectMacro(sub.Block, phrase, func() ([]ast.Statement, error) {
+		var resolved []ast.Statement
 		for _, s := range scopedSnippets {
 			resolved = append(resolved, l.loadSnippetVCL("snippet::"+s.Name, s.Data)...)
 		}
-		sub.Block.Statements = append(resolved, sub.Block.Statements...)
-		return
-	}
-
-	var found bool
-	for _, stmt := range sub.Block.Statements {
-		if hasFastlyBoilerPlateMacro(stmt.LeadingComment(), phrase) && !found {
-			// Macro found but embedding snippets should do only once
-			for _, s := range scopedSnippets {
-				resolved = append(resolved, l.loadSnippetVCL("snippet::"+s.Name, s.Data)...)
-			}
-			found = true
-		}
-		resolved = append(resolved, stmt)
-	}
-	// assign resolved statements to subroutine block
-	sub.Block.Statements = resolved
-
+		return resolved, nil
+	})
 	if found {
+		for _, s := range scopedSnippets {
+			l.snippetSites["snippet::"+s.Name] = site
+		}
 		return
 	}
 
@@ -952,6 +1141,7 @@ func (l *Linter) lintFastlyBoilerPlateMacro(sub *ast.SubroutineDeclaration, ctx
 		Message: fmt.Sprintf(
 			`Subroutine "%s" is missing Fastly boilerplate comment "%s" inside definition`, sub.Name.Value, phrase,
 		),
+		Fix: boilerplateMacroFix(sub, phrase),
 	}
 	l.Error(err.Match(SUBROUTINE_BOILERPLATE_MACRO))
 }
@@ -961,10 +1151,16 @@ func (l *Linter) lintBlockStatement(block *ast.BlockStatement, ctx *context.Cont
 	defer l.ignore.TeardownBlockStatement(block.GetMeta())
 
 	statements := l.resolveIncludeStatements(block.Statements, ctx, false)
+	l.estimateIncludedSize(block.Statements, statements)
+	unreachable := unreachableStatements(statements)
 	for _, stmt := range statements {
 		func(v ast.Statement, c *context.Context) {
 			l.ignore.SetupStatement(v.GetMeta())
 			defer l.ignore.TeardownStatement()
+			if exit, ok := unreachable[v]; ok {
+				l.Error(UnreachableStatement(v, exit).Match(UNREACHABLE_CODE))
+			}
+			l.lintAnnotations(v.GetMeta().Leading, annotation.Statement)
 			l.lint(v, c)
 		}(stmt, ctx)
 	}
@@ -986,6 +1182,8 @@ func (l *Linter) lintDeclareStatement(stmt *ast.DeclareStatement, ctx *context.C
 		}
 		l.Error(err.Match(DECLARE_STATEMENT_SYNTAX))
 	}
+	l.lintNamingConvention(stmt.Name, "variable", ctx)
+	l.lintShadowing(stmt.Name, "variable")
 
 	vt, ok := types.ValueTypeMap[stmt.ValueType.Value]
 	if !ok {
@@ -1014,18 +1212,28 @@ func (l *Linter) lintSetStatement(stmt *ast.SetStatement, ctx *context.Context)
 	}
 
 	// Check protected header will be modified
-	if isProtectedHTTPHeaderName(stmt.Ident.Value) {
-		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value))
+	if l.protected.contains(stmt.Ident.Value) {
+		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value).Match(PROTECTED_HEADER))
 	}
 
-	left, err := ctx.Set(stmt.Ident.Value)
+	left, err := ctx.Set(l.lintSubfield(stmt.Ident))
 	if err != nil {
+		if l.lintReqHashScope(stmt, ctx) {
+			return types.NeverType
+		}
+		if l.lintWritableScope(stmt, ctx) {
+			// Assignment checks are meaningless for the variable which could not be set in this scope
+			l.lint(stmt.Value, ctx)
+			return types.NeverType
+		}
 		err := &LintError{
 			Severity: ERROR,
 			Token:    stmt.Ident.GetMeta().Token,
 			Message:  err.Error(),
 		}
-		l.Error(err)
+		l.Error(err.Match(VARIABLE_ACCESS))
+	} else {
+		l.lintDeprecatedVariable(stmt.Ident, ctx)
 	}
 
 	if err := isValidStatementExpression(stmt.Value); err != nil {
@@ -1038,6 +1246,12 @@ func (l *Linter) lintSetStatement(stmt *ast.SetStatement, ctx *context.Context)
 	}
 
 	right := l.lint(stmt.Value, ctx)
+	l.lintDebugHeaderLeak(stmt.Ident, stmt.Value)
+	l.lintImageOptimizerHeader(stmt.Ident, stmt.Value, ctx)
+	l.lintImageOptimizerURL(stmt.Ident, stmt.Value)
+	if l.lintTimeArithmetic(stmt, left) {
+		return types.NeverType
+	}
 
 	// Fastly has various assignment operators and required correspond types for each operator
 	// https://developer.fastly.com/reference/vcl/operators/#assignment-operators
@@ -1056,6 +1270,7 @@ func (l *Linter) lintSetStatement(stmt *ast.SetStatement, ctx *context.Context)
 		l.lintLogicalOperator(stmt.Operator, left, right)
 	default: // "="
 		l.lintAssignOperator(stmt.Operator, stmt.Ident.Value, left, right, isLiteralExpression(stmt.Value))
+		l.lintHeaderStringification(stmt.Ident, stmt.Value, right)
 	}
 
 	return types.NeverType
@@ -1067,16 +1282,19 @@ func (l *Linter) lintUnsetStatement(stmt *ast.UnsetStatement, ctx *context.Conte
 	}
 
 	// Check protected header will be modified
-	if isProtectedHTTPHeaderName(stmt.Ident.Value) {
-		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value))
+	if l.protected.contains(stmt.Ident.Value) {
+		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value).Match(PROTECTED_HEADER))
 	}
 
-	if err := ctx.Unset(stmt.Ident.Value); err != nil {
-		l.Error(&LintError{
+	if err := ctx.Unset(l.lintSubfield(stmt.Ident)); err != nil {
+		err := &LintError{
 			Severity: ERROR,
 			Token:    stmt.Ident.GetMeta().Token,
 			Message:  err.Error(),
-		})
+		}
+		l.Error(err.Match(VARIABLE_ACCESS))
+	} else {
+		l.lintDeprecatedVariable(stmt.Ident, ctx)
 	}
 
 	return types.NeverType
@@ -1088,16 +1306,19 @@ func (l *Linter) lintRemoveStatement(stmt *ast.RemoveStatement, ctx *context.Con
 	}
 
 	// Check protected header will be modified
-	if isProtectedHTTPHeaderName(stmt.Ident.Value) {
-		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value))
+	if l.protected.contains(stmt.Ident.Value) {
+		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value).Match(PROTECTED_HEADER))
 	}
 
-	if err := ctx.Unset(stmt.Ident.Value); err != nil {
-		l.Error(&LintError{
+	if err := ctx.Unset(l.lintSubfield(stmt.Ident)); err != nil {
+		err := &LintError{
 			Severity: ERROR,
 			Token:    stmt.Ident.GetMeta().Token,
 			Message:  err.Error(),
-		})
+		}
+		l.Error(err.Match(VARIABLE_ACCESS))
+	} else {
+		l.lintDeprecatedVariable(stmt.Ident, ctx)
 	}
 
 	return types.NeverType
@@ -1105,6 +1326,8 @@ func (l *Linter) lintRemoveStatement(stmt *ast.RemoveStatement, ctx *context.Con
 
 func (l *Linter) lintIfStatement(stmt *ast.IfStatement, ctx *context.Context) types.Type {
 	l.lintIfCondition(stmt.Condition, ctx)
+	l.lintDuplicateConditions(stmt)
+	l.estimateIfWorkspace(stmt)
 
 	// push regex captured variables
 	if err := pushRegexGroupVars(stmt.Condition, ctx); err != nil {
@@ -1115,7 +1338,20 @@ func (l *Linter) lintIfStatement(stmt *ast.IfStatement, ctx *context.Context) ty
 		}
 		l.Error(err.Match(REGEX_MATCHED_VALUE_MAY_OVERRIDE))
 	}
+
+	// Track capture group count of regex matching for each branch and merge them after the statement
+	var groups []int
+	consequence := l.enterRegexCondition(stmt.Condition)
+	condition := l.regexGroups
+	l.regexGroups = consequence
+	// Enclosing condition is stacked only while linting its consequence,
+	// else if and else branches are reached when the condition is not satisfied
+	l.conditions = append(l.conditions, stmt.Condition)
 	l.lint(stmt.Consequence, ctx)
+	l.conditions = l.conditions[:len(l.conditions)-1]
+	l.lintComplianceAuthenticatedPath(stmt.Condition, stmt.Consequence, ctx)
+	groups = append(groups, l.regexGroups)
+	l.regexGroups = condition
 
 	for _, a := range stmt.Another {
 		l.lintIfCondition(a.Condition, ctx)
@@ -1127,12 +1363,23 @@ func (l *Linter) lintIfStatement(stmt *ast.IfStatement, ctx *context.Context) ty
 			}
 			l.Error(err.Match(REGEX_MATCHED_VALUE_MAY_OVERRIDE))
 		}
+		l.conditions = append(l.conditions, a.Condition)
+		consequence := l.enterRegexCondition(a.Condition)
+		condition = l.regexGroups
+		l.regexGroups = consequence
 		l.lint(a.Consequence, ctx)
+		l.lintComplianceAuthenticatedPath(a.Condition, a.Consequence, ctx)
+		groups = append(groups, l.regexGroups)
+		l.regexGroups = condition
+		l.conditions = l.conditions[:len(l.conditions)-1]
 	}
 
 	if stmt.Alternative != nil {
 		l.lint(stmt.Alternative, ctx)
 	}
+	for i := range groups {
+		l.regexGroups = mergeRegexGroups(l.regexGroups, groups[i])
+	}
 
 	return types.NeverType
 }
@@ -1157,12 +1404,14 @@ func (l *Linter) lintIfCondition(cond ast.Expression, ctx *context.Context) {
 	cc := l.lint(cond, ctx)
 	// Condition expression return type must be BOOL or STRING
 	if !expectType(cc, types.StringType, types.BoolType) {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    cond.GetMeta().Token,
 			Message:  fmt.Sprintf("Condition return type %s may not be used in boolean comparison", cc.String()),
-		})
+		}
+		l.Error(err.Match(CONDITION_TYPE))
 	}
+	l.lintConstantCondition(cond)
 }
 
 func (l *Linter) lintRestartStatement(stmt *ast.RestartStatement, ctx *context.Context) types.Type {
@@ -1190,8 +1439,8 @@ func (l *Linter) lintAddStatement(stmt *ast.AddStatement, ctx *context.Context)
 	}
 
 	// Check protected header will be modified
-	if isProtectedHTTPHeaderName(stmt.Ident.Value) {
-		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value))
+	if l.protected.contains(stmt.Ident.Value) {
+		l.Error(ProtectedHTTPHeader(stmt.Ident.GetMeta(), stmt.Ident.Value).Match(PROTECTED_HEADER))
 	}
 
 	// Add statement could use only for HTTP headers.
@@ -1210,13 +1459,16 @@ func (l *Linter) lintAddStatement(stmt *ast.AddStatement, ctx *context.Context)
 		l.Error(err.Match(ADD_STATEMENT_SYNTAX))
 	}
 
-	left, err := ctx.Get(stmt.Ident.Value)
+	left, err := ctx.Get(l.lintSubfield(stmt.Ident))
 	if err != nil {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    stmt.Ident.GetMeta().Token,
 			Message:  err.Error(),
-		})
+		}
+		l.Error(err.Match(VARIABLE_ACCESS))
+	} else {
+		l.lintDeprecatedVariable(stmt.Ident, ctx)
 	}
 
 	if err := isValidStatementExpression(stmt.Value); err != nil {
@@ -1229,6 +1481,7 @@ func (l *Linter) lintAddStatement(stmt *ast.AddStatement, ctx *context.Context)
 	}
 
 	right := l.lint(stmt.Value, ctx)
+	l.lintDebugHeaderLeak(stmt.Ident, stmt.Value)
 
 	// Commonly, add statement operator must be "="
 	if stmt.Operator.Operator != "=" {
@@ -1240,6 +1493,7 @@ func (l *Linter) lintAddStatement(stmt *ast.AddStatement, ctx *context.Context)
 		l.Error(err.Match(OPERATOR_ASSIGNMENT))
 	}
 	l.lintAssignOperator(stmt.Operator, stmt.Ident.Value, left, right, isLiteralExpression(stmt.Value))
+	l.lintHeaderStringification(stmt.Ident, stmt.Value, right)
 
 	return types.NeverType
 }
@@ -1248,11 +1502,15 @@ func (l *Linter) lintCallStatement(stmt *ast.CallStatement, ctx *context.Context
 	// Note that this linter analyze up to down,
 	// so all call target subroutine must be defined before call it.
 	if s, ok := ctx.Subroutines[stmt.Subroutine.Value]; !ok {
-		l.Error(UndefinedSubroutine(stmt.GetMeta(), stmt.Subroutine.Value).Match(CALL_STATEMENT_SUBROUTINE_NOTFOUND))
+		err := UndefinedSubroutine(stmt.GetMeta(), stmt.Subroutine.Value)
+		relateSimilarName(err, stmt.Subroutine.Value, subroutineTokens(ctx))
+		l.Error(err.Match(CALL_STATEMENT_SUBROUTINE_NOTFOUND))
 	} else {
 		// Mark subroutine is explicitly called
 		s.IsUsed = true
 	}
+	// Called subroutine may run regex matching
+	l.regexGroups = regexGroupsUnknown
 
 	return types.NeverType
 }
@@ -1274,12 +1532,12 @@ func (l *Linter) lintErrorStatement(stmt *ast.ErrorStatement, ctx *context.Conte
 	case *ast.Ident:
 		code := l.lint(t, ctx)
 		if code != types.IntegerType {
-			l.Error(InvalidType(t.GetMeta(), t.Value, types.IntegerType, code))
+			l.Error(InvalidType(t.GetMeta(), t.Value, types.IntegerType, code).Match(ERROR_STATEMENT_SYNTAX))
 		}
 	case *ast.FunctionCallExpression:
 		code := l.lint(t, ctx)
 		if code != types.IntegerType {
-			l.Error(InvalidType(t.GetMeta(), "error code", types.IntegerType, code))
+			l.Error(InvalidType(t.GetMeta(), "error code", types.IntegerType, code).Match(ERROR_STATEMENT_SYNTAX))
 		}
 	case *ast.Integer:
 		if t.Value > 699 {
@@ -1287,7 +1545,7 @@ func (l *Linter) lintErrorStatement(stmt *ast.ErrorStatement, ctx *context.Conte
 		}
 	default:
 		code := l.lint(t, ctx)
-		l.Error(InvalidType(t.GetMeta(), "error code", types.IntegerType, code))
+		l.Error(InvalidType(t.GetMeta(), "error code", types.IntegerType, code).Match(ERROR_STATEMENT_SYNTAX))
 	}
 
 	return types.NeverType
@@ -1299,27 +1557,30 @@ func (l *Linter) lintLogStatement(stmt *ast.LogStatement, ctx *context.Context)
 		case *ast.String:
 			return types.NeverType
 		default:
-			l.Error(&LintError{
+			err := &LintError{
 				Severity: ERROR,
 				Token:    stmt.GetMeta().Token,
 				Message:  "Only string literals may be passed to log directly.",
-			})
+			}
+			l.Error(err.Match(LOG_STATEMENT_SYNTAX))
 			return types.NeverType
 		}
 	}
 
 	l.lint(stmt.Value, ctx)
+	l.lintComplianceLogStatement(stmt)
 	return types.NeverType
 }
 
 func (l *Linter) lintReturnStatement(stmt *ast.ReturnStatement, ctx *context.Context) types.Type {
 	if ctx.ReturnType != nil {
 		if stmt.HasParenthesis {
-			l.Error(&LintError{
+			err := &LintError{
 				Severity: ERROR,
 				Token:    stmt.Token,
 				Message:  fmt.Sprintf("Function %s: only actions may be enclosed in ()", ctx.CurrentFunction()),
-			})
+			}
+			l.Error(err.Match(RETURN_STATEMENT_SYNTAX))
 		}
 
 		if stmt.ReturnExpression == nil {
@@ -1357,38 +1618,8 @@ func (l *Linter) lintReturnStatement(stmt *ast.ReturnStatement, ctx *context.Con
 	}
 
 	// legal return actions are different in subroutine.
-	// https://developer.fastly.com/learning/vcl/using/#the-vcl-request-lifecycle
-	expects := make([]string, 0, 3)
-
-	switch ctx.Mode() {
-	case context.RECV:
-		// https://developer.fastly.com/reference/vcl/subroutines/recv/
-		expects = append(expects, "lookup", "pass", "error", "restart")
-	case context.HASH:
-		// https://developer.fastly.com/reference/vcl/subroutines/hash/
-		expects = append(expects, "hash")
-	case context.HIT:
-		// https://developer.fastly.com/reference/vcl/subroutines/hit/
-		expects = append(expects, "deliver", "pass", "error", "restart")
-	case context.MISS:
-		// https://developer.fastly.com/reference/vcl/subroutines/miss/
-		expects = append(expects, "fetch", "deliver_stale", "pass", "error")
-	case context.PASS:
-		// https://developer.fastly.com/reference/vcl/subroutines/pass/
-		expects = append(expects, "pass")
-	case context.FETCH:
-		// https://developer.fastly.com/reference/vcl/subroutines/fetch/
-		expects = append(expects, "deliver", "deliver_stale", "pass", "error", "restart")
-	case context.ERROR:
-		// https://developer.fastly.com/reference/vcl/subroutines/error/
-		expects = append(expects, "deliver", "deliver_stale", "restart")
-	case context.DELIVER:
-		// https://developer.fastly.com/reference/vcl/subroutines/deliver/
-		expects = append(expects, "deliver", "restart")
-	case context.LOG:
-		// https://developer.fastly.com/reference/vcl/subroutines/log/
-		expects = append(expects, "deliver")
-	}
+	// Custom subroutine could be called from multiple scopes, then expects states which are legal in all of them.
+	expects := context.ReturnStates(ctx.Mode())
 
 	// If return statement does not have arguemnt, but Fastly requires next state in state-machine method like "vcl_recv"
 	if stmt.ReturnExpression == nil {
@@ -1405,8 +1636,11 @@ func (l *Linter) lintReturnStatement(stmt *ast.ReturnStatement, ctx *context.Con
 
 	if !expectState((*stmt.ReturnExpression).String(), expects...) {
 		l.Error(InvalidReturnState(
-			(*stmt.ReturnExpression).GetMeta(), context.ScopeString(ctx.Mode()), (*stmt.ReturnExpression).String(), expects...,
-		).Match(RESTART_STATEMENT_SCOPE))
+			(*stmt.ReturnExpression).GetMeta(),
+			strings.TrimSpace(context.ScopesString(ctx.Mode())),
+			(*stmt.ReturnExpression).String(),
+			expects...,
+		).Match(RETURN_STATEMENT_STATE))
 	}
 	return types.NeverType
 }
@@ -1427,7 +1661,7 @@ func (l *Linter) lintSyntheticStatement(stmt *ast.SyntheticStatement, ctx *conte
 }
 
 func (l *Linter) lintIdent(exp *ast.Ident, ctx *context.Context) types.Type {
-	v, err := ctx.Get(exp.Value)
+	v, err := ctx.Get(l.lintSubfield(exp))
 	if err != nil {
 		if b, ok := ctx.Backends[exp.Value]; ok {
 			// mark backend is used
@@ -1459,13 +1693,17 @@ func (l *Linter) lintIdent(exp *ast.Ident, ctx *context.Context) types.Type {
 			return types.IDType
 		}
 
-		// Convert to lint error
-		l.Error(&LintError{
+		// Convert to lint error, undefined identifier may be a typo of declared names
+		l.Error(relateSimilarName(&LintError{
 			Severity: ERROR,
 			Token:    exp.GetMeta().Token,
 			Message:  err.Error(),
-		})
+		}, exp.Value, backendTokens(ctx), aclTokens(ctx), tableTokens(ctx)).Match(VARIABLE_ACCESS))
+		return v
 	}
+
+	l.lintDeprecatedVariable(exp, ctx)
+	l.lintRegexGroupReference(exp)
 	return v
 }
 
@@ -1532,14 +1770,14 @@ func (l *Linter) lintPrefixExpression(exp *ast.PrefixExpression, ctx *context.Co
 		if !expectType(right, types.IntegerType, types.FloatType, types.RTimeType) {
 			l.Error(InvalidTypeExpression(
 				exp.GetMeta(), right, types.IntegerType, types.FloatType, types.RTimeType,
-			))
+			).Match(OPERATOR_CONDITIONAL))
 		}
 		return right
 	case "+":
 		if !expectType(right, types.StringType, types.IntegerType, types.FloatType, types.RTimeType, types.BoolType) {
 			l.Error(InvalidTypeExpression(
 				exp.GetMeta(), right, types.StringType, types.IntegerType, types.FloatType, types.RTimeType, types.BoolType,
-			))
+			).Match(OPERATOR_CONDITIONAL))
 		}
 		return right
 	}
@@ -1605,14 +1843,7 @@ func (l *Linter) lintInfixExpression(exp *ast.InfixExpression, ctx *context.Cont
 		}
 		// And, if right expression is STRING, regex must be valid
 		if v, ok := exp.Right.(*ast.String); ok {
-			if _, err := regexp.Compile(v.Value); err != nil {
-				err := &LintError{
-					Severity: ERROR,
-					Token:    exp.Right.GetMeta().Token,
-					Message:  "regex string is invalid, " + err.Error(),
-				}
-				l.Error(err)
-			}
+			l.lintRegexPattern(v)
 		}
 		return types.BoolType
 	case "+":
@@ -1637,7 +1868,7 @@ func (l *Linter) lintInfixExpression(exp *ast.InfixExpression, ctx *context.Cont
 		case types.StringType:
 			break
 		default:
-			l.Error(ImplicitTypeConversion(exp.GetMeta(), left, types.StringType))
+			l.Error(ImplicitTypeConversion(exp.GetMeta(), left, types.StringType).Match(IMPLICIT_TYPE_CONVERSION))
 		}
 
 		switch right {
@@ -1650,7 +1881,7 @@ func (l *Linter) lintInfixExpression(exp *ast.InfixExpression, ctx *context.Cont
 		case types.StringType:
 			break
 		default:
-			l.Error(ImplicitTypeConversion(exp.GetMeta(), right, types.StringType))
+			l.Error(ImplicitTypeConversion(exp.GetMeta(), right, types.StringType).Match(IMPLICIT_TYPE_CONVERSION))
 		}
 		return types.StringType
 	case "&&", "||":
@@ -1663,6 +1894,7 @@ func (l *Linter) lintInfixExpression(exp *ast.InfixExpression, ctx *context.Cont
 
 func (l *Linter) lintIfExpression(exp *ast.IfExpression, ctx *context.Context) types.Type {
 	l.lintIfCondition(exp.Condition, ctx)
+	l.enterRegexCondition(exp.Condition)
 	if err := pushRegexGroupVars(exp.Condition, ctx); err != nil {
 		err := &LintError{
 			Severity: INFO,
@@ -1673,29 +1905,32 @@ func (l *Linter) lintIfExpression(exp *ast.IfExpression, ctx *context.Context) t
 	}
 
 	if isConstantExpression(exp.Consequence) {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    exp.Consequence.GetMeta().Token,
 			Message:  "Cannot use constant literal in If expression consequence",
-		})
+		}
+		l.Error(err.Match(IF_EXPRESSION_SYNTAX))
 	}
 	left := l.lint(exp.Consequence, ctx)
 
 	if isConstantExpression(exp.Alternative) {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    exp.Alternative.GetMeta().Token,
 			Message:  "Cannot use constant literal in If expression alternative",
-		})
+		}
+		l.Error(err.Match(IF_EXPRESSION_SYNTAX))
 	}
 	right := l.lint(exp.Alternative, ctx)
 
 	if left != right {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: WARNING,
 			Token:    exp.GetMeta().Token,
 			Message:  "If expression returns different type between consequence and alternative",
-		})
+		}
+		l.Error(err.Match(IF_EXPRESSION_TYPE))
 	}
 	return left
 }
@@ -1703,14 +1938,19 @@ func (l *Linter) lintIfExpression(exp *ast.IfExpression, ctx *context.Context) t
 func (l *Linter) lintFunctionCallExpression(exp *ast.FunctionCallExpression, ctx *context.Context) types.Type {
 	fn, err := ctx.GetFunction(exp.Function.Value)
 	if err != nil {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    exp.Function.GetMeta().Token,
 			Message:  err.Error(),
-		})
+		}
+		l.Error(err.Match(FUNCTION_NOTFOUND))
 		return types.NeverType
 	}
+	l.lintDeprecatedFunction(exp.Function, fn)
 
+	l.lintImageOptimizerQueryFunction(exp)
+	l.lintRegexFunctionArguments(exp)
+	l.lintTimeFormatArguments(exp)
 	return l.lintFunctionArguments(fn, functionMeta{
 		name:      exp.Function.String(),
 		token:     exp.Function.GetMeta().Token,
@@ -1722,20 +1962,23 @@ func (l *Linter) lintFunctionCallExpression(exp *ast.FunctionCallExpression, ctx
 func (l *Linter) lintFunctionStatement(exp *ast.FunctionCallStatement, ctx *context.Context) types.Type {
 	fn, err := ctx.GetFunction(exp.Function.Value)
 	if err != nil {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    exp.Function.GetMeta().Token,
 			Message:  err.Error(),
-		})
+		}
+		l.Error(err.Match(FUNCTION_NOTFOUND))
 		return types.NeverType
 	}
+	l.lintDeprecatedFunction(exp.Function, fn)
 
 	if fn.Return != types.NeverType {
-		l.Error(&LintError{
+		err := &LintError{
 			Severity: ERROR,
 			Token:    exp.Function.GetMeta().Token,
 			Message:  fmt.Sprintf(`Unused return type for function "%s"`, exp.Function.Value),
-		})
+		}
+		l.Error(err.Match(FUNCTION_UNUSED_RETURN))
 		return types.NeverType
 	}
 
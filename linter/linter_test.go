@@ -46,6 +46,16 @@ func assertError(t *testing.T, input string, opts ...context.Option) {
 	if l.FatalError != nil {
 		t.Errorf("Fatal error: %s", l.FatalError.Error)
 	}
+	assertRuleMatched(t, l)
+}
+
+// All diagnostics must have a rule in order to override its severity
+func assertRuleMatched(t *testing.T, l *Linter) {
+	for _, d := range l.Diagnostics {
+		if d.Rule == "" {
+			t.Errorf("Diagnostic does not have a rule: %s", d.Message)
+		}
+	}
 }
 func assertErrorWithSeverity(t *testing.T, input string, severity Severity, opts ...context.Option) {
 	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
@@ -66,6 +76,7 @@ func assertErrorWithSeverity(t *testing.T, input string, severity Severity, opts
 	if le.Severity != severity {
 		t.Errorf("Severity expects %s but got %s with: %s", severity, le.Severity, le)
 	}
+	assertRuleMatched(t, l)
 }
 
 func TestLintAclStatement(t *testing.T) {
@@ -445,6 +456,7 @@ func TestLintStuff(t *testing.T) {
 			%s
 			sub example BOOL {
 				log resp.http.bar;
+				return true;
 			}
 
 			sub vcl_log {
@@ -1491,6 +1503,33 @@ sub vcl_recv {
 		assertNoError(t, input)
 	})
 
+	t.Run("state: illegal state in reserved subroutine", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+	#Fastly fetch
+	return (lookup);
+}`
+		assertError(t, input)
+	})
+
+	t.Run("state: legal state in all scopes of custom subroutine", func(t *testing.T) {
+		input := `
+// @scope: recv, fetch
+sub custom_sub {
+	return (pass);
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("state: illegal state in one of scopes of custom subroutine", func(t *testing.T) {
+		input := `
+// @scope: recv, fetch
+sub custom_sub {
+	return (lookup);
+}`
+		assertError(t, input)
+	})
+
 	t.Run("sub: return correct type", func(t *testing.T) {
 		input := `
 sub custom_sub INTEGER {
@@ -2100,12 +2139,12 @@ func TestLintGotoStatement(t *testing.T) {
 		declare local var.x INTEGER;
 		set var.x = 1;
 
-		goto set_and_update;
-
 		if (var.x == 1) {
-			set var.x = 2;
+			goto set_and_update;
 		}
 
+		set var.x = 2;
+
 		set_and_update:
 		set var.x = 3;
 	}
@@ -2661,3 +2700,39 @@ sub vcl_recv {
 		assertNoError(t, input)
 	})
 }
+
+func TestSeverityOverrides(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.unused STRING;
+  error 800;
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		return
+	}
+
+	l := New(WithSeverities(map[Rule]Severity{
+		UNUSED_VARIABLE:      ERROR,
+		ERROR_STATEMENT_CODE: IGNORE,
+	}))
+	l.lint(vcl, context.New())
+	if len(l.Diagnostics) != 1 {
+		t.Errorf("Expects one diagnostic but got %d: %v", len(l.Diagnostics), l.Errors)
+		return
+	}
+	if d := l.Diagnostics[0]; d.Rule != UNUSED_VARIABLE || d.Severity != ERROR {
+		t.Errorf("Expects overridden ERROR of %s but got %s of %s", UNUSED_VARIABLE, d.Severity, d.Rule)
+	}
+
+	for _, v := range []string{"error", "Warning", "INFO", "ignore"} {
+		if _, err := ParseSeverity(v); err != nil {
+			t.Errorf("Unexpected error for %s: %s", v, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Errorf("Expects error for unknown severity")
+	}
+}
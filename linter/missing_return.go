@@ -0,0 +1,85 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Lint typed subroutine which could reach the end of the subroutine without returning a value.
+// Fastly compilation fails when some paths fall off the end of the typed subroutine.
+func (l *Linter) lintMissingReturn(decl *ast.SubroutineDeclaration) {
+	if decl.ReturnType == nil || isTerminated(decl.Block.Statements) {
+		return
+	}
+
+	err := &LintError{
+		Severity: ERROR,
+		Token:    decl.Name.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"Subroutine %s must return %s value but some paths reach the end without returning",
+			decl.Name.Value, decl.ReturnType.Value,
+		),
+	}
+	l.Error(err.Match(SUBROUTINE_MISSING_RETURN))
+}
+
+// Check all paths of the statements end with the statement which exits from the subroutine
+func isTerminated(statements []ast.Statement) bool {
+	var terminated bool
+	for _, stmt := range statements {
+		switch t := stmt.(type) {
+		case *ast.GotoDestinationStatement:
+			// Statements after goto destination could be reached by goto statement
+			terminated = false
+		case *ast.ReturnStatement, *ast.ErrorStatement, *ast.RestartStatement, *ast.GotoStatement:
+			terminated = true
+		case *ast.BlockStatement:
+			if isTerminated(t.Statements) {
+				terminated = true
+			}
+		case *ast.IfStatement:
+			if isTerminatedIf(t) {
+				terminated = true
+			}
+		case *ast.SwitchStatement:
+			if isTerminatedSwitch(t) {
+				terminated = true
+			}
+		}
+	}
+	return terminated
+}
+
+// If statement terminates only when it has else branch and all branches terminate
+func isTerminatedIf(stmt *ast.IfStatement) bool {
+	if stmt.Alternative == nil {
+		return false
+	}
+	if !isTerminated(stmt.Consequence.Statements) || !isTerminated(stmt.Alternative.Statements) {
+		return false
+	}
+	for _, another := range stmt.Another {
+		if !isTerminated(another.Consequence.Statements) {
+			return false
+		}
+	}
+	return true
+}
+
+// Switch statement terminates only when it has default case and all cases terminate.
+// Case which falls through terminates by the following case.
+func isTerminatedSwitch(stmt *ast.SwitchStatement) bool {
+	if stmt.Default < 0 {
+		return false
+	}
+	for i, c := range stmt.Cases {
+		if c.IsFallthrough() && i < len(stmt.Cases)-1 {
+			continue
+		}
+		if !isTerminated(c.Statements) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package linter
+
+import (
+	"testing"
+)
+
+func TestLintMissingReturn(t *testing.T) {
+	t.Run("all paths return", func(t *testing.T) {
+		assertNoError(t, `
+sub is_api BOOL {
+  if (req.url ~ "^/api/") {
+    return true;
+  } else if (req.url ~ "^/v1/") {
+    return true;
+  } else {
+    return false;
+  }
+}
+
+sub is_admin BOOL {
+  if (req.url ~ "^/admin/") {
+    return true;
+  }
+  return false;
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (is_api() || is_admin()) {
+    return (pass);
+  }
+}`)
+	})
+
+	t.Run("if statement without else", func(t *testing.T) {
+		assertErrorWithSeverity(t, `
+sub is_api BOOL {
+  if (req.url ~ "^/api/") {
+    return true;
+  } else if (req.url ~ "^/v1/") {
+    return false;
+  }
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (is_api()) {
+    return (pass);
+  }
+}`, ERROR)
+	})
+
+	t.Run("branch falls through", func(t *testing.T) {
+		assertErrorWithSeverity(t, `
+sub is_api BOOL {
+  if (req.url ~ "^/api/") {
+    return true;
+  } else {
+    set req.http.X-API = "0";
+  }
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (is_api()) {
+    return (pass);
+  }
+}`, ERROR)
+	})
+
+	t.Run("goto destination after return", func(t *testing.T) {
+		assertErrorWithSeverity(t, `
+sub is_api BOOL {
+  if (req.url ~ "^/api/") {
+    goto api;
+  }
+  return false;
+  api:
+  set req.http.X-API = "1";
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (is_api()) {
+    return (pass);
+  }
+}`, ERROR)
+	})
+
+	t.Run("subroutine without return type", func(t *testing.T) {
+		assertNoError(t, `
+sub set_api {
+  if (req.url ~ "^/api/") {
+    set req.http.X-API = "1";
+  }
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  call set_api;
+}`)
+	})
+}
@@ -0,0 +1,52 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Compiled naming convention patterns keyed by declaration type
+type namingPatterns map[string]*regexp.Regexp
+
+// Lint declared name matches the naming convention pattern of the declaration type.
+// Naming convention is opt-in, nothing is checked unless the pattern is configured.
+func (l *Linter) lintNamingConvention(ident *ast.Ident, kind string, ctx *context.Context) {
+	pattern, ok := l.naming[kind]
+	if !ok {
+		return
+	}
+
+	name := ident.Value
+	switch kind {
+	case "subroutine":
+		// Fastly reserved subroutine names could not be changed
+		if context.IsFastlySubroutine(name) {
+			return
+		}
+	case "variable":
+		name = strings.TrimPrefix(name, "var.")
+	}
+	if pattern.MatchString(name) {
+		return
+	}
+
+	err := &LintError{
+		Severity: WARNING,
+		Token:    ident.GetMeta().Token,
+		Message:  fmt.Sprintf(`Name "%s" of %s does not match naming convention "%s"`, name, kind, pattern.String()),
+	}
+	// Only local variables could be renamed because other declarations may be referenced from other files
+	if kind == "variable" && ctx.CurrentSubroutine != nil {
+		for _, c := range nameCandidates(name) {
+			if c != name && isValidVariableName(c) && pattern.MatchString(c) {
+				err.Fix = renameVariableFix(ctx.CurrentSubroutine, ident, c)
+				break
+			}
+		}
+	}
+	l.Error(err.Match(NAMING_CONVENTION))
+}
@@ -0,0 +1,119 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintNamingConvention(t *testing.T) {
+	naming := &config.NamingConfig{
+		Subroutine: "^[a-z_]+$",
+		Backend:    "^be_",
+		Acl:        "^acl_",
+		Table:      "^t_",
+		Variable:   "^l_",
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		expect int
+	}{
+		{
+			name: "all names follow the convention",
+			input: `
+backend be_origin {
+  .host = "example.com";
+}
+acl acl_internal {
+  "192.0.2.0"/24;
+}
+table t_routes {
+  "/": "root",
+}
+sub custom_recv {
+  declare local var.l_path STRING;
+  set var.l_path = table.lookup(t_routes, req.url.path, "");
+  if (client.ip ~ acl_internal) {
+    set req.backend = be_origin;
+  }
+}
+sub vcl_recv {
+  #FASTLY RECV
+  call custom_recv;
+}`,
+		},
+		{
+			name: "names violate the convention",
+			input: `
+backend origin {
+  .host = "example.com";
+}
+acl internal {
+  "192.0.2.0"/24;
+}
+table routes {
+  "/": "root",
+}
+sub CustomRecv {
+  declare local var.path STRING;
+  set var.path = table.lookup(routes, req.url.path, "");
+  if (client.ip ~ internal) {
+    set req.backend = origin;
+  }
+}
+sub vcl_recv {
+  #FASTLY RECV
+  call CustomRecv;
+}`,
+			expect: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+		if err != nil {
+			t.Errorf("%s: unexpected parser error: %s", tt.name, err)
+			continue
+		}
+		l := New(WithNamingConvention(naming))
+		l.Lint(vcl, context.New())
+
+		var actual int
+		for _, d := range l.Diagnostics {
+			if d.Rule == NAMING_CONVENTION {
+				actual++
+			}
+		}
+		if actual != tt.expect {
+			t.Errorf("%s: naming convention errors mismatch, expect=%d, got=%d, diagnostics=%v", tt.name, tt.expect, actual, l.Diagnostics)
+		}
+	}
+}
+
+func TestLintNamingConventionDisabled(t *testing.T) {
+	input := `
+backend origin {
+  .host = "example.com";
+}
+sub vcl_recv {
+  #FASTLY RECV
+  set req.backend = origin;
+}`
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		return
+	}
+	l := New()
+	l.Lint(vcl, context.New())
+	for _, d := range l.Diagnostics {
+		if d.Rule == NAMING_CONVENTION {
+			t.Errorf("Naming convention should not be checked without configuration")
+		}
+	}
+}
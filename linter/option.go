@@ -0,0 +1,167 @@
+package linter
+
+import (
+	"github.com/ysugimoto/falco/annotation"
+	"github.com/ysugimoto/falco/config"
+)
+
+type Option func(l *Linter)
+
+func WithDebugHeader(c *config.DebugHeaderConfig) Option {
+	return func(l *Linter) {
+		l.debugHeader = c
+	}
+}
+
+func WithCompliance(c *config.ComplianceConfig) Option {
+	return func(l *Linter) {
+		l.compliance = c
+	}
+}
+
+// WithCategories limits reporting rules by their categories.
+// Empty only list means all categories are reported except skipped ones.
+func WithCategories(only, skip []Category) Option {
+	return func(l *Linter) {
+		if len(only) == 0 && len(skip) == 0 {
+			return
+		}
+		f := &categoryFilter{
+			only: make(map[Category]struct{}),
+			skip: make(map[Category]struct{}),
+		}
+		for _, c := range only {
+			f.only[c] = struct{}{}
+		}
+		for _, c := range skip {
+			f.skip[c] = struct{}{}
+		}
+		l.categories = f
+	}
+}
+
+// WithSeverities overrides severity of the rules, the rule is not reported when overridden as IGNORE
+func WithSeverities(severities map[Rule]Severity) Option {
+	return func(l *Linter) {
+		l.severities = severities
+	}
+}
+
+// WithCustomRules adds custom rules to the linter in addition to registered ones
+func WithCustomRules(rules ...CustomRule) Option {
+	return func(l *Linter) {
+		l.customRules = append(l.customRules, rules...)
+	}
+}
+
+// WithNamingConvention enables naming convention rule with patterns compiled from config.
+// Invalid patterns are ignored so the caller should validate config beforehand.
+func WithNamingConvention(c *config.NamingConfig) Option {
+	return func(l *Linter) {
+		if patterns, err := c.Patterns(); err == nil {
+			l.naming = patterns
+		}
+	}
+}
+
+// WithPolicies enables statement policies.
+// Invalid policies are ignored so the caller should validate them with ValidatePolicies beforehand.
+func WithPolicies(c []*config.PolicyConfig) Option {
+	return func(l *Linter) {
+		if policies, err := compilePolicies(c); err == nil {
+			l.policies = policies
+		}
+	}
+}
+
+// WithFacts enables collecting facts about linted VCL
+func WithFacts() Option {
+	return func(l *Linter) {
+		l.facts = newFacts()
+	}
+}
+
+// WithComputeHosts marks hostnames which are served by Compute services in the mixed-platform account
+func WithComputeHosts(hosts []string) Option {
+	return func(l *Linter) {
+		l.computeHosts = hostPatterns(hosts)
+	}
+}
+
+// WithServiceDomains enables validating hostnames in conditions against domains which are attached to the service
+func WithServiceDomains(domains []string) Option {
+	return func(l *Linter) {
+		l.serviceDomains = hostPatterns(domains)
+	}
+}
+
+// WithComplexity overrides thresholds of complexity rules
+func WithComplexity(c *config.ComplexityConfig) Option {
+	return func(l *Linter) {
+		l.complexity = c
+	}
+}
+
+// WithTableLimits overrides thresholds of table limitation rules
+func WithTableLimits(c *config.TableLimitsConfig) Option {
+	return func(l *Linter) {
+		l.tableLimits = c
+	}
+}
+
+// WithCompilationLimits overrides thresholds of compilation limit rules
+func WithCompilationLimits(c *config.CompilationLimitsConfig) Option {
+	return func(l *Linter) {
+		l.compilationLimits = c
+	}
+}
+
+// WithProtectedHeaders adds user reserved headers to protected headers, or allows modifying Fastly protected headers
+func WithProtectedHeaders(c *config.ProtectedHeaderConfig) Option {
+	return func(l *Linter) {
+		if c == nil {
+			return
+		}
+		l.protected.add(c.Headers)
+		l.protected.allow(c.Allow)
+	}
+}
+
+// WithModuleCache shares parsed included modules with other linters which use the same cache
+func WithModuleCache(c *ModuleCache) Option {
+	return func(l *Linter) {
+		l.includes.shared = c
+	}
+}
+
+// Recover the panic while linting each declaration and continue to lint the rest of declarations.
+// The panic is reported as FatalError with the declaration and stack trace.
+func WithExplainFatal(v bool) Option {
+	return func(l *Linter) {
+		l.explainFatal = v
+	}
+}
+
+// WithFailFast stops linting at the first error, rest of declarations are not linted
+func WithFailFast(v bool) Option {
+	return func(l *Linter) {
+		l.failFast = v
+	}
+}
+
+// WithAnnotations registers annotations which are consumed by other tools like transformer plugins,
+// so that they are not reported as unknown annotations. Any value is accepted for these annotations.
+func WithAnnotations(names []string) Option {
+	return func(l *Linter) {
+		for _, name := range names {
+			if _, ok := l.annotations.Lookup(name); ok {
+				continue
+			}
+			l.annotations.Register(&annotation.Definition{ // nolint:errcheck
+				Name:   name,
+				Kind:   annotation.Any,
+				Target: annotation.AnyTarget,
+			})
+		}
+	}
+}
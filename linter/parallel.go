@@ -0,0 +1,49 @@
+package linter
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Entry is an entry VCL which is linted by LintParallel
+type Entry struct {
+	VCL     *ast.VCL
+	Context *context.Context
+}
+
+// LintParallel lints multiple entry VCLs concurrently with the options.
+// Included modules are parsed only once and shared between entries via the module cache,
+// nil cache creates new one for this call. Concurrency less than 1 means GOMAXPROCS.
+// Linters are returned in the same order of entries.
+func LintParallel(entries []*Entry, cache *ModuleCache, concurrency int, options ...Option) []*Linter {
+	if cache == nil {
+		cache = NewModuleCache()
+	}
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	// Copy options not to share the underlying array between goroutines
+	options = append(options[:len(options):len(options)], WithModuleCache(cache))
+
+	linters := make([]*Linter, len(entries))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range entries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			l := New(options...)
+			l.Lint(entries[i].VCL, entries[i].Context)
+			linters[i] = l
+		}(i)
+	}
+	wg.Wait()
+	return linters
+}
@@ -0,0 +1,75 @@
+package linter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintParallel(t *testing.T) {
+	const size = 8
+
+	main := `
+include "shared";
+include "service";
+sub vcl_recv {
+  #FASTLY RECV
+  call shared_recv;
+  call service_recv;
+}`
+	messages := func(l *Linter) []string {
+		var m []string
+		for _, d := range l.Diagnostics {
+			m = append(m, fmt.Sprintf("%s:%d %s", d.Token.File, d.Token.Line, d.Message))
+		}
+		return m
+	}
+
+	var entries []*Entry
+	var expects [][]string
+	for i := 0; i < size; i++ {
+		// "service" module is resolved to the different content in each entry
+		r := &mockResolver{
+			dependency: map[string]string{
+				"shared": `sub shared_recv { set req.http.Shared = 1; }`,
+				"service": fmt.Sprintf(`sub service_recv {
+  set req.http.Service%d = %d;
+}`, i, i),
+			},
+		}
+
+		vcl, err := parser.New(lexer.NewFromString(main)).ParseVCL()
+		if err != nil {
+			t.Fatalf("Unexpected parser error: %s", err)
+		}
+		l := New()
+		l.Lint(vcl, context.New(context.WithResolver(r)))
+		expects = append(expects, messages(l))
+
+		vcl, _ = parser.New(lexer.NewFromString(main)).ParseVCL() // nolint:errcheck
+		entries = append(entries, &Entry{
+			VCL:     vcl,
+			Context: context.New(context.WithResolver(r)),
+		})
+	}
+
+	if len(expects[0]) == 0 {
+		t.Fatalf("Entries should have lint errors to compare")
+	}
+
+	cache := NewModuleCache()
+	linters := LintParallel(entries, cache, 3)
+	for i, l := range linters {
+		if diff := cmp.Diff(expects[i], messages(l)); diff != "" {
+			t.Errorf("Diagnostics of entry %d mismatch, diff=%s", i, diff)
+		}
+	}
+	// "shared" module is parsed once, and "service" module is parsed for each content
+	if cache.Len() != size+1 {
+		t.Errorf("Cache should have %d modules, got %d", size+1, cache.Len())
+	}
+}
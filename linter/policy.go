@@ -0,0 +1,246 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+)
+
+var policyPhases = map[string]int{
+	"recv":    context.RECV,
+	"hash":    context.HASH,
+	"hit":     context.HIT,
+	"miss":    context.MISS,
+	"pass":    context.PASS,
+	"fetch":   context.FETCH,
+	"error":   context.ERROR,
+	"deliver": context.DELIVER,
+	"log":     context.LOG,
+}
+
+var policyStatements = map[string]struct{}{
+	"add":              {},
+	"call":             {},
+	"declare":          {},
+	"error":            {},
+	"esi":              {},
+	"goto":             {},
+	"log":              {},
+	"remove":           {},
+	"restart":          {},
+	"return":           {},
+	"set":              {},
+	"synthetic":        {},
+	"synthetic.base64": {},
+	"unset":            {},
+}
+
+// Compiled statement policy
+type policy struct {
+	name       string
+	message    string
+	severity   Severity
+	statements map[string]struct{}
+	functions  []glob.Glob
+	variables  []glob.Glob
+	phases     int
+	except     int
+	files      []glob.Glob
+}
+
+// ValidatePolicies validates policy configurations
+func ValidatePolicies(configs []*config.PolicyConfig) error {
+	_, err := compilePolicies(configs)
+	return err
+}
+
+func compilePolicies(configs []*config.PolicyConfig) ([]*policy, error) {
+	var policies []*policy
+	for i, c := range configs {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		p := &policy{
+			name:       name,
+			message:    c.Message,
+			severity:   ERROR,
+			statements: make(map[string]struct{}),
+		}
+
+		switch strings.ToUpper(c.Severity) {
+		case "", "ERROR":
+		case "WARNING":
+			p.severity = WARNING
+		case "INFO":
+			p.severity = INFO
+		default:
+			return nil, fmt.Errorf(`Policy %s has invalid severity "%s"`, name, c.Severity)
+		}
+		for _, s := range c.Statements {
+			s = strings.ToLower(s)
+			if _, ok := policyStatements[s]; !ok {
+				return nil, fmt.Errorf(`Policy %s has unknown statement "%s"`, name, s)
+			}
+			p.statements[s] = struct{}{}
+		}
+
+		var err error
+		if p.phases, err = parsePolicyPhases(name, c.Phases); err != nil {
+			return nil, err
+		}
+		if p.except, err = parsePolicyPhases(name, c.ExceptPhases); err != nil {
+			return nil, err
+		}
+		if p.phases > 0 && p.except > 0 {
+			return nil, fmt.Errorf("Policy %s could not specify both phases and except_phases", name)
+		}
+		if p.functions, err = compilePolicyGlobs(name, c.Functions, false); err != nil {
+			return nil, err
+		}
+		if p.variables, err = compilePolicyGlobs(name, c.Variables, true); err != nil {
+			return nil, err
+		}
+		if p.files, err = compilePolicyGlobs(name, c.Files, false, '/'); err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func parsePolicyPhases(name string, phases []string) (int, error) {
+	var mode int
+	for _, v := range phases {
+		phase, ok := policyPhases[strings.ToLower(strings.TrimPrefix(v, "vcl_"))]
+		if !ok {
+			return 0, fmt.Errorf(`Policy %s has unknown phase "%s"`, name, v)
+		}
+		mode |= phase
+	}
+	return mode, nil
+}
+
+func compilePolicyGlobs(name string, patterns []string, lower bool, separators ...rune) ([]glob.Glob, error) {
+	var globs []glob.Glob
+	for _, pattern := range patterns {
+		if lower {
+			pattern = strings.ToLower(pattern)
+		}
+		g, err := glob.Compile(pattern, separators...)
+		if err != nil {
+			return nil, fmt.Errorf(`Policy %s has invalid glob pattern "%s": %w`, name, pattern, err)
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+func matchGlobs(globs []glob.Glob, v string) bool {
+	for i := range globs {
+		if globs[i].Match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns phases which the policy is violated in current mode
+func (p *policy) violatedPhases(mode int) int {
+	switch {
+	case p.phases > 0:
+		return mode & p.phases
+	case p.except > 0:
+		return mode &^ p.except
+	default:
+		return mode
+	}
+}
+
+// Lint statements, function calls and variable modifications against configured policies.
+func (l *Linter) lintPolicies(node ast.Node, ctx *context.Context) {
+	if len(l.policies) == 0 {
+		return
+	}
+
+	var statement, function, variable string
+	switch t := node.(type) {
+	case *ast.AddStatement:
+		statement, variable = "add", t.Ident.Value
+	case *ast.CallStatement:
+		statement = "call"
+	case *ast.DeclareStatement:
+		statement = "declare"
+	case *ast.ErrorStatement:
+		statement = "error"
+	case *ast.EsiStatement:
+		statement = "esi"
+	case *ast.GotoStatement:
+		statement = "goto"
+	case *ast.LogStatement:
+		statement = "log"
+	case *ast.RemoveStatement:
+		statement, variable = "remove", t.Ident.Value
+	case *ast.RestartStatement:
+		statement = "restart"
+	case *ast.ReturnStatement:
+		statement = "return"
+	case *ast.SetStatement:
+		statement, variable = "set", t.Ident.Value
+	case *ast.SyntheticStatement:
+		statement = "synthetic"
+	case *ast.SyntheticBase64Statement:
+		statement = "synthetic.base64"
+	case *ast.UnsetStatement:
+		statement, variable = "unset", t.Ident.Value
+	case *ast.FunctionCallStatement:
+		function = t.Function.Value
+	case *ast.FunctionCallExpression:
+		function = t.Function.Value
+	default:
+		return
+	}
+
+	defer l.profiler.record(POLICY_VIOLATION, time.Now())
+
+	tok := node.GetMeta().Token
+	for _, p := range l.policies {
+		if len(p.files) > 0 && !matchGlobs(p.files, tok.File) {
+			continue
+		}
+		phases := p.violatedPhases(ctx.Mode())
+		if phases == 0 {
+			continue
+		}
+
+		var target string
+		if _, ok := p.statements[statement]; ok {
+			target = fmt.Sprintf(`Statement "%s"`, statement)
+		} else if function != "" && matchGlobs(p.functions, function) {
+			target = fmt.Sprintf(`Function "%s"`, function)
+		} else if variable != "" && matchGlobs(p.variables, strings.ToLower(variable)) {
+			target = fmt.Sprintf(`Modifying variable "%s"`, variable)
+		} else {
+			continue
+		}
+
+		message := fmt.Sprintf(
+			"%s is prohibited in %s by policy %s",
+			target, strings.TrimSpace(context.ScopesString(phases)), p.name,
+		)
+		if p.message != "" {
+			message += ": " + p.message
+		}
+		err := &LintError{
+			Severity: p.severity,
+			Token:    tok,
+			Message:  message,
+		}
+		l.Error(err.Match(POLICY_VIOLATION))
+	}
+}
@@ -0,0 +1,134 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintPolicies(t *testing.T) {
+	policies := []*config.PolicyConfig{
+		{
+			Name:       "no-restart-in-deliver",
+			Statements: []string{"restart"},
+			Phases:     []string{"deliver"},
+		},
+		{
+			Name:         "synthetic-only-in-error",
+			Statements:   []string{"synthetic"},
+			ExceptPhases: []string{"vcl_error"},
+		},
+		{
+			Name:         "hash-only-in-hash",
+			Variables:    []string{"req.hash"},
+			ExceptPhases: []string{"hash"},
+			Severity:     "warning",
+		},
+		{
+			Name:      "no-digest-in-edge",
+			Functions: []string{"digest.*"},
+			Files:     []string{"edge/*.vcl"},
+		},
+	}
+
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.hash += req.url;
+  set req.http.Hash = digest.hash_sha256(req.url);
+  error 600;
+}
+
+sub vcl_hash {
+  #FASTLY HASH
+  set req.hash += req.http.host;
+  return (hash);
+}
+
+sub vcl_error {
+  #FASTLY ERROR
+  synthetic "error";
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  if (resp.status == 503) {
+    restart;
+  }
+  synthetic "deliver";
+  return (deliver);
+}`
+
+	tests := []struct {
+		file   string
+		expect []string
+	}{
+		{
+			file: "main.vcl",
+			expect: []string{
+				`Warning: Modifying variable "req.hash" is prohibited in RECV by policy hash-only-in-hash`,
+				`Error: Statement "restart" is prohibited in DELIVER by policy no-restart-in-deliver`,
+				`Error: Statement "synthetic" is prohibited in DELIVER by policy synthetic-only-in-error`,
+			},
+		},
+		{
+			file: "edge/main.vcl",
+			expect: []string{
+				`Warning: Modifying variable "req.hash" is prohibited in RECV by policy hash-only-in-hash`,
+				`Error: Function "digest.hash_sha256" is prohibited in RECV by policy no-digest-in-edge`,
+				`Error: Statement "restart" is prohibited in DELIVER by policy no-restart-in-deliver`,
+				`Error: Statement "synthetic" is prohibited in DELIVER by policy synthetic-only-in-error`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile(tt.file))).ParseVCL()
+		if err != nil {
+			t.Errorf("Unexpected parser error: %s", err)
+			return
+		}
+		l := New(WithPolicies(policies))
+		l.Lint(vcl, context.New())
+
+		var actual []string
+		for _, d := range l.Diagnostics {
+			if d.Rule == POLICY_VIOLATION {
+				actual = append(actual, string(d.Severity)+": "+d.Message)
+			}
+		}
+		if diff := cmp.Diff(tt.expect, actual); diff != "" {
+			t.Errorf("%s: policy violations mismatch, diff=%s", tt.file, diff)
+		}
+	}
+}
+
+func TestValidatePolicies(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *config.PolicyConfig
+	}{
+		{name: "unknown statement", policy: &config.PolicyConfig{Statements: []string{"purge"}}},
+		{name: "unknown phase", policy: &config.PolicyConfig{Statements: []string{"restart"}, Phases: []string{"init"}}},
+		{name: "invalid severity", policy: &config.PolicyConfig{Statements: []string{"restart"}, Severity: "fatal"}},
+		{name: "invalid glob", policy: &config.PolicyConfig{Functions: []string{"digest.[a"}}},
+		{
+			name:   "both phases",
+			policy: &config.PolicyConfig{Statements: []string{"restart"}, Phases: []string{"recv"}, ExceptPhases: []string{"hash"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePolicies([]*config.PolicyConfig{tt.policy}); err == nil {
+			t.Errorf("%s: expected error but got nil", tt.name)
+		}
+	}
+	if err := ValidatePolicies([]*config.PolicyConfig{{Statements: []string{"Restart"}, Phases: []string{"DELIVER"}}}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
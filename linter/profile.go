@@ -0,0 +1,151 @@
+package linter
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Prefix of pseudo rules of built-in lint passes.
+// Built-in rules are checked by the pass of each node type like lintSetStatement while walking the AST,
+// so their time is recorded per pass, e.g. builtin/set-statement.
+const profileBuiltinPrefix = "builtin/"
+
+// Custom rules are reordered by measured cost every this number of checked nodes
+const ruleReorderInterval = 256
+
+// RuleProfile is the time spent for the rule
+type RuleProfile struct {
+	Rule  Rule          `json:"rule"`
+	Calls int           `json:"calls"`
+	Time  time.Duration `json:"time"`
+}
+
+// Built-in pass which is running, time of nested passes and rules is excluded from the pass
+type profileFrame struct {
+	rule     Rule
+	start    time.Time
+	excluded time.Duration
+}
+
+type ruleProfiler struct {
+	profiles map[Rule]*RuleProfile
+	// Count of nodes which custom rules have checked
+	checks int
+	// Stack of built-in passes which are running
+	frames []profileFrame
+	// Pseudo rule names of built-in passes for each node type
+	passes map[reflect.Type]Rule
+}
+
+func newRuleProfiler() *ruleProfiler {
+	return &ruleProfiler{
+		profiles: make(map[Rule]*RuleProfile),
+		passes:   make(map[reflect.Type]Rule),
+	}
+}
+
+// Record the time spent for the rule since start, intended to be used with defer
+func (p *ruleProfiler) record(rule Rule, start time.Time) {
+	p.add(rule, time.Since(start))
+}
+
+func (p *ruleProfiler) add(rule Rule, elapsed time.Duration) {
+	v, ok := p.profiles[rule]
+	if !ok {
+		v = &RuleProfile{Rule: rule}
+		p.profiles[rule] = v
+	}
+	v.Calls++
+	v.Time += elapsed
+	// Exclude the time from the pass which runs the rule
+	if n := len(p.frames); n > 0 {
+		p.frames[n-1].excluded += elapsed
+	}
+}
+
+// Start the built-in pass of the node, must be paired with leave
+func (p *ruleProfiler) enter(node interface{}) {
+	rt := reflect.TypeOf(node)
+	rule, ok := p.passes[rt]
+	if !ok {
+		if rt.Kind() == reflect.Ptr {
+			rt = rt.Elem()
+		}
+		rule = Rule(profileBuiltinPrefix + kebabCase(rt.Name()))
+		p.passes[reflect.TypeOf(node)] = rule
+	}
+	p.frames = append(p.frames, profileFrame{rule: rule, start: time.Now()})
+}
+
+// Finish the built-in pass which is started at last, and record its own time
+func (p *ruleProfiler) leave() {
+	n := len(p.frames)
+	f := p.frames[n-1]
+	p.frames = p.frames[:n-1]
+	elapsed := time.Since(f.start)
+	p.add(f.rule, elapsed-f.excluded)
+	// Parent pass excludes whole time of the nested pass, not only its own time
+	if n > 1 {
+		p.frames[n-2].excluded += f.excluded
+	}
+}
+
+// Convert the type name like SetStatement or VCL to set-statement or vcl
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Split words at the lower to upper boundary, or before the last upper of the acronym like IPLiteral
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Average time spent for the rule per call
+func (p *ruleProfiler) cost(rule Rule) time.Duration {
+	v, ok := p.profiles[rule]
+	if !ok || v.Calls == 0 {
+		return 0
+	}
+	return v.Time / time.Duration(v.Calls)
+}
+
+func (p *ruleProfiler) total() time.Duration {
+	var total time.Duration
+	for _, v := range p.profiles {
+		total += v.Time
+	}
+	return total
+}
+
+// Order custom rules by measured cost so that cheap rules run first.
+// It makes fail-fast mode stop earlier and is stable for rules which have the same cost.
+func (l *Linter) reorderCustomRules() {
+	sort.SliceStable(l.customRules, func(i, j int) bool {
+		return l.profiler.cost(l.customRules[i].Name()) < l.profiler.cost(l.customRules[j].Name())
+	})
+}
+
+// Profiles returns time spent for each rule in descending order of the time
+func (l *Linter) Profiles() []*RuleProfile {
+	profiles := make([]*RuleProfile, 0, len(l.profiler.profiles))
+	for _, v := range l.profiler.profiles {
+		profiles = append(profiles, v)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Time == profiles[j].Time {
+			return profiles[i].Rule < profiles[j].Rule
+		}
+		return profiles[i].Time > profiles[j].Time
+	})
+	return profiles
+}
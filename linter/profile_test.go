@@ -0,0 +1,123 @@
+package linter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+// Rule which spends time for every node
+type slowRule struct{}
+
+func (r *slowRule) Name() Rule {
+	return "test/slow"
+}
+
+func (r *slowRule) Check(node ast.Node, ctx *context.Context) []*LintError {
+	time.Sleep(10 * time.Microsecond)
+	return nil
+}
+
+// Rule which does nothing
+type cheapRule struct{}
+
+func (r *cheapRule) Name() Rule {
+	return "test/cheap"
+}
+
+func (r *cheapRule) Check(node ast.Node, ctx *context.Context) []*LintError {
+	return nil
+}
+
+func TestFailFast(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  call undefined_subroutine;
+  call another_undefined_subroutine;
+}`
+
+	tests := []struct {
+		name     string
+		failFast bool
+		expect   int
+	}{
+		{name: "report all errors", expect: 2},
+		{name: "stop at the first error", failFast: true, expect: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New(WithFailFast(tt.failFast))
+			l.Lint(vcl, context.New())
+
+			var errors int
+			for _, d := range l.Diagnostics {
+				if d.Severity == ERROR {
+					errors++
+				}
+			}
+			if errors != tt.expect {
+				t.Errorf("Error count unmatch, expect=%d, got=%d", tt.expect, errors)
+			}
+		})
+	}
+}
+
+func TestRuleProfiles(t *testing.T) {
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = "foo";
+}`
+
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		return
+	}
+	l := New(WithCustomRules(&slowRule{}, &cheapRule{}))
+	start := time.Now()
+	l.Lint(vcl, context.New())
+	elapsed := time.Since(start)
+
+	profiles := make(map[Rule]*RuleProfile)
+	for _, p := range l.Profiles() {
+		profiles[p.Rule] = p
+	}
+	for _, rule := range []Rule{
+		"test/slow",
+		"test/cheap",
+		"builtin/vcl",
+		"builtin/subroutine-declaration",
+		"builtin/set-statement",
+		"builtin/string",
+		UNUSED_DECLARATION,
+	} {
+		if _, ok := profiles[rule]; !ok {
+			t.Errorf("Profile of %s is not found", rule)
+		}
+	}
+	if profiles["test/slow"].Calls == 0 || profiles["test/slow"].Calls != profiles["test/cheap"].Calls {
+		t.Errorf("Custom rules must be called for each node, slow=%d, cheap=%d", profiles["test/slow"].Calls, profiles["test/cheap"].Calls)
+	}
+	// Time of custom rules and nested passes must be excluded from each pass
+	if total := l.profiler.total(); total > elapsed {
+		t.Errorf("Profiled time must not exceed the elapsed time, total=%s, elapsed=%s", total, elapsed)
+	}
+
+	// Cheap rule runs first after reordering
+	l.reorderCustomRules()
+	if l.customRules[0].Name() != "test/cheap" {
+		t.Errorf("Custom rules must be ordered by cost, got %s first", l.customRules[0].Name())
+	}
+}
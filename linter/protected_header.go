@@ -0,0 +1,66 @@
+package linter
+
+import (
+	"strings"
+)
+
+// Some HTTP Headers is protected in Fastly.
+// @see https://developer.fastly.com/reference/http/http-headers/
+// Consider the character case, we always treat header names as lower-case.
+var protectedHeaderNames = map[string]struct{}{
+	"req.http.proxy-authenticate":  {},
+	"req.http.proxy-authorization": {},
+	"req.http.content-length":      {},
+	"req.http.content-range":       {},
+	"req.http.te":                  {},
+	"req.http.trailer":             {},
+	"req.http.expect":              {},
+	"req.http.transfer-encoding":   {},
+	"req.http.upgrade":             {},
+	"req.http.fastly-ff":           {},
+}
+
+// Protected header names, keys are lower-cased variable names like "req.http.fastly-ff"
+// or bare header names like "x-internal-auth" which match HTTP header of any variable
+type protectedHeaders map[string]struct{}
+
+func defaultProtectedHeaders() protectedHeaders {
+	p := protectedHeaders{}
+	for name := range protectedHeaderNames {
+		p[name] = struct{}{}
+	}
+	return p
+}
+
+// Add protected headers which are reserved by the user
+func (p protectedHeaders) add(names []string) {
+	for i := range names {
+		p[strings.ToLower(names[i])] = struct{}{}
+	}
+}
+
+// Allow modifying protected headers, bare header name allows the header of any variable
+func (p protectedHeaders) allow(names []string) {
+	for i := range names {
+		name := strings.ToLower(names[i])
+		for key := range p {
+			if key == name || (!strings.Contains(name, ".") && httpHeaderName(key) == name) {
+				delete(p, key)
+			}
+		}
+	}
+}
+
+// Check header name exists in protected header names
+func (p protectedHeaders) contains(name string) bool {
+	lower := strings.ToLower(name)
+	if _, ok := p[lower]; ok {
+		return true
+	}
+	header := httpHeaderName(lower)
+	if header == "" {
+		return false
+	}
+	_, ok := p[header]
+	return ok
+}
@@ -0,0 +1,91 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintProtectedHeaders(t *testing.T) {
+	lintWithProtectedHeaders := func(t *testing.T, c *config.ProtectedHeaderConfig, input string) *Linter {
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Fatalf("unexpected parser error: %s", err)
+		}
+		l := New(WithProtectedHeaders(c))
+		l.lint(vcl, context.New())
+		return l
+	}
+
+	tests := []struct {
+		name   string
+		config *config.ProtectedHeaderConfig
+		input  string
+		expect int
+	}{
+		{
+			name:   "default protected header",
+			config: nil,
+			input: `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.http.Fastly-FF = "1";
+}`,
+			expect: 1,
+		},
+		{
+			name:   "user reserved header name applies to any variable",
+			config: &config.ProtectedHeaderConfig{Headers: []string{"X-Internal-Auth"}},
+			input: `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.http.X-Internal-Auth = "1";
+   unset req.http.x-internal-auth;
+}
+sub vcl_deliver {
+   #FASTLY DELIVER
+   set resp.http.X-Internal-Auth = "1";
+}`,
+			expect: 3,
+		},
+		{
+			name:   "user reserved variable name",
+			config: &config.ProtectedHeaderConfig{Headers: []string{"req.http.X-Internal-Auth"}},
+			input: `
+sub vcl_deliver {
+   #FASTLY DELIVER
+   set resp.http.X-Internal-Auth = "1";
+}`,
+			expect: 0,
+		},
+		{
+			name:   "allow default protected header",
+			config: &config.ProtectedHeaderConfig{Allow: []string{"Fastly-FF"}},
+			input: `
+sub vcl_recv {
+   #FASTLY RECV
+   set req.http.Fastly-FF = "1";
+   set req.http.Upgrade = "websocket";
+}`,
+			expect: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lintWithProtectedHeaders(t, tt.config, tt.input)
+			var count int
+			for _, d := range l.Diagnostics {
+				if d.Rule == PROTECTED_HEADER {
+					count++
+				}
+			}
+			if count != tt.expect {
+				t.Errorf("Expects %d %s errors but got %v", tt.expect, PROTECTED_HEADER, l.Diagnostics)
+			}
+		})
+	}
+}
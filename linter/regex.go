@@ -0,0 +1,143 @@
+package linter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	regexp "go.elara.ws/pcre"
+)
+
+// Functions which accept regular expression pattern, value is the index of pattern argument
+var regexPatternArguments = map[string]int{
+	"regsub":                       1,
+	"regsuball":                    1,
+	"querystring.regfilter":        1,
+	"querystring.regfilter_except": 1,
+}
+
+// Fastly compiles regular expression with PCRE2, so the pattern is validated by PCRE2 engine
+// instead of Go's regexp which does not support atomic group, lookbehind, possessive quantifier and so on.
+// Note that compiled regexp is released by finalizer of the library, calling Close() explicitly causes double free.
+func validateRegex(pattern string) error {
+	_, err := regexp.Compile(pattern)
+	return err
+}
+
+func (l *Linter) lintRegexPattern(node *ast.String) {
+	if err := validateRegex(node.Value); err != nil {
+		err := &LintError{
+			Severity: ERROR,
+			Token:    node.GetMeta().Token,
+			Message:  fmt.Sprintf("regex string is invalid, %s", err),
+		}
+		l.Error(err.Match(VALID_REGEX))
+	}
+}
+
+func (l *Linter) lintRegexFunctionArguments(exp *ast.FunctionCallExpression) {
+	index, ok := regexPatternArguments[exp.Function.Value]
+	if !ok || len(exp.Arguments) <= index {
+		return
+	}
+	// Only literal pattern could be validated statically
+	if v, ok := exp.Arguments[index].(*ast.String); ok {
+		l.lintRegexPattern(v)
+	}
+}
+
+// Capture group count of the latest regex matching in the subroutine.
+// Zero or positive value is the count of capture groups, and following special values are used.
+const (
+	// No regex matching has run in the subroutine yet
+	regexGroupsNone = -2
+	// Capture group count could not be determined, e.g. the pattern is not a string literal
+	regexGroupsUnknown = -1
+)
+
+// Count capture groups of regex matchings in the expression.
+// If the expression has multiple regex matchings, returns the largest count because any of them could be the latest one.
+func regexCaptureGroups(exp ast.Expression) int {
+	switch t := exp.(type) {
+	case *ast.PrefixExpression:
+		return regexCaptureGroups(t.Right)
+	case *ast.GroupedExpression:
+		return regexCaptureGroups(t.Right)
+	case *ast.InfixExpression:
+		if t.Operator != "~" && t.Operator != "!~" {
+			return mergeRegexGroups(regexCaptureGroups(t.Left), regexCaptureGroups(t.Right))
+		}
+		v, ok := t.Right.(*ast.String)
+		if !ok {
+			return regexGroupsUnknown
+		}
+		re, err := regexp.Compile(v.Value)
+		if err != nil {
+			return regexGroupsUnknown
+		}
+		return re.NumSubexp()
+	}
+	return regexGroupsNone
+}
+
+// Merge capture group counts of the flow paths which join.
+// Unknown count wins over any count, and none is ignored because re.group.N variables are not changed in the path.
+func mergeRegexGroups(a, b int) int {
+	switch {
+	case a == regexGroupsUnknown || b == regexGroupsUnknown:
+		return regexGroupsUnknown
+	case a == regexGroupsNone:
+		return b
+	case b == regexGroupsNone:
+		return a
+	case a > b:
+		return a
+	default:
+		return b
+	}
+}
+
+// Find positive regex matching which is evaluated in the condition always like `req.url ~ "..."`.
+func isDirectRegexMatching(cond ast.Expression) bool {
+	switch t := cond.(type) {
+	case *ast.GroupedExpression:
+		return isDirectRegexMatching(t.Right)
+	case *ast.InfixExpression:
+		return t.Operator == "~"
+	}
+	return false
+}
+
+// Update capture group count by regex matchings in the condition,
+// and returns the count which is used in the consequence of the condition.
+// Note that re.group.N variables are not changed when regex does not match,
+// so the count after the condition is merged with the previous one.
+func (l *Linter) enterRegexCondition(cond ast.Expression) int {
+	groups := regexCaptureGroups(cond)
+	l.regexGroups = mergeRegexGroups(l.regexGroups, groups)
+	if groups != regexGroupsNone && isDirectRegexMatching(cond) {
+		// Consequence is reached only when the regex matched
+		return groups
+	}
+	return l.regexGroups
+}
+
+func (l *Linter) lintRegexGroupReference(exp *ast.Ident) {
+	if l.regexGroups < 0 || !strings.HasPrefix(exp.Value, "re.group.") {
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(exp.Value, "re.group."))
+	if err != nil || n <= l.regexGroups {
+		return
+	}
+	e := &LintError{
+		Severity: ERROR,
+		Token:    exp.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"%s is referenced but the latest regex matching has only %d capture groups",
+			exp.Value, l.regexGroups,
+		),
+	}
+	l.Error(e.Match(REGEX_GROUP_OUT_OF_RANGE))
+}
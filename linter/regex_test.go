@@ -0,0 +1,152 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintRegexFunctionArguments(t *testing.T) {
+	t.Run("pass with PCRE pattern", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+	#FASTLY RECV
+	set req.url = regsub(req.url, "(?<=/)(?>foo|bar)++", "baz");
+	set req.url = querystring.regfilter(req.url, "^utm_");
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("error: invalid regsub pattern", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+	#FASTLY RECV
+	set req.url = regsuball(req.url, "^/(foo", "");
+}`
+		assertErrorWithSeverity(t, input, ERROR)
+	})
+
+	t.Run("error: invalid querystring filter pattern", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+	#FASTLY RECV
+	set req.url = querystring.regfilter_except(req.url, "[a-");
+}`
+		assertErrorWithSeverity(t, input, ERROR)
+	})
+}
+
+func TestLintRegexGroupReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect int
+	}{
+		{
+			name: "group is in range",
+			input: `
+sub vcl_recv {
+	#FASTLY RECV
+	if (req.url ~ "^/(foo)/(?:bar)/(baz)") {
+		set req.http.First = re.group.1;
+		set req.http.Second = re.group.2;
+	}
+}`,
+			expect: 0,
+		},
+		{
+			name: "group exceeds capture count in consequence",
+			input: `
+sub vcl_recv {
+	#FASTLY RECV
+	if (req.url ~ "^/(foo)/(bar)") {
+		set req.http.Fifth = re.group.5;
+	}
+}`,
+			expect: 1,
+		},
+		{
+			name: "any of previous regex may have enough groups",
+			input: `
+sub vcl_recv {
+	#FASTLY RECV
+	if (req.url ~ "^/(a)/(b)/(c)") {
+		set req.http.Matched = "1";
+	}
+	if (req.http.Host) {
+		if (req.http.Host ~ "^(www)\.") {
+			set req.http.Matched = "1";
+		}
+	}
+	set req.http.Third = re.group.3;
+}`,
+			expect: 0,
+		},
+		{
+			name: "group exceeds capture count after branches",
+			input: `
+sub vcl_recv {
+	#FASTLY RECV
+	if (req.url ~ "^/(a)") {
+		set req.http.Matched = "1";
+	} else if (req.url ~ "^/(a)/(b)") {
+		set req.http.Matched = "2";
+	}
+	set req.http.Third = re.group.3;
+}`,
+			expect: 1,
+		},
+		{
+			name: "pattern is not a literal",
+			input: `
+sub vcl_recv {
+	#FASTLY RECV
+	declare local var.pattern STRING;
+	set var.pattern = "^/(a)/(b)";
+	if (req.url ~ var.pattern) {
+		set req.http.Third = re.group.3;
+	}
+}`,
+			expect: 0,
+		},
+		{
+			name: "called subroutine may run regex matching",
+			input: `
+sub match {
+	if (req.url ~ "^/(a)/(b)/(c)") {
+		set req.http.Matched = "1";
+	}
+}
+sub vcl_recv {
+	#FASTLY RECV
+	if (req.url ~ "^/(a)") {
+		call match;
+		set req.http.Third = re.group.3;
+	}
+}`,
+			expect: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Fatalf("unexpected parser error: %s", err)
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			var count int
+			for _, d := range l.Diagnostics {
+				if d.Rule == REGEX_GROUP_OUT_OF_RANGE {
+					count++
+				}
+			}
+			if count != tt.expect {
+				t.Errorf("Expects %d %s errors but got %v", tt.expect, REGEX_GROUP_OUT_OF_RANGE, l.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/token"
+)
+
+// Relate the first declaration to the duplicated declaration diagnostic
+func relateFirstDeclaration(d *Diagnostic, first token.Token) *Diagnostic {
+	return d.Relate(first, "First declaration is here")
+}
+
+// Collect declaration tokens of backends and directors which can be referred as backend
+func backendTokens(ctx *context.Context) map[string]token.Token {
+	tokens := make(map[string]token.Token)
+	for name, b := range ctx.Backends {
+		switch {
+		case b.BackendDecl != nil:
+			tokens[name] = b.BackendDecl.GetMeta().Token
+		case b.DirectorDecl != nil:
+			tokens[name] = b.DirectorDecl.GetMeta().Token
+		}
+	}
+	return tokens
+}
+
+func aclTokens(ctx *context.Context) map[string]token.Token {
+	tokens := make(map[string]token.Token)
+	for name, a := range ctx.Acls {
+		if a.Decl != nil {
+			tokens[name] = a.Decl.GetMeta().Token
+		}
+	}
+	return tokens
+}
+
+func tableTokens(ctx *context.Context) map[string]token.Token {
+	tokens := make(map[string]token.Token)
+	for name, t := range ctx.Tables {
+		if t.Decl != nil {
+			tokens[name] = t.Decl.GetMeta().Token
+		}
+	}
+	return tokens
+}
+
+func subroutineTokens(ctx *context.Context) map[string]token.Token {
+	tokens := make(map[string]token.Token)
+	for name, s := range ctx.Subroutines {
+		if s.Decl != nil {
+			tokens[name] = s.Decl.GetMeta().Token
+		}
+	}
+	return tokens
+}
+
+// Relate the declaration which has the most similar name to the undefined name diagnostic
+func relateSimilarName(d *Diagnostic, name string, declarations ...map[string]token.Token) *Diagnostic {
+	candidates := make(map[string]token.Token)
+	for _, decls := range declarations {
+		for k, v := range decls {
+			candidates[k] = v
+		}
+	}
+	names := make([]string, 0, len(candidates))
+	for k := range candidates {
+		names = append(names, k)
+	}
+	// Sort names to find the same candidate when multiple names have the same distance
+	sort.Strings(names)
+
+	if similar := nearestName(name, names); similar != "" {
+		d.Relate(candidates[similar], fmt.Sprintf(`Similar name "%s" is declared`, similar))
+	}
+	return d
+}
+
+// Find the most similar name by edit distance, returns empty string if no names are similar enough
+func nearestName(name string, names []string) string {
+	var nearest string
+	// Allow about one edit per three characters, at least two edits
+	threshold := len(name) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+	for _, n := range names {
+		if n == name {
+			continue
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(n)); d <= threshold {
+			nearest, threshold = n, d-1
+		}
+	}
+	return nearest
+}
+
+// Levenshtein distance of two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
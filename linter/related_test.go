@@ -0,0 +1,167 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/snippets"
+)
+
+func lintRelated(t *testing.T, input string, opts ...context.Option) []*Diagnostic {
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("unexpected parser error: %s", err)
+		t.FailNow()
+	}
+	l := New()
+	l.lint(vcl, context.New(opts...))
+	if l.FatalError != nil {
+		t.Errorf("Fatal error: %s", l.FatalError.Error)
+	}
+	return l.Diagnostics
+}
+
+// Find the diagnostic which has related information, returns nil if not found.
+// Empty rule matches any diagnostic
+func findRelated(diagnostics []*Diagnostic, rule Rule) *Diagnostic {
+	for _, d := range diagnostics {
+		if (rule == "" || d.Rule == rule) && len(d.Related) > 0 {
+			return d
+		}
+	}
+	return nil
+}
+
+func TestRelateFirstDeclaration(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		rule  Rule
+		line  int
+	}{
+		{
+			name: "duplicated acl",
+			input: `
+acl internal {
+  "192.0.2.0"/24;
+}
+
+acl internal {
+  "198.51.100.0"/24;
+}`,
+			rule: ACL_DUPLICATED,
+			line: 2,
+		},
+		{
+			name: "duplicated table",
+			input: `
+table routes {
+  "/": "root",
+}
+
+table routes {
+  "/": "root",
+}`,
+			rule: TABLE_DUPLICATED,
+			line: 2,
+		},
+		{
+			name: "duplicated backend",
+			input: `
+backend origin {
+  .host = "example.com";
+}
+
+backend origin {
+  .host = "example.org";
+}`,
+			rule: BACKEND_DUPLICATED,
+			line: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		d := findRelated(lintRelated(t, tt.input), tt.rule)
+		if d == nil {
+			t.Errorf("%s: diagnostic should relate the first declaration", tt.name)
+			continue
+		}
+		if d.Related[0].Token.Line != tt.line {
+			t.Errorf("%s: related line mismatch, expect=%d, got=%d", tt.name, tt.line, d.Related[0].Token.Line)
+		}
+		if d.Related[0].Message != "First declaration is here" {
+			t.Errorf("%s: unexpected related message %s", tt.name, d.Related[0].Message)
+		}
+	}
+}
+
+func TestRelateSimilarName(t *testing.T) {
+	input := `
+backend origin_main {
+  .host = "example.com";
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  set req.backend = origin_man;
+}`
+	d := findRelated(lintRelated(t, input), "")
+	if d == nil {
+		t.Errorf("Undefined backend diagnostic should relate similar backend")
+		return
+	}
+	if diff := cmp.Diff(`Similar name "origin_main" is declared`, d.Related[0].Message); diff != "" {
+		t.Errorf("Related message mismatch, diff=%s", diff)
+	}
+	if d.Related[0].Token.Line != 2 {
+		t.Errorf("Related line mismatch, expect=2, got=%d", d.Related[0].Token.Line)
+	}
+}
+
+func TestNearestName(t *testing.T) {
+	names := []string{"F_origin", "origin_main", "origin_sub", "shield"}
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{name: "origin_man", expect: "origin_main"},
+		{name: "ORIGIN_SUB", expect: "origin_sub"},
+		{name: "shiled", expect: "shield"},
+		{name: "fallback", expect: ""},
+	}
+
+	for _, tt := range tests {
+		if actual := nearestName(tt.name, names); actual != tt.expect {
+			t.Errorf("Nearest name of %s mismatch, expect=%s, got=%s", tt.name, tt.expect, actual)
+		}
+	}
+}
+
+func TestRelateSnippetInjectionSite(t *testing.T) {
+	s := &snippets.Snippets{
+		IncludeSnippets: map[string]snippets.SnippetItem{
+			"recv_injection": {
+				Name: "recv_injection",
+				Data: `set req.http.InjectedViaMacro = 1;`,
+			},
+		},
+	}
+	input := `
+sub vcl_recv {
+  #FASTLY RECV
+  include "snippet::recv_injection";
+}`
+	for _, d := range lintRelated(t, input, context.WithSnippets(s)) {
+		if d.Token.File != "snippet::recv_injection" {
+			continue
+		}
+		if len(d.Related) == 0 || d.Related[0].Token.Line != 4 {
+			t.Errorf("Diagnostic in snippet should relate the include statement, got %v", d.Related)
+		}
+		return
+	}
+	t.Errorf("Diagnostic in snippet should be reported")
+}
@@ -0,0 +1,114 @@
+package linter
+
+import (
+	"sort"
+	"strings"
+)
+
+// ReportPosition is a location in the source file, Line and Column start from 1
+type ReportPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// ReportSpan is a span of the source file, End points to the next position of the last character
+type ReportSpan struct {
+	Start ReportPosition `json:"start"`
+	End   ReportPosition `json:"end"`
+}
+
+// ReportRelated is a secondary location of the diagnostic like the first declaration of duplicated one
+type ReportRelated struct {
+	File    string     `json:"file"`
+	Span    ReportSpan `json:"span"`
+	Message string     `json:"message"`
+}
+
+// ReportDiagnostic is a lint result in the structured report
+type ReportDiagnostic struct {
+	Rule      Rule             `json:"rule,omitempty"`
+	Category  Category         `json:"category,omitempty"`
+	Severity  string           `json:"severity"`
+	File      string           `json:"file"`
+	Span      ReportSpan       `json:"span"`
+	Message   string           `json:"message"`
+	Reference string           `json:"reference,omitempty"`
+	Related   []*ReportRelated `json:"related,omitempty"`
+	// True when the diagnostic has suggested fix which could be applied by --fix option
+	Fixable bool `json:"fixable"`
+}
+
+// ReportSummary is the count of diagnostics per severity
+type ReportSummary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Infos    int `json:"infos"`
+}
+
+// Report is the structured lint result which could be consumed by dashboards and bots
+// instead of parsing human readable console output. Fields are stable across versions.
+type Report struct {
+	Summary     ReportSummary       `json:"summary"`
+	Diagnostics []*ReportDiagnostic `json:"diagnostics"`
+}
+
+// NewReport creates the report from diagnostics, diagnostics are sorted by file and location.
+// Ignored diagnostics are not included.
+func NewReport(diagnostics []*Diagnostic) *Report {
+	r := &Report{
+		Diagnostics: []*ReportDiagnostic{},
+	}
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case ERROR:
+			r.Summary.Errors++
+		case WARNING:
+			r.Summary.Warnings++
+		case INFO:
+			r.Summary.Infos++
+		default:
+			continue
+		}
+
+		rd := &ReportDiagnostic{
+			Rule:      d.Rule,
+			Severity:  strings.ToLower(string(d.Severity)),
+			File:      d.Token.File,
+			Span:      reportSpan(d.Range()),
+			Message:   d.Message,
+			Reference: d.Reference,
+			Fixable:   d.Fix != nil,
+		}
+		// Errors which are reported by external policy engine may not have the rule
+		if d.Rule != "" {
+			rd.Category = d.Rule.Category()
+		}
+		for _, rel := range d.Related {
+			rd.Related = append(rd.Related, &ReportRelated{
+				File:    rel.Token.File,
+				Span:    reportSpan(rel.Range()),
+				Message: rel.Message,
+			})
+		}
+		r.Diagnostics = append(r.Diagnostics, rd)
+	}
+
+	sort.SliceStable(r.Diagnostics, func(i, j int) bool {
+		a, b := r.Diagnostics[i], r.Diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Span.Start.Line != b.Span.Start.Line {
+			return a.Span.Start.Line < b.Span.Start.Line
+		}
+		return a.Span.Start.Column < b.Span.Start.Column
+	})
+	return r
+}
+
+func reportSpan(r Range) ReportSpan {
+	return ReportSpan{
+		Start: ReportPosition{Line: r.Start.Line, Column: r.Start.Column},
+		End:   ReportPosition{Line: r.End.Line, Column: r.End.Column},
+	}
+}
@@ -0,0 +1,78 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestNewReport(t *testing.T) {
+	input := `
+acl internal {
+  "10.0.0.1";
+}
+acl internal {
+  "10.0.0.2";
+}
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.foo STRING;
+  if (client.ip ~ internal) {
+    set req.http.Foo = "1";
+  }
+}`
+	vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile("main.vcl"))).ParseVCL()
+	if err != nil {
+		t.Fatalf("unexpected parser error: %s", err)
+	}
+	l := New()
+	l.Lint(vcl, context.New())
+
+	report := NewReport(l.Diagnostics)
+	expect := &Report{
+		Summary: ReportSummary{Errors: 1, Warnings: 1},
+		Diagnostics: []*ReportDiagnostic{
+			{
+				Rule:     ACL_DUPLICATED,
+				Category: CORRECTNESS,
+				Severity: "error",
+				File:     "main.vcl",
+				Span: ReportSpan{
+					Start: ReportPosition{Line: 5, Column: 5},
+					End:   ReportPosition{Line: 5, Column: 13},
+				},
+				Message:   `Duplicate definition of ACL "internal"`,
+				Reference: Rule(ACL_DUPLICATED).Reference(),
+				Related: []*ReportRelated{
+					{
+						File: "main.vcl",
+						Span: ReportSpan{
+							Start: ReportPosition{Line: 2, Column: 1},
+							End:   ReportPosition{Line: 2, Column: 4},
+						},
+						Message: "First declaration is here",
+					},
+				},
+			},
+			{
+				Rule:     UNUSED_VARIABLE,
+				Category: STYLE,
+				Severity: "warning",
+				File:     "main.vcl",
+				Span: ReportSpan{
+					Start: ReportPosition{Line: 10, Column: 3},
+					End:   ReportPosition{Line: 10, Column: 10},
+				},
+				Message:   `Variable "foo" is unused`,
+				Reference: Rule(UNUSED_VARIABLE).Reference(),
+				Fixable:   true,
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, report); diff != "" {
+		t.Errorf("Report mismatch, diff=%s", diff)
+	}
+}
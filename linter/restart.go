@@ -0,0 +1,86 @@
+package linter
+
+import (
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Report restart statement which is always executed in vcl_recv.
+// Restarted request runs vcl_recv again, so the request restarts until it exceeds the limit
+// unless req.restarts is checked before the restart statement.
+func (l *Linter) lintRestartLoops(ctx *context.Context) {
+	recv, ok := ctx.Subroutines["vcl_recv"]
+	if !ok || recv.Decl == nil {
+		return
+	}
+	l.findUnconditionalRestart(recv.Decl.Block.Statements, ctx, nil)
+}
+
+// Walk statements which are always executed, returns true when the rest of caller statements are not executed
+// because the request exits, restarts or checks req.restarts.
+// Statements in if statements are not walked because they are executed conditionally.
+func (l *Linter) findUnconditionalRestart(statements []ast.Statement, ctx *context.Context, calls []*ast.CallStatement) bool {
+	for _, stmt := range statements {
+		switch t := stmt.(type) {
+		case *ast.BlockStatement:
+			if l.findUnconditionalRestart(t.Statements, ctx, calls) {
+				return true
+			}
+		case *ast.IfStatement:
+			if checksRestarts(t) {
+				return true
+			}
+		case *ast.CallStatement:
+			sub, ok := ctx.Subroutines[t.Subroutine.Value]
+			if !ok || sub.Decl == nil || isCalledFrom(calls, t.Subroutine.Value) {
+				continue
+			}
+			if l.findUnconditionalRestart(sub.Decl.Block.Statements, ctx, append(calls[:len(calls):len(calls)], t)) {
+				return true
+			}
+		case *ast.ReturnStatement:
+			// Bare return in called subroutine continues caller statements
+			return len(calls) == 0 || t.ReturnExpression != nil
+		case *ast.ErrorStatement:
+			return true
+		case *ast.RestartStatement:
+			err := &LintError{
+				Severity: WARNING,
+				Token:    t.GetMeta().Token,
+				Message: "restart statement is always executed in vcl_recv without checking req.restarts, " +
+					"the request restarts until it exceeds the limit",
+			}
+			for _, c := range calls {
+				err.Relate(c.GetMeta().Token, "Subroutine "+c.Subroutine.Value+" is always called here")
+			}
+			l.Error(err.Match(RESTART_STATEMENT_LOOP))
+			return true
+		}
+	}
+	return false
+}
+
+// Check any condition of the if statement refers req.restarts
+func checksRestarts(stmt *ast.IfStatement) bool {
+	conditions := []ast.Expression{stmt.Condition}
+	for _, a := range stmt.Another {
+		conditions = append(conditions, a.Condition)
+	}
+	for _, c := range conditions {
+		for _, ident := range collectIdents(c) {
+			if ident.Value == "req.restarts" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isCalledFrom(calls []*ast.CallStatement, name string) bool {
+	for _, c := range calls {
+		if c.Subroutine.Value == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintRestartLoops(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  []int
+		related int
+	}{
+		{
+			name: "unconditional restart",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = "foo";
+  restart;
+}`,
+			expect: []int{5},
+		},
+		{
+			name: "restart in subroutine always called",
+			input: `
+sub do_restart {
+  restart;
+}
+
+sub prepare {
+  set req.http.Foo = "foo";
+  call do_restart;
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  call prepare;
+}`,
+			expect:  []int{3},
+			related: 2,
+		},
+		{
+			name: "guarded by req.restarts",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.restarts > 0) {
+    return(lookup);
+  }
+  restart;
+}`,
+		},
+		{
+			name: "conditional restart",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.url ~ "^/old/") {
+    set req.url = regsub(req.url, "^/old/", "/new/");
+    restart;
+  }
+}`,
+		},
+		{
+			name: "bare return in called subroutine",
+			input: `
+sub check {
+  return;
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  call check;
+  restart;
+}`,
+			expect: []int{9},
+		},
+		{
+			name: "request exits before restart",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  return(lookup);
+  restart;
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.Lint(vcl, context.New())
+
+			var lines []int
+			for _, d := range l.Diagnostics {
+				if d.Rule != RESTART_STATEMENT_LOOP {
+					continue
+				}
+				lines = append(lines, d.Token.Line)
+				if len(d.Related) != tt.related {
+					t.Errorf("Expects %d related information but got %d", tt.related, len(d.Related))
+				}
+			}
+			if diff := cmp.Diff(tt.expect, lines); diff != "" {
+				t.Errorf("Restart loop lines unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
@@ -10,63 +10,128 @@ func (r Rule) Reference() string {
 }
 
 const (
-	ACL_SYNTAX                           = "acl/syntax"
-	ACL_DUPLICATED                       = "acl/duplicated"
-	BACKEND_SYNTAX                       = "backend/syntax"
-	BACKEND_DUPLICATED                   = "backend/duplicated"
-	BACKEND_NOTFOUND                     = "backend/notfound"
-	BACKEND_PROBER_CONFIGURATION         = "backend/prober-configuration"
-	DIRECTOR_SYNTAX                      = "director/syntax"
-	DIRECTOR_DUPLICATED                  = "director/duplicated"
-	DIRECTOR_PROPS_RANDOM                = "director/props-random"
-	DIRECTOR_PROPS_FALLBACK              = "director/props-fallback"
-	DIRECTOR_PROPS_HASH                  = "director/props-hash"
-	DIRECTOR_PROPS_CLIENT                = "director/props-client"
-	DIRECTOR_PROPS_CHASH                 = "director/props-chash"
-	DIRECTOR_BACKEND_REQUIRED            = "director/backend-required"
-	TABLE_SYNTAX                         = "table/syntax"
-	TABLE_TYPE_VARIATION                 = "table/type-variation"
-	TABLE_ITEM_LIMITATION                = "table/item-limitation"
-	TABLE_DUPLICATED                     = "table/duplicated"
-	SUBROUTINE_SYNTAX                    = "subroutine/syntax"
-	SUBROUTINE_BOILERPLATE_MACRO         = "subroutine/boilerplate-macro"
-	SUBROUTINE_DUPLICATED                = "subroutine/duplicated"
-	SUBROUTINE_INVALID_RETURN_TYPE       = "subroutine/invalid-return-type"
-	PENALTYBOX_SYNTAX                    = "penaltybox/syntax"
-	PENALTYBOX_DUPLICATED                = "penaltybox/duplicated"
-	PENALTYBOX_NONEMPTY_BLOCK            = "penaltybox/nonempty-block"
-	RATECOUNTER_SYNTAX                   = "ratecounter/syntax"
-	RATECOUNTER_DUPLICATED               = "ratecounter/duplicated"
-	RATECOUNTER_NONEMPTY_BLOCK           = "ratecounter/nonempty-block"
-	DECLARE_STATEMENT_SYNTAX             = "declare-statement/syntax"
-	DECLARE_STATEMENT_INVALID_TYPE       = "declare-statement/invalid-type"
-	DECLARE_STATEMENT_DUPLICATED         = "declare-statement/duplicated"
-	SET_STATEMENT_SYNTAX                 = "set-statement/syntax"
-	OPERATOR_ASSIGNMENT                  = "operator/assignment"
-	UNSET_STATEMENT_SYNTAX               = "unset-statement/syntax"
-	REMOVE_STATEMENT_SYNTAX              = "remote-statement/syntax"
-	OPERATOR_CONDITIONAL                 = "operator/conditional"
-	RESTART_STATEMENT_SCOPE              = "restart-statement/scope"
-	ADD_STATEMENT_SYNTAX                 = "add-statement/syntax"
-	CALL_STATEMENT_SYNTAX                = "call-statement/syntax"
-	CALL_STATEMENT_SUBROUTINE_NOTFOUND   = "call-statement/subroutine-notfound"
-	ERROR_STATEMENT_SCOPE                = "error-statement/scope"
-	ERROR_STATEMENT_CODE                 = "error-statement/code"
-	SYNTHETIC_STATEMENT_SCOPE            = "synthetic-statement/scope"
-	SYNTHETIC_BASE64_STATEMENT_SCOPE     = "synthetic-base64-statement/scope"
-	GOTO_DUPLICATED                      = "goto/duplicated"
-	GOTO_SYNTAX                          = "goto/syntax"
-	CONDITION_LITERAL                    = "condition/literal"
-	VALID_IP                             = "valid-ip"
-	FUNCTION_ARGUMENTS                   = "function/arguments"
-	FUNCTION_ARGUMENT_TYPE               = "function/argument-type"
-	INCLUDE_STATEMENT_MODULE_NOT_FOUND   = "include/module-not-found"
-	INCLUDE_STATEMENT_MODULE_LOAD_FAILED = "include/module-load-failed"
-	REGEX_MATCHED_VALUE_MAY_OVERRIDE     = "regex/matched-value-override"
-	UNUSED_DECLARATION                   = "unused/declaration"
-	UNUSED_VARIABLE                      = "unused/variable"
-	UNUSED_GOTO                          = "unused/goto"
-	DISALLOW_EMPTY_RETURN                = "disallow-empty-return"
+	ACL_SYNTAX                             = "acl/syntax"
+	ACL_DUPLICATED                         = "acl/duplicated"
+	ACL_NOTFOUND                           = "acl/notfound"
+	ACL_OVERLAPPED_CIDR                    = "acl/overlapped-cidr"
+	ACL_ENTRY_LIMITATION                   = "acl/entry-limitation"
+	BACKEND_SYNTAX                         = "backend/syntax"
+	BACKEND_DUPLICATED                     = "backend/duplicated"
+	BACKEND_NOTFOUND                       = "backend/notfound"
+	BACKEND_PROBER_CONFIGURATION           = "backend/prober-configuration"
+	DIRECTOR_SYNTAX                        = "director/syntax"
+	DIRECTOR_DUPLICATED                    = "director/duplicated"
+	DIRECTOR_PROPS_RANDOM                  = "director/props-random"
+	DIRECTOR_PROPS_FALLBACK                = "director/props-fallback"
+	DIRECTOR_PROPS_HASH                    = "director/props-hash"
+	DIRECTOR_PROPS_CLIENT                  = "director/props-client"
+	DIRECTOR_PROPS_CHASH                   = "director/props-chash"
+	DIRECTOR_BACKEND_REQUIRED              = "director/backend-required"
+	TABLE_SYNTAX                           = "table/syntax"
+	TABLE_TYPE_VARIATION                   = "table/type-variation"
+	TABLE_ITEM_LIMITATION                  = "table/item-limitation"
+	TABLE_KEY_LENGTH                       = "table/key-length"
+	TABLE_VALUE_LENGTH                     = "table/value-length"
+	TABLE_DUPLICATED                       = "table/duplicated"
+	SUBROUTINE_SYNTAX                      = "subroutine/syntax"
+	SUBROUTINE_BOILERPLATE_MACRO           = "subroutine/boilerplate-macro"
+	SUBROUTINE_DUPLICATED                  = "subroutine/duplicated"
+	SUBROUTINE_INVALID_RETURN_TYPE         = "subroutine/invalid-return-type"
+	SUBROUTINE_EXIT_BEFORE_MACRO           = "subroutine/exit-before-macro"
+	SUBROUTINE_MISSING_RETURN              = "subroutine/missing-return"
+	PENALTYBOX_SYNTAX                      = "penaltybox/syntax"
+	PENALTYBOX_DUPLICATED                  = "penaltybox/duplicated"
+	PENALTYBOX_NONEMPTY_BLOCK              = "penaltybox/nonempty-block"
+	RATECOUNTER_SYNTAX                     = "ratecounter/syntax"
+	RATECOUNTER_DUPLICATED                 = "ratecounter/duplicated"
+	RATECOUNTER_NONEMPTY_BLOCK             = "ratecounter/nonempty-block"
+	DECLARE_STATEMENT_SYNTAX               = "declare-statement/syntax"
+	DECLARE_STATEMENT_INVALID_TYPE         = "declare-statement/invalid-type"
+	DECLARE_STATEMENT_DUPLICATED           = "declare-statement/duplicated"
+	SET_STATEMENT_SYNTAX                   = "set-statement/syntax"
+	OPERATOR_ASSIGNMENT                    = "operator/assignment"
+	VARIABLE_ACCESS                        = "variable/access"
+	VARIABLE_SUBFIELD                      = "variable/subfield"
+	UNSET_STATEMENT_SYNTAX                 = "unset-statement/syntax"
+	REMOVE_STATEMENT_SYNTAX                = "remove-statement/syntax"
+	OPERATOR_CONDITIONAL                   = "operator/conditional"
+	IMPLICIT_TYPE_CONVERSION               = "implicit-type-conversion"
+	HEADER_STRINGIFICATION                 = "header/stringification"
+	RESTART_STATEMENT_SCOPE                = "restart-statement/scope"
+	RESTART_STATEMENT_LOOP                 = "restart-statement/loop"
+	ADD_STATEMENT_SYNTAX                   = "add-statement/syntax"
+	CALL_STATEMENT_SYNTAX                  = "call-statement/syntax"
+	CALL_STATEMENT_SUBROUTINE_NOTFOUND     = "call-statement/subroutine-notfound"
+	ERROR_STATEMENT_SCOPE                  = "error-statement/scope"
+	ERROR_STATEMENT_CODE                   = "error-statement/code"
+	ERROR_STATEMENT_SYNTAX                 = "error-statement/syntax"
+	LOG_STATEMENT_SYNTAX                   = "log-statement/syntax"
+	RETURN_STATEMENT_SYNTAX                = "return-statement/syntax"
+	RETURN_STATEMENT_STATE                 = "return-statement/state"
+	SYNTHETIC_STATEMENT_SCOPE              = "synthetic-statement/scope"
+	SYNTHETIC_BASE64_STATEMENT_SCOPE       = "synthetic-base64-statement/scope"
+	GOTO_DUPLICATED                        = "goto/duplicated"
+	GOTO_SYNTAX                            = "goto/syntax"
+	GOTO_NOTFOUND                          = "goto/notfound"
+	SWITCH_STATEMENT_SYNTAX                = "switch-statement/syntax"
+	SWITCH_STATEMENT_DUPLICATE_CASE        = "switch-statement/duplicate-case"
+	SWITCH_STATEMENT_FALLTHROUGH           = "switch-statement/fallthrough"
+	CONDITION_LITERAL                      = "condition/literal"
+	CONDITION_TYPE                         = "condition/type"
+	CONDITION_CONSTANT                     = "condition/constant"
+	CONDITION_DUPLICATED                   = "condition/duplicated"
+	IF_EXPRESSION_SYNTAX                   = "if-expression/syntax"
+	IF_EXPRESSION_TYPE                     = "if-expression/type"
+	VALID_IP                               = "valid-ip"
+	VALID_REGEX                            = "valid-regex"
+	VALID_TIME_FORMAT                      = "valid-time-format"
+	TIME_ARITHMETIC                        = "time/arithmetic"
+	FUNCTION_NOTFOUND                      = "function/notfound"
+	FUNCTION_ARGUMENTS                     = "function/arguments"
+	FUNCTION_ARGUMENT_TYPE                 = "function/argument-type"
+	FUNCTION_UNUSED_RETURN                 = "function/unused-return"
+	DEPRECATED_VARIABLE                    = "deprecated/variable"
+	DEPRECATED_FUNCTION                    = "deprecated/function"
+	PROTECTED_HEADER                       = "protected-header"
+	INCLUDE_STATEMENT_MODULE_NOT_FOUND     = "include/module-not-found"
+	INCLUDE_STATEMENT_MODULE_LOAD_FAILED   = "include/module-load-failed"
+	INCLUDE_STATEMENT_CYCLE                = "include/cycle"
+	REGEX_MATCHED_VALUE_MAY_OVERRIDE       = "regex/matched-value-override"
+	REGEX_GROUP_OUT_OF_RANGE               = "regex/group-out-of-range"
+	UNUSED_DECLARATION                     = "unused/declaration"
+	UNUSED_VARIABLE                        = "unused/variable"
+	UNUSED_GOTO                            = "unused/goto"
+	UNREACHABLE_CODE                       = "unreachable-code"
+	COMPLEXITY_CYCLOMATIC                  = "complexity/cyclomatic"
+	COMPLEXITY_NESTING                     = "complexity/nesting"
+	COMPILATION_VCL_SIZE                   = "compilation/vcl-size"
+	COMPILATION_SUBROUTINES                = "compilation/subroutines"
+	COMPILATION_WORKSPACE                  = "compilation/workspace"
+	DISALLOW_EMPTY_RETURN                  = "disallow-empty-return"
+	CACHE_TTL_BEFORE_PASS                  = "cache/ttl-before-pass"
+	CACHE_STALE_UNCACHEABLE                = "cache/stale-uncacheable"
+	CACHE_HIT_FOR_PASS_TTL                 = "cache/hit-for-pass-ttl"
+	CACHE_HASH_SCOPE                       = "cache/hash-scope"
+	CACHE_HASH_MISSING_KEY                 = "cache/hash-missing-key"
+	CACHE_POISONING                        = "cache/poisoning"
+	CACHE_SET_COOKIE                       = "cache/set-cookie"
+	DEBUG_HEADER_LEAK                      = "debug-header/leak"
+	COMPLIANCE_LOG_SENSITIVE_HEADER        = "compliance/log-sensitive-header"
+	COMPLIANCE_AUTHENTICATED_CACHE_CONTROL = "compliance/authenticated-cache-control"
+	COMPLIANCE_BACKEND_TLS_VERSION         = "compliance/backend-tls-version"
+	NAMING_CONVENTION                      = "naming/convention"
+	NAMING_SHADOWING                       = "naming/shadowing"
+	POLICY_VIOLATION                       = "policy/violation"
+	REGO_POLICY_VIOLATION                  = "policy/rego"
+	IMAGE_OPTIMIZER_API_HEADER             = "image-optimizer/api-header"
+	IMAGE_OPTIMIZER_QUERY                  = "image-optimizer/query"
+	COMPUTE_HOST_ASSUMPTION                = "compute/host-assumption"
+	DOMAIN_NOT_ATTACHED                    = "domain/not-attached"
+	SOURCE_ENCODING                        = "source/encoding"
+	SOURCE_INVALID_UTF8                    = "source/invalid-utf8"
+	ANNOTATION_UNKNOWN                     = "annotation/unknown"
+	ANNOTATION_DUPLICATED                  = "annotation/duplicated"
+	ANNOTATION_INVALID                     = "annotation/invalid"
 )
 
 var references = map[Rule]string{
@@ -81,8 +146,12 @@ var references = map[Rule]string{
 	TABLE_SYNTAX:                     "https://developer.fastly.com/reference/vcl/declarations/table/",
 	TABLE_TYPE_VARIATION:             "https://developer.fastly.com/reference/vcl/declarations/table/#type-variations",
 	TABLE_ITEM_LIMITATION:            "https://developer.fastly.com/reference/vcl/declarations/table/#limitations",
+	TABLE_KEY_LENGTH:                 "https://developer.fastly.com/reference/vcl/declarations/table/#limitations",
+	TABLE_VALUE_LENGTH:               "https://developer.fastly.com/reference/vcl/declarations/table/#limitations",
 	SUBROUTINE_SYNTAX:                "https://developer.fastly.com/reference/vcl/subroutines/",
 	SUBROUTINE_BOILERPLATE_MACRO:     "https://developer.fastly.com/learning/vcl/using/#adding-vcl-to-your-service-configuration",
+	SUBROUTINE_EXIT_BEFORE_MACRO:     "https://developer.fastly.com/learning/vcl/using/#adding-vcl-to-your-service-configuration",
+	SUBROUTINE_MISSING_RETURN:        "https://developer.fastly.com/reference/vcl/subroutines/",
 	PENALTYBOX_SYNTAX:                "https://developer.fastly.com/reference/vcl/declarations/penaltybox/",
 	PENALTYBOX_NONEMPTY_BLOCK:        "https://developer.fastly.com/reference/vcl/declarations/penaltybox/",
 	RATECOUNTER_SYNTAX:               "https://developer.fastly.com/reference/vcl/declarations/ratecounter/",
@@ -95,11 +164,20 @@ var references = map[Rule]string{
 	REMOVE_STATEMENT_SYNTAX:          "https://developer.fastly.com/reference/vcl/statements/remove/",
 	OPERATOR_CONDITIONAL:             "https://developer.fastly.com/reference/vcl/operators/#conditional-operators",
 	RESTART_STATEMENT_SCOPE:          "https://developer.fastly.com/reference/vcl/statements/restart/",
+	RESTART_STATEMENT_LOOP:           "https://developer.fastly.com/reference/vcl/statements/restart/",
 	ADD_STATEMENT_SYNTAX:             "https://developer.fastly.com/reference/vcl/statements/add/",
 	CALL_STATEMENT_SYNTAX:            "https://developer.fastly.com/reference/vcl/statements/call/",
 	ERROR_STATEMENT_SCOPE:            "https://developer.fastly.com/reference/vcl/statements/error/",
 	ERROR_STATEMENT_CODE:             "https://developer.fastly.com/reference/vcl/statements/error/#best-practices-for-using-status-codes-for-errors",
+	ERROR_STATEMENT_SYNTAX:           "https://developer.fastly.com/reference/vcl/statements/error/",
+	LOG_STATEMENT_SYNTAX:             "https://developer.fastly.com/reference/vcl/statements/log/",
+	RETURN_STATEMENT_SYNTAX:          "https://developer.fastly.com/reference/vcl/statements/return/",
+	RETURN_STATEMENT_STATE:           "https://developer.fastly.com/learning/vcl/using/#the-vcl-request-lifecycle",
 	SYNTHETIC_STATEMENT_SCOPE:        "https://developer.fastly.com/reference/vcl/statements/synthetic/",
 	SYNTHETIC_BASE64_STATEMENT_SCOPE: "https://developer.fastly.com/reference/vcl/statements/synthetic-base64/",
 	DISALLOW_EMPTY_RETURN:            "https://developer.fastly.com/reference/vcl/subroutines#returning-a-state",
+	IMAGE_OPTIMIZER_API_HEADER:       "https://developer.fastly.com/reference/io/#enabling-image-optimization",
+	IMAGE_OPTIMIZER_QUERY:            "https://developer.fastly.com/reference/io/",
+	CACHE_HASH_SCOPE:                 "https://developer.fastly.com/reference/vcl/variables/cache-object/req-hash/",
+	CACHE_HASH_MISSING_KEY:           "https://developer.fastly.com/reference/vcl/subroutines/hash/",
 }
@@ -0,0 +1,177 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Lint the response which is cached by positive beresp.ttl does not keep Set-Cookie header.
+// The cookie is served to all clients which hit the cached object, so it is a classic privacy issue of the shared cache.
+// Set-Cookie header is considered to be handled when the subroutine or called subroutines strip the header,
+// or pass the response in the branch which checks beresp.http.Set-Cookie.
+//
+// When compliance rule pack is enabled, vcl_fetch must handle Set-Cookie header even if TTL is not set
+// because the response could be cached by Cache-Control header of the origin.
+func (l *Linter) lintSetCookieCache(decl *ast.SubroutineDeclaration, scope int, ctx *context.Context) {
+	if scope&context.FETCH == 0 {
+		return
+	}
+	if handlesSetCookie(decl.Block, ctx, map[string]struct{}{decl.Name.Value: {}}) {
+		return
+	}
+	if l.lintSetCookieCacheBlock(decl.Block, false) {
+		return
+	}
+	if !l.compliance.IsEnabled() || decl.Name.Value != "vcl_fetch" {
+		return
+	}
+
+	// Unconditional pass never caches any responses
+	for _, stmt := range decl.Block.Statements {
+		if isReturnState(stmt, "pass") {
+			return
+		}
+	}
+	err := &LintError{
+		Severity: WARNING,
+		Token:    decl.Name.GetMeta().Token,
+		Message:  "Responses which have Set-Cookie header may be cached, pass or remove Set-Cookie header in vcl_fetch",
+	}
+	l.Error(err.Match(CACHE_SET_COOKIE))
+}
+
+// Report positive beresp.ttl in the block which could cache the response, returns true when any TTL is reported.
+// uncacheable is true when the enclosing block passes or disables cache of the response.
+func (l *Linter) lintSetCookieCacheBlock(block *ast.BlockStatement, uncacheable bool) bool {
+	if block == nil {
+		return false
+	}
+
+	var ttls []*ast.SetStatement
+	for _, stmt := range block.Statements {
+		switch t := stmt.(type) {
+		case *ast.ReturnStatement:
+			if isReturnState(t, "pass") {
+				uncacheable = true
+			}
+		case *ast.SetStatement:
+			switch strings.ToLower(t.Ident.Value) {
+			case "beresp.cacheable":
+				if v, ok := t.Value.(*ast.Boolean); ok && !v.Value {
+					uncacheable = true
+				}
+			case "beresp.ttl":
+				if isPositiveTTL(t) {
+					ttls = append(ttls, t)
+				}
+			}
+		}
+	}
+
+	var reported bool
+	if !uncacheable {
+		for _, ttl := range ttls {
+			reported = true
+			err := &LintError{
+				Severity: WARNING,
+				Token:    ttl.Ident.GetMeta().Token,
+				Message: fmt.Sprintf(
+					"Response is cached for %s with Set-Cookie header, the cookie could be served to other clients. "+
+						"Pass the response or remove Set-Cookie header in vcl_fetch",
+					ttl.Value.String(),
+				),
+			}
+			l.Error(err.Match(CACHE_SET_COOKIE))
+		}
+	}
+
+	for _, stmt := range block.Statements {
+		switch t := stmt.(type) {
+		case *ast.BlockStatement:
+			reported = l.lintSetCookieCacheBlock(t, uncacheable) || reported
+		case *ast.IfStatement:
+			// Branches which check Set-Cookie header are written with care of the cookie
+			branches := append([]*ast.IfStatement{t}, t.Another...)
+			var checked bool
+			for _, b := range branches {
+				checked = checked || hasIdent(b.Condition, "beresp.http.set-cookie")
+			}
+			if checked {
+				continue
+			}
+			for _, b := range branches {
+				reported = l.lintSetCookieCacheBlock(b.Consequence, uncacheable) || reported
+			}
+			reported = l.lintSetCookieCacheBlock(t.Alternative, uncacheable) || reported
+		case *ast.SwitchStatement:
+			if hasIdent(t.Control, "beresp.http.set-cookie") {
+				continue
+			}
+			for _, c := range t.Cases {
+				reported = l.lintSetCookieCacheBlock(caseBlock(c), uncacheable) || reported
+			}
+		}
+	}
+	return reported
+}
+
+// Find the statement which strips Set-Cookie header, or the branch which checks Set-Cookie header
+// and then prevents caching. Called subroutines are also walked because the stripping is often shared.
+func handlesSetCookie(block *ast.BlockStatement, ctx *context.Context, visited map[string]struct{}) bool {
+	var handled bool
+	walkStatements(block, func(stmt ast.Statement) bool {
+		switch t := stmt.(type) {
+		case *ast.UnsetStatement:
+			handled = strings.EqualFold(t.Ident.Value, "beresp.http.set-cookie")
+		case *ast.RemoveStatement:
+			handled = strings.EqualFold(t.Ident.Value, "beresp.http.set-cookie")
+		case *ast.FunctionCallStatement:
+			handled = isSetCookieUnset(t)
+		case *ast.IfStatement:
+			branches := append([]*ast.IfStatement{t}, t.Another...)
+			for _, b := range branches {
+				if hasIdent(b.Condition, "beresp.http.set-cookie") && preventsSetCookieCache(b.Consequence) {
+					handled = true
+				}
+			}
+		case *ast.CallStatement:
+			name := t.Subroutine.Value
+			if _, ok := visited[name]; ok {
+				break
+			}
+			visited[name] = struct{}{}
+			if s, ok := ctx.Subroutines[name]; ok && s.Decl != nil {
+				handled = handlesSetCookie(s.Decl.Block, ctx, visited)
+			}
+		}
+		return !handled
+	})
+	return handled
+}
+
+// header.unset(beresp, "Set-Cookie") strips the header as well as unset statement
+func isSetCookieUnset(stmt *ast.FunctionCallStatement) bool {
+	if stmt.Function.Value != "header.unset" || len(stmt.Arguments) != 2 {
+		return false
+	}
+	if v, ok := stmt.Arguments[0].(*ast.Ident); !ok || v.Value != "beresp" {
+		return false
+	}
+	v, ok := stmt.Arguments[1].(*ast.String)
+	return ok && strings.EqualFold(v.Value, "set-cookie")
+}
+
+func isPositiveTTL(stmt *ast.SetStatement) bool {
+	if stmt.Operator.Operator != "=" {
+		return false
+	}
+	v, ok := stmt.Value.(*ast.RTime)
+	if !ok {
+		return false
+	}
+	d, ok := parseRTime(v.Value)
+	return ok && d > 0
+}
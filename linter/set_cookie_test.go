@@ -0,0 +1,133 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintSetCookieCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []int
+	}{
+		{
+			name: "positive TTL keeps Set-Cookie header",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.ttl = 1h;
+  if (beresp.status == 200) {
+    set beresp.ttl = 1d;
+  }
+  return(deliver);
+}`,
+			expect: []int{4, 6},
+		},
+		{
+			name: "zero TTL does not cache the response",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.ttl = 0s;
+  return(deliver);
+}`,
+		},
+		{
+			name: "Set-Cookie header is removed",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  unset beresp.http.Set-Cookie;
+  set beresp.ttl = 1h;
+  return(deliver);
+}`,
+		},
+		{
+			name: "Set-Cookie header is removed in called subroutine",
+			input: `
+sub strip_cookie {
+  header.unset(beresp, "Set-Cookie");
+}
+
+sub vcl_fetch {
+  #FASTLY FETCH
+  call strip_cookie;
+  set beresp.ttl = 1h;
+  return(deliver);
+}`,
+		},
+		{
+			name: "response with Set-Cookie header is passed",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.http.Set-Cookie) {
+    set req.http.Fastly-Cachetype = "SETCOOKIE";
+    return(pass);
+  }
+  set beresp.ttl = 1h;
+  return(deliver);
+}`,
+		},
+		{
+			name: "TTL is set in the branch without Set-Cookie header",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (!beresp.http.Set-Cookie) {
+    set beresp.ttl = 1h;
+  }
+  return(deliver);
+}`,
+		},
+		{
+			name: "TTL is used for hit-for-pass",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  if (beresp.status == 500) {
+    set beresp.ttl = 120s;
+    return(pass);
+  }
+  set beresp.cacheable = false;
+  set beresp.ttl = 1h;
+  return(deliver);
+}`,
+		},
+		{
+			name: "subroutine is not called in fetch scope",
+			input: `
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set resp.http.Foo = "bar";
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+
+			var lines []int
+			for _, d := range l.Diagnostics {
+				if d.Rule == CACHE_SET_COOKIE {
+					lines = append(lines, d.Token.Line)
+				}
+			}
+			if diff := cmp.Diff(tt.expect, lines); diff != "" {
+				t.Errorf("Reported lines unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
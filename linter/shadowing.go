@@ -0,0 +1,42 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Lint user defined names which collide with predefined variables or builtin functions like "sub math" or "var.req".
+// These names are valid but hard to read, and the name could become ambiguous when Fastly adds variables or functions.
+func (l *Linter) lintShadowing(ident *ast.Ident, kind string) {
+	name := ident.Value
+	switch kind {
+	case "subroutine":
+		// Fastly reserved subroutine names are intended
+		if context.IsFastlySubroutine(name) {
+			return
+		}
+	case "variable":
+		// Local variable is accessed with "var." prefix, so the first segment of the name is confusing
+		name = strings.TrimPrefix(name, "var.")
+		if idx := strings.Index(name, "."); idx != -1 {
+			name = name[:idx]
+		}
+	}
+
+	predefined, ok := context.LookupPredefinedName(name)
+	if !ok {
+		return
+	}
+	err := &LintError{
+		Severity: WARNING,
+		Token:    ident.GetMeta().Token,
+		Message: fmt.Sprintf(
+			`Name "%s" of %s collides with %s "%s", consider renaming to avoid confusion`,
+			ident.Value, kind, predefined.Kind, predefined.Name,
+		),
+	}
+	l.Error(err.Match(NAMING_SHADOWING))
+}
@@ -0,0 +1,96 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintShadowing(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{
+			name: "names which do not collide",
+			input: `
+sub calculate_score {
+  declare local var.request_url STRING;
+  set var.request_url = req.url;
+}
+sub vcl_recv {
+  #FASTLY RECV
+  call calculate_score;
+}`,
+		},
+		{
+			name: "subroutine collides with builtin function namespace",
+			input: `
+sub math {
+  set req.http.X-Math = "1";
+}
+sub vcl_recv {
+  #FASTLY RECV
+  call math;
+}`,
+			expect: []string{`Name "math" of subroutine collides with builtin function namespace "math", consider renaming to avoid confusion`},
+		},
+		{
+			name: "subroutine collides with builtin function in different case",
+			input: `
+sub Substr {
+  set req.http.X-Sub = "1";
+}
+sub vcl_recv {
+  #FASTLY RECV
+  call Substr;
+}`,
+			expect: []string{`Name "Substr" of subroutine collides with builtin function "substr", consider renaming to avoid confusion`},
+		},
+		{
+			name: "local variables collide with predefined variables",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.req STRING;
+  declare local var.client.name STRING;
+  set var.req = req.url;
+  set var.client.name = var.req;
+  set req.http.X-Client = var.client.name;
+}`,
+			expect: []string{
+				`Name "var.req" of variable collides with predefined variable "req", consider renaming to avoid confusion`,
+				`Name "var.client.name" of variable collides with predefined variable "client", consider renaming to avoid confusion`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+		if err != nil {
+			t.Errorf("%s: unexpected parser error: %s", tt.name, err)
+			continue
+		}
+		l := New()
+		l.Lint(vcl, context.New())
+
+		var actual []string
+		for _, d := range l.Diagnostics {
+			if d.Rule == NAMING_SHADOWING {
+				actual = append(actual, d.Message)
+			}
+		}
+		if len(actual) != len(tt.expect) {
+			t.Errorf("%s: shadowing errors mismatch, expect=%v, got=%v", tt.name, tt.expect, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != tt.expect[i] {
+				t.Errorf("%s: message mismatch, expect=%s, got=%s", tt.name, tt.expect[i], actual[i])
+			}
+		}
+	}
+}
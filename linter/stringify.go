@@ -0,0 +1,40 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/types"
+)
+
+// Stringification formats which Fastly performs when non-STRING value is assigned to STRING.
+// Types which are stringified obviously like INTEGER or IP are not listed.
+var stringificationFormats = map[types.Type]string{
+	types.FloatType: `fixed three decimal places like "0.500"`,
+	types.RTimeType: `seconds with three decimal places like "10.000"`,
+	types.TimeType:  `HTTP date like "Thu, 01 Jan 1970 00:00:00 GMT"`,
+	types.BoolType:  `"1" or "0", not "true" or "false"`,
+}
+
+// Lint non-STRING value is assigned to HTTP header as it is.
+// The value is stringified by Fastly silently, and the result often surprises like comparing header with "0" for FLOAT.
+func (l *Linter) lintHeaderStringification(ident *ast.Ident, value ast.Expression, right types.Type) {
+	if !strings.Contains(strings.ToLower(ident.Value), ".http.") {
+		return
+	}
+	format, ok := stringificationFormats[right]
+	if !ok {
+		return
+	}
+
+	err := &LintError{
+		Severity: INFO,
+		Token:    value.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"%s value is stringified as %s when assigned to %s",
+			right.String(), format, ident.Value,
+		),
+	}
+	l.Error(err.Match(HEADER_STRINGIFICATION))
+}
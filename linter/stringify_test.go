@@ -0,0 +1,83 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintHeaderStringification(t *testing.T) {
+	t.Run("FLOAT is assigned to header", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.ratio FLOAT;
+  set var.ratio = 0.5;
+  set req.http.Ratio = var.ratio;
+}`
+		assertErrorWithSeverity(t, input, INFO)
+	})
+
+	t.Run("TIME is added to header", func(t *testing.T) {
+		input := `
+sub vcl_deliver {
+  #FASTLY DELIVER
+  add resp.http.X-Now = now;
+}`
+		assertErrorWithSeverity(t, input, INFO)
+	})
+
+	t.Run("BOOL is assigned to header", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Is-Tls = req.is_ssl;
+}`
+		assertErrorWithSeverity(t, input, INFO)
+	})
+
+	t.Run("INTEGER and STRING are not reported", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Restarts = req.restarts;
+  set req.http.Now = strftime({"%s"}, now);
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("FLOAT is assigned to local variable", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.ratio FLOAT;
+  declare local var.s STRING;
+  set var.ratio = 0.5;
+  set var.s = var.ratio;
+  set req.http.S = var.s;
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("severity could be upgraded", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Is-Tls = req.is_ssl;
+}`
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Fatalf("unexpected parser error: %s", err)
+		}
+		l := New(WithSeverities(map[Rule]Severity{HEADER_STRINGIFICATION: WARNING}))
+		l.lint(vcl, context.New())
+		if len(l.Errors) != 1 {
+			t.Fatalf("Expect one lint error but got %d", len(l.Errors))
+		}
+		if d := l.Errors[0].(*LintError); d.Severity != WARNING {
+			t.Errorf("Severity expects %s but got %s", WARNING, d.Severity)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Headers which hold a single value, Fastly tokenizes them by comma but the subfield is never found
+var singleValueHeaders = map[string]struct{}{
+	"host":                {},
+	"user-agent":          {},
+	"content-length":      {},
+	"content-location":    {},
+	"date":                {},
+	"expires":             {},
+	"last-modified":       {},
+	"if-modified-since":   {},
+	"if-unmodified-since": {},
+	"location":            {},
+	"referer":             {},
+	"age":                 {},
+	"etag":                {},
+	"retry-after":         {},
+	"server":              {},
+}
+
+// Lint subfield accessor like req.http.Cache-Control:max-age.
+// Fastly tokenizes the header value by comma and finds the key of "key=value" item,
+// but the accessor on other variables silently does nothing.
+// Returns the variable name which should be looked up in the context,
+// the base variable is returned for the invalid accessor to avoid cascading undefined variable errors.
+func (l *Linter) lintSubfield(ident *ast.Ident) string {
+	base, field, found := strings.Cut(ident.Value, ":")
+	// Goto label also has colon suffix but it is not a variable
+	if !found || !strings.Contains(base, ".") {
+		return ident.Value
+	}
+
+	header := httpHeaderName(base)
+	if header == "" {
+		err := &LintError{
+			Severity: ERROR,
+			Token:    ident.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"Subfield accessor could be used only for HTTP header variables, %s is not a header variable",
+				base,
+			),
+		}
+		l.Error(err.Match(VARIABLE_SUBFIELD))
+		return base
+	}
+
+	if field == "" {
+		err := &LintError{
+			Severity: ERROR,
+			Token:    ident.GetMeta().Token,
+			Message:  fmt.Sprintf("Subfield name of %s is empty", ident.Value),
+		}
+		l.Error(err.Match(VARIABLE_SUBFIELD))
+		return ident.Value
+	}
+
+	if _, ok := singleValueHeaders[strings.ToLower(header)]; ok {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    ident.GetMeta().Token,
+			Message: fmt.Sprintf(
+				"%s header holds a single value, subfield %s would never be found",
+				header, field,
+			),
+		}
+		l.Error(err.Match(VARIABLE_SUBFIELD))
+	}
+	return ident.Value
+}
@@ -0,0 +1,84 @@
+package linter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintSubfield(t *testing.T) {
+	t.Run("valid subfield accessors", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = req.http.Cache-Control:max-age;
+  set req.http.Edge-Control:cache-maxage = "1h";
+  unset req.http.Cookie:session;
+  goto done;
+  done:
+}`
+		assertNoError(t, input)
+	})
+
+	tests := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{
+			name: "subfield on non-header variables",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.S STRING;
+  set var.S = "foo";
+  set req.http.Foo = req.url:foo;
+  set req.http.Bar = var.S:foo;
+}`,
+			expect: []string{"6:variable/subfield", "7:variable/subfield"},
+		},
+		{
+			name: "empty subfield name",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  unset req.http.Foo:;
+}`,
+			expect: []string{"4:variable/subfield"},
+		},
+		{
+			name: "subfield on single value header",
+			input: `
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set resp.http.Foo = req.http.Host:port;
+}`,
+			expect: []string{"4:variable/subfield"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			assertRuleMatched(t, l)
+
+			var actual []string
+			for _, d := range l.Diagnostics {
+				actual = append(actual, fmt.Sprintf("%d:%s", d.Token.Line, d.Rule))
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Diagnostics unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/types"
+)
+
+func (l *Linter) lintSwitchStatement(stmt *ast.SwitchStatement, ctx *context.Context) types.Type {
+	control := l.lintSwitchControl(stmt.Control, ctx)
+	l.lintDuplicateCases(stmt)
+	l.lintCaseTerminations(stmt)
+
+	// Track capture group count of regex matching for each case and merge them after the statement like if statement
+	var groups []int
+	for i, c := range stmt.Cases {
+		condition := l.regexGroups
+		consequence := condition
+		var cond ast.Expression
+		if !c.IsDefault() {
+			l.lintCaseValue(c, control, ctx)
+			cond = caseCondition(stmt, c)
+			if err := pushRegexGroupVars(cond, ctx); err != nil {
+				err := &LintError{
+					Severity: INFO,
+					Token:    c.Value.GetMeta().Token,
+					Message:  err.Error(),
+				}
+				l.Error(err.Match(REGEX_MATCHED_VALUE_MAY_OVERRIDE))
+			}
+			consequence = l.enterRegexCondition(cond)
+			condition = l.regexGroups
+		}
+		// Statements could be reached by fallthrough from the previous case without matching this case
+		if i > 0 && stmt.Cases[i-1].IsFallthrough() {
+			consequence = mergeRegexGroups(condition, groups[len(groups)-1])
+		}
+
+		l.regexGroups = consequence
+		if cond != nil {
+			l.conditions = append(l.conditions, cond)
+		}
+		l.lintBlockStatement(caseBlock(c), ctx)
+		if cond != nil {
+			l.conditions = l.conditions[:len(l.conditions)-1]
+		}
+		groups = append(groups, l.regexGroups)
+		l.regexGroups = condition
+	}
+	for i := range groups {
+		l.regexGroups = mergeRegexGroups(l.regexGroups, groups[i])
+	}
+
+	return types.NeverType
+}
+
+// Control expression is compared with case values, so it must not be a literal and only STRING or INTEGER is accepted
+func (l *Linter) lintSwitchControl(exp ast.Expression, ctx *context.Context) types.Type {
+	if isLiteralExpression(exp) {
+		err := &LintError{
+			Severity: ERROR,
+			Token:    exp.GetMeta().Token,
+			Message:  "Control expression of switch statement could not be a literal",
+		}
+		l.Error(err.Match(SWITCH_STATEMENT_SYNTAX))
+	}
+
+	control := l.lint(exp, ctx)
+	if control != types.NeverType && !expectType(control, types.StringType, types.IntegerType) {
+		err := &LintError{
+			Severity: ERROR,
+			Token:    exp.GetMeta().Token,
+			Message:  fmt.Sprintf("Control expression type %s may not be used in switch statement, it must be STRING or INTEGER", control.String()),
+		}
+		l.Error(err.Match(SWITCH_STATEMENT_SYNTAX))
+		return types.NeverType
+	}
+	return control
+}
+
+func (l *Linter) lintCaseValue(c *ast.CaseStatement, control types.Type, ctx *context.Context) {
+	value := l.lint(c.Value, ctx)
+	if control == types.NeverType {
+		return
+	}
+	if c.Operator == "~" {
+		if control != types.StringType {
+			err := &LintError{
+				Severity: ERROR,
+				Token:    c.Value.GetMeta().Token,
+				Message:  fmt.Sprintf("Regex case could be used only for STRING control expression, got %s", control.String()),
+			}
+			l.Error(err.Match(SWITCH_STATEMENT_SYNTAX))
+			return
+		}
+		if v, ok := c.Value.(*ast.String); ok {
+			l.lintRegexPattern(v)
+		}
+		return
+	}
+	if value != control {
+		err := &LintError{
+			Severity: ERROR,
+			Token:    c.Value.GetMeta().Token,
+			Message:  fmt.Sprintf("Case value type %s does not match the control expression type %s", value.String(), control.String()),
+		}
+		l.Error(err.Match(SWITCH_STATEMENT_SYNTAX))
+	}
+}
+
+// Case which has the same value as the previous case is never matched
+func (l *Linter) lintDuplicateCases(stmt *ast.SwitchStatement) {
+	seen := map[string]*ast.CaseStatement{}
+	for _, c := range stmt.Cases {
+		if c.IsDefault() {
+			continue
+		}
+		key := c.Operator + c.Value.String()
+		first, found := seen[key]
+		if !found {
+			seen[key] = c
+			continue
+		}
+		err := &LintError{
+			Severity: WARNING,
+			Token:    c.Value.GetMeta().Token,
+			Message:  fmt.Sprintf("Case %s is the same as the previous case, this case is never matched", c.Value.String()),
+		}
+		err.Relate(first.Value.GetMeta().Token, "Previous case is here")
+		l.Error(err.Match(SWITCH_STATEMENT_DUPLICATE_CASE))
+	}
+}
+
+// Each case must end with break, fallthrough or the statement which exits from the subroutine explicitly,
+// and break and fallthrough statement must be the last statement of the case.
+func (l *Linter) lintCaseTerminations(stmt *ast.SwitchStatement) {
+	for i, c := range stmt.Cases {
+		for j, s := range c.Statements {
+			var keyword string
+			switch s.(type) {
+			case *ast.BreakStatement:
+				keyword = "break"
+			case *ast.FallthroughStatement:
+				keyword = "fallthrough"
+			default:
+				continue
+			}
+			if j == len(c.Statements)-1 {
+				continue
+			}
+			err := &LintError{
+				Severity: ERROR,
+				Token:    s.GetMeta().Token,
+				Message:  fmt.Sprintf("%s statement must be the last statement of the case", keyword),
+			}
+			l.Error(err.Match(SWITCH_STATEMENT_SYNTAX))
+		}
+
+		if c.IsFallthrough() {
+			if i == len(stmt.Cases)-1 {
+				err := &LintError{
+					Severity: ERROR,
+					Token:    c.Statements[len(c.Statements)-1].GetMeta().Token,
+					Message:  "fallthrough statement could not be used in the last case of switch statement",
+				}
+				l.Error(err.Match(SWITCH_STATEMENT_FALLTHROUGH))
+			}
+			continue
+		}
+		if !isTerminatedCase(c) {
+			err := &LintError{
+				Severity: ERROR,
+				Token:    c.GetMeta().Token,
+				Message:  "Case must end with break or fallthrough statement, implicit fallthrough is not allowed",
+			}
+			l.Error(err.Match(SWITCH_STATEMENT_FALLTHROUGH))
+		}
+	}
+}
+
+// Case is evaluated as the condition which compares the control expression with the case value
+func caseCondition(stmt *ast.SwitchStatement, c *ast.CaseStatement) *ast.InfixExpression {
+	operator := "=="
+	if c.Operator == "~" {
+		operator = "~"
+	}
+	return &ast.InfixExpression{
+		Meta:     c.Value.GetMeta(),
+		Left:     stmt.Control,
+		Operator: operator,
+		Right:    c.Value,
+	}
+}
+
+// Case ends with break statement or the statement which exits from the subroutine
+func isTerminatedCase(c *ast.CaseStatement) bool {
+	if len(c.Statements) == 0 {
+		return false
+	}
+	switch c.Statements[len(c.Statements)-1].(type) {
+	case *ast.BreakStatement, *ast.ReturnStatement, *ast.ErrorStatement, *ast.RestartStatement, *ast.GotoStatement:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package linter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintSwitchStatement(t *testing.T) {
+	t.Run("valid switch statement", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.http.Host) {
+  case "example.com":
+    set req.http.Foo = "1";
+    fallthrough;
+  case ~ "^api\.(.+)$":
+    set req.http.Bar = re.group.1;
+    break;
+  default:
+    return(pass);
+  }
+}`
+		assertNoError(t, input)
+	})
+
+	tests := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{
+			name: "duplicated case",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.http.Host) {
+  case "example.com":
+    break;
+  case ~ "example.com":
+    break;
+  case "example.com":
+    break;
+  }
+}`,
+			expect: []string{"9:switch-statement/duplicate-case"},
+		},
+		{
+			name: "fallthrough in the last case",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.http.Host) {
+  case "example.com":
+    fallthrough;
+  default:
+    set req.http.Foo = "1";
+    fallthrough;
+  }
+}`,
+			expect: []string{"9:switch-statement/fallthrough"},
+		},
+		{
+			name: "implicit fallthrough",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.http.Host) {
+  case "example.com":
+  case "example.org":
+    set req.http.Foo = "1";
+  default:
+    return(lookup);
+  }
+}`,
+			expect: []string{"5:switch-statement/fallthrough", "6:switch-statement/fallthrough"},
+		},
+		{
+			name: "break is not the last statement",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.http.Host) {
+  case "example.com":
+    break;
+    set req.http.Foo = "1";
+    break;
+  }
+}`,
+			expect: []string{"6:switch-statement/syntax"},
+		},
+		{
+			name: "case value type mismatch",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.restarts) {
+  case "1":
+    break;
+  case ~ "^1":
+    break;
+  case 2:
+    break;
+  }
+}`,
+			expect: []string{"5:switch-statement/syntax", "7:switch-statement/syntax"},
+		},
+		{
+			name: "unsupported control type",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.is_ssl) {
+  case "1":
+    break;
+  }
+}`,
+			expect: []string{"4:switch-statement/syntax"},
+		},
+		{
+			name: "literal control expression",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  switch ("example.com") {
+  case "example.com":
+    break;
+  }
+}`,
+			expect: []string{"4:switch-statement/syntax"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			assertRuleMatched(t, l)
+
+			var actual []string
+			for _, d := range l.Diagnostics {
+				actual = append(actual, fmt.Sprintf("%d:%s", d.Token.Line, d.Rule))
+			}
+			if diff := cmp.Diff(tt.expect, actual); diff != "" {
+				t.Errorf("Diagnostics unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSwitchStatementMissingReturn(t *testing.T) {
+	input := `
+sub get_value STRING {
+  switch (req.http.Host) {
+  case "example.com":
+    fallthrough;
+  case "example.org":
+    return "org";
+  default:
+    return "default";
+  }
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.Foo = get_value();
+}`
+	assertNoError(t, input)
+}
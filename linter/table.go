@@ -0,0 +1,101 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/config"
+)
+
+// Default table limitations
+// https://developer.fastly.com/reference/vcl/declarations/table/#limitations
+const (
+	defaultTableMaxItems       = 1000
+	defaultTableMaxKeyLength   = 256
+	defaultTableMaxValueLength = 8000
+)
+
+// Edge dictionaries which are fetched from remote are parsed with this file name prefix
+const edgeDictionaryFilePrefix = "Remote.EdgeDictionary:"
+
+// Get the table limitation which is applied to the table declaration.
+// Edge dictionaries and inline tables have individual thresholds because limitations could be increased separately.
+func (l *Linter) tableLimit(decl *ast.TableDeclaration) (string, config.TableLimit) {
+	kind := "Table"
+	limit := config.TableLimit{
+		MaxItems:       defaultTableMaxItems,
+		MaxKeyLength:   defaultTableMaxKeyLength,
+		MaxValueLength: defaultTableMaxValueLength,
+	}
+
+	var c *config.TableLimit
+	if strings.HasPrefix(decl.GetMeta().Token.File, edgeDictionaryFilePrefix) {
+		kind = "Edge dictionary"
+		if l.tableLimits != nil {
+			c = l.tableLimits.EdgeDictionary
+		}
+	} else if l.tableLimits != nil {
+		c = l.tableLimits.Table
+	}
+	if c == nil {
+		return kind, limit
+	}
+	if c.MaxItems > 0 {
+		limit.MaxItems = c.MaxItems
+	}
+	if c.MaxKeyLength > 0 {
+		limit.MaxKeyLength = c.MaxKeyLength
+	}
+	if c.MaxValueLength > 0 {
+		limit.MaxValueLength = c.MaxValueLength
+	}
+	return kind, limit
+}
+
+func (l *Linter) lintTableLimits(decl *ast.TableDeclaration) {
+	kind, limit := l.tableLimit(decl)
+
+	if len(decl.Properties) > limit.MaxItems {
+		err := &LintError{
+			Severity: WARNING,
+			Token:    decl.Name.GetMeta().Token,
+			Message: fmt.Sprintf(
+				`%s "%s" has %d items which exceed the limit of %d`,
+				kind, decl.Name.Value, len(decl.Properties), limit.MaxItems,
+			),
+		}
+		l.Error(err.Match(TABLE_ITEM_LIMITATION))
+	}
+
+	for _, p := range decl.Properties {
+		if n := utf8.RuneCountInString(p.Key.Value); n > limit.MaxKeyLength {
+			err := &LintError{
+				Severity: WARNING,
+				Token:    p.Key.GetMeta().Token,
+				Message: fmt.Sprintf(
+					`Key of %s "%s" has %d characters which exceed the limit of %d`,
+					strings.ToLower(kind), decl.Name.Value, n, limit.MaxKeyLength,
+				),
+			}
+			l.Error(err.Match(TABLE_KEY_LENGTH))
+		}
+		// Only string literal value could be measured
+		v, ok := p.Value.(*ast.String)
+		if !ok {
+			continue
+		}
+		if n := utf8.RuneCountInString(v.Value); n > limit.MaxValueLength {
+			err := &LintError{
+				Severity: WARNING,
+				Token:    v.GetMeta().Token,
+				Message: fmt.Sprintf(
+					`Value of %s "%s" has %d characters which exceed the limit of %d`,
+					strings.ToLower(kind), decl.Name.Value, n, limit.MaxValueLength,
+				),
+			}
+			l.Error(err.Match(TABLE_VALUE_LENGTH))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintTableLimits(t *testing.T) {
+	input := `
+table example {
+  "foo": "short",
+  "long_key_name": "long value",
+  "bar": "baz",
+}`
+
+	tests := []struct {
+		name   string
+		file   string
+		config *config.TableLimitsConfig
+		expect []int
+		rules  []Rule
+	}{
+		{
+			name: "default thresholds",
+		},
+		{
+			name: "inline table thresholds",
+			config: &config.TableLimitsConfig{
+				Table: &config.TableLimit{MaxItems: 2, MaxKeyLength: 8, MaxValueLength: 6},
+			},
+			expect: []int{2, 4, 4},
+			rules:  []Rule{TABLE_ITEM_LIMITATION, TABLE_KEY_LENGTH, TABLE_VALUE_LENGTH},
+		},
+		{
+			name: "edge dictionary thresholds are not applied to inline table",
+			config: &config.TableLimitsConfig{
+				EdgeDictionary: &config.TableLimit{MaxItems: 2},
+			},
+		},
+		{
+			name: "edge dictionary thresholds",
+			file: "Remote.EdgeDictionary:example",
+			config: &config.TableLimitsConfig{
+				Table:          &config.TableLimit{MaxItems: 1},
+				EdgeDictionary: &config.TableLimit{MaxKeyLength: 8},
+			},
+			expect: []int{4},
+			rules:  []Rule{TABLE_KEY_LENGTH},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile(tt.file))).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New(WithTableLimits(tt.config))
+			l.Lint(vcl, context.New())
+
+			var lines []int
+			var rules []Rule
+			for _, d := range l.Diagnostics {
+				switch d.Rule {
+				case TABLE_ITEM_LIMITATION, TABLE_KEY_LENGTH, TABLE_VALUE_LENGTH:
+					lines = append(lines, d.Token.Line)
+					rules = append(rules, d.Rule)
+				}
+			}
+			if diff := cmp.Diff(tt.expect, lines); diff != "" {
+				t.Errorf("Table limit lines unmatch, diff: %s", diff)
+			}
+			if diff := cmp.Diff(tt.rules, rules); diff != "" {
+				t.Errorf("Table limit rules unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
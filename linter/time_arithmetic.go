@@ -0,0 +1,182 @@
+package linter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/types"
+)
+
+// Units of RTIME literal from the largest one, the number is formatted with the largest unit which divides it
+var rtimeUnits = []struct {
+	suffix  string
+	seconds int64
+}{
+	{suffix: "d", seconds: 24 * 60 * 60},
+	{suffix: "h", seconds: 60 * 60},
+	{suffix: "m", seconds: 60},
+	{suffix: "s", seconds: 1},
+}
+
+// Lint numbers which are used as time in set statement like "set beresp.ttl = 3600;".
+// Fastly interprets numbers as seconds for RTIME and as unix epoch seconds for TIME,
+// so suggest RTIME literal or std.integer2time function which tells the unit explicitly.
+// Returns true if the statement is reported so that generic type mismatch error is not reported.
+func (l *Linter) lintTimeArithmetic(stmt *ast.SetStatement, left types.Type) bool {
+	if left != types.RTimeType && left != types.TimeType {
+		return false
+	}
+
+	if call, ok := stmt.Value.(*ast.FunctionCallExpression); ok {
+		return l.lintIntegerToTime(stmt, call, left)
+	}
+
+	seconds, ok := numericSeconds(stmt.Value)
+	if !ok {
+		return false
+	}
+
+	var replacement string
+	switch {
+	case left == types.RTimeType && (stmt.Operator.Operator == "=" || stmt.Operator.Operator == "+=" || stmt.Operator.Operator == "-="):
+		replacement = formatRTime(seconds)
+	case left == types.TimeType && stmt.Operator.Operator == "=" && seconds == math.Trunc(seconds):
+		replacement = fmt.Sprintf("std.integer2time(%d)", int64(seconds))
+	default:
+		return false
+	}
+
+	// Fastly accepts negated number, but literal is rejected as type mismatch
+	severity := WARNING
+	if isLiteralExpression(stmt.Value) {
+		severity = ERROR
+	}
+	err := &LintError{
+		Severity: severity,
+		Token:    stmt.Value.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"Number %s is assigned to %s %s, use %s to make the unit of time explicit",
+			numberLiteral(stmt.Value), left.String(), stmt.Ident.Value, replacement,
+		),
+		Fix: &Fix{
+			Message: "Replace with " + replacement,
+			Edits: []*TextEdit{
+				{
+					Range:   expressionRange(stmt.Value),
+					NewText: replacement,
+				},
+			},
+		},
+	}
+	l.Error(err.Match(TIME_ARITHMETIC))
+	return true
+}
+
+// std.integer2time returns TIME of unix epoch seconds, assigning it to RTIME works as seconds by accident
+func (l *Linter) lintIntegerToTime(stmt *ast.SetStatement, call *ast.FunctionCallExpression, left types.Type) bool {
+	if left != types.RTimeType || call.Function.Value != "std.integer2time" || len(call.Arguments) != 1 {
+		return false
+	}
+	seconds, ok := numericSeconds(call.Arguments[0])
+	if !ok {
+		return false
+	}
+
+	replacement := formatRTime(seconds)
+	err := &LintError{
+		Severity: WARNING,
+		Token:    call.Function.GetMeta().Token,
+		Message: fmt.Sprintf(
+			"std.integer2time returns TIME of unix epoch but it is assigned to RTIME %s, use %s instead",
+			stmt.Ident.Value, replacement,
+		),
+		Fix: l.integerToTimeFix(stmt, call, replacement),
+	}
+	l.Error(err.Match(TIME_ARITHMETIC))
+	return true
+}
+
+// Suggest replacing the function call with RTIME literal.
+// AST does not have the position of the closing parenthesis, so the call is replaced until the end of the line
+// and nothing is suggested when the statement may share the line with other statements or comments.
+func (l *Linter) integerToTimeFix(stmt *ast.SetStatement, call *ast.FunctionCallExpression, replacement string) *Fix {
+	if len(l.path) < 2 {
+		return nil
+	}
+	block, ok := l.path[len(l.path)-2].(*ast.BlockStatement)
+	if !ok || !isStatementOnOwnLine(block, stmt) || len(stmt.GetMeta().Trailing) > 0 {
+		return nil
+	}
+	line := stmt.GetMeta().Token.Line
+	if !expressionOnLine(call, line) {
+		return nil
+	}
+
+	start := tokenRange(call.Function.GetMeta().Token)
+	return &Fix{
+		Message: "Replace with " + replacement,
+		Edits: []*TextEdit{
+			{
+				Range: Range{
+					File:  start.File,
+					Start: start.Start,
+					End:   Position{Line: line + 1, Column: 1},
+				},
+				NewText: replacement + ";\n",
+			},
+		},
+	}
+}
+
+// Get number of seconds from integer, float and negated literal
+func numericSeconds(exp ast.Expression) (float64, bool) {
+	switch t := exp.(type) {
+	case *ast.Integer:
+		return float64(t.Value), true
+	case *ast.Float:
+		return t.Value, true
+	case *ast.PrefixExpression:
+		if t.Operator != "-" {
+			return 0, false
+		}
+		v, ok := numericSeconds(t.Right)
+		return -v, ok
+	}
+	return 0, false
+}
+
+// Number as written in the source, String() of the expression wraps negated number with parenthesis
+func numberLiteral(exp ast.Expression) string {
+	if p, ok := exp.(*ast.PrefixExpression); ok {
+		return p.Operator + numberLiteral(p.Right)
+	}
+	return exp.GetMeta().Token.Literal
+}
+
+// Format seconds as RTIME literal like 1h, 90s or 1.5s
+func formatRTime(seconds float64) string {
+	if seconds != math.Trunc(seconds) {
+		return strconv.FormatFloat(seconds, 'f', -1, 64) + "s"
+	}
+	v := int64(seconds)
+	if v == 0 {
+		return "0s"
+	}
+	for _, unit := range rtimeUnits {
+		if v%unit.seconds == 0 {
+			return strconv.FormatInt(v/unit.seconds, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(v, 10) + "s"
+}
+
+// Range of literal and negated literal expression
+func expressionRange(exp ast.Expression) Range {
+	r := tokenRange(exp.GetMeta().Token)
+	if p, ok := exp.(*ast.PrefixExpression); ok {
+		r.End = expressionRange(p.Right).End
+	}
+	return r
+}
@@ -0,0 +1,168 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintTimeArithmetic(t *testing.T) {
+	t.Run("RTIME literal and variable", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  declare local var.TTL INTEGER;
+  set var.TTL = 60;
+  set beresp.ttl = 1h;
+  set beresp.grace = var.TTL;
+  unset beresp.http.Set-Cookie;
+  return(deliver);
+}`
+		assertNoError(t, input)
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		severity Severity
+		fix      string
+	}{
+		{
+			name:     "integer literal is assigned to RTIME",
+			input:    `set beresp.ttl = 3600;`,
+			severity: ERROR,
+			fix:      "1h",
+		},
+		{
+			name:     "integer literal is added to RTIME",
+			input:    `set beresp.ttl += 90;`,
+			severity: ERROR,
+			fix:      "90s",
+		},
+		{
+			name:     "float literal is assigned to RTIME",
+			input:    `set beresp.grace = 1.5;`,
+			severity: ERROR,
+			fix:      "1.5s",
+		},
+		{
+			name:     "negated integer is assigned to RTIME",
+			input:    `set beresp.ttl = -120;`,
+			severity: WARNING,
+			fix:      "-2m",
+		},
+		{
+			name:     "std.integer2time is assigned to RTIME",
+			input:    `set beresp.stale_while_revalidate = std.integer2time(86400);`,
+			severity: WARNING,
+			fix:      "1d;\n",
+		},
+		{
+			name:     "std.integer2time is added to RTIME",
+			input:    `set beresp.ttl += std.integer2time(-90);`,
+			severity: WARNING,
+			fix:      "-90s;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "sub vcl_fetch {\n  #FASTLY FETCH\n  unset beresp.http.Set-Cookie;\n  " + tt.input + "\n  return(deliver);\n}"
+			vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+			if len(l.Diagnostics) != 1 {
+				t.Errorf("Expect one diagnostic but got %d", len(l.Diagnostics))
+				return
+			}
+			d := l.Diagnostics[0]
+			if d.Rule != TIME_ARITHMETIC || d.Severity != tt.severity {
+				t.Errorf("Unexpected diagnostic %s of %s", d.Rule, d.Severity)
+			}
+			var fix string
+			if d.Fix != nil {
+				fix = d.Fix.Edits[0].NewText
+			}
+			if diff := cmp.Diff(tt.fix, fix); diff != "" {
+				t.Errorf("Fix unmatch, diff: %s", diff)
+			}
+		})
+	}
+
+	t.Run("integer literal is assigned to TIME", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.T TIME;
+  set var.T = 1700000000;
+  set req.http.T = strftime({"%s"}, var.T);
+}`
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("unexpected parser error: %s", err)
+			return
+		}
+		l := New()
+		l.lint(vcl, context.New())
+		if len(l.Diagnostics) != 1 || l.Diagnostics[0].Fix == nil {
+			t.Errorf("Expect one diagnostic with fix, got %v", l.Diagnostics)
+			return
+		}
+		edit := l.Diagnostics[0].Fix.Edits[0]
+		expect := &TextEdit{
+			Range: Range{
+				Start: Position{Line: 5, Column: 15},
+				End:   Position{Line: 5, Column: 25},
+			},
+			NewText: "std.integer2time(1700000000)",
+		}
+		if diff := cmp.Diff(expect, edit); diff != "" {
+			t.Errorf("Edit unmatch, diff: %s", diff)
+		}
+	})
+
+	t.Run("std.integer2time fix replaces the call until the end of line", func(t *testing.T) {
+		input := `
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.ttl = std.integer2time( 3600 ) ;
+  unset beresp.http.Set-Cookie;
+  set beresp.grace = std.integer2time(60); # trailing comment
+  return(deliver);
+}`
+		vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("unexpected parser error: %s", err)
+			return
+		}
+		l := New()
+		l.lint(vcl, context.New())
+		if len(l.Diagnostics) != 2 {
+			t.Errorf("Expect two diagnostics, got %v", l.Diagnostics)
+			return
+		}
+		expect := &TextEdit{
+			Range: Range{
+				Start: Position{Line: 4, Column: 20},
+				End:   Position{Line: 5, Column: 1},
+			},
+			NewText: "1h;\n",
+		}
+		if l.Diagnostics[0].Fix == nil {
+			t.Errorf("Expect fix for the statement on its own line")
+		} else if diff := cmp.Diff(expect, l.Diagnostics[0].Fix.Edits[0]); diff != "" {
+			t.Errorf("Edit unmatch, diff: %s", diff)
+		}
+		// Trailing comment would be removed by the fix
+		if l.Diagnostics[1].Fix != nil {
+			t.Errorf("Fix must not be suggested for the statement with trailing comment")
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package linter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Conversion specifiers which strftime function supports.
+// E and O modifiers accept a subset of specifiers, and padding flags "-", "_" and "0" accept all specifiers except "%".
+const (
+	strftimeSpecifiers          = "aAbBcCdDeFgGhHIjklmMnpPrRsStTuUVwWxXyYzZ"
+	strftimeEModifierSpecifiers = "cCnpPrRstTuxXyYzZ"
+	strftimeOModifierSpecifiers = "CdegGHIjklmMnpPrRsStTuUVwWyzZ"
+)
+
+// Time string formats which std.time function could parse, the string of unix epoch seconds is also accepted
+var stdTimeFormats = []string{
+	time.RFC1123,
+	time.RFC822,
+	time.RFC850,
+	time.ANSIC,
+	"2006-01-02 15:04:05", // ISO 8601 subset
+}
+
+// Only literal format string could be validated statically
+func (l *Linter) lintTimeFormatArguments(exp *ast.FunctionCallExpression) {
+	if len(exp.Arguments) == 0 {
+		return
+	}
+	v, ok := exp.Arguments[0].(*ast.String)
+	if !ok {
+		return
+	}
+
+	switch exp.Function.Value {
+	case "strftime":
+		if err := validateStrftimeFormat(v.Value); err != nil {
+			err := &LintError{
+				Severity: ERROR,
+				Token:    v.GetMeta().Token,
+				Message:  fmt.Sprintf("strftime format string is invalid, %s", err),
+			}
+			l.Error(err.Match(VALID_TIME_FORMAT))
+		}
+	case "std.time":
+		if !isParsableTimeString(v.Value) {
+			err := &LintError{
+				Severity: WARNING,
+				Token:    v.GetMeta().Token,
+				Message:  fmt.Sprintf(`"%s" could not be parsed as time, std.time always returns the fallback time`, v.Value),
+			}
+			l.Error(err.Match(VALID_TIME_FORMAT))
+		}
+	}
+}
+
+func validateStrftimeFormat(format string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if i+1 >= len(format) {
+			return fmt.Errorf("incomplete conversion specifier at the end")
+		}
+		i++
+		specifiers := strftimeSpecifiers + "%"
+		switch format[i] {
+		case 'E':
+			specifiers = strftimeEModifierSpecifiers
+		case 'O':
+			specifiers = strftimeOModifierSpecifiers
+		case '-', '_', '0':
+			specifiers = strftimeSpecifiers
+		default:
+			if !strings.ContainsRune(specifiers, rune(format[i])) {
+				return fmt.Errorf(`unsupported conversion specifier "%%%c" at position %d`, format[i], i)
+			}
+			continue
+		}
+		if i+1 >= len(format) {
+			return fmt.Errorf(`incomplete conversion specifier "%%%c" at the end`, format[i])
+		}
+		i++
+		if !strings.ContainsRune(specifiers, rune(format[i])) {
+			return fmt.Errorf(`unsupported conversion specifier "%%%c%c" at position %d`, format[i-1], format[i], i-1)
+		}
+	}
+	return nil
+}
+
+func isParsableTimeString(v string) bool {
+	for _, format := range stdTimeFormats {
+		if _, err := time.Parse(format, v); err == nil {
+			return true
+		}
+	}
+	// Fraction of epoch seconds is truncated
+	seconds, _, _ := strings.Cut(v, ".")
+	_, err := strconv.ParseInt(seconds, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,81 @@
+package linter
+
+import (
+	"testing"
+)
+
+func TestLintTimeFormatArguments(t *testing.T) {
+	t.Run("valid time formats", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.A = strftime({"%Y-%m-%d %H:%M:%S %z %%"}, now);
+  set req.http.B = strftime({"%-m/%_d %0H %Ec %OH"}, now);
+  set req.http.C = strftime(req.http.Format, now);
+  set req.http.D = strftime({"%s"}, std.time("Mon, 02 Jan 2006 15:04:05 MST", now));
+  set req.http.E = strftime({"%s"}, std.time("2006-01-02 15:04:05", now));
+  set req.http.F = strftime({"%s"}, std.time("1136214245.123", now));
+}`
+		assertNoError(t, input)
+	})
+
+	t.Run("unsupported conversion specifier", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.A = strftime({"%Y-%m-%d %Q"}, now);
+}`
+		assertError(t, input)
+	})
+
+	t.Run("unsupported conversion specifier with modifier", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.A = strftime({"%Ea"}, now);
+}`
+		assertError(t, input)
+	})
+
+	t.Run("incomplete conversion specifier", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.A = strftime({"%Y-%"}, now);
+}`
+		assertError(t, input)
+	})
+
+	t.Run("unparsable time string", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.A = strftime({"%s"}, std.time("2023/03/03", now));
+}`
+		assertError(t, input)
+	})
+}
+
+func TestValidateStrftimeFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		expect string
+	}{
+		{format: "%Y-%m-%d"},
+		{format: "%Q", expect: `unsupported conversion specifier "%Q" at position 1`},
+		{format: "%-%", expect: `unsupported conversion specifier "%-%" at position 1`},
+		{format: "%O", expect: `incomplete conversion specifier "%O" at the end`},
+		{format: "100%", expect: "incomplete conversion specifier at the end"},
+	}
+
+	for _, tt := range tests {
+		err := validateStrftimeFormat(tt.format)
+		var actual string
+		if err != nil {
+			actual = err.Error()
+		}
+		if actual != tt.expect {
+			t.Errorf("Unexpected result for %s, expect=%q, got=%q", tt.format, tt.expect, actual)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+)
+
+// Find the first statement of each unreachable region in the block, mapped to the statement which exits before it.
+// Statements after return, error, restart and goto are never executed until the next goto destination
+// because only goto statement could jump into there.
+func unreachableStatements(statements []ast.Statement) map[ast.Statement]ast.Statement {
+	unreachable := make(map[ast.Statement]ast.Statement)
+
+	var exit ast.Statement
+	var reported bool
+	for _, stmt := range statements {
+		if _, ok := stmt.(*ast.GotoDestinationStatement); ok {
+			exit = nil
+			continue
+		}
+		if exit != nil {
+			// Report once per region
+			if !reported {
+				unreachable[stmt] = exit
+				reported = true
+			}
+			continue
+		}
+		switch stmt.(type) {
+		case *ast.ReturnStatement, *ast.ErrorStatement, *ast.RestartStatement, *ast.GotoStatement:
+			exit = stmt
+			reported = false
+		}
+	}
+	return unreachable
+}
+
+func UnreachableStatement(stmt, exit ast.Statement) *LintError {
+	var keyword, related string
+	switch t := exit.(type) {
+	case *ast.ReturnStatement:
+		keyword, related = "return", "Subroutine returns here"
+	case *ast.ErrorStatement:
+		keyword, related = "error", "Request moves to vcl_error here"
+	case *ast.RestartStatement:
+		keyword, related = "restart", "Request restarts here"
+	case *ast.GotoStatement:
+		keyword, related = "goto", "Always jumps to "+t.Destination.Value+" here"
+	}
+
+	err := &LintError{
+		Severity: WARNING,
+		Token:    stmt.GetMeta().Token,
+		Message:  fmt.Sprintf("Unreachable code after %s statement, it is never executed", keyword),
+	}
+	err.Relate(exit.GetMeta().Token, related)
+	return err
+}
@@ -0,0 +1,106 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintUnreachableCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []int
+	}{
+		{
+			name: "statements after return",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo) {
+    return(pass);
+    set req.http.Bar = "bar";
+    set req.http.Baz = "baz";
+  }
+  return(lookup);
+}`,
+			expect: []int{6},
+		},
+		{
+			name: "statements after error and restart",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo) {
+    error 403;
+    set req.http.Bar = "bar";
+  } else {
+    restart;
+    set req.http.Baz = "baz";
+  }
+}`,
+			expect: []int{6, 9},
+		},
+		{
+			name: "statements between goto and destination",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  goto skip;
+  set req.http.Bar = "bar";
+  skip:
+  set req.http.Baz = "baz";
+}`,
+			expect: []int{5},
+		},
+		{
+			name: "destination after return is reachable",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo) {
+    goto done;
+  }
+  return(pass);
+  done:
+  return(lookup);
+}`,
+		},
+		{
+			name: "exit statement at the end of block",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.http.Foo) {
+    return(pass);
+  }
+  set req.http.Bar = "bar";
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.lint(vcl, context.New())
+
+			var lines []int
+			for _, d := range l.Diagnostics {
+				if d.Rule == UNREACHABLE_CODE {
+					lines = append(lines, d.Token.Line)
+				}
+			}
+			if diff := cmp.Diff(tt.expect, lines); diff != "" {
+				t.Errorf("Unreachable lines unmatch, diff: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package linter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+)
+
+// Lint set statement to the variable which is writable in other scopes, like beresp.ttl in vcl_deliver.
+// Reports where the variable could be set instead of generic access error,
+// and suggests moving the statement to the subroutine of the writable scope.
+// Returns true if the statement is reported.
+func (l *Linter) lintWritableScope(stmt *ast.SetStatement, ctx *context.Context) bool {
+	accessor, ok := ctx.Writable(stmt.Ident.Value)
+	if !ok || accessor.Scopes&ctx.Mode() == ctx.Mode() {
+		return false
+	}
+
+	message := fmt.Sprintf(
+		`Variable "%s" could not be set in scope of %s, it is writable in scope of %s`,
+		stmt.Ident.Value,
+		strings.TrimSpace(context.ScopesString(ctx.Mode()&^accessor.Scopes)),
+		strings.TrimSpace(context.ScopesString(accessor.Scopes)),
+	)
+
+	var fix *Fix
+	if sub := ctx.CurrentSubroutine; sub != nil {
+		if context.IsFastlySubroutine(sub.Name.Value) {
+			fix = moveStatementFix(sub, stmt, writableSubroutine(accessor.Scopes, ctx))
+		} else if accessor.Scopes&ctx.Mode() != 0 {
+			// The subroutine is called from multiple scopes and some of them could set the variable
+			message += fmt.Sprintf(
+				`, annotate the subroutine with "@scope: %s" if it is called only in these scopes`,
+				annotationScopes(accessor.Scopes&ctx.Mode()),
+			)
+		}
+	}
+
+	err := &LintError{
+		Severity: ERROR,
+		Token:    stmt.Ident.GetMeta().Token,
+		Message:  message,
+		Fix:      fix,
+	}
+	l.Error(err.Match(VARIABLE_ACCESS).Ref(accessor.Reference))
+	return true
+}
+
+// Find the declared Fastly reserved subroutine which the variable is writable in.
+// Returns nil when the variable is writable in multiple scopes because the destination could not be determined.
+func writableSubroutine(scopes int, ctx *context.Context) *ast.SubroutineDeclaration {
+	name := context.ScopeString(scopes)
+	if name == "UNKNOWN" {
+		return nil
+	}
+	s, ok := ctx.Subroutines["vcl_"+strings.ToLower(name)]
+	if !ok {
+		return nil
+	}
+	return s.Decl
+}
+
+// Format scopes as the value of @scope annotation like "hit, error"
+func annotationScopes(scopes int) string {
+	names := strings.Fields(strings.ToLower(context.ScopesString(scopes)))
+	return strings.Join(names, ", ")
+}
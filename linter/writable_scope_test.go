@@ -0,0 +1,121 @@
+package linter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func TestLintWritableScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect []string
+		fix    string
+	}{
+		{
+			name: "writable in other phase",
+			input: `
+sub vcl_fetch {
+  #FASTLY FETCH
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set beresp.ttl = 10s;
+  return (deliver);
+}`,
+			expect: []string{`Variable "beresp.ttl" could not be set in scope of DELIVER, it is writable in scope of FETCH`},
+			fix:    "Move statement to vcl_fetch",
+		},
+		{
+			name: "destination subroutine is not declared",
+			input: `
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set beresp.http.X-Foo = "bar";
+  return (deliver);
+}`,
+			expect: []string{`Variable "beresp.http.X-Foo" could not be set in scope of DELIVER, it is writable in scope of FETCH`},
+		},
+		{
+			name: "writable in multiple phases",
+			input: `
+sub vcl_hit {
+  #FASTLY HIT
+  return (deliver);
+}
+
+sub vcl_deliver {
+  #FASTLY DELIVER
+  set obj.status = 200;
+  return (deliver);
+}`,
+			expect: []string{`Variable "obj.status" could not be set in scope of DELIVER, it is writable in scope of HIT ERROR`},
+		},
+		{
+			name: "user defined subroutine called from multiple phases",
+			input: `
+// @scope: fetch, deliver
+sub set_ttl {
+  set beresp.ttl = 10s;
+}`,
+			expect: []string{
+				`Variable "beresp.ttl" could not be set in scope of DELIVER, it is writable in scope of FETCH, ` +
+					`annotate the subroutine with "@scope: fetch" if it is called only in these scopes`,
+			},
+		},
+		{
+			name: "read-only variable keeps generic message",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set client.ip = "127.0.0.1";
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vcl, err := parser.New(lexer.NewFromString(tt.input)).ParseVCL()
+			if err != nil {
+				t.Errorf("unexpected parser error: %s", err)
+				return
+			}
+			l := New()
+			l.Lint(vcl, context.New())
+
+			var messages []string
+			var fix string
+			for _, d := range l.Diagnostics {
+				if d.Rule != VARIABLE_ACCESS {
+					continue
+				}
+				if tt.expect != nil && d.Reference == "" {
+					t.Errorf("Reference of the variable should be set")
+				}
+				if d.Fix != nil {
+					fix = d.Fix.Message
+				}
+				messages = append(messages, d.Message)
+			}
+			if tt.expect == nil {
+				if len(messages) != 1 || !strings.HasPrefix(messages[0], `Variable "client.ip" is read-only`) {
+					t.Errorf("Generic read-only error should be reported, got %v", messages)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.expect, messages); diff != "" {
+				t.Errorf("Messages unmatch, diff: %s", diff)
+			}
+			if fix != tt.fix {
+				t.Errorf("Fix expects %q but got %q", tt.fix, fix)
+			}
+		})
+	}
+}
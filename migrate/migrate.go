@@ -0,0 +1,98 @@
+// Package migrate rewrites configuration files and VCL comments which are written for former falco versions
+// to the current format. Files are rewritten line by line so that formatting and comments are kept as they are.
+package migrate
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Change is a rewritten line of the file
+type Change struct {
+	Line   int    `json:"line"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Rule IDs which are renamed, old ID to new ID
+var renamedRules = map[string]string{
+	// Typo in former versions, the rule has been documented as remove-statement/syntax
+	"remote-statement/syntax": "remove-statement/syntax",
+	// Compliance rule pack reports Set-Cookie caching under the rule which is enabled by default
+	"compliance/cache-set-cookie": "cache/set-cookie",
+}
+
+// RenamedRule returns the current rule ID when the rule ID is renamed from former versions
+func RenamedRule(rule string) (string, bool) {
+	v, ok := renamedRules[rule]
+	return v, ok
+}
+
+// Rewriter rewrites a line, returns the line as it is when nothing to rewrite
+type rewriter func(line string) string
+
+// Rewrite rule IDs which are used as the key of severity overrides like "linter.rules" in YAML or JSON
+var configRewriters = func() []rewriter {
+	var rewriters []rewriter
+	for from, to := range renamedRules {
+		pattern := regexp.MustCompile(`(^|[\s{,])(["']?)` + regexp.QuoteMeta(from) + `(["']?\s*:)`)
+		replace := "${1}${2}" + to + "${3}"
+		rewriters = append(rewriters, func(line string) string {
+			return pattern.ReplaceAllString(line, replace)
+		})
+	}
+	return rewriters
+}()
+
+const scopeNames = `(?i:recv|hash|hit|miss|pass|fetch|error|deliver|log)`
+
+// Former scope annotation like "// @recv, @fetch" which is written as "// @scope: recv, fetch" currently
+var scopeAnnotation = regexp.MustCompile(
+	`^(\s*(?://|#|/\*+|\*)\s*)@(` + scopeNames + `(?:\s*,\s*@?` + scopeNames + `)*)(\s*(?:\*/)?\s*)$`,
+)
+
+// Rewrite comments in VCL files, ignore comments and annotations
+var vclRewriters = []rewriter{
+	func(line string) string {
+		m := scopeAnnotation.FindStringSubmatch(line)
+		if m == nil {
+			return line
+		}
+		scopes := strings.Split(m[2], ",")
+		for i := range scopes {
+			scopes[i] = strings.TrimPrefix(strings.TrimSpace(scopes[i]), "@")
+		}
+		return m[1] + "@scope: " + strings.Join(scopes, ", ") + m[3]
+	},
+}
+
+// Config migrates the configuration file of YAML or JSON format
+func Config(data []byte) ([]byte, []*Change) {
+	return rewrite(data, configRewriters)
+}
+
+// VCL migrates ignore comments and annotations of VCL file
+func VCL(data []byte) ([]byte, []*Change) {
+	return rewrite(data, vclRewriters)
+}
+
+func rewrite(data []byte, rewriters []rewriter) ([]byte, []*Change) {
+	var changes []*Change
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		rewritten := line
+		for _, r := range rewriters {
+			rewritten = r(rewritten)
+		}
+		if rewritten == line {
+			continue
+		}
+		changes = append(changes, &Change{
+			Line:   i + 1,
+			Before: line,
+			After:  rewritten,
+		})
+		lines[i] = rewritten
+	}
+	return []byte(strings.Join(lines, "\n")), changes
+}
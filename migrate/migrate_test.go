@@ -0,0 +1,107 @@
+package migrate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		input := `linter:
+  rules:
+    # keep this comment
+    remote-statement/syntax: IGNORE
+    "remote-statement/syntax": ERROR
+    unused/variable: ERROR
+`
+		expect := `linter:
+  rules:
+    # keep this comment
+    remove-statement/syntax: IGNORE
+    "remove-statement/syntax": ERROR
+    unused/variable: ERROR
+`
+		out, changes := Config([]byte(input))
+		if diff := cmp.Diff(expect, string(out)); diff != "" {
+			t.Errorf("Migrated config mismatch, diff=%s", diff)
+		}
+		if len(changes) != 2 || changes[0].Line != 4 || changes[1].Line != 5 {
+			t.Errorf("Changes should point line 4 and 5, got %v", changes)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		input := `{"linter":{"rules":{"remote-statement/syntax":"IGNORE"}}}`
+		expect := `{"linter":{"rules":{"remove-statement/syntax":"IGNORE"}}}`
+		out, _ := Config([]byte(input))
+		if diff := cmp.Diff(expect, string(out)); diff != "" {
+			t.Errorf("Migrated config mismatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("rule which is merged", func(t *testing.T) {
+		input := "linter:\n  rules:\n    compliance/cache-set-cookie: ERROR\n"
+		expect := "linter:\n  rules:\n    cache/set-cookie: ERROR\n"
+		out, _ := Config([]byte(input))
+		if diff := cmp.Diff(expect, string(out)); diff != "" {
+			t.Errorf("Migrated config mismatch, diff=%s", diff)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		input := "linter:\n  rules:\n    remove-statement/syntax: IGNORE\n"
+		out, changes := Config([]byte(input))
+		if len(changes) != 0 || string(out) != input {
+			t.Errorf("Up to date config should not be changed")
+		}
+	})
+}
+
+func TestVCL(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name:   "single scope",
+			input:  "// @recv",
+			expect: "// @scope: recv",
+		},
+		{
+			name:   "multiple scopes",
+			input:  "  # @hit, @pass,fetch",
+			expect: "  # @scope: hit, pass, fetch",
+		},
+		{
+			name:   "block comment",
+			input:  "/* @deliver */",
+			expect: "/* @scope: deliver */",
+		},
+		{
+			name:   "current annotation",
+			input:  "// @scope: recv",
+			expect: "// @scope: recv",
+		},
+		{
+			name:   "other annotation",
+			input:  "// @suite: foo",
+			expect: "// @suite: foo",
+		},
+		{
+			name:   "not a comment",
+			input:  `set req.http.Foo = "@recv";`,
+			expect: `set req.http.Foo = "@recv";`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _ := VCL([]byte(tt.input))
+			if diff := cmp.Diff(tt.expect, string(out)); diff != "" {
+				t.Errorf("Migrated VCL mismatch, diff=%s", diff)
+			}
+		})
+	}
+}
@@ -48,7 +48,7 @@ func (p *Parser) parseAclCidr() (*ast.AclCidr, error) {
 	var err error
 	if p.curTokenIs(token.NOT) {
 		cidr.Inverse = &ast.Boolean{
-			Meta:  clearComments(p.curToken),
+			Meta:  shareMeta(p.curToken),
 			Value: true,
 		}
 		p.nextToken() // point to IP token
@@ -58,7 +58,7 @@ func (p *Parser) parseAclCidr() (*ast.AclCidr, error) {
 		return nil, errors.WithStack(UnexpectedToken(p.peekToken, token.STRING))
 	}
 	cidr.IP = p.parseIP()
-	cidr.IP.Meta = clearComments(cidr.IP.Meta)
+	cidr.IP.Meta = shareMeta(cidr.IP.Meta)
 
 	// If SLASH token is found on peek token, need to parse CIDR mask bit
 	if p.peekTokenIs(token.SLASH) {
@@ -79,6 +79,13 @@ func (p *Parser) parseAclCidr() (*ast.AclCidr, error) {
 	cidr.Meta.Trailing = p.trailing()
 	p.nextToken() // point to semicolon
 
+	if hasComments(cidr.Meta) {
+		if cidr.Inverse != nil {
+			cidr.Inverse.Meta = detachMeta(cidr.Meta, cidr.Inverse.Meta)
+		}
+		cidr.IP.Meta = detachMeta(cidr.Meta, cidr.IP.Meta)
+	}
+
 	return cidr, nil
 }
 
@@ -358,11 +365,14 @@ func (p *Parser) parseTableProperty() (*ast.TableProperty, error) {
 		Meta: p.curToken,
 		Key:  p.parseString(),
 	}
-	prop.Key.Meta = clearComments(prop.Key.Meta)
+	prop.Key.Meta = shareMeta(prop.Key.Meta)
 
 	if !p.expectPeek(token.COLON) {
 		return nil, errors.WithStack(UnexpectedToken(p.peekToken, "COLON"))
 	}
+	if len(p.curToken.Leading) > 0 {
+		prop.Key.Meta = detachMeta(prop.Meta, prop.Key.Meta)
+	}
 	swapLeadingTrailing(p.curToken, prop.Key.Meta)
 
 	p.nextToken() // point to table value token
@@ -417,6 +427,9 @@ func (p *Parser) parseTableProperty() (*ast.TableProperty, error) {
 		// Other tokens are invalid
 		return nil, errors.WithStack(UnexpectedToken(p.peekToken, "COMMA"))
 	}
+	if hasComments(prop.Meta) {
+		prop.Key.Meta = detachMeta(prop.Meta, prop.Key.Meta)
+	}
 
 	return prop, nil
 }
@@ -68,8 +68,45 @@ func clearComments(m *ast.Meta) *ast.Meta {
 	return &mm
 }
 
+// Child node which has the same token as its parent node shares the parent's Meta while it has no comments.
+// It saves a Meta allocation per entry, which is significant for huge generated table and ACL declarations.
+func shareMeta(m *ast.Meta) *ast.Meta {
+	if hasComments(m) {
+		return clearComments(m)
+	}
+	return m
+}
+
+// Copy the shared Meta without comments before comments are attached to the parent or the child,
+// so that comments are printed only once
+func detachMeta(parent, child *ast.Meta) *ast.Meta {
+	if child != parent {
+		return child
+	}
+	mm := *child
+	mm.Leading = ast.Comments{}
+	mm.Trailing = ast.Comments{}
+	mm.Infix = ast.Comments{}
+	return &mm
+}
+
+func hasComments(m *ast.Meta) bool {
+	return len(m.Leading) > 0 || len(m.Trailing) > 0 || len(m.Infix) > 0
+}
+
 func isGotoDestination(t token.Token) bool {
 	components := strings.Split(t.Literal, ":")
 
 	return len(components) == 2
 }
+
+// "default" case of switch statement, "default:" is lexed as single IDENT
+func isDefaultCase(t token.Token) bool {
+	return t.Type == token.IDENT && (t.Literal == "default" || t.Literal == "default:")
+}
+
+// Keywords of switch statement are not reserved so that they could be used as names of declarations,
+// they are treated as keywords only where the statement starts
+func isKeyword(t token.Token, keyword string) bool {
+	return t.Type == token.IDENT && t.Literal == keyword
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+)
+
+func largeDeclarationVCL(size int) string {
+	var buf strings.Builder
+	buf.WriteString("table large_table STRING {\n")
+	for i := 0; i < size; i++ {
+		buf.WriteString(fmt.Sprintf("  \"/path/to/%d\": \"/redirect/to/%d\",\n", i, i))
+	}
+	buf.WriteString("}\n\nacl large_acl {\n")
+	for i := 0; i < size; i++ {
+		buf.WriteString(fmt.Sprintf("  \"10.%d.%d.0\"/24;\n", i/256%256, i%256))
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func BenchmarkParseLargeDeclarations(b *testing.B) {
+	input := largeDeclarationVCL(50000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := New(lexer.NewFromString(input)).ParseVCL(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEntryMetaSharing(t *testing.T) {
+	input := `table example {
+  "foo": "bar",
+  // leading comment
+  "baz": "qux", // trailing comment
+  "quux" /* infix comment */ : "corge",
+}
+
+acl internal {
+  "192.168.0.1"/32;
+  !"192.168.0.2";
+  "192.168.0.3"; // trailing comment
+}
+`
+	vcl, err := New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parse error: %s", err)
+		return
+	}
+	table := vcl.Statements[0].(*ast.TableDeclaration)
+	if table.Properties[0].Key.Meta != table.Properties[0].Meta {
+		t.Errorf("Table entry without comments should share Meta with its key")
+	}
+	if table.Properties[1].Key.Meta == table.Properties[1].Meta {
+		t.Errorf("Table entry with comments should not share Meta with its key")
+	}
+	if table.Properties[2].Key.Meta == table.Properties[2].Meta {
+		t.Errorf("Table entry with comments before colon should not share Meta with its key")
+	}
+
+	acl := vcl.Statements[1].(*ast.AclDeclaration)
+	if acl.CIDRs[0].IP.Meta != acl.CIDRs[0].Meta {
+		t.Errorf("ACL entry without comments should share Meta with its IP")
+	}
+	if acl.CIDRs[1].Inverse.Meta != acl.CIDRs[1].Meta || acl.CIDRs[1].IP.Meta == acl.CIDRs[1].Meta {
+		t.Errorf("Negated ACL entry should share Meta with its inverse sign only")
+	}
+	if acl.CIDRs[2].IP.Meta == acl.CIDRs[2].Meta {
+		t.Errorf("ACL entry with comments should not share Meta with its IP")
+	}
+
+	// Comments must be printed only once
+	for _, v := range []string{table.String(), acl.String()} {
+		if strings.Count(v, "trailing comment") != 1 {
+			t.Errorf("Trailing comment should be printed once:\n%s", v)
+		}
+	}
+	for _, v := range []string{"leading comment", "infix comment"} {
+		if strings.Count(table.String(), v) != 1 {
+			t.Errorf("%s should be printed once:\n%s", v, table.String())
+		}
+	}
+}
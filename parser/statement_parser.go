@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/token"
@@ -45,7 +47,6 @@ func (p *Parser) parseIncludeStatement() (ast.Statement, error) {
 	return i, nil
 }
 
-// nolint: dupl
 func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
 	// Note: block statement is used for declaration/statement inside like subroutine, if, elseif, else
 	// on start this statement, current token must point start of LEFT_BRACE
@@ -56,66 +57,8 @@ func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
 	}
 
 	for !p.peekTokenIs(token.RIGHT_BRACE) {
-		var stmt ast.Statement
-		var err error
-
 		p.nextToken() // point to statement
-		switch p.curToken.Token.Type {
-		// https://github.com/ysugimoto/falco/issues/17
-		// VCL accepts block syntax:
-		// ```
-		// sub vcl_recv {
-		//   {
-		//      log "recv";
-		//   }
-		// }
-		// ```
-		case token.LEFT_BRACE:
-			stmt, err = p.parseBlockStatement()
-		case token.SET:
-			stmt, err = p.parseSetStatement()
-		case token.UNSET:
-			stmt, err = p.parseUnsetStatement()
-		case token.REMOVE:
-			stmt, err = p.parseRemoveStatement()
-		case token.ADD:
-			stmt, err = p.parseAddStatement()
-		case token.CALL:
-			stmt, err = p.parseCallStatement()
-		case token.DECLARE:
-			stmt, err = p.parseDeclareStatement()
-		case token.ERROR:
-			stmt, err = p.parseErrorStatement()
-		case token.ESI:
-			stmt, err = p.parseEsiStatement()
-		case token.LOG:
-			stmt, err = p.parseLogStatement()
-		case token.RESTART:
-			stmt, err = p.parseRestartStatement()
-		case token.RETURN:
-			stmt, err = p.parseReturnStatement()
-		case token.SYNTHETIC:
-			stmt, err = p.parseSyntheticStatement()
-		case token.SYNTHETIC_BASE64:
-			stmt, err = p.parseSyntheticBase64Statement()
-		case token.IF:
-			stmt, err = p.parseIfStatement()
-		case token.GOTO:
-			stmt, err = p.parseGotoStatement()
-		case token.INCLUDE:
-			stmt, err = p.parseIncludeStatement()
-		case token.IDENT:
-			// Check if the current ident is a function call
-			if p.peekTokenIs(token.LEFT_PAREN) {
-				stmt, err = p.parseFunctionCall()
-			} else {
-				// Could be a goto destination
-				stmt, err = p.parseGotoDestination()
-			}
-		default:
-			err = UnexpectedToken(p.peekToken)
-		}
-
+		stmt, err := p.parseStatement()
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -131,6 +74,78 @@ func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
 	return b, nil
 }
 
+// Parse statement inside block like subroutine, if and switch case.
+// On start, current token must point to the start of statement.
+func (p *Parser) parseStatement() (ast.Statement, error) {
+	var stmt ast.Statement
+	var err error
+
+	switch p.curToken.Token.Type {
+	// https://github.com/ysugimoto/falco/issues/17
+	// VCL accepts block syntax:
+	// ```
+	// sub vcl_recv {
+	//   {
+	//      log "recv";
+	//   }
+	// }
+	// ```
+	case token.LEFT_BRACE:
+		stmt, err = p.parseBlockStatement()
+	case token.SET:
+		stmt, err = p.parseSetStatement()
+	case token.UNSET:
+		stmt, err = p.parseUnsetStatement()
+	case token.REMOVE:
+		stmt, err = p.parseRemoveStatement()
+	case token.ADD:
+		stmt, err = p.parseAddStatement()
+	case token.CALL:
+		stmt, err = p.parseCallStatement()
+	case token.DECLARE:
+		stmt, err = p.parseDeclareStatement()
+	case token.ERROR:
+		stmt, err = p.parseErrorStatement()
+	case token.ESI:
+		stmt, err = p.parseEsiStatement()
+	case token.LOG:
+		stmt, err = p.parseLogStatement()
+	case token.RESTART:
+		stmt, err = p.parseRestartStatement()
+	case token.RETURN:
+		stmt, err = p.parseReturnStatement()
+	case token.SYNTHETIC:
+		stmt, err = p.parseSyntheticStatement()
+	case token.SYNTHETIC_BASE64:
+		stmt, err = p.parseSyntheticBase64Statement()
+	case token.IF:
+		stmt, err = p.parseIfStatement()
+	case token.GOTO:
+		stmt, err = p.parseGotoStatement()
+	case token.INCLUDE:
+		stmt, err = p.parseIncludeStatement()
+	case token.IDENT:
+		switch {
+		// Switch statement starts with "switch (", otherwise "switch" is an identifier
+		case isKeyword(p.curToken.Token, "switch") && p.peekTokenIs(token.LEFT_PAREN):
+			stmt, err = p.parseSwitchStatement()
+		// Check if the current ident is a function call
+		case p.peekTokenIs(token.LEFT_PAREN):
+			stmt, err = p.parseFunctionCall()
+		default:
+			// Could be a goto destination
+			stmt, err = p.parseGotoDestination()
+		}
+	default:
+		err = UnexpectedToken(p.peekToken)
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return stmt, nil
+}
+
 // nolint: dupl
 func (p *Parser) parseSetStatement() (*ast.SetStatement, error) {
 	stmt := &ast.SetStatement{
@@ -603,6 +618,153 @@ func (p *Parser) parseAnotherIfStatement() (*ast.IfStatement, error) {
 	return stmt, nil
 }
 
+func (p *Parser) parseSwitchStatement() (*ast.SwitchStatement, error) {
+	stmt := &ast.SwitchStatement{
+		Meta:    p.curToken,
+		Default: -1,
+	}
+
+	if !p.expectPeek(token.LEFT_PAREN) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, "LEFT_PAREN"))
+	}
+
+	p.nextToken() // point to control expression
+	control, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	stmt.Control = control
+
+	if !p.expectPeek(token.RIGHT_PAREN) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, "RIGHT_PAREN"))
+	}
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil, errors.WithStack(UnexpectedToken(p.peekToken, "LEFT_BRACE"))
+	}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken() // point to CASE or default
+		c, err := p.parseCaseStatement()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if c.IsDefault() {
+			if stmt.Default >= 0 {
+				return nil, errors.WithStack(&ParseError{
+					Token:   c.Token,
+					Message: "Switch statement could have only one default case",
+				})
+			}
+			stmt.Default = len(stmt.Cases)
+		}
+		stmt.Cases = append(stmt.Cases, c)
+	}
+
+	stmt.Meta.Trailing = p.trailing()
+	p.nextToken() // point to RIGHT_BRACE
+
+	// RIGHT_BRACE leading comments are switch infix comments
+	swapLeadingInfix(p.curToken, stmt.Meta)
+
+	return stmt, nil
+}
+
+// Parse case or default of switch statement, statements of the case continue until next case or end of switch.
+// Note that "default:" is lexed as single IDENT because the lexer accepts colon in the identifier.
+func (p *Parser) parseCaseStatement() (*ast.CaseStatement, error) {
+	stmt := &ast.CaseStatement{
+		Meta: p.curToken,
+	}
+
+	switch {
+	case isKeyword(p.curToken.Token, "case"):
+		if p.peekTokenIs(token.REGEX_MATCH) {
+			p.nextToken() // point to REGEX_MATCH
+			stmt.Operator = p.curToken.Token.Literal
+		}
+		p.nextToken() // point to case value
+		switch p.curToken.Token.Type {
+		case token.STRING:
+			stmt.Value = p.parseString()
+		case token.INT:
+			if stmt.Operator != "" {
+				return nil, errors.WithStack(UnexpectedToken(p.curToken, "STRING"))
+			}
+			v, err := p.parseInteger()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			stmt.Value = v
+		default:
+			return nil, errors.WithStack(UnexpectedToken(p.curToken, "STRING", "INTEGER"))
+		}
+		if !p.peekTokenIs(token.COLON) {
+			return nil, errors.WithStack(UnexpectedToken(p.peekToken, "COLON"))
+		}
+		stmt.Meta.Trailing = p.trailing()
+		p.nextToken() // point to COLON
+	case isDefaultCase(p.curToken.Token):
+		if !strings.HasSuffix(p.curToken.Token.Literal, ":") {
+			if !p.peekTokenIs(token.COLON) {
+				return nil, errors.WithStack(UnexpectedToken(p.peekToken, "COLON"))
+			}
+			p.nextToken() // point to COLON
+		}
+		stmt.Meta.Trailing = p.trailing()
+	default:
+		return nil, errors.WithStack(UnexpectedToken(p.curToken, "case", "default"))
+	}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) && !isKeyword(p.peekToken.Token, "case") && !isDefaultCase(p.peekToken.Token) {
+		var s ast.Statement
+		var err error
+
+		p.nextToken() // point to statement
+		switch {
+		case isKeyword(p.curToken.Token, "break") && p.peekTokenIs(token.SEMICOLON):
+			s, err = p.parseBreakStatement()
+		case isKeyword(p.curToken.Token, "fallthrough") && p.peekTokenIs(token.SEMICOLON):
+			s, err = p.parseFallthroughStatement()
+		default:
+			s, err = p.parseStatement()
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		stmt.Statements = append(stmt.Statements, s)
+	}
+
+	return stmt, nil
+}
+
+func (p *Parser) parseBreakStatement() (*ast.BreakStatement, error) {
+	stmt := &ast.BreakStatement{
+		Meta: p.curToken,
+	}
+
+	if !p.peekTokenIs(token.SEMICOLON) {
+		return nil, errors.WithStack(MissingSemicolon(p.curToken))
+	}
+	stmt.Meta.Trailing = p.trailing()
+	p.nextToken() // point to SEMICOLON
+
+	return stmt, nil
+}
+
+func (p *Parser) parseFallthroughStatement() (*ast.FallthroughStatement, error) {
+	stmt := &ast.FallthroughStatement{
+		Meta: p.curToken,
+	}
+
+	if !p.peekTokenIs(token.SEMICOLON) {
+		return nil, errors.WithStack(MissingSemicolon(p.curToken))
+	}
+	stmt.Meta.Trailing = p.trailing()
+	p.nextToken() // point to SEMICOLON
+
+	return stmt, nil
+}
+
 func (p *Parser) parseGotoStatement() (*ast.GotoStatement, error) {
 	stmt := &ast.GotoStatement{
 		Meta: p.curToken,
@@ -1721,3 +1721,181 @@ sub vcl_recv {
 		assert(t, vcl, expect)
 	})
 }
+
+func TestSwitchStatement(t *testing.T) {
+	t.Run("cases with fallthrough and default", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+	switch (req.http.Host) {
+	// Leading comment
+	case "example.com": // Trailing comment
+		esi;
+		fallthrough;
+	case ~ "^api\.":
+		break;
+	default:
+		restart;
+		break;
+	// Infix comment
+	}
+}`
+		expect := &ast.VCL{
+			Statements: []ast.Statement{
+				&ast.SubroutineDeclaration{
+					Meta: ast.New(T, 0),
+					Name: &ast.Ident{
+						Meta:  ast.New(T, 0),
+						Value: "vcl_recv",
+					},
+					Block: &ast.BlockStatement{
+						Meta: ast.New(T, 1),
+						Statements: []ast.Statement{
+							&ast.SwitchStatement{
+								Meta: ast.New(T, 1, comments(), comments(), comments("// Infix comment")),
+								Control: &ast.Ident{
+									Meta:  ast.New(T, 1),
+									Value: "req.http.Host",
+								},
+								Cases: []*ast.CaseStatement{
+									{
+										Meta: ast.New(T, 2, comments("// Leading comment"), comments("// Trailing comment")),
+										Value: &ast.String{
+											Meta:  ast.New(T, 2),
+											Value: "example.com",
+										},
+										Statements: []ast.Statement{
+											&ast.EsiStatement{
+												Meta: ast.New(T, 2),
+											},
+											&ast.FallthroughStatement{
+												Meta: ast.New(T, 2),
+											},
+										},
+									},
+									{
+										Meta:     ast.New(T, 2),
+										Operator: "~",
+										Value: &ast.String{
+											Meta:  ast.New(T, 2),
+											Value: `^api\.`,
+										},
+										Statements: []ast.Statement{
+											&ast.BreakStatement{
+												Meta: ast.New(T, 2),
+											},
+										},
+									},
+									{
+										Meta: ast.New(T, 2),
+										Statements: []ast.Statement{
+											&ast.RestartStatement{
+												Meta: ast.New(T, 2),
+											},
+											&ast.BreakStatement{
+												Meta: ast.New(T, 2),
+											},
+										},
+									},
+								},
+								Default: 2,
+							},
+						},
+					},
+				},
+			},
+		}
+		vcl, err := New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("%+v", err)
+		}
+		assert(t, vcl, expect)
+	})
+
+	t.Run("default with space before colon and integer case", func(t *testing.T) {
+		input := `
+sub vcl_recv {
+	switch (std.itoa(req.restarts)) {
+	case 1:
+		break;
+	default :
+		break;
+	}
+}`
+		vcl, err := New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+		sw := vcl.Statements[0].(*ast.SubroutineDeclaration).Block.Statements[0].(*ast.SwitchStatement)
+		if len(sw.Cases) != 2 || sw.Default != 1 {
+			t.Errorf("Unexpected cases: %s", sw.String())
+		}
+	})
+
+	t.Run("keywords of switch statement as declaration names", func(t *testing.T) {
+		input := `
+backend switch {
+	.host = "example.com";
+}
+table case {
+	"/": "root",
+}
+acl break {
+	"127.0.0.1";
+}
+sub fallthrough {
+	set req.backend = switch;
+}
+sub vcl_recv {
+	call fallthrough;
+	if (client.ip ~ break && table.lookup(case, req.url)) {
+		switch (req.url) {
+		case "/":
+			call fallthrough;
+			break;
+		}
+	}
+}`
+		vcl, err := New(lexer.NewFromString(input)).ParseVCL()
+		if err != nil {
+			t.Errorf("%+v", err)
+			return
+		}
+		names := []string{
+			vcl.Statements[0].(*ast.BackendDeclaration).Name.Value,
+			vcl.Statements[1].(*ast.TableDeclaration).Name.Value,
+			vcl.Statements[2].(*ast.AclDeclaration).Name.Value,
+			vcl.Statements[3].(*ast.SubroutineDeclaration).Name.Value,
+		}
+		for i, expect := range []string{"switch", "case", "break", "fallthrough"} {
+			if names[i] != expect {
+				t.Errorf("Declaration name mismatch, expect=%s, got=%s", expect, names[i])
+			}
+		}
+		stmts := vcl.Statements[4].(*ast.SubroutineDeclaration).Block.Statements
+		if _, ok := stmts[0].(*ast.CallStatement); !ok {
+			t.Errorf("Expected call statement, got=%T", stmts[0])
+		}
+		sw := stmts[1].(*ast.IfStatement).Consequence.Statements[0].(*ast.SwitchStatement)
+		if len(sw.Cases) != 1 || len(sw.Cases[0].Statements) != 2 {
+			t.Errorf("Unexpected cases: %s", sw.String())
+		}
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		inputs := map[string]string{
+			"multiple default": `sub vcl_recv { switch (req.url) { default: break; default: break; } }`,
+			"missing colon":    `sub vcl_recv { switch (req.url) { case "/" break; } }`,
+			"regex integer":    `sub vcl_recv { switch (req.url) { case ~ 1: break; } }`,
+			"ident case value": `sub vcl_recv { switch (req.url) { case req.http.Foo: break; } }`,
+			"break outside":    `sub vcl_recv { break; }`,
+			"break in if":      `sub vcl_recv { switch (req.url) { case "/": if (req.http.Foo) { break; } break; } }`,
+			"statement first":  `sub vcl_recv { switch (req.url) { esi; } }`,
+		}
+		for name, input := range inputs {
+			if _, err := New(lexer.NewFromString(input)).ParseVCL(); err == nil {
+				t.Errorf("%s: expected parse error but got nil", name)
+			}
+		}
+	})
+}
@@ -0,0 +1,16 @@
+// Package falco is the stable API to use falco as a Go library.
+//
+// Packages at the root of the module like lexer, parser, linter and interpreter are the implementation of
+// falco command, and their exported identifiers may change in any minor release when the command needs it.
+// This package exposes a small set of functions and types on top of them, which follow semantic versioning:
+//
+//   - Exported functions, types and fields of this package are not removed or changed incompatibly
+//     until the next major version.
+//   - New functions, options, fields and declaration kinds may be added in minor releases.
+//   - Types of this package are owned by this package, they never alias or expose types of the implementation
+//     packages. Values are converted from the implementation, so its changes do not break downstream tools.
+//   - Lint results are returned as Report whose JSON schema is the same as `falco lint --format json`.
+//
+// Rules and their messages are not a part of the compatibility guarantee because they are improved continuously,
+// so downstream tools should not depend on the message text.
+package falco
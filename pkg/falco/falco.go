@@ -0,0 +1,207 @@
+package falco
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/interpreter"
+	"github.com/ysugimoto/falco/interpreter/cache"
+	icontext "github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/limitations"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+// Parse VCL source code, name is used as the file name of positions.
+// Returns the error which wraps ParseError when the VCL has syntax error, use errors.As to get it.
+func Parse(name, src string) (*VCL, error) {
+	vcl, err := parse(name, src)
+	if err != nil {
+		return nil, err
+	}
+	return newVCL(vcl), nil
+}
+
+func parse(name, src string) (*ast.VCL, error) {
+	vcl, err := parser.New(lexer.NewFromString(src, lexer.WithFile(name))).ParseVCL()
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return vcl, nil
+}
+
+// Convert the error of implementation packages to the error which is owned by this package
+func convertError(err error) error {
+	var pe *parser.ParseError
+	if errors.As(err, &pe) {
+		return errors.WithStack(newParseError(pe))
+	}
+	return errors.WithStack(err)
+}
+
+// NewFileResolvers creates resolvers for main VCL file, included modules are found from include paths.
+// Main accepts glob pattern so that multiple resolvers may be returned.
+func NewFileResolvers(main string, includePaths []string) ([]*Resolver, error) {
+	resolvers, err := resolver.NewFileResolvers(main, includePaths)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ret := make([]*Resolver, len(resolvers))
+	for i := range resolvers {
+		ret[i] = &Resolver{resolver: resolvers[i]}
+	}
+	return ret, nil
+}
+
+// NewStaticResolver creates resolver for main VCL source code which does not include any modules
+func NewStaticResolver(name, src string) *Resolver {
+	return &Resolver{resolver: resolver.NewStaticResolver(name, src)}
+}
+
+type lintOptions struct {
+	rules map[string]string
+	only  []string
+	skip  []string
+}
+
+// LintOption configures the linter like configuration file of falco command
+type LintOption func(o *lintOptions)
+
+// WithRuleSeverities overrides severities of rules, same as linter.rules field of the configuration file.
+// Value accepts error, warning, info and ignore.
+func WithRuleSeverities(rules map[string]string) LintOption {
+	return func(o *lintOptions) {
+		o.rules = rules
+	}
+}
+
+// WithCategories runs only rules which belong to the only categories, or skips rules in the skip categories.
+// Categories are correctness, style, performance and security.
+func WithCategories(only, skip []string) LintOption {
+	return func(o *lintOptions) {
+		o.only = only
+		o.skip = skip
+	}
+}
+
+// Lint main VCL of the resolver and included modules.
+// Returns the error which wraps ParseError when the VCL has syntax error, use errors.As to get it.
+func Lint(rslv *Resolver, options ...LintOption) (*Report, error) {
+	o := &lintOptions{}
+	for i := range options {
+		options[i](o)
+	}
+	linterOptions, err := o.linterOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	main, err := rslv.resolver.MainVCL()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	vcl, err := parse(main.Name, main.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	lt := linter.New(linterOptions...)
+	lt.Lint(vcl, context.New(context.WithResolver(rslv.resolver)))
+	if lt.FatalError != nil {
+		return nil, convertError(lt.FatalError.Error)
+	}
+	return newReport(linter.NewReport(lt.Diagnostics)), nil
+}
+
+func (o *lintOptions) linterOptions() ([]linter.Option, error) {
+	severities := make(map[linter.Rule]linter.Severity)
+	for rule, v := range o.rules {
+		severity, err := linter.ParseSeverity(v)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		severities[linter.Rule(rule)] = severity
+	}
+	only, err := linter.ParseCategories(o.only)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	skip, err := linter.ParseCategories(o.skip)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return []linter.Option{
+		linter.WithSeverities(severities),
+		linter.WithCategories(only, skip),
+	}, nil
+}
+
+// NewHandler creates HTTP handler which simulates Fastly edge with main VCL of the resolver.
+// Main VCL is parsed once, and each request is processed through VCL subroutines by individual interpreter
+// which shares the cache with other requests, then the client response is written.
+func NewHandler(rslv *Resolver) http.Handler {
+	h := &handler{
+		resolver: rslv,
+		cache:    cache.New(),
+	}
+	h.vcl, h.err = parseMainVCL(rslv)
+	return h
+}
+
+type handler struct {
+	resolver *Resolver
+	vcl      *ast.VCL
+	cache    *cache.Cache
+	// Error on parsing main VCL, it is responded for every request
+	err error
+}
+
+func parseMainVCL(rslv *Resolver) (*ast.VCL, error) {
+	main, err := rslv.resolver.MainVCL()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := limitations.CheckFastlyVCLLimitation(main.Data); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return parse(main.Name, main.Data)
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.err != nil {
+		http.Error(w, h.err.Error(), http.StatusInternalServerError)
+		return
+	}
+	i := interpreter.New(
+		icontext.WithResolver(h.resolver.resolver),
+		icontext.WithVCL(h.vcl),
+		icontext.WithCache(h.cache),
+	)
+	i.Debugger = silentDebugger{}
+	resp, err := i.Serve(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for key, values := range resp.Header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	if resp.Body != nil {
+		defer resp.Body.Close()
+		io.Copy(w, resp.Body) // nolint:errcheck
+	}
+}
+
+// Library should not write process messages to stderr
+type silentDebugger struct{}
+
+func (d silentDebugger) Run(node ast.Node) interpreter.DebugState {
+	return interpreter.DebugPass
+}
+func (d silentDebugger) Message(msg string) {}
@@ -0,0 +1,244 @@
+package falco
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/context"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/parser"
+)
+
+const testVCL = `
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.url ~ "^/foo") {
+    error 601;
+  }
+  return(lookup);
+}
+
+sub vcl_error {
+  #FASTLY ERROR
+  if (obj.status == 601) {
+    set obj.status = 200;
+    synthetic "OK";
+    return(deliver);
+  }
+}`
+
+func TestParse(t *testing.T) {
+	vcl, err := Parse("main.vcl", testVCL)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %s", err)
+	}
+	expect := []*Declaration{
+		{Kind: KindSubroutine, Name: "vcl_recv", Position: Position{File: "main.vcl", Line: 2, Column: 1}},
+		{Kind: KindSubroutine, Name: "vcl_error", Position: Position{File: "main.vcl", Line: 10, Column: 1}},
+	}
+	if diff := cmp.Diff(expect, vcl.Declarations); diff != "" {
+		t.Errorf("Declarations unmatch, diff=%s", diff)
+	}
+
+	_, err = Parse("main.vcl", "sub vcl_recv {")
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expects ParseError, got %v", err)
+	}
+	if pe.Position.File != "main.vcl" || pe.Position.Line == 0 {
+		t.Errorf("Unexpected error position: %+v", pe.Position)
+	}
+}
+
+func TestLint(t *testing.T) {
+	src := `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.unused STRING;
+  set req.http.Foo = 1;
+}`
+
+	tests := []struct {
+		name     string
+		options  []LintOption
+		errors   int
+		warnings int
+		isError  bool
+	}{
+		{name: "default", errors: 1, warnings: 1},
+		{
+			name:     "override severity",
+			options:  []LintOption{WithRuleSeverities(map[string]string{"operator/assignment": "warning"})},
+			warnings: 2,
+		},
+		{
+			name:    "only security rules",
+			options: []LintOption{WithCategories([]string{"security"}, nil)},
+		},
+		{
+			name:    "invalid severity",
+			options: []LintOption{WithRuleSeverities(map[string]string{"operator/assignment": "fatal"})},
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Lint(NewStaticResolver("main.vcl", src), tt.options...)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("Expects error but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected lint error: %s", err)
+			}
+			if report.Summary.Errors != tt.errors {
+				t.Errorf("Errors count unmatch, expect=%d, got=%d", tt.errors, report.Summary.Errors)
+			}
+			if report.Summary.Warnings != tt.warnings {
+				t.Errorf("Warnings count unmatch, expect=%d, got=%d", tt.warnings, report.Summary.Warnings)
+			}
+		})
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	h := NewHandler(NewStaticResolver("main.vcl", testVCL))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Status code unmatch, expect=200, got=%d", rec.Code)
+	}
+	if body := rec.Body.String(); body != "OK" {
+		t.Errorf("Response body unmatch, expect=OK, got=%s", body)
+	}
+}
+
+func TestNewHandlerSharesCache(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("origin")) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Test server URL parsing error: %s", err)
+	}
+	src := fmt.Sprintf(`
+backend origin {
+  .host = "%s";
+  .port = "%s";
+  .ssl = false;
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  return(lookup);
+}
+
+sub vcl_fetch {
+  #FASTLY FETCH
+  set beresp.ttl = 1h;
+}
+
+sub vcl_deliver {
+  add resp.http.X-Deliver = "1";
+}
+
+sub vcl_deliver {
+  add resp.http.X-Deliver = "2";
+}`, parsed.Hostname(), parsed.Port())
+
+	h := NewHandler(NewStaticResolver("main.vcl", src))
+	for _, state := range []string{"MISS", "HIT"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status code unmatch, expect=200, got=%d, body=%s", state, rec.Code, rec.Body.String())
+		}
+		if v := rec.Header().Get("X-Cache"); v != state {
+			t.Errorf("X-Cache header unmatch, expect=%s, got=%s", state, v)
+		}
+		// Concatenated subroutines must not be accumulated over requests
+		if v := rec.Header().Values("X-Deliver"); len(v) != 2 {
+			t.Errorf("%s: X-Deliver header unmatch, expect=[1 2], got=%v", state, v)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Backend requests count unmatch, expect=1, got=%d", requests)
+	}
+}
+
+func TestReportSchema(t *testing.T) {
+	src := `
+sub vcl_recv {
+  #FASTLY RECV
+  declare local var.unused STRING;
+  set req.http.Foo = 1;
+}`
+	report, err := Lint(NewStaticResolver("main.vcl", src))
+	if err != nil {
+		t.Fatalf("Unexpected lint error: %s", err)
+	}
+
+	// Report must be the same JSON as `falco lint --format json`
+	vcl, err := parser.New(lexer.NewFromString(src, lexer.WithFile("main.vcl"))).ParseVCL()
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %s", err)
+	}
+	lt := linter.New()
+	lt.Lint(vcl, context.New())
+	expect, err := json.Marshal(linter.NewReport(lt.Diagnostics))
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %s", err)
+	}
+	actual, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %s", err)
+	}
+	if diff := cmp.Diff(string(expect), string(actual)); diff != "" {
+		t.Errorf("Report JSON unmatch, diff=%s", diff)
+	}
+}
+
+// Exported types must not expose types of the implementation packages
+func TestExportedTypes(t *testing.T) {
+	self := reflect.TypeOf(VCL{}).PkgPath()
+	var check func(name string, rt reflect.Type)
+	check = func(name string, rt reflect.Type) {
+		switch rt.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			check(name, rt.Elem())
+			return
+		}
+		if rt.PkgPath() != "" && rt.PkgPath() != self && strings.HasPrefix(rt.PkgPath(), "github.com/ysugimoto/falco") {
+			t.Errorf("%s exposes implementation type %s", name, rt)
+			return
+		}
+		if rt.PkgPath() != self || rt.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < rt.NumField(); i++ {
+			if f := rt.Field(i); f.IsExported() {
+				check(rt.Name()+"."+f.Name, f.Type)
+			}
+		}
+	}
+	for _, v := range []any{VCL{}, ParseError{}, Resolver{}, Report{}} {
+		check(reflect.TypeOf(v).Name(), reflect.TypeOf(v))
+	}
+}
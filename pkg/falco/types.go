@@ -0,0 +1,211 @@
+package falco
+
+import (
+	"fmt"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/linter"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/token"
+)
+
+// Types in this file are owned by this package and converted from the implementation packages,
+// so that changes of the implementation do not break downstream tools.
+
+// Kinds of root declarations
+const (
+	KindAcl         = "acl"
+	KindBackend     = "backend"
+	KindDirector    = "director"
+	KindTable       = "table"
+	KindSubroutine  = "subroutine"
+	KindPenaltybox  = "penaltybox"
+	KindRatecounter = "ratecounter"
+	KindImport      = "import"
+	KindInclude     = "include"
+)
+
+// Position is the location in the VCL source, Line and Column start from 1
+type Position struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func newPosition(t token.Token) Position {
+	return Position{File: t.File, Line: t.Line, Column: t.Position}
+}
+
+// Declaration is the root declaration of VCL like backend, table or subroutine.
+// Name is the module name for include statement.
+type Declaration struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Position Position `json:"position"`
+}
+
+// VCL is the parsed VCL program
+type VCL struct {
+	Declarations []*Declaration `json:"declarations"`
+
+	vcl *ast.VCL
+}
+
+func newVCL(vcl *ast.VCL) *VCL {
+	v := &VCL{
+		Declarations: []*Declaration{},
+		vcl:          vcl,
+	}
+	for _, stmt := range vcl.Statements {
+		d := &Declaration{Position: newPosition(stmt.GetMeta().Token)}
+		switch t := stmt.(type) {
+		case *ast.AclDeclaration:
+			d.Kind, d.Name = KindAcl, t.Name.Value
+		case *ast.BackendDeclaration:
+			d.Kind, d.Name = KindBackend, t.Name.Value
+		case *ast.DirectorDeclaration:
+			d.Kind, d.Name = KindDirector, t.Name.Value
+		case *ast.TableDeclaration:
+			d.Kind, d.Name = KindTable, t.Name.Value
+		case *ast.SubroutineDeclaration:
+			d.Kind, d.Name = KindSubroutine, t.Name.Value
+		case *ast.PenaltyboxDeclaration:
+			d.Kind, d.Name = KindPenaltybox, t.Name.Value
+		case *ast.RatecounterDeclaration:
+			d.Kind, d.Name = KindRatecounter, t.Name.Value
+		case *ast.ImportStatement:
+			d.Kind, d.Name = KindImport, t.Name.Value
+		case *ast.IncludeStatement:
+			d.Kind, d.Name = KindInclude, t.Module.Value
+		default:
+			continue
+		}
+		v.Declarations = append(v.Declarations, d)
+	}
+	return v
+}
+
+// String returns the formatted VCL source code
+func (v *VCL) String() string {
+	return v.vcl.String()
+}
+
+// ParseError is returned when VCL has syntax error
+type ParseError struct {
+	Position Position
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	var file string
+	if e.Position.File != "" {
+		file = " at " + e.Position.File
+	}
+	return fmt.Sprintf(
+		"Parse Error: %s%s, line: %d, position: %d",
+		e.Message, file, e.Position.Line, e.Position.Column,
+	)
+}
+
+func newParseError(pe *parser.ParseError) *ParseError {
+	return &ParseError{
+		Position: newPosition(pe.Token),
+		Message:  pe.Message,
+	}
+}
+
+// Resolver provides main VCL and included modules
+type Resolver struct {
+	resolver resolver.Resolver
+}
+
+// Name returns the name of main VCL
+func (r *Resolver) Name() string {
+	return r.resolver.Name()
+}
+
+// ReportPosition is the position in the lint report, Line and Column start from 1
+type ReportPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// ReportSpan is a span of the source file, End points to the next position of the last character
+type ReportSpan struct {
+	Start ReportPosition `json:"start"`
+	End   ReportPosition `json:"end"`
+}
+
+// ReportRelated is a secondary location of the diagnostic like the first declaration of duplicated one
+type ReportRelated struct {
+	File    string     `json:"file"`
+	Span    ReportSpan `json:"span"`
+	Message string     `json:"message"`
+}
+
+// ReportDiagnostic is a lint result in the report
+type ReportDiagnostic struct {
+	Rule      string           `json:"rule,omitempty"`
+	Category  string           `json:"category,omitempty"`
+	Severity  string           `json:"severity"`
+	File      string           `json:"file"`
+	Span      ReportSpan       `json:"span"`
+	Message   string           `json:"message"`
+	Reference string           `json:"reference,omitempty"`
+	Related   []*ReportRelated `json:"related,omitempty"`
+	// True when the diagnostic has suggested fix which could be applied by `falco lint --fix`
+	Fixable bool `json:"fixable"`
+}
+
+// ReportSummary is the count of diagnostics per severity
+type ReportSummary struct {
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+	Infos    int `json:"infos"`
+}
+
+// Report is the lint result, JSON schema is the same as `falco lint --format json`
+type Report struct {
+	Summary     ReportSummary       `json:"summary"`
+	Diagnostics []*ReportDiagnostic `json:"diagnostics"`
+}
+
+func newReport(r *linter.Report) *Report {
+	report := &Report{
+		Summary: ReportSummary{
+			Errors:   r.Summary.Errors,
+			Warnings: r.Summary.Warnings,
+			Infos:    r.Summary.Infos,
+		},
+		Diagnostics: make([]*ReportDiagnostic, 0, len(r.Diagnostics)),
+	}
+	for _, d := range r.Diagnostics {
+		rd := &ReportDiagnostic{
+			Rule:      string(d.Rule),
+			Category:  string(d.Category),
+			Severity:  d.Severity,
+			File:      d.File,
+			Span:      newReportSpan(d.Span),
+			Message:   d.Message,
+			Reference: d.Reference,
+			Fixable:   d.Fixable,
+		}
+		for _, rel := range d.Related {
+			rd.Related = append(rd.Related, &ReportRelated{
+				File:    rel.File,
+				Span:    newReportSpan(rel.Span),
+				Message: rel.Message,
+			})
+		}
+		report.Diagnostics = append(report.Diagnostics, rd)
+	}
+	return report
+}
+
+func newReportSpan(s linter.ReportSpan) ReportSpan {
+	return ReportSpan{
+		Start: ReportPosition{Line: s.Start.Line, Column: s.Start.Column},
+		End:   ReportPosition{Line: s.End.Line, Column: s.End.Column},
+	}
+}
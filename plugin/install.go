@@ -0,0 +1,281 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Manifest file which records installed plugins in the plugin directory
+const manifestFile = "plugins.json"
+
+// Installed is a plugin which is installed to the plugin directory
+type Installed struct {
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	Source       string    `json:"source"`
+	Capabilities []string  `json:"capabilities"`
+	Binary       string    `json:"binary"`
+	SHA256       string    `json:"sha256"`
+	Verified     bool      `json:"verified"`
+	InstalledAt  time.Time `json:"installed_at"`
+}
+
+// DefaultPath returns the plugin directory which is used when the path is not configured
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".falco/plugins"
+	}
+	return filepath.Join(home, ".falco", "plugins")
+}
+
+// Installer downloads and verifies the plugin, then places the binary on the plugin directory
+type Installer struct {
+	registry *Registry
+	dir      string
+	// Base64 encoded ed25519 public keys, the signature is required when keys are specified
+	trustedKeys []ed25519.PublicKey
+}
+
+func NewInstaller(registry *Registry, dir string, trustedKeys []string) (*Installer, error) {
+	i := &Installer{
+		registry: registry,
+		dir:      dir,
+	}
+	for _, k := range trustedKeys {
+		key, err := base64.StdEncoding.DecodeString(k)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Trusted key %s is not a base64 encoded ed25519 public key", k)
+		}
+		i.trustedKeys = append(i.trustedKeys, ed25519.PublicKey(key))
+	}
+	return i, nil
+}
+
+// Install resolves the plugin and installs it, the same name plugin is overwritten
+func (i *Installer) Install(ctx context.Context, name, version string) (*Installed, error) {
+	pkg, err := i.registry.Resolve(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := i.registry.fetch(ctx, pkg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download plugin %s: %w", pkg.Name, err)
+	}
+
+	sum := sha256.Sum256(buf)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, pkg.SHA256) {
+		return nil, fmt.Errorf("Checksum mismatch for plugin %s, expected %s but got %s", pkg.Name, pkg.SHA256, actual)
+	}
+	verified, err := i.verify(pkg, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	binary := BinaryName(pkg.Name)
+	if isGzip(buf) {
+		if buf, err = extract(buf, binary); err != nil {
+			return nil, fmt.Errorf("Failed to extract plugin %s: %w", pkg.Name, err)
+		}
+	}
+
+	if err := os.MkdirAll(i.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("Failed to create plugin directory %s: %w", i.dir, err)
+	}
+	// Write to temporary file and rename it to avoid leaving broken binary
+	tmp, err := os.CreateTemp(i.dir, "."+binary+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("Failed to write plugin %s: %w", pkg.Name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("Failed to write plugin %s: %w", pkg.Name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return nil, fmt.Errorf("Failed to change mode of plugin %s: %w", pkg.Name, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(i.dir, binary)); err != nil {
+		return nil, fmt.Errorf("Failed to place plugin %s: %w", pkg.Name, err)
+	}
+
+	installed := &Installed{
+		Name:         pkg.Name,
+		Version:      pkg.Version,
+		Source:       pkg.Source,
+		Capabilities: pkg.Capabilities,
+		Binary:       filepath.Join(i.dir, binary),
+		SHA256:       strings.ToLower(pkg.SHA256),
+		Verified:     verified,
+		InstalledAt:  time.Now().UTC(),
+	}
+	if err := i.record(installed); err != nil {
+		return nil, err
+	}
+	return installed, nil
+}
+
+// Verify signature of the downloaded file, returns true when the signature is verified.
+// Signature is required only when the trusted keys are configured.
+func (i *Installer) verify(pkg *Package, buf []byte) (bool, error) {
+	if len(i.trustedKeys) == 0 {
+		return false, nil
+	}
+	if pkg.Signature == "" {
+		return false, fmt.Errorf("Plugin %s %s is not signed but trusted keys are configured", pkg.Name, pkg.Version)
+	}
+	sig, err := base64.StdEncoding.DecodeString(pkg.Signature)
+	if err != nil {
+		return false, fmt.Errorf("Signature of plugin %s is not base64 encoded: %w", pkg.Name, err)
+	}
+	for _, key := range i.trustedKeys {
+		if ed25519.Verify(key, buf, sig) {
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("Signature of plugin %s %s is not signed by trusted keys", pkg.Name, pkg.Version)
+}
+
+func (i *Installer) record(installed *Installed) error {
+	plugins, err := loadManifest(i.dir)
+	if err != nil {
+		return err
+	}
+	var replaced bool
+	for j := range plugins {
+		if plugins[j].Name == installed.Name {
+			plugins[j] = installed
+			replaced = true
+		}
+	}
+	if !replaced {
+		plugins = append(plugins, installed)
+	}
+	sort.Slice(plugins, func(a, b int) bool {
+		return plugins[a].Name < plugins[b].Name
+	})
+
+	buf, err := json.MarshalIndent(plugins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode plugin manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(i.dir, manifestFile), buf, 0o644); err != nil {
+		return fmt.Errorf("Failed to write plugin manifest: %w", err)
+	}
+	return nil
+}
+
+func loadManifest(dir string) ([]*Installed, error) {
+	buf, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed to read plugin manifest: %w", err)
+	}
+	var plugins []*Installed
+	if err := json.Unmarshal(buf, &plugins); err != nil {
+		return nil, fmt.Errorf("Failed to decode plugin manifest: %w", err)
+	}
+	return plugins, nil
+}
+
+// List returns installed plugins in the plugin directory,
+// and transformer binaries in PATH which are installed manually.
+// Version of manually installed plugins is empty because falco could not know it.
+func List(dir string) ([]*Installed, error) {
+	plugins, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]struct{})
+	for _, p := range plugins {
+		found[p.Name] = struct{}{}
+	}
+
+	dirs := append([]string{dir}, filepath.SplitList(os.Getenv("PATH"))...)
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasPrefix(e.Name(), TransformerPrefix) {
+				continue
+			}
+			name := strings.TrimPrefix(e.Name(), TransformerPrefix)
+			if _, ok := found[name]; ok {
+				continue
+			}
+			found[name] = struct{}{}
+			plugins = append(plugins, &Installed{
+				Name:         name,
+				Source:       "PATH",
+				Capabilities: []string{CapabilityTransform},
+				Binary:       filepath.Join(d, e.Name()),
+			})
+		}
+	}
+	sort.SliceStable(plugins, func(a, b int) bool {
+		return plugins[a].Name < plugins[b].Name
+	})
+	return plugins, nil
+}
+
+// LookPath finds the plugin binary from the plugin directory first, then PATH
+func LookPath(dir, name string) (string, error) {
+	binary := BinaryName(name)
+	if dir != "" {
+		path := filepath.Join(dir, binary)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+			return path, nil
+		}
+	}
+	return exec.LookPath(binary)
+}
+
+func isGzip(buf []byte) bool {
+	return len(buf) > 2 && buf[0] == 0x1f && buf[1] == 0x8b
+}
+
+// Extract the binary from tar.gz archive
+func extract(buf []byte, binary string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg || filepath.Base(h.Name) != binary {
+			continue
+		}
+		return io.ReadAll(io.LimitReader(tr, maxDownloadSize))
+	}
+	return nil, fmt.Errorf("Binary %s is not found in the archive", binary)
+}
@@ -0,0 +1,193 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func checksum(buf []byte) string {
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:])
+}
+
+func tarGzip(t *testing.T, name string, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Write(data) // nolint:errcheck
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestInstallFromRegistry(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho transform\n")
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := &Index{
+		Plugins: []*Entry{
+			{
+				Name:         "example",
+				Capabilities: []string{CapabilityTransform},
+				Releases: []*Release{
+					{
+						Version: "1.1.0",
+						Assets: []*Asset{
+							{
+								OS:        "linux",
+								Arch:      "amd64",
+								URL:       "example_1.1.0",
+								SHA256:    checksum(binary),
+								Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, binary)),
+							},
+						},
+					},
+					{
+						Version: "1.0.0",
+						Assets: []*Asset{
+							{OS: "linux", Arch: "amd64", URL: "example_1.0.0", SHA256: checksum([]byte("other"))},
+						},
+					},
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.json":
+			json.NewEncoder(w).Encode(index) // nolint:errcheck
+		case "/example_1.1.0", "/example_1.0.0":
+			w.Write(binary) // nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	newInstaller := func(t *testing.T, keys []string) (*Installer, string) {
+		dir := t.TempDir()
+		registry := NewRegistry(server.URL+"/index.json", server.Client())
+		registry.goos, registry.goarch = "linux", "amd64"
+		installer, err := NewInstaller(registry, dir, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return installer, dir
+	}
+
+	t.Run("install latest with signature", func(t *testing.T) {
+		installer, dir := newInstaller(t, []string{base64.StdEncoding.EncodeToString(pub)})
+		installed, err := installer.Install(context.Background(), "example", "")
+		if err != nil {
+			t.Fatalf("Unexpected install error: %s", err)
+		}
+		if installed.Version != "1.1.0" || !installed.Verified {
+			t.Errorf("Unexpected installed plugin: %+v", installed)
+		}
+		buf, err := os.ReadFile(filepath.Join(dir, "falco-transform-example"))
+		if err != nil || !bytes.Equal(buf, binary) {
+			t.Errorf("Plugin binary is not placed: %s", err)
+		}
+
+		plugins, err := List(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plugins) == 0 || plugins[0].Name != "example" || plugins[0].Version != "1.1.0" {
+			t.Errorf("Installed plugin is not listed: %+v", plugins)
+		}
+		if path, err := LookPath(dir, "example"); err != nil || path != filepath.Join(dir, "falco-transform-example") {
+			t.Errorf("Installed plugin is not found: %s, %v", path, err)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		installer, _ := newInstaller(t, nil)
+		_, err := installer.Install(context.Background(), "example", "v1.0.0")
+		if err == nil || !strings.Contains(err.Error(), "Checksum mismatch") {
+			t.Errorf("Checksum mismatch error is expected, got %v", err)
+		}
+	})
+
+	t.Run("unsigned plugin with trusted keys", func(t *testing.T) {
+		other, _, _ := ed25519.GenerateKey(nil)
+		installer, _ := newInstaller(t, []string{base64.StdEncoding.EncodeToString(other)})
+		_, err := installer.Install(context.Background(), "example", "1.1.0")
+		if err == nil || !strings.Contains(err.Error(), "not signed by trusted keys") {
+			t.Errorf("Signature error is expected, got %v", err)
+		}
+	})
+
+	t.Run("plugin not found", func(t *testing.T) {
+		installer, _ := newInstaller(t, nil)
+		if _, err := installer.Install(context.Background(), "unknown", ""); err == nil {
+			t.Errorf("Not found error is expected")
+		}
+	})
+}
+
+func TestInstallFromGitHub(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho transform\n")
+	archive := tarGzip(t, "falco-transform-example", binary)
+	asset := "falco-transform-example_1.0.0_linux_amd64.tar.gz"
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/falco-transform-example/releases/latest":
+			json.NewEncoder(w).Encode(map[string]interface{}{ // nolint:errcheck
+				"tag_name": "v1.0.0",
+				"assets": []map[string]string{
+					{"name": "falco-transform-example_1.0.0_linux_arm64.tar.gz", "browser_download_url": server.URL + "/arm64"},
+					{"name": asset, "browser_download_url": server.URL + "/asset"},
+					{"name": "checksums.txt", "browser_download_url": server.URL + "/checksums"},
+				},
+			})
+		case "/asset":
+			w.Write(archive) // nolint:errcheck
+		case "/checksums":
+			w.Write([]byte(checksum([]byte("arm64")) + "  falco-transform-example_1.0.0_linux_arm64.tar.gz\n" + checksum(archive) + "  " + asset + "\n")) // nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	registry := NewRegistry("", server.Client())
+	registry.githubAPI = server.URL
+	registry.goos, registry.goarch = "linux", "amd64"
+	installer, err := NewInstaller(registry, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed, err := installer.Install(context.Background(), "github.com/owner/falco-transform-example", "")
+	if err != nil {
+		t.Fatalf("Unexpected install error: %s", err)
+	}
+	if installed.Name != "example" || installed.Version != "v1.0.0" || installed.Verified {
+		t.Errorf("Unexpected installed plugin: %+v", installed)
+	}
+	buf, err := os.ReadFile(filepath.Join(dir, "falco-transform-example"))
+	if err != nil || !bytes.Equal(buf, binary) {
+		t.Errorf("Plugin binary is not extracted from the archive: %v", err)
+	}
+}
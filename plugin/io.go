@@ -60,6 +60,10 @@ func init() {
 	gob.Register(&ast.GroupedExpression{})
 	gob.Register(&ast.GotoStatement{})
 	gob.Register(&ast.GotoDestinationStatement{})
+	gob.Register(&ast.SwitchStatement{})
+	gob.Register(&ast.CaseStatement{})
+	gob.Register(&ast.BreakStatement{})
+	gob.Register(&ast.FallthroughStatement{})
 	gob.Register(&ast.VCL{})
 }
 
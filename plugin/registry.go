@@ -0,0 +1,299 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Capabilities which the plugin declares
+const (
+	// Transformer which receives encoded VCL from stdin, executed by -t option
+	CapabilityTransform = "transform"
+)
+
+// Prefix of transformer binary, falco looks up "falco-transform-[name]" for the transformer
+const TransformerPrefix = "falco-transform-"
+
+// Limit size of downloaded files to avoid exhausting memory
+const maxDownloadSize = 256 << 20
+
+// BinaryName returns the binary name of the plugin
+func BinaryName(name string) string {
+	return TransformerPrefix + name
+}
+
+// Asset is a downloadable file of the plugin release for a platform
+type Asset struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	// URL of the binary or tar.gz archive, relative URL is resolved from the registry location
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+	// Base64 encoded ed25519 signature of the downloaded file
+	Signature string `json:"signature,omitempty"`
+}
+
+// Release is a version of the plugin
+type Release struct {
+	Version string   `json:"version"`
+	Assets  []*Asset `json:"assets"`
+}
+
+// Entry is a plugin which is listed in the registry
+type Entry struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Capabilities []string   `json:"capabilities"`
+	Releases     []*Release `json:"releases"`
+}
+
+// Index is the document which the registry serves as JSON
+type Index struct {
+	Plugins []*Entry `json:"plugins"`
+}
+
+// Package is the resolved plugin which is going to be installed
+type Package struct {
+	Name         string
+	Version      string
+	Source       string
+	Capabilities []string
+	URL          string
+	SHA256       string
+	Signature    string
+}
+
+// Registry resolves plugins from the registry index or GitHub releases
+type Registry struct {
+	index  string
+	client *http.Client
+	// API endpoint of GitHub, could be overridden in testing
+	githubAPI string
+	goos      string
+	goarch    string
+}
+
+func NewRegistry(index string, client *http.Client) *Registry {
+	return &Registry{
+		index:     index,
+		client:    client,
+		githubAPI: "https://api.github.com",
+		goos:      runtime.GOOS,
+		goarch:    runtime.GOARCH,
+	}
+}
+
+// Resolve finds the plugin package for the current platform.
+// The name is resolved from GitHub releases when it has "github.com/" prefix like "github.com/owner/repo",
+// otherwise it is resolved from the registry index. Empty version means the latest release.
+func (r *Registry) Resolve(ctx context.Context, name, version string) (*Package, error) {
+	if strings.HasPrefix(name, "github.com/") {
+		return r.resolveGitHub(ctx, strings.TrimPrefix(name, "github.com/"), version)
+	}
+	return r.resolveIndex(ctx, name, version)
+}
+
+func (r *Registry) resolveIndex(ctx context.Context, name, version string) (*Package, error) {
+	if r.index == "" {
+		return nil, fmt.Errorf(
+			"Plugin registry is not configured, specify plugin.registry in configuration or install from GitHub releases like github.com/owner/repo",
+		)
+	}
+	buf, err := r.fetch(ctx, r.index)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch plugin registry %s: %w", r.index, err)
+	}
+	var index Index
+	if err := json.Unmarshal(buf, &index); err != nil {
+		return nil, fmt.Errorf("Failed to decode plugin registry %s: %w", r.index, err)
+	}
+
+	var entry *Entry
+	for _, p := range index.Plugins {
+		if p.Name == name {
+			entry = p
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("Plugin %s is not found in the registry", name)
+	}
+
+	// Releases are listed in newest first order
+	var release *Release
+	for _, rel := range entry.Releases {
+		if version == "" || strings.TrimPrefix(rel.Version, "v") == strings.TrimPrefix(version, "v") {
+			release = rel
+			break
+		}
+	}
+	if release == nil {
+		return nil, fmt.Errorf("Version %s of plugin %s is not found in the registry", version, name)
+	}
+
+	for _, a := range release.Assets {
+		if a.OS != r.goos || a.Arch != r.goarch {
+			continue
+		}
+		if a.SHA256 == "" {
+			return nil, fmt.Errorf("Plugin %s %s does not have checksum in the registry", name, release.Version)
+		}
+		return &Package{
+			Name:         entry.Name,
+			Version:      release.Version,
+			Source:       r.index,
+			Capabilities: entry.Capabilities,
+			URL:          r.resolveURL(a.URL),
+			SHA256:       a.SHA256,
+			Signature:    a.Signature,
+		}, nil
+	}
+	return nil, fmt.Errorf("Plugin %s %s is not available for %s/%s", name, release.Version, r.goos, r.goarch)
+}
+
+// GitHub release response, only used fields are declared
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (r *Registry) resolveGitHub(ctx context.Context, repo, version string) (*Package, error) {
+	if strings.Count(repo, "/") != 1 {
+		return nil, fmt.Errorf("GitHub repository must be specified as github.com/owner/repo, got %s", repo)
+	}
+	endpoint := r.githubAPI + "/repos/" + repo + "/releases/latest"
+	if version != "" {
+		endpoint = r.githubAPI + "/repos/" + repo + "/releases/tags/" + url.PathEscape(version)
+	}
+	buf, err := r.fetch(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch GitHub release of %s: %w", repo, err)
+	}
+	var release githubRelease
+	if err := json.Unmarshal(buf, &release); err != nil {
+		return nil, fmt.Errorf("Failed to decode GitHub release of %s: %w", repo, err)
+	}
+
+	name := strings.TrimPrefix(repo[strings.Index(repo, "/")+1:], TransformerPrefix)
+	pkg := &Package{
+		Name:         name,
+		Version:      release.TagName,
+		Source:       "github.com/" + repo,
+		Capabilities: []string{CapabilityTransform},
+	}
+
+	// Find the asset for the platform, and checksums file which is generated by goreleaser and similar tools
+	var asset, checksums, signature string
+	for _, a := range release.Assets {
+		lower := strings.ToLower(a.Name)
+		switch {
+		case strings.HasSuffix(lower, "checksums.txt"):
+			checksums = a.URL
+		case strings.HasSuffix(lower, ".sig"):
+			continue
+		case hasToken(lower, r.goos) && hasToken(lower, r.goarch) && asset == "":
+			asset = a.Name
+			pkg.URL = a.URL
+		}
+	}
+	if asset == "" {
+		return nil, fmt.Errorf("Release %s of %s does not have asset for %s/%s", release.TagName, repo, r.goos, r.goarch)
+	}
+	if checksums == "" {
+		return nil, fmt.Errorf("Release %s of %s does not have checksums.txt, could not verify the asset", release.TagName, repo)
+	}
+	for _, a := range release.Assets {
+		if a.Name == asset+".sig" {
+			signature = a.URL
+		}
+	}
+
+	buf, err = r.fetch(ctx, checksums)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch checksums of %s: %w", repo, err)
+	}
+	// Each line is formatted as "[sha256 hex]  [file name]"
+	for _, line := range strings.Split(string(buf), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			pkg.SHA256 = fields[0]
+		}
+	}
+	if pkg.SHA256 == "" {
+		return nil, fmt.Errorf("Checksum of %s is not found in checksums.txt", asset)
+	}
+
+	if signature != "" {
+		buf, err := r.fetch(ctx, signature)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch signature of %s: %w", asset, err)
+		}
+		pkg.Signature = strings.TrimSpace(string(buf))
+	}
+	return pkg, nil
+}
+
+// Check the asset name contains the platform name as a token, "arm" should not match "arm64"
+func hasToken(name, token string) bool {
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	for _, f := range fields {
+		if f == token {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve relative asset URL from the registry location
+func (r *Registry) resolveURL(asset string) string {
+	if strings.Contains(asset, "://") || filepath.IsAbs(asset) {
+		return asset
+	}
+	if base, err := url.Parse(r.index); err == nil && base.Scheme != "" {
+		if ref, err := url.Parse(asset); err == nil {
+			return base.ResolveReference(ref).String()
+		}
+	}
+	return filepath.Join(filepath.Dir(r.index), asset)
+}
+
+// Fetch HTTP(S) URL or local file, local registry is useful for private plugins
+func (r *Registry) fetch(ctx context.Context, location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(strings.TrimPrefix(location, "file://"))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected status code %d from %s", resp.StatusCode, location)
+	}
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) > maxDownloadSize {
+		return nil, fmt.Errorf("Response from %s exceeds %d bytes", location, maxDownloadSize)
+	}
+	return buf, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"encoding/json"
 	"net/http"
+	"net/url"
 
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -16,6 +17,10 @@ import (
 
 const (
 	fastlyApiBaseUrl = "https://api.fastly.com"
+	// Page size of paginated list APIs
+	fastlyApiPerPage = 100
+	// Maximum operations in a single batch API request
+	fastlyApiBatchSize = 1000
 )
 
 type FastlyClient struct {
@@ -32,14 +37,28 @@ func NewFastlyClient(c *http.Client, serviceId, apiKey string) *FastlyClient {
 	}
 }
 
+// Read resources via GET request, most of falco features DO NOT change any resources
 func (c *FastlyClient) request(ctx context.Context, url string, v interface{}) error {
-	// falco always call API with GET request because we DO NOT change any resources
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fastlyApiBaseUrl+url, nil)
+	return c.do(ctx, http.MethodGet, url, nil, v)
+}
+
+// Send request with JSON body, used only for the commands which explicitly sync resources
+func (c *FastlyClient) do(ctx context.Context, method, url string, body, v interface{}) error {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fastlyApiBaseUrl+url, &payload)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	req.Header.Set("Fastly-Key", c.apiKey)
 	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -119,15 +138,46 @@ func (c *FastlyClient) ListEdgeDictionaries(ctx context.Context, version int64)
 }
 
 func (c *FastlyClient) ListEdgeDictionaryItems(ctx context.Context, dictId string) ([]*EdgeDictionaryItem, error) {
-	endpoint := fmt.Sprintf("/service/%s/dictionary/%s/items", c.serviceId, dictId)
 	var items []*EdgeDictionaryItem
-	if err := c.request(ctx, endpoint, &items); err != nil {
-		return nil, errors.WithStack(err)
+	// Dictionary items API is paginated so fetch all pages
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf(
+			"/service/%s/dictionary/%s/items?page=%d&per_page=%d", c.serviceId, dictId, page, fastlyApiPerPage,
+		)
+		var v []*EdgeDictionaryItem
+		if err := c.request(ctx, endpoint, &v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		items = append(items, v...)
+		if len(v) < fastlyApiPerPage {
+			break
+		}
 	}
 
 	return items, nil
 }
 
+func (c *FastlyClient) GetEdgeDictionary(ctx context.Context, version int64, name string) (*EdgeDictionary, error) {
+	endpoint := fmt.Sprintf("/service/%s/version/%d/dictionary/%s", c.serviceId, version, url.PathEscape(name))
+	var dict EdgeDictionary
+	if err := c.request(ctx, endpoint, &dict); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &dict, nil
+}
+
+// BatchUpdateEdgeDictionaryItems creates, updates, or deletes dictionary items.
+// Dictionary items are versionless so changes are applied to the active version immediately.
+func (c *FastlyClient) BatchUpdateEdgeDictionaryItems(
+	ctx context.Context,
+	dictId string,
+	ops []*EdgeDictionaryItemOperation,
+) error {
+	endpoint := fmt.Sprintf("/service/%s/dictionary/%s/items", c.serviceId, dictId)
+	return batchUpdate(ctx, c, endpoint, "items", ops)
+}
+
 func (c *FastlyClient) ListAccessControlLists(ctx context.Context, version int64) ([]*AccessControl, error) {
 	endpoint := fmt.Sprintf("/service/%s/version/%d/acl", c.serviceId, version)
 	var acls []*AccessControl
@@ -166,15 +216,64 @@ func (c *FastlyClient) ListAccessControlLists(ctx context.Context, version int64
 }
 
 func (c *FastlyClient) ListAccessControlEntries(ctx context.Context, aclId string) ([]*AccessControlEntry, error) {
-	endpoint := fmt.Sprintf("/service/%s/acl/%s/entries", c.serviceId, aclId)
 	var entries []*AccessControlEntry
-	if err := c.request(ctx, endpoint, &entries); err != nil {
-		return nil, errors.WithStack(err)
+	// ACL entries API is paginated so fetch all pages
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf(
+			"/service/%s/acl/%s/entries?page=%d&per_page=%d", c.serviceId, aclId, page, fastlyApiPerPage,
+		)
+		var v []*AccessControlEntry
+		if err := c.request(ctx, endpoint, &v); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		entries = append(entries, v...)
+		if len(v) < fastlyApiPerPage {
+			break
+		}
 	}
 
 	return entries, nil
 }
 
+func (c *FastlyClient) GetAccessControl(ctx context.Context, version int64, name string) (*AccessControl, error) {
+	endpoint := fmt.Sprintf("/service/%s/version/%d/acl/%s", c.serviceId, version, url.PathEscape(name))
+	var acl AccessControl
+	if err := c.request(ctx, endpoint, &acl); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &acl, nil
+}
+
+// BatchUpdateAccessControlEntries creates or deletes ACL entries.
+// ACL entries are versionless so changes are applied to the active version immediately.
+func (c *FastlyClient) BatchUpdateAccessControlEntries(
+	ctx context.Context,
+	aclId string,
+	ops []*AccessControlEntryOperation,
+) error {
+	endpoint := fmt.Sprintf("/service/%s/acl/%s/entries", c.serviceId, aclId)
+	return batchUpdate(ctx, c, endpoint, "entries", ops)
+}
+
+// Send batch operations with chunking because batch API accepts limited number of operations per request
+func batchUpdate[T any](ctx context.Context, c *FastlyClient, endpoint, field string, ops []T) error {
+	for i := 0; i < len(ops); i += fastlyApiBatchSize {
+		end := i + fastlyApiBatchSize
+		if end > len(ops) {
+			end = len(ops)
+		}
+		body := map[string][]T{
+			field: ops[i:end],
+		}
+		var resp map[string]interface{}
+		if err := c.do(ctx, http.MethodPatch, endpoint, body, &resp); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
 func (c *FastlyClient) ListBackends(ctx context.Context, version int64) ([]*Backend, error) {
 	endpoint := fmt.Sprintf("/service/%s/version/%d/backend", c.serviceId, version)
 	var backends []*Backend
@@ -185,6 +284,36 @@ func (c *FastlyClient) ListBackends(ctx context.Context, version int64) ([]*Back
 	return backends, nil
 }
 
+func (c *FastlyClient) ListDomains(ctx context.Context, version int64) ([]*Domain, error) {
+	endpoint := fmt.Sprintf("/service/%s/version/%d/domain", c.serviceId, version)
+	var domains []*Domain
+	if err := c.request(ctx, endpoint, &domains); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return domains, nil
+}
+
+func (c *FastlyClient) ListCustomVCLs(ctx context.Context, version int64) ([]*CustomVCL, error) {
+	endpoint := fmt.Sprintf("/service/%s/version/%d/vcl", c.serviceId, version)
+	var vcls []*CustomVCL
+	if err := c.request(ctx, endpoint, &vcls); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return vcls, nil
+}
+
+func (c *FastlyClient) GetSettings(ctx context.Context, version int64) (*Settings, error) {
+	endpoint := fmt.Sprintf("/service/%s/version/%d/settings", c.serviceId, version)
+	var settings Settings
+	if err := c.request(ctx, endpoint, &settings); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &settings, nil
+}
+
 func (c *FastlyClient) ListSnippets(ctx context.Context, version int64) ([]*VCLSnippet, error) {
 	endpoint := fmt.Sprintf("/service/%s/version/%d/snippet", c.serviceId, version)
 	var snippets []*VCLSnippet
@@ -274,10 +403,11 @@ type listLoggingEndpointResponse struct {
 func (c *FastlyClient) ListLoggingEndpoints(ctx context.Context, version int64) ([]string, error) {
 	var endpoints []string
 	var eg errgroup.Group
+	var mu sync.Mutex
 
 	basePath := fmt.Sprintf("/service/%s/version/%d/logging", c.serviceId, version)
 	for i := range fastlyRealtimeLoggingTypes {
-		eg.Go(c.listLoggingEndpoint(ctx, &endpoints, basePath+"/"+fastlyRealtimeLoggingTypes[i]))
+		eg.Go(c.listLoggingEndpoint(ctx, &mu, &endpoints, basePath+"/"+fastlyRealtimeLoggingTypes[i]))
 	}
 
 	if err := eg.Wait(); err != nil {
@@ -286,12 +416,20 @@ func (c *FastlyClient) ListLoggingEndpoints(ctx context.Context, version int64)
 	return endpoints, nil
 }
 
-func (c *FastlyClient) listLoggingEndpoint(ctx context.Context, endpoints *[]string, path string) func() error {
+// Endpoints of each logging type are listed concurrently, so appending to the result is guarded by the mutex
+func (c *FastlyClient) listLoggingEndpoint(
+	ctx context.Context,
+	mu *sync.Mutex,
+	endpoints *[]string,
+	path string,
+) func() error {
 	return func() error {
 		var resp []listLoggingEndpointResponse
 		if err := c.request(ctx, path, &resp); err != nil {
 			return err
 		}
+		mu.Lock()
+		defer mu.Unlock()
 		for i := range resp {
 			*endpoints = append(*endpoints, resp[i].Name)
 		}
@@ -258,3 +258,88 @@ func TestListBackends(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestListDomains(t *testing.T) {
+	c := NewFastlyClient(&http.Client{
+		Transport: &TestRoundTripper{
+			StatusCode: 200,
+			Body: `
+[
+  {
+    "name": "www.example.com",
+    "comment": "example",
+    "service_id": "0yGwmmav8rcXRC7yRwzPNQ",
+    "version": 10
+  }
+]`,
+		},
+	}, "dummy", "dummy")
+
+	domains, err := c.ListDomains(context.Background(), 10)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	if len(domains) != 1 {
+		t.Errorf("domains should have 1 items but got %d", len(domains))
+		t.FailNow()
+	}
+	if domains[0].Name != "www.example.com" {
+		t.Errorf("domain name assertion error, expects=www.example.com but got=%s", domains[0].Name)
+	}
+}
+
+type recordRoundTripper struct {
+	requests []*http.Request
+	bodies   []string
+}
+
+func (t *recordRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.requests = append(t.requests, r)
+	t.bodies = append(t.bodies, string(body))
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"status":"ok"}`)),
+	}, nil
+}
+
+func TestBatchUpdateAccessControlEntries(t *testing.T) {
+	rt := &recordRoundTripper{}
+	c := NewFastlyClient(&http.Client{Transport: rt}, "dummy", "dummy")
+
+	subnet := int64(24)
+	ops := make([]*AccessControlEntryOperation, fastlyApiBatchSize+1)
+	for i := range ops {
+		ops[i] = &AccessControlEntryOperation{Op: "create", Ip: "192.0.2.0", Subnet: &subnet, Negated: "0"}
+	}
+	ops[fastlyApiBatchSize] = &AccessControlEntryOperation{Op: "delete", Id: "6yxNzlOpW1V7JfSwvLGtOc"}
+
+	if err := c.BatchUpdateAccessControlEntries(context.Background(), "acl_id", ops); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	if len(rt.requests) != 2 {
+		t.Errorf("operations should be sent with 2 requests but got %d", len(rt.requests))
+		t.FailNow()
+	}
+	for _, r := range rt.requests {
+		if r.Method != http.MethodPatch {
+			t.Errorf("method assertion error, expects=PATCH but got=%s", r.Method)
+		}
+		if r.URL.Path != "/service/dummy/acl/acl_id/entries" {
+			t.Errorf("path assertion error, got=%s", r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type header should be application/json")
+		}
+	}
+	expect := `{"entries":[{"op":"delete","id":"6yxNzlOpW1V7JfSwvLGtOc"}]}`
+	if strings.TrimSpace(rt.bodies[1]) != expect {
+		t.Errorf("body assertion error, expects=%s but got=%s", expect, rt.bodies[1])
+	}
+}
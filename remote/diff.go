@@ -0,0 +1,35 @@
+package remote
+
+import (
+	"sort"
+)
+
+// DiffEdgeDictionaryItems calculates operations to make remote dictionary items equal to desired key-value pairs.
+// Operations are sorted by item key in order to display and apply changes deterministically.
+func DiffEdgeDictionaryItems(desired map[string]string, items []*EdgeDictionaryItem) []*EdgeDictionaryItemOperation {
+	current := make(map[string]string, len(items))
+	for _, item := range items {
+		current[item.Key] = item.Value
+	}
+
+	var ops []*EdgeDictionaryItemOperation
+	for key, value := range desired {
+		v, ok := current[key]
+		switch {
+		case !ok:
+			ops = append(ops, &EdgeDictionaryItemOperation{Op: "create", Key: key, Value: value})
+		case v != value:
+			ops = append(ops, &EdgeDictionaryItemOperation{Op: "update", Key: key, Value: value})
+		}
+	}
+	for key := range current {
+		if _, ok := desired[key]; !ok {
+			ops = append(ops, &EdgeDictionaryItemOperation{Op: "delete", Key: key})
+		}
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Key < ops[j].Key
+	})
+	return ops
+}
@@ -0,0 +1,31 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffEdgeDictionaryItems(t *testing.T) {
+	desired := map[string]string{
+		"keep":   "value",
+		"update": "new",
+		"create": "value",
+		"empty":  "",
+	}
+	items := []*EdgeDictionaryItem{
+		{Key: "keep", Value: "value"},
+		{Key: "update", Value: "old"},
+		{Key: "delete", Value: "value"},
+		{Key: "empty", Value: ""},
+	}
+
+	expect := []*EdgeDictionaryItemOperation{
+		{Op: "create", Key: "create", Value: "value"},
+		{Op: "delete", Key: "delete"},
+		{Op: "update", Key: "update", Value: "new"},
+	}
+	if diff := cmp.Diff(expect, DiffEdgeDictionaryItems(desired, items)); diff != "" {
+		t.Errorf("Diff operations mismatch, diff=%s", diff)
+	}
+}
@@ -16,6 +16,13 @@ type EdgeDictionaryItem struct {
 	Value string `json:"item_value"`
 }
 
+// Operation for batch updating dictionary items, Op is "create", "update", "upsert" or "delete"
+type EdgeDictionaryItemOperation struct {
+	Op    string `json:"op"`
+	Key   string `json:"item_key"`
+	Value string `json:"item_value"`
+}
+
 type AccessControl struct {
 	Id      string `json:"id"`
 	Name    string `json:"name"`
@@ -23,18 +30,34 @@ type AccessControl struct {
 }
 
 type AccessControlEntry struct {
+	Id      string `json:"id"`
 	Ip      string `json:"ip"`
 	Negated string `json:"negated"`
 	Subnet  *int64 `json:"subnet"`
 	Comment string `json:"comment"`
 }
 
+// Operation for batch updating ACL entries, Op is "create" or "delete"
+type AccessControlEntryOperation struct {
+	Op      string `json:"op"`
+	Id      string `json:"id,omitempty"`
+	Ip      string `json:"ip,omitempty"`
+	Subnet  *int64 `json:"subnet,omitempty"`
+	Negated string `json:"negated,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
 type Backend struct {
 	Name    string  `json:"name"`
 	Shield  *string `json:"shield"`
 	Address *string `json:"address"`
 }
 
+type Domain struct {
+	Name    string `json:"name"`
+	Comment string `json:"comment"`
+}
+
 type DirectorType int8
 
 const (
@@ -58,3 +81,17 @@ type VCLSnippet struct {
 	Priority string  `json:"priority"`
 	Content  *string `json:"content"`
 }
+
+type CustomVCL struct {
+	Name    string `json:"name"`
+	Main    bool   `json:"main"`
+	Content string `json:"content"`
+}
+
+// Settings of the service version, Fastly API names fields with "general." prefix
+type Settings struct {
+	DefaultHost     string `json:"general.default_host"`
+	DefaultTTL      int64  `json:"general.default_ttl"`
+	StaleIfError    bool   `json:"general.stale_if_error"`
+	StaleIfErrorTTL int64  `json:"general.stale_if_error_ttl"`
+}
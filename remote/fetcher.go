@@ -149,3 +149,25 @@ func (f *FastlyApiFetcher) LoggingEndpoints() ([]string, error) {
 
 	return f.client.ListLoggingEndpoints(c, version)
 }
+
+// Domains returns domain names which are attached to the service
+func (f *FastlyApiFetcher) Domains() ([]string, error) {
+	c, timeout := context.WithTimeout(_context.Background(), f.timeout)
+	defer timeout()
+
+	version, err := f.getVersion(c)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get latest version %w", err)
+	}
+
+	domains, err := f.client.ListDomains(c, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var r []string
+	for _, d := range domains {
+		r = append(r, d.Name)
+	}
+	return r, nil
+}
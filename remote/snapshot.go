@@ -0,0 +1,131 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/types"
+)
+
+// Snapshot is remote resources of the service version which are written by import command
+type Snapshot struct {
+	ServiceId        string              `json:"service_id"`
+	Version          int64               `json:"version"`
+	Settings         *Settings           `json:"settings,omitempty"`
+	Domains          []string            `json:"domains"`
+	LoggingEndpoints []string            `json:"logging_endpoints"`
+	Backends         []*SnapshotBackend  `json:"backends"`
+	Dictionaries     []*SnapshotResource `json:"dictionaries"`
+	Acls             []*SnapshotResource `json:"acls"`
+	Snippets         []*SnapshotSnippet  `json:"snippets"`
+}
+
+type SnapshotBackend struct {
+	Name    string  `json:"name"`
+	Address *string `json:"address,omitempty"`
+	Shield  *string `json:"shield,omitempty"`
+}
+
+// Edge dictionary or ACL, File is the data file which has items or entries.
+// Private dictionary does not have the file because its items could not be read.
+type SnapshotResource struct {
+	Name    string `json:"name"`
+	File    string `json:"file,omitempty"`
+	Private bool   `json:"private,omitempty"`
+}
+
+// VCL snippet, content is stored in File
+type SnapshotSnippet struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Priority int64  `json:"priority"`
+	Dynamic  bool   `json:"dynamic,omitempty"`
+	File     string `json:"file"`
+}
+
+// SnapshotFetcher reads remote resources from the snapshot instead of communicating with Fastly API,
+// so the imported service could be linted as well as the remote option is provided.
+type SnapshotFetcher struct {
+	snapshot *Snapshot
+	// Directory of the snapshot file, files of resources are relative from it
+	dir string
+}
+
+// NewSnapshotFetcher reads snapshot JSON file
+func NewSnapshotFetcher(file string) (*SnapshotFetcher, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return nil, fmt.Errorf("Failed to parse snapshot file %s: %w", file, err)
+	}
+	return &SnapshotFetcher{
+		snapshot: &s,
+		dir:      filepath.Dir(file),
+	}, nil
+}
+
+func (f *SnapshotFetcher) Backends() ([]*types.RemoteBackend, error) {
+	r := []*types.RemoteBackend{}
+	for _, b := range f.snapshot.Backends {
+		r = append(r, &types.RemoteBackend{
+			Name:    b.Name,
+			Shield:  b.Shield,
+			Address: b.Address,
+		})
+	}
+	return r, nil
+}
+
+// Dictionaries returns only names as well as FastlyApiFetcher does, items are used by the simulator via data files
+func (f *SnapshotFetcher) Dictionaries() ([]*types.RemoteDictionary, error) {
+	r := []*types.RemoteDictionary{}
+	for _, d := range f.snapshot.Dictionaries {
+		r = append(r, &types.RemoteDictionary{
+			Name: d.Name,
+		})
+	}
+	return r, nil
+}
+
+// Acls returns only names as well as FastlyApiFetcher does, entries are used by the simulator via data files
+func (f *SnapshotFetcher) Acls() ([]*types.RemoteAcl, error) {
+	r := []*types.RemoteAcl{}
+	for _, a := range f.snapshot.Acls {
+		r = append(r, &types.RemoteAcl{
+			Name: a.Name,
+		})
+	}
+	return r, nil
+}
+
+func (f *SnapshotFetcher) Snippets() ([]*types.RemoteVCL, error) {
+	var r []*types.RemoteVCL
+	for _, v := range f.snapshot.Snippets {
+		content, err := os.ReadFile(filepath.Join(f.dir, v.File))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read VCL snippet %s: %w", v.Name, err)
+		}
+		r = append(r, &types.RemoteVCL{
+			Name:     v.Name,
+			Type:     v.Type,
+			Content:  string(content),
+			Priority: v.Priority,
+		})
+	}
+	return r, nil
+}
+
+func (f *SnapshotFetcher) LoggingEndpoints() ([]string, error) {
+	return f.snapshot.LoggingEndpoints, nil
+}
+
+// Domains returns domain names which are attached to the service
+func (f *SnapshotFetcher) Domains() ([]string, error) {
+	return f.snapshot.Domains, nil
+}
@@ -0,0 +1,31 @@
+package routing
+
+import (
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/snippets"
+)
+
+type Option func(a *Analyzer)
+
+// WithResolver resolves include statements, included modules are not analyzed without resolver
+func WithResolver(rslv resolver.Resolver) Option {
+	return func(a *Analyzer) {
+		a.resolver = rslv
+	}
+}
+
+// WithSnippets resolves "snippet::" include statements by Fastly managed snippets
+func WithSnippets(s *snippets.Snippets) Option {
+	return func(a *Analyzer) {
+		a.snippets = s
+	}
+}
+
+// WithMaxRoutes limits the number of routes, rest of paths are not analyzed when exceeded
+func WithMaxRoutes(n int) Option {
+	return func(a *Analyzer) {
+		if n > 0 {
+			a.maxRoutes = n
+		}
+	}
+}
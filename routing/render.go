@@ -0,0 +1,109 @@
+package routing
+
+import (
+	"encoding/csv"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Conditions of the route which is always taken
+const alwaysCondition = "(always)"
+
+// BackendName returns the backend name, "(default)" is suffixed when the backend is not set on the route
+func (r *Route) BackendName() string {
+	if r.Backend == "" {
+		return "(none)"
+	}
+	if r.DefaultBackend {
+		return r.Backend + " (default)"
+	}
+	return r.Backend
+}
+
+// ConditionStrings returns stringified conditions, the route is always taken when conditions are empty
+func (r *Route) ConditionStrings() []string {
+	if len(r.Conditions) == 0 {
+		return []string{alwaysCondition}
+	}
+	conditions := make([]string, len(r.Conditions))
+	for i := range r.Conditions {
+		conditions[i] = r.Conditions[i].String()
+	}
+	return conditions
+}
+
+// WriteCSV writes the table as CSV, conditions of the route are joined with AND
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{
+		{"#", "Conditions", "Backend", "Action", "Location"},
+	}
+	for i, r := range t.Routes {
+		records = append(records, []string{
+			strconv.Itoa(i + 1),
+			strings.Join(r.ConditionStrings(), " AND "),
+			r.BackendName(),
+			r.Action,
+			r.Location(),
+		})
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+var htmlTemplate = template.Must(template.New("routes").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+code { white-space: pre-wrap; }
+ul { margin: 0; padding-left: 1.2em; }
+.note { color: #b05000; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- if .Table.Truncated }}
+<p class="note">Routes are truncated because the number of paths exceeds the limit.</p>
+{{- end }}
+<table>
+<thead>
+<tr><th>#</th><th>Conditions</th><th>Backend</th><th>Action</th><th>Location</th></tr>
+</thead>
+<tbody>
+{{- range $i, $r := .Table.Routes }}
+<tr>
+<td>{{ inc $i }}</td>
+<td><ul>{{ range $r.ConditionStrings }}<li><code>{{ . }}</code></li>{{ end }}</ul></td>
+<td>{{ $r.BackendName }}</td>
+<td>{{ $r.Action }}</td>
+<td>{{ $r.Location }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML writes the table as standalone HTML document
+func (t *Table) WriteHTML(w io.Writer, title string) error {
+	err := htmlTemplate.Execute(w, map[string]interface{}{
+		"Title": title,
+		"Table": t,
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,406 @@
+package routing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/resolver"
+	"github.com/ysugimoto/falco/snippets"
+)
+
+const defaultMaxRoutes = 1000
+
+// Action of the route when vcl_recv reaches the end without return statement
+const defaultAction = "lookup"
+
+var errTruncated = errors.New("Routes are truncated")
+
+// Condition is a branch condition which the route passes through
+type Condition struct {
+	Expression string `json:"expression"`
+	// True when the route passes through the else side of the condition
+	Negated bool `json:"negated"`
+}
+
+func (c *Condition) String() string {
+	if c.Negated {
+		return "NOT (" + c.Expression + ")"
+	}
+	return c.Expression
+}
+
+// Route is a distinct outcome of vcl_recv with the conditions which lead to it
+type Route struct {
+	Conditions []*Condition `json:"conditions"`
+	Backend    string       `json:"backend"`
+	// True when the backend is not set on the route and the first declared backend is used
+	DefaultBackend bool   `json:"default_backend"`
+	Action         string `json:"action"`
+	// Position of the statement which decides the action, empty when vcl_recv reaches the end
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// Location returns the position of the statement which decides the action
+func (r *Route) Location() string {
+	if r.Line == 0 {
+		return "end of vcl_recv"
+	}
+	return fmt.Sprintf("%s:%d", r.File, r.Line)
+}
+
+// Table is a decision table of routing logic in vcl_recv
+type Table struct {
+	// Main VCL file name, set by the caller
+	Main   string   `json:"main,omitempty"`
+	Routes []*Route `json:"routes"`
+	// True when routes exceed the limit and rest of paths are not analyzed
+	Truncated bool `json:"truncated"`
+}
+
+// Analyzer enumerates paths of vcl_recv and collects the backend and action of each path.
+// Statements in called subroutines and included modules are followed as the interpreter does.
+type Analyzer struct {
+	resolver  resolver.Resolver
+	snippets  *snippets.Snippets
+	maxRoutes int
+
+	subroutines    map[string]*ast.SubroutineDeclaration
+	defaultBackend string
+	table          *Table
+}
+
+func New(opts ...Option) *Analyzer {
+	a := &Analyzer{
+		maxRoutes: defaultMaxRoutes,
+	}
+	for i := range opts {
+		opts[i](a)
+	}
+	return a
+}
+
+// State of the path on walking statements
+type path struct {
+	conditions []*Condition
+	backend    string
+	// Call stack of subroutines to prevent infinite recursion
+	calls []string
+}
+
+// Copy the path with additional condition, returns false when the condition contradicts the path
+func (p path) assume(c *Condition) (path, bool) {
+	switch {
+	case c.Expression == "true" && c.Negated, c.Expression == "false" && !c.Negated:
+		return p, false
+	case c.Expression == "true" || c.Expression == "false":
+		return p, true
+	}
+	for _, v := range p.conditions {
+		if v.Expression != c.Expression {
+			continue
+		}
+		if v.Negated != c.Negated {
+			return p, false
+		}
+		// Same condition is already assumed
+		return p, true
+	}
+	p.conditions = append(p.conditions[:len(p.conditions):len(p.conditions)], c)
+	return p, true
+}
+
+// Analyze builds the decision table from vcl_recv of the VCL
+func (a *Analyzer) Analyze(vcl *ast.VCL) (*Table, error) {
+	a.subroutines = make(map[string]*ast.SubroutineDeclaration)
+	a.defaultBackend = ""
+	a.table = &Table{Routes: []*Route{}}
+
+	statements, err := a.resolveIncludes(vcl.Statements, true)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, stmt := range statements {
+		switch t := stmt.(type) {
+		case *ast.BackendDeclaration:
+			if a.defaultBackend == "" {
+				a.defaultBackend = t.Name.Value
+			}
+		case *ast.SubroutineDeclaration:
+			// Functional subroutine could not be called by call statement
+			if t.ReturnType == nil {
+				a.subroutines[t.Name.Value] = t
+			}
+		}
+	}
+
+	// Service without vcl_recv always looks up the cache
+	var recv []ast.Statement
+	if sub, ok := a.subroutines["vcl_recv"]; ok {
+		recv = sub.Block.Statements
+	}
+	err = a.walk(recv, path{}, func(p path) error {
+		return a.emit(p, defaultAction, nil)
+	})
+	if err != nil && err != errTruncated {
+		return nil, errors.WithStack(err)
+	}
+	return a.table, nil
+}
+
+func (a *Analyzer) emit(p path, action string, node ast.Node) error {
+	if len(a.table.Routes) >= a.maxRoutes {
+		a.table.Truncated = true
+		return errTruncated
+	}
+	route := &Route{
+		Conditions: p.conditions,
+		Backend:    p.backend,
+		Action:     action,
+	}
+	if route.Conditions == nil {
+		route.Conditions = []*Condition{}
+	}
+	if route.Backend == "" {
+		route.Backend = a.defaultBackend
+		route.DefaultBackend = true
+	}
+	if node != nil {
+		token := node.GetMeta().Token
+		route.File = token.File
+		route.Line = token.Line
+	}
+	a.table.Routes = append(a.table.Routes, route)
+	return nil
+}
+
+// Walk statements in order, next is called when statements reach the end or bare return statement.
+// Branches are walked with the rest of statements so that each path is walked to the terminal action.
+func (a *Analyzer) walk(statements []ast.Statement, p path, next func(path) error) error {
+	for i, stmt := range statements {
+		rest := statements[i+1:]
+
+		switch t := stmt.(type) {
+		case *ast.IncludeStatement:
+			included, err := a.resolveIncludes([]ast.Statement{t}, false)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return a.walk(concat(included, rest), p, next)
+
+		case *ast.BlockStatement:
+			return a.walk(concat(t.Statements, rest), p, next)
+
+		case *ast.SetStatement:
+			if strings.EqualFold(t.Ident.Value, "req.backend") {
+				p.backend = t.Value.String()
+			}
+
+		case *ast.IfStatement:
+			return a.walkIfStatement(t, rest, p, next)
+
+		case *ast.SwitchStatement:
+			return a.walkSwitchStatement(t, rest, p, next)
+
+		case *ast.CallStatement:
+			name := t.Subroutine.Value
+			sub, ok := a.subroutines[name]
+			if !ok || contains(p.calls, name) {
+				continue
+			}
+			caller := p.calls
+			p.calls = append(caller[:len(caller):len(caller)], name)
+			return a.walk(sub.Block.Statements, p, func(returned path) error {
+				returned.calls = caller
+				return a.walk(rest, returned, next)
+			})
+
+		case *ast.GotoStatement:
+			for j, v := range rest {
+				if d, ok := v.(*ast.GotoDestinationStatement); ok && d.Name.Value == t.Destination.Value+":" {
+					return a.walk(rest[j+1:], p, next)
+				}
+			}
+
+		case *ast.ReturnStatement:
+			if t.ReturnExpression == nil {
+				return next(p)
+			}
+			return a.emit(p, returnAction(*t.ReturnExpression), t)
+
+		case *ast.ErrorStatement:
+			action := "error"
+			if t.Code != nil {
+				action += " " + t.Code.String()
+			}
+			if t.Argument != nil {
+				action += " " + t.Argument.String()
+			}
+			return a.emit(p, action, t)
+
+		case *ast.RestartStatement:
+			return a.emit(p, "restart", t)
+		}
+	}
+	return next(p)
+}
+
+func (a *Analyzer) walkIfStatement(stmt *ast.IfStatement, rest []ast.Statement, p path, next func(path) error) error {
+	branches := append([]*ast.IfStatement{stmt}, stmt.Another...)
+
+	// Conditions of the former branches are negated on the latter branches
+	negated := p
+	for _, b := range branches {
+		expr := condition(b.Condition)
+		if taken, ok := negated.assume(&Condition{Expression: expr}); ok {
+			if err := a.walk(concat(b.Consequence.Statements, rest), taken, next); err != nil {
+				return err
+			}
+		}
+		var ok bool
+		if negated, ok = negated.assume(&Condition{Expression: expr, Negated: true}); !ok {
+			return nil
+		}
+	}
+
+	var statements []ast.Statement
+	if stmt.Alternative != nil {
+		statements = stmt.Alternative.Statements
+	}
+	return a.walk(concat(statements, rest), negated, next)
+}
+
+// Cases are walked like else-if chain, and the default case is taken when no cases match
+func (a *Analyzer) walkSwitchStatement(stmt *ast.SwitchStatement, rest []ast.Statement, p path, next func(path) error) error {
+	negated := p
+	for i, c := range stmt.Cases {
+		if c.IsDefault() {
+			continue
+		}
+		operator := "=="
+		if c.Operator == "~" {
+			operator = "~"
+		}
+		expr := stmt.Control.String() + " " + operator + " " + c.Value.String()
+		if taken, ok := negated.assume(&Condition{Expression: expr}); ok {
+			if err := a.walk(concat(caseStatements(stmt, i), rest), taken, next); err != nil {
+				return err
+			}
+		}
+		var ok bool
+		if negated, ok = negated.assume(&Condition{Expression: expr, Negated: true}); !ok {
+			return nil
+		}
+	}
+
+	var statements []ast.Statement
+	if stmt.Default >= 0 {
+		statements = caseStatements(stmt, stmt.Default)
+	}
+	return a.walk(concat(statements, rest), negated, next)
+}
+
+// Statements which are executed from the matched case, following cases are also executed while the case falls through
+func caseStatements(stmt *ast.SwitchStatement, index int) []ast.Statement {
+	var statements []ast.Statement
+	for _, c := range stmt.Cases[index:] {
+		statements = append(statements, c.Statements...)
+		if !c.IsFallthrough() {
+			break
+		}
+	}
+	return statements
+}
+
+// Resolve include statements recursively, nested include statements in modules are also resolved
+func (a *Analyzer) resolveIncludes(statements []ast.Statement, isRoot bool) ([]ast.Statement, error) {
+	var resolved []ast.Statement
+	for _, stmt := range statements {
+		include, ok := stmt.(*ast.IncludeStatement)
+		if !ok {
+			resolved = append(resolved, stmt)
+			continue
+		}
+
+		var name, data string
+		if strings.HasPrefix(include.Module.Value, "snippet::") {
+			if a.snippets == nil {
+				continue
+			}
+			snip, ok := a.snippets.IncludeSnippets[strings.TrimPrefix(include.Module.Value, "snippet::")]
+			if !ok {
+				return nil, fmt.Errorf("Failed to include VCL snippets '%s'", include.Module.Value)
+			}
+			name, data = include.Module.Value, snip.Data
+		} else {
+			if a.resolver == nil {
+				continue
+			}
+			module, err := a.resolver.Resolve(include)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to include VCL module '%s'", include.Module.Value)
+			}
+			name, data = module.Name, module.Data
+		}
+
+		p := parser.New(lexer.NewFromString(data, lexer.WithFile(name)))
+		var included []ast.Statement
+		if isRoot {
+			vcl, err := p.ParseVCL()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			included = vcl.Statements
+		} else {
+			var err error
+			if included, err = p.ParseSnippetVCL(); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+
+		recursive, err := a.resolveIncludes(included, isRoot)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, recursive...)
+	}
+	return resolved, nil
+}
+
+func returnAction(expr ast.Expression) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Value
+	case *ast.GroupedExpression:
+		return returnAction(t.Right)
+	}
+	return expr.String()
+}
+
+// Stringify the condition without outermost parenthesis
+func condition(expr ast.Expression) string {
+	v := expr.String()
+	switch expr.(type) {
+	case *ast.InfixExpression, *ast.PrefixExpression, *ast.GroupedExpression:
+		// Whole expression is wrapped by the parenthesis
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+func concat(a, b []ast.Statement) []ast.Statement {
+	return append(a[:len(a):len(a)], b...)
+}
+
+func contains(list []string, v string) bool {
+	for i := range list {
+		if list[i] == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package routing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+)
+
+func analyze(t *testing.T, input string, opts ...Option) *Table {
+	vcl, err := parser.New(lexer.NewFromString(input, lexer.WithFile("main.vcl"))).ParseVCL()
+	if err != nil {
+		t.Errorf("Unexpected parser error: %s", err)
+		t.FailNow()
+	}
+	table, err := New(opts...).Analyze(vcl)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		t.FailNow()
+	}
+	return table
+}
+
+func cond(expr string, negated bool) *Condition {
+	return &Condition{Expression: expr, Negated: negated}
+}
+
+func TestAnalyze(t *testing.T) {
+	input := `
+backend F_origin {}
+backend F_api {}
+
+sub set_api {
+  set req.backend = F_api;
+  if (req.request != "GET") {
+    return(pass);
+  }
+}
+
+sub vcl_recv {
+  #FASTLY RECV
+  if (req.url ~ "^/api/") {
+    call set_api;
+  } else if (req.url == "/health") {
+    error 200 "OK";
+  } else {
+    if (req.url ~ "^/api/") {
+      restart;
+    }
+  }
+  return(lookup);
+}`
+
+	table := analyze(t, input)
+	expect := &Table{
+		Routes: []*Route{
+			{
+				Conditions: []*Condition{cond(`req.url ~ "^/api/"`, false), cond(`req.request != "GET"`, false)},
+				Backend:    "F_api",
+				Action:     "pass",
+				File:       "main.vcl",
+				Line:       8,
+			},
+			{
+				Conditions: []*Condition{cond(`req.url ~ "^/api/"`, false), cond(`req.request != "GET"`, true)},
+				Backend:    "F_api",
+				Action:     "lookup",
+				File:       "main.vcl",
+				Line:       23,
+			},
+			{
+				Conditions:     []*Condition{cond(`req.url ~ "^/api/"`, true), cond(`req.url == "/health"`, false)},
+				Backend:        "F_origin",
+				DefaultBackend: true,
+				Action:         `error 200 "OK"`,
+				File:           "main.vcl",
+				Line:           17,
+			},
+			// Nested condition contradicts the outer condition so restart is never reached
+			{
+				Conditions:     []*Condition{cond(`req.url ~ "^/api/"`, true), cond(`req.url == "/health"`, true)},
+				Backend:        "F_origin",
+				DefaultBackend: true,
+				Action:         "lookup",
+				File:           "main.vcl",
+				Line:           23,
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, table); diff != "" {
+		t.Errorf("Table mismatch, diff=%s", diff)
+	}
+}
+
+func TestAnalyzeDefaultAction(t *testing.T) {
+	t.Run("vcl_recv reaches the end", func(t *testing.T) {
+		table := analyze(t, `
+sub vcl_recv {
+  if (req.http.Cookie) {
+    set req.backend = F_app;
+  }
+}`)
+		if len(table.Routes) != 2 {
+			t.Errorf("Expects 2 routes but got %d", len(table.Routes))
+			return
+		}
+		for _, r := range table.Routes {
+			if r.Action != "lookup" || r.Location() != "end of vcl_recv" {
+				t.Errorf("Route should look up by default, got %s at %s", r.Action, r.Location())
+			}
+		}
+	})
+
+	t.Run("vcl_recv is not declared", func(t *testing.T) {
+		table := analyze(t, `backend F_origin {}`)
+		expect := []*Route{
+			{Conditions: []*Condition{}, Backend: "F_origin", DefaultBackend: true, Action: "lookup"},
+		}
+		if diff := cmp.Diff(expect, table.Routes); diff != "" {
+			t.Errorf("Routes mismatch, diff=%s", diff)
+		}
+	})
+}
+
+func TestAnalyzeSwitchStatement(t *testing.T) {
+	table := analyze(t, `
+backend F_origin {}
+backend F_api {}
+
+sub vcl_recv {
+  #FASTLY RECV
+  switch (req.http.Host) {
+  case ~ "^api\.":
+    set req.backend = F_api;
+    fallthrough;
+  case "example.com":
+    return(pass);
+  default:
+    error 404;
+  }
+}`)
+	expect := []*Route{
+		{
+			Conditions: []*Condition{cond(`req.http.Host ~ "^api\."`, false)},
+			Backend:    "F_api",
+			Action:     "pass",
+			File:       "main.vcl",
+			Line:       12,
+		},
+		{
+			Conditions:     []*Condition{cond(`req.http.Host ~ "^api\."`, true), cond(`req.http.Host == "example.com"`, false)},
+			Backend:        "F_origin",
+			DefaultBackend: true,
+			Action:         "pass",
+			File:           "main.vcl",
+			Line:           12,
+		},
+		{
+			Conditions:     []*Condition{cond(`req.http.Host ~ "^api\."`, true), cond(`req.http.Host == "example.com"`, true)},
+			Backend:        "F_origin",
+			DefaultBackend: true,
+			Action:         "error 404",
+			File:           "main.vcl",
+			Line:           14,
+		},
+	}
+	if diff := cmp.Diff(expect, table.Routes); diff != "" {
+		t.Errorf("Routes mismatch, diff=%s", diff)
+	}
+}
+
+func TestAnalyzeMaxRoutes(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("sub vcl_recv {\n")
+	for i := 0; i < 10; i++ {
+		buf.WriteString("  if (req.http.X-Flag-" + string(rune('A'+i)) + ") { set req.http.Foo = \"1\"; }\n")
+	}
+	buf.WriteString("}\n")
+
+	table := analyze(t, buf.String(), WithMaxRoutes(100))
+	if len(table.Routes) != 100 || !table.Truncated {
+		t.Errorf("Routes should be truncated to 100, got %d, truncated=%t", len(table.Routes), table.Truncated)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	table := analyze(t, `
+backend F_origin {}
+sub vcl_recv {
+  if (req.url ~ "^/admin") {
+    error 403;
+  }
+}`)
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	expect := strings.Join([]string{
+		"#,Conditions,Backend,Action,Location",
+		`1,"req.url ~ ""^/admin""",F_origin (default),error 403,main.vcl:5`,
+		`2,"NOT (req.url ~ ""^/admin"")",F_origin (default),lookup,end of vcl_recv`,
+	}, "\n") + "\n"
+	if diff := cmp.Diff(expect, buf.String()); diff != "" {
+		t.Errorf("CSV mismatch, diff=%s", diff)
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	table := analyze(t, `
+sub vcl_recv {
+  if (req.http.X-Debug == "<script>") {
+    return(pass);
+  }
+}`)
+	var buf bytes.Buffer
+	if err := table.WriteHTML(&buf, "main.vcl"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if strings.Contains(buf.String(), "<script>") {
+		t.Errorf("Conditions must be escaped in HTML")
+	}
+	if !strings.Contains(buf.String(), "<td>pass</td>") {
+		t.Errorf("Action should be rendered")
+	}
+}
@@ -201,10 +201,8 @@ func fetchVCLSnippets(fetcher Fetcher) (
 		return nil, nil, fmt.Errorf("Failed to get VCL snippets: %w", err)
 	}
 
-	// Sort by priority
-	sort.Slice(snippets, func(i, j int) bool {
-		return snippets[i].Priority > snippets[j].Priority
-	})
+	// Fastly renders snippets in ascending order of priority, lower number runs first
+	sortSnippets(snippets)
 
 	scoped := make(map[string][]SnippetItem)
 	include := make(map[string]SnippetItem)
@@ -212,8 +210,9 @@ func fetchVCLSnippets(fetcher Fetcher) (
 		// "none" type means that user could include the snippet arbitrary
 		if snip.Type == "none" {
 			include[snip.Name] = SnippetItem{
-				Name: snip.Name,
-				Data: snip.Content,
+				Name:     snip.Name,
+				Data:     snip.Content,
+				Priority: snip.Priority,
 			}
 			continue
 		}
@@ -222,10 +221,22 @@ func fetchVCLSnippets(fetcher Fetcher) (
 			scoped[snip.Type] = []SnippetItem{}
 		}
 		scoped[snip.Type] = append(scoped[snip.Type], SnippetItem{
-			Name: snip.Name,
-			Data: snip.Content,
+			Name:     snip.Name,
+			Data:     snip.Content,
+			Priority: snip.Priority,
 		})
 	}
 
 	return scoped, include, nil
 }
+
+// Sort snippets by priority, snippets which have the same priority are sorted by name
+// in order to render the same VCL regardless of API response order.
+func sortSnippets(snippets []*types.RemoteVCL) {
+	sort.SliceStable(snippets, func(i, j int) bool {
+		if snippets[i].Priority != snippets[j].Priority {
+			return snippets[i].Priority < snippets[j].Priority
+		}
+		return snippets[i].Name < snippets[j].Name
+	})
+}
@@ -0,0 +1,120 @@
+package snippets
+
+import (
+	"strings"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/token"
+)
+
+// MacroPhrase returns Fastly boilerplate macro phrase for the phase like "FASTLY RECV"
+func MacroPhrase(phase string) string {
+	return strings.ToUpper("FASTLY " + phase)
+}
+
+// HasMacro returns true when the comment text contains the boilerplate macro phrase
+func HasMacro(comments, phrase string) bool {
+	for _, c := range strings.Split(comments, "\n") {
+		c = strings.TrimLeft(c, " */#")
+		if strings.HasPrefix(strings.ToUpper(c), phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find comment token which contains the boilerplate macro phrase
+func macroToken(comments ast.Comments, phrase string) (token.Token, bool) {
+	for _, c := range comments {
+		if HasMacro(c.Value, phrase) {
+			return c.Token, true
+		}
+	}
+	return token.Null, false
+}
+
+// InjectMacro inserts snippet statements at the place of Fastly boilerplate macro in the block.
+//
+// Fastly replaces the first macro comment with snippets textually on generating VCL,
+// so snippets are inserted at exactly the same position in the AST:
+//   - macro in leading comment of the statement: snippets are inserted before the statement
+//   - macro in trailing comment of the statement: snippets are inserted after the statement
+//   - macro in the end of the block: snippets are appended to the block
+//
+// Macro is also found in nested blocks like if statement. Load function is called only when the macro is found.
+// Returns the comment token of the macro and true if the macro is found.
+func InjectMacro(
+	block *ast.BlockStatement,
+	phrase string,
+	load func() ([]ast.Statement, error),
+) (token.Token, bool, error) {
+
+	site, found := findMacroSite(block, phrase)
+	if !found {
+		return token.Null, false, nil
+	}
+	statements, err := load()
+	if err != nil {
+		return token.Null, false, err
+	}
+	site.inject(statements)
+	return site.token, true, nil
+}
+
+// Place of the macro which snippets are injected at
+type macroSite struct {
+	block *ast.BlockStatement
+	// Insert position of block statements
+	index int
+	token token.Token
+}
+
+func (s *macroSite) inject(statements []ast.Statement) {
+	var injected []ast.Statement
+	injected = append(injected, s.block.Statements[:s.index]...)
+	injected = append(injected, statements...)
+	injected = append(injected, s.block.Statements[s.index:]...)
+	s.block.Statements = injected
+}
+
+// Find the first macro in source order
+func findMacroSite(block *ast.BlockStatement, phrase string) (*macroSite, bool) {
+	if block == nil {
+		return nil, false
+	}
+
+	for i, stmt := range block.Statements {
+		if t, ok := macroToken(stmt.GetMeta().Leading, phrase); ok {
+			return &macroSite{block: block, index: i, token: t}, true
+		}
+		for _, nested := range nestedBlocks(stmt) {
+			if site, ok := findMacroSite(nested, phrase); ok {
+				return site, true
+			}
+		}
+		if t, ok := macroToken(stmt.GetMeta().Trailing, phrase); ok {
+			return &macroSite{block: block, index: i + 1, token: t}, true
+		}
+	}
+	if t, ok := macroToken(block.Infix, phrase); ok {
+		return &macroSite{block: block, index: len(block.Statements), token: t}, true
+	}
+	return nil, false
+}
+
+func nestedBlocks(stmt ast.Statement) []*ast.BlockStatement {
+	switch t := stmt.(type) {
+	case *ast.BlockStatement:
+		return []*ast.BlockStatement{t}
+	case *ast.IfStatement:
+		blocks := []*ast.BlockStatement{t.Consequence}
+		for _, a := range t.Another {
+			blocks = append(blocks, a.Consequence)
+		}
+		if t.Alternative != nil {
+			blocks = append(blocks, t.Alternative)
+		}
+		return blocks
+	}
+	return nil
+}
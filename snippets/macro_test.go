@@ -0,0 +1,233 @@
+package snippets
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/lexer"
+	"github.com/ysugimoto/falco/parser"
+	"github.com/ysugimoto/falco/types"
+)
+
+func parseSubroutine(t *testing.T, input string) *ast.SubroutineDeclaration {
+	vcl, err := parser.New(lexer.NewFromString(input)).ParseVCL()
+	if err != nil {
+		t.Fatalf("Unexpected parser error: %s", err)
+	}
+	return vcl.Statements[0].(*ast.SubroutineDeclaration)
+}
+
+// Flatten statements in the block without comments to compare the order
+func flatten(block *ast.BlockStatement) []string {
+	var lines []string
+	for _, stmt := range block.Statements {
+		switch t := stmt.(type) {
+		case *ast.IfStatement:
+			lines = append(lines, "if ("+t.Condition.String()+") {")
+			lines = append(lines, flatten(t.Consequence)...)
+			lines = append(lines, "}")
+		default:
+			lines = append(lines, stripComments(t.String()))
+		}
+	}
+	return lines
+}
+
+func injectSnippet(t *testing.T, block *ast.BlockStatement) (bool, int) {
+	var called int
+	_, found, err := InjectMacro(block, MacroPhrase("recv"), func() ([]ast.Statement, error) {
+		called++
+		sub := parseSubroutine(t, `sub snippet { set req.http.X-Snippet = "1"; }`)
+		return sub.Block.Statements, nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return found, called
+}
+
+func TestInjectMacro(t *testing.T) {
+	snippet := `set req.http.X-Snippet = "1";`
+	tests := []struct {
+		name   string
+		input  string
+		expect []string
+	}{
+		{
+			name: "leading comment",
+			input: `
+sub vcl_recv {
+  set req.http.A = "1";
+  #FASTLY RECV
+  set req.http.B = "1";
+}`,
+			expect: []string{`set req.http.A = "1";`, snippet, `set req.http.B = "1";`},
+		},
+		{
+			name: "trailing comment",
+			input: `
+sub vcl_recv {
+  set req.http.A = "1"; #FASTLY RECV
+  set req.http.B = "1";
+}`,
+			expect: []string{`set req.http.A = "1";`, snippet, `set req.http.B = "1";`},
+		},
+		{
+			name: "end of block",
+			input: `
+sub vcl_recv {
+  set req.http.A = "1";
+  # FASTLY recv
+}`,
+			expect: []string{`set req.http.A = "1";`, snippet},
+		},
+		{
+			name: "empty block",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+}`,
+			expect: []string{snippet},
+		},
+		{
+			name: "nested block",
+			input: `
+sub vcl_recv {
+  if (req.http.A) {
+    #FASTLY RECV
+    set req.http.B = "1";
+  }
+  set req.http.C = "1";
+}`,
+			expect: []string{"if (req.http.A) {", snippet, `set req.http.B = "1";`, "}", `set req.http.C = "1";`},
+		},
+		{
+			name: "only the first macro",
+			input: `
+sub vcl_recv {
+  #FASTLY RECV
+  set req.http.A = "1";
+  #FASTLY RECV
+  set req.http.B = "1";
+}`,
+			expect: []string{snippet, `set req.http.A = "1";`, `set req.http.B = "1";`},
+		},
+	}
+
+	for _, tt := range tests {
+		sub := parseSubroutine(t, tt.input)
+		found, called := injectSnippet(t, sub.Block)
+		if !found || called != 1 {
+			t.Errorf("%s: macro should be found and snippets should be loaded once, found=%t, called=%d", tt.name, found, called)
+			continue
+		}
+		if diff := cmp.Diff(tt.expect, flatten(sub.Block)); diff != "" {
+			t.Errorf("%s: injected statements mismatch, diff=%s", tt.name, diff)
+		}
+	}
+
+	sub := parseSubroutine(t, `
+sub vcl_recv {
+  # FASTLY DELIVER
+  set req.http.A = "1";
+}`)
+	if found, called := injectSnippet(t, sub.Block); found || called != 0 {
+		t.Errorf("Snippets should not be loaded when macro is not found")
+	}
+}
+
+func TestSortSnippets(t *testing.T) {
+	snippets := []*types.RemoteVCL{
+		{Name: "b_third", Priority: 100},
+		{Name: "first", Priority: 10},
+		{Name: "last", Priority: 200},
+		{Name: "a_second", Priority: 100},
+	}
+	sortSnippets(snippets)
+
+	var actual []string
+	for _, s := range snippets {
+		actual = append(actual, s.Name)
+	}
+	if diff := cmp.Diff([]string{"first", "a_second", "b_third", "last"}, actual); diff != "" {
+		t.Errorf("Sorted snippets mismatch, diff=%s", diff)
+	}
+}
+
+type mockFetcher struct {
+	Fetcher
+	snippets []*types.RemoteVCL
+}
+
+func (m *mockFetcher) Snippets() ([]*types.RemoteVCL, error) {
+	return m.snippets, nil
+}
+
+// Composite of custom VCL and snippets must be the same as generated VCL
+func TestCompositeMatchesGeneratedVCL(t *testing.T) {
+	fetcher := &mockFetcher{
+		snippets: []*types.RemoteVCL{
+			{Name: "b_third", Type: "recv", Priority: 100, Content: `set req.http.X-Snippet = "b_third";`},
+			{Name: "first", Type: "recv", Priority: 10, Content: `set req.http.X-Snippet = "first";`},
+			{Name: "a_second", Type: "recv", Priority: 100, Content: `set req.http.X-Snippet = "a_second";`},
+			{Name: "included", Type: "none", Priority: 100, Content: `set req.http.X-Snippet = "included";`},
+		},
+	}
+	scoped, include, err := fetchVCLSnippets(fetcher)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if _, ok := include["included"]; !ok || len(scoped["recv"]) != 3 {
+		t.Errorf("Snippets should be factoried by type, scoped=%v, include=%v", scoped, include)
+		return
+	}
+
+	custom, err := os.ReadFile("testdata/custom.vcl")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	generated, err := os.ReadFile("testdata/generated.vcl")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+
+	sub := parseSubroutine(t, string(custom))
+	_, found, err := InjectMacro(sub.Block, MacroPhrase("recv"), func() ([]ast.Statement, error) {
+		var statements []ast.Statement
+		for _, s := range scoped["recv"] {
+			p := parseSubroutine(t, "sub snippet {\n"+s.Data+"\n}")
+			statements = append(statements, p.Block.Statements...)
+		}
+		return statements, nil
+	})
+	if err != nil || !found {
+		t.Errorf("Macro should be found, err=%v", err)
+		return
+	}
+
+	expect := flatten(parseSubroutine(t, string(generated)).Block)
+	if diff := cmp.Diff(expect, flatten(sub.Block)); diff != "" {
+		t.Errorf("Composite VCL does not match generated VCL, diff=%s", diff)
+	}
+}
+
+func stripComments(v string) string {
+	var lines []string
+	for _, line := range strings.Split(v, "\n") {
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
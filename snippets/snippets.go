@@ -1,8 +1,9 @@
 package snippets
 
 type SnippetItem struct {
-	Data string
-	Name string
+	Data     string
+	Name     string
+	Priority int64
 }
 
 type Snippets struct {
@@ -12,6 +13,8 @@ type Snippets struct {
 	ScopedSnippets   map[string][]SnippetItem
 	IncludeSnippets  map[string]SnippetItem
 	LoggingEndpoints map[string]struct{}
+	// Domain names which are attached to the service, nil when the fetcher could not provide them
+	Domains []string
 }
 
 func (s *Snippets) EmbedSnippets() []SnippetItem {
@@ -48,3 +51,23 @@ func (s *Snippets) FetchLoggingEndpoint(fetcher Fetcher) error {
 	}
 	return nil
 }
+
+// DomainFetcher is implemented by fetchers which could provide domains of the service.
+type DomainFetcher interface {
+	Domains() ([]string, error)
+}
+
+// Fastly domains are used to validate hostnames in VCL conditions.
+// Domains are fetched only when the fetcher supports them.
+func (s *Snippets) FetchDomains(fetcher Fetcher) error {
+	f, ok := fetcher.(DomainFetcher)
+	if !ok {
+		return nil
+	}
+	domains, err := f.Domains()
+	if err != nil {
+		return err
+	}
+	s.Domains = domains
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/process"
 	"github.com/ysugimoto/falco/lexer"
 )
 
@@ -12,32 +13,58 @@ type TestCase struct {
 	Error error
 	Scope string
 	Time  int64 // msec order
+	// Explanation of caching decision, only set when enabled
+	CacheExplanation *process.CacheExplanation
+	// Errors of failed runs before the final run when the test is retried
+	RetryErrors []error
+	// Failure of quarantined test does not fail the suite
+	Quarantined bool
+}
+
+// IsFailed returns true when the test fails and it is not quarantined
+func (t *TestCase) IsFailed() bool {
+	return t.Error != nil && !t.Quarantined
+}
+
+// IsFlaky returns true when the test passes after retries
+func (t *TestCase) IsFlaky() bool {
+	return t.Error == nil && len(t.RetryErrors) > 0
 }
 
 func (t *TestCase) MarshalJSON() ([]byte, error) {
 	v := struct {
-		Name  string `json:"name"`
-		Error string `json:"error,omitempty"`
-		Scope string `json:"scope"`
-		Time  int64  `json:"elapsed_time"`
+		Name             string                    `json:"name"`
+		Error            string                    `json:"error,omitempty"`
+		Scope            string                    `json:"scope"`
+		Time             int64                     `json:"elapsed_time"`
+		CacheExplanation *process.CacheExplanation `json:"cache_explanation,omitempty"`
+		Retries          int                       `json:"retries,omitempty"`
+		Quarantined      bool                      `json:"quarantined,omitempty"`
 	}{
-		Name:  t.Name,
-		Scope: t.Scope,
-		Time:  t.Time,
+		Name:             t.Name,
+		Scope:            t.Scope,
+		Time:             t.Time,
+		CacheExplanation: t.CacheExplanation,
+		Retries:          len(t.RetryErrors),
+		Quarantined:      t.Quarantined,
 	}
 	if t.Error != nil {
-		switch e := t.Error.(type) {
-		case *errors.AssertionError:
-			v.Error = e.Message
-		case *errors.TestingError:
-			v.Error = e.Message
-		default:
-			v.Error = e.Error()
-		}
+		v.Error = errorMessage(t.Error)
 	}
 	return json.Marshal(v)
 }
 
+func errorMessage(err error) string {
+	switch e := err.(type) {
+	case *errors.AssertionError:
+		return e.Message
+	case *errors.TestingError:
+		return e.Message
+	default:
+		return e.Error()
+	}
+}
+
 type TestResult struct {
 	Filename string       `json:"file"`
 	Cases    []*TestCase  `json:"suites"`
@@ -46,7 +73,7 @@ type TestResult struct {
 
 func (t *TestResult) IsPassed() bool {
 	for i := range t.Cases {
-		if t.Cases[i].Error != nil {
+		if t.Cases[i].IsFailed() {
 			return false
 		}
 	}
@@ -63,6 +90,8 @@ type TestCounter struct {
 	Asserts int `json:"asserts"`
 	Passes  int `json:"passes"`
 	Fails   int `json:"fails"`
+	// Failed assertions in quarantined tests, they are not counted as Fails
+	Quarantined int `json:"quarantined"`
 }
 
 func NewTestCounter() *TestCounter {
@@ -78,3 +107,17 @@ func (c *TestCounter) Fail() {
 	c.Asserts++
 	c.Fails++
 }
+
+func (c *TestCounter) merge(v *TestCounter) {
+	c.Asserts += v.Asserts
+	c.Passes += v.Passes
+	c.Fails += v.Fails
+	c.Quarantined += v.Quarantined
+}
+
+// Merge counter of quarantined test, failed assertions are not counted as fails
+func (c *TestCounter) mergeQuarantined(v *TestCounter) {
+	c.Asserts += v.Asserts
+	c.Passes += v.Passes
+	c.Quarantined += v.Fails + v.Quarantined
+}
@@ -0,0 +1,184 @@
+package function
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Assert_headers_Name = "assert.headers"
+
+var Assert_headers_ArgumentTypes = []value.Type{value.IdentType, value.StringType}
+
+// Pseudo header name which asserts status code of response
+const statusPseudoHeader = ":status"
+
+func Assert_headers_Validate(args []value.Value) error {
+	if len(args) < 2 || len(args) > 3 {
+		return errors.ArgumentNotInRange(Assert_headers_Name, 2, 3, args)
+	}
+
+	for i := range Assert_headers_ArgumentTypes {
+		if args[i].Type() != Assert_headers_ArgumentTypes[i] {
+			return errors.TypeMismatch(Assert_headers_Name, i+1, Assert_headers_ArgumentTypes[i], args[i].Type())
+		}
+	}
+
+	if len(args) == 3 {
+		if args[2].Type() != value.StringType {
+			return errors.TypeMismatch(Assert_headers_Name, 3, value.StringType, args[2].Type())
+		}
+	}
+	return nil
+}
+
+// Assert that headers of req, bereq, beresp, resp or obj contain expected headers.
+// Expected headers are written as "Name: value" lines, and ":status" pseudo header asserts status code of the response.
+// Headers which are not written in expected headers are ignored, but they are shown in the diff.
+func Assert_headers(ctx *context.Context, args ...value.Value) (value.Value, error) {
+	if err := Assert_headers_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	target := value.Unwrap[*value.Ident](args[0])
+	actual, err := targetHeaders(ctx, target.Value)
+	if err != nil {
+		return nil, err
+	}
+	expect, err := parseExpectedHeaders(value.Unwrap[*value.String](args[1]).Value)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, missing, changed := diffHeaders(expect, actual)
+	ret := &value.Boolean{Value: missing == 0 && changed == 0}
+	if ret.Value {
+		return ret, nil
+	}
+
+	var assertionErr *errors.AssertionError
+	if len(args) == 3 {
+		assertionErr = errors.NewAssertionError(target, value.Unwrap[*value.String](args[2]).Value)
+	} else {
+		assertionErr = errors.NewAssertionError(
+			target,
+			"Headers of %s mismatch: %d missing, %d changed",
+			target.Value, missing, changed,
+		)
+	}
+	assertionErr.Diff = diff
+	return ret, assertionErr
+}
+
+func targetHeaders(ctx *context.Context, target string) (http.Header, error) {
+	var header http.Header
+	var resp *http.Response
+
+	switch target {
+	case "req":
+		if ctx.Request != nil {
+			header = ctx.Request.Header
+		}
+	case "bereq":
+		if ctx.BackendRequest != nil {
+			header = ctx.BackendRequest.Header
+		}
+	case "beresp":
+		resp = ctx.BackendResponse
+	case "resp":
+		resp = ctx.Response
+	case "obj":
+		resp = ctx.Object
+	default:
+		return nil, errors.NewTestingError(
+			"[%s] Target must be one of req, bereq, beresp, resp or obj but %s provided",
+			Assert_headers_Name, target,
+		)
+	}
+
+	if resp != nil {
+		header = resp.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header[statusPseudoHeader] = []string{strconv.Itoa(resp.StatusCode)}
+	}
+	if header == nil {
+		return nil, errors.NewTestingError("[%s] %s is not available", Assert_headers_Name, target)
+	}
+	return header, nil
+}
+
+func parseExpectedHeaders(expect string) (http.Header, error) {
+	header := http.Header{}
+	for _, line := range strings.Split(expect, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		// Skip leading colon of pseudo header to find separator
+		idx := strings.Index(line[1:], ":")
+		if idx == -1 {
+			return nil, errors.NewTestingError(
+				`[%s] Expected header must be "Name: value" format but "%s" provided`,
+				Assert_headers_Name, line,
+			)
+		}
+		name := strings.TrimSpace(line[:idx+1])
+		if !strings.HasPrefix(name, ":") {
+			name = http.CanonicalHeaderKey(name)
+		}
+		header[name] = append(header[name], strings.TrimSpace(line[idx+2:]))
+	}
+	return header, nil
+}
+
+// Make a structural diff of headers which are sorted by name.
+// Each line is prefixed with " " for equal header, "-" for expected header and "+" for actual header.
+// Returns diff lines, count of missing headers and count of changed headers.
+func diffHeaders(expect, actual http.Header) ([]string, int, int) {
+	names := map[string]struct{}{}
+	for name := range expect {
+		names[name] = struct{}{}
+	}
+	for name := range actual {
+		names[name] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var diff []string
+	var missing, changed int
+	format := func(prefix, name string, values []string) {
+		for _, v := range values {
+			diff = append(diff, prefix+" "+name+": "+v)
+		}
+	}
+
+	for _, name := range sorted {
+		e, inExpect := expect[name]
+		a, inActual := actual[name]
+		switch {
+		case !inExpect:
+			format("+", name, a)
+		case !inActual:
+			format("-", name, e)
+			missing++
+		case strings.Join(e, "\n") == strings.Join(a, "\n"):
+			format(" ", name, e)
+		default:
+			format("-", name, e)
+			format("+", name, a)
+			changed++
+		}
+	}
+	return diff, missing, changed
+}
@@ -0,0 +1,110 @@
+package function
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+func Test_Assert_headers(t *testing.T) {
+	ctx := &context.Context{
+		Request: &http.Request{
+			Header: http.Header{
+				"X-Foo": {"foo"},
+			},
+		},
+		Response: &http.Response{
+			StatusCode: 200,
+			Header: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Content-Type":  {"text/html"},
+				"Set-Cookie":    {"a=1", "b=2"},
+			},
+		},
+	}
+
+	tests := []struct {
+		args   []value.Value
+		err    error
+		expect *value.Boolean
+	}{
+		{
+			args: []value.Value{
+				&value.Ident{Value: "resp"},
+				&value.String{Value: ":status: 200\ncontent-type: text/html\nSet-Cookie: a=1\nSet-Cookie: b=2"},
+			},
+			expect: &value.Boolean{Value: true},
+		},
+		{
+			args: []value.Value{
+				&value.Ident{Value: "resp"},
+				&value.String{Value: ":status: 404\nContent-Type: text/html\nVary: Accept-Encoding"},
+			},
+			expect: &value.Boolean{Value: false},
+			err: &errors.AssertionError{
+				Message: "Headers of resp mismatch: 1 missing, 1 changed",
+				Diff: []string{
+					"- :status: 404",
+					"+ :status: 200",
+					"+ Cache-Control: max-age=60",
+					"  Content-Type: text/html",
+					"+ Set-Cookie: a=1",
+					"+ Set-Cookie: b=2",
+					"- Vary: Accept-Encoding",
+				},
+			},
+		},
+		{
+			args: []value.Value{
+				&value.Ident{Value: "req"},
+				&value.String{Value: "X-Foo: bar"},
+				&value.String{Value: "custom_message"},
+			},
+			expect: &value.Boolean{Value: false},
+			err: &errors.AssertionError{
+				Message: "custom_message",
+				Diff: []string{
+					"- X-Foo: bar",
+					"+ X-Foo: foo",
+				},
+			},
+		},
+		{
+			args: []value.Value{
+				&value.Ident{Value: "beresp"},
+				&value.String{Value: "X-Foo: foo"},
+			},
+			err: &errors.TestingError{},
+		},
+		{
+			args: []value.Value{
+				&value.Ident{Value: "req"},
+				&value.String{Value: "X-Foo"},
+			},
+			err: &errors.TestingError{},
+		},
+	}
+
+	for i := range tests {
+		v, err := Assert_headers(ctx, tests[i].args...)
+		if diff := cmp.Diff(
+			tests[i].err,
+			err,
+			cmpopts.IgnoreFields(errors.AssertionError{}, "Actual"),
+			cmpopts.IgnoreFields(errors.TestingError{}, "Message"),
+		); diff != "" {
+			t.Errorf("Assert_headers()[%d] error: diff=%s", i, diff)
+		}
+		if tests[i].expect == nil {
+			continue
+		}
+		if diff := cmp.Diff(tests[i].expect, v); diff != "" {
+			t.Errorf("Assert_headers()[%d] return value: diff=%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package function
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Assert_logged_Name = "assert.logged"
+
+func Assert_logged_Validate(args []value.Value) error {
+	return validateLogAssertion(Assert_logged_Name, args)
+}
+
+func validateLogAssertion(name string, args []value.Value) error {
+	if len(args) < 1 || len(args) > 3 {
+		return errors.ArgumentNotInRange(name, 1, 3, args)
+	}
+
+	for i := range args {
+		if args[i].Type() != value.StringType {
+			return errors.TypeMismatch(name, i+1, value.StringType, args[i].Type())
+		}
+	}
+	return nil
+}
+
+// Split emitted log line into endpoint name and content.
+// Log line for Fastly logging endpoint is formatted as "syslog [service_id] [endpoint] :: [content]"
+func parseLogLine(line string) (string, string) {
+	rest, ok := strings.CutPrefix(line, "syslog ")
+	if !ok {
+		return "", line
+	}
+	_, rest, _ = strings.Cut(rest, " ")
+	endpoint, content, ok := strings.Cut(rest, " :: ")
+	if !ok {
+		return "", line
+	}
+	return endpoint, content
+}
+
+// Find emitted log lines which match the endpoint prefix and content pattern
+func findLogs(name string, ctx *context.Context, args []value.Value) ([]string, error) {
+	endpoint := value.Unwrap[*value.String](args[0]).Value
+
+	var re *regexp.Regexp
+	if len(args) > 1 {
+		pattern := value.Unwrap[*value.String](args[1]).Value
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return nil, errors.NewTestingError(
+				"%s: Invalid regexp string provided: %s", name, pattern,
+			)
+		}
+	}
+
+	var matched []string
+	for _, line := range ctx.EmittedLogs {
+		e, content := parseLogLine(line)
+		if !strings.HasPrefix(e, endpoint) {
+			continue
+		}
+		if re != nil && !re.MatchString(content) {
+			continue
+		}
+		matched = append(matched, line)
+	}
+	return matched, nil
+}
+
+func logAssertionTarget(args []value.Value) string {
+	target := "endpoint " + value.Unwrap[*value.String](args[0]).Value
+	if len(args) > 1 {
+		target += " matching " + value.Unwrap[*value.String](args[1]).Value
+	}
+	return target
+}
+
+func Assert_logged(ctx *context.Context, args ...value.Value) (value.Value, error) {
+	if err := Assert_logged_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	matched, err := findLogs(Assert_logged_Name, ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) > 0 {
+		return &value.Boolean{Value: true}, nil
+	}
+
+	actual := &value.String{Value: strings.Join(ctx.EmittedLogs, "\n")}
+	if len(args) == 3 {
+		return &value.Boolean{}, errors.NewAssertionError(actual, value.Unwrap[*value.String](args[2]).Value)
+	}
+	return &value.Boolean{}, errors.NewAssertionError(
+		actual,
+		"Log for %s should be emitted but not found in %d emitted log line(s)",
+		logAssertionTarget(args), len(ctx.EmittedLogs),
+	)
+}
@@ -0,0 +1,104 @@
+package function
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+func Test_Assert_logged(t *testing.T) {
+	ctx := &context.Context{
+		EmittedLogs: []string{
+			"syslog service_id access_log :: GET /foo 200",
+			"syslog service_id bigquery_audit :: user=alice",
+			"plain log line",
+		},
+	}
+
+	tests := []struct {
+		args []value.Value
+		err  error
+	}{
+		{
+			args: []value.Value{&value.String{Value: "access_log"}},
+		},
+		{
+			args: []value.Value{&value.String{Value: "bigquery"}, &value.String{Value: "^user=\\w+$"}},
+		},
+		{
+			args: []value.Value{&value.String{Value: ""}, &value.String{Value: "^plain"}},
+		},
+		{
+			args: []value.Value{&value.String{Value: "access_log"}, &value.String{Value: "POST"}},
+			err:  &errors.AssertionError{},
+		},
+		{
+			args: []value.Value{&value.String{Value: "s3"}, &value.String{Value: ".*"}, &value.String{Value: "custom_message"}},
+			err:  &errors.AssertionError{},
+		},
+		{
+			args: []value.Value{&value.String{Value: "access_log"}, &value.String{Value: "[a"}},
+			err:  &errors.TestingError{},
+		},
+		{
+			args: []value.Value{&value.Integer{Value: 1}},
+			err:  &errors.TestingError{},
+		},
+	}
+
+	for i := range tests {
+		_, err := Assert_logged(ctx, tests[i].args...)
+		if diff := cmp.Diff(
+			tests[i].err,
+			err,
+			cmpopts.IgnoreFields(errors.AssertionError{}, "Message", "Actual"),
+			cmpopts.IgnoreFields(errors.TestingError{}, "Message"),
+		); diff != "" {
+			t.Errorf("Assert_logged()[%d] error: diff=%s", i, diff)
+		}
+	}
+}
+
+func Test_Assert_not_logged(t *testing.T) {
+	ctx := &context.Context{
+		EmittedLogs: []string{
+			"syslog service_id access_log :: GET /foo 200",
+		},
+	}
+
+	tests := []struct {
+		args []value.Value
+		err  error
+	}{
+		{
+			args: []value.Value{&value.String{Value: "error_log"}},
+		},
+		{
+			args: []value.Value{&value.String{Value: "access_log"}, &value.String{Value: " 5\\d\\d$"}},
+		},
+		{
+			args: []value.Value{&value.String{Value: "access"}},
+			err:  &errors.AssertionError{},
+		},
+		{
+			args: []value.Value{&value.String{Value: "access_log"}, &value.String{Value: "GET"}, &value.String{Value: "custom_message"}},
+			err:  &errors.AssertionError{},
+		},
+	}
+
+	for i := range tests {
+		_, err := Assert_not_logged(ctx, tests[i].args...)
+		if diff := cmp.Diff(
+			tests[i].err,
+			err,
+			cmpopts.IgnoreFields(errors.AssertionError{}, "Message", "Actual"),
+			cmpopts.IgnoreFields(errors.TestingError{}, "Message"),
+		); diff != "" {
+			t.Errorf("Assert_not_logged()[%d] error: diff=%s", i, diff)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package function
+
+import (
+	"strings"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Assert_not_logged_Name = "assert.not_logged"
+
+func Assert_not_logged_Validate(args []value.Value) error {
+	return validateLogAssertion(Assert_not_logged_Name, args)
+}
+
+func Assert_not_logged(ctx *context.Context, args ...value.Value) (value.Value, error) {
+	if err := Assert_not_logged_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	matched, err := findLogs(Assert_not_logged_Name, ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) == 0 {
+		return &value.Boolean{Value: true}, nil
+	}
+
+	actual := &value.String{Value: strings.Join(matched, "\n")}
+	if len(args) == 3 {
+		return &value.Boolean{}, errors.NewAssertionError(actual, value.Unwrap[*value.String](args[2]).Value)
+	}
+	return &value.Boolean{}, errors.NewAssertionError(
+		actual,
+		"Log for %s should not be emitted but found %d log line(s)",
+		logAssertionTarget(args), len(matched),
+	)
+}
@@ -0,0 +1,46 @@
+package function
+
+import (
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Testing_advance_time_Name = "testing.advance_time"
+
+var Testing_advance_time_ArgumentTypes = []value.Type{value.RTimeType}
+
+func Testing_advance_time_Validate(args []value.Value) error {
+	if len(args) != 1 {
+		return errors.ArgumentNotEnough(Testing_advance_time_Name, 1, args)
+	}
+
+	for i := range Testing_advance_time_ArgumentTypes {
+		if args[i].Type() != Testing_advance_time_ArgumentTypes[i] {
+			return errors.TypeMismatch(
+				Testing_advance_time_Name, i+1, Testing_advance_time_ArgumentTypes[i], args[i].Type(),
+			)
+		}
+	}
+	return nil
+}
+
+// Advance the simulated clock by the duration, the clock starts from current time
+// if testing.fixed_time is not called so that the time is fixed after this function is called
+func Testing_advance_time(
+	ctx *context.Context,
+	args ...value.Value,
+) (value.Value, error) {
+
+	if err := Testing_advance_time_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	d := value.Unwrap[*value.RTime](args[0]).Value
+	if d < 0 {
+		return value.Null, errors.NewTestingError("Could not advance time by negative duration %s", d)
+	}
+	advanced := ctx.Now().Add(d)
+	ctx.FixedTime = &advanced
+	return value.Null, nil
+}
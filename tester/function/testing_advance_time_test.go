@@ -0,0 +1,42 @@
+package function
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+func Test_advance_time(t *testing.T) {
+	t.Run("Advance from fixed time", func(t *testing.T) {
+		fixed := time.Unix(1700000000, 0)
+		c := &context.Context{FixedTime: &fixed}
+		for i := 0; i < 3; i++ {
+			if _, err := Testing_advance_time(c, &value.RTime{Value: 10 * time.Second}); err != nil {
+				t.Errorf("Unexpected error on Testing_advance_time, %s", err)
+				return
+			}
+		}
+		if !c.FixedTime.Equal(fixed.Add(30 * time.Second)) {
+			t.Errorf("Unexpected advanced time, expect=%s, got=%s", fixed.Add(30*time.Second), c.FixedTime)
+		}
+	})
+	t.Run("Advance from current time", func(t *testing.T) {
+		c := &context.Context{}
+		before := time.Now()
+		if _, err := Testing_advance_time(c, &value.RTime{Value: time.Hour}); err != nil {
+			t.Errorf("Unexpected error on Testing_advance_time, %s", err)
+			return
+		}
+		if c.FixedTime == nil || c.FixedTime.Before(before.Add(time.Hour)) {
+			t.Errorf("Expected time to be fixed an hour later, got=%v", c.FixedTime)
+		}
+	})
+	t.Run("Negative duration", func(t *testing.T) {
+		c := &context.Context{}
+		if _, err := Testing_advance_time(c, &value.RTime{Value: -time.Second}); err == nil {
+			t.Errorf("Expected error for negative duration")
+		}
+	})
+}
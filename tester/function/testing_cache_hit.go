@@ -0,0 +1,40 @@
+package function
+
+import (
+	"github.com/ysugimoto/falco/interpreter"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Testing_cache_hit_Name = "testing.cache_hit"
+
+func Testing_cache_hit_Validate(args []value.Value) error {
+	if len(args) > 1 {
+		return errors.ArgumentNotInRange(Testing_cache_hit_Name, 0, 1, args)
+	}
+	if len(args) == 1 && args[0].Type() != value.StringType {
+		return errors.TypeMismatch(Testing_cache_hit_Name, 1, value.StringType, args[0].Type())
+	}
+	return nil
+}
+
+// Simulate the request hits the cache object, returns the hit count of the object.
+// Hit count is tracked per object in the test case, so obj.hits increases on each call.
+func Testing_cache_hit(
+	ctx *context.Context,
+	i *interpreter.Interpreter,
+	args ...value.Value,
+) (value.Value, error) {
+
+	if err := Testing_cache_hit_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	var hash string
+	if len(args) == 1 {
+		hash = value.Unwrap[*value.String](args[0]).Value
+	}
+	item := i.TestCacheHit(hash)
+	return &value.Integer{Value: int64(item.Hits)}, nil
+}
@@ -0,0 +1,47 @@
+package function
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ysugimoto/falco/interpreter"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
+	"github.com/ysugimoto/falco/resolver"
+)
+
+func Test_cache_hit(t *testing.T) {
+	ip := interpreter.New(context.WithResolver(resolver.NewStaticResolver("main", "")))
+	if err := ip.TestProcessInit(httptest.NewRequest(http.MethodGet, "http://localhost/", nil)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	t.Run("hit count increases per object", func(t *testing.T) {
+		tests := []struct {
+			args   []value.Value
+			expect int64
+		}{
+			{expect: 1},
+			{expect: 2},
+			{args: []value.Value{&value.String{Value: "/other"}}, expect: 1},
+			{expect: 3},
+		}
+		for _, tt := range tests {
+			ret, err := Testing_cache_hit(context.New(), ip, tt.args...)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				continue
+			}
+			if v := value.Unwrap[*value.Integer](ret).Value; v != tt.expect {
+				t.Errorf("Hit count expects %d but got %d", tt.expect, v)
+			}
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		if _, err := Testing_cache_hit(context.New(), ip, &value.Integer{Value: 1}); err == nil {
+			t.Errorf("Expected error but nil")
+		}
+	})
+}
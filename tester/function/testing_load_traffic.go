@@ -0,0 +1,127 @@
+package function
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Testing_load_traffic_Name = "testing.load_traffic"
+
+var Testing_load_traffic_ArgumentTypes = []value.Type{value.IdentType, value.StringType}
+
+func Testing_load_traffic_Validate(args []value.Value) error {
+	if len(args) != 2 {
+		return errors.ArgumentNotEnough(Testing_load_traffic_Name, 2, args)
+	}
+
+	for i := range Testing_load_traffic_ArgumentTypes {
+		if args[i].Type() != Testing_load_traffic_ArgumentTypes[i] {
+			return errors.TypeMismatch(
+				Testing_load_traffic_Name, i+1, Testing_load_traffic_ArgumentTypes[i], args[i].Type(),
+			)
+		}
+	}
+	return nil
+}
+
+// A row of traffic CSV, offset is seconds from the simulated time when the traffic is loaded
+type trafficRow struct {
+	offset int64
+	entry  string
+	count  int64
+}
+
+// Replay the traffic which is loaded from CSV file to the ratecounter.
+// Each row is formatted as "offset_seconds,entry,count" and the header row is optional.
+// The simulated clock is advanced to the last offset so that the following assertions see the end of the traffic.
+func Testing_load_traffic(
+	ctx *context.Context,
+	args ...value.Value,
+) (value.Value, error) {
+
+	if err := Testing_load_traffic_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	name := value.Unwrap[*value.Ident](args[0]).Value
+	if _, ok := ctx.Ratecounters[name]; !ok {
+		return value.Null, errors.NewTestingError("ratecounter %s not found in VCL", name)
+	}
+
+	file := value.Unwrap[*value.String](args[1]).Value
+	fp, err := os.Open(file)
+	if err != nil {
+		return value.Null, errors.NewTestingError("Failed to open traffic file %s: %s", file, err)
+	}
+	defer fp.Close()
+
+	rows, err := parseTrafficRows(fp)
+	if err != nil {
+		return value.Null, errors.NewTestingError("Failed to parse traffic file %s: %s", file, err)
+	}
+
+	start := ctx.Now()
+	rc := ctx.RateLimit.Ratecounter(name)
+	var last int64
+	for _, row := range rows {
+		rc.Increment(row.entry, row.count, start.Add(time.Duration(row.offset)*time.Second))
+		last = row.offset
+	}
+	end := start.Add(time.Duration(last) * time.Second)
+	ctx.FixedTime = &end
+
+	return value.Null, nil
+}
+
+func parseTrafficRows(r io.Reader) ([]trafficRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var rows []trafficRow
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		offset, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 64)
+		if err != nil {
+			// First row could be a header
+			if row == 1 {
+				continue
+			}
+			return nil, errors.NewTestingError("row %d: offset must be an integer, got %s", row, record[0])
+		}
+		if offset < 0 {
+			return nil, errors.NewTestingError("row %d: offset must not be negative, got %d", row, offset)
+		}
+		count, err := strconv.ParseInt(strings.TrimSpace(record[2]), 10, 64)
+		if err != nil {
+			return nil, errors.NewTestingError("row %d: count must be an integer, got %s", row, record[2])
+		}
+		rows = append(rows, trafficRow{
+			offset: offset,
+			entry:  strings.TrimSpace(record[1]),
+			count:  count,
+		})
+	}
+
+	// Replay in time order even if rows are not sorted in the file
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].offset < rows[j].offset
+	})
+	return rows, nil
+}
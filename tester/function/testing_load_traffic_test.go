@@ -0,0 +1,90 @@
+package function
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ysugimoto/falco/ast"
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+func Test_load_traffic(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traffic.csv")
+	traffic := `offset,entry,count
+0,192.0.2.1,10
+# burst of the client
+30,192.0.2.1,50
+5,192.0.2.2,3
+45,192.0.2.1,20
+`
+	if err := os.WriteFile(file, []byte(traffic), 0o644); err != nil {
+		t.Fatalf("Failed to write traffic file: %s", err)
+	}
+
+	fixed := time.Unix(1700000000, 0)
+	c := context.New()
+	c.FixedTime = &fixed
+	c.Ratecounters["rc"] = &ast.RatecounterDeclaration{}
+	c.Penaltyboxes["pb"] = &ast.PenaltyboxDeclaration{}
+
+	_, err := Testing_load_traffic(c, &value.Ident{Value: "rc", Literal: true}, &value.String{Value: file})
+	if err != nil {
+		t.Fatalf("Unexpected error on Testing_load_traffic, %s", err)
+	}
+	if !c.FixedTime.Equal(fixed.Add(45 * time.Second)) {
+		t.Errorf("Expected clock to be advanced to the last offset, got=%s", c.FixedTime)
+	}
+
+	tests := []struct {
+		entry  string
+		window time.Duration
+		expect int64
+	}{
+		{entry: "192.0.2.1", window: 10 * time.Second, expect: 20},
+		{entry: "192.0.2.1", window: 20 * time.Second, expect: 70},
+		{entry: "192.0.2.1", window: 60 * time.Second, expect: 80},
+		{entry: "192.0.2.2", window: 60 * time.Second, expect: 3},
+	}
+	for i, tt := range tests {
+		ret, err := Testing_ratecounter_bucket(
+			c,
+			&value.Ident{Value: "rc", Literal: true},
+			&value.String{Value: tt.entry},
+			&value.RTime{Value: tt.window},
+		)
+		if err != nil {
+			t.Errorf("[%d] Unexpected error on Testing_ratecounter_bucket, %s", i, err)
+			continue
+		}
+		if v := value.Unwrap[*value.Integer](ret).Value; v != tt.expect {
+			t.Errorf("[%d] Unexpected bucket count, expect=%d, got=%d", i, tt.expect, v)
+		}
+	}
+
+	// Penaltybox expiry is observed by advancing the clock
+	c.RateLimit.Penaltybox("pb").Add("192.0.2.1", 2*time.Minute, *c.FixedTime)
+	if _, err := Testing_advance_time(c, &value.RTime{Value: 90 * time.Second}); err != nil {
+		t.Fatalf("Unexpected error on Testing_advance_time, %s", err)
+	}
+	ret, err := Testing_penaltybox_ttl(c, &value.Ident{Value: "pb", Literal: true}, &value.String{Value: "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("Unexpected error on Testing_penaltybox_ttl, %s", err)
+	}
+	if v := value.Unwrap[*value.RTime](ret).Value; v != 30*time.Second {
+		t.Errorf("Unexpected penaltybox ttl, expect=30s, got=%s", v)
+	}
+
+	if _, err := Testing_load_traffic(c, &value.Ident{Value: "undefined", Literal: true}, &value.String{Value: file}); err == nil {
+		t.Errorf("Expected error for undefined ratecounter")
+	}
+	invalid := filepath.Join(t.TempDir(), "invalid.csv")
+	if err := os.WriteFile(invalid, []byte("0,192.0.2.1,1\nfoo,192.0.2.1,1\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write traffic file: %s", err)
+	}
+	if _, err := Testing_load_traffic(c, &value.Ident{Value: "rc", Literal: true}, &value.String{Value: invalid}); err == nil {
+		t.Errorf("Expected error for invalid offset")
+	}
+}
@@ -0,0 +1,37 @@
+package function
+
+import (
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/builtin"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Testing_penaltybox_ttl_Name = "testing.penaltybox_ttl"
+
+var Testing_penaltybox_ttl_ArgumentTypes = []value.Type{value.IdentType, value.StringType}
+
+func Testing_penaltybox_ttl_Validate(args []value.Value) error {
+	return builtin.ValidateRatelimitArguments(Testing_penaltybox_ttl_Name, Testing_penaltybox_ttl_ArgumentTypes, 1, args)
+}
+
+// Return the remaining duration until the entry expires from the penaltybox, 0s if the entry is not in the box
+func Testing_penaltybox_ttl(
+	ctx *context.Context,
+	args ...value.Value,
+) (value.Value, error) {
+
+	if err := Testing_penaltybox_ttl_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	name := value.Unwrap[*value.Ident](args[0]).Value
+	if _, ok := ctx.Penaltyboxes[name]; !ok {
+		return value.Null, errors.NewTestingError("penaltybox %s not found in VCL", name)
+	}
+
+	entry := args[1].String()
+	return &value.RTime{
+		Value: ctx.RateLimit.Penaltybox(name).TTL(entry, ctx.Now()),
+	}, nil
+}
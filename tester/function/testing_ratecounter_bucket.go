@@ -0,0 +1,43 @@
+package function
+
+import (
+	"time"
+
+	"github.com/ysugimoto/falco/interpreter/context"
+	"github.com/ysugimoto/falco/interpreter/function/builtin"
+	"github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+)
+
+const Testing_ratecounter_bucket_Name = "testing.ratecounter_bucket"
+
+var Testing_ratecounter_bucket_ArgumentTypes = []value.Type{value.IdentType, value.StringType, value.RTimeType}
+
+func Testing_ratecounter_bucket_Validate(args []value.Value) error {
+	return builtin.ValidateRatelimitArguments(Testing_ratecounter_bucket_Name, Testing_ratecounter_bucket_ArgumentTypes, 1, args)
+}
+
+// Return the count of the entry in the window which ends at the simulated time
+func Testing_ratecounter_bucket(
+	ctx *context.Context,
+	args ...value.Value,
+) (value.Value, error) {
+
+	if err := Testing_ratecounter_bucket_Validate(args); err != nil {
+		return nil, errors.NewTestingError(err.Error())
+	}
+
+	name := value.Unwrap[*value.Ident](args[0]).Value
+	if _, ok := ctx.Ratecounters[name]; !ok {
+		return value.Null, errors.NewTestingError("ratecounter %s not found in VCL", name)
+	}
+	window := value.Unwrap[*value.RTime](args[2]).Value
+	if window <= 0 || window > time.Minute {
+		return value.Null, errors.NewTestingError("window must be between 1s and 60s, %s provided", window)
+	}
+
+	entry := args[1].String()
+	return &value.Integer{
+		Value: ctx.RateLimit.Ratecounter(name).Bucket(entry, window, ctx.Now()),
+	}, nil
+}
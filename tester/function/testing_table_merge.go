@@ -54,6 +54,7 @@ func Testing_table_merge(
 	for i := range merge.Properties {
 		Testing_table_MergeProperty(base, merge.Properties[i])
 	}
+	ctx.ResetTableIndex(base)
 
 	return value.Null, nil
 }
@@ -73,6 +73,7 @@ func Testing_table_set(
 			Value: val,
 		},
 	})
+	ctx.ResetTableIndex(v)
 
 	return value.Null, nil
 }
@@ -0,0 +1,144 @@
+package tester
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	ife "github.com/ysugimoto/falco/interpreter/function/errors"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string          `xml:"name,attr"`
+	ClassName string          `xml:"classname,attr"`
+	Time      string          `xml:"time,attr"`
+	Failure   *junitFailure   `xml:"failure,omitempty"`
+	Skipped   *junitSkipped   `xml:"skipped,omitempty"`
+	Flaky     []*junitFailure `xml:"flakyFailure,omitempty"`
+	Rerun     []*junitFailure `xml:"rerunFailure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes test results as JUnit XML so that CI services could display them in their test UI.
+// Each test file becomes a test suite and each scope of testing subroutine becomes a test case.
+// Following Maven Surefire convention, failures of retried runs are written as flakyFailure when the test passes finally,
+// or rerunFailure when the test still fails. Failure of quarantined test is written as skipped.
+func (f *TestFactory) WriteJUnit(w io.Writer) error {
+	root := &junitTestSuites{Name: "falco"}
+	for _, r := range f.Results {
+		suite := &junitTestSuite{Name: r.Filename}
+		var elapsed int64
+		for _, c := range r.Cases {
+			tc := junitCase(r.Filename, c)
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+			if tc.Skipped != nil {
+				suite.Skipped++
+			}
+			elapsed += c.Time
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suite.Tests = len(suite.Cases)
+		suite.Time = junitTime(elapsed)
+
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Skipped += suite.Skipped
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithStack(err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return errors.WithStack(err)
+}
+
+func junitCase(filename string, c *TestCase) *junitTestCase {
+	tc := &junitTestCase{
+		Name:      fmt.Sprintf("[%s] %s", c.Scope, c.Name),
+		ClassName: filename,
+		Time:      junitTime(c.Time),
+	}
+
+	var retries []*junitFailure
+	for _, err := range c.RetryErrors {
+		retries = append(retries, junitError(err))
+	}
+
+	switch {
+	case c.Error == nil:
+		tc.Flaky = retries
+	case c.Quarantined:
+		tc.Skipped = &junitSkipped{
+			Message: "Quarantined test fails: " + errorMessage(c.Error),
+		}
+	default:
+		tc.Failure = junitError(c.Error)
+		tc.Rerun = retries
+	}
+	return tc
+}
+
+func junitError(err error) *junitFailure {
+	failure := &junitFailure{
+		Message: errorMessage(err),
+		Type:    "error",
+		Body:    err.Error(),
+	}
+	switch e := err.(type) {
+	case *ife.AssertionError:
+		failure.Type = "assertion"
+		if len(e.Diff) > 0 {
+			failure.Body += "\nDiff (-expect +actual):\n" + strings.Join(e.Diff, "\n")
+		} else if e.Actual != nil {
+			failure.Body += "\nActual Value: " + e.Actual.String()
+		}
+		failure.Body += fmt.Sprintf("\nat line %d, position %d", e.Token.Line, e.Token.Position)
+	case *ife.TestingError:
+		failure.Type = "testing"
+		failure.Body += fmt.Sprintf("\nat line %d, position %d", e.Token.Line, e.Token.Position)
+	}
+	return failure
+}
+
+// Format milliseconds as seconds which JUnit time attribute expects
+func junitTime(msec int64) string {
+	return fmt.Sprintf("%.3f", float64(msec)/1000)
+}
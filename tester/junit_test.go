@@ -0,0 +1,58 @@
+package tester
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	ife "github.com/ysugimoto/falco/interpreter/function/errors"
+	"github.com/ysugimoto/falco/interpreter/value"
+	"github.com/ysugimoto/falco/token"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	assertion := &ife.AssertionError{
+		Token:   token.Token{Line: 10, Position: 3},
+		Actual:  &value.String{Value: "foo"},
+		Message: "Assertion error: expect=bar, actual=foo",
+	}
+	factory := &TestFactory{
+		Results: []*TestResult{
+			{
+				Filename: "main.test.vcl",
+				Cases: []*TestCase{
+					{Name: "passes", Scope: "RECV", Time: 12},
+					{Name: "fails", Scope: "RECV", Error: assertion, RetryErrors: []error{assertion}},
+					{Name: "flaky", Scope: "DELIVER", Time: 1500, RetryErrors: []error{errors.New("Timeout")}},
+					{Name: "quarantined", Scope: "RECV", Error: assertion, Quarantined: true},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := factory.WriteJUnit(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="falco" tests="4" failures="1" skipped="1">
+  <testsuite name="main.test.vcl" tests="4" failures="1" errors="0" skipped="1" time="1.512">
+    <testcase name="[RECV] passes" classname="main.test.vcl" time="0.012"></testcase>
+    <testcase name="[RECV] fails" classname="main.test.vcl" time="0.000">
+      <failure message="Assertion error: expect=bar, actual=foo" type="assertion">Assertion Error: Assertion error: expect=bar, actual=foo&#xA;Actual Value: foo&#xA;at line 10, position 3</failure>
+      <rerunFailure message="Assertion error: expect=bar, actual=foo" type="assertion">Assertion Error: Assertion error: expect=bar, actual=foo&#xA;Actual Value: foo&#xA;at line 10, position 3</rerunFailure>
+    </testcase>
+    <testcase name="[DELIVER] flaky" classname="main.test.vcl" time="1.500">
+      <flakyFailure message="Timeout" type="error">Timeout</flakyFailure>
+    </testcase>
+    <testcase name="[RECV] quarantined" classname="main.test.vcl" time="0.000">
+      <skipped message="Quarantined test fails: Assertion error: expect=bar, actual=foo"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if diff := cmp.Diff(expect, buf.String()); diff != "" {
+		t.Errorf("JUnit XML mismatch, diff=%s", diff)
+	}
+}
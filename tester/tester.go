@@ -4,17 +4,21 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/ysugimoto/falco/annotation"
 	"github.com/ysugimoto/falco/ast"
 	"github.com/ysugimoto/falco/config"
+	"github.com/ysugimoto/falco/crash"
 	"github.com/ysugimoto/falco/interpreter"
 	icontext "github.com/ysugimoto/falco/interpreter/context"
-	"github.com/ysugimoto/falco/interpreter/function"
 	"github.com/ysugimoto/falco/interpreter/value"
 	"github.com/ysugimoto/falco/interpreter/variable"
 	"github.com/ysugimoto/falco/lexer"
@@ -27,6 +31,9 @@ import (
 var (
 	defaultTimeout = 10 // testing process will be timeouted in 10 minutes
 	ErrTimeout     = errors.New("Timeout")
+
+	// Testing variables are stateless so inject them only once
+	injectTestingVariables sync.Once
 )
 
 type Tester struct {
@@ -66,21 +73,52 @@ func (t *Tester) listTestFiles(mainVCL string) ([]string, error) {
 	return testFiles, nil
 }
 
-// Only expose function for running tests
+// Only expose function for running tests.
+// Test files are run concurrently in worker pool, each test file has its own interpreters, counter and debugger
+// and the results are aggregated in the order of test files so the output is deterministic.
 func (t *Tester) Run(main string) (*TestFactory, error) {
 	// Find test target VCL files
 	targetFiles, err := t.listTestFiles(main)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+
+	concurrency := t.config.Parallel
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
 	// Run tests
-	var results []*TestResult
+	results := make([]*TestResult, len(targetFiles))
+	errs := make([]error, len(targetFiles))
+	counters := make([]*TestCounter, len(targetFiles))
+	debuggers := make([]*Debugger, len(targetFiles))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
 	for i := range targetFiles {
-		result, err := t.run(targetFiles[i])
-		if err != nil {
-			return nil, errors.WithStack(err)
+		counters[i] = NewTestCounter()
+		debuggers[i] = NewDebugger()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = t.run(targetFiles[i], counters[i], debuggers[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range targetFiles {
+		// Raise the panic in the caller goroutine so that the command could report the crash
+		var p *crash.Panic
+		if errors.As(errs[i], &p) {
+			panic(p)
+		}
+		if errs[i] != nil {
+			return nil, errors.WithStack(errs[i])
 		}
-		results = append(results, result)
+		t.counter.merge(counters[i])
+		t.debugger.stack = append(t.debugger.stack, debuggers[i].stack...)
 	}
 
 	return &TestFactory{
@@ -91,7 +129,7 @@ func (t *Tester) Run(main string) (*TestFactory, error) {
 }
 
 // Actually run testing method
-func (t *Tester) run(testFile string) (*TestResult, error) {
+func (t *Tester) run(testFile string, counter *TestCounter, debugger *Debugger) (*TestResult, error) {
 	resolvers, err := resolver.NewFileResolvers(testFile, t.config.IncludePaths)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -112,8 +150,9 @@ func (t *Tester) run(testFile string) (*TestResult, error) {
 	mockRequest := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
 	ctx := context.Background()
 
-	errChan := make(chan error)
-	finishChan := make(chan []*TestCase)
+	// Channels are buffered not to leak the goroutine when the test is timed out
+	errChan := make(chan error, 1)
+	finishChan := make(chan []*TestCase, 1)
 
 	timeout := defaultTimeout
 	if t.config.Timeout > 0 {
@@ -122,6 +161,12 @@ func (t *Tester) run(testFile string) (*TestResult, error) {
 	timeoutChan := time.After(time.Duration(timeout) * time.Minute)
 
 	go func(vcl *ast.VCL) {
+		// Panic in this goroutine could not be recovered by the caller, pass it through the channel
+		defer func() {
+			if r := recover(); r != nil {
+				errChan <- crash.Wrap(r)
+			}
+		}()
 		// Factory definitions in the test file
 		defs := t.factoryDefinitions(vcl)
 		var cases []*TestCase
@@ -132,24 +177,33 @@ func (t *Tester) run(testFile string) (*TestResult, error) {
 				continue
 			}
 
-			// Some functions like "testing.table_set()" will take side-effect for another testing subroutine
-			// so we always initialize interpreter, inject testing functions for each subroutine
-			i := t.setupInterpreter(defs)
-
-			if err := i.TestProcessInit(mockRequest.Clone(ctx)); err != nil {
-				errChan <- errors.WithStack(err)
-				return
-			}
 			suite, scopes := t.findTestSuites(sub)
-			for _, s := range scopes {
-				start := time.Now()
-				err := i.ProcessTestSubroutine(s, sub)
-				cases = append(cases, &TestCase{
-					Name:  suite,
-					Error: errors.Cause(err),
-					Scope: s.String(),
-					Time:  time.Since(start).Milliseconds(),
-				})
+			quarantined := t.isQuarantined(suite)
+			// Flaky test is retried with fresh interpreter and counter,
+			// only the final run is reported and errors of failed runs are kept as retry errors
+			var retryErrors []error
+			for run := 0; ; run++ {
+				c := NewTestCounter()
+				subCases, err := t.runSubroutine(sub, suite, scopes, defs, mockRequest.Clone(ctx), c, debugger)
+				if err != nil {
+					errChan <- errors.WithStack(err)
+					return
+				}
+				if failure := firstFailure(subCases); failure != nil && run < t.config.Retries {
+					retryErrors = append(retryErrors, failure)
+					continue
+				}
+				for _, tc := range subCases {
+					tc.RetryErrors = retryErrors
+					tc.Quarantined = quarantined
+				}
+				if quarantined {
+					counter.mergeQuarantined(c)
+				} else {
+					counter.merge(c)
+				}
+				cases = append(cases, subCases...)
+				break
 			}
 		}
 		finishChan <- cases
@@ -170,32 +224,70 @@ func (t *Tester) run(testFile string) (*TestResult, error) {
 	}
 }
 
+// Run testing subroutine on each scope
+func (t *Tester) runSubroutine(
+	sub *ast.SubroutineDeclaration,
+	suite string,
+	scopes []icontext.Scope,
+	defs *tf.Definiions,
+	req *http.Request,
+	counter *TestCounter,
+	debugger *Debugger,
+) ([]*TestCase, error) {
+	// Some functions like "testing.table_set()" will take side-effect for another testing subroutine
+	// so we always initialize interpreter, inject testing functions for each subroutine
+	i := t.setupInterpreter(defs, counter, debugger)
+
+	if err := i.TestProcessInit(req); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var cases []*TestCase
+	for _, s := range scopes {
+		start := time.Now()
+		err := i.ProcessTestSubroutine(s, sub)
+		cases = append(cases, &TestCase{
+			Name:             suite,
+			Error:            errors.Cause(err),
+			Scope:            s.String(),
+			Time:             time.Since(start).Milliseconds(),
+			CacheExplanation: i.CacheExplanation(),
+		})
+	}
+	return cases, nil
+}
+
+func firstFailure(cases []*TestCase) error {
+	for _, c := range cases {
+		if c.Error != nil {
+			return c.Error
+		}
+	}
+	return nil
+}
+
+// Check test suite matches any of quarantine patterns
+func (t *Tester) isQuarantined(suite string) bool {
+	for _, pattern := range t.config.Quarantine {
+		if ok, err := path.Match(pattern, suite); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Find test suite name and may multile scopes
 func (t *Tester) findTestSuites(sub *ast.SubroutineDeclaration) (string, []icontext.Scope) {
 	// Find test suite name and scope from annotation
 	suiteName := sub.Name.Value
 
 	var scopes []icontext.Scope
-	comments := sub.GetMeta().Leading
-	for i := range comments {
-		l := strings.TrimLeft(comments[i].Value, " */#")
-		if !strings.HasPrefix(l, "@") {
-			continue
-		}
-		// If @suite annotation found, use it as suite name
-		if strings.HasPrefix(l, "@suite:") {
-			suiteName = strings.TrimSpace(strings.TrimPrefix(l, "@suite:"))
-			continue
-		}
-		var an []string
-		if strings.HasPrefix(l, "@scope:") {
-			an = strings.Split(strings.TrimPrefix(l, "@scope:"), ",")
-		} else {
-			an = strings.Split(strings.TrimPrefix(l, "@"), ",")
-		}
-		for _, s := range an {
-			scopes = append(scopes, icontext.ScopeByString(strings.TrimSpace(s)))
-		}
+	annotations, _ := annotation.Parse(sub.GetMeta().Leading, annotation.Subroutine)
+	// If @suite annotation found, use it as suite name
+	if a, ok := annotations.Get("suite"); ok {
+		suiteName = a.Values[0]
+	}
+	for _, s := range annotations.Values("scope") {
+		scopes = append(scopes, icontext.ScopeByString(s))
 	}
 
 	if len(scopes) > 0 {
@@ -229,9 +321,9 @@ func (t *Tester) findTestSuites(sub *ast.SubroutineDeclaration) (string, []icont
 }
 
 // Set up interprete for each test subroutines
-func (t *Tester) setupInterpreter(defs *tf.Definiions) *interpreter.Interpreter {
+func (t *Tester) setupInterpreter(defs *tf.Definiions, counter *TestCounter, debugger *Debugger) *interpreter.Interpreter {
 	i := interpreter.New(t.interpreterOptions...)
-	i.Debugger = t.debugger
+	i.Debugger = debugger
 	i.IdentResolver = func(val string) value.Value {
 		if v, ok := defs.Backends[val]; ok {
 			return v
@@ -245,8 +337,11 @@ func (t *Tester) setupInterpreter(defs *tf.Definiions) *interpreter.Interpreter
 		}
 		return nil
 	}
-	variable.Inject(&tv.TestingVariables{})
-	function.Inject(tf.TestingFunctions(i, defs, t.counter))
+	injectTestingVariables.Do(func() {
+		variable.Inject(&tv.TestingVariables{})
+	})
+	// Testing functions are bound to the interpreter, then inject them only to the interpreter
+	i.InjectFunctions(tf.TestingFunctions(i, defs, counter))
 
 	return i
 }